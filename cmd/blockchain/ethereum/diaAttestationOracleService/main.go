@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/attestation"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/evm"
+	"github.com/diadata-org/diadata/pkg/logging"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+// Publishes attestations, e.g. supplies of assets or state roots of other chains, into a
+// DIAAttestationOracle contract. Attestations are signed with the attestor key and sent from
+// the feeder wallet.
+func main() {
+	logging.Setup("diaAttestationOracleService")
+	var deployedContract = flag.String("deployedContract", "", "Address of the deployed DIAAttestationOracle contract")
+	var secretsFile = flag.String("secretsFile", "/run/secrets/oracle_keys", "File with the json key and password lines of each wallet. Updates are spread round-robin across all wallets")
+	var attestorKeyFile = flag.String("attestorKeyFile", "/run/secrets/attestor_key", "File with the hex encoded private key signing the attestations")
+	var blockchainNode = flag.String("blockchainNode", "https://polygon-rpc.com", "Node address for blockchain connection")
+	var chainId = flag.Int64("chainId", 137, "Chain-ID of the network to connect to")
+	var chainType = flag.String("chainType", "", "Fee model of the chain: l1, opstack or arbitrum. Detected from the chain id if empty")
+	var maxFeeWei = flag.String("maxFeeWei", "", "Maximal fee in wei of a single update, including the L1 data fee on rollups. Empty for no cap")
+	var gasMarginPercent = flag.Uint64("gasMarginPercent", feeEstimation.DefaultGasMarginPercent, "Percentage added to the estimated gas of updates")
+	var gasLimit = flag.Uint64("gasLimit", 0, "Gas limit of updates instead of the estimated gas, 0 to estimate")
+	var privateRelay = flag.String("privateRelay", "", "RPC of a private relay receiving the transactions instead of the public mempool, e.g. "+evm.FlashbotsProtectURL+" or "+evm.MEVBlockerURL+" on Ethereum mainnet. Empty to send to blockchainNode")
+	var supplySymbols = flag.String("supplySymbols", "", "Comma separated list of symbols whose supply is attested")
+	var stateRoots = flag.String("stateRoots", "", "Comma separated CHAIN=NODE pairs of EVM chains whose state root is attested")
+	var confirmations = flag.Uint64("confirmations", 64, "Number of blocks on top of a block before its state root is attested")
+	var frequencySeconds = flag.Int("frequencySeconds", 300, "Number of seconds between two checks of all sources")
+	var heartbeatSeconds = flag.Int("heartbeatSeconds", 86400, "Number of seconds after which unchanged values are published again, 0 to disable")
+	var metricsAddr = flag.String("metricsAddr", ":9090", "Address serving /metrics and /metrics/dashboard, empty to disable")
+	var selftestMode = flag.Bool("selftest", false, "Verify all dependencies, print a report and exit")
+	flag.Parse()
+	if !*selftestMode {
+		metrics.Serve(*metricsAddr, "diaAttestationOracleService")
+	}
+
+	if !common.IsHexAddress(*deployedContract) {
+		log.Fatal("deployedContract is required")
+	}
+
+	/*
+	 * Read secrets for unlocking the ETH accounts and the attestor key
+	 */
+	wallets, err := evm.ReadWallets(*secretsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	attestorKey, err := ioutil.ReadFile(*attestorKeyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(attestorKey)), "0x"))
+	if err != nil {
+		log.Fatalf("Failed to parse attestor key: %v", err)
+	}
+
+	var maxFee *big.Int
+	if *maxFeeWei != "" {
+		var ok bool
+		maxFee, ok = new(big.Int).SetString(*maxFeeWei, 10)
+		if !ok {
+			log.Fatalf("Invalid maxFeeWei %s", *maxFeeWei)
+		}
+	}
+	chain, err := evm.NewChain(*blockchainNode, wallets, *chainId, *chainType, maxFee, feeEstimation.GasLimit{MarginPercent: *gasMarginPercent, Override: *gasLimit})
+	if err != nil {
+		log.Fatalf("Failed to connect to the Ethereum client: %v", err)
+	}
+	if *privateRelay != "" {
+		err = chain.SetPrivateRelay(*privateRelay)
+		if err != nil {
+			log.Fatalf("Failed to connect to the private relay: %v", err)
+		}
+	}
+	attestor, err := evm.NewAttestor(chain, common.HexToAddress(*deployedContract), key)
+	if err != nil {
+		log.Fatalf("Failed to bind contract: %v", err)
+	}
+
+	/*
+	 * Set up the sources
+	 */
+	var sources []attestation.Source
+	if *supplySymbols != "" {
+		sources = append(sources, &attestation.SupplySource{Symbols: strings.Split(*supplySymbols, ",")})
+	}
+	if *stateRoots != "" {
+		for _, entry := range strings.Split(*stateRoots, ",") {
+			parts := strings.SplitN(entry, "=", 2)
+			if len(parts) != 2 {
+				log.Fatalf("Invalid state root source %s, expected CHAIN=NODE", entry)
+			}
+			source, err := attestation.NewStateRootSource(parts[0], parts[1], *confirmations)
+			if err != nil {
+				log.Fatalf("Failed to connect to %s: %v", parts[0], err)
+			}
+			sources = append(sources, source)
+		}
+	}
+	if len(sources) == 0 {
+		log.Fatal("No sources configured")
+	}
+
+	feeder := attestation.NewFeeder(sources, attestor, time.Duration(*frequencySeconds)*time.Second, time.Duration(*heartbeatSeconds)*time.Second)
+	if *selftestMode {
+		selftest.Exit("diaAttestationOracleService", feeder.Checks())
+	}
+	log.WithFields(log.Fields{"sources": len(sources), "signer": attestor.Signer().Hex()}).Info("publishing attestations")
+	feeder.Run()
+}
@@ -7,25 +7,27 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
-	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaCoingeckoOracleService"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("diaCoingeckoOracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -36,6 +38,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 120, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 86400, "Number of seconds to sleep between full oracle runs")
 	var chainId = flag.Int64("chainId", 1, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -79,7 +83,12 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
-	periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
+	err = periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+	if err != nil {
+		log.WithError(err).Error("oracle update failed")
+	}
+	breaker.Record(err)
 	/*
 	 * Update Oracle periodically with top coins
 	 */
@@ -88,7 +97,15 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
+				err := periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+				if err != nil {
+					log.WithError(err).Error("oracle update failed")
+				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -99,20 +116,18 @@ func periodicOracleUpdateHelper(numCoins *int, sleepSeconds int, auth *bind.Tran
 
 	topCoins, err := getTopCoinsFromCoingecko(*numCoins)
 	if err != nil {
-		log.Fatalf("Failed to get top %d coins from Coingecko: %v", numCoins, err)
+		return fmt.Errorf("Failed to get top %d coins from Coingecko: %v", *numCoins, err)
 	}
 
 	// Get quotation for topCoins and update Oracle
 	for _, symbol := range topCoins {
 		rawQuot, err := getForeignQuotationFromDia("Coingecko", symbol)
 		if err != nil {
-			log.Fatalf("Failed to retrieve Coingecko data from DIA: %v", err)
-			return err
+			return fmt.Errorf("Failed to retrieve Coingecko data from DIA: %v", err)
 		}
 		err = updateForeignQuotation(rawQuot, auth, contract, conn)
 		if err != nil {
-			log.Fatalf("Failed to update Coingecko Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Coingecko Oracle: %v", err)
 		}
 		time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	}
@@ -126,8 +141,7 @@ func updateForeignQuotation(foreignQuotation *models.ForeignQuotation, auth *bin
 	timestamp := foreignQuotation.Time.Unix()
 	err := updateOracle(conn, contract, auth, symbol, int64(price*100000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -135,7 +149,7 @@ func updateForeignQuotation(foreignQuotation *models.ForeignQuotation, auth *bin
 
 // getTopCoinsFromCoingecko returns the symbols of the top @numCoins assets from coingecko by market cap
 func getTopCoinsFromCoingecko(numCoins int) ([]string, error) {
-	response, err := http.Get("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=" + strconv.Itoa(numCoins) + "&page=1&sparkline=false")
+	response, err := utils.GetWithBackoff("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=" + strconv.Itoa(numCoins) + "&page=1&sparkline=false")
 	if err != nil {
 		return nil, err
 	}
@@ -164,7 +178,7 @@ func getTopCoinsFromCoingecko(numCoins int) ([]string, error) {
 }
 
 func getForeignQuotationFromDia(source, symbol string) (*models.ForeignQuotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/foreignQuotation/" + strings.Title(strings.ToLower(source)) + "/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/foreignQuotation/" + strings.Title(strings.ToLower(source)) + "/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -201,8 +215,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -218,29 +231,27 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.SetValue(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
-	}, key, big.NewInt(value), big.NewInt(timestamp))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetValue(opts, key, big.NewInt(value), big.NewInt(timestamp))
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("key: %s\n", key)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(log.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
 
@@ -4,10 +4,10 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
 	"net/http"
 	"net/url"
@@ -17,15 +17,20 @@ import (
 	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaCoinmarketcapOracleService"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("diaCoinmarketcapOracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -36,6 +41,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 120, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 86400, "Number of seconds to sleep between full oracle runs")
 	var chainId = flag.Int64("chainId", 1, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -79,7 +86,12 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
-	periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
+	err = periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+	if err != nil {
+		log.WithError(err).Error("oracle update failed")
+	}
+	breaker.Record(err)
 	/*
 	 * Update Oracle periodically with top coins
 	 */
@@ -88,7 +100,15 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
+				err := periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+				if err != nil {
+					log.WithError(err).Error("oracle update failed")
+				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -100,19 +120,17 @@ func periodicOracleUpdateHelper(numCoins *int, sleepSeconds int, auth *bind.Tran
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	topCoins, err := getTopCoinsFromCoinmarketcap(*numCoins)
 	if err != nil {
-		log.Fatalf("Failed to get top %d coins from Coinmarketcap: %v", numCoins, err)
+		return fmt.Errorf("Failed to get top %d coins from Coinmarketcap: %v", *numCoins, err)
 	}
 	// Get quotation for topCoins and update Oracle
 	for _, symbol := range topCoins {
 		rawQuot, err := getForeignQuotationFromDia("CoinMarketCap", symbol)
 		if err != nil {
-			log.Fatalf("Failed to retrieve Coinmarketcap data from DIA: %v", err)
-			return err
+			return fmt.Errorf("Failed to retrieve Coinmarketcap data from DIA: %v", err)
 		}
 		err = updateForeignQuotation(rawQuot, auth, contract, conn)
 		if err != nil {
-			log.Fatalf("Failed to update Coinmarketcap Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Coinmarketcap Oracle: %v", err)
 		}
 		time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	}
@@ -126,8 +144,7 @@ func updateForeignQuotation(foreignQuotation *models.ForeignQuotation, auth *bin
 	timestamp := foreignQuotation.Time.Unix()
 	err := updateOracle(conn, contract, auth, symbol, int64(price*100000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -141,7 +158,7 @@ func getTopCoinsFromCoinmarketcap(numCoins int) ([]string, error) {
 	var lines []string
 	file, err := os.Open("/run/secrets/Coinmarketcap-API.key") // Read in key information
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer file.Close()
 	scanner := bufio.NewScanner(file)
@@ -149,10 +166,10 @@ func getTopCoinsFromCoinmarketcap(numCoins int) ([]string, error) {
 		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	if len(lines) != 1 {
-		log.Fatal("Secrets file for coinmarketcap API key should have exactly one line")
+		return nil, errors.New("Secrets file for coinmarketcap API key should have exactly one line")
 	}
 	apiKey := lines[0]
 
@@ -206,7 +223,7 @@ func getTopCoinsFromCoinmarketcap(numCoins int) ([]string, error) {
 }
 
 func getForeignQuotationFromDia(source, symbol string) (*models.ForeignQuotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/foreignQuotation/" + source + "/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/foreignQuotation/" + source + "/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -243,8 +260,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -260,28 +276,26 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.SetValue(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
-	}, key, big.NewInt(value), big.NewInt(timestamp))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetValue(opts, key, big.NewInt(value), big.NewInt(timestamp))
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("key: %s\n", key)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(log.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
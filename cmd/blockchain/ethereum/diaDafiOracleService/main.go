@@ -6,23 +6,26 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
-	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaDafiOracleService"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("diaDafiOracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -33,6 +36,8 @@ func main() {
 	var frequencySeconds = flag.Int("frequencySeconds", 120, "Number of seconds to sleep between checking oracle runs")
 	var deviationPermille = flag.Int("deviationPermille", 30, "Permille of deviation to trigger an oracle update")
 	var chainId = flag.Int64("chainId", 56, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -80,16 +85,22 @@ func main() {
 	/*
 	 * Update Oracle periodically with top coins
 	 */
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
 	ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
 	oldPrice := 0.0
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
 				oldPrice, err = periodicOracleUpdateHelper(*sleepSeconds, oldPrice, *deviationPermille, auth, contract, conn)
 				if err != nil {
-					log.Println(err)
+					log.WithError(err).Error("oracle update failed")
 				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -101,8 +112,7 @@ func periodicOracleUpdateHelper(sleepSeconds int, oldPrice float64, deviationPer
 	// Get quotation for DAFI coin and update Oracle
 	rawDafiQ, err := getQuotationFromDia("DAFI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve DAFI quotation data from DIA: %v", err)
-		return oldPrice, err
+		return oldPrice, fmt.Errorf("Failed to retrieve DAFI quotation data from DIA: %v", err)
 	}
 	rawDafiQ.Name = "DAFI"
 
@@ -110,11 +120,10 @@ func periodicOracleUpdateHelper(sleepSeconds int, oldPrice float64, deviationPer
 	newPrice := rawDafiQ.Price
 
 	if (newPrice > (oldPrice * (1 + float64(deviationPermille) / 1000))) || (newPrice < (oldPrice * (1 - float64(deviationPermille) / 1000))) {
-		log.Println("Entering deviation based update zone")
+		log.WithFields(log.Fields{"oldPrice": oldPrice, "newPrice": newPrice}).Info("deviation exceeded, updating oracle")
 		err = updateQuotation(rawDafiQ, auth, contract, conn)
 		if err != nil {
-			log.Fatalf("Failed to update DAFI Oracle: %v", err)
-			return oldPrice, err
+			return oldPrice, fmt.Errorf("Failed to update DAFI Oracle: %v", err)
 		}
 		return newPrice, nil
 	}
@@ -138,8 +147,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -151,8 +159,7 @@ func updateQuotation(quotation *models.Quotation, auth *bind.TransactOpts, contr
 	timestamp := time.Now().Unix()
 	err := updateOracle(conn, contract, auth, symbol, int64(price*100000000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -168,34 +175,32 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.SetValue(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
-	}, key, big.NewInt(value), big.NewInt(timestamp))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetValue(opts, key, big.NewInt(value), big.NewInt(timestamp))
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("key: %s\n", key)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(log.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
 
 func getQuotationFromDia(symbol string) (*models.Quotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
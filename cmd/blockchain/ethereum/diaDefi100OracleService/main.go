@@ -15,8 +15,11 @@ import (
 	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaDefi100OracleService"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -27,10 +30,11 @@ import (
 var log *logrus.Logger
 
 func init() {
-	log = logrus.New()
+	log = logrus.StandardLogger()
 }
 
 func main() {
+	logging.Setup("diaDefi100OracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -40,6 +44,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 120, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 86400, "Number of seconds to sleep between full oracle runs")
 	var chainId = flag.Int64("chainId", 1, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -83,7 +89,12 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
-	periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
+	err = periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+	if err != nil {
+		log.WithError(err).Error("oracle update failed")
+	}
+	breaker.Record(err)
 	/*
 	 * Update Oracle periodically with top coins
 	 */
@@ -92,7 +103,15 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
+				err := periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+				if err != nil {
+					log.WithError(err).Error("oracle update failed")
+				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -106,13 +125,12 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 	// Get fresh market cap data and update Oracle
 	marketcap, err := getDefiMCFromCoingecko()
 	if err != nil {
-		log.Fatalf("Failed to get data from Coingecko: %v", err)
+		return fmt.Errorf("Failed to get data from Coingecko: %v", err)
 	}
 
 	err = updateMarketCap(marketcap, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Defi100 Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Defi100 Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -120,14 +138,12 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 	// D100 Quotation
 	rawD100Q, err := getQuotationFromDia("D100")
 	if err != nil {
-		log.Fatalf("Failed to retrieve D100 quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve D100 quotation data from DIA: %v", err)
 	}
 	rawD100Q.Name = "D100"
 	err = updateQuotation(rawD100Q, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update D100 Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update D100 Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -176,7 +192,7 @@ func getDefiMCFromCoingecko() (float64, error) {
 }
 
 func getQuotationFromDia(symbol string) (*models.Quotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -213,8 +229,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(logrus.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -225,8 +240,7 @@ func updateMarketCap(marketCap float64, auth *bind.TransactOpts, contract *diaDe
 	timestamp := time.Now().Unix()
 	err := updateOracle(conn, contract, auth, symbol, int64(marketCap*100000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -238,8 +252,7 @@ func updateQuotation(quotation *models.Quotation, auth *bind.TransactOpts, contr
 	timestamp := time.Now().Unix()
 	err := updateOracle(conn, contract, auth, symbol, int64(price*100000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -255,28 +268,26 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.SetValue(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
-	}, key, big.NewInt(value), big.NewInt(timestamp))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetValue(opts, key, big.NewInt(value), big.NewInt(timestamp))
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("key: %s\n", key)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(logrus.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
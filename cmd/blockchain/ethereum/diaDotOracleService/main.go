@@ -6,23 +6,27 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
-	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaOracleService"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("diaDotOracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -32,6 +36,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 10, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 120, "Number of seconds to sleep between checking oracle runs")
 	var chainId = flag.Int64("chainId", 1285, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -81,16 +87,22 @@ func main() {
 	/*
 	 * Update Oracle periodically with coins
 	 */
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
 	ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
 				for _, s := range symbols {
+					if !breaker.Allow() {
+						log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+						break
+					}
 					err = periodicOracleUpdateHelper(auth, contract, conn, s)
 					if err != nil {
-						log.Println(err)
+						log.WithError(err).Error("oracle update failed")
 					}
+					breaker.Record(err)
 					time.Sleep(time.Duration(*sleepSeconds) * time.Second)
 				}
 			}
@@ -101,17 +113,14 @@ func main() {
 
 func periodicOracleUpdateHelper(auth *bind.TransactOpts, contract *diaOracleService.DIAOracle, conn *ethclient.Client, symbol string) error {
 	// Get quotation for token and update Oracle
-	rawQ, err := getQuotationFromDia(symbol)
+	rawQ, err := oracleFeeder.GetFeedQuotation(symbol, getQuotationFromDia)
 	if err != nil {
-		log.Fatalf("Failed to retrieve %s quotation data from DIA: %v", symbol, err)
-		return err
+		return fmt.Errorf("Failed to retrieve %s quotation data from DIA: %v", symbol, err)
 	}
-	rawQ.Name = symbol
 
 	err = updateQuotation(rawQ, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update DIA Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update DIA Oracle: %v", err)
 	}
 	return nil
 }
@@ -132,21 +141,19 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
 }
 
 func updateQuotation(quotation *models.Quotation, auth *bind.TransactOpts, contract *diaOracleService.DIAOracle, conn *ethclient.Client) error {
-	symbol := quotation.Symbol + "/USD"
+	symbol := oracleFeeder.Key(quotation)
 	price := quotation.Price
 	timestamp := time.Now().Unix()
 	err := updateOracle(conn, contract, auth, symbol, int64(price*100000000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -162,29 +169,27 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.SetValue(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 1000725,
 		GasPrice: gasPrice,
-	}, key, big.NewInt(value), big.NewInt(timestamp))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetValue(opts, key, big.NewInt(value), big.NewInt(timestamp))
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("key: %s\n", key)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(log.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
 
@@ -193,7 +198,7 @@ func getQuotationFromDia(symbol string) (*models.Quotation, error) {
 	if symbol == "SOLAR" {
 		urlBase = "https://rest.diadata.org"
 	}
-	response, err := http.Get(urlBase + "/v1/quotation/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(urlBase + "/v1/quotation/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
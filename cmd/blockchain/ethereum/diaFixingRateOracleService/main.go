@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaOracleServiceV2"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// Publishes the daily fixing rates on-chain. Each fixing is written exactly once under the
+// key SYMBOL/USD-FIX, with the fixing time as timestamp.
+func main() {
+	logging.Setup("diaFixingRateOracleService")
+	var deployedContract = flag.String("deployedContract", "", "Address of the deployed oracle contract")
+	var secretsFile = flag.String("secretsFile", "/run/secrets/oracle_keys", "File with wallet secrets")
+	var blockchainNode = flag.String("blockchainNode", "https://matic-mainnet-full-rpc.bwarelabs.com", "Node address for blockchain connection")
+	var symbolsFlag = flag.String("symbols", "BTC,ETH,DIA,USDC", "Comma separated list of symbols")
+	var sleepSeconds = flag.Int("sleepSeconds", 10, "Number of seconds to sleep between calls")
+	var frequencySeconds = flag.Int("frequencySeconds", 300, "Number of seconds to sleep between checking for new fixings")
+	var chainId = flag.Int64("chainId", 137, "Chain-ID of the network to connect to")
+	var chainType = flag.String("chainType", "", "Fee model of the chain: l1, opstack or arbitrum. Detected from the chain id if empty")
+	var maxFeeWei = flag.String("maxFeeWei", "", "Maximal fee in wei of a single update, including the L1 data fee on rollups. Empty for no cap")
+	var gasMarginPercent = flag.Uint64("gasMarginPercent", feeEstimation.DefaultGasMarginPercent, "Percentage added to the estimated gas of updates")
+	var gasLimit = flag.Uint64("gasLimit", 0, "Gas limit of updates instead of the estimated gas, 0 to estimate")
+	flag.Parse()
+
+	/*
+	 * Read secrets for unlocking the ETH account
+	 */
+	var lines []string
+	file, err := os.Open(*secretsFile) // Read in key information
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if len(lines) != 2 {
+		log.Fatal("Secrets file should have exactly two lines")
+	}
+	key := lines[0]
+	key_password := lines[1]
+
+	symbols := strings.Split(*symbolsFlag, ",")
+	lastFixingTimes := make(map[string]time.Time)
+
+	/*
+	 * Setup connection to contract, deploy if necessary
+	 */
+
+	conn, err := ethclient.Dial(*blockchainNode)
+	if err != nil {
+		log.Fatalf("Failed to connect to the Ethereum client: %v", err)
+	}
+
+	auth, err := bind.NewTransactorWithChainID(strings.NewReader(key), key_password, big.NewInt(*chainId))
+	if err != nil {
+		log.Fatalf("Failed to create authorized transactor: %v", err)
+	}
+
+	var contract *diaOracleServiceV2.DIAOracleV2
+	var watcher *diaOracleServiceV2.ProxyWatcher
+	err = deployOrBindContract(*deployedContract, conn, auth, &contract, &watcher)
+	if err != nil {
+		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
+	}
+
+	if *chainType == "" {
+		*chainType = feeEstimation.ChainType(*chainId)
+	}
+	var maxFee *big.Int
+	if *maxFeeWei != "" {
+		var ok bool
+		maxFee, ok = new(big.Int).SetString(*maxFeeWei, 10)
+		if !ok {
+			log.Fatalf("Invalid maxFeeWei %s", *maxFeeWei)
+		}
+	}
+	estimator, err := feeEstimation.NewEstimator(conn, *chainType, maxFee, feeEstimation.GasLimit{MarginPercent: *gasMarginPercent, Override: *gasLimit})
+	if err != nil {
+		log.Fatalf("Failed to set up fee estimation: %v", err)
+	}
+
+	/*
+	 * Publish new fixings
+	 */
+	ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
+	for ; true; <-ticker.C {
+		if err := watcher.Check(); err != nil {
+			log.WithError(err).Warn("skipping oracle updates")
+			continue
+		}
+		for _, s := range symbols {
+			fixing, err := getFixingRateFromDia(s)
+			if err != nil {
+				log.WithError(err).WithField("symbol", s).Error("failed to retrieve fixing rate from DIA")
+				continue
+			}
+			if !fixing.FixingTime.After(lastFixingTimes[s]) {
+				continue
+			}
+			err = updateOracle(conn, contract, watcher.Address(), estimator, auth, s+"/USD-FIX", int64(fixing.Price*100000000), fixing.FixingTime.Unix())
+			if errors.Is(err, feeEstimation.ErrEstimateGas) {
+				log.WithError(err).WithField("symbol", s).Warn("skipping fixing rate")
+				continue
+			}
+			if err != nil {
+				log.WithError(err).WithField("symbol", s).Error("failed to update fixing rate")
+				continue
+			}
+			lastFixingTimes[s] = fixing.FixingTime
+			time.Sleep(time.Duration(*sleepSeconds) * time.Second)
+		}
+	}
+}
+
+func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth *bind.TransactOpts, contract **diaOracleServiceV2.DIAOracleV2, watcher **diaOracleServiceV2.ProxyWatcher) error {
+	var err error
+	addr := common.HexToAddress(deployedContract)
+	if deployedContract != "" {
+		*contract, err = diaOracleServiceV2.NewDIAOracleV2(addr, conn)
+		if err != nil {
+			return err
+		}
+	} else {
+		// deploy contract
+		var tx *types.Transaction
+		addr, tx, *contract, err = diaOracleServiceV2.DeployDIAOracleV2(auth, conn)
+		if err != nil {
+			return err
+		}
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
+		time.Sleep(180000 * time.Millisecond)
+	}
+	// Proxies are resolved so that the implementation behind them is checked for compatibility.
+	*watcher, err = diaOracleServiceV2.NewProxyWatcher(conn, addr)
+	return err
+}
+
+func updateOracle(
+	client *ethclient.Client,
+	contract *diaOracleServiceV2.DIAOracleV2,
+	contractAddress common.Address,
+	estimator *feeEstimation.Estimator,
+	auth *bind.TransactOpts,
+	key string,
+	value int64,
+	timestamp int64) error {
+
+	gasPrice, err := client.SuggestGasPrice(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// Get 110% of the gas price
+	fGas := new(big.Float).SetInt(gasPrice)
+	fGas.Mul(fGas, big.NewFloat(1.1))
+	gasPrice, _ = fGas.Int(nil)
+
+	// On rollups, SuggestGasPrice doesn't reflect the L1 data fee, which is most of the cost.
+	data, err := diaOracleServiceV2.PackSetValue(key, big.NewInt(value), big.NewInt(timestamp))
+	if err != nil {
+		return err
+	}
+	fee, err := estimator.Estimate(context.Background(), auth.From, contractAddress, data, gasPrice)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"chain": estimator.ChainType(), "key": key, "fee": fee.String()}).Debug("estimated fee")
+	err = estimator.CheckFee(fee)
+	if err != nil {
+		return err
+	}
+
+	// Write values to smart contract
+	tx, err := contract.SetValue(&bind.TransactOpts{
+		From:     auth.From,
+		Signer:   auth.Signer,
+		GasLimit: fee.GasLimit,
+		GasPrice: gasPrice,
+	}, key, big.NewInt(value), big.NewInt(timestamp))
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
+	return nil
+}
+
+func getFixingRateFromDia(symbol string) (*models.FixingRate, error) {
+	response, err := http.Get(dia.BaseUrl + "/v1/fixingRate/" + strings.ToUpper(symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Error on dia api with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var fixing models.FixingRate
+	err = json.Unmarshal(contents, &fixing)
+	if err != nil {
+		return nil, err
+	}
+	return &fixing, nil
+}
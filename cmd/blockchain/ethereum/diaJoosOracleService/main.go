@@ -6,22 +6,25 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaCoingeckoOracleService"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("diaJoosOracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -32,6 +35,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 120, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 86400, "Number of seconds to sleep between full oracle runs")
 	var chainId = flag.Int64("chainId", 1, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -75,7 +80,12 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
-	periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
+	err = periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+	if err != nil {
+		log.WithError(err).Error("oracle update failed")
+	}
+	breaker.Record(err)
 	/*
 	 * Update Oracle periodically with top coins
 	 */
@@ -84,7 +94,15 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
+				err := periodicOracleUpdateHelper(numCoins, *sleepSeconds, auth, contract, conn)
+				if err != nil {
+					log.WithError(err).Error("oracle update failed")
+				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -96,26 +114,22 @@ func periodicOracleUpdateHelper(numCoins *int, sleepSeconds int, auth *bind.Tran
 	// Get quotation for JOOS coin and update Oracle
 	rawQuot, err := getForeignQuotationByAddress("0x05f9abf4b0c5661e83b92c056a8791d5ccd7ca52")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Coingecko data for JOOS: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Coingecko data for JOOS: %v", err)
 	}
 	err = updateForeignQuotation(rawQuot, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Coingecko Oracle for JOOS: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Coingecko Oracle for JOOS: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Get quotation for WBTC coin and update Oracle
 	rawQuotWBTC, err := getForeignQuotationByAddress("0x2260fac5e5542a773aa44fbcfedf7c193bc2c599")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Coingecko data for WBTC: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Coingecko data for WBTC: %v", err)
 	}
 	err = updateForeignQuotation(rawQuotWBTC, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Coingecko Oracle for WBTC: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Coingecko Oracle for WBTC: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -128,8 +142,7 @@ func updateForeignQuotation(foreignQuotation *models.ForeignQuotation, auth *bin
 	timestamp := foreignQuotation.Time.Unix()
 	err := updateOracle(conn, contract, auth, symbol, int64(price*100000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -151,8 +164,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -168,29 +180,27 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.SetValue(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
-	}, key, big.NewInt(value), big.NewInt(timestamp))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.SetValue(opts, key, big.NewInt(value), big.NewInt(timestamp))
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("key: %s\n", key)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(log.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
 
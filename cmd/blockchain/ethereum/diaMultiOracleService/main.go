@@ -0,0 +1,316 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/evm"
+	"github.com/diadata-org/diadata/pkg/logging"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// Target types
+const (
+	// typeOracleV2 is a DIAOracleV2 contract holding all symbols, given by deployedContract.
+	typeOracleV2 = "diaOracleV2"
+	// typeAggregatorV3 are AggregatorV3Interface compatible adapters, one per symbol, given by adapters.
+	typeAggregatorV3 = "aggregatorV3"
+)
+
+// target is an entry of the targets file. Targets on the same node share the nonce of the
+// wallet, so chainType, maxFeeWei and the gas limit settings are taken from the first target of
+// each node, and so is the Safe.
+type target struct {
+	Name             string            `json:"name"`
+	Type             string            `json:"type"`
+	BlockchainNode   string            `json:"blockchainNode"`
+	ChainID          int64             `json:"chainId"`
+	ChainType        string            `json:"chainType"`
+	DeployedContract string            `json:"deployedContract"`
+	Adapters         map[string]string `json:"adapters"`
+	MaxFeeWei        string            `json:"maxFeeWei"`
+	// GasLimit is used instead of the estimated gas if non-zero.
+	GasLimit uint64 `json:"gasLimit"`
+	// GasMarginPercent overrides the gasMarginPercent flag.
+	GasMarginPercent *uint64 `json:"gasMarginPercent"`
+	// Safe is the address of a Safe multisig owning the contracts, through which all
+	// transactions on the node are submitted.
+	Safe string `json:"safe"`
+	// SafeMode is execute or propose, see evm.SafeExecute and evm.SafePropose. Defaults to propose.
+	SafeMode string `json:"safeMode"`
+	// SafeServiceURL is the Safe Transaction Service of the chain, required to propose.
+	SafeServiceURL string `json:"safeServiceUrl"`
+	// PrivateRelay is the RPC of a private relay receiving all transactions on the node
+	// instead of the public mempool, e.g. https://rpc.flashbots.net on Ethereum mainnet.
+	PrivateRelay string `json:"privateRelay"`
+}
+
+// keeperHeartbeat is an entry of the heartbeats file. It calls a liveness method of the keeper
+// contract of a tenant, which falls back to a backup oracle once the calls stop. Heartbeats use
+// the chain of the targets on the same node, if any.
+type keeperHeartbeat struct {
+	Tenant         string `json:"tenant"`
+	BlockchainNode string `json:"blockchainNode"`
+	ChainID        int64  `json:"chainId"`
+	ChainType      string `json:"chainType"`
+	Contract       string `json:"contract"`
+	// Method is the signature of the liveness method, e.g. heartbeat() or ping(uint256).
+	Method          string `json:"method"`
+	IntervalSeconds int    `json:"intervalSeconds"`
+}
+
+// Pushes the same values to several oracle contracts, e.g. a main and a backup contract or the
+// same contract on several chains, from one wallet. Targets are either DIAOracleV2 contracts or
+// sets of Chainlink AggregatorV3Interface compatible adapters.
+func main() {
+	logging.Setup("diaMultiOracleService")
+	var targetsFile = flag.String("targetsFile", "/config/oracles/multiOracle.json", "JSON file listing the target contracts")
+	var secretsFile = flag.String("secretsFile", "/run/secrets/oracle_keys", "File with the json key and password lines of each wallet. Updates are spread round-robin across all wallets")
+	var symbolsFlag = flag.String("symbols", "BTC,ETH,DIA,USDC", "Comma separated list of symbols, quoted in USD unless given as SYMBOL/QUOTE, e.g. ETH/EUR or MATIC/ETH")
+	var sleepSeconds = flag.Int("sleepSeconds", 10, "Number of seconds to sleep between calls")
+	var frequencySeconds = flag.Int("frequencySeconds", 120, "Number of seconds to sleep between checking oracle runs")
+	var deviationPermille = flag.Int("deviationPermille", 10, "Permille of deviation to trigger an oracle update")
+	var absoluteDeviationsFlag = flag.String("absoluteDeviations", "", "Comma separated SYMBOL:USD absolute deviations which also trigger an update, e.g. USDT:0.002")
+	var gasMarginPercent = flag.Uint64("gasMarginPercent", feeEstimation.DefaultGasMarginPercent, "Percentage added to the estimated gas of updates, unless overridden by a target")
+	var heartbeatSeconds = flag.Int("heartbeatSeconds", 0, "Number of seconds after which an update is forced, 0 to disable")
+	var twapWindowSeconds = flag.Int("twapWindowSeconds", 0, "Push the time-weighted average over this many seconds instead of the latest quotation, 0 to disable")
+	var twapFilter = flag.String("twapFilter", "MA120", "Filter of the filters service the time-weighted average is taken of")
+	var fiatSymbolsFlag = flag.String("fiatSymbols", "", "Comma separated fiat currencies priced by the foreign quotations of fiatSource, e.g. EUR,GBP,JPY to feed EUR/USD or ETH/EUR")
+	var fiatSource = flag.String("fiatSource", "", "Source of the foreign quotation endpoint of the DIA API serving the rates of fiatSymbols")
+	var fiatMaxAgeHours = flag.Int("fiatMaxAgeHours", 96, "Number of hours after which a fiat rate is stale and not pushed, spanning weekends and holidays")
+	var decisionLogFile = flag.String("decisionLogFile", "", "File recording all update decisions, served under /decisions on metricsAddr. Empty to keep them in memory only")
+	var decisionRetentionHours = flag.Int("decisionRetentionHours", 168, "Number of hours update decisions are kept")
+	var adminTokenFile = flag.String("adminTokenFile", "/run/secrets/feeder_admin_token", "File holding the bearer token of the admin API served under /admin/ on metricsAddr. The API is disabled if the file doesn't exist")
+	var heartbeatsFile = flag.String("heartbeatsFile", "", "JSON file listing the keeper heartbeats sent while the feeder is healthy, empty to disable")
+	var safeOwnersFile = flag.String("safeOwnersFile", "/run/secrets/safe_owner_keys", "File with the hex encoded private keys of Safe owners, one per line, signing the transactions of targets with a safe")
+	var auditUpdates = flag.Bool("auditUpdates", false, "Record all attempted and confirmed updates in the oracleupdate table of postgres")
+	var metricsAddr = flag.String("metricsAddr", ":9090", "Address serving /metrics and /metrics/dashboard, empty to disable")
+	var selftestMode = flag.Bool("selftest", false, "Verify all dependencies, print a report and exit")
+	flag.Parse()
+	if !*selftestMode {
+		metrics.Serve(*metricsAddr, "diaMultiOracleService")
+	}
+
+	/*
+	 * Read secrets for unlocking the ETH accounts
+	 */
+	wallets, err := evm.ReadWallets(*secretsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	/*
+	 * Bind all target contracts
+	 */
+	data, err := ioutil.ReadFile(*targetsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var targets []target
+	err = json.Unmarshal(data, &targets)
+	if err != nil {
+		log.Fatalf("Failed to parse targets file: %v", err)
+	}
+	if len(targets) == 0 {
+		log.Fatal("No targets configured")
+	}
+
+	var auditTrail *evm.AuditTrail
+	if *auditUpdates {
+		relDB, err := models.NewPostgresDataStore()
+		if err != nil {
+			log.Fatalf("Failed to connect to postgres: %v", err)
+		}
+		auditTrail = evm.NewAuditTrail(relDB)
+	}
+
+	chains := make(map[string]*evm.Chain)
+	var safeOwners []*ecdsa.PrivateKey
+	var updaters []oracleFeeder.Target
+	for _, t := range targets {
+		var maxFee *big.Int
+		if t.MaxFeeWei != "" {
+			var ok bool
+			maxFee, ok = new(big.Int).SetString(t.MaxFeeWei, 10)
+			if !ok {
+				log.Fatalf("Invalid maxFeeWei of target %s", t.Name)
+			}
+		}
+		chain, ok := chains[t.BlockchainNode]
+		if !ok {
+			gasLimit := feeEstimation.GasLimit{MarginPercent: *gasMarginPercent, Override: t.GasLimit}
+			if t.GasMarginPercent != nil {
+				gasLimit.MarginPercent = *t.GasMarginPercent
+			}
+			chain, err = evm.NewChain(t.BlockchainNode, wallets, t.ChainID, t.ChainType, maxFee, gasLimit)
+			if err != nil {
+				log.Fatalf("Failed to connect target %s: %v", t.Name, err)
+			}
+			if auditTrail != nil {
+				chain.SetAuditTrail(auditTrail)
+			}
+			if t.PrivateRelay != "" {
+				err = chain.SetPrivateRelay(t.PrivateRelay)
+				if err != nil {
+					log.Fatalf("Failed to connect private relay of target %s: %v", t.Name, err)
+				}
+			}
+			if t.Safe != "" {
+				if !common.IsHexAddress(t.Safe) {
+					log.Fatalf("Invalid safe of target %s", t.Name)
+				}
+				if safeOwners == nil {
+					safeOwners, err = evm.ReadSafeOwners(*safeOwnersFile)
+					if err != nil {
+						log.Fatalf("Failed to read safe owner keys: %v", err)
+					}
+				}
+				mode := t.SafeMode
+				if mode == "" {
+					mode = evm.SafePropose
+				}
+				safe, err := evm.NewSafe(chain, common.HexToAddress(t.Safe), mode, safeOwners, t.SafeServiceURL)
+				if err != nil {
+					log.Fatalf("Failed to set up safe of target %s: %v", t.Name, err)
+				}
+				chain.SetSafe(safe)
+			}
+			chains[t.BlockchainNode] = chain
+		}
+		var updater oracleFeeder.Updater
+		switch t.Type {
+		case "", typeOracleV2:
+			if !common.IsHexAddress(t.DeployedContract) {
+				log.Fatalf("Invalid deployedContract of target %s", t.Name)
+			}
+			updater, err = evm.NewOracle(chain, common.HexToAddress(t.DeployedContract))
+		case typeAggregatorV3:
+			adapters := make(map[string]common.Address)
+			for symbol, address := range t.Adapters {
+				if !common.IsHexAddress(address) {
+					log.Fatalf("Invalid adapter of %s in target %s", symbol, t.Name)
+				}
+				adapters[symbol] = common.HexToAddress(address)
+			}
+			if len(adapters) == 0 {
+				log.Fatalf("No adapters configured for target %s", t.Name)
+			}
+			updater, err = evm.NewAggregator(chain, adapters)
+		default:
+			log.Fatalf("Unknown type %s of target %s", t.Type, t.Name)
+		}
+		if err != nil {
+			log.Fatalf("Failed to bind contract of target %s: %v", t.Name, err)
+		}
+		updaters = append(updaters, oracleFeeder.Target{Name: t.Name, Updater: updater})
+	}
+	multi := oracleFeeder.NewMultiUpdater(updaters)
+
+	/*
+	 * Report the state of all targets once per cycle
+	 */
+	go func() {
+		ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
+		for range ticker.C {
+			for _, s := range multi.Status() {
+				log.WithFields(log.Fields{
+					"target":      s.Name,
+					"lastSuccess": s.LastSuccess,
+					"failures":    s.ConsecutiveFailures,
+					"pending":     s.Pending,
+					"lastError":   s.LastError,
+				}).Info("target status")
+			}
+		}
+	}()
+
+	absoluteDeviations, err := oracleFeeder.ParseAbsoluteDeviations(*absoluteDeviationsFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var fiatSymbols []string
+	if *fiatSymbolsFlag != "" {
+		if *fiatSource == "" {
+			log.Fatal("fiatSource is required to feed fiatSymbols")
+		}
+		fiatSymbols = strings.Split(*fiatSymbolsFlag, ",")
+	}
+	decisions, err := oracleFeeder.NewDecisionLog(*decisionLogFile, time.Duration(*decisionRetentionHours)*time.Hour)
+	if err != nil {
+		log.Fatalf("Failed to open decision log: %v", err)
+	}
+	metrics.Handle("/decisions", decisions.Handler())
+	feeder := oracleFeeder.NewFeeder(oracleFeeder.Config{
+		Symbols:            strings.Split(*symbolsFlag, ","),
+		Frequency:          time.Duration(*frequencySeconds) * time.Second,
+		Sleep:              time.Duration(*sleepSeconds) * time.Second,
+		DeviationPermille:  *deviationPermille,
+		AbsoluteDeviations: absoluteDeviations,
+		Heartbeat:          time.Duration(*heartbeatSeconds) * time.Second,
+		TWAPWindow:         time.Duration(*twapWindowSeconds) * time.Second,
+		TWAPFilter:         *twapFilter,
+		FiatSymbols:        fiatSymbols,
+		FiatSource:         *fiatSource,
+		FiatMaxAge:         time.Duration(*fiatMaxAgeHours) * time.Hour,
+		Decisions:          decisions,
+	}, multi)
+	adminToken, err := oracleFeeder.ReadAdminToken(*adminTokenFile)
+	if err != nil {
+		log.Fatalf("Failed to read admin token: %v", err)
+	}
+	if adminToken != "" {
+		metrics.Handle("/admin/", feeder.AdminHandler(adminToken))
+	}
+	if *selftestMode {
+		selftest.Exit("diaMultiOracleService", feeder.Checks())
+	}
+
+	/*
+	 * Signal liveness to the keeper contracts of all tenants
+	 */
+	if *heartbeatsFile != "" {
+		data, err := ioutil.ReadFile(*heartbeatsFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var heartbeats []keeperHeartbeat
+		err = json.Unmarshal(data, &heartbeats)
+		if err != nil {
+			log.Fatalf("Failed to parse heartbeats file: %v", err)
+		}
+		// A healthy feeder completes a cycle at least every frequency plus the sleeps between
+		// the updates of all symbols.
+		maxCycleAge := 2*time.Duration(*frequencySeconds)*time.Second + time.Duration(len(feeder.Symbols())*(*sleepSeconds))*time.Second
+		healthy := evm.FeederHealth(feeder.LastCycle, maxCycleAge)
+		for _, h := range heartbeats {
+			if !common.IsHexAddress(h.Contract) {
+				log.Fatalf("Invalid contract of heartbeat of %s", h.Tenant)
+			}
+			chain, ok := chains[h.BlockchainNode]
+			if !ok {
+				chain, err = evm.NewChain(h.BlockchainNode, wallets, h.ChainID, h.ChainType, nil, feeEstimation.GasLimit{MarginPercent: *gasMarginPercent})
+				if err != nil {
+					log.Fatalf("Failed to connect heartbeat of %s: %v", h.Tenant, err)
+				}
+				chains[h.BlockchainNode] = chain
+			}
+			heartbeat, err := evm.NewHeartbeat(h.Tenant, chain, common.HexToAddress(h.Contract), h.Method, time.Duration(h.IntervalSeconds)*time.Second, healthy)
+			if err != nil {
+				log.Fatal(err)
+			}
+			go heartbeat.Run()
+		}
+	}
+	feeder.Run()
+}
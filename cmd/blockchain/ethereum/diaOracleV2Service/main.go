@@ -3,26 +3,32 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"math"
 	"math/big"
-	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaOracleServiceV2"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("diaOracleV2Service")
 	/*
 	 * Read in Oracle address
 	 */
@@ -33,8 +39,19 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 10, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 120, "Number of seconds to sleep between checking oracle runs")
 	var deviationPermille = flag.Int("deviationPermille", 10, "Permille of deviation to trigger an oracle update")
+	var absoluteDeviationsFlag = flag.String("absoluteDeviations", "", "Comma separated SYMBOL:USD absolute deviations which also trigger an update, e.g. USDT:0.002")
 	var chainId = flag.Int64("chainId", 137, "Chain-ID of the network to connect to")
+	var chainType = flag.String("chainType", "", "Fee model of the chain: l1, opstack or arbitrum. Detected from the chain id if empty")
+	var maxFeeWei = flag.String("maxFeeWei", "", "Maximal fee in wei of a single update, including the L1 data fee on rollups. Empty for no cap")
+	var gasMarginPercent = flag.Uint64("gasMarginPercent", feeEstimation.DefaultGasMarginPercent, "Percentage added to the estimated gas of updates")
+	var gasLimit = flag.Uint64("gasLimit", 0, "Gas limit of updates instead of the estimated gas, 0 to estimate")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
+	absoluteDeviations, err := oracleFeeder.ParseAbsoluteDeviations(*absoluteDeviationsFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	/*
 	 * Read secrets for unlocking the ETH account
@@ -76,26 +93,59 @@ func main() {
 	}
 
 	var contract *diaOracleServiceV2.DIAOracleV2
-	err = deployOrBindContract(*deployedContract, conn, auth, &contract)
+	var watcher *diaOracleServiceV2.ProxyWatcher
+	err = deployOrBindContract(*deployedContract, conn, auth, &contract, &watcher)
 	if err != nil {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
 
+	if *chainType == "" {
+		*chainType = feeEstimation.ChainType(*chainId)
+	}
+	var maxFee *big.Int
+	if *maxFeeWei != "" {
+		var ok bool
+		maxFee, ok = new(big.Int).SetString(*maxFeeWei, 10)
+		if !ok {
+			log.Fatalf("Invalid maxFeeWei %s", *maxFeeWei)
+		}
+	}
+	estimator, err := feeEstimation.NewEstimator(conn, *chainType, maxFee, feeEstimation.GasLimit{MarginPercent: *gasMarginPercent, Override: *gasLimit})
+	if err != nil {
+		log.Fatalf("Failed to set up fee estimation: %v", err)
+	}
+
 	/*
 	 * Update Oracle periodically with top coins
 	 */
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
 	ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
 	go func() {
 		for {
 			select {
 			case <-ticker.C:
+				if err := watcher.Check(); err != nil {
+					log.WithError(err).Warn("skipping oracle updates")
+					continue
+				}
 				for _, s := range symbols {
+					if !breaker.Allow() {
+						log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+						break
+					}
 					oldPrice := oldPrices[s]
-					oldPrice, err = periodicOracleUpdateHelper(oldPrice, *deviationPermille, auth, contract, conn, s)
+					oldPrice, err = periodicOracleUpdateHelper(oldPrice, *deviationPermille, absoluteDeviations[s], auth, contract, watcher.Address(), estimator, conn, s)
 					oldPrices[s] = oldPrice
+					// Updates whose gas can't be estimated would revert, so they are skipped
+					// without counting as failures.
+					if errors.Is(err, feeEstimation.ErrEstimateGas) {
+						log.WithError(err).WithField("symbol", s).Warn("skipping update")
+						err = nil
+					}
 					if err != nil {
-						log.Println(err)
+						log.WithError(err).Error("oracle update failed")
 					}
+					breaker.Record(err)
 					time.Sleep(time.Duration(*sleepSeconds) * time.Second)
 				}
 			}
@@ -104,25 +154,22 @@ func main() {
 	select {}
 }
 
-func periodicOracleUpdateHelper(oldPrice float64, deviationPermille int, auth *bind.TransactOpts, contract *diaOracleServiceV2.DIAOracleV2, conn *ethclient.Client, symbol string) (float64, error) {
+func periodicOracleUpdateHelper(oldPrice float64, deviationPermille int, absoluteDeviation float64, auth *bind.TransactOpts, contract *diaOracleServiceV2.DIAOracleV2, contractAddress common.Address, estimator *feeEstimation.Estimator, conn *ethclient.Client, symbol string) (float64, error) {
 
 	// Get quotation for token and update Oracle
-	rawQ, err := getQuotationFromDia(symbol)
+	rawQ, err := oracleFeeder.GetFeedQuotation(symbol, getQuotationFromDia)
 	if err != nil {
-		log.Fatalf("Failed to retrieve %s quotation data from DIA: %v", symbol, err)
-		return oldPrice, err
+		return oldPrice, fmt.Errorf("Failed to retrieve %s quotation data from DIA: %v", symbol, err)
 	}
-	rawQ.Name = symbol
 
 	// Check for deviation
 	newPrice := rawQ.Price
 
-	if (newPrice > (oldPrice * (1 + float64(deviationPermille)/1000))) || (newPrice < (oldPrice * (1 - float64(deviationPermille)/1000))) {
-		log.Println("Entering deviation based update zone")
-		err = updateQuotation(rawQ, auth, contract, conn)
+	if (newPrice > (oldPrice * (1 + float64(deviationPermille)/1000))) || (newPrice < (oldPrice * (1 - float64(deviationPermille)/1000))) || (absoluteDeviation > 0 && math.Abs(newPrice-oldPrice) > absoluteDeviation) {
+		log.WithFields(log.Fields{"oldPrice": oldPrice, "newPrice": newPrice}).Info("deviation exceeded, updating oracle")
+		err = updateQuotation(rawQ, auth, contract, contractAddress, estimator, conn)
 		if err != nil {
-			log.Fatalf("Failed to update DIA Oracle: %v", err)
-			return oldPrice, err
+			return oldPrice, fmt.Errorf("Failed to update DIA Oracle: %w", err)
 		}
 		return newPrice, nil
 	}
@@ -130,37 +177,37 @@ func periodicOracleUpdateHelper(oldPrice float64, deviationPermille int, auth *b
 	return oldPrice, nil
 }
 
-func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth *bind.TransactOpts, contract **diaOracleServiceV2.DIAOracleV2) error {
+func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth *bind.TransactOpts, contract **diaOracleServiceV2.DIAOracleV2, watcher **diaOracleServiceV2.ProxyWatcher) error {
 	var err error
+	addr := common.HexToAddress(deployedContract)
 	if deployedContract != "" {
-		*contract, err = diaOracleServiceV2.NewDIAOracleV2(common.HexToAddress(deployedContract), conn)
+		*contract, err = diaOracleServiceV2.NewDIAOracleV2(addr, conn)
 		if err != nil {
 			return err
 		}
 	} else {
 		// deploy contract
-		var addr common.Address
 		var tx *types.Transaction
 		addr, tx, *contract, err = diaOracleServiceV2.DeployDIAOracleV2(auth, conn)
 		if err != nil {
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
-	return nil
+	// Proxies are resolved so that the implementation behind them is checked for compatibility.
+	*watcher, err = diaOracleServiceV2.NewProxyWatcher(conn, addr)
+	return err
 }
 
-func updateQuotation(quotation *models.Quotation, auth *bind.TransactOpts, contract *diaOracleServiceV2.DIAOracleV2, conn *ethclient.Client) error {
-	symbol := quotation.Symbol + "/USD"
+func updateQuotation(quotation *models.Quotation, auth *bind.TransactOpts, contract *diaOracleServiceV2.DIAOracleV2, contractAddress common.Address, estimator *feeEstimation.Estimator, conn *ethclient.Client) error {
+	symbol := oracleFeeder.Key(quotation)
 	price := quotation.Price
 	timestamp := time.Now().Unix()
-	err := updateOracle(conn, contract, auth, symbol, int64(price*100000000), timestamp)
+	err := updateOracle(conn, contract, contractAddress, estimator, auth, symbol, int64(price*100000000), timestamp)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %w", err)
 	}
 
 	return nil
@@ -169,6 +216,8 @@ func updateQuotation(quotation *models.Quotation, auth *bind.TransactOpts, contr
 func updateOracle(
 	client *ethclient.Client,
 	contract *diaOracleServiceV2.DIAOracleV2,
+	contractAddress common.Address,
+	estimator *feeEstimation.Estimator,
 	auth *bind.TransactOpts,
 	key string,
 	value int64,
@@ -176,34 +225,46 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
+	// On rollups, SuggestGasPrice doesn't reflect the L1 data fee, which is most of the cost.
+	data, err := diaOracleServiceV2.PackSetValue(key, big.NewInt(value), big.NewInt(timestamp))
+	if err != nil {
+		return err
+	}
+	fee, err := estimator.Estimate(context.Background(), auth.From, contractAddress, data, gasPrice)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"chain": estimator.ChainType(), "key": key, "fee": fee.String()}).Debug("estimated fee")
+	err = estimator.CheckFee(fee)
+	if err != nil {
+		return err
+	}
+
 	// Write values to smart contract
 	tx, err := contract.SetValue(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 1000725,
+		GasLimit: fee.GasLimit,
 		GasPrice: gasPrice,
 	}, key, big.NewInt(value), big.NewInt(timestamp))
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("key: %s\n", key)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(log.Fields{"key": key, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
 
 func getQuotationFromDia(symbol string) (*models.Quotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/evm"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/randomness"
+	"github.com/diadata-org/diadata/pkg/logging"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// Publishes rounds of a drand randomness beacon into a DIARandomOracle contract. Each round is
+// verified against the public key of the drand network before it is sent from the feeder
+// wallet.
+func main() {
+	logging.Setup("diaRandomOracleService")
+	var deployedContract = flag.String("deployedContract", "", "Address of the deployed DIARandomOracle contract")
+	var secretsFile = flag.String("secretsFile", "/run/secrets/oracle_keys", "File with the json key and password lines of each wallet. Updates are spread round-robin across all wallets")
+	var blockchainNode = flag.String("blockchainNode", "https://polygon-rpc.com", "Node address for blockchain connection")
+	var chainId = flag.Int64("chainId", 137, "Chain-ID of the network to connect to")
+	var chainType = flag.String("chainType", "", "Fee model of the chain: l1, opstack or arbitrum. Detected from the chain id if empty")
+	var maxFeeWei = flag.String("maxFeeWei", "", "Maximal fee in wei of a single update, including the L1 data fee on rollups. Empty for no cap")
+	var gasMarginPercent = flag.Uint64("gasMarginPercent", feeEstimation.DefaultGasMarginPercent, "Percentage added to the estimated gas of updates")
+	var gasLimit = flag.Uint64("gasLimit", 0, "Gas limit of updates instead of the estimated gas, 0 to estimate")
+	var privateRelay = flag.String("privateRelay", "", "RPC of a private relay receiving the transactions instead of the public mempool, e.g. "+evm.FlashbotsProtectURL+" or "+evm.MEVBlockerURL+" on Ethereum mainnet. Empty to send to blockchainNode")
+	var drandURL = flag.String("drandURL", randomness.DefaultDrandURL, "HTTP relay of the drand network")
+	var chainHash = flag.String("chainHash", randomness.QuicknetChainHash, "Chain hash of the drand network, which must use the bls-unchained-g1-rfc9380 scheme")
+	var publicKey = flag.String("publicKey", "", "Hex encoded public key of the drand network. Taken from the relay if empty")
+	var frequencySeconds = flag.Int("frequencySeconds", 60, "Number of seconds between two published rounds")
+	var metricsAddr = flag.String("metricsAddr", ":9090", "Address serving /metrics and /metrics/dashboard, empty to disable")
+	var selftestMode = flag.Bool("selftest", false, "Verify all dependencies, print a report and exit")
+	flag.Parse()
+	if !*selftestMode {
+		metrics.Serve(*metricsAddr, "diaRandomOracleService")
+	}
+
+	if !common.IsHexAddress(*deployedContract) {
+		log.Fatal("deployedContract is required")
+	}
+	pinnedKey, err := hex.DecodeString(strings.TrimPrefix(*publicKey, "0x"))
+	if err != nil {
+		log.Fatalf("Invalid publicKey: %v", err)
+	}
+
+	/*
+	 * Read secrets for unlocking the ETH accounts
+	 */
+	wallets, err := evm.ReadWallets(*secretsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var maxFee *big.Int
+	if *maxFeeWei != "" {
+		var ok bool
+		maxFee, ok = new(big.Int).SetString(*maxFeeWei, 10)
+		if !ok {
+			log.Fatalf("Invalid maxFeeWei %s", *maxFeeWei)
+		}
+	}
+	chain, err := evm.NewChain(*blockchainNode, wallets, *chainId, *chainType, maxFee, feeEstimation.GasLimit{MarginPercent: *gasMarginPercent, Override: *gasLimit})
+	if err != nil {
+		log.Fatalf("Failed to connect to the Ethereum client: %v", err)
+	}
+	if *privateRelay != "" {
+		err = chain.SetPrivateRelay(*privateRelay)
+		if err != nil {
+			log.Fatalf("Failed to connect to the private relay: %v", err)
+		}
+	}
+	oracle, err := evm.NewRandomOracle(chain, common.HexToAddress(*deployedContract))
+	if err != nil {
+		log.Fatalf("Failed to bind contract: %v", err)
+	}
+
+	feeder, err := randomness.NewFeeder(randomness.NewDrand(*drandURL, *chainHash), pinnedKey, oracle, time.Duration(*frequencySeconds)*time.Second)
+	if err != nil {
+		log.Fatalf("Failed to set up drand network %s: %v", *chainHash, err)
+	}
+	if *selftestMode {
+		selftest.Exit("diaRandomOracleService", feeder.Checks())
+	}
+	log.Printf("Publishing rounds of drand network %s from %s", *chainHash, *drandURL)
+	feeder.Run()
+}
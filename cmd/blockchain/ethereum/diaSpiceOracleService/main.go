@@ -7,14 +7,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/big"
-	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/oracleService"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -25,10 +27,11 @@ import (
 var log *logrus.Logger
 
 func init() {
-	log = logrus.New()
+	log = logrus.StandardLogger()
 }
 
 func main() {
+	logging.Setup("diaSpiceOracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -38,6 +41,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 120, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 86400, "Number of seconds to sleep between full oracle runs")
 	var chainId = flag.Int64("chainId", 42, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -81,7 +86,12 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
-	periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
+	err = periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+	if err != nil {
+		log.WithError(err).Error("oracle update failed")
+	}
+	breaker.Record(err)
 	/*
 	 * Update Oracle periodically
 	 */
@@ -90,7 +100,15 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
+				err := periodicOracleUpdateHelper(*sleepSeconds, auth, contract, conn)
+				if err != nil {
+					log.WithError(err).Error("oracle update failed")
+				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -103,14 +121,12 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 	// SPICE Quotation
 	rawSpiceQ, err := getQuotationFromDia("SPICE")
 	if err != nil {
-		log.Fatalf("Failed to retrieve SPICE quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve SPICE quotation data from DIA: %v", err)
 	}
 	rawSpiceQ.Name = "SPICE"
 	err = updateQuotation(rawSpiceQ, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update SPICE Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update SPICE Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -118,15 +134,13 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 	// ETH Quotation
 	rawEthQ, err := getQuotationFromDia("ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve ETH quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve ETH quotation data from DIA: %v", err)
 	}
 	rawEthQ.Name = "WETH"
 	rawEthQ.Symbol = "WETH"
 	err = updateQuotation(rawEthQ, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update ETH Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update ETH Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -137,8 +151,7 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 	rawSpiceWethQ.Price = rawSpiceQ.Price / rawEthQ.Price
 	err = updateQuotation(rawSpiceWethQ, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update SPICE/WETH Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update SPICE/WETH Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -146,14 +159,12 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 	// USDC Quotation
 	rawUsdcQ, err := getQuotationFromDia("USDC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve USDC quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve USDC quotation data from DIA: %v", err)
 	}
 	rawUsdcQ.Name = "USDC"
 	err = updateQuotation(rawUsdcQ, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update USDC Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update USDC Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -161,14 +172,12 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 	// WBTC Quotation
 	rawWbtcQ, err := getQuotationFromDia("WBTC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve WBTC quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve WBTC quotation data from DIA: %v", err)
 	}
 	rawWbtcQ.Name = "WBTC"
 	err = updateQuotation(rawWbtcQ, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update WBTC Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update WBTC Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -176,7 +185,7 @@ func periodicOracleUpdateHelper(sleepSeconds int, auth *bind.TransactOpts, contr
 }
 
 func getQuotationFromDia(symbol string) (*models.Quotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -213,8 +222,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(logrus.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -226,8 +234,7 @@ func updateQuotation(quotation *models.Quotation, auth *bind.TransactOpts, contr
 	circSupply := 0
 	err := updateOracle(conn, contract, auth, symbol, symbol, int64(price*100000), int64(circSupply))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -244,28 +251,26 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.UpdateCoinInfo(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
-	}, name, symbol, big.NewInt(price), big.NewInt(supply), big.NewInt(time.Now().Unix()))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.UpdateCoinInfo(opts, name, symbol, big.NewInt(price), big.NewInt(supply), big.NewInt(time.Now().Unix()))
+	})
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("Symbol: %s\n", symbol)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
+	log.WithFields(logrus.Fields{"symbol": symbol, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
@@ -6,24 +6,27 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
-	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/oracleService"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("oracleService-eth")
 	/*
 	 * Read in Oracle address
 	 */
@@ -34,6 +37,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 120, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 86400, "Number of seconds to sleep between full oracle runs")
 	var chainId = flag.Int64("chainId", 1, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -79,7 +84,12 @@ func main() {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
 
-	periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
+	err = periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+	if err != nil {
+		log.WithError(err).Error("oracle update failed")
+	}
+	breaker.Record(err)
 	/*
 	 * Update Oracle periodically with top coins
 	 */
@@ -88,7 +98,15 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
+				err := periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+				if err != nil {
+					log.WithError(err).Error("oracle update failed")
+				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -105,54 +123,45 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	rawBTCQ, err := getQuotationFromDia("BTC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve BTC quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve BTC quotation data from DIA: %v", err)
 	}
 	rawBTCS, err := getSupplyFromDia("BTC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve BTC supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve BTC supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawBTCQ, rawBTCS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update BTC Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update BTC Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// ETH Quotation
 	rawETHQ, err := getQuotationFromDia("ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve ETH quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve ETH quotation data from DIA: %v", err)
 	}
 	rawETHS, err := getSupplyFromDia("ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve ETH supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve ETH supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawETHQ, rawETHS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update ETH Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update ETH Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// DIA Quotation
 	rawDIAQ, err := getQuotationFromDia("DIA")
 	if err != nil {
-		log.Fatalf("Failed to retrieve DIA quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve DIA quotation data from DIA: %v", err)
 	}
 	rawDIAS, err := getSupplyFromDia("DIA")
 	if err != nil {
-		log.Fatalf("Failed to retrieve DIA supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve DIA supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawDIAQ, rawDIAS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update DIA Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update DIA Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -163,13 +172,11 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// Maker Rate
 	rawMaker, err := getDefiRatesFromDia("MAKERDAO", "ETH-A")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Makerdao data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Makerdao data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawMaker, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Makerdao Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Makerdao Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -180,13 +187,11 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// CREAM State Data
 	rawCreamState, err := getDefiStateFromDia("CREAM")
 	if err != nil {
-		log.Fatalf("Failed to retrieve CREAM state data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve CREAM state data from DIA: %v", err)
 	}
 	err = updateDefiState(rawCreamState, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update CREAM state Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update CREAM state Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -197,14 +202,12 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// Pancakeswap Chart Point
 	rawPancake, err := getDEXFromDia("PanCakeSwap", "WBNB")
 	if err != nil {
-		log.Fatalf("Failed to retrieve PanCakeSwap from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve PanCakeSwap from DIA: %v", err)
 	}
 
 	err = updateDEX(rawPancake, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update PanCakeSwap Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update PanCakeSwap Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -215,14 +218,12 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// YFI WETH pool rate
 	rawYFI, err := getFarmingPoolFromDia("yfi", "WETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve YFI pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve YFI pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawYFI, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update YFI Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update YFI Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -241,8 +242,7 @@ func updateCoin(coin models.Coin, auth *bind.TransactOpts, contract *oracleServi
 	// Get 5 digits after the comma by multiplying price with 100000
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(*supply))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 	return nil
 }
@@ -256,8 +256,7 @@ func updateTopCoins(topCoins []models.Coin, sleepSeconds int, auth *bind.Transac
 		// Get 5 digits after the comma by multiplying price with 100000
 		err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(*supply))
 		if err != nil {
-			log.Fatalf("Failed to update Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Oracle: %v", err)
 		}
 		time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	}
@@ -274,14 +273,12 @@ func updateDEX(dexData *models.Points, auth *bind.TransactOpts, contract *oracle
 		// Set supply to 0, as we don't have a supply for one exchange
 		err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(supply))
 		if err != nil {
-			log.Fatalf("Failed to update Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Oracle: %v", err)
 		}
 	} else {
 		err := updateOracle(conn, contract, auth, "", "", int64(0), int64(0))
 		if err != nil {
-			log.Fatalf("Failed to update Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Oracle: %v", err)
 		}
 	}
 	return nil
@@ -295,8 +292,7 @@ func updateECBRate(ecbRate *models.CurrencyChange, auth *bind.TransactOpts, cont
 	// Set supply to 0, as we don't have a supply for fiat currencies
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), 0)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -310,8 +306,7 @@ func updateDefiRate(defiRate *dia.DefiRate, auth *bind.TransactOpts, contract *o
 	// Get 5 digits after the comma by multiplying price with 100000
 	err := updateOracle(conn, contract, auth, name, symbol, int64(lendingRate*100000), int64(borrowingRate*100000))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -324,8 +319,7 @@ func updateDefiState(defiState *dia.DefiProtocolState, auth *bind.TransactOpts,
 	// Get 5 digits after the comma by multiplying price with 100000
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), 0)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -337,8 +331,7 @@ func updateForeignQuotation(foreignQuotation *models.ForeignQuotation, auth *bin
 	price := foreignQuotation.Price
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), 0)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -351,8 +344,7 @@ func updateQuotation(quotation *models.Quotation, supply *dia.Supply, auth *bind
 	circSupply := supply.CirculatingSupply
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(circSupply))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -365,8 +357,7 @@ func updateFarmingPool(poolData *models.FarmingPool, auth *bind.TransactOpts, co
 	balance := poolData.Balance
 	err := updateOracle(conn, contract, auth, protocolName, poolID, int64(rate*100000), int64(balance*100000))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 	return nil
 }
@@ -387,8 +378,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n\n", tx.Hash())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -399,7 +389,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 // ------------------------------------------------------------------------------------------------
 
 func getCoinDetailsFromDia(symbol string) (*models.Coin, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/symbol/" + symbol)
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/symbol/" + symbol)
 	if err != nil {
 		return nil, err
 	} else {
@@ -424,7 +414,7 @@ func getCoinDetailsFromDia(symbol string) (*models.Coin, error) {
 }
 
 func getToplistFromDia() (*models.Coins, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/coins")
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/coins")
 	if err != nil {
 		return nil, err
 	} else {
@@ -450,7 +440,7 @@ func getToplistFromDia() (*models.Coins, error) {
 
 // Getting EUR vs XXX rate
 func getECBRatesFromDia(symbol string) (*models.CurrencyChange, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/coins")
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/coins")
 	if err != nil {
 		return nil, err
 	} else {
@@ -482,7 +472,7 @@ func getECBRatesFromDia(symbol string) (*models.CurrencyChange, error) {
 
 // Getting defi rate
 func getDefiRatesFromDia(protocol string, symbol string) (*dia.DefiRate, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/defiLendingRate/" + strings.ToUpper(protocol) + "/" + strings.ToUpper(symbol) + "/" + strconv.FormatInt(time.Now().Unix(), 10))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/defiLendingRate/" + strings.ToUpper(protocol) + "/" + strings.ToUpper(symbol) + "/" + strconv.FormatInt(time.Now().Unix(), 10))
 	if err != nil {
 		return nil, err
 	} else {
@@ -508,7 +498,7 @@ func getDefiRatesFromDia(protocol string, symbol string) (*dia.DefiRate, error)
 
 // Getting defi state
 func getDefiStateFromDia(protocol string) (*dia.DefiProtocolState, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/defiLendingState/" + strings.ToUpper(protocol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/defiLendingState/" + strings.ToUpper(protocol))
 	if err != nil {
 		return nil, err
 	} else {
@@ -533,7 +523,7 @@ func getDefiStateFromDia(protocol string) (*dia.DefiProtocolState, error) {
 }
 
 func getDEXFromDia(dexname string, symbol string) (*models.Points, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/chartPoints/MAIR120/" + dexname + "/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/chartPoints/MAIR120/" + dexname + "/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	} else {
@@ -558,7 +548,7 @@ func getDEXFromDia(dexname string, symbol string) (*models.Points, error) {
 }
 
 func getForeignQuotationFromDia(source, symbol string) (*models.ForeignQuotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/foreignQuotation/" + strings.Title(strings.ToLower(source)) + "/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/foreignQuotation/" + strings.Title(strings.ToLower(source)) + "/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -580,7 +570,7 @@ func getForeignQuotationFromDia(source, symbol string) (*models.ForeignQuotation
 }
 
 func getQuotationFromDia(symbol string) (*models.Quotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -602,7 +592,7 @@ func getQuotationFromDia(symbol string) (*models.Quotation, error) {
 }
 
 func getSupplyFromDia(symbol string) (*dia.Supply, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/supply/" + symbol)
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/supply/" + symbol)
 	if err != nil {
 		return nil, err
 	}
@@ -624,7 +614,7 @@ func getSupplyFromDia(symbol string) (*dia.Supply, error) {
 }
 
 func getFarmingPoolFromDia(protocol string, poolID string) (*models.FarmingPool, error) {
-	response, err := http.Get(dia.BaseUrl + "v1/FarmingPoolData/" + strings.ToUpper(protocol) + "/" + poolID)
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "v1/FarmingPoolData/" + strings.ToUpper(protocol) + "/" + poolID)
 
 	if err != nil {
 		return nil, err
@@ -658,31 +648,28 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.UpdateCoinInfo(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
 		//	Nonce: big.NewInt(time.Now().Unix()),
-	}, name, symbol, big.NewInt(price), big.NewInt(supply), big.NewInt(time.Now().Unix()))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.UpdateCoinInfo(opts, name, symbol, big.NewInt(price), big.NewInt(supply), big.NewInt(time.Now().Unix()))
+	})
 	// prices are with 5 digits after the comma
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("Symbol: %s\n", symbol)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
-	log.Printf("Tx Nonce: %d\n", tx.Nonce())
+	log.WithFields(log.Fields{"symbol": symbol, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "nonce": tx.Nonce(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
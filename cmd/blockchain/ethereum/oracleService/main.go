@@ -6,24 +6,27 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/big"
-	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
 	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/oracleService"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/logging"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	logging.Setup("oracleService")
 	/*
 	 * Read in Oracle address
 	 */
@@ -34,6 +37,8 @@ func main() {
 	var sleepSeconds = flag.Int("sleepSeconds", 120, "Number of seconds to sleep between calls")
 	var frequencySeconds = flag.Int("frequencySeconds", 86400, "Number of seconds to sleep between full oracle runs")
 	var chainId = flag.Int64("chainId", 1, "Chain-ID of the network to connect to")
+	var maxFailures = flag.Int("maxFailures", 5, "Number of consecutive failed updates after which updates are paused")
+	var cooldownSeconds = flag.Int("cooldownSeconds", 600, "Number of seconds updates are paused after maxFailures consecutive failures")
 	flag.Parse()
 
 	/*
@@ -77,7 +82,12 @@ func main() {
 		log.Fatalf("Failed to Deploy or Bind contract: %v", err)
 	}
 
-	periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+	breaker := utils.NewCircuitBreaker(*maxFailures, time.Duration(*cooldownSeconds)*time.Second)
+	err = periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+	if err != nil {
+		log.WithError(err).Error("oracle update failed")
+	}
+	breaker.Record(err)
 	/*
 	 * Update Oracle periodically with top coins
 	 */
@@ -86,7 +96,15 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+				if !breaker.Allow() {
+					log.WithField("failures", breaker.Failures()).Warn("skipping oracle update, circuit breaker open")
+					continue
+				}
+				err := periodicOracleUpdateHelper(topCoins, *sleepSeconds, auth, contract, conn)
+				if err != nil {
+					log.WithError(err).Error("oracle update failed")
+				}
+				breaker.Record(err)
 			}
 		}
 	}()
@@ -103,108 +121,90 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	rawBTCQ, err := getQuotationFromDia("BTC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve BTC quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve BTC quotation data from DIA: %v", err)
 	}
 	rawBTCS, err := getSupplyFromDia("BTC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve BTC supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve BTC supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawBTCQ, rawBTCS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update BTC Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update BTC Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// BNB Quotation
 	rawBNBQ, err := getQuotationFromDia("BNB")
 	if err != nil {
-		log.Fatalf("Failed to retrieve BNB quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve BNB quotation data from DIA: %v", err)
 	}
 	rawBNBS, err := getSupplyFromDia("BNB")
 	if err != nil {
-		log.Fatalf("Failed to retrieve BNB supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve BNB supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawBNBQ, rawBNBS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update BNB Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update BNB Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// ETH Quotation
 	rawETHQ, err := getQuotationFromDia("ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve ETH quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve ETH quotation data from DIA: %v", err)
 	}
 	rawETHS, err := getSupplyFromDia("ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve ETH supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve ETH supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawETHQ, rawETHS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update ETH Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update ETH Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// USDT Quotation
 	rawUSDTQ, err := getQuotationFromDia("USDT")
 	if err != nil {
-		log.Fatalf("Failed to retrieve USDT quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve USDT quotation data from DIA: %v", err)
 	}
 	rawUSDTS, err := getSupplyFromDia("USDT")
 	if err != nil {
-		log.Fatalf("Failed to retrieve USDT supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve USDT supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawUSDTQ, rawUSDTS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update USDT Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update USDT Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// XRP Quotation
 	rawXRPQ, err := getQuotationFromDia("XRP")
 	if err != nil {
-		log.Fatalf("Failed to retrieve XRP quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve XRP quotation data from DIA: %v", err)
 	}
 	rawXRPS, err := getSupplyFromDia("XRP")
 	if err != nil {
-		log.Fatalf("Failed to retrieve XRP supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve XRP supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawXRPQ, rawXRPS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update XRP Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update XRP Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// USDC Quotation
 	rawUSDCQ, err := getQuotationFromDia("USDC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve USDC quotation data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve USDC quotation data from DIA: %v", err)
 	}
 	rawUSDCS, err := getSupplyFromDia("USDC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve USDC supply data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve USDC supply data from DIA: %v", err)
 	}
 	err = updateQuotation(rawUSDCQ, rawUSDCS, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update USDC Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update USDC Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -215,117 +215,99 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// Maker Rate
 	rawMaker, err := getDefiRatesFromDia("MAKERDAO", "ETH-A")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Makerdao data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Makerdao data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawMaker, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Makerdao Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Makerdao Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// CREAM Rates
 	rawCream, err := getDefiRatesFromDia("CREAM", "UNI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve CREAM data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve CREAM data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawCream, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update CREAM Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update CREAM Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// fortube Rates
 	rawFortube, err := getDefiRatesFromDia("FORTUBE", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve forTube data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve forTube data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawFortube, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Fortube Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Fortube Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// nuo Rates
 	rawNuo, err := getDefiRatesFromDia("NUO", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Nuo data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Nuo data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawNuo, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Nuo Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Nuo Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// bZx Rates
 	rawBzx, err := getDefiRatesFromDia("BZX", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve bZx data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve bZx data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawBzx, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update bZx Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update bZx Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Compound Rates
 	rawCompound, err := getDefiRatesFromDia("COMPOUND", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Compound data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Compound data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawCompound, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Compound Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Compound Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// DYDX Rates
 	rawDydx, err := getDefiRatesFromDia("DYDX", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve DYDX data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve DYDX data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawDydx, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update DYDX Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update DYDX Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Aave Rates
 	rawAave, err := getDefiRatesFromDia("AAVE", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Aave data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Aave data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawAave, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Aave Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Aave Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Aave Rates
 	rawBitfinex, err := getDefiRatesFromDia("BITFINEX", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Bitfinex data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Bitfinex data from DIA: %v", err)
 	}
 	err = updateDefiRate(rawBitfinex, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Bitfinex Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Bitfinex Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -336,52 +318,44 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// MAKERDAO State Data
 	rawMakerState, err := getDefiStateFromDia("MAKERDAO")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Maker state data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Maker state data from DIA: %v", err)
 	}
 	err = updateDefiState(rawMakerState, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Maker state Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Maker state Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// CREAM State Data
 	rawCreamState, err := getDefiStateFromDia("CREAM")
 	if err != nil {
-		log.Fatalf("Failed to retrieve CREAM state data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve CREAM state data from DIA: %v", err)
 	}
 	err = updateDefiState(rawCreamState, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update CREAM state Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update CREAM state Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// DYDX State Data
 	rawDydxState, err := getDefiStateFromDia("DYDX")
 	if err != nil {
-		log.Fatalf("Failed to retrieve DYDX state data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve DYDX state data from DIA: %v", err)
 	}
 	err = updateDefiState(rawDydxState, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update DYDX state Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update DYDX state Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Compound State Data
 	rawCompoundState, err := getDefiStateFromDia("COMPOUND")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Compound state data from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Compound state data from DIA: %v", err)
 	}
 	err = updateDefiState(rawCompoundState, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Compound state Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Compound state Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -392,55 +366,47 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// ECB Chart Point
 	/*rawECB, err := getECBRatesFromDia("EUR")
 	if err != nil {
-		log.Fatalf("Failed to retrieve ECB from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve ECB from DIA: %v", err)
 	}
 	err = updateECBRate(rawECB, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update ECB Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update ECB Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)*/
 
 	// Pancakeswap Chart Point
 	rawPancake, err := getDEXFromDia("PanCakeSwap", "WBNB")
 	if err != nil {
-		log.Fatalf("Failed to retrieve PanCakeSwap from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve PanCakeSwap from DIA: %v", err)
 	}
 
 	err = updateDEX(rawPancake, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update PanCakeSwap Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update PanCakeSwap Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// CREX24 Chart Point
 	rawCrex24, err := getDEXFromDia("CREX24", "CREX")
 	if err != nil {
-		log.Fatalf("Failed to retrieve CREX24 from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve CREX24 from DIA: %v", err)
 	}
 
 	err = updateDEX(rawCrex24, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update CREX24 Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update CREX24 Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Bitmax CEX Chart Point
 	rawBitmax, err := getDEXFromDia("Bitmax", "ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Bitmax from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Bitmax from DIA: %v", err)
 	}
 
 	err = updateDEX(rawBitmax, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Bitmax Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Bitmax Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -461,42 +427,36 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// Curvefi DEX Chart Point
 	rawCurvefi, err := getDEXFromDia("Curvefi", "DAI")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Curvefi from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Curvefi from DIA: %v", err)
 	}
 
 	err = updateDEX(rawCurvefi, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Curvefi Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Curvefi Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Gnosis DEX Chart Point
 	rawGnosis, err := getDEXFromDia("Gnosis", "ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Gnosis from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Gnosis from DIA: %v", err)
 	}
 
 	err = updateDEX(rawGnosis, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Gnosis Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Gnosis Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Uniswap Chart Point
 	rawUniswap, err := getDEXFromDia("Uniswap", "ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Uniswap from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Uniswap from DIA: %v", err)
 	}
 
 	err = updateDEX(rawUniswap, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Uniswap Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Uniswap Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -517,83 +477,71 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// Bancor Chart Point
 	rawBancor, err := getDEXFromDia("Bancor", "ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Bancor from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Bancor from DIA: %v", err)
 	}
 
 	err = updateDEX(rawBancor, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Bancor Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Bancor Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// 0x Chart Point
 	raw0x, err := getDEXFromDia("0x", "ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve 0x from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve 0x from DIA: %v", err)
 	}
 
 	err = updateDEX(raw0x, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update 0x Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update 0x Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Kyber Chart Point
 	rawKyber, err := getDEXFromDia("Kyber", "ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Kyber from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Kyber from DIA: %v", err)
 	}
 
 	err = updateDEX(rawKyber, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Kyber Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Kyber Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Sushi Chart Point
 	rawSushi, err := getDEXFromDia("SushiSwap", "ETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Sushi from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Sushi from DIA: %v", err)
 	}
 
 	err = updateDEX(rawSushi, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Sushi Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Sushi Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// STEX Chart Point
 	rawSTEX, err := getDEXFromDia("STEX", "PLEX")
 	if err != nil {
-		log.Fatalf("Failed to retrieve STEX from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve STEX from DIA: %v", err)
 	}
 
 	err = updateDEX(rawSTEX, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update STEX Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update STEX Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// DIA token
 	diaToken, err := getCoinDetailsFromDia("DIA")
 	if err != nil {
-		log.Fatalf("Failed to retrieve token DIA from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve token DIA from DIA: %v", err)
 	}
 	err = updateCoin(*diaToken, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update DIA Token Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update DIA Token Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
@@ -604,106 +552,91 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 	// Balancer WETH/WBTC pool rate
 	/*rawBalancer, err := getFarmingPoolFromDia("Balancer", "0x1efF8aF5D577060BA4ac8A29A13525bb0Ee2A3D5")
 	if err != nil {
-		log.Fatalf("Failed to retrieve Balancer pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve Balancer pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawBalancer, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Balancer Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Balancer Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// CVAULT WETH pool rate
 	rawCvault, err := getFarmingPoolFromDia("Cvault", "0")
 	if err != nil {
-		log.Fatalf("Failed to retrieve CVAULT pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve CVAULT pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawCvault, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update CVAULT Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update CVAULT Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)*/
 
 	// YFI WETH pool rate
 	rawYFI, err := getFarmingPoolFromDia("yfi", "WETH")
 	if err != nil {
-		log.Fatalf("Failed to retrieve YFI pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve YFI pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawYFI, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update YFI Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update YFI Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// SYNTHETIX sETH total debt
 	rawSYNTHETIX, err := getFarmingPoolFromDia("Synthetix", "0xD0DC005d31C2979CC0d38718e23c82D1A50004C0")
 	if err != nil {
-		log.Fatalf("Failed to retrieve SYNTHETIX pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve SYNTHETIX pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawSYNTHETIX, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update SYNTHETIX Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update SYNTHETIX Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// LOOPRING total reward
 	rawLRC, err := getFarmingPoolFromDia("Loopring", "LRC")
 	if err != nil {
-		log.Fatalf("Failed to retrieve LOOPRING pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve LOOPRING pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawLRC, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update LOOPRING Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update LOOPRING Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// CURVEFI virtual price
 	rawCURVEFI, err := getFarmingPoolFromDia("Curvefi", "3")
 	if err != nil {
-		log.Fatalf("Failed to retrieve CURVEFI pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve CURVEFI pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawCURVEFI, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update CURVEFI Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update CURVEFI Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// BARNBRIDGE total reward
 	rawBARNBRIDGE, err := getFarmingPoolFromDia("BARNBRIDGE", "STABLECOIN")
 	if err != nil {
-		log.Fatalf("Failed to retrieve BARNBRIDGE pool from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve BARNBRIDGE pool from DIA: %v", err)
 	}
 
 	err = updateFarmingPool(rawBARNBRIDGE, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update BARNBRIDGE Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update BARNBRIDGE Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)
 
 	// Top 15 coins
 	/*rawCoins, err := getToplistFromDia()
 	if err != nil {
-		log.Fatalf("Failed to retrieve toplist from DIA: %v", err)
-		return err
+		return fmt.Errorf("Failed to retrieve toplist from DIA: %v", err)
 	}
 
 	cleanedCoins := []models.Coin{}
@@ -720,8 +653,7 @@ func periodicOracleUpdateHelper(topCoins *int, sleepSeconds int, auth *bind.Tran
 
 	err = updateTopCoins(topCoinSlice, auth, contract, conn)
 	if err != nil {
-		log.Fatalf("Failed to update Coins Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Coins Oracle: %v", err)
 	}
 	time.Sleep(time.Duration(sleepSeconds) * time.Second)*/
 
@@ -740,8 +672,7 @@ func updateCoin(coin models.Coin, auth *bind.TransactOpts, contract *oracleServi
 	// Get 5 digits after the comma by multiplying price with 100000
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(*supply))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 	return nil
 }
@@ -755,8 +686,7 @@ func updateTopCoins(topCoins []models.Coin, sleepSeconds int, auth *bind.Transac
 		// Get 5 digits after the comma by multiplying price with 100000
 		err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(*supply))
 		if err != nil {
-			log.Fatalf("Failed to update Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Oracle: %v", err)
 		}
 		time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	}
@@ -773,14 +703,12 @@ func updateDEX(dexData *models.Points, auth *bind.TransactOpts, contract *oracle
 		// Set supply to 0, as we don't have a supply for one exchange
 		err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(supply))
 		if err != nil {
-			log.Fatalf("Failed to update Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Oracle: %v", err)
 		}
 	} else {
 		err := updateOracle(conn, contract, auth, "", "", int64(0), int64(0))
 		if err != nil {
-			log.Fatalf("Failed to update Oracle: %v", err)
-			return err
+			return fmt.Errorf("Failed to update Oracle: %v", err)
 		}
 	}
 	return nil
@@ -794,8 +722,7 @@ func updateECBRate(ecbRate *models.CurrencyChange, auth *bind.TransactOpts, cont
 	// Set supply to 0, as we don't have a supply for fiat currencies
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), 0)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -809,8 +736,7 @@ func updateDefiRate(defiRate *dia.DefiRate, auth *bind.TransactOpts, contract *o
 	// Get 5 digits after the comma by multiplying price with 100000
 	err := updateOracle(conn, contract, auth, name, symbol, int64(lendingRate*100000), int64(borrowingRate*100000))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -823,8 +749,7 @@ func updateDefiState(defiState *dia.DefiProtocolState, auth *bind.TransactOpts,
 	// Get 5 digits after the comma by multiplying price with 100000
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), 0)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -836,8 +761,7 @@ func updateForeignQuotation(foreignQuotation *models.ForeignQuotation, auth *bin
 	price := foreignQuotation.Price
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), 0)
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -850,8 +774,7 @@ func updateQuotation(quotation *models.Quotation, supply *dia.Supply, auth *bind
 	circSupply := supply.CirculatingSupply
 	err := updateOracle(conn, contract, auth, name, symbol, int64(price*100000), int64(circSupply))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 
 	return nil
@@ -864,8 +787,7 @@ func updateFarmingPool(poolData *models.FarmingPool, auth *bind.TransactOpts, co
 	balance := poolData.Balance
 	err := updateOracle(conn, contract, auth, protocolName, poolID, int64(rate*100000), int64(balance*100000))
 	if err != nil {
-		log.Fatalf("Failed to update Oracle: %v", err)
-		return err
+		return fmt.Errorf("Failed to update Oracle: %v", err)
 	}
 	return nil
 }
@@ -897,10 +819,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 			log.Fatalf("could not deploy contract: %v", err)
 			return err
 		}
-		fmt.Println(tx.GasPrice())
-		log.Printf("Contract pending deploy: 0x%x\n", addr)
-		log.Printf("Transaction waiting to be mined: 0x%x\n", tx.Hash())
-		log.Printf("Tx Nonce: %d\n\n", tx.Nonce())
+		log.WithFields(log.Fields{"contract": addr.Hex(), "txHash": tx.Hash().Hex(), "nonce": tx.Nonce(), "gasPrice": tx.GasPrice()}).Info("contract pending deploy")
 		time.Sleep(180000 * time.Millisecond)
 	}
 	return nil
@@ -911,7 +830,7 @@ func deployOrBindContract(deployedContract string, conn *ethclient.Client, auth
 // ------------------------------------------------------------------------------------------------
 
 func getCoinDetailsFromDia(symbol string) (*models.Coin, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/symbol/" + symbol)
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/symbol/" + symbol)
 	if err != nil {
 		return nil, err
 	} else {
@@ -936,7 +855,7 @@ func getCoinDetailsFromDia(symbol string) (*models.Coin, error) {
 }
 
 func getToplistFromDia() (*models.Coins, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/coins")
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/coins")
 	if err != nil {
 		return nil, err
 	} else {
@@ -962,7 +881,7 @@ func getToplistFromDia() (*models.Coins, error) {
 
 // Getting EUR vs XXX rate
 func getECBRatesFromDia(symbol string) (*models.CurrencyChange, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/coins")
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/coins")
 	if err != nil {
 		return nil, err
 	} else {
@@ -994,7 +913,7 @@ func getECBRatesFromDia(symbol string) (*models.CurrencyChange, error) {
 
 // Getting defi rate
 func getDefiRatesFromDia(protocol string, symbol string) (*dia.DefiRate, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/defiLendingRate/" + strings.ToUpper(protocol) + "/" + strings.ToUpper(symbol) + "/" + strconv.FormatInt(time.Now().Unix(), 10))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/defiLendingRate/" + strings.ToUpper(protocol) + "/" + strings.ToUpper(symbol) + "/" + strconv.FormatInt(time.Now().Unix(), 10))
 	if err != nil {
 		return nil, err
 	} else {
@@ -1020,7 +939,7 @@ func getDefiRatesFromDia(protocol string, symbol string) (*dia.DefiRate, error)
 
 // Getting defi state
 func getDefiStateFromDia(protocol string) (*dia.DefiProtocolState, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/defiLendingState/" + strings.ToUpper(protocol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/defiLendingState/" + strings.ToUpper(protocol))
 	if err != nil {
 		return nil, err
 	} else {
@@ -1045,7 +964,7 @@ func getDefiStateFromDia(protocol string) (*dia.DefiProtocolState, error) {
 }
 
 func getDEXFromDia(dexname string, symbol string) (*models.Points, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/chartPoints/MAIR120/" + dexname + "/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/chartPoints/MAIR120/" + dexname + "/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	} else {
@@ -1070,7 +989,7 @@ func getDEXFromDia(dexname string, symbol string) (*models.Points, error) {
 }
 
 func getForeignQuotationFromDia(source, symbol string) (*models.ForeignQuotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/foreignQuotation/" + strings.Title(strings.ToLower(source)) + "/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/foreignQuotation/" + strings.Title(strings.ToLower(source)) + "/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -1092,7 +1011,7 @@ func getForeignQuotationFromDia(source, symbol string) (*models.ForeignQuotation
 }
 
 func getQuotationFromDia(symbol string) (*models.Quotation, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
 	if err != nil {
 		return nil, err
 	}
@@ -1114,7 +1033,7 @@ func getQuotationFromDia(symbol string) (*models.Quotation, error) {
 }
 
 func getSupplyFromDia(symbol string) (*dia.Supply, error) {
-	response, err := http.Get(dia.BaseUrl + "/v1/supply/" + symbol)
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/supply/" + symbol)
 	if err != nil {
 		return nil, err
 	}
@@ -1136,7 +1055,7 @@ func getSupplyFromDia(symbol string) (*dia.Supply, error) {
 }
 
 func getFarmingPoolFromDia(protocol string, poolID string) (*models.FarmingPool, error) {
-	response, err := http.Get(dia.BaseUrl + "v1/FarmingPoolData/" + strings.ToUpper(protocol) + "/" + poolID)
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "v1/FarmingPoolData/" + strings.ToUpper(protocol) + "/" + poolID)
 
 	if err != nil {
 		return nil, err
@@ -1170,31 +1089,28 @@ func updateOracle(
 
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Get 110% of the gas price
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("suggested gas price")
 	fGas := new(big.Float).SetInt(gasPrice)
 	fGas.Mul(fGas, big.NewFloat(1.1))
 	gasPrice, _ = fGas.Int(nil)
-	fmt.Println(gasPrice)
+	log.WithField("gasPrice", gasPrice).Debug("gas price with margin")
 	// Write values to smart contract
-	tx, err := contract.UpdateCoinInfo(&bind.TransactOpts{
+	tx, err := feeEstimation.DefaultGasLimit.Transact(&bind.TransactOpts{
 		From:     auth.From,
 		Signer:   auth.Signer,
-		GasLimit: 800725,
 		GasPrice: gasPrice,
 		//	Nonce: big.NewInt(time.Now().Unix()),
-	}, name, symbol, big.NewInt(price), big.NewInt(supply), big.NewInt(time.Now().Unix()))
+	}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.UpdateCoinInfo(opts, name, symbol, big.NewInt(price), big.NewInt(supply), big.NewInt(time.Now().Unix()))
+	})
 	// prices are with 5 digits after the comma
 	if err != nil {
 		return err
 	}
-	fmt.Println(tx.GasPrice())
-	log.Printf("Symbol: %s\n", symbol)
-	log.Printf("Tx To: %s\n", tx.To().String())
-	log.Printf("Tx Hash: 0x%x\n", tx.Hash())
-	log.Printf("Tx Nonce: %d\n", tx.Nonce())
+	log.WithFields(log.Fields{"symbol": symbol, "txHash": tx.Hash().Hex(), "to": tx.To().Hex(), "nonce": tx.Nonce(), "gasPrice": tx.GasPrice()}).Info("transaction sent")
 	return nil
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/near"
+	"github.com/diadata-org/diadata/pkg/logging"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	logging.Setup("diaOracleNearService")
+	var deployedContract = flag.String("deployedContract", "", "Account ID of the deployed oracle contract")
+	var secretsFile = flag.String("secretsFile", "/run/secrets/oracle_keys", "near-cli credentials file of the signing account")
+	var blockchainNode = flag.String("blockchainNode", "https://rpc.mainnet.near.org", "JSON-RPC node address for blockchain connection")
+	var method = flag.String("method", "set_value", "Contract method called with key, value and timestamp")
+	var gas = flag.Uint64("gas", 30000000000000, "Gas attached to each call")
+	var deposit = flag.String("deposit", "0", "Deposit in yoctoNEAR attached to each call")
+	var symbolsFlag = flag.String("symbols", "BTC,ETH,DIA,USDC,NEAR", "Comma separated list of symbols, quoted in USD unless given as SYMBOL/QUOTE, e.g. ETH/EUR or MATIC/ETH")
+	var sleepSeconds = flag.Int("sleepSeconds", 10, "Number of seconds to sleep between calls")
+	var frequencySeconds = flag.Int("frequencySeconds", 120, "Number of seconds to sleep between checking oracle runs")
+	var deviationPermille = flag.Int("deviationPermille", 10, "Permille of deviation to trigger an oracle update")
+	var absoluteDeviationsFlag = flag.String("absoluteDeviations", "", "Comma separated SYMBOL:USD absolute deviations which also trigger an update, e.g. USDT:0.002")
+	var heartbeatSeconds = flag.Int("heartbeatSeconds", 0, "Number of seconds after which an update is forced, 0 to disable")
+	var twapWindowSeconds = flag.Int("twapWindowSeconds", 0, "Push the time-weighted average over this many seconds instead of the latest quotation, 0 to disable")
+	var twapFilter = flag.String("twapFilter", "MA120", "Filter of the filters service the time-weighted average is taken of")
+	var fiatSymbolsFlag = flag.String("fiatSymbols", "", "Comma separated fiat currencies priced by the foreign quotations of fiatSource, e.g. EUR,GBP,JPY to feed EUR/USD or ETH/EUR")
+	var fiatSource = flag.String("fiatSource", "", "Source of the foreign quotation endpoint of the DIA API serving the rates of fiatSymbols")
+	var fiatMaxAgeHours = flag.Int("fiatMaxAgeHours", 96, "Number of hours after which a fiat rate is stale and not pushed, spanning weekends and holidays")
+	var decisionLogFile = flag.String("decisionLogFile", "", "File recording all update decisions, served under /decisions on metricsAddr. Empty to keep them in memory only")
+	var decisionRetentionHours = flag.Int("decisionRetentionHours", 168, "Number of hours update decisions are kept")
+	var adminTokenFile = flag.String("adminTokenFile", "/run/secrets/feeder_admin_token", "File holding the bearer token of the admin API served under /admin/ on metricsAddr. The API is disabled if the file doesn't exist")
+	var metricsAddr = flag.String("metricsAddr", ":9090", "Address serving /metrics and /metrics/dashboard, empty to disable")
+	var selftestMode = flag.Bool("selftest", false, "Verify all dependencies, print a report and exit")
+	flag.Parse()
+	if !*selftestMode {
+		metrics.Serve(*metricsAddr, "diaOracleNearService")
+	}
+
+	if *deployedContract == "" {
+		log.Fatal("deployedContract is required, contracts on NEAR are deployed with near-cli")
+	}
+	depositAmount, ok := new(big.Int).SetString(*deposit, 10)
+	if !ok {
+		log.Fatalf("Invalid deposit %s", *deposit)
+	}
+
+	account, err := near.LoadAccount(*secretsFile)
+	if err != nil {
+		log.Fatalf("Failed to load NEAR account: %v", err)
+	}
+	client := near.NewClient(*blockchainNode, account)
+	oracle := near.NewOracle(client, *deployedContract, *method, *gas, depositAmount)
+
+	absoluteDeviations, err := oracleFeeder.ParseAbsoluteDeviations(*absoluteDeviationsFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var fiatSymbols []string
+	if *fiatSymbolsFlag != "" {
+		if *fiatSource == "" {
+			log.Fatal("fiatSource is required to feed fiatSymbols")
+		}
+		fiatSymbols = strings.Split(*fiatSymbolsFlag, ",")
+	}
+	decisions, err := oracleFeeder.NewDecisionLog(*decisionLogFile, time.Duration(*decisionRetentionHours)*time.Hour)
+	if err != nil {
+		log.Fatalf("Failed to open decision log: %v", err)
+	}
+	metrics.Handle("/decisions", decisions.Handler())
+	feeder := oracleFeeder.NewFeeder(oracleFeeder.Config{
+		Symbols:            strings.Split(*symbolsFlag, ","),
+		Frequency:          time.Duration(*frequencySeconds) * time.Second,
+		Sleep:              time.Duration(*sleepSeconds) * time.Second,
+		DeviationPermille:  *deviationPermille,
+		AbsoluteDeviations: absoluteDeviations,
+		Heartbeat:          time.Duration(*heartbeatSeconds) * time.Second,
+		TWAPWindow:         time.Duration(*twapWindowSeconds) * time.Second,
+		TWAPFilter:         *twapFilter,
+		FiatSymbols:        fiatSymbols,
+		FiatSource:         *fiatSource,
+		FiatMaxAge:         time.Duration(*fiatMaxAgeHours) * time.Hour,
+		Decisions:          decisions,
+	}, oracle)
+	adminToken, err := oracleFeeder.ReadAdminToken(*adminTokenFile)
+	if err != nil {
+		log.Fatalf("Failed to read admin token: %v", err)
+	}
+	if adminToken != "" {
+		metrics.Handle("/admin/", feeder.AdminHandler(adminToken))
+	}
+	log.Printf("Feeding %s from account %s", *deployedContract, account.AccountID)
+	if *selftestMode {
+		selftest.Exit("diaOracleNearService", feeder.Checks())
+	}
+	feeder.Run()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/starknet"
+	"github.com/diadata-org/diadata/pkg/logging"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	logging.Setup("diaOracleStarknetService")
+	var deployedContract = flag.String("deployedContract", "", "Address of the deployed Cairo oracle contract")
+	var accountAddress = flag.String("account", "", "Address of the account contract sending the updates")
+	var cairoVersion = flag.Int("cairoVersion", 1, "Cairo version of the account contract, 0 or 1")
+	var secretsFile = flag.String("secretsFile", "/run/secrets/oracle_keys", "File with the hex encoded private key of the account's signer")
+	var blockchainNode = flag.String("blockchainNode", "", "JSON-RPC node address for blockchain connection")
+	var maxFee = flag.String("maxFee", "", "Maximal fee in wei paid by a single update, empty for no limit")
+	var symbolsFlag = flag.String("symbols", "BTC,ETH,DIA,USDC,STRK", "Comma separated list of symbols, quoted in USD unless given as SYMBOL/QUOTE, e.g. ETH/EUR or MATIC/ETH")
+	var sleepSeconds = flag.Int("sleepSeconds", 10, "Number of seconds to sleep between calls")
+	var frequencySeconds = flag.Int("frequencySeconds", 120, "Number of seconds to sleep between checking oracle runs")
+	var deviationPermille = flag.Int("deviationPermille", 10, "Permille of deviation to trigger an oracle update")
+	var absoluteDeviationsFlag = flag.String("absoluteDeviations", "", "Comma separated SYMBOL:USD absolute deviations which also trigger an update, e.g. USDT:0.002")
+	var heartbeatSeconds = flag.Int("heartbeatSeconds", 0, "Number of seconds after which an update is forced, 0 to disable")
+	var twapWindowSeconds = flag.Int("twapWindowSeconds", 0, "Push the time-weighted average over this many seconds instead of the latest quotation, 0 to disable")
+	var twapFilter = flag.String("twapFilter", "MA120", "Filter of the filters service the time-weighted average is taken of")
+	var fiatSymbolsFlag = flag.String("fiatSymbols", "", "Comma separated fiat currencies priced by the foreign quotations of fiatSource, e.g. EUR,GBP,JPY to feed EUR/USD or ETH/EUR")
+	var fiatSource = flag.String("fiatSource", "", "Source of the foreign quotation endpoint of the DIA API serving the rates of fiatSymbols")
+	var fiatMaxAgeHours = flag.Int("fiatMaxAgeHours", 96, "Number of hours after which a fiat rate is stale and not pushed, spanning weekends and holidays")
+	var decisionLogFile = flag.String("decisionLogFile", "", "File recording all update decisions, served under /decisions on metricsAddr. Empty to keep them in memory only")
+	var decisionRetentionHours = flag.Int("decisionRetentionHours", 168, "Number of hours update decisions are kept")
+	var adminTokenFile = flag.String("adminTokenFile", "/run/secrets/feeder_admin_token", "File holding the bearer token of the admin API served under /admin/ on metricsAddr. The API is disabled if the file doesn't exist")
+	var metricsAddr = flag.String("metricsAddr", ":9090", "Address serving /metrics and /metrics/dashboard, empty to disable")
+	var selftestMode = flag.Bool("selftest", false, "Verify all dependencies, print a report and exit")
+	flag.Parse()
+	if !*selftestMode {
+		metrics.Serve(*metricsAddr, "diaOracleStarknetService")
+	}
+
+	if *blockchainNode == "" {
+		log.Fatal("blockchainNode is required")
+	}
+	contract, err := starknet.ParseFelt(*deployedContract)
+	if err != nil {
+		log.Fatalf("Invalid deployedContract: %v", err)
+	}
+	address, err := starknet.ParseFelt(*accountAddress)
+	if err != nil {
+		log.Fatalf("Invalid account: %v", err)
+	}
+	var maxFeeAmount *big.Int
+	if *maxFee != "" {
+		var ok bool
+		maxFeeAmount, ok = new(big.Int).SetString(*maxFee, 10)
+		if !ok {
+			log.Fatalf("Invalid maxFee %s", *maxFee)
+		}
+	}
+	secret, err := ioutil.ReadFile(*secretsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	privateKey, err := starknet.ParseFelt(strings.TrimSpace(string(secret)))
+	if err != nil {
+		log.Fatalf("Failed to parse private key: %v", err)
+	}
+
+	account, err := starknet.NewAccount(starknet.NewClient(*blockchainNode), address, privateKey, *cairoVersion, maxFeeAmount)
+	if err != nil {
+		log.Fatalf("Failed to set up account: %v", err)
+	}
+	oracle := starknet.NewOracle(account, contract)
+
+	absoluteDeviations, err := oracleFeeder.ParseAbsoluteDeviations(*absoluteDeviationsFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var fiatSymbols []string
+	if *fiatSymbolsFlag != "" {
+		if *fiatSource == "" {
+			log.Fatal("fiatSource is required to feed fiatSymbols")
+		}
+		fiatSymbols = strings.Split(*fiatSymbolsFlag, ",")
+	}
+	decisions, err := oracleFeeder.NewDecisionLog(*decisionLogFile, time.Duration(*decisionRetentionHours)*time.Hour)
+	if err != nil {
+		log.Fatalf("Failed to open decision log: %v", err)
+	}
+	metrics.Handle("/decisions", decisions.Handler())
+	feeder := oracleFeeder.NewFeeder(oracleFeeder.Config{
+		Symbols:            strings.Split(*symbolsFlag, ","),
+		Frequency:          time.Duration(*frequencySeconds) * time.Second,
+		Sleep:              time.Duration(*sleepSeconds) * time.Second,
+		DeviationPermille:  *deviationPermille,
+		AbsoluteDeviations: absoluteDeviations,
+		Heartbeat:          time.Duration(*heartbeatSeconds) * time.Second,
+		TWAPWindow:         time.Duration(*twapWindowSeconds) * time.Second,
+		TWAPFilter:         *twapFilter,
+		FiatSymbols:        fiatSymbols,
+		FiatSource:         *fiatSource,
+		FiatMaxAge:         time.Duration(*fiatMaxAgeHours) * time.Hour,
+		Decisions:          decisions,
+	}, oracle)
+	adminToken, err := oracleFeeder.ReadAdminToken(*adminTokenFile)
+	if err != nil {
+		log.Fatalf("Failed to read admin token: %v", err)
+	}
+	if adminToken != "" {
+		metrics.Handle("/admin/", feeder.AdminHandler(adminToken))
+	}
+	log.Printf("Feeding %s from account %s", *deployedContract, *accountAddress)
+	if *selftestMode {
+		selftest.Exit("diaOracleStarknetService", feeder.Checks())
+	}
+	feeder.Run()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	scrapers "github.com/diadata-org/diadata/internal/pkg/exchange-scrapers"
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+// parseBackfillRange parses a range such as "from=2024-01-01T00:00:00Z to=2024-01-02T00:00:00Z".
+// Times are RFC3339 or unix seconds, to defaults to now.
+func parseBackfillRange(s string) (from time.Time, to time.Time, err error) {
+	to = time.Now()
+	for _, field := range strings.Fields(s) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return from, to, fmt.Errorf("invalid backfill parameter %q", field)
+		}
+		t, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			t, err = utils.StrToUnixtime(parts[1])
+			if err != nil {
+				return from, to, fmt.Errorf("invalid time %q", parts[1])
+			}
+		}
+		switch parts[0] {
+		case "from":
+			from = t
+		case "to":
+			to = t
+		default:
+			return from, to, fmt.Errorf("unknown backfill parameter %q", parts[0])
+		}
+	}
+	if from.IsZero() {
+		return from, to, fmt.Errorf("backfill needs from")
+	}
+	return from, to, nil
+}
+
+// backfill stores the trades of @pairs which are missing in the trade store in the range
+// described by @backfillRange.
+func backfill(es scrapers.APIScraper, pairs []dia.Pair, backfillRange string) error {
+	historicalScraper, ok := es.(scrapers.HistoricalScraper)
+	if !ok {
+		return fmt.Errorf("%s has no historical trades to backfill", *exchange)
+	}
+	from, to, err := parseBackfillRange(backfillRange)
+	if err != nil {
+		return err
+	}
+	ds, err := models.NewDataStore()
+	if err != nil {
+		return err
+	}
+	log.Infof("backfilling %d pairs on %s from %v to %v", len(pairs), *exchange, from, to)
+	_, err = scrapers.Backfill(historicalScraper, ds, *exchange, pairs, from, to)
+	return err
+}
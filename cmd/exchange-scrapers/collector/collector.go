@@ -2,13 +2,21 @@ package main
 
 import (
 	"flag"
+	"strings"
 	"sync"
 	"time"
 
 	scrapers "github.com/diadata-org/diadata/internal/pkg/exchange-scrapers"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/dedupHelper"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/leaderHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/pairMappingHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/proxyHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/recordHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/scraperRegistry"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/segmentio/kafka-go"
 	"github.com/sirupsen/logrus"
@@ -20,7 +28,7 @@ func init() {
 	log = logrus.New()
 }
 
-func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, exchange string) {
+func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, dedup *dedupHelper.Deduplicator, mappings *pairMappingHelper.Resolver, elector *leaderHelper.Elector, exchange string) {
 	lastTradeTime := time.Now()
 	watchdogDelay := scrapers.Exchanges[exchange].WatchdogDelay
 	t := time.NewTicker(time.Duration(watchdogDelay) * time.Second)
@@ -39,16 +47,39 @@ func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, exchan
 				return
 			}
 			lastTradeTime = time.Now()
-			if t.Time.Before(lastTradeTime) && t.Price >= 0 {
+			scraperRegistry.Trade(exchange, t.Time)
+			if mappings.Resolve(t) != models.MappingVerified && *verifiedPairsOnly {
+				continue
+			}
+			// Standbys don't publish, and don't mark trades as seen, which would drop them at the leader.
+			if !elector.IsLeader() {
+				continue
+			}
+			if t.Time.Before(lastTradeTime) && t.Price >= 0 && !dedup.Duplicate(t) {
 				kafkaHelper.WriteMessage(w, t)
 			}
 		}
 	}
 }
 
+// handlePoolVolumes stores the volumes of the pools of a DEX as they are reported.
+func handlePoolVolumes(c chan *dia.PoolVolume, ds models.Datastore) {
+	for volume := range c {
+		err := ds.SetPoolVolume(*volume)
+		if err != nil {
+			log.Error("SetPoolVolume: ", err)
+		}
+	}
+}
+
 var (
-	exchange         = flag.String("exchange", "", "which exchange")
-	onePairPerSymbol = flag.Bool("onePairPerSymbol", false, "one Pair max Per Symbol ?")
+	exchange          = flag.String("exchange", "", "which exchange")
+	onePairPerSymbol  = flag.Bool("onePairPerSymbol", false, "one Pair max Per Symbol ?")
+	backfillRange     = flag.String("backfill", "", "Store the missing trades of all pairs in a time range and exit, e.g. -backfill from=2024-01-01T00:00:00Z to=2024-01-02T00:00:00Z. Times are RFC3339 or unix seconds, to defaults to now")
+	metricsAddr       = flag.String("metricsAddr", ":9090", "Address serving /metrics, /metrics/dashboard and the scraper status under /scrapers, empty to disable")
+	recordDir         = flag.String("record", "", "Record the websocket messages and REST responses of the scraper to files in this directory, for replay in tests")
+	verifiedPairsOnly = flag.Bool("verifiedPairsOnly", false, "Drop the trades of pairs which aren't mapped to verified assets")
+	leaderElection    = flag.Bool("leaderElection", false, "Run as one of several replicas of the scraper, of which only the leader elected through a lease in redis publishes trades")
 )
 
 func init() {
@@ -66,6 +97,22 @@ func init() {
 // main manages all PairScrapers and handles incoming trade information
 func main() {
 
+	// Requests of scrapers which use plain http and websocket clients go through the proxies
+	// configured for the exchange, too.
+	proxyHelper.Install(*exchange)
+	if *recordDir != "" {
+		if err := recordHelper.Start(*recordDir); err != nil {
+			log.Fatal("record: ", err)
+		}
+		log.Info("recording payloads to ", *recordDir)
+	}
+
+	// The scraper is stale after the delay without trades at which handleTrades restarts it.
+	scraperRegistry.Register(*exchange, time.Duration(scrapers.Exchanges[*exchange].WatchdogDelay)*time.Second)
+	go scraperRegistry.Default.Run(10 * time.Second)
+	metrics.Handle("/scrapers", scraperRegistry.Default.Handler())
+	metrics.Serve(*metricsAddr, "collector")
+
 	ds, err := models.NewRedisDataStore()
 	if err != nil {
 		log.Errorln("NewDataStore:", err)
@@ -86,6 +133,19 @@ func main() {
 	}
 	es := scrapers.NewAPIScraper(*exchange, configApi.ApiKey, configApi.SecretKey)
 
+	if *backfillRange != "" {
+		var pairs []dia.Pair
+		for _, configPair := range pairsExchange {
+			pairs = append(pairs, dia.Pair{Symbol: configPair.Symbol, ForeignName: configPair.ForeignName})
+		}
+		// The range may be given unquoted, then its second part is an argument.
+		err := backfill(es, pairs, strings.Join(append([]string{*backfillRange}, flag.Args()...), " "))
+		if err != nil {
+			log.Fatal("backfill: ", err)
+		}
+		return
+	}
+
 	w := kafkaHelper.NewWriter(kafkaHelper.TopicTrades)
 	defer w.Close()
 
@@ -109,10 +169,37 @@ func main() {
 			if err != nil {
 				log.Println(err)
 			} else {
+				scraperRegistry.SubscribePair(*exchange, configPair.ForeignName)
 				wg.Add(1)
 			}
 		}
 		defer wg.Wait()
 	}
-	go handleTrades(es.Channel(), &wg, w, *exchange)
+	if poolVolumeScraper, ok := es.(scrapers.PoolVolumeScraper); ok {
+		go handlePoolVolumes(poolVolumeScraper.PoolVolumeChannel(), ds)
+	}
+	// Trades resent after a restart of the scraper or a reconnect are written only once.
+	var seenStore dedupHelper.SeenStore
+	if ds != nil {
+		seenStore = ds
+	}
+	// Trades carry the assets their pair is mapped to by the pair discovery or a curator.
+	var mappingStore pairMappingHelper.MappingStore
+	relDB, err := models.NewPostgresDataStore()
+	if err != nil {
+		log.Error("NewPostgresDataStore, pairs are not mapped to assets: ", err)
+	} else {
+		mappingStore = relDB
+	}
+	mappings := pairMappingHelper.NewResolver(*exchange, mappingStore, pairMappingHelper.DefaultRefresh)
+	// Replicas all scrape, so that a standby takes over without reconnecting when the leader dies.
+	var elector *leaderHelper.Elector
+	if *leaderElection {
+		if ds == nil {
+			log.Fatal("leader election needs redis")
+		}
+		elector = leaderHelper.NewElector("collector_"+*exchange, ds, leaderHelper.DefaultTTL)
+		go elector.Run()
+	}
+	go handleTrades(es.Channel(), &wg, w, dedupHelper.NewDeduplicator("kafka", seenStore, dedupHelper.DefaultTTL), mappings, elector, *exchange)
 }
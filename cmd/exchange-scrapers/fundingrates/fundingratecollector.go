@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	fundingrates "github.com/diadata-org/diadata/internal/pkg/fundingrate-scrapers"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+var (
+	exchange = flag.String("exchange", "", "which exchange")
+	interval = flag.Duration("interval", 5*time.Minute, "time between two snapshots of the funding rates")
+)
+
+func init() {
+	flag.Parse()
+	if *exchange == "" {
+		flag.Usage()
+		log.Fatal("exchange is required")
+	}
+}
+
+// main periodically stores the funding rates of all perpetual futures of an exchange.
+func main() {
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("NewDataStore: ", err)
+	}
+	scraper := fundingrates.New(*exchange)
+	if scraper == nil {
+		log.Fatalf("no funding rate scraper for exchange %s", *exchange)
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		rates, err := scraper.FetchFundingRates()
+		if err != nil {
+			log.Errorf("funding rates of %s: %v", *exchange, err)
+			continue
+		}
+		log.Infof("got %d funding rates of %s", len(rates), *exchange)
+		err = ds.SaveFundingRatesInflux(rates)
+		if err != nil {
+			log.Errorf("save funding rates of %s: %v", *exchange, err)
+		}
+	}
+}
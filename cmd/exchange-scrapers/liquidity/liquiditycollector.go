@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	liquidityscrapers "github.com/diadata-org/diadata/internal/pkg/liquidity-scrapers"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+var (
+	exchange = flag.String("exchange", "", "which DEX")
+	interval = flag.Duration("interval", 10*time.Minute, "time between two snapshots of the pools")
+	minTVL   = flag.Float64("minTVL", 10000, "least TVL in USD of a pool to be stored")
+)
+
+func init() {
+	flag.Parse()
+	if *exchange == "" {
+		flag.Usage()
+		log.Fatal("exchange is required")
+	}
+}
+
+// main periodically stores the reserves and TVL of the liquidity pools of a DEX.
+func main() {
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("NewDataStore: ", err)
+	}
+	scraper, err := liquidityscrapers.New(*exchange, ds.GetPriceUSD, *minTVL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		liquidities, err := scraper.FetchPoolLiquidity()
+		if err != nil {
+			log.Errorf("pool liquidity of %s: %v", *exchange, err)
+			continue
+		}
+		log.Infof("got liquidity of %d pools of %s", len(liquidities), *exchange)
+		err = ds.SavePoolLiquidityInflux(liquidities)
+		if err != nil {
+			log.Errorf("save pool liquidity of %s: %v", *exchange, err)
+		}
+	}
+}
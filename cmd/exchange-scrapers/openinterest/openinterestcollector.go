@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	openinterest "github.com/diadata-org/diadata/internal/pkg/openinterest-scrapers"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+var (
+	exchange = flag.String("exchange", "", "which exchange")
+	interval = flag.Duration("interval", 5*time.Minute, "time between two snapshots of the open interest")
+)
+
+func init() {
+	flag.Parse()
+	if *exchange == "" {
+		flag.Usage()
+		log.Fatal("exchange is required")
+	}
+}
+
+// main periodically stores the open interest of all futures instruments of an exchange.
+func main() {
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("NewDataStore: ", err)
+	}
+	scraper := openinterest.New(*exchange)
+	if scraper == nil {
+		log.Fatalf("no open interest scraper for exchange %s", *exchange)
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		openInterests, err := scraper.FetchOpenInterest()
+		if err != nil {
+			log.Errorf("open interest of %s: %v", *exchange, err)
+			continue
+		}
+		log.Infof("got open interest of %d instruments of %s", len(openInterests), *exchange)
+		err = ds.SaveOpenInterestInflux(openInterests)
+		if err != nil {
+			log.Errorf("save open interest of %s: %v", *exchange, err)
+		}
+	}
+}
@@ -19,13 +19,25 @@ func init() {
 
 const (
 	watchdogDelay = 60 * 60
+	flushInterval = 10 * time.Second
 )
 
-func handleorderBook(datastore *models.DB, c chan *dia.OptionOrderbookDatum, wg *sync.WaitGroup, exchange string) {
+// handleorderBook stores the order book data and, if the scraper is an OptionMarketScraper, the
+// trades and market data of options. @trades and @marketData are nil otherwise.
+func handleorderBook(datastore *models.DB, c chan *dia.OptionOrderbookDatum, trades chan *dia.OptionTrade, marketData chan *dia.OptionMarketDatum, wg *sync.WaitGroup, exchange string) {
 	lastTradeTime := time.Now()
 	t := time.NewTicker(time.Duration(watchdogDelay) * time.Second)
+	flushTicker := time.NewTicker(flushInterval)
 	for {
 		select {
+		case trade := <-trades:
+			lastTradeTime = time.Now()
+			datastore.SaveOptionTradeInflux(*trade)
+		case datum := <-marketData:
+			lastTradeTime = time.Now()
+			datastore.SaveOptionMarketDatumInflux(*datum)
+		case <-flushTicker.C:
+			datastore.Flush()
 		case <-t.C:
 			duration := time.Since(lastTradeTime)
 			if duration > time.Duration(watchdogDelay)*time.Second {
@@ -81,6 +93,12 @@ func main() {
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 
-	go handleorderBook(ds, es.Channel(), &wg, *exchange)
+	var trades chan *dia.OptionTrade
+	var marketData chan *dia.OptionMarketDatum
+	if marketScraper, ok := es.(options.OptionMarketScraper); ok {
+		trades = marketScraper.TradeChannel()
+		marketData = marketScraper.MarketChannel()
+	}
+	go handleorderBook(ds, es.Channel(), trades, marketData, &wg, *exchange)
 	wg.Wait()
 }
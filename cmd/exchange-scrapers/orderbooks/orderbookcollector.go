@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	orderbooks "github.com/diadata-org/diadata/internal/pkg/orderbook-scrapers"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+var (
+	exchange = flag.String("exchange", "", "which exchange")
+	interval = flag.Duration("interval", 5*time.Minute, "time between two snapshots of the order book of a pair")
+)
+
+func init() {
+	flag.Parse()
+	if *exchange == "" {
+		flag.Usage()
+		log.Fatal("exchange is required")
+	}
+}
+
+// main periodically stores the order book depth of all pairs of an exchange.
+func main() {
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("NewDataStore: ", err)
+	}
+	scraper := orderbooks.New(*exchange)
+	if scraper == nil {
+		log.Fatalf("no order book scraper for exchange %s", *exchange)
+	}
+
+	// Only the pairs of the config are collected, as all pairs of an exchange would exceed the
+	// rate limits of its API.
+	var pairs []dia.Pair
+	for _, pair := range configCollectors.NewConfigCollectors(*exchange, ".json").AllPairs() {
+		if !pair.Ignore {
+			pairs = append(pairs, pair)
+		}
+	}
+	if len(pairs) == 0 {
+		log.Fatalf("no pairs configured for exchange %s", *exchange)
+	}
+	log.Infof("collecting order books of %d pairs on %s", len(pairs), *exchange)
+
+	// The requests are spread evenly over the interval.
+	ticker := time.NewTicker(*interval / time.Duration(len(pairs)))
+	defer ticker.Stop()
+	for {
+		for _, pair := range pairs {
+			<-ticker.C
+			depth, err := scraper.FetchDepth(pair)
+			if err != nil {
+				log.Errorf("order book of %s: %v", pair.ForeignName, err)
+				continue
+			}
+			err = ds.SaveOrderBookDepthInflux(*depth)
+			if err != nil {
+				log.Errorf("save order book depth of %s: %v", pair.ForeignName, err)
+			}
+		}
+	}
+}
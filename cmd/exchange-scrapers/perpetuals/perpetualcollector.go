@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	perpetuals "github.com/diadata-org/diadata/internal/pkg/perpetual-scrapers"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+const (
+	watchdogDelay = 10 * time.Minute
+	flushInterval = time.Second
+)
+
+var (
+	exchange = flag.String("exchange", "", "which exchange")
+)
+
+func init() {
+	flag.Parse()
+	if *exchange == "" {
+		flag.Usage()
+		log.Fatal("exchange is required")
+	}
+}
+
+// main stores the trades and the mark and index prices of all perpetual futures of an exchange.
+func main() {
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("NewDataStore: ", err)
+	}
+	scraper := perpetuals.New(*exchange)
+	if scraper == nil {
+		log.Fatalf("no perpetual futures scraper for exchange %s", *exchange)
+	}
+
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+	watchdogTicker := time.NewTicker(watchdogDelay)
+	defer watchdogTicker.Stop()
+	lastTime := time.Now()
+	for {
+		select {
+		case trade, ok := <-scraper.Trades():
+			if !ok {
+				log.Fatal("trades channel closed")
+			}
+			lastTime = time.Now()
+			err = ds.SavePerpetualTradeInflux(*trade)
+			if err != nil {
+				log.Errorf("save perpetual trade of %s: %v", trade.Instrument, err)
+			}
+		case price, ok := <-scraper.Prices():
+			if !ok {
+				log.Fatal("prices channel closed")
+			}
+			lastTime = time.Now()
+			err = ds.SavePerpetualPriceInflux(*price)
+			if err != nil {
+				log.Errorf("save perpetual price of %s: %v", price.Instrument, err)
+			}
+		case <-flushTicker.C:
+			err = ds.Flush()
+			if err != nil {
+				log.Error("flush: ", err)
+			}
+		case <-watchdogTicker.C:
+			if duration := time.Since(lastTime); duration > watchdogDelay {
+				log.Error(duration)
+				panic("frozen? ")
+			}
+		}
+	}
+}
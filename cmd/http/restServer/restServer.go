@@ -1,7 +1,11 @@
 package main
 
 import (
+	"compress/gzip"
+	"flag"
+	"net"
 	"os"
+	"strings"
 	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
@@ -9,9 +13,14 @@ import (
 	_ "github.com/diadata-org/diadata/api/docs"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/http/grpcApi"
 	"github.com/diadata-org/diadata/pkg/http/restServer/diaApi"
+	"github.com/diadata-org/diadata/pkg/http/restServer/graphqlApi"
 	"github.com/diadata-org/diadata/pkg/http/restServer/kafkaApi"
+	"github.com/diadata-org/diadata/pkg/http/restServer/streamApi"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/selftest"
 	"github.com/gin-contrib/cache"
 	"github.com/gin-contrib/cache/persistence"
 	"github.com/gin-gonic/contrib/static"
@@ -71,9 +80,18 @@ const (
 	cachingTimeShort  = time.Minute * 2
 	cachingTimeMedium = time.Minute * 10
 	cachingTimeLong   = time.Minute * 100
+	// quotationMaxAge is the time clients may reuse a quotation without revalidating it.
+	quotationMaxAge = time.Second * 30
 )
 
-var identityKey = "id"
+// Decimals of the price strings added next to prices, see diaApi.PriceStrings.
+const (
+	pricePrecision = 8
+	// Trades of high-decimal tokens need more decimals than aggregated prices.
+	tradePricePrecision = 12
+)
+
+var identityKey = diaApi.IdentityKey
 
 func helloHandler(c *gin.Context) {
 	claims := jwt.ExtractClaims(c)
@@ -83,14 +101,69 @@ func helloHandler(c *gin.Context) {
 	})
 }
 
+var selftestMode = flag.Bool("selftest", false, "Verify all dependencies, print a report and exit")
+var streamEnabled = flag.Bool("stream", true, "Stream trades and quotations to websocket clients at /v1/stream and server-sent events clients at /v1/stream/sse")
+var grpcAddr = flag.String("grpc", ":9091", "Address of the gRPC API, disabled if empty")
+var healthExchanges = flag.String("healthExchanges", "Binance,CoinBase,Kraken", "Comma separated exchanges whose trades must be fresh for /health to succeed")
+var healthFilters = flag.String("healthFilters", "MAIR120", "Comma separated filters whose values must be fresh for /health to succeed")
+var healthMaxExchangeLag = flag.Duration("healthMaxExchangeLag", 15*time.Minute, "Age of the last trade of an exchange from which it is stale")
+var healthMaxFilterLag = flag.Duration("healthMaxFilterLag", 10*time.Minute, "Age of the last value of a filter from which it is stale")
+
+// selftestChecks verifies the databases and the kafka topics served by the API.
+func selftestChecks(store *models.DB, relStore *models.RelDB, relErr error) []selftest.Check {
+	return []selftest.Check{
+		{Name: "redis", Run: store.PingRedis},
+		{Name: "influx", Run: store.PingInflux},
+		{Name: "postgres", Run: func() error {
+			if relStore == nil {
+				return relErr
+			}
+			return relStore.PingPostgres()
+		}},
+		{Name: "kafka trades", Run: func() error { return kafkaHelper.Ping(kafkaHelper.TopicTrades) }},
+		{Name: "kafka trades block", Run: func() error { return kafkaHelper.Ping(kafkaHelper.TopicTradesBlock) }},
+	}
+}
+
+// splitFlag returns the non-empty values of the comma separated flag @value.
+func splitFlag(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func main() {
+	flag.Parse()
 
 	r := gin.New()
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
+	r.Use(diaApi.Metrics())
+	r.Use(diaApi.Compression(gzip.DefaultCompression))
 
 	config := dia.GetConfigApi()
 
+	store, err := models.NewDataStore()
+	if err != nil {
+		log.Errorln("NewDataStore", err)
+	}
+	relStore, err := models.NewRelDataStore()
+	if err != nil {
+		log.Errorln("NewRelDataStore", err)
+	}
+	if *selftestMode {
+		selftest.Exit("restServer", selftestChecks(store, relStore, err))
+	}
+	diaApiEnv := &diaApi.Env{
+		DataStore:      store,
+		RelDB:          *relStore,
+		BootstrapAdmin: config.ApiKey,
+	}
+
 	// the jwt middleware
 	authMiddleware, err := jwt.New(&jwt.GinJWTMiddleware{
 		Realm:       "party zone",
@@ -119,11 +192,15 @@ func main() {
 			userID := loginVals.Username
 			password := loginVals.Password
 
-			if userID == config.ApiKey && password == config.SecretKey { // Temporary: only 1 valid key so far.
+			// The configured api key is the bootstrap admin. All other users are stored in postgres.
+			if userID == config.ApiKey && password == config.SecretKey {
+				return &User{
+					UserName: userID,
+				}, nil
+			}
+			if adminUser, err := relStore.VerifyAdminUser(userID, password); err == nil {
 				return &User{
-					UserName:  userID,
-					LastName:  "Bo-Yi",
-					FirstName: "Wu",
+					UserName: adminUser.Username,
 				}, nil
 			}
 
@@ -131,8 +208,11 @@ func main() {
 			return nil, jwt.ErrFailedAuthentication
 		},
 		Authorizator: func(data interface{}, c *gin.Context) bool {
-			if v, ok := data.(*User); ok && v.UserName == config.ApiKey {
-				return true
+			// Fine-grained permissions are checked per route by diaApiEnv.RequireRole.
+			if v, ok := data.(*User); ok {
+				if _, err := diaApiEnv.AdminUserForIdentity(v.UserName); err == nil {
+					return true
+				}
 			}
 			log.Warning("Authorizator rejected")
 			return false
@@ -181,34 +261,108 @@ func main() {
 	}
 
 	memoryStore := persistence.NewInMemoryStore(time.Second)
+	// Quotations are polled at high frequency, so their responses are shared by all api instances.
+	redisStore := persistence.NewRedisCache(models.RedisAddress(), "", time.Second)
 
-	store, err := models.NewDataStore()
+	// GraphQL schema over the same data as the REST endpoints
+	graphqlHandler, err := graphqlApi.NewHandler(store, relStore)
 	if err != nil {
-		log.Errorln("NewDataStore", err)
+		log.Fatal("graphql schema: ", err)
 	}
-	relStore, err := models.NewRelDataStore()
-	if err != nil {
-		log.Errorln("NewRelDataStore", err)
+	r.POST("/v1/graphql", gin.WrapH(graphqlHandler))
+
+	// Websocket and server-sent events streams of the trades and filters read from kafka
+	var streamHub *streamApi.Hub
+	if *streamEnabled {
+		streamHub = streamApi.NewHub()
+		go streamHub.StreamTrades(kafkaHelper.NewReaderNextMessage(kafkaHelper.TopicTrades))
+		go streamHub.StreamQuotations(kafkaHelper.NewReaderNextMessage(kafkaHelper.TopicFiltersBlock))
+		r.GET("/v1/stream", streamHub.ServeWS)
+		r.GET("/v1/stream/sse", streamHub.ServeSSE)
 	}
-	diaApiEnv := &diaApi.Env{
-		DataStore: store,
-		RelDB:     *relStore,
+
+	// gRPC API sharing the datastore and the stream hub with the REST API
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatal("grpc listen: ", err)
+		}
+		go func() {
+			log.Fatal("grpc: ", grpcApi.NewServer(store, streamHub).Serve(lis))
+		}()
 	}
 
 	diaAuth := r.Group("/v1")
 	diaAuth.Use(authMiddleware.MiddlewareFunc())
+	diaAuth.Use(diaApiEnv.AuditMutations())
+	{
+		diaAuth.POST("/supply", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.PostSupply)
+		diaAuth.POST("/indexRebalance/:symbol", diaApiEnv.RequireRole(models.RoleOperator), diaApiEnv.PostIndexRebalance)
+		diaAuth.POST("/basket", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.PostBasket)
+		diaAuth.GET("/webhooks", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.GetWebhooks)
+		diaAuth.POST("/webhooks", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.PostWebhook)
+		diaAuth.DELETE("/webhooks/:id", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.DeleteWebhook)
+		diaAuth.GET("/webhooks/:id/deadLetters", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.GetWebhookDeadLetters)
+	}
+
+	// Management of admin users and their roles
+	diaAdmin := r.Group("/v1/admin")
+	diaAdmin.Use(authMiddleware.MiddlewareFunc())
+	diaAdmin.Use(diaApiEnv.AuditMutations())
 	{
-		diaAuth.POST("/supply", diaApiEnv.PostSupply)
-		diaAuth.POST("/indexRebalance/:symbol", diaApiEnv.PostIndexRebalance)
+		diaAdmin.GET("/users", diaApiEnv.RequireRole(models.RoleAdmin), diaApiEnv.GetAdminUsers)
+		diaAdmin.POST("/users", diaApiEnv.RequireRole(models.RoleAdmin), diaApiEnv.PostAdminUser)
+		diaAdmin.DELETE("/users/:username", diaApiEnv.RequireRole(models.RoleAdmin), diaApiEnv.DeleteAdminUser)
+		diaAdmin.GET("/audit", diaApiEnv.RequireRole(models.RoleOperator), diaApiEnv.GetAdminAudit)
+
+		// Pin or forbid pools used for the pricing of an asset
+		diaAdmin.GET("/routeRules/:symbol", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.GetRouteRules)
+		diaAdmin.POST("/routeRules/:symbol", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.PostRouteRule)
+		diaAdmin.DELETE("/routeRules/:symbol", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.DeleteRouteRule)
+
+		// Mapping of the symbols and pairs of exchanges to assets
+		diaAdmin.GET("/mappings/:exchange/symbols", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.GetSymbolMappings)
+		diaAdmin.POST("/mappings/:exchange/symbols", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.PostSymbolMapping)
+		diaAdmin.DELETE("/mappings/:exchange/symbols/:symbol", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.DeleteSymbolMapping)
+		diaAdmin.GET("/mappings/:exchange/pairs", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.GetPairMappings)
+
+		// Bridged and wrapped representations of canonical assets
+		diaAdmin.POST("/equivalences/:symbol", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.PostAssetEquivalence)
+		diaAdmin.DELETE("/equivalences/:symbol", diaApiEnv.RequireRole(models.RoleCurator), diaApiEnv.DeleteAssetEquivalence)
+
+		// Audit trail of the updates of oracle contracts
+		diaAdmin.GET("/oracleUpdates", diaApiEnv.RequireRole(models.RoleViewer), diaApiEnv.GetOracleUpdates)
 	}
 
 	dia := r.Group("/v1")
 	{
 		// Endpoints for cryptocurrencies/exchanges
-		dia.GET("/quotation/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetQuotation))
-		dia.GET("/lastTrades/:symbol", diaApiEnv.GetLastTrades)
-		dia.GET("/lastPriceBefore/:filter/:exchange/:symbol/:timestamp", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLastPriceBefore))
-		dia.GET("/lastPriceBeforeAllExchanges/:filter/:symbol/:timestamp", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLastPriceBeforeAllExchanges))
+		dia.GET("/quotation/:symbol", diaApi.ConditionalGet(quotationMaxAge), diaApi.PriceStrings(pricePrecision), cache.CachePage(redisStore, cachingTimeShort, diaApiEnv.GetQuotation))
+		dia.GET("/quotations", diaApi.ConditionalGet(quotationMaxAge), diaApi.PriceStrings(pricePrecision), cache.CachePage(redisStore, cachingTimeShort, diaApiEnv.GetQuotations))
+		dia.GET("/quotationRange/:symbol", diaApi.ConditionalGet(quotationMaxAge), diaApi.PriceStrings(pricePrecision), cache.CachePage(redisStore, cachingTimeShort, diaApiEnv.GetQuotationRange))
+		dia.GET("/candles/:symbol", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetCandles))
+		dia.GET("/vwap/:symbol", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetVWAP))
+		dia.GET("/volatility/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetVolatility))
+		dia.GET("/correlation", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetCorrelations))
+		dia.GET("/orderbookDepth/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetOrderBookDepth))
+		dia.GET("/fundingrate/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetFundingRates))
+		dia.GET("/openinterest/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetOpenInterest))
+		dia.GET("/poolLiquidity", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetPoolLiquidity))
+		dia.GET("/poolLiquidity/:exchange/:pool", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetPoolLiquidityHistory))
+		dia.GET("/stakingYield/:asset", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetStakingYield))
+		dia.GET("/liquidStakingRate/:token", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLiquidStakingRate))
+		dia.GET("/gasprice/:chain", diaApiEnv.GetGasPrice)
+		dia.GET("/stablecoins/peg", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetStablecoinPegs))
+		dia.GET("/basket/:id", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetBasket))
+		dia.GET("/basket/:id/history", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetBasketHistory))
+		// Exports are streamed, so they are neither cached nor rewritten by PriceStrings.
+		dia.GET("/export/trades/:symbol", diaApiEnv.ExportTrades)
+		dia.GET("/export/quotations/:symbol", diaApiEnv.ExportQuotations)
+		dia.GET("/lastTrades/:symbol", diaApi.PriceStrings(tradePricePrecision), diaApiEnv.GetLastTrades)
+		dia.GET("/exchangePrice/:exchange/:symbol", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetExchangePrice))
+		dia.GET("/crossrate/:base/:quote", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetCrossRate))
+		dia.GET("/lastPriceBefore/:filter/:exchange/:symbol/:timestamp", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLastPriceBefore))
+		dia.GET("/lastPriceBeforeAllExchanges/:filter/:symbol/:timestamp", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLastPriceBeforeAllExchanges))
 		dia.GET("/supply/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetSupply))
 		dia.GET("/supplies/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetSupplies))
 		dia.GET("/symbol/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetSymbolDetails))
@@ -217,7 +371,13 @@ func main() {
 		dia.GET("/volume24/:exchange", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.Get24hVolume))
 		dia.GET("/coins", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetCoins))
 		dia.GET("/pairs", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetPairs))
-		dia.GET("/exchanges", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetExchanges))
+		dia.GET("/assets", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetAssets))
+		dia.GET("/search", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.SearchAssets))
+		dia.GET("/equivalences/:symbol", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetAssetEquivalences))
+		dia.GET("/exchanges", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetExchanges))
+		dia.GET("/pairMeta/:exchange", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetPairMeta))
+		dia.GET("/pairMeta/:exchange/:pair", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetPairMeta))
+		dia.GET("/pricingRoute/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetPricingRoute))
 		dia.GET("/defiLendingProtocols", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetLendingProtocols))
 		dia.GET("/chartPoints/:filter/:exchange/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetChartPoints))
 		dia.GET("/chartPointsAllExchanges/:filter/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetChartPointsAllExchanges))
@@ -226,6 +386,9 @@ func main() {
 		dia.GET("/defiLendingRate/:protocol/:asset/:time", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetDefiRate))
 		dia.GET("/defiLendingState/:protocol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetDefiState))
 		dia.GET("/defiLendingState/:protocol/:time", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetDefiState))
+		dia.GET("/lendingRates/:protocol/:blockchain", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLendingRates))
+		dia.GET("/lendingRates/:protocol/:blockchain/:asset", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLendingRates))
+		dia.GET("/lendingCollateral/:protocol/:blockchain", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetLendingCollateral))
 
 		dia.GET("/FarmingPools", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetFarmingPools))
 		dia.GET("/FarmingPoolData/:protocol/:poolID", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetFarmingPoolData))
@@ -245,21 +408,21 @@ func main() {
 		dia.GET("/compoundedAvgDIA/:symbol/:days/:dpy/:time", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetCompoundedAvgDIA))
 
 		// Endpoints for fiat currencies
-		dia.GET("/fiatQuotations", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetFiatQuotations))
+		dia.GET("/fiatQuotations", diaApi.ConditionalGet(quotationMaxAge), cache.CachePage(redisStore, cachingTimeShort, diaApiEnv.GetFiatQuotations))
 
 		// Endpoints for stocks
 		dia.GET("/stockSymbols", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetStockSymbols))
-		dia.GET("/stockQuotation/:source/:symbol", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
-		dia.GET("/stockQuotation/:source/:symbol/:time", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
+		dia.GET("/stockQuotation/:source/:symbol", diaApi.ConditionalGet(quotationMaxAge), diaApi.PriceStrings(pricePrecision), cache.CachePage(redisStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
+		dia.GET("/stockQuotation/:source/:symbol/:time", diaApi.ConditionalGet(quotationMaxAge), diaApi.PriceStrings(pricePrecision), cache.CachePage(redisStore, cachingTimeShort, diaApiEnv.GetStockQuotation))
 
 		// Endpoints for foreign sources
-		dia.GET("/foreignQuotation/:source/:symbol", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetForeignQuotation))
-		dia.GET("/foreignQuotation/:source/:symbol/:time", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetForeignQuotation))
+		dia.GET("/foreignQuotation/:source/:symbol", diaApi.ConditionalGet(quotationMaxAge), diaApi.PriceStrings(pricePrecision), cache.CachePage(redisStore, cachingTimeLong, diaApiEnv.GetForeignQuotation))
+		dia.GET("/foreignQuotation/:source/:symbol/:time", diaApi.ConditionalGet(quotationMaxAge), diaApi.PriceStrings(pricePrecision), cache.CachePage(redisStore, cachingTimeLong, diaApiEnv.GetForeignQuotation))
 		dia.GET("/foreignSymbols/:source", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetForeignSymbols))
 
 		// Gold asset
-		dia.GET("/goldPaxgOunces", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetPaxgQuotationOunces))
-		dia.GET("/goldPaxgGrams", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetPaxgQuotationGrams))
+		dia.GET("/goldPaxgOunces", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetPaxgQuotationOunces))
+		dia.GET("/goldPaxgGrams", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetPaxgQuotationGrams))
 
 		// External supply reports
 		dia.GET("/diaTotalSupply", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetDiaTotalSupply))
@@ -269,6 +432,14 @@ func main() {
 		dia.GET("/index/:symbol", diaApiEnv.GetCryptoIndex)
 		dia.GET("/cryptoIndexMintAmounts/:symbol", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetCryptoIndexMintAmounts))
 
+		// Endpoints for daily fixing rates
+		dia.GET("/fixingRate/:symbol", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetFixingRate))
+		dia.GET("/fixingRate/:symbol/:date", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetFixingRate))
+		dia.GET("/fixingRates/:symbol", diaApi.PriceStrings(pricePrecision), cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetFixingRates))
+
+		// Basis between spot and perpetual swaps
+		dia.GET("/basis/:asset", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetBasis))
+
 		// Endpoints for NFTs
 		dia.GET("/AllNFTClasses/:blockchain", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetAllNFTClasses))
 		dia.GET("/NFTClasses/:limit/:offset", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetNFTClasses))
@@ -276,11 +447,25 @@ func main() {
 		dia.GET("/NFT/:blockchain/:address/:id", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetNFT))
 		dia.GET("/NFTTrades/:blockchain/:address/:id", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetNFTTrades))
 		dia.GET("/NFTPrice30Days/:blockchain/:address", cache.CachePage(memoryStore, cachingTimeLong, diaApiEnv.GetNFTPrice30Days))
+		dia.GET("/nft/floor/:blockchain/:address", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetNFTFloor))
+		dia.GET("/nft/volume/:blockchain/:address", cache.CachePage(memoryStore, cachingTimeShort, diaApiEnv.GetNFTVolume))
 	}
 
 	r.Use(static.Serve("/v1/chart", static.LocalFile("/charts", true)))
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Prometheus metrics and the matching Grafana dashboard
+	r.GET("/metrics", gin.WrapH(metrics.DefaultRegistry.Handler()))
+	r.GET("/metrics/dashboard", gin.WrapH(metrics.DefaultRegistry.DashboardHandler("restServer")))
+
+	// Ingestion and computation lag of exchanges and filters, 503 if a critical one is stale
+	r.GET("/health", diaApiEnv.Health(diaApi.HealthConfig{
+		CriticalExchanges: splitFlag(*healthExchanges),
+		CriticalFilters:   splitFlag(*healthFilters),
+		MaxExchangeLag:    *healthMaxExchangeLag,
+		MaxFilterLag:      *healthMaxFilterLag,
+	}))
+
 	// This environment variable is either set in docker-compose or empty
 	executionMode := os.Getenv("EXEC_MODE")
 	if executionMode == "production" {
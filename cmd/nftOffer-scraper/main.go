@@ -34,6 +34,9 @@ func main() {
 	case "CryptoKitties":
 		log.Println("NFT Offers Scraper: Start scraping bids from CryptoKitties")
 		scraper = nftofferscrapers.NewCryptokittiesScraper(rdb)
+	case "Blur":
+		log.Println("NFT Offers Scraper: Start scraping listings from Blur")
+		scraper = nftofferscrapers.NewBlurScraper(rdb)
 	default:
 		for {
 			time.Sleep(24 * time.Hour)
@@ -57,6 +57,12 @@ func main() {
 	case "Opensea":
 		log.Println("NFT Data Scraper: Start scraping trades from Opensea")
 		scraper = nfttradescrapers.NewOpenSeaScraper(rdb)
+	case "Seaport":
+		log.Println("NFT Data Scraper: Start scraping trades from OpenSea Seaport")
+		scraper = nfttradescrapers.NewSeaportScraper(rdb)
+	case "Blur":
+		log.Println("NFT Data Scraper: Start scraping trades from Blur")
+		scraper = nfttradescrapers.NewBlurScraper(rdb)
 	default:
 		for {
 			time.Sleep(24 * time.Hour)
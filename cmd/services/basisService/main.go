@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/basisService"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+func main() {
+	assetsFlag := flag.String("assets", "BTC,ETH,LINK,DOT,SOL,ADA", "Comma separated list of assets to compute the basis for")
+	frequencySeconds := flag.Int("frequencySeconds", 60, "Number of seconds between two basis computations")
+	flag.Parse()
+	assets := strings.Split(*assetsFlag, ",")
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	sources := []basisService.PerpSource{
+		&basisService.BinancePerpSource{},
+		&basisService.OKExPerpSource{},
+	}
+
+	ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
+	for ; true; <-ticker.C {
+		for _, source := range sources {
+			err := basisService.UpdateBasis(ds, source, assets)
+			if err != nil {
+				log.Errorf("update basis on %s: %v", source.Venue(), err)
+			}
+		}
+	}
+}
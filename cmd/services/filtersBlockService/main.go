@@ -9,13 +9,16 @@ import (
 	filters "github.com/diadata-org/diadata/internal/pkg/filtersBlockService"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/segmentio/kafka-go"
 	log "github.com/sirupsen/logrus"
 )
 
 var (
-	replayInflux = flag.Bool("replayInflux", false, "replayInflux ?")
+	replayInflux   = flag.Bool("replayInflux", false, "replayInflux ?")
+	maxVolumeShare = flag.Float64("maxVolumeShare", 0.4, "Maximal weight of a single exchange pair in the price across exchanges, 1 to publish the moving average MAIR instead")
+	metricsAddr    = flag.String("metricsAddr", ":9090", "Address serving /metrics and /metrics/dashboard, empty to disable")
 )
 
 func init() {
@@ -114,13 +117,23 @@ func createTradeBlockFromInflux(d models.Datastore, f *filters.FiltersBlockServi
 }
 
 func main() {
+	metrics.Serve(*metricsAddr, "filtersBlockService")
+
+	// Trades of bridged and wrapped assets feed the price of their canonical asset, too.
+	var equivalences filters.EquivalenceStore
+	relDB, err := models.NewPostgresDataStore()
+	if err != nil {
+		log.Errorln("NewPostgresDataStore, assets have no canonical price:", err)
+	} else {
+		equivalences = relDB
+	}
 
 	if *replayInflux {
 		s, err := models.NewInfluxDataStore()
 		if err != nil {
 			log.Errorln("NewDataStore", err)
 		}
-		f := filters.NewFiltersBlockService(nil, s, nil)
+		f := filters.NewFiltersBlockService(nil, s, nil, *maxVolumeShare, equivalences)
 		createTradeBlockFromInflux(s, f)
 	} else {
 		s, err := models.NewDataStore()
@@ -129,7 +142,7 @@ func main() {
 		}
 		channel := make(chan *dia.FiltersBlock)
 
-		f := filters.NewFiltersBlockService(loadFilterPointsFromPreviousBlock(), s, channel)
+		f := filters.NewFiltersBlockService(loadFilterPointsFromPreviousBlock(), s, channel, *maxVolumeShare, equivalences)
 
 		w := kafkaHelper.NewSyncWriter(kafkaHelper.TopicFiltersBlock)
 
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/fixingRateService"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+func main() {
+	symbolsFlag := flag.String("symbols", "BTC,ETH,DIA,USDC,USDT,DAI", "Comma separated list of symbols to compute fixing rates for")
+	flag.Parse()
+	symbols := strings.Split(*symbolsFlag, ",")
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Fatal("relational datastore error: ", err)
+	}
+
+	// published holds the last fixing time per symbol that is known to be stored.
+	published := make(map[string]time.Time)
+	ticker := time.NewTicker(time.Minute)
+	for ; true; <-ticker.C {
+		fixingTime := fixingRateService.LastFixingTime(time.Now())
+		for _, symbol := range symbols {
+			if published[symbol].Equal(fixingTime) {
+				continue
+			}
+			if _, err := relDB.GetFixingRate(symbol, fixingTime); err == nil {
+				published[symbol] = fixingTime
+				continue
+			}
+			fixing, err := fixingRateService.ComputeFixingRate(ds, symbol, fixingTime)
+			if err != nil {
+				log.Errorf("compute fixing rate of %s at %v: %v", symbol, fixingTime, err)
+				continue
+			}
+			err = relDB.SetFixingRate(fixing)
+			if err != nil && err != models.ErrFixingRateExists {
+				log.Errorf("store fixing rate of %s at %v: %v", symbol, fixingTime, err)
+				continue
+			}
+			log.Infof("published fixing rate of %s at %v: %v", symbol, fixingTime, fixing.Price)
+			published[symbol] = fixingTime
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/gasPriceService"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+func main() {
+	chainsFlag := flag.String("chains", "Ethereum,Polygon,Arbitrum,Optimism,Base,Avalanche,BinanceSmartChain", "Comma separated list of EVM blockchains to sample gas prices on")
+	blocks := flag.Int("blocks", 5, "Number of recent blocks whose priority fees are sampled")
+	frequencySeconds := flag.Int("frequencySeconds", 30, "Number of seconds between two samples")
+	flag.Parse()
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	clients := make(map[string]*rpc.Client)
+	for _, blockchain := range strings.Split(*chainsFlag, ",") {
+		node := ethhelper.RPCNode(blockchain)
+		if node == "" {
+			log.Fatalf("no RPC node of %s, set %s_RPC_NODE", blockchain, strings.ToUpper(blockchain))
+		}
+		clients[blockchain], err = rpc.Dial(node)
+		if err != nil {
+			log.Fatalf("dial RPC node of %s: %v", blockchain, err)
+		}
+	}
+
+	ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
+	for ; true; <-ticker.C {
+		for blockchain, client := range clients {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			gasPrice, err := gasPriceService.SampleGasPrice(ctx, client, blockchain, *blocks)
+			cancel()
+			if err != nil {
+				log.Errorf("sample gas price of %s: %v", blockchain, err)
+				continue
+			}
+			err = ds.SaveGasPriceInflux(gasPrice)
+			if err != nil {
+				log.Errorf("save gas price of %s: %v", blockchain, err)
+			}
+		}
+	}
+}
@@ -19,6 +19,8 @@ import (
 var (
 	log *logrus.Logger
 	db  models.Datastore
+	// relDB holds the mappings of pairs to assets. It is nil if postgres isn't available.
+	relDB *models.RelDB
 )
 
 type Pairs struct {
@@ -99,6 +101,7 @@ func updateExchangePairs() {
 					err := db.SetAvailablePairsForExchange(exchange, pairs)
 					if err == nil {
 						log.Info("Exchange: ", exchange, " updated")
+						updateMappings(exchange, pairs)
 					} else {
 						log.Error("Error adding pairs to redis for exchange: ", exchange, " error: ", err.Error())
 					}
@@ -106,6 +109,9 @@ func updateExchangePairs() {
 					//	log.Info("locale ", err.Error())
 					log.Error("Error fetching pairs for exchange: ", exchange, " error: ", err.Error())
 				}
+				if metaScraper, ok := scraper.(scrapers.PairMetaScraper); ok {
+					updatePairMeta(exchange, metaScraper)
+				}
 				go func(s scrapers.APIScraper, exchange string) {
 					time.Sleep(5 * time.Second)
 					log.Error("Closing scraper: ", exchange)
@@ -119,6 +125,21 @@ func updateExchangePairs() {
 	}
 }
 
+// updatePairMeta stores tick sizes, minimal order sizes and fee tiers of all pairs of @exchange.
+func updatePairMeta(exchange string, scraper scrapers.PairMetaScraper) {
+	meta, err := scraper.FetchPairMeta()
+	if err != nil {
+		log.Error("Error fetching pair meta for exchange: ", exchange, " error: ", err.Error())
+		return
+	}
+	err = db.SetPairMetaForExchange(exchange, meta)
+	if err != nil {
+		log.Error("Error adding pair meta to redis for exchange: ", exchange, " error: ", err.Error())
+		return
+	}
+	log.Info("Exchange: ", exchange, " pair meta updated for ", len(meta), " pairs")
+}
+
 func addLocalPairs(exchange string, remotePairs []dia.Pair) []dia.Pair {
 	localPairs, _ := getPairsFromConfig(exchange)
 	log.Info(exchange, " num remote: ", len(remotePairs), ", num pLocales: ", len(localPairs))
@@ -156,6 +177,7 @@ func getInitialExchangePairs() {
 			err := db.SetAvailablePairsForExchange(e, pairsToSave)
 			if err == nil {
 				log.Info("Exchange: ", e, " set")
+				updateMappings(e, pairsToSave)
 			} else {
 				log.Error("Error setting pairs for exchange:", e, " error:", err.Error())
 			}
@@ -177,6 +199,11 @@ func main() {
 	if err != nil {
 		panic("Can not initialize db, error: " + err.Error())
 	}
+	relDB, err = models.NewPostgresDataStore()
+	if err != nil {
+		log.Error("Can not initialize postgres, pairs are not mapped to assets: ", err)
+		relDB = nil
+	}
 	updateExchangePairs()
 	c := make(chan os.Signal)
 	signal.Notify(c, os.Interrupt)
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/pairMappingHelper"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// updateMappings stores the pairs of @exchange in postgres and maps their symbols to assets.
+// Symbols matching exactly one asset are verified, symbols matching several assets are flagged
+// as ambiguous until a curator picks one. Curated symbols are left as they are.
+func updateMappings(exchange string, pairs []dia.Pair) {
+	if relDB == nil {
+		return
+	}
+	var exchangePairs []models.ExchangePairMapping
+	for _, pair := range pairs {
+		exchangePairs = append(exchangePairs, models.ExchangePairMapping{
+			Symbol:      pair.Symbol,
+			ForeignName: pair.ForeignName,
+			BaseSymbol:  pairMappingHelper.BaseSymbol(exchange, pair),
+		})
+	}
+	err := relDB.SetExchangePairs(exchange, exchangePairs)
+	if err != nil {
+		log.Error("Error storing pairs in postgres for exchange: ", exchange, " error: ", err.Error())
+		return
+	}
+
+	symbols, err := relDB.GetExchangeSymbolMappings(exchange, "")
+	if err != nil {
+		log.Error("Error getting symbol mappings for exchange: ", exchange, " error: ", err.Error())
+		return
+	}
+	statuses := make(map[string]int)
+	for _, symbol := range symbols {
+		if !symbol.Curated {
+			candidates, err := relDB.GetAssetsBySymbol(symbol.Symbol)
+			if err != nil {
+				log.Error("Error getting assets for symbol: ", symbol.Symbol, " error: ", err.Error())
+				continue
+			}
+			status, asset := pairMappingHelper.Classify(candidates)
+			if status != symbol.Status || asset != symbol.Asset {
+				symbol.Status, symbol.Asset = status, asset
+				err = relDB.SetExchangeSymbolMapping(symbol)
+				if err != nil {
+					log.Error("Error setting mapping of symbol: ", symbol.Symbol, " error: ", err.Error())
+					continue
+				}
+			}
+		}
+		statuses[symbol.Status]++
+	}
+	err = relDB.UpdateExchangePairMappings(exchange)
+	if err != nil {
+		log.Error("Error updating pair mappings for exchange: ", exchange, " error: ", err.Error())
+		return
+	}
+	log.Infof("Exchange: %s symbols mapped: %d verified, %d ambiguous, %d unverified", exchange,
+		statuses[models.MappingVerified], statuses[models.MappingAmbiguous], statuses[models.MappingUnverified])
+}
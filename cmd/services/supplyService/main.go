@@ -8,6 +8,7 @@ import (
 	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -22,10 +23,13 @@ func main() {
 	if err != nil {
 		log.Fatal("datastore error: ", err)
 	}
-	conn, err := ethclient.Dial("http://159.69.120.42:8545/")
+	rpcClient, err := rpc.Dial("http://159.69.120.42:8545/")
 	if err != nil {
 		log.Fatal(err)
 	}
+	// Balances of locked wallets are read from storage proofs in one request per token.
+	enumerator := supplyservice.NewHolderEnumerator(rpcClient)
+	conn := enumerator.Client()
 	// Fetch token contract addresses from json file
 	tokenAddresses, err := ethhelper.GetAddressesFromFile(tokensListFilename)
 	if err != nil {
@@ -38,7 +42,7 @@ func main() {
 		log.Error(err)
 	}
 	// Initial run
-	err = setSupplies(tokenAddresses, lockedWalletsMap, ds, conn)
+	err = setSupplies(tokenAddresses, lockedWalletsMap, ds, conn, enumerator)
 	if err != nil {
 		log.Error(err)
 	}
@@ -49,7 +53,7 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				err = setSupplies(tokenAddresses, lockedWalletsMap, ds, conn)
+				err = setSupplies(tokenAddresses, lockedWalletsMap, ds, conn, enumerator)
 				if err != nil {
 					log.Error(err)
 				}
@@ -60,10 +64,10 @@ func main() {
 
 }
 
-func setSupplies(tokenAddresses []string, lockedWalletsMap map[string][]string, ds models.Datastore, conn *ethclient.Client) error {
+func setSupplies(tokenAddresses []string, lockedWalletsMap map[string][]string, ds models.Datastore, conn *ethclient.Client, enumerator *supplyservice.HolderEnumerator) error {
 	for _, address := range tokenAddresses {
 
-		supp, err := supplyservice.GetTotalSupplyfromMainNet(address, lockedWalletsMap[address], conn)
+		supp, err := supplyservice.GetTotalSupplyfromMainNet(address, lockedWalletsMap[address], conn, enumerator)
 		if err != nil || len(supp.Symbol) < 2 || supp.Supply < 2 {
 			if strings.ToLower(address) == "0x9f8f72aa9304c8b593d555f12ef6589cc3a579a2" {
 				// Comment: maker contract emits byte32 instead of string
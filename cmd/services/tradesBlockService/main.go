@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"flag"
 	"github.com/diadata-org/diadata/internal/pkg/tradesBlockService"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	"github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/selftest"
 	"github.com/segmentio/kafka-go"
 	log "github.com/sirupsen/logrus"
 	"sync"
@@ -26,7 +29,22 @@ func handleBlocks(blockMaker *tradesBlockService.TradesBlockService, wg *sync.Wa
 	}
 }
 
+var metricsAddr = flag.String("metricsAddr", ":9090", "Address serving /metrics and /metrics/dashboard, empty to disable")
+var selftestMode = flag.Bool("selftest", false, "Verify all dependencies, print a report and exit")
+var maxLateness = flag.Duration("maxLateness", tradesBlockService.DefaultMaxLateness, "Time a block stays open for late trades, e.g. confirmed on-chain trades")
+
 func main() {
+	flag.Parse()
+	if *selftestMode {
+		s, _ := models.NewDataStore()
+		selftest.Exit("tradesBlockService", []selftest.Check{
+			{Name: "redis", Run: s.PingRedis},
+			{Name: "influx", Run: s.PingInflux},
+			{Name: "kafka trades", Run: func() error { return kafkaHelper.Ping(kafkaHelper.TopicTrades) }},
+			{Name: "kafka trades block", Run: func() error { return kafkaHelper.Ping(kafkaHelper.TopicTradesBlock) }},
+		})
+	}
+	metrics.Serve(*metricsAddr, "tradesBlockService")
 
 	w := kafkaHelper.NewSyncWriter(kafkaHelper.TopicTradesBlock)
 	defer w.Close()
@@ -39,7 +57,7 @@ func main() {
 		log.Errorln("NewDataStore", err)
 	}
 
-	tradesBlockService := tradesBlockService.NewTradesBlockService(s, dia.BlockSizeSeconds)
+	tradesBlockService := tradesBlockService.NewTradesBlockService(s, dia.BlockSizeSeconds, *maxLateness)
 
 	wg := sync.WaitGroup{}
 	go handleBlocks(tradesBlockService, &wg, w)
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/webhookService"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+func main() {
+	frequencySeconds := flag.Int("frequencySeconds", 10, "Number of seconds between two evaluations of the webhooks")
+	attempts := flag.Int("attempts", 5, "Number of callbacks per event before it is dead-lettered")
+	backoffSeconds := flag.Int("backoffSeconds", 2, "Number of seconds before the first retry, doubled for each further retry")
+	flag.Parse()
+
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("datastore error: ", err)
+	}
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Fatal("relational datastore error: ", err)
+	}
+
+	monitor := webhookService.NewMonitor()
+	dispatcher := webhookService.NewDispatcher(*attempts, time.Duration(*backoffSeconds)*time.Second)
+	// Callbacks are retried concurrently, but dead letters are stored here as the postgres
+	// connection isn't safe for concurrent use.
+	deadLetters := make(chan *models.WebhookDeadLetter, 100)
+
+	ticker := time.NewTicker(time.Duration(*frequencySeconds) * time.Second)
+	for {
+		select {
+		case deadLetter := <-deadLetters:
+			err = relDB.SetWebhookDeadLetter(*deadLetter)
+			if err != nil {
+				log.Errorf("store dead letter of webhook %s: %v", deadLetter.WebhookID, err)
+			}
+		case <-ticker.C:
+			webhooks, err := relDB.GetWebhooks("")
+			if err != nil {
+				log.Error("get webhooks: ", err)
+				continue
+			}
+			updated := make(map[string]bool)
+			for _, webhook := range webhooks {
+				if _, ok := updated[webhook.Symbol]; ok {
+					continue
+				}
+				q, err := ds.GetQuotation(webhook.Symbol)
+				if err != nil {
+					log.Errorf("get quotation of %s: %v", webhook.Symbol, err)
+					updated[webhook.Symbol] = false
+					continue
+				}
+				updated[webhook.Symbol] = monitor.Update(webhook.Symbol, q.Price, q.Time)
+			}
+			for _, webhook := range webhooks {
+				if !updated[webhook.Symbol] {
+					continue
+				}
+				event, ok := monitor.Evaluate(webhook)
+				if !ok {
+					continue
+				}
+				log.Infof("webhook %s triggered: %s %s %v at %v", webhook.ID, webhook.Symbol, webhook.Condition, webhook.Threshold, event.Price)
+				go func(webhook models.Webhook, event webhookService.Event) {
+					if deadLetter := dispatcher.Deliver(webhook, event); deadLetter != nil {
+						deadLetters <- deadLetter
+					}
+				}(webhook, event)
+			}
+		}
+	}
+}
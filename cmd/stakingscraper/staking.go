@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	stakingscrapers "github.com/diadata-org/diadata/internal/pkg/staking-scrapers"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log *logrus.Logger
+
+func init() {
+	log = logrus.New()
+}
+
+var (
+	assets   = flag.String("assets", "ETH,SOL,ATOM,DOT", "comma separated proof of stake assets")
+	tokens   = flag.String("tokens", "wstETH,rETH,cbETH,sfrxETH,swETH", "comma separated liquid staking tokens")
+	interval = flag.Duration("interval", time.Hour, "time between two updates of the staking yields")
+	// 300 blocks of Ethereum are about an hour.
+	blocks    = flag.Uint64("blocks", 300, "blocks between two updates of the liquid staking rates")
+	blockPoll = flag.Duration("blockPoll", time.Minute, "time between two polls of the latest block")
+)
+
+// main periodically stores the staking yields of proof of stake assets and, every few blocks,
+// the redemption rates of liquid staking tokens.
+func main() {
+	flag.Parse()
+	ds, err := models.NewDataStore()
+	if err != nil {
+		log.Fatal("NewDataStore: ", err)
+	}
+
+	stakingScrapers := make(map[string]stakingscrapers.StakingScraper)
+	for _, asset := range strings.Split(*assets, ",") {
+		scraper := stakingscrapers.New(asset)
+		if scraper == nil {
+			log.Fatalf("no staking scraper for asset %s", asset)
+		}
+		stakingScrapers[asset] = scraper
+	}
+	liquidStakingScraper, err := stakingscrapers.NewLiquidStaking(strings.Split(*tokens, ","))
+	if err != nil {
+		log.Fatal("liquid staking scraper: ", err)
+	}
+
+	go updateLiquidStakingRates(ds, liquidStakingScraper)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		for asset, scraper := range stakingScrapers {
+			yield, err := scraper.FetchYield()
+			if err != nil {
+				log.Errorf("staking yield of %s: %v", asset, err)
+				continue
+			}
+			log.Infof("staking yield of %s: %.2f%%", asset, yield.APR)
+			err = ds.SaveStakingYieldInflux(yield)
+			if err != nil {
+				log.Errorf("save staking yield of %s: %v", asset, err)
+			}
+		}
+	}
+}
+
+// updateLiquidStakingRates stores the redemption rates read by @scraper whenever the latest block
+// is at least *blocks past the block of the last update.
+func updateLiquidStakingRates(ds models.Datastore, scraper stakingscrapers.LiquidStakingScraper) {
+	var lastBlock uint64
+	ticker := time.NewTicker(*blockPoll)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		block, err := scraper.BlockNumber()
+		if err != nil {
+			log.Error("latest block: ", err)
+			continue
+		}
+		if lastBlock != 0 && block < lastBlock+*blocks {
+			continue
+		}
+		rates, err := scraper.FetchRates(block)
+		if err != nil {
+			log.Errorf("liquid staking rates at block %d: %v", block, err)
+			continue
+		}
+		for _, rate := range rates {
+			log.Infof("liquid staking rate of %s at block %d: %v %s", rate.Token, block, rate.Rate, rate.Underlying)
+			err = ds.SaveLiquidStakingRateInflux(rate)
+			if err != nil {
+				log.Errorf("save liquid staking rate of %s: %v", rate.Token, err)
+			}
+		}
+		lastBlock = block
+	}
+}
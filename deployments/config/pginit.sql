@@ -1,4 +1,5 @@
 CREATE EXTENSION "pgcrypto";
+CREATE EXTENSION "pg_trgm";
 
 
 -- Table asset is the single source of truth for all assets handled at DIA.
@@ -16,6 +17,10 @@ CREATE TABLE asset (
     UNIQUE (address, blockchain)
 );
 
+-- Trigram indexes serve the fuzzy asset search on symbol and name.
+CREATE INDEX asset_symbol_trgm ON asset USING gin (symbol gin_trgm_ops);
+CREATE INDEX asset_name_trgm ON asset USING gin (name gin_trgm_ops);
+
 -- Table exchangepair holds all trading pairs for the pair scrapers.
 -- The format has to be the same as emitted by the exchange's API in order
 -- for the pair scrapers to be able to scrape trading data from the API.
@@ -29,18 +34,38 @@ CREATE TABLE exchangepair (
     -- Only trades with verified pairs are processed further and thereby enter price calculation.
     verified boolean default false,
     id_quotetoken uuid REFERENCES asset(asset_id),
-    id_basetoken uuid REFERENCES asset(asset_id)
+    id_basetoken uuid REFERENCES asset(asset_id),
+    -- basesymbol is the exchange's symbol of the token prices are given in, as in dia.Trade.
+    basesymbol text,
+    -- ambiguous is set if symbol or basesymbol matches several assets.
+    ambiguous boolean default false
 );
 
+-- exchangesymbol maps the symbols of an exchange to assets. The pairdiscoveryservice verifies
+-- symbols matching exactly one asset and flags symbols matching several ones as ambiguous.
+-- Curated mappings are set through the admin API and never changed by the service.
 CREATE TABLE exchangesymbol (
     exchangesymbol_id UUID DEFAULT gen_random_uuid(),
     symbol text not null,
     exchange text not null,
     UNIQUE (symbol,exchange),
     verified boolean default false,
-    asset_id uuid REFERENCES asset(asset_id)
+    asset_id uuid REFERENCES asset(asset_id),
+    ambiguous boolean default false,
+    curated boolean default false
+);
+
+-- assetequivalence groups the representations of one economic asset on several blockchains,
+-- e.g. bridged or wrapped tokens. Trades of all assets of a group feed the price of the
+-- canonical symbol, besides the price of their own symbol.
+CREATE TABLE assetequivalence (
+    asset_id uuid REFERENCES asset(asset_id) ON DELETE CASCADE,
+    canonical text not null,
+    UNIQUE(asset_id)
 );
 
+CREATE INDEX assetequivalence_canonical ON assetequivalence (canonical);
+
 -- blockchain table stores all blockchains available in our databases
 CREATE TABLE blockchain (
     blockchain_id integer primary key generated always as identity,
@@ -162,3 +187,127 @@ CREATE TABLE blockdata (
     UNIQUE(blockchain, block_number),
     UNIQUE(blockdata_id)
 );
+
+---------------------------------------
+---- tables for admin permissions -----
+---------------------------------------
+
+-- adminuser holds all users of the authenticated admin/curation endpoints.
+-- role is one of viewer, curator, operator, admin.
+CREATE TABLE adminuser (
+    adminuser_id UUID DEFAULT gen_random_uuid(),
+    username text not null,
+    password_hash text not null,
+    role text not null,
+    UNIQUE(username),
+    UNIQUE(adminuser_id)
+);
+
+-- adminaudit records every mutating action performed on the admin API.
+CREATE TABLE adminaudit (
+    adminaudit_id UUID DEFAULT gen_random_uuid(),
+    username text not null,
+    role text,
+    method text not null,
+    path text not null,
+    body text,
+    status_code integer,
+    action_time timestamp not null,
+    UNIQUE(adminaudit_id)
+);
+
+---------------------------------------
+------- tables for fixing rates -------
+---------------------------------------
+
+-- fixingrate holds the once-daily reference prices. Fixings are immutable once published.
+CREATE TABLE fixingrate (
+    fixingrate_id UUID DEFAULT gen_random_uuid(),
+    symbol text not null,
+    price numeric not null,
+    volume numeric,
+    num_trades integer,
+    methodology text not null,
+    window_start timestamp not null,
+    window_end timestamp not null,
+    fixing_time timestamp not null,
+    computed_at timestamp not null,
+    UNIQUE(symbol, fixing_time),
+    UNIQUE(fixingrate_id)
+);
+
+CREATE RULE fixingrate_no_update AS ON UPDATE TO fixingrate DO INSTEAD NOTHING;
+CREATE RULE fixingrate_no_delete AS ON DELETE TO fixingrate DO INSTEAD NOTHING;
+
+---------------------------------------
+------ tables for oracle updates ------
+---------------------------------------
+
+-- oracleupdate records every attempted and confirmed update of an oracle contract by the feeders.
+CREATE TABLE oracleupdate (
+    oracleupdate_id UUID DEFAULT gen_random_uuid(),
+    chain_id bigint not null,
+    contract text not null,
+    key text not null,
+    value numeric,
+    value_time timestamp,
+    tx_hash text,
+    gas_used bigint,
+    block_number bigint,
+    status text not null,
+    error text,
+    update_time timestamp not null,
+    UNIQUE(oracleupdate_id)
+);
+
+CREATE INDEX oracleupdate_key_time ON oracleupdate (key, update_time);
+
+CREATE RULE oracleupdate_no_update AS ON UPDATE TO oracleupdate DO INSTEAD NOTHING;
+CREATE RULE oracleupdate_no_delete AS ON DELETE TO oracleupdate DO INSTEAD NOTHING;
+
+-- basket is a user defined weighted basket of assets whose index value is computed from the
+-- filtered prices of its constituents.
+CREATE TABLE basket (
+    basket_id UUID DEFAULT gen_random_uuid(),
+    name text not null,
+    base_value numeric not null,
+    base_time timestamp not null,
+    creator text,
+    UNIQUE(basket_id)
+);
+
+-- basketconstituent holds the weights of the assets in a basket and their prices at base time.
+CREATE TABLE basketconstituent (
+    basket_id UUID REFERENCES basket(basket_id) ON DELETE CASCADE,
+    symbol text not null,
+    weight numeric not null,
+    base_price numeric not null,
+    UNIQUE(basket_id, symbol)
+);
+
+-- webhook is a subscription to a price alert, which the webhook service posts to url when its
+-- condition triggers.
+CREATE TABLE webhook (
+    webhook_id UUID DEFAULT gen_random_uuid(),
+    url text not null,
+    symbol text not null,
+    condition text not null,
+    threshold numeric not null,
+    window_minutes integer,
+    secret text not null,
+    creator text,
+    created_at timestamp not null,
+    UNIQUE(webhook_id)
+);
+
+-- webhookdeadletter holds the callbacks which failed after all retries.
+CREATE TABLE webhookdeadletter (
+    webhook_id UUID REFERENCES webhook(webhook_id) ON DELETE CASCADE,
+    payload text not null,
+    attempts integer,
+    status_code integer,
+    error text,
+    failed_at timestamp not null
+);
+
+CREATE INDEX webhookdeadletter_webhook_time ON webhookdeadletter (webhook_id, failed_at);
@@ -0,0 +1,57 @@
+package basisService
+
+import (
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+const year = 365 * 24 * time.Hour
+
+// ComputeBasis returns the relative basis between @markPrice and @spotPrice and its annualized
+// value. Perps have no expiry, so the basis is annualized like the funding payment which
+// converges it, i.e. it is assumed to be carried over every @fundingInterval.
+func ComputeBasis(spotPrice float64, markPrice float64, fundingInterval time.Duration) (basis float64, annualized float64) {
+	if spotPrice == 0 || fundingInterval <= 0 {
+		return 0, 0
+	}
+	basis = (markPrice - spotPrice) / spotPrice
+	annualized = basis * float64(year) / float64(fundingInterval)
+	return
+}
+
+// UpdateBasis computes the basis of all perps of @assets on @source against the DIA spot
+// quotations and stores them in influx.
+func UpdateBasis(ds models.Datastore, source PerpSource, assets []string) error {
+	marks, err := source.FetchMarkPrices(assets)
+	if err != nil {
+		return err
+	}
+	for _, mark := range marks {
+		quotation, err := ds.GetQuotation(mark.Asset)
+		if err != nil {
+			log.Errorf("get quotation of %s: %v", mark.Asset, err)
+			continue
+		}
+		basis, annualized := ComputeBasis(quotation.Price, mark.MarkPrice, mark.FundingInterval)
+		bp := models.BasisPoint{
+			Asset:           mark.Asset,
+			Venue:           source.Venue(),
+			Instrument:      mark.Instrument,
+			SpotPrice:       quotation.Price,
+			MarkPrice:       mark.MarkPrice,
+			Basis:           basis,
+			AnnualizedBasis: annualized,
+			FundingRate:     mark.FundingRate,
+			Time:            time.Now(),
+		}
+		err = ds.SaveBasisInflux(bp)
+		if err != nil {
+			log.Errorf("save basis of %s on %s: %v", mark.Asset, source.Venue(), err)
+		}
+	}
+	return nil
+}
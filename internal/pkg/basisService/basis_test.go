@@ -0,0 +1,22 @@
+package basisService
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeBasis(t *testing.T) {
+	basis, annualized := ComputeBasis(100, 100.1, 8*time.Hour)
+	if math.Abs(basis-0.001) > 1e-12 {
+		t.Errorf("expected basis 0.001, got %v", basis)
+	}
+	if math.Abs(annualized-1.095) > 1e-9 {
+		t.Errorf("expected annualized basis 1.095, got %v", annualized)
+	}
+
+	basis, annualized = ComputeBasis(0, 100, 8*time.Hour)
+	if basis != 0 || annualized != 0 {
+		t.Errorf("expected zero basis for zero spot price, got %v, %v", basis, annualized)
+	}
+}
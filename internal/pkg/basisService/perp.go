@@ -0,0 +1,149 @@
+package basisService
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+// PerpMark is the mark price of a perpetual swap along with its current funding rate.
+type PerpMark struct {
+	Asset           string
+	Instrument      string
+	MarkPrice       float64
+	FundingRate     float64
+	FundingInterval time.Duration
+	Time            time.Time
+}
+
+// PerpSource provides mark prices of the USD(T) margined perpetual swaps on a venue.
+type PerpSource interface {
+	Venue() string
+	FetchMarkPrices(assets []string) ([]PerpMark, error)
+}
+
+// BinancePerpSource fetches mark prices of the USDT margined perps on Binance futures.
+type BinancePerpSource struct{}
+
+// Venue -
+func (s *BinancePerpSource) Venue() string {
+	return "BinanceFutures"
+}
+
+// FetchMarkPrices returns the mark prices and last funding rates of the perps of @assets.
+func (s *BinancePerpSource) FetchMarkPrices(assets []string) ([]PerpMark, error) {
+	var resp []struct {
+		Symbol          string `json:"symbol"`
+		MarkPrice       string `json:"markPrice"`
+		LastFundingRate string `json:"lastFundingRate"`
+		Time            int64  `json:"time"`
+	}
+	data, err := utils.GetRequest("https://fapi.binance.com/fapi/v1/premiumIndex")
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	instruments := make(map[string]string)
+	for _, asset := range assets {
+		instruments[asset+"USDT"] = asset
+	}
+	var marks []PerpMark
+	for _, p := range resp {
+		asset, ok := instruments[p.Symbol]
+		if !ok {
+			continue
+		}
+		mark := PerpMark{
+			Asset:           asset,
+			Instrument:      p.Symbol,
+			FundingInterval: 8 * time.Hour,
+			Time:            time.Unix(0, p.Time*int64(time.Millisecond)),
+		}
+		mark.MarkPrice, err = strconv.ParseFloat(p.MarkPrice, 64)
+		if err != nil {
+			continue
+		}
+		mark.FundingRate, _ = strconv.ParseFloat(p.LastFundingRate, 64)
+		marks = append(marks, mark)
+	}
+	return marks, nil
+}
+
+// OKExPerpSource fetches mark prices of the USDT margined perpetual swaps on OKEx.
+type OKExPerpSource struct{}
+
+// Venue -
+func (s *OKExPerpSource) Venue() string {
+	return "OKExSwap"
+}
+
+type okexPublicResponse struct {
+	Code string              `json:"code"`
+	Msg  string              `json:"msg"`
+	Data []map[string]string `json:"data"`
+}
+
+// FetchMarkPrices returns the mark prices and current funding rates of the swaps of @assets.
+func (s *OKExPerpSource) FetchMarkPrices(assets []string) ([]PerpMark, error) {
+	var resp okexPublicResponse
+	data, err := utils.GetRequest("https://aws.okex.com/api/v5/public/mark-price?instType=SWAP")
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	instruments := make(map[string]string)
+	for _, asset := range assets {
+		instruments[asset+"-USDT-SWAP"] = asset
+	}
+	var marks []PerpMark
+	for _, p := range resp.Data {
+		asset, ok := instruments[p["instId"]]
+		if !ok {
+			continue
+		}
+		mark := PerpMark{
+			Asset:           asset,
+			Instrument:      p["instId"],
+			FundingInterval: 8 * time.Hour,
+		}
+		mark.MarkPrice, err = strconv.ParseFloat(p["markPx"], 64)
+		if err != nil {
+			continue
+		}
+		ts, _ := strconv.ParseInt(p["ts"], 10, 64)
+		mark.Time = time.Unix(0, ts*int64(time.Millisecond))
+		mark.FundingRate, err = s.fetchFundingRate(mark.Instrument)
+		if err != nil {
+			log.Warnf("funding rate of %s: %v", mark.Instrument, err)
+		}
+		marks = append(marks, mark)
+	}
+	return marks, nil
+}
+
+func (s *OKExPerpSource) fetchFundingRate(instrument string) (float64, error) {
+	var resp okexPublicResponse
+	data, err := utils.GetRequest("https://aws.okex.com/api/v5/public/funding-rate?instId=" + instrument)
+	if err != nil {
+		return 0, err
+	}
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Data) == 0 {
+		return 0, errors.New("no funding rate: " + resp.Msg)
+	}
+	return strconv.ParseFloat(resp.Data[0]["fundingRate"], 64)
+}
@@ -0,0 +1,74 @@
+pragma solidity 0.7.4;
+
+interface AggregatorV3Interface {
+    function decimals() external view returns (uint8);
+    function description() external view returns (string memory);
+    function version() external view returns (uint256);
+    function getRoundData(uint80 _roundId) external view returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound);
+    function latestRoundData() external view returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound);
+}
+
+// DIAAggregatorV3Adapter holds a single DIA feed, e.g. BTC/USD, behind Chainlink's
+// AggregatorV3Interface. Every update starts a new round.
+contract DIAAggregatorV3Adapter is AggregatorV3Interface {
+    struct Round {
+        int256 answer;
+        uint256 updatedAt;
+    }
+
+    uint8 public override decimals;
+    string public override description;
+    uint256 public constant override version = 1;
+
+    uint80 latestRoundId;
+    mapping (uint80 => Round) rounds;
+    address oracleUpdater;
+
+    event AnswerUpdated(int256 indexed current, uint256 indexed roundId, uint256 updatedAt);
+    event NewRound(uint256 indexed roundId, address indexed startedBy, uint256 startedAt);
+    event UpdaterAddressChange(address newUpdater);
+
+    constructor(uint8 _decimals, string memory _description) {
+        decimals = _decimals;
+        description = _description;
+        oracleUpdater = msg.sender;
+    }
+
+    function updateAnswer(int256 answer, uint256 updatedAt) public {
+        require(msg.sender == oracleUpdater);
+        require(updatedAt > rounds[latestRoundId].updatedAt, "Stale answer");
+        latestRoundId++;
+        rounds[latestRoundId] = Round(answer, updatedAt);
+        emit NewRound(latestRoundId, msg.sender, updatedAt);
+        emit AnswerUpdated(answer, latestRoundId, updatedAt);
+    }
+
+    function getRoundData(uint80 _roundId) public view override returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound) {
+        Round memory round = rounds[_roundId];
+        require(round.updatedAt > 0, "No data present");
+        return (_roundId, round.answer, round.updatedAt, round.updatedAt, _roundId);
+    }
+
+    function latestRoundData() external view override returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound) {
+        return getRoundData(latestRoundId);
+    }
+
+    // Legacy AggregatorInterface
+    function latestAnswer() external view returns (int256) {
+        return rounds[latestRoundId].answer;
+    }
+
+    function latestTimestamp() external view returns (uint256) {
+        return rounds[latestRoundId].updatedAt;
+    }
+
+    function latestRound() external view returns (uint256) {
+        return latestRoundId;
+    }
+
+    function updateOracleUpdaterAddress(address newOracleUpdaterAddress) public {
+        require(msg.sender == oracleUpdater);
+        oracleUpdater = newOracleUpdaterAddress;
+        emit UpdaterAddressChange(newOracleUpdaterAddress);
+    }
+}
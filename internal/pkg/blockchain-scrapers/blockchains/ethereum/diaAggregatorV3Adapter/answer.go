@@ -0,0 +1,34 @@
+package diaAggregatorV3Adapter
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ScaleAnswer returns @price as a fixed point answer with @decimals decimals, as expected by
+// consumers of AggregatorV3Interface. Digits beyond @decimals are truncated. The conversion
+// works on the shortest decimal representation of @price, so that e.g. 0.1 with 18 decimals
+// becomes 100000000000000000 rather than the binary approximation.
+func ScaleAnswer(price float64, decimals uint8) (*big.Int, error) {
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		return nil, fmt.Errorf("invalid price %v", price)
+	}
+	s := strconv.FormatFloat(price, 'f', -1, 64)
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+	if len(fraction) > int(decimals) {
+		fraction = fraction[:decimals]
+	} else {
+		fraction += strings.Repeat("0", int(decimals)-len(fraction))
+	}
+	answer, ok := new(big.Int).SetString(integer+fraction, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid price %v", price)
+	}
+	return answer, nil
+}
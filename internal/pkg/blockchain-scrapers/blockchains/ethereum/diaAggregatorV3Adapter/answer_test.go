@@ -0,0 +1,33 @@
+package diaAggregatorV3Adapter
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScaleAnswer(t *testing.T) {
+	cases := []struct {
+		price    float64
+		decimals uint8
+		answer   string
+	}{
+		{43012.12345678, 8, "4301212345678"},
+		{0.1, 18, "100000000000000000"},
+		{1.23456789123, 8, "123456789"},
+		{2, 0, "2"},
+		{-0.5, 8, "-50000000"},
+		{1e21, 8, "100000000000000000000000000000"},
+	}
+	for _, c := range cases {
+		answer, err := ScaleAnswer(c.price, c.decimals)
+		if err != nil {
+			t.Fatalf("ScaleAnswer(%v, %d): %v", c.price, c.decimals, err)
+		}
+		if answer.String() != c.answer {
+			t.Errorf("ScaleAnswer(%v, %d) = %s, expected %s", c.price, c.decimals, answer, c.answer)
+		}
+	}
+	if _, err := ScaleAnswer(math.NaN(), 8); err == nil {
+		t.Error("expected error for NaN")
+	}
+}
@@ -0,0 +1,17 @@
+package diaAggregatorV3Adapter
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// PackUpdateAnswer returns the calldata of updateAnswer(@answer, @updatedAt), e.g. for fee estimations.
+func PackUpdateAnswer(answer *big.Int, updatedAt *big.Int) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(DIAAggregatorV3AdapterABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack("updateAnswer", answer, updatedAt)
+}
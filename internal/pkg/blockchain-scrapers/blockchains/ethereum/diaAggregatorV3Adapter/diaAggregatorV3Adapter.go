@@ -0,0 +1,960 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package diaAggregatorV3Adapter
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// DIAAggregatorV3AdapterABI is the input ABI used to generate the binding from.
+const DIAAggregatorV3AdapterABI = "[{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"_decimals\",\"type\":\"uint8\"},{\"internalType\":\"string\",\"name\":\"_description\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"int256\",\"name\":\"current\",\"type\":\"int256\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"roundId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"updatedAt\",\"type\":\"uint256\"}],\"name\":\"AnswerUpdated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"roundId\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"startedBy\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"startedAt\",\"type\":\"uint256\"}],\"name\":\"NewRound\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"newUpdater\",\"type\":\"address\"}],\"name\":\"UpdaterAddressChange\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"description\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint80\",\"name\":\"_roundId\",\"type\":\"uint80\"}],\"name\":\"getRoundData\",\"outputs\":[{\"internalType\":\"uint80\",\"name\":\"roundId\",\"type\":\"uint80\"},{\"internalType\":\"int256\",\"name\":\"answer\",\"type\":\"int256\"},{\"internalType\":\"uint256\",\"name\":\"startedAt\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"updatedAt\",\"type\":\"uint256\"},{\"internalType\":\"uint80\",\"name\":\"answeredInRound\",\"type\":\"uint80\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"latestAnswer\",\"outputs\":[{\"internalType\":\"int256\",\"name\":\"\",\"type\":\"int256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"latestRound\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"latestRoundData\",\"outputs\":[{\"internalType\":\"uint80\",\"name\":\"roundId\",\"type\":\"uint80\"},{\"internalType\":\"int256\",\"name\":\"answer\",\"type\":\"int256\"},{\"internalType\":\"uint256\",\"name\":\"startedAt\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"updatedAt\",\"type\":\"uint256\"},{\"internalType\":\"uint80\",\"name\":\"answeredInRound\",\"type\":\"uint80\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"latestTimestamp\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"int256\",\"name\":\"answer\",\"type\":\"int256\"},{\"internalType\":\"uint256\",\"name\":\"updatedAt\",\"type\":\"uint256\"}],\"name\":\"updateAnswer\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newOracleUpdaterAddress\",\"type\":\"address\"}],\"name\":\"updateOracleUpdaterAddress\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"version\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]"
+
+// DIAAggregatorV3Adapter is an auto generated Go binding around an Ethereum contract.
+type DIAAggregatorV3Adapter struct {
+	DIAAggregatorV3AdapterCaller     // Read-only binding to the contract
+	DIAAggregatorV3AdapterTransactor // Write-only binding to the contract
+	DIAAggregatorV3AdapterFilterer   // Log filterer for contract events
+}
+
+// DIAAggregatorV3AdapterCaller is an auto generated read-only Go binding around an Ethereum contract.
+type DIAAggregatorV3AdapterCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIAAggregatorV3AdapterTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type DIAAggregatorV3AdapterTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIAAggregatorV3AdapterFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type DIAAggregatorV3AdapterFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIAAggregatorV3AdapterSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type DIAAggregatorV3AdapterSession struct {
+	Contract     *DIAAggregatorV3Adapter // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts           // Call options to use throughout this session
+	TransactOpts bind.TransactOpts       // Transaction auth options to use throughout this session
+}
+
+// DIAAggregatorV3AdapterCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type DIAAggregatorV3AdapterCallerSession struct {
+	Contract *DIAAggregatorV3AdapterCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts                 // Call options to use throughout this session
+}
+
+// DIAAggregatorV3AdapterTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type DIAAggregatorV3AdapterTransactorSession struct {
+	Contract     *DIAAggregatorV3AdapterTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts                 // Transaction auth options to use throughout this session
+}
+
+// DIAAggregatorV3AdapterRaw is an auto generated low-level Go binding around an Ethereum contract.
+type DIAAggregatorV3AdapterRaw struct {
+	Contract *DIAAggregatorV3Adapter // Generic contract binding to access the raw methods on
+}
+
+// DIAAggregatorV3AdapterCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type DIAAggregatorV3AdapterCallerRaw struct {
+	Contract *DIAAggregatorV3AdapterCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// DIAAggregatorV3AdapterTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type DIAAggregatorV3AdapterTransactorRaw struct {
+	Contract *DIAAggregatorV3AdapterTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewDIAAggregatorV3Adapter creates a new instance of DIAAggregatorV3Adapter, bound to a specific deployed contract.
+func NewDIAAggregatorV3Adapter(address common.Address, backend bind.ContractBackend) (*DIAAggregatorV3Adapter, error) {
+	contract, err := bindDIAAggregatorV3Adapter(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAggregatorV3Adapter{DIAAggregatorV3AdapterCaller: DIAAggregatorV3AdapterCaller{contract: contract}, DIAAggregatorV3AdapterTransactor: DIAAggregatorV3AdapterTransactor{contract: contract}, DIAAggregatorV3AdapterFilterer: DIAAggregatorV3AdapterFilterer{contract: contract}}, nil
+}
+
+// NewDIAAggregatorV3AdapterCaller creates a new read-only instance of DIAAggregatorV3Adapter, bound to a specific deployed contract.
+func NewDIAAggregatorV3AdapterCaller(address common.Address, caller bind.ContractCaller) (*DIAAggregatorV3AdapterCaller, error) {
+	contract, err := bindDIAAggregatorV3Adapter(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAggregatorV3AdapterCaller{contract: contract}, nil
+}
+
+// NewDIAAggregatorV3AdapterTransactor creates a new write-only instance of DIAAggregatorV3Adapter, bound to a specific deployed contract.
+func NewDIAAggregatorV3AdapterTransactor(address common.Address, transactor bind.ContractTransactor) (*DIAAggregatorV3AdapterTransactor, error) {
+	contract, err := bindDIAAggregatorV3Adapter(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAggregatorV3AdapterTransactor{contract: contract}, nil
+}
+
+// NewDIAAggregatorV3AdapterFilterer creates a new log filterer instance of DIAAggregatorV3Adapter, bound to a specific deployed contract.
+func NewDIAAggregatorV3AdapterFilterer(address common.Address, filterer bind.ContractFilterer) (*DIAAggregatorV3AdapterFilterer, error) {
+	contract, err := bindDIAAggregatorV3Adapter(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAggregatorV3AdapterFilterer{contract: contract}, nil
+}
+
+// bindDIAAggregatorV3Adapter binds a generic wrapper to an already deployed contract.
+func bindDIAAggregatorV3Adapter(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(DIAAggregatorV3AdapterABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _DIAAggregatorV3Adapter.Contract.DIAAggregatorV3AdapterCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.DIAAggregatorV3AdapterTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.DIAAggregatorV3AdapterTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _DIAAggregatorV3Adapter.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.contract.Transact(opts, method, params...)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "decimals")
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) Decimals() (uint8, error) {
+	return _DIAAggregatorV3Adapter.Contract.Decimals(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) Decimals() (uint8, error) {
+	return _DIAAggregatorV3Adapter.Contract.Decimals(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// Description is a free data retrieval call binding the contract method 0x7284e416.
+//
+// Solidity: function description() view returns(string)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) Description(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "description")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Description is a free data retrieval call binding the contract method 0x7284e416.
+//
+// Solidity: function description() view returns(string)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) Description() (string, error) {
+	return _DIAAggregatorV3Adapter.Contract.Description(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// Description is a free data retrieval call binding the contract method 0x7284e416.
+//
+// Solidity: function description() view returns(string)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) Description() (string, error) {
+	return _DIAAggregatorV3Adapter.Contract.Description(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// GetRoundData is a free data retrieval call binding the contract method 0x9a6fc8f5.
+//
+// Solidity: function getRoundData(uint80 _roundId) view returns(uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) GetRoundData(opts *bind.CallOpts, _roundId *big.Int) (struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "getRoundData", _roundId)
+
+	outstruct := new(struct {
+		RoundId         *big.Int
+		Answer          *big.Int
+		StartedAt       *big.Int
+		UpdatedAt       *big.Int
+		AnsweredInRound *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+	outstruct.RoundId = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.Answer = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.StartedAt = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.UpdatedAt = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.AnsweredInRound = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetRoundData is a free data retrieval call binding the contract method 0x9a6fc8f5.
+//
+// Solidity: function getRoundData(uint80 _roundId) view returns(uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) GetRoundData(_roundId *big.Int) (struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}, error) {
+	return _DIAAggregatorV3Adapter.Contract.GetRoundData(&_DIAAggregatorV3Adapter.CallOpts, _roundId)
+}
+
+// GetRoundData is a free data retrieval call binding the contract method 0x9a6fc8f5.
+//
+// Solidity: function getRoundData(uint80 _roundId) view returns(uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) GetRoundData(_roundId *big.Int) (struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}, error) {
+	return _DIAAggregatorV3Adapter.Contract.GetRoundData(&_DIAAggregatorV3Adapter.CallOpts, _roundId)
+}
+
+// LatestAnswer is a free data retrieval call binding the contract method 0x50d25bcd.
+//
+// Solidity: function latestAnswer() view returns(int256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) LatestAnswer(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "latestAnswer")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// LatestAnswer is a free data retrieval call binding the contract method 0x50d25bcd.
+//
+// Solidity: function latestAnswer() view returns(int256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) LatestAnswer() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestAnswer(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// LatestAnswer is a free data retrieval call binding the contract method 0x50d25bcd.
+//
+// Solidity: function latestAnswer() view returns(int256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) LatestAnswer() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestAnswer(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// LatestRound is a free data retrieval call binding the contract method 0x668a0f02.
+//
+// Solidity: function latestRound() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) LatestRound(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "latestRound")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// LatestRound is a free data retrieval call binding the contract method 0x668a0f02.
+//
+// Solidity: function latestRound() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) LatestRound() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestRound(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// LatestRound is a free data retrieval call binding the contract method 0x668a0f02.
+//
+// Solidity: function latestRound() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) LatestRound() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestRound(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// LatestRoundData is a free data retrieval call binding the contract method 0xfeaf968c.
+//
+// Solidity: function latestRoundData() view returns(uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) LatestRoundData(opts *bind.CallOpts) (struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "latestRoundData")
+
+	outstruct := new(struct {
+		RoundId         *big.Int
+		Answer          *big.Int
+		StartedAt       *big.Int
+		UpdatedAt       *big.Int
+		AnsweredInRound *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+	outstruct.RoundId = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.Answer = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.StartedAt = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.UpdatedAt = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.AnsweredInRound = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// LatestRoundData is a free data retrieval call binding the contract method 0xfeaf968c.
+//
+// Solidity: function latestRoundData() view returns(uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) LatestRoundData() (struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestRoundData(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// LatestRoundData is a free data retrieval call binding the contract method 0xfeaf968c.
+//
+// Solidity: function latestRoundData() view returns(uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) LatestRoundData() (struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestRoundData(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// LatestTimestamp is a free data retrieval call binding the contract method 0x8205bf6a.
+//
+// Solidity: function latestTimestamp() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) LatestTimestamp(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "latestTimestamp")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// LatestTimestamp is a free data retrieval call binding the contract method 0x8205bf6a.
+//
+// Solidity: function latestTimestamp() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) LatestTimestamp() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestTimestamp(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// LatestTimestamp is a free data retrieval call binding the contract method 0x8205bf6a.
+//
+// Solidity: function latestTimestamp() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) LatestTimestamp() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.LatestTimestamp(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// Version is a free data retrieval call binding the contract method 0x54fd4d50.
+//
+// Solidity: function version() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCaller) Version(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _DIAAggregatorV3Adapter.contract.Call(opts, &out, "version")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Version is a free data retrieval call binding the contract method 0x54fd4d50.
+//
+// Solidity: function version() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) Version() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.Version(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// Version is a free data retrieval call binding the contract method 0x54fd4d50.
+//
+// Solidity: function version() view returns(uint256)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterCallerSession) Version() (*big.Int, error) {
+	return _DIAAggregatorV3Adapter.Contract.Version(&_DIAAggregatorV3Adapter.CallOpts)
+}
+
+// UpdateAnswer is a paid mutator transaction binding the contract method 0x231702b8.
+//
+// Solidity: function updateAnswer(int256 answer, uint256 updatedAt) returns()
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterTransactor) UpdateAnswer(opts *bind.TransactOpts, answer *big.Int, updatedAt *big.Int) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.contract.Transact(opts, "updateAnswer", answer, updatedAt)
+}
+
+// UpdateAnswer is a paid mutator transaction binding the contract method 0x231702b8.
+//
+// Solidity: function updateAnswer(int256 answer, uint256 updatedAt) returns()
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) UpdateAnswer(answer *big.Int, updatedAt *big.Int) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.UpdateAnswer(&_DIAAggregatorV3Adapter.TransactOpts, answer, updatedAt)
+}
+
+// UpdateAnswer is a paid mutator transaction binding the contract method 0x231702b8.
+//
+// Solidity: function updateAnswer(int256 answer, uint256 updatedAt) returns()
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterTransactorSession) UpdateAnswer(answer *big.Int, updatedAt *big.Int) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.UpdateAnswer(&_DIAAggregatorV3Adapter.TransactOpts, answer, updatedAt)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterTransactor) UpdateOracleUpdaterAddress(opts *bind.TransactOpts, newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.contract.Transact(opts, "updateOracleUpdaterAddress", newOracleUpdaterAddress)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterSession) UpdateOracleUpdaterAddress(newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.UpdateOracleUpdaterAddress(&_DIAAggregatorV3Adapter.TransactOpts, newOracleUpdaterAddress)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterTransactorSession) UpdateOracleUpdaterAddress(newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIAAggregatorV3Adapter.Contract.UpdateOracleUpdaterAddress(&_DIAAggregatorV3Adapter.TransactOpts, newOracleUpdaterAddress)
+}
+
+// DIAAggregatorV3AdapterAnswerUpdatedIterator is returned from FilterAnswerUpdated and is used to iterate over the raw logs and unpacked data for AnswerUpdated events raised by the DIAAggregatorV3Adapter contract.
+type DIAAggregatorV3AdapterAnswerUpdatedIterator struct {
+	Event *DIAAggregatorV3AdapterAnswerUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIAAggregatorV3AdapterAnswerUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIAAggregatorV3AdapterAnswerUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIAAggregatorV3AdapterAnswerUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIAAggregatorV3AdapterAnswerUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIAAggregatorV3AdapterAnswerUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIAAggregatorV3AdapterAnswerUpdated represents a AnswerUpdated event raised by the DIAAggregatorV3Adapter contract.
+type DIAAggregatorV3AdapterAnswerUpdated struct {
+	Current   *big.Int
+	RoundId   *big.Int
+	UpdatedAt *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterAnswerUpdated is a free log retrieval operation binding the contract event 0x0559884fd3a460db3073b7fc896cc77986f16e378210ded43186175bf646fc5f.
+//
+// Solidity: event AnswerUpdated(int256 indexed current, uint256 indexed roundId, uint256 updatedAt)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) FilterAnswerUpdated(opts *bind.FilterOpts, current []*big.Int, roundId []*big.Int) (*DIAAggregatorV3AdapterAnswerUpdatedIterator, error) {
+
+	var currentRule []interface{}
+	for _, currentItem := range current {
+		currentRule = append(currentRule, currentItem)
+	}
+	var roundIdRule []interface{}
+	for _, roundIdItem := range roundId {
+		roundIdRule = append(roundIdRule, roundIdItem)
+	}
+
+	logs, sub, err := _DIAAggregatorV3Adapter.contract.FilterLogs(opts, "AnswerUpdated", currentRule, roundIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAggregatorV3AdapterAnswerUpdatedIterator{contract: _DIAAggregatorV3Adapter.contract, event: "AnswerUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchAnswerUpdated is a free log subscription operation binding the contract event 0x0559884fd3a460db3073b7fc896cc77986f16e378210ded43186175bf646fc5f.
+//
+// Solidity: event AnswerUpdated(int256 indexed current, uint256 indexed roundId, uint256 updatedAt)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) WatchAnswerUpdated(opts *bind.WatchOpts, sink chan<- *DIAAggregatorV3AdapterAnswerUpdated, current []*big.Int, roundId []*big.Int) (event.Subscription, error) {
+
+	var currentRule []interface{}
+	for _, currentItem := range current {
+		currentRule = append(currentRule, currentItem)
+	}
+	var roundIdRule []interface{}
+	for _, roundIdItem := range roundId {
+		roundIdRule = append(roundIdRule, roundIdItem)
+	}
+
+	logs, sub, err := _DIAAggregatorV3Adapter.contract.WatchLogs(opts, "AnswerUpdated", currentRule, roundIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIAAggregatorV3AdapterAnswerUpdated)
+				if err := _DIAAggregatorV3Adapter.contract.UnpackLog(event, "AnswerUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAnswerUpdated is a log parse operation binding the contract event 0x0559884fd3a460db3073b7fc896cc77986f16e378210ded43186175bf646fc5f.
+//
+// Solidity: event AnswerUpdated(int256 indexed current, uint256 indexed roundId, uint256 updatedAt)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) ParseAnswerUpdated(log types.Log) (*DIAAggregatorV3AdapterAnswerUpdated, error) {
+	event := new(DIAAggregatorV3AdapterAnswerUpdated)
+	if err := _DIAAggregatorV3Adapter.contract.UnpackLog(event, "AnswerUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// DIAAggregatorV3AdapterNewRoundIterator is returned from FilterNewRound and is used to iterate over the raw logs and unpacked data for NewRound events raised by the DIAAggregatorV3Adapter contract.
+type DIAAggregatorV3AdapterNewRoundIterator struct {
+	Event *DIAAggregatorV3AdapterNewRound // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIAAggregatorV3AdapterNewRoundIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIAAggregatorV3AdapterNewRound)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIAAggregatorV3AdapterNewRound)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIAAggregatorV3AdapterNewRoundIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIAAggregatorV3AdapterNewRoundIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIAAggregatorV3AdapterNewRound represents a NewRound event raised by the DIAAggregatorV3Adapter contract.
+type DIAAggregatorV3AdapterNewRound struct {
+	RoundId   *big.Int
+	StartedBy common.Address
+	StartedAt *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterNewRound is a free log retrieval operation binding the contract event 0x0109fc6f55cf40689f02fbaad7af7fe7bbac8a3d2186600afc7d3e10cac60271.
+//
+// Solidity: event NewRound(uint256 indexed roundId, address indexed startedBy, uint256 startedAt)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) FilterNewRound(opts *bind.FilterOpts, roundId []*big.Int, startedBy []common.Address) (*DIAAggregatorV3AdapterNewRoundIterator, error) {
+
+	var roundIdRule []interface{}
+	for _, roundIdItem := range roundId {
+		roundIdRule = append(roundIdRule, roundIdItem)
+	}
+	var startedByRule []interface{}
+	for _, startedByItem := range startedBy {
+		startedByRule = append(startedByRule, startedByItem)
+	}
+
+	logs, sub, err := _DIAAggregatorV3Adapter.contract.FilterLogs(opts, "NewRound", roundIdRule, startedByRule)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAggregatorV3AdapterNewRoundIterator{contract: _DIAAggregatorV3Adapter.contract, event: "NewRound", logs: logs, sub: sub}, nil
+}
+
+// WatchNewRound is a free log subscription operation binding the contract event 0x0109fc6f55cf40689f02fbaad7af7fe7bbac8a3d2186600afc7d3e10cac60271.
+//
+// Solidity: event NewRound(uint256 indexed roundId, address indexed startedBy, uint256 startedAt)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) WatchNewRound(opts *bind.WatchOpts, sink chan<- *DIAAggregatorV3AdapterNewRound, roundId []*big.Int, startedBy []common.Address) (event.Subscription, error) {
+
+	var roundIdRule []interface{}
+	for _, roundIdItem := range roundId {
+		roundIdRule = append(roundIdRule, roundIdItem)
+	}
+	var startedByRule []interface{}
+	for _, startedByItem := range startedBy {
+		startedByRule = append(startedByRule, startedByItem)
+	}
+
+	logs, sub, err := _DIAAggregatorV3Adapter.contract.WatchLogs(opts, "NewRound", roundIdRule, startedByRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIAAggregatorV3AdapterNewRound)
+				if err := _DIAAggregatorV3Adapter.contract.UnpackLog(event, "NewRound", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseNewRound is a log parse operation binding the contract event 0x0109fc6f55cf40689f02fbaad7af7fe7bbac8a3d2186600afc7d3e10cac60271.
+//
+// Solidity: event NewRound(uint256 indexed roundId, address indexed startedBy, uint256 startedAt)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) ParseNewRound(log types.Log) (*DIAAggregatorV3AdapterNewRound, error) {
+	event := new(DIAAggregatorV3AdapterNewRound)
+	if err := _DIAAggregatorV3Adapter.contract.UnpackLog(event, "NewRound", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// DIAAggregatorV3AdapterUpdaterAddressChangeIterator is returned from FilterUpdaterAddressChange and is used to iterate over the raw logs and unpacked data for UpdaterAddressChange events raised by the DIAAggregatorV3Adapter contract.
+type DIAAggregatorV3AdapterUpdaterAddressChangeIterator struct {
+	Event *DIAAggregatorV3AdapterUpdaterAddressChange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIAAggregatorV3AdapterUpdaterAddressChangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIAAggregatorV3AdapterUpdaterAddressChange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIAAggregatorV3AdapterUpdaterAddressChange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIAAggregatorV3AdapterUpdaterAddressChangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIAAggregatorV3AdapterUpdaterAddressChangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIAAggregatorV3AdapterUpdaterAddressChange represents a UpdaterAddressChange event raised by the DIAAggregatorV3Adapter contract.
+type DIAAggregatorV3AdapterUpdaterAddressChange struct {
+	NewUpdater common.Address
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterUpdaterAddressChange is a free log retrieval operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) FilterUpdaterAddressChange(opts *bind.FilterOpts) (*DIAAggregatorV3AdapterUpdaterAddressChangeIterator, error) {
+
+	logs, sub, err := _DIAAggregatorV3Adapter.contract.FilterLogs(opts, "UpdaterAddressChange")
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAggregatorV3AdapterUpdaterAddressChangeIterator{contract: _DIAAggregatorV3Adapter.contract, event: "UpdaterAddressChange", logs: logs, sub: sub}, nil
+}
+
+// WatchUpdaterAddressChange is a free log subscription operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) WatchUpdaterAddressChange(opts *bind.WatchOpts, sink chan<- *DIAAggregatorV3AdapterUpdaterAddressChange) (event.Subscription, error) {
+
+	logs, sub, err := _DIAAggregatorV3Adapter.contract.WatchLogs(opts, "UpdaterAddressChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIAAggregatorV3AdapterUpdaterAddressChange)
+				if err := _DIAAggregatorV3Adapter.contract.UnpackLog(event, "UpdaterAddressChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUpdaterAddressChange is a log parse operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIAAggregatorV3Adapter *DIAAggregatorV3AdapterFilterer) ParseUpdaterAddressChange(log types.Log) (*DIAAggregatorV3AdapterUpdaterAddressChange, error) {
+	event := new(DIAAggregatorV3AdapterUpdaterAddressChange)
+	if err := _DIAAggregatorV3Adapter.contract.UnpackLog(event, "UpdaterAddressChange", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
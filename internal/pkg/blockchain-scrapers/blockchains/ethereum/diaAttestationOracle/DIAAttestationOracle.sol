@@ -0,0 +1,71 @@
+pragma solidity 0.7.4;
+
+// DIAAttestationOracle stores arbitrary values, e.g. bridge state roots or supply attestations,
+// under string keys. Values are signed off-chain by the attestor and submitted by the updater,
+// so that a compromised updater wallet can't forge values.
+contract DIAAttestationOracle {
+    struct Attestation {
+        bytes value;
+        uint128 timestamp;
+    }
+
+    mapping (string => Attestation) values;
+    address oracleUpdater;
+    address attestor;
+
+    event OracleUpdate(string key, bytes value, uint128 timestamp);
+    event UpdaterAddressChange(address newUpdater);
+    event AttestorChange(address newAttestor);
+
+    constructor(address _attestor) {
+        oracleUpdater = msg.sender;
+        attestor = _attestor;
+    }
+
+    function setValue(string memory key, bytes memory value, uint128 timestamp, bytes memory signature) public {
+        require(msg.sender == oracleUpdater);
+        require(timestamp > values[key].timestamp, "Stale attestation");
+        require(recover(digest(key, value, timestamp), signature) == attestor, "Invalid signature");
+        values[key] = Attestation(value, timestamp);
+        emit OracleUpdate(key, value, timestamp);
+    }
+
+    function getValue(string memory key) external view returns (bytes memory, uint128) {
+        Attestation memory a = values[key];
+        return (a.value, a.timestamp);
+    }
+
+    // digest is the hash signed by the attestor, following eth_sign.
+    function digest(string memory key, bytes memory value, uint128 timestamp) public view returns (bytes32) {
+        bytes32 hash = keccak256(abi.encode(address(this), key, value, timestamp));
+        return keccak256(abi.encodePacked("\x19Ethereum Signed Message:\n32", hash));
+    }
+
+    function recover(bytes32 hash, bytes memory signature) internal pure returns (address) {
+        require(signature.length == 65, "Invalid signature length");
+        bytes32 r;
+        bytes32 s;
+        uint8 v;
+        assembly {
+            r := mload(add(signature, 32))
+            s := mload(add(signature, 64))
+            v := byte(0, mload(add(signature, 96)))
+        }
+        if (v < 27) {
+            v += 27;
+        }
+        return ecrecover(hash, v, r, s);
+    }
+
+    function updateOracleUpdaterAddress(address newOracleUpdaterAddress) public {
+        require(msg.sender == oracleUpdater);
+        oracleUpdater = newOracleUpdaterAddress;
+        emit UpdaterAddressChange(newOracleUpdaterAddress);
+    }
+
+    function updateAttestor(address newAttestor) public {
+        require(msg.sender == oracleUpdater);
+        attestor = newAttestor;
+        emit AttestorChange(newAttestor);
+    }
+}
@@ -0,0 +1,17 @@
+package diaAttestationOracle
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// PackSetValue returns the calldata of setValue(@key, @value, @timestamp, @signature), e.g. for fee estimations.
+func PackSetValue(key string, value []byte, timestamp *big.Int, signature []byte) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(DIAAttestationOracleABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack("setValue", key, value, timestamp, signature)
+}
@@ -0,0 +1,702 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package diaAttestationOracle
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// DIAAttestationOracleABI is the input ABI used to generate the binding from.
+const DIAAttestationOracleABI = "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_attestor\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"newAttestor\",\"type\":\"address\"}],\"name\":\"AttestorChange\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"string\",\"name\":\"key\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"value\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"uint128\",\"name\":\"timestamp\",\"type\":\"uint128\"}],\"name\":\"OracleUpdate\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"newUpdater\",\"type\":\"address\"}],\"name\":\"UpdaterAddressChange\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"key\",\"type\":\"string\"},{\"internalType\":\"bytes\",\"name\":\"value\",\"type\":\"bytes\"},{\"internalType\":\"uint128\",\"name\":\"timestamp\",\"type\":\"uint128\"}],\"name\":\"digest\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"key\",\"type\":\"string\"}],\"name\":\"getValue\",\"outputs\":[{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"},{\"internalType\":\"uint128\",\"name\":\"\",\"type\":\"uint128\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"key\",\"type\":\"string\"},{\"internalType\":\"bytes\",\"name\":\"value\",\"type\":\"bytes\"},{\"internalType\":\"uint128\",\"name\":\"timestamp\",\"type\":\"uint128\"},{\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"name\":\"setValue\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newAttestor\",\"type\":\"address\"}],\"name\":\"updateAttestor\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newOracleUpdaterAddress\",\"type\":\"address\"}],\"name\":\"updateOracleUpdaterAddress\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+
+// DIAAttestationOracle is an auto generated Go binding around an Ethereum contract.
+type DIAAttestationOracle struct {
+	DIAAttestationOracleCaller     // Read-only binding to the contract
+	DIAAttestationOracleTransactor // Write-only binding to the contract
+	DIAAttestationOracleFilterer   // Log filterer for contract events
+}
+
+// DIAAttestationOracleCaller is an auto generated read-only Go binding around an Ethereum contract.
+type DIAAttestationOracleCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIAAttestationOracleTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type DIAAttestationOracleTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIAAttestationOracleFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type DIAAttestationOracleFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIAAttestationOracleSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type DIAAttestationOracleSession struct {
+	Contract     *DIAAttestationOracle // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts         // Call options to use throughout this session
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// DIAAttestationOracleCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type DIAAttestationOracleCallerSession struct {
+	Contract *DIAAttestationOracleCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts               // Call options to use throughout this session
+}
+
+// DIAAttestationOracleTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type DIAAttestationOracleTransactorSession struct {
+	Contract     *DIAAttestationOracleTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts               // Transaction auth options to use throughout this session
+}
+
+// DIAAttestationOracleRaw is an auto generated low-level Go binding around an Ethereum contract.
+type DIAAttestationOracleRaw struct {
+	Contract *DIAAttestationOracle // Generic contract binding to access the raw methods on
+}
+
+// DIAAttestationOracleCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type DIAAttestationOracleCallerRaw struct {
+	Contract *DIAAttestationOracleCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// DIAAttestationOracleTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type DIAAttestationOracleTransactorRaw struct {
+	Contract *DIAAttestationOracleTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewDIAAttestationOracle creates a new instance of DIAAttestationOracle, bound to a specific deployed contract.
+func NewDIAAttestationOracle(address common.Address, backend bind.ContractBackend) (*DIAAttestationOracle, error) {
+	contract, err := bindDIAAttestationOracle(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAttestationOracle{DIAAttestationOracleCaller: DIAAttestationOracleCaller{contract: contract}, DIAAttestationOracleTransactor: DIAAttestationOracleTransactor{contract: contract}, DIAAttestationOracleFilterer: DIAAttestationOracleFilterer{contract: contract}}, nil
+}
+
+// NewDIAAttestationOracleCaller creates a new read-only instance of DIAAttestationOracle, bound to a specific deployed contract.
+func NewDIAAttestationOracleCaller(address common.Address, caller bind.ContractCaller) (*DIAAttestationOracleCaller, error) {
+	contract, err := bindDIAAttestationOracle(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAttestationOracleCaller{contract: contract}, nil
+}
+
+// NewDIAAttestationOracleTransactor creates a new write-only instance of DIAAttestationOracle, bound to a specific deployed contract.
+func NewDIAAttestationOracleTransactor(address common.Address, transactor bind.ContractTransactor) (*DIAAttestationOracleTransactor, error) {
+	contract, err := bindDIAAttestationOracle(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAttestationOracleTransactor{contract: contract}, nil
+}
+
+// NewDIAAttestationOracleFilterer creates a new log filterer instance of DIAAttestationOracle, bound to a specific deployed contract.
+func NewDIAAttestationOracleFilterer(address common.Address, filterer bind.ContractFilterer) (*DIAAttestationOracleFilterer, error) {
+	contract, err := bindDIAAttestationOracle(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAttestationOracleFilterer{contract: contract}, nil
+}
+
+// bindDIAAttestationOracle binds a generic wrapper to an already deployed contract.
+func bindDIAAttestationOracle(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(DIAAttestationOracleABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_DIAAttestationOracle *DIAAttestationOracleRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _DIAAttestationOracle.Contract.DIAAttestationOracleCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_DIAAttestationOracle *DIAAttestationOracleRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.DIAAttestationOracleTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_DIAAttestationOracle *DIAAttestationOracleRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.DIAAttestationOracleTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_DIAAttestationOracle *DIAAttestationOracleCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _DIAAttestationOracle.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_DIAAttestationOracle *DIAAttestationOracleTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_DIAAttestationOracle *DIAAttestationOracleTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.contract.Transact(opts, method, params...)
+}
+
+// Digest is a free data retrieval call binding the contract method 0x954fcd77.
+//
+// Solidity: function digest(string key, bytes value, uint128 timestamp) view returns(bytes32)
+func (_DIAAttestationOracle *DIAAttestationOracleCaller) Digest(opts *bind.CallOpts, key string, value []byte, timestamp *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _DIAAttestationOracle.contract.Call(opts, &out, "digest", key, value, timestamp)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// Digest is a free data retrieval call binding the contract method 0x954fcd77.
+//
+// Solidity: function digest(string key, bytes value, uint128 timestamp) view returns(bytes32)
+func (_DIAAttestationOracle *DIAAttestationOracleSession) Digest(key string, value []byte, timestamp *big.Int) ([32]byte, error) {
+	return _DIAAttestationOracle.Contract.Digest(&_DIAAttestationOracle.CallOpts, key, value, timestamp)
+}
+
+// Digest is a free data retrieval call binding the contract method 0x954fcd77.
+//
+// Solidity: function digest(string key, bytes value, uint128 timestamp) view returns(bytes32)
+func (_DIAAttestationOracle *DIAAttestationOracleCallerSession) Digest(key string, value []byte, timestamp *big.Int) ([32]byte, error) {
+	return _DIAAttestationOracle.Contract.Digest(&_DIAAttestationOracle.CallOpts, key, value, timestamp)
+}
+
+// GetValue is a free data retrieval call binding the contract method 0x960384a0.
+//
+// Solidity: function getValue(string key) view returns(bytes, uint128)
+func (_DIAAttestationOracle *DIAAttestationOracleCaller) GetValue(opts *bind.CallOpts, key string) ([]byte, *big.Int, error) {
+	var out []interface{}
+	err := _DIAAttestationOracle.contract.Call(opts, &out, "getValue", key)
+
+	if err != nil {
+		return *new([]byte), *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return out0, out1, err
+
+}
+
+// GetValue is a free data retrieval call binding the contract method 0x960384a0.
+//
+// Solidity: function getValue(string key) view returns(bytes, uint128)
+func (_DIAAttestationOracle *DIAAttestationOracleSession) GetValue(key string) ([]byte, *big.Int, error) {
+	return _DIAAttestationOracle.Contract.GetValue(&_DIAAttestationOracle.CallOpts, key)
+}
+
+// GetValue is a free data retrieval call binding the contract method 0x960384a0.
+//
+// Solidity: function getValue(string key) view returns(bytes, uint128)
+func (_DIAAttestationOracle *DIAAttestationOracleCallerSession) GetValue(key string) ([]byte, *big.Int, error) {
+	return _DIAAttestationOracle.Contract.GetValue(&_DIAAttestationOracle.CallOpts, key)
+}
+
+// SetValue is a paid mutator transaction binding the contract method 0xd65e3ab1.
+//
+// Solidity: function setValue(string key, bytes value, uint128 timestamp, bytes signature) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleTransactor) SetValue(opts *bind.TransactOpts, key string, value []byte, timestamp *big.Int, signature []byte) (*types.Transaction, error) {
+	return _DIAAttestationOracle.contract.Transact(opts, "setValue", key, value, timestamp, signature)
+}
+
+// SetValue is a paid mutator transaction binding the contract method 0xd65e3ab1.
+//
+// Solidity: function setValue(string key, bytes value, uint128 timestamp, bytes signature) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleSession) SetValue(key string, value []byte, timestamp *big.Int, signature []byte) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.SetValue(&_DIAAttestationOracle.TransactOpts, key, value, timestamp, signature)
+}
+
+// SetValue is a paid mutator transaction binding the contract method 0xd65e3ab1.
+//
+// Solidity: function setValue(string key, bytes value, uint128 timestamp, bytes signature) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleTransactorSession) SetValue(key string, value []byte, timestamp *big.Int, signature []byte) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.SetValue(&_DIAAttestationOracle.TransactOpts, key, value, timestamp, signature)
+}
+
+// UpdateAttestor is a paid mutator transaction binding the contract method 0xcf7f0e19.
+//
+// Solidity: function updateAttestor(address newAttestor) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleTransactor) UpdateAttestor(opts *bind.TransactOpts, newAttestor common.Address) (*types.Transaction, error) {
+	return _DIAAttestationOracle.contract.Transact(opts, "updateAttestor", newAttestor)
+}
+
+// UpdateAttestor is a paid mutator transaction binding the contract method 0xcf7f0e19.
+//
+// Solidity: function updateAttestor(address newAttestor) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleSession) UpdateAttestor(newAttestor common.Address) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.UpdateAttestor(&_DIAAttestationOracle.TransactOpts, newAttestor)
+}
+
+// UpdateAttestor is a paid mutator transaction binding the contract method 0xcf7f0e19.
+//
+// Solidity: function updateAttestor(address newAttestor) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleTransactorSession) UpdateAttestor(newAttestor common.Address) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.UpdateAttestor(&_DIAAttestationOracle.TransactOpts, newAttestor)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleTransactor) UpdateOracleUpdaterAddress(opts *bind.TransactOpts, newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIAAttestationOracle.contract.Transact(opts, "updateOracleUpdaterAddress", newOracleUpdaterAddress)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleSession) UpdateOracleUpdaterAddress(newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.UpdateOracleUpdaterAddress(&_DIAAttestationOracle.TransactOpts, newOracleUpdaterAddress)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIAAttestationOracle *DIAAttestationOracleTransactorSession) UpdateOracleUpdaterAddress(newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIAAttestationOracle.Contract.UpdateOracleUpdaterAddress(&_DIAAttestationOracle.TransactOpts, newOracleUpdaterAddress)
+}
+
+// DIAAttestationOracleAttestorChangeIterator is returned from FilterAttestorChange and is used to iterate over the raw logs and unpacked data for AttestorChange events raised by the DIAAttestationOracle contract.
+type DIAAttestationOracleAttestorChangeIterator struct {
+	Event *DIAAttestationOracleAttestorChange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIAAttestationOracleAttestorChangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIAAttestationOracleAttestorChange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIAAttestationOracleAttestorChange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIAAttestationOracleAttestorChangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIAAttestationOracleAttestorChangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIAAttestationOracleAttestorChange represents a AttestorChange event raised by the DIAAttestationOracle contract.
+type DIAAttestationOracleAttestorChange struct {
+	NewAttestor common.Address
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterAttestorChange is a free log retrieval operation binding the contract event 0x83efcb9cc0dbacf1d19eeb49621e303bc96d13da027f577f96d5d839ec1c35b1.
+//
+// Solidity: event AttestorChange(address newAttestor)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) FilterAttestorChange(opts *bind.FilterOpts) (*DIAAttestationOracleAttestorChangeIterator, error) {
+
+	logs, sub, err := _DIAAttestationOracle.contract.FilterLogs(opts, "AttestorChange")
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAttestationOracleAttestorChangeIterator{contract: _DIAAttestationOracle.contract, event: "AttestorChange", logs: logs, sub: sub}, nil
+}
+
+// WatchAttestorChange is a free log subscription operation binding the contract event 0x83efcb9cc0dbacf1d19eeb49621e303bc96d13da027f577f96d5d839ec1c35b1.
+//
+// Solidity: event AttestorChange(address newAttestor)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) WatchAttestorChange(opts *bind.WatchOpts, sink chan<- *DIAAttestationOracleAttestorChange) (event.Subscription, error) {
+
+	logs, sub, err := _DIAAttestationOracle.contract.WatchLogs(opts, "AttestorChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIAAttestationOracleAttestorChange)
+				if err := _DIAAttestationOracle.contract.UnpackLog(event, "AttestorChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAttestorChange is a log parse operation binding the contract event 0x83efcb9cc0dbacf1d19eeb49621e303bc96d13da027f577f96d5d839ec1c35b1.
+//
+// Solidity: event AttestorChange(address newAttestor)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) ParseAttestorChange(log types.Log) (*DIAAttestationOracleAttestorChange, error) {
+	event := new(DIAAttestationOracleAttestorChange)
+	if err := _DIAAttestationOracle.contract.UnpackLog(event, "AttestorChange", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// DIAAttestationOracleOracleUpdateIterator is returned from FilterOracleUpdate and is used to iterate over the raw logs and unpacked data for OracleUpdate events raised by the DIAAttestationOracle contract.
+type DIAAttestationOracleOracleUpdateIterator struct {
+	Event *DIAAttestationOracleOracleUpdate // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIAAttestationOracleOracleUpdateIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIAAttestationOracleOracleUpdate)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIAAttestationOracleOracleUpdate)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIAAttestationOracleOracleUpdateIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIAAttestationOracleOracleUpdateIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIAAttestationOracleOracleUpdate represents a OracleUpdate event raised by the DIAAttestationOracle contract.
+type DIAAttestationOracleOracleUpdate struct {
+	Key       string
+	Value     []byte
+	Timestamp *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterOracleUpdate is a free log retrieval operation binding the contract event 0x7f7bf6fa44dbea06f895b4717d2458e22e36d3498862ffda71704556494fcf71.
+//
+// Solidity: event OracleUpdate(string key, bytes value, uint128 timestamp)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) FilterOracleUpdate(opts *bind.FilterOpts) (*DIAAttestationOracleOracleUpdateIterator, error) {
+
+	logs, sub, err := _DIAAttestationOracle.contract.FilterLogs(opts, "OracleUpdate")
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAttestationOracleOracleUpdateIterator{contract: _DIAAttestationOracle.contract, event: "OracleUpdate", logs: logs, sub: sub}, nil
+}
+
+// WatchOracleUpdate is a free log subscription operation binding the contract event 0x7f7bf6fa44dbea06f895b4717d2458e22e36d3498862ffda71704556494fcf71.
+//
+// Solidity: event OracleUpdate(string key, bytes value, uint128 timestamp)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) WatchOracleUpdate(opts *bind.WatchOpts, sink chan<- *DIAAttestationOracleOracleUpdate) (event.Subscription, error) {
+
+	logs, sub, err := _DIAAttestationOracle.contract.WatchLogs(opts, "OracleUpdate")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIAAttestationOracleOracleUpdate)
+				if err := _DIAAttestationOracle.contract.UnpackLog(event, "OracleUpdate", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOracleUpdate is a log parse operation binding the contract event 0x7f7bf6fa44dbea06f895b4717d2458e22e36d3498862ffda71704556494fcf71.
+//
+// Solidity: event OracleUpdate(string key, bytes value, uint128 timestamp)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) ParseOracleUpdate(log types.Log) (*DIAAttestationOracleOracleUpdate, error) {
+	event := new(DIAAttestationOracleOracleUpdate)
+	if err := _DIAAttestationOracle.contract.UnpackLog(event, "OracleUpdate", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// DIAAttestationOracleUpdaterAddressChangeIterator is returned from FilterUpdaterAddressChange and is used to iterate over the raw logs and unpacked data for UpdaterAddressChange events raised by the DIAAttestationOracle contract.
+type DIAAttestationOracleUpdaterAddressChangeIterator struct {
+	Event *DIAAttestationOracleUpdaterAddressChange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIAAttestationOracleUpdaterAddressChangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIAAttestationOracleUpdaterAddressChange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIAAttestationOracleUpdaterAddressChange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIAAttestationOracleUpdaterAddressChangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIAAttestationOracleUpdaterAddressChangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIAAttestationOracleUpdaterAddressChange represents a UpdaterAddressChange event raised by the DIAAttestationOracle contract.
+type DIAAttestationOracleUpdaterAddressChange struct {
+	NewUpdater common.Address
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterUpdaterAddressChange is a free log retrieval operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) FilterUpdaterAddressChange(opts *bind.FilterOpts) (*DIAAttestationOracleUpdaterAddressChangeIterator, error) {
+
+	logs, sub, err := _DIAAttestationOracle.contract.FilterLogs(opts, "UpdaterAddressChange")
+	if err != nil {
+		return nil, err
+	}
+	return &DIAAttestationOracleUpdaterAddressChangeIterator{contract: _DIAAttestationOracle.contract, event: "UpdaterAddressChange", logs: logs, sub: sub}, nil
+}
+
+// WatchUpdaterAddressChange is a free log subscription operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) WatchUpdaterAddressChange(opts *bind.WatchOpts, sink chan<- *DIAAttestationOracleUpdaterAddressChange) (event.Subscription, error) {
+
+	logs, sub, err := _DIAAttestationOracle.contract.WatchLogs(opts, "UpdaterAddressChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIAAttestationOracleUpdaterAddressChange)
+				if err := _DIAAttestationOracle.contract.UnpackLog(event, "UpdaterAddressChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUpdaterAddressChange is a log parse operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIAAttestationOracle *DIAAttestationOracleFilterer) ParseUpdaterAddressChange(log types.Log) (*DIAAttestationOracleUpdaterAddressChange, error) {
+	event := new(DIAAttestationOracleUpdaterAddressChange)
+	if err := _DIAAttestationOracle.contract.UnpackLog(event, "UpdaterAddressChange", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,40 @@
+package diaAttestationOracle
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Digest returns the hash which the attestor signs to write @value under @key with @timestamp
+// into the contract at @contract. It equals digest() of the contract: the eth_sign hash of
+// keccak256(abi.encode(contract, key, value, timestamp)).
+func Digest(contract common.Address, key string, value []byte, timestamp *big.Int) ([]byte, error) {
+	var args abi.Arguments
+	for _, t := range []string{"address", "string", "bytes", "uint128"} {
+		typ, err := abi.NewType(t, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	encoded, err := args.Pack(contract, key, value, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return accounts.TextHash(crypto.Keccak256(encoded)), nil
+}
+
+// Sign returns the signature of the attestation of @value under @key with @timestamp for the
+// contract at @contract, made with the key of the @attestor.
+func Sign(contract common.Address, key string, value []byte, timestamp *big.Int, attestor *ecdsa.PrivateKey) ([]byte, error) {
+	digest, err := Digest(contract, key, value, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(digest, attestor)
+}
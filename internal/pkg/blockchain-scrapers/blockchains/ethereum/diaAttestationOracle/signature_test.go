@@ -0,0 +1,42 @@
+package diaAttestationOracle
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDigest(t *testing.T) {
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	digest, err := Digest(contract, "SUPPLY/DIA", []byte{1, 2}, big.NewInt(1700000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "cc6ebc5146d36e6fee513d257987f1c5898e1cbdf5f7ba96b1296f3a61eacfa4"
+	if hex.EncodeToString(digest) != expected {
+		t.Errorf("expected digest %s, got %x", expected, digest)
+	}
+}
+
+func TestSign(t *testing.T) {
+	attestor, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	signature, err := Sign(contract, "SUPPLY/DIA", []byte{1, 2}, big.NewInt(1700000000), attestor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, _ := Digest(contract, "SUPPLY/DIA", []byte{1, 2}, big.NewInt(1700000000))
+	public, err := crypto.SigToPub(digest, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*public) != crypto.PubkeyToAddress(attestor.PublicKey) {
+		t.Error("signature doesn't recover to the attestor")
+	}
+}
@@ -0,0 +1,17 @@
+package diaOracleServiceV2
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// PackSetValue returns the calldata of setValue(@key, @value, @timestamp), e.g. for fee estimations.
+func PackSetValue(key string, value *big.Int, timestamp *big.Int) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(DIAOracleV2ABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack("setValue", key, value, timestamp)
+}
@@ -0,0 +1,148 @@
+package diaOracleServiceV2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// EIP-1967 storage slots of the implementation and the beacon of a proxy.
+	implementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	beaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	// beaconImplementation is the selector of implementation() on a beacon.
+	beaconImplementation = common.Hex2Bytes("5c60da1b")
+	// requiredMethods are called by the oracle feeders and must be present in the implementation.
+	requiredMethods = []string{"setValue", "getValue"}
+)
+
+// ChainReader is the part of ethclient.Client needed to resolve proxies.
+type ChainReader interface {
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+}
+
+// ResolveImplementation returns the implementation of @address if it is an EIP-1967 proxy,
+// either directly or through a beacon. Otherwise it returns @address itself and false.
+func ResolveImplementation(ctx context.Context, client ChainReader, address common.Address) (common.Address, bool, error) {
+	slot, err := client.StorageAt(ctx, address, implementationSlot, nil)
+	if err != nil {
+		return common.Address{}, false, err
+	}
+	if implementation := common.BytesToAddress(slot); implementation != (common.Address{}) {
+		return implementation, true, nil
+	}
+
+	slot, err = client.StorageAt(ctx, address, beaconSlot, nil)
+	if err != nil {
+		return common.Address{}, false, err
+	}
+	beacon := common.BytesToAddress(slot)
+	if beacon == (common.Address{}) {
+		return address, false, nil
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &beacon, Data: beaconImplementation}, nil)
+	if err != nil {
+		return common.Address{}, false, fmt.Errorf("call implementation() on beacon %s: %v", beacon.Hex(), err)
+	}
+	if len(result) < 32 {
+		return common.Address{}, false, fmt.Errorf("beacon %s returned no implementation", beacon.Hex())
+	}
+	return common.BytesToAddress(result[:32]), true, nil
+}
+
+// CheckCompatibility returns an error if the deployed bytecode @code doesn't dispatch all
+// methods of DIAOracleV2 used by the feeders.
+func CheckCompatibility(code []byte) error {
+	if len(code) == 0 {
+		return errors.New("no contract code")
+	}
+	parsed, err := abi.JSON(strings.NewReader(DIAOracleV2ABI))
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, name := range requiredMethods {
+		// The dispatcher compares the calldata against each selector pushed with PUSH4.
+		push := append([]byte{0x63}, parsed.Methods[name].ID...)
+		if !bytes.Contains(code, push) {
+			missing = append(missing, parsed.Methods[name].Sig)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("contract doesn't implement %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ProxyWatcher tracks the implementation behind an oracle address, so that feeders stop
+// writing when a proxy is upgraded to an incompatible implementation.
+type ProxyWatcher struct {
+	client  ChainReader
+	address common.Address
+	// implementation is the last implementation which passed the compatibility check.
+	implementation common.Address
+}
+
+// NewProxyWatcher resolves and checks the implementation behind @address.
+func NewProxyWatcher(client ChainReader, address common.Address) (*ProxyWatcher, error) {
+	w := &ProxyWatcher{client: client, address: address}
+	implementation, isProxy, err := w.resolveAndCheck()
+	if err != nil {
+		return nil, err
+	}
+	w.implementation = implementation
+	if isProxy {
+		log.Infof("oracle %s is an EIP-1967 proxy of %s", address.Hex(), implementation.Hex())
+	}
+	return w, nil
+}
+
+func (w *ProxyWatcher) resolveAndCheck() (common.Address, bool, error) {
+	ctx := context.Background()
+	implementation, isProxy, err := ResolveImplementation(ctx, w.client, w.address)
+	if err != nil {
+		return implementation, isProxy, err
+	}
+	code, err := w.client.CodeAt(ctx, implementation, nil)
+	if err != nil {
+		return implementation, isProxy, err
+	}
+	err = CheckCompatibility(code)
+	if err != nil {
+		return implementation, isProxy, fmt.Errorf("implementation %s of oracle %s is incompatible: %v", implementation.Hex(), w.address.Hex(), err)
+	}
+	return implementation, isProxy, nil
+}
+
+// Check resolves the implementation again. If it changed, the new implementation is checked
+// for compatibility. An error means that the oracle must not be written to.
+func (w *ProxyWatcher) Check() error {
+	implementation, _, err := ResolveImplementation(context.Background(), w.client, w.address)
+	if err != nil {
+		return err
+	}
+	if implementation == w.implementation {
+		return nil
+	}
+	log.Warnf("!!! IMPLEMENTATION OF ORACLE %s CHANGED FROM %s TO %s !!!", w.address.Hex(), w.implementation.Hex(), implementation.Hex())
+	implementation, _, err = w.resolveAndCheck()
+	if err != nil {
+		return err
+	}
+	w.implementation = implementation
+	log.Warnf("new implementation %s of oracle %s is compatible, resuming updates", implementation.Hex(), w.address.Hex())
+	return nil
+}
+
+// Address returns the address of the watched oracle.
+func (w *ProxyWatcher) Address() common.Address {
+	return w.address
+}
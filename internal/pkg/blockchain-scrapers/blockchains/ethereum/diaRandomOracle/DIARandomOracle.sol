@@ -0,0 +1,47 @@
+pragma solidity 0.7.4;
+
+// DIARandomOracle stores rounds of a drand randomness beacon. The feeder verifies the BLS
+// signature of each round against the public key of the drand network before submitting it,
+// consumers can verify it again with the BLS12-381 precompiles where available. The contract
+// only checks that the randomness is the SHA-256 hash of the signature, as drand derives it.
+contract DIARandomOracle {
+    struct Random {
+        bytes32 randomness;
+        bytes signature;
+    }
+
+    mapping (uint256 => Random) values;
+    uint256 lastRound;
+    address oracleUpdater;
+
+    event OracleUpdate(uint256 round, bytes32 randomness, bytes signature);
+    event UpdaterAddressChange(address newUpdater);
+
+    constructor() {
+        oracleUpdater = msg.sender;
+    }
+
+    function setRandomValue(uint256 round, bytes32 randomness, bytes memory signature) public {
+        require(msg.sender == oracleUpdater);
+        require(round > lastRound, "Stale round");
+        require(sha256(signature) == randomness, "Invalid randomness");
+        values[round] = Random(randomness, signature);
+        lastRound = round;
+        emit OracleUpdate(round, randomness, signature);
+    }
+
+    function getRandomValue(uint256 round) external view returns (bytes32, bytes memory) {
+        Random memory r = values[round];
+        return (r.randomness, r.signature);
+    }
+
+    function getLastRound() external view returns (uint256) {
+        return lastRound;
+    }
+
+    function updateOracleUpdaterAddress(address newOracleUpdaterAddress) public {
+        require(msg.sender == oracleUpdater);
+        oracleUpdater = newOracleUpdaterAddress;
+        emit UpdaterAddressChange(newOracleUpdaterAddress);
+    }
+}
@@ -0,0 +1,17 @@
+package diaRandomOracle
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// PackSetRandomValue returns the calldata of setRandomValue(@round, @randomness, @signature), e.g. for fee estimations.
+func PackSetRandomValue(round *big.Int, randomness [32]byte, signature []byte) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(DIARandomOracleABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack("setRandomValue", round, randomness, signature)
+}
@@ -0,0 +1,547 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package diaRandomOracle
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// DIARandomOracleABI is the input ABI used to generate the binding from.
+const DIARandomOracleABI = "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"round\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"randomness\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"name\":\"OracleUpdate\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"newUpdater\",\"type\":\"address\"}],\"name\":\"UpdaterAddressChange\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"getLastRound\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"round\",\"type\":\"uint256\"}],\"name\":\"getRandomValue\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"round\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"randomness\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"name\":\"setRandomValue\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newOracleUpdaterAddress\",\"type\":\"address\"}],\"name\":\"updateOracleUpdaterAddress\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+
+// DIARandomOracle is an auto generated Go binding around an Ethereum contract.
+type DIARandomOracle struct {
+	DIARandomOracleCaller     // Read-only binding to the contract
+	DIARandomOracleTransactor // Write-only binding to the contract
+	DIARandomOracleFilterer   // Log filterer for contract events
+}
+
+// DIARandomOracleCaller is an auto generated read-only Go binding around an Ethereum contract.
+type DIARandomOracleCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIARandomOracleTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type DIARandomOracleTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIARandomOracleFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type DIARandomOracleFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DIARandomOracleSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type DIARandomOracleSession struct {
+	Contract     *DIARandomOracle  // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// DIARandomOracleCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type DIARandomOracleCallerSession struct {
+	Contract *DIARandomOracleCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts          // Call options to use throughout this session
+}
+
+// DIARandomOracleTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type DIARandomOracleTransactorSession struct {
+	Contract     *DIARandomOracleTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts          // Transaction auth options to use throughout this session
+}
+
+// DIARandomOracleRaw is an auto generated low-level Go binding around an Ethereum contract.
+type DIARandomOracleRaw struct {
+	Contract *DIARandomOracle // Generic contract binding to access the raw methods on
+}
+
+// DIARandomOracleCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type DIARandomOracleCallerRaw struct {
+	Contract *DIARandomOracleCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// DIARandomOracleTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type DIARandomOracleTransactorRaw struct {
+	Contract *DIARandomOracleTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewDIARandomOracle creates a new instance of DIARandomOracle, bound to a specific deployed contract.
+func NewDIARandomOracle(address common.Address, backend bind.ContractBackend) (*DIARandomOracle, error) {
+	contract, err := bindDIARandomOracle(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &DIARandomOracle{DIARandomOracleCaller: DIARandomOracleCaller{contract: contract}, DIARandomOracleTransactor: DIARandomOracleTransactor{contract: contract}, DIARandomOracleFilterer: DIARandomOracleFilterer{contract: contract}}, nil
+}
+
+// NewDIARandomOracleCaller creates a new read-only instance of DIARandomOracle, bound to a specific deployed contract.
+func NewDIARandomOracleCaller(address common.Address, caller bind.ContractCaller) (*DIARandomOracleCaller, error) {
+	contract, err := bindDIARandomOracle(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DIARandomOracleCaller{contract: contract}, nil
+}
+
+// NewDIARandomOracleTransactor creates a new write-only instance of DIARandomOracle, bound to a specific deployed contract.
+func NewDIARandomOracleTransactor(address common.Address, transactor bind.ContractTransactor) (*DIARandomOracleTransactor, error) {
+	contract, err := bindDIARandomOracle(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DIARandomOracleTransactor{contract: contract}, nil
+}
+
+// NewDIARandomOracleFilterer creates a new log filterer instance of DIARandomOracle, bound to a specific deployed contract.
+func NewDIARandomOracleFilterer(address common.Address, filterer bind.ContractFilterer) (*DIARandomOracleFilterer, error) {
+	contract, err := bindDIARandomOracle(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &DIARandomOracleFilterer{contract: contract}, nil
+}
+
+// bindDIARandomOracle binds a generic wrapper to an already deployed contract.
+func bindDIARandomOracle(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(DIARandomOracleABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_DIARandomOracle *DIARandomOracleRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _DIARandomOracle.Contract.DIARandomOracleCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_DIARandomOracle *DIARandomOracleRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.DIARandomOracleTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_DIARandomOracle *DIARandomOracleRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.DIARandomOracleTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_DIARandomOracle *DIARandomOracleCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _DIARandomOracle.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_DIARandomOracle *DIARandomOracleTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_DIARandomOracle *DIARandomOracleTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetLastRound is a free data retrieval call binding the contract method 0x4231a2c3.
+//
+// Solidity: function getLastRound() view returns(uint256)
+func (_DIARandomOracle *DIARandomOracleCaller) GetLastRound(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _DIARandomOracle.contract.Call(opts, &out, "getLastRound")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetLastRound is a free data retrieval call binding the contract method 0x4231a2c3.
+//
+// Solidity: function getLastRound() view returns(uint256)
+func (_DIARandomOracle *DIARandomOracleSession) GetLastRound() (*big.Int, error) {
+	return _DIARandomOracle.Contract.GetLastRound(&_DIARandomOracle.CallOpts)
+}
+
+// GetLastRound is a free data retrieval call binding the contract method 0x4231a2c3.
+//
+// Solidity: function getLastRound() view returns(uint256)
+func (_DIARandomOracle *DIARandomOracleCallerSession) GetLastRound() (*big.Int, error) {
+	return _DIARandomOracle.Contract.GetLastRound(&_DIARandomOracle.CallOpts)
+}
+
+// GetRandomValue is a free data retrieval call binding the contract method 0x25e41da1.
+//
+// Solidity: function getRandomValue(uint256 round) view returns(bytes32, bytes)
+func (_DIARandomOracle *DIARandomOracleCaller) GetRandomValue(opts *bind.CallOpts, round *big.Int) ([32]byte, []byte, error) {
+	var out []interface{}
+	err := _DIARandomOracle.contract.Call(opts, &out, "getRandomValue", round)
+
+	if err != nil {
+		return *new([32]byte), *new([]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	out1 := *abi.ConvertType(out[1], new([]byte)).(*[]byte)
+
+	return out0, out1, err
+
+}
+
+// GetRandomValue is a free data retrieval call binding the contract method 0x25e41da1.
+//
+// Solidity: function getRandomValue(uint256 round) view returns(bytes32, bytes)
+func (_DIARandomOracle *DIARandomOracleSession) GetRandomValue(round *big.Int) ([32]byte, []byte, error) {
+	return _DIARandomOracle.Contract.GetRandomValue(&_DIARandomOracle.CallOpts, round)
+}
+
+// GetRandomValue is a free data retrieval call binding the contract method 0x25e41da1.
+//
+// Solidity: function getRandomValue(uint256 round) view returns(bytes32, bytes)
+func (_DIARandomOracle *DIARandomOracleCallerSession) GetRandomValue(round *big.Int) ([32]byte, []byte, error) {
+	return _DIARandomOracle.Contract.GetRandomValue(&_DIARandomOracle.CallOpts, round)
+}
+
+// SetRandomValue is a paid mutator transaction binding the contract method 0xd8272098.
+//
+// Solidity: function setRandomValue(uint256 round, bytes32 randomness, bytes signature) returns()
+func (_DIARandomOracle *DIARandomOracleTransactor) SetRandomValue(opts *bind.TransactOpts, round *big.Int, randomness [32]byte, signature []byte) (*types.Transaction, error) {
+	return _DIARandomOracle.contract.Transact(opts, "setRandomValue", round, randomness, signature)
+}
+
+// SetRandomValue is a paid mutator transaction binding the contract method 0xd8272098.
+//
+// Solidity: function setRandomValue(uint256 round, bytes32 randomness, bytes signature) returns()
+func (_DIARandomOracle *DIARandomOracleSession) SetRandomValue(round *big.Int, randomness [32]byte, signature []byte) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.SetRandomValue(&_DIARandomOracle.TransactOpts, round, randomness, signature)
+}
+
+// SetRandomValue is a paid mutator transaction binding the contract method 0xd8272098.
+//
+// Solidity: function setRandomValue(uint256 round, bytes32 randomness, bytes signature) returns()
+func (_DIARandomOracle *DIARandomOracleTransactorSession) SetRandomValue(round *big.Int, randomness [32]byte, signature []byte) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.SetRandomValue(&_DIARandomOracle.TransactOpts, round, randomness, signature)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIARandomOracle *DIARandomOracleTransactor) UpdateOracleUpdaterAddress(opts *bind.TransactOpts, newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIARandomOracle.contract.Transact(opts, "updateOracleUpdaterAddress", newOracleUpdaterAddress)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIARandomOracle *DIARandomOracleSession) UpdateOracleUpdaterAddress(newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.UpdateOracleUpdaterAddress(&_DIARandomOracle.TransactOpts, newOracleUpdaterAddress)
+}
+
+// UpdateOracleUpdaterAddress is a paid mutator transaction binding the contract method 0x6aa45efc.
+//
+// Solidity: function updateOracleUpdaterAddress(address newOracleUpdaterAddress) returns()
+func (_DIARandomOracle *DIARandomOracleTransactorSession) UpdateOracleUpdaterAddress(newOracleUpdaterAddress common.Address) (*types.Transaction, error) {
+	return _DIARandomOracle.Contract.UpdateOracleUpdaterAddress(&_DIARandomOracle.TransactOpts, newOracleUpdaterAddress)
+}
+
+// DIARandomOracleOracleUpdateIterator is returned from FilterOracleUpdate and is used to iterate over the raw logs and unpacked data for OracleUpdate events raised by the DIARandomOracle contract.
+type DIARandomOracleOracleUpdateIterator struct {
+	Event *DIARandomOracleOracleUpdate // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIARandomOracleOracleUpdateIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIARandomOracleOracleUpdate)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIARandomOracleOracleUpdate)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIARandomOracleOracleUpdateIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIARandomOracleOracleUpdateIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIARandomOracleOracleUpdate represents a OracleUpdate event raised by the DIARandomOracle contract.
+type DIARandomOracleOracleUpdate struct {
+	Round      *big.Int
+	Randomness [32]byte
+	Signature  []byte
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterOracleUpdate is a free log retrieval operation binding the contract event 0xcd7704703fcf67e5d7890e8f9c1ff39d58d2fcbae8957d5f89865db1d1e91021.
+//
+// Solidity: event OracleUpdate(uint256 round, bytes32 randomness, bytes signature)
+func (_DIARandomOracle *DIARandomOracleFilterer) FilterOracleUpdate(opts *bind.FilterOpts) (*DIARandomOracleOracleUpdateIterator, error) {
+
+	logs, sub, err := _DIARandomOracle.contract.FilterLogs(opts, "OracleUpdate")
+	if err != nil {
+		return nil, err
+	}
+	return &DIARandomOracleOracleUpdateIterator{contract: _DIARandomOracle.contract, event: "OracleUpdate", logs: logs, sub: sub}, nil
+}
+
+// WatchOracleUpdate is a free log subscription operation binding the contract event 0xcd7704703fcf67e5d7890e8f9c1ff39d58d2fcbae8957d5f89865db1d1e91021.
+//
+// Solidity: event OracleUpdate(uint256 round, bytes32 randomness, bytes signature)
+func (_DIARandomOracle *DIARandomOracleFilterer) WatchOracleUpdate(opts *bind.WatchOpts, sink chan<- *DIARandomOracleOracleUpdate) (event.Subscription, error) {
+
+	logs, sub, err := _DIARandomOracle.contract.WatchLogs(opts, "OracleUpdate")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIARandomOracleOracleUpdate)
+				if err := _DIARandomOracle.contract.UnpackLog(event, "OracleUpdate", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOracleUpdate is a log parse operation binding the contract event 0xcd7704703fcf67e5d7890e8f9c1ff39d58d2fcbae8957d5f89865db1d1e91021.
+//
+// Solidity: event OracleUpdate(uint256 round, bytes32 randomness, bytes signature)
+func (_DIARandomOracle *DIARandomOracleFilterer) ParseOracleUpdate(log types.Log) (*DIARandomOracleOracleUpdate, error) {
+	event := new(DIARandomOracleOracleUpdate)
+	if err := _DIARandomOracle.contract.UnpackLog(event, "OracleUpdate", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// DIARandomOracleUpdaterAddressChangeIterator is returned from FilterUpdaterAddressChange and is used to iterate over the raw logs and unpacked data for UpdaterAddressChange events raised by the DIARandomOracle contract.
+type DIARandomOracleUpdaterAddressChangeIterator struct {
+	Event *DIARandomOracleUpdaterAddressChange // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DIARandomOracleUpdaterAddressChangeIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DIARandomOracleUpdaterAddressChange)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DIARandomOracleUpdaterAddressChange)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DIARandomOracleUpdaterAddressChangeIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DIARandomOracleUpdaterAddressChangeIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DIARandomOracleUpdaterAddressChange represents a UpdaterAddressChange event raised by the DIARandomOracle contract.
+type DIARandomOracleUpdaterAddressChange struct {
+	NewUpdater common.Address
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterUpdaterAddressChange is a free log retrieval operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIARandomOracle *DIARandomOracleFilterer) FilterUpdaterAddressChange(opts *bind.FilterOpts) (*DIARandomOracleUpdaterAddressChangeIterator, error) {
+
+	logs, sub, err := _DIARandomOracle.contract.FilterLogs(opts, "UpdaterAddressChange")
+	if err != nil {
+		return nil, err
+	}
+	return &DIARandomOracleUpdaterAddressChangeIterator{contract: _DIARandomOracle.contract, event: "UpdaterAddressChange", logs: logs, sub: sub}, nil
+}
+
+// WatchUpdaterAddressChange is a free log subscription operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIARandomOracle *DIARandomOracleFilterer) WatchUpdaterAddressChange(opts *bind.WatchOpts, sink chan<- *DIARandomOracleUpdaterAddressChange) (event.Subscription, error) {
+
+	logs, sub, err := _DIARandomOracle.contract.WatchLogs(opts, "UpdaterAddressChange")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DIARandomOracleUpdaterAddressChange)
+				if err := _DIARandomOracle.contract.UnpackLog(event, "UpdaterAddressChange", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUpdaterAddressChange is a log parse operation binding the contract event 0x121e958a4cadf7f8dadefa22cc019700365240223668418faebed197da07089f.
+//
+// Solidity: event UpdaterAddressChange(address newUpdater)
+func (_DIARandomOracle *DIARandomOracleFilterer) ParseUpdaterAddressChange(log types.Log) (*DIARandomOracleUpdaterAddressChange, error) {
+	event := new(DIARandomOracleUpdaterAddressChange)
+	if err := _DIARandomOracle.contract.UnpackLog(event, "UpdaterAddressChange", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,248 @@
+package feeEstimation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Chain types with different fee models.
+const (
+	// ChainL1 charges gas only.
+	ChainL1 = "l1"
+	// ChainOPStack (Optimism, Base) charges an L1 data fee on top of the L2 gas.
+	ChainOPStack = "opstack"
+	// ChainArbitrum charges the L1 data fee as additional L2 gas.
+	ChainArbitrum = "arbitrum"
+)
+
+// DefaultGasMarginPercent is the default safety margin added to estimated gas.
+const DefaultGasMarginPercent = 20
+
+// DefaultGasLimit adds DefaultGasMarginPercent to estimated gas.
+var DefaultGasLimit = GasLimit{MarginPercent: DefaultGasMarginPercent}
+
+// ErrEstimateGas is returned by Estimate if the node can't estimate the gas of a transaction,
+// e.g. because it would revert. Callers should skip the update rather than send it blindly.
+var ErrEstimateGas = errors.New("gas estimation failed")
+
+var (
+	// gasPriceOracle is the OP-stack predeploy computing the L1 data fee.
+	gasPriceOracle    = common.HexToAddress("0x420000000000000000000000000000000000000F")
+	gasPriceOracleABI = `[{"inputs":[{"internalType":"bytes","name":"_data","type":"bytes"}],"name":"getL1Fee","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+	// nodeInterface is the virtual Arbitrum contract estimating the L1 component of a transaction.
+	nodeInterface    = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+	nodeInterfaceABI = `[{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"bool","name":"contractCreation","type":"bool"},{"internalType":"bytes","name":"data","type":"bytes"}],"name":"gasEstimateL1Component","outputs":[{"internalType":"uint64","name":"gasEstimateForL1","type":"uint64"},{"internalType":"uint256","name":"baseFee","type":"uint256"},{"internalType":"uint256","name":"l1BaseFeeEstimate","type":"uint256"}],"stateMutability":"payable","type":"function"}]`
+)
+
+// ChainType returns the fee model of the chain with @chainID.
+func ChainType(chainID int64) string {
+	switch chainID {
+	// Optimism, Base and their testnets.
+	case 10, 420, 11155420, 8453, 84531, 84532:
+		return ChainOPStack
+	// Arbitrum One, Arbitrum Nova and their testnets.
+	case 42161, 42170, 421613, 421614:
+		return ChainArbitrum
+	}
+	return ChainL1
+}
+
+// Fee is the estimated cost of a transaction in wei.
+type Fee struct {
+	GasPrice *big.Int
+	GasLimit uint64
+	// ExecutionFee is the fee of the estimated L2 gas, without L1 component on Arbitrum.
+	ExecutionFee *big.Int
+	// L1Fee is the fee for posting the transaction data to L1.
+	L1Fee *big.Int
+	// MaxFee is the fee if the whole gas limit is used.
+	MaxFee *big.Int
+}
+
+// Total returns the expected fee of the transaction.
+func (f Fee) Total() *big.Int {
+	return new(big.Int).Add(f.ExecutionFee, f.L1Fee)
+}
+
+func (f Fee) String() string {
+	return fmt.Sprintf("total %s wei (execution %s, l1 data %s, max %s) at gas price %s", f.Total(), f.ExecutionFee, f.L1Fee, f.MaxFee, f.GasPrice)
+}
+
+// GasLimit configures the gas limit of transactions.
+type GasLimit struct {
+	// MarginPercent is added to the estimated gas.
+	MarginPercent uint64
+	// Override is used as gas limit instead of the estimation if non-zero, for chains on which
+	// the estimation is unreliable.
+	Override uint64
+}
+
+// For returns the gas limit of a transaction with @estimated gas.
+func (g GasLimit) For(estimated uint64) uint64 {
+	if g.Override > 0 {
+		return g.Override
+	}
+	return estimated * (100 + g.MarginPercent) / 100
+}
+
+// Transact sends the transaction which @call makes with @opts and the gas limit for the gas
+// estimated by the node. @call must pass the options it gets to a method of a contract binding.
+// Failed estimations are returned as ErrEstimateGas.
+func (g GasLimit) Transact(opts *bind.TransactOpts, call func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	send := *opts
+	if g.Override > 0 {
+		send.GasLimit = g.Override
+		return call(&send)
+	}
+	// With no gas limit, the binding estimates the gas of the transaction, which isn't sent.
+	estimate := *opts
+	estimate.GasLimit = 0
+	estimate.NoSend = true
+	tx, err := call(&estimate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEstimateGas, err)
+	}
+	send.GasLimit = g.For(tx.Gas())
+	return call(&send)
+}
+
+// Estimator estimates the full fee of transactions, including the L1 data fee on rollups
+// which SuggestGasPrice doesn't reflect.
+type Estimator struct {
+	client    *ethclient.Client
+	chainType string
+	// maxFee caps the fee of a single transaction, nil for no cap.
+	maxFee   *big.Int
+	gasLimit GasLimit
+	gpo      abi.ABI
+	ni       abi.ABI
+}
+
+// NewEstimator returns an estimator for a chain of type @chainType. A non-nil @maxFee is
+// enforced by CheckFee. Gas limits are derived from the estimated gas by @gasLimit.
+func NewEstimator(client *ethclient.Client, chainType string, maxFee *big.Int, gasLimit GasLimit) (*Estimator, error) {
+	if chainType != ChainL1 && chainType != ChainOPStack && chainType != ChainArbitrum {
+		return nil, fmt.Errorf("unknown chain type %s", chainType)
+	}
+	gpo, err := abi.JSON(strings.NewReader(gasPriceOracleABI))
+	if err != nil {
+		return nil, err
+	}
+	ni, err := abi.JSON(strings.NewReader(nodeInterfaceABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Estimator{client: client, chainType: chainType, maxFee: maxFee, gasLimit: gasLimit, gpo: gpo, ni: ni}, nil
+}
+
+// ChainType returns the fee model used by the estimator.
+func (e *Estimator) ChainType() string {
+	return e.chainType
+}
+
+// Estimate returns the fee of a call of @to with @data from @from at @gasPrice, including the
+// gas limit to send it with. On Arbitrum, the gas limit is raised if it doesn't cover the L1
+// component. Failed estimations are returned as ErrEstimateGas.
+func (e *Estimator) Estimate(ctx context.Context, from common.Address, to common.Address, data []byte, gasPrice *big.Int) (Fee, error) {
+	gas, err := e.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, GasPrice: gasPrice, Data: data})
+	if err != nil {
+		return Fee{}, fmt.Errorf("%w: %v", ErrEstimateGas, err)
+	}
+	fee := Fee{
+		GasPrice: gasPrice,
+		GasLimit: e.gasLimit.For(gas),
+		L1Fee:    big.NewInt(0),
+	}
+
+	switch e.chainType {
+	case ChainOPStack:
+		fee.L1Fee, err = e.opStackL1Fee(ctx, from, to, data, fee.GasLimit, gasPrice)
+		if err != nil {
+			return Fee{}, err
+		}
+	case ChainArbitrum:
+		l1Gas, err := e.arbitrumL1Gas(ctx, to, data)
+		if err != nil {
+			return Fee{}, err
+		}
+		// The estimated gas already includes the L1 component.
+		if l1Gas > gas {
+			l1Gas = gas
+		}
+		fee.L1Fee = new(big.Int).Mul(new(big.Int).SetUint64(l1Gas), gasPrice)
+		gas -= l1Gas
+		// The L1 component varies with the L1 base fee, so leave some headroom.
+		if required := (gas + l1Gas) * 6 / 5; fee.GasLimit < required {
+			fee.GasLimit = required
+		}
+	}
+
+	fee.ExecutionFee = new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+	fee.MaxFee = new(big.Int).Mul(new(big.Int).SetUint64(fee.GasLimit), gasPrice)
+	if e.chainType == ChainOPStack {
+		fee.MaxFee.Add(fee.MaxFee, fee.L1Fee)
+	}
+	return fee, nil
+}
+
+// CheckFee returns an error if the maximal fee of @fee exceeds the configured cap.
+func (e *Estimator) CheckFee(fee Fee) error {
+	if e.maxFee != nil && fee.MaxFee.Cmp(e.maxFee) > 0 {
+		return fmt.Errorf("maximal fee %s wei exceeds cap of %s wei", fee.MaxFee, e.maxFee)
+	}
+	return nil
+}
+
+// opStackL1Fee asks the GasPriceOracle predeploy for the L1 data fee of the transaction.
+func (e *Estimator) opStackL1Fee(ctx context.Context, from common.Address, to common.Address, data []byte, gasLimit uint64, gasPrice *big.Int) (*big.Int, error) {
+	nonce, err := e.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	// The oracle adds the size of a signature to the unsigned transaction.
+	unsigned, err := rlp.EncodeToBytes(types.NewTransaction(nonce, to, big.NewInt(0), gasLimit, gasPrice, data))
+	if err != nil {
+		return nil, err
+	}
+	input, err := e.gpo.Pack("getL1Fee", unsigned)
+	if err != nil {
+		return nil, err
+	}
+	output, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &gasPriceOracle, Data: input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getL1Fee: %v", err)
+	}
+	result, err := e.gpo.Unpack("getL1Fee", output)
+	if err != nil {
+		return nil, err
+	}
+	return result[0].(*big.Int), nil
+}
+
+// arbitrumL1Gas asks the NodeInterface for the L2 gas charged for posting the call data to L1.
+func (e *Estimator) arbitrumL1Gas(ctx context.Context, to common.Address, data []byte) (uint64, error) {
+	input, err := e.ni.Pack("gasEstimateL1Component", to, false, data)
+	if err != nil {
+		return 0, err
+	}
+	output, err := e.client.CallContract(ctx, ethereum.CallMsg{To: &nodeInterface, Data: input}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("gasEstimateL1Component: %v", err)
+	}
+	result, err := e.ni.Unpack("gasEstimateL1Component", output)
+	if err != nil {
+		return 0, err
+	}
+	return result[0].(uint64), nil
+}
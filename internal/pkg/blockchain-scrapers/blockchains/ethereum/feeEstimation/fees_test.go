@@ -0,0 +1,59 @@
+package feeEstimation
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestGasLimitFor(t *testing.T) {
+	cases := []struct {
+		gasLimit  GasLimit
+		estimated uint64
+		expected  uint64
+	}{
+		{GasLimit{}, 50000, 50000},
+		{GasLimit{MarginPercent: DefaultGasMarginPercent}, 50000, 60000},
+		{GasLimit{MarginPercent: 15}, 33333, 38332},
+		{GasLimit{MarginPercent: 20, Override: 1000725}, 50000, 1000725},
+	}
+	for i, c := range cases {
+		result := c.gasLimit.For(c.estimated)
+		if result != c.expected {
+			t.Errorf("case %d: expected %d, got %d", i, c.expected, result)
+		}
+	}
+}
+
+func TestGasLimitTransact(t *testing.T) {
+	var sent []bind.TransactOpts
+	call := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		sent = append(sent, *opts)
+		gas := opts.GasLimit
+		if gas == 0 {
+			gas = 50000
+		}
+		return types.NewTransaction(0, common.Address{}, nil, gas, nil, nil), nil
+	}
+	tx, err := DefaultGasLimit.Transact(&bind.TransactOpts{GasLimit: 1000725}, call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 2 || sent[0].GasLimit != 0 || !sent[0].NoSend || sent[1].NoSend {
+		t.Fatalf("expected an estimation and a transaction, got %+v", sent)
+	}
+	if tx.Gas() != 60000 {
+		t.Errorf("expected gas limit 60000, got %d", tx.Gas())
+	}
+
+	sent = nil
+	tx, err = GasLimit{Override: 1000725}.Transact(&bind.TransactOpts{}, call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || tx.Gas() != 1000725 {
+		t.Errorf("expected a transaction with gas limit 1000725, got %d calls and %d", len(sent), tx.Gas())
+	}
+}
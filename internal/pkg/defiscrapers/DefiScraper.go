@@ -133,6 +133,28 @@ func (s *DefiScraper) UpdateRates(defiType string) error {
 			}
 			helper = NewAAVEv2(s, protocol)
 		}
+	case "AAVEv3":
+		{
+
+			protocol = dia.DefiProtocol{
+				Name:                 "AAVEv3",
+				Address:              AAVEv3Markets[dia.ETHEREUM],
+				UnderlyingBlockchain: "Ethereum",
+				Token:                "",
+			}
+			helper = NewAAVEv3(s, protocol, AAVEv3Markets)
+		}
+	case "SPARK":
+		{
+
+			protocol = dia.DefiProtocol{
+				Name:                 "SPARK",
+				Address:              SparkMarkets[dia.ETHEREUM],
+				UnderlyingBlockchain: "Ethereum",
+				Token:                "",
+			}
+			helper = NewAAVEv3(s, protocol, SparkMarkets)
+		}
 	case "DDEX":
 		{
 
@@ -176,6 +198,17 @@ func (s *DefiScraper) UpdateRates(defiType string) error {
 			}
 			helper = NewCompound(s, protocol)
 		}
+	case "COMPOUNDv3":
+		{
+
+			protocol = dia.DefiProtocol{
+				Name:                 "COMPOUNDv3",
+				Address:              CompoundV3Markets[dia.ETHEREUM][0],
+				UnderlyingBlockchain: "Ethereum",
+				Token:                "",
+			}
+			helper = NewCompoundV3(s, protocol)
+		}
 	case "CREAM":
 		{
 
@@ -272,6 +305,14 @@ func (s *DefiScraper) UpdateState(defiType string) error {
 		{
 			helper = NewAAVEv2(s, protocol)
 		}
+	case "AAVEv3":
+		{
+			helper = NewAAVEv3(s, protocol, AAVEv3Markets)
+		}
+	case "SPARK":
+		{
+			helper = NewAAVEv3(s, protocol, SparkMarkets)
+		}
 	case "RAY":
 		{
 			helper = NewRAY(s, protocol)
@@ -284,6 +325,10 @@ func (s *DefiScraper) UpdateState(defiType string) error {
 		{
 			helper = NewCompound(s, protocol)
 		}
+	case "COMPOUNDv3":
+		{
+			helper = NewCompoundV3(s, protocol)
+		}
 	case "BZX":
 		{
 			helper = NewBZX(s, protocol)
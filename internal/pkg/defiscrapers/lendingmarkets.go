@@ -0,0 +1,20 @@
+package defiscrapers
+
+import (
+	"math"
+
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// dialBlockchain connects to the RPC node of @blockchain.
+func dialBlockchain(blockchain string) (*ethclient.Client, error) {
+	return ethclient.Dial(ethhelper.RPCNode(blockchain))
+}
+
+// compoundedAPY returns the APY in percent of a rate per second compounded every second.
+func compoundedAPY(ratePerSecond float64) float64 {
+	return (math.Pow(1+ratePerSecond, secondsPerYear) - 1) * 100
+}
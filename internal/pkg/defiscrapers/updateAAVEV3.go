@@ -0,0 +1,163 @@
+package defiscrapers
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// aaveV3DataProviderABI is the part of the ABI of the pool data provider of Aave v3 and its
+// forks which is needed for reading rates.
+const aaveV3DataProviderABI = `[
+{"inputs":[],"name":"getAllReservesTokens","outputs":[{"components":[{"internalType":"string","name":"symbol","type":"string"},{"internalType":"address","name":"tokenAddress","type":"address"}],"internalType":"struct IPoolDataProvider.TokenData[]","name":"","type":"tuple[]"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"address","name":"asset","type":"address"}],"name":"getReserveData","outputs":[{"internalType":"uint256","name":"unbacked","type":"uint256"},{"internalType":"uint256","name":"accruedToTreasuryScaled","type":"uint256"},{"internalType":"uint256","name":"totalAToken","type":"uint256"},{"internalType":"uint256","name":"totalStableDebt","type":"uint256"},{"internalType":"uint256","name":"totalVariableDebt","type":"uint256"},{"internalType":"uint256","name":"liquidityRate","type":"uint256"},{"internalType":"uint256","name":"variableBorrowRate","type":"uint256"},{"internalType":"uint256","name":"stableBorrowRate","type":"uint256"},{"internalType":"uint256","name":"averageStableBorrowRate","type":"uint256"},{"internalType":"uint256","name":"liquidityIndex","type":"uint256"},{"internalType":"uint256","name":"variableBorrowIndex","type":"uint256"},{"internalType":"uint40","name":"lastUpdateTimestamp","type":"uint40"}],"stateMutability":"view","type":"function"}
+]`
+
+// aaveV3TokenData is a reserve of an Aave v3 market.
+type aaveV3TokenData struct {
+	Symbol       string
+	TokenAddress common.Address
+}
+
+// AAVEv3Markets are the pool data providers of the Aave v3 markets by blockchain.
+// https://docs.aave.com/developers/deployed-contracts/v3-mainnet
+var AAVEv3Markets = map[string]string{
+	dia.ETHEREUM:          "0x7B4EB56E7CD4b454BA8ff71E4518426369a138a3",
+	dia.POLYGON:           "0x69FA688f1Dc47d4B5d8029D5a35FB7a548310654",
+	dia.ARBITRUM:          "0x69FA688f1Dc47d4B5d8029D5a35FB7a548310654",
+	dia.OPTIMISM:          "0x69FA688f1Dc47d4B5d8029D5a35FB7a548310654",
+	dia.AVALANCHE:         "0x69FA688f1Dc47d4B5d8029D5a35FB7a548310654",
+	dia.BASE:              "0x2d8A3C5677189723C4cB8873CfC9C8976FDF38Ac",
+	dia.BINANCESMARTCHAIN: "0x41585C50524fb8c3899B43D7D797d9486AAc94DB",
+	dia.GNOSIS:            "0x501B4c19dd9C2e06E94dA7b6D5Ed4ddA013EC741",
+}
+
+// aaveV3MulticallBatchSize is the number of reserves whose data is read in one multicall.
+const aaveV3MulticallBatchSize = 100
+
+// SparkMarkets are the pool data providers of the Spark markets, a fork of Aave v3, by blockchain.
+// https://docs.spark.fi/developers/deployments
+var SparkMarkets = map[string]string{
+	dia.ETHEREUM: "0xFc21d6d146E6086B8359705C8b28512a983db0cb",
+}
+
+// AAVEv3Protocol scrapes the rates of all reserves of the markets of Aave v3 or one of its forks.
+type AAVEv3Protocol struct {
+	scraper  *DefiScraper
+	protocol dia.DefiProtocol
+	markets  map[string]string
+}
+
+func NewAAVEv3(scraper *DefiScraper, protocol dia.DefiProtocol, markets map[string]string) *AAVEv3Protocol {
+	return &AAVEv3Protocol{scraper: scraper, protocol: protocol, markets: markets}
+}
+
+// UpdateRate sends the supply APY, the variable borrow APY and the utilization of each reserve. A
+// market which can't be read is skipped.
+func (proto *AAVEv3Protocol) UpdateRate() error {
+	dataProviderABI, err := abi.JSON(strings.NewReader(aaveV3DataProviderABI))
+	if err != nil {
+		return err
+	}
+	for blockchain, dataProvider := range proto.markets {
+		rates, err := proto.fetchRates(blockchain, common.HexToAddress(dataProvider), dataProviderABI)
+		if err != nil {
+			log.Errorf("%s: fetch rates on %s: %v", proto.protocol.Name, blockchain, err)
+			continue
+		}
+		for _, rate := range rates {
+			log.Printf("writing %s rate of %s on %s: %v", proto.protocol.Name, rate.Asset, blockchain, rate)
+			proto.scraper.RateChannel() <- rate
+		}
+	}
+	return nil
+}
+
+func (proto *AAVEv3Protocol) fetchRates(blockchain string, dataProvider common.Address, dataProviderABI abi.ABI) ([]*dia.DefiRate, error) {
+	client, err := dialBlockchain(blockchain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	contract := bind.NewBoundContract(dataProvider, dataProviderABI, client, nil, nil)
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	var out []interface{}
+	err = contract.Call(opts, &out, "getAllReservesTokens")
+	if err != nil {
+		return nil, err
+	}
+	reserves := *abi.ConvertType(out[0], new([]aaveV3TokenData)).(*[]aaveV3TokenData)
+
+	// The data of all reserves is read in a multicall, so that the rates are of the same block.
+	calls := make([]ethhelper.Call, len(reserves))
+	for i, reserve := range reserves {
+		input, err := dataProviderABI.Pack("getReserveData", reserve.TokenAddress)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = ethhelper.Call{Target: dataProvider, CallData: input}
+	}
+	results, err := ethhelper.Multicall(opts.Context, client, calls, aaveV3MulticallBatchSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	var rates []*dia.DefiRate
+	for i, reserve := range reserves {
+		if !results[i].Success {
+			log.Errorf("%s: reserve data of %s on %s reverted", proto.protocol.Name, reserve.Symbol, blockchain)
+			continue
+		}
+		out, err = dataProviderABI.Unpack("getReserveData", results[i].ReturnData)
+		if err != nil {
+			return nil, err
+		}
+		totalAToken := *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+		totalStableDebt := *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+		totalVariableDebt := *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+		liquidityRate := *abi.ConvertType(out[5], new(*big.Int)).(**big.Int)
+		variableBorrowRate := *abi.ConvertType(out[6], new(*big.Int)).(**big.Int)
+		rates = append(rates, &dia.DefiRate{
+			Timestamp:     t,
+			LendingRate:   rayAPY(liquidityRate),
+			BorrowingRate: rayAPY(variableBorrowRate),
+			Utilization:   aaveV3Utilization(totalAToken, totalStableDebt, totalVariableDebt),
+			Asset:         reserve.Symbol,
+			Protocol:      proto.protocol.Name,
+			Blockchain:    blockchain,
+		})
+	}
+	return rates, nil
+}
+
+// aaveV3Utilization returns the share of the supplied @totalAToken of a reserve which is borrowed
+// at stable or variable rates.
+func aaveV3Utilization(totalAToken, totalStableDebt, totalVariableDebt *big.Int) float64 {
+	if totalAToken.Sign() <= 0 {
+		return 0
+	}
+	debt := new(big.Float).SetInt(new(big.Int).Add(totalStableDebt, totalVariableDebt))
+	utilization, _ := new(big.Float).Quo(debt, new(big.Float).SetInt(totalAToken)).Float64()
+	return utilization
+}
+
+// UpdateState is a no-op as only the rates of the markets are scraped.
+func (proto *AAVEv3Protocol) UpdateState() error {
+	return nil
+}
+
+// rayAPY returns the APY in percent of an annual rate in ray units, which Aave compounds every second.
+func rayAPY(rate *big.Int) float64 {
+	apr, _ := new(big.Float).Quo(new(big.Float).SetInt(rate), big.NewFloat(1e27)).Float64()
+	return compoundedAPY(apr / secondsPerYear)
+}
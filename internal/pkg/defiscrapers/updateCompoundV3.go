@@ -0,0 +1,267 @@
+package defiscrapers
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/defiscrapers/aave/contract"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// cometABI is the part of the ABI of Compound v3 markets (Comet) which is needed for reading rates.
+const cometABI = `[
+{"inputs":[],"name":"baseToken","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"getUtilization","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"uint256","name":"utilization","type":"uint256"}],"name":"getSupplyRate","outputs":[{"internalType":"uint64","name":"","type":"uint64"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"uint256","name":"utilization","type":"uint256"}],"name":"getBorrowRate","outputs":[{"internalType":"uint64","name":"","type":"uint64"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"numAssets","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"uint8","name":"i","type":"uint8"}],"name":"getAssetInfo","outputs":[{"components":[{"internalType":"uint8","name":"offset","type":"uint8"},{"internalType":"address","name":"asset","type":"address"},{"internalType":"address","name":"priceFeed","type":"address"},{"internalType":"uint64","name":"scale","type":"uint64"},{"internalType":"uint64","name":"borrowCollateralFactor","type":"uint64"},{"internalType":"uint64","name":"liquidateCollateralFactor","type":"uint64"},{"internalType":"uint64","name":"liquidationFactor","type":"uint64"},{"internalType":"uint128","name":"supplyCap","type":"uint128"}],"internalType":"struct CometCore.AssetInfo","name":"","type":"tuple"}],"stateMutability":"view","type":"function"}
+]`
+
+// cometAssetInfo is a collateral asset of a Compound v3 market.
+type cometAssetInfo struct {
+	Offset                    uint8
+	Asset                     common.Address
+	PriceFeed                 common.Address
+	Scale                     uint64
+	BorrowCollateralFactor    uint64
+	LiquidateCollateralFactor uint64
+	LiquidationFactor         uint64
+	SupplyCap                 *big.Int
+}
+
+// CompoundV3Markets are the Comet contracts of the Compound v3 markets by blockchain. Each market
+// lends a single base asset.
+// https://docs.compound.finance/#networks
+var CompoundV3Markets = map[string][]string{
+	dia.ETHEREUM: {
+		"0xc3d688B66703497DAA19211EEdff47f25384cdc3", // USDC
+		"0xA17581A9E3356d9A858b789D68B4d866e593aE94", // WETH
+		"0x3Afdc9BCA9213A35503b077a6072F3D0d5AB0840", // USDT
+		"0x3D0bb1ccaB520A66e607822fC55BC921738fAFE3", // wstETH
+	},
+	dia.POLYGON: {
+		"0xF25212E676D1F7F89Cd72fFEe66158f541246445", // USDC.e
+		"0xaeB318360f27748Acb200CE616E389A6C9409a07", // USDT
+	},
+	dia.ARBITRUM: {
+		"0x9c4ec768c28520B50860ea7a15bd7213a9fF58bf", // USDC
+		"0xA5EDBDD9646f8dFF606d7448e414884C7d905dCA", // USDC.e
+		"0x6f7D514bbD4aFf3BcD1140B7344b32f063dEe486", // WETH
+		"0xd98Be00b5D27fc98112BdE293e487f8D4cA57d07", // USDT
+	},
+	dia.BASE: {
+		"0xb125E6687d4313864e53df431d5425969c15Eb2F", // USDC
+		"0x46e6b214b524310239732D51387075E0e70970bf", // WETH
+	},
+	dia.OPTIMISM: {
+		"0x2e44e174f7D53F0212823acC11C01A11d58c5bCB", // USDC
+		"0x995E394b8B2437aC8Ce61Ee0bC610D617962B214", // USDT
+		"0xE36A30D249f7761327fd973001A32010b521b6Fd", // WETH
+	},
+}
+
+// CompoundV3Protocol scrapes the rates of the base assets and the collateral factors of the
+// collateral assets of the Compound v3 markets.
+type CompoundV3Protocol struct {
+	scraper  *DefiScraper
+	protocol dia.DefiProtocol
+}
+
+func NewCompoundV3(scraper *DefiScraper, protocol dia.DefiProtocol) *CompoundV3Protocol {
+	return &CompoundV3Protocol{scraper: scraper, protocol: protocol}
+}
+
+// UpdateRate sends the supply APY, the borrow APY and the utilization of the base asset of each
+// market. A market which can't be read is skipped.
+func (proto *CompoundV3Protocol) UpdateRate() error {
+	parsedABI, err := abi.JSON(strings.NewReader(cometABI))
+	if err != nil {
+		return err
+	}
+	for blockchain, comets := range CompoundV3Markets {
+		rates, err := proto.fetchRates(blockchain, comets, parsedABI)
+		if err != nil {
+			log.Errorf("%s: fetch rates on %s: %v", proto.protocol.Name, blockchain, err)
+			continue
+		}
+		for _, rate := range rates {
+			log.Printf("writing %s rate of %s on %s: %v", proto.protocol.Name, rate.Asset, blockchain, rate)
+			proto.scraper.RateChannel() <- rate
+		}
+	}
+	return nil
+}
+
+func (proto *CompoundV3Protocol) fetchRates(blockchain string, comets []string, parsedABI abi.ABI) ([]*dia.DefiRate, error) {
+	client, err := dialBlockchain(blockchain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	var rates []*dia.DefiRate
+	for _, address := range comets {
+		rate, err := proto.fetchRate(client, opts, common.HexToAddress(address), parsedABI)
+		if err != nil {
+			log.Errorf("%s: fetch rate of market %s on %s: %v", proto.protocol.Name, address, blockchain, err)
+			continue
+		}
+		rate.Blockchain = blockchain
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (proto *CompoundV3Protocol) fetchRate(client *ethclient.Client, opts *bind.CallOpts, address common.Address, parsedABI abi.ABI) (*dia.DefiRate, error) {
+	call := cometCall(bind.NewBoundContract(address, parsedABI, client, nil, nil), opts)
+	baseToken, err := call("baseToken")
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := tokenSymbol(client, opts, *abi.ConvertType(baseToken, new(common.Address)).(*common.Address))
+	if err != nil {
+		return nil, err
+	}
+	utilization, err := call("getUtilization")
+	if err != nil {
+		return nil, err
+	}
+	supplyRate, err := call("getSupplyRate", utilization)
+	if err != nil {
+		return nil, err
+	}
+	borrowRate, err := call("getBorrowRate", utilization)
+	if err != nil {
+		return nil, err
+	}
+	return &dia.DefiRate{
+		Timestamp:     time.Now(),
+		LendingRate:   compoundedAPY(float64(supplyRate.(uint64)) / 1e18),
+		BorrowingRate: compoundedAPY(float64(borrowRate.(uint64)) / 1e18),
+		Utilization:   cometFactor(*abi.ConvertType(utilization, new(*big.Int)).(**big.Int)),
+		Asset:         symbol,
+		Protocol:      proto.protocol.Name,
+	}, nil
+}
+
+// UpdateState stores the collateral factors of the collateral assets of each market. A market
+// which can't be read is skipped.
+func (proto *CompoundV3Protocol) UpdateState() error {
+	parsedABI, err := abi.JSON(strings.NewReader(cometABI))
+	if err != nil {
+		return err
+	}
+	for blockchain, comets := range CompoundV3Markets {
+		collaterals, err := proto.fetchCollaterals(blockchain, comets, parsedABI)
+		if err != nil {
+			log.Errorf("%s: fetch collaterals on %s: %v", proto.protocol.Name, blockchain, err)
+			continue
+		}
+		err = proto.scraper.datastore.SaveLendingCollateralInflux(collaterals)
+		if err != nil {
+			log.Errorf("%s: save collaterals on %s: %v", proto.protocol.Name, blockchain, err)
+		}
+	}
+	return nil
+}
+
+func (proto *CompoundV3Protocol) fetchCollaterals(blockchain string, comets []string, parsedABI abi.ABI) ([]dia.LendingCollateral, error) {
+	client, err := dialBlockchain(blockchain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	var collaterals []dia.LendingCollateral
+	for _, address := range comets {
+		marketCollaterals, err := proto.fetchMarketCollaterals(client, opts, common.HexToAddress(address), parsedABI)
+		if err != nil {
+			log.Errorf("%s: fetch collaterals of market %s on %s: %v", proto.protocol.Name, address, blockchain, err)
+			continue
+		}
+		for i := range marketCollaterals {
+			marketCollaterals[i].Blockchain = blockchain
+		}
+		collaterals = append(collaterals, marketCollaterals...)
+	}
+	return collaterals, nil
+}
+
+func (proto *CompoundV3Protocol) fetchMarketCollaterals(client *ethclient.Client, opts *bind.CallOpts, address common.Address, parsedABI abi.ABI) ([]dia.LendingCollateral, error) {
+	call := cometCall(bind.NewBoundContract(address, parsedABI, client, nil, nil), opts)
+	baseToken, err := call("baseToken")
+	if err != nil {
+		return nil, err
+	}
+	market, err := tokenSymbol(client, opts, *abi.ConvertType(baseToken, new(common.Address)).(*common.Address))
+	if err != nil {
+		return nil, err
+	}
+	numAssets, err := call("numAssets")
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	var collaterals []dia.LendingCollateral
+	for i := uint8(0); i < numAssets.(uint8); i++ {
+		out, err := call("getAssetInfo", i)
+		if err != nil {
+			return nil, err
+		}
+		info := *abi.ConvertType(out, new(cometAssetInfo)).(*cometAssetInfo)
+		symbol, err := tokenSymbol(client, opts, info.Asset)
+		if err != nil {
+			return nil, err
+		}
+		supplyCap, _ := new(big.Float).Quo(new(big.Float).SetInt(info.SupplyCap), new(big.Float).SetUint64(info.Scale)).Float64()
+		collaterals = append(collaterals, dia.LendingCollateral{
+			Protocol:                  proto.protocol.Name,
+			Market:                    market,
+			Asset:                     symbol,
+			Address:                   info.Asset.Hex(),
+			BorrowCollateralFactor:    float64(info.BorrowCollateralFactor) / 1e18,
+			LiquidateCollateralFactor: float64(info.LiquidateCollateralFactor) / 1e18,
+			SupplyCap:                 supplyCap,
+			Time:                      t,
+		})
+	}
+	return collaterals, nil
+}
+
+// cometCall returns a function calling a view function of @comet with a single result.
+func cometCall(comet *bind.BoundContract, opts *bind.CallOpts) func(method string, params ...interface{}) (interface{}, error) {
+	return func(method string, params ...interface{}) (interface{}, error) {
+		var out []interface{}
+		err := comet.Call(opts, &out, method, params...)
+		if err != nil {
+			return nil, err
+		}
+		return out[0], nil
+	}
+}
+
+// tokenSymbol returns the symbol of the ERC20 token at @address.
+func tokenSymbol(client *ethclient.Client, opts *bind.CallOpts, address common.Address) (string, error) {
+	token, err := contract.NewERC20(address, client)
+	if err != nil {
+		return "", err
+	}
+	return token.Symbol(opts)
+}
+
+// cometFactor returns a rate or factor of Comet, which are scaled by 1e18, as a fraction.
+func cometFactor(factor *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(factor), big.NewFloat(1e18)).Float64()
+	return f
+}
@@ -0,0 +1,153 @@
+package dexRouting
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/go-redis/redis"
+)
+
+const (
+	// MaxHops is the maximal number of pools followed until an asset is priced in USD.
+	MaxHops = 4
+	// sampleSize is the number of last trades used to rank the pools of an asset.
+	sampleSize = 1000
+)
+
+// Pool is a pair on an exchange in which an asset is traded against a base token.
+type Pool struct {
+	Exchange  string
+	Pair      string
+	BaseToken string
+	// Price is the price of the last trade in units of BaseToken.
+	Price float64
+	// Liquidity is the USD volume of the sampled trades. Pool reserves are not stored,
+	// so traded volume is used as the measure of liquidity.
+	Liquidity float64
+	Trades    int
+	LastTrade time.Time
+	Forbidden bool
+	Pinned    bool
+}
+
+// Hop is one step of a pricing route: @Symbol is priced in the base token of the selected pool.
+type Hop struct {
+	Symbol     string
+	Pool       Pool
+	Candidates []Pool
+}
+
+// Route explains how the USD price of an asset is obtained.
+type Route struct {
+	Asset string
+	Hops  []Hop
+	// PriceUSD is the product of the hop prices. It is only set for complete routes.
+	PriceUSD float64
+	Complete bool
+	// Reason is set if the route does not end in USD.
+	Reason string
+	Time   time.Time
+}
+
+// Pools aggregates @trades of @symbol by exchange and pair and marks them according to @rules.
+// The pools are sorted by liquidity, highest first.
+func Pools(symbol string, trades []dia.Trade, rules dia.RouteRules) []Pool {
+	index := make(map[string]int)
+	var pools []Pool
+	for _, t := range trades {
+		if t.Symbol != symbol {
+			continue
+		}
+		key := t.Source + "/" + strings.ToUpper(t.Pair)
+		i, ok := index[key]
+		if !ok {
+			i = len(pools)
+			index[key] = i
+			pools = append(pools, Pool{
+				Exchange:  t.Source,
+				Pair:      t.Pair,
+				BaseToken: t.BaseToken(),
+				Forbidden: !rules.Allows(t.Source, t.Pair),
+			})
+			for _, r := range rules {
+				if r.Action == dia.RoutePin && r.Matches(t.Source, t.Pair) {
+					pools[i].Pinned = true
+				}
+			}
+		}
+		p := &pools[i]
+		p.Trades++
+		p.Liquidity += math.Abs(t.Volume) * t.EstimatedUSDPrice
+		if t.Time.After(p.LastTrade) {
+			p.LastTrade = t.Time
+			p.Price = t.Price
+		}
+	}
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].Liquidity > pools[j].Liquidity
+	})
+	return pools
+}
+
+// SelectPool returns the most liquid pool which is not forbidden.
+func SelectPool(pools []Pool) (Pool, bool) {
+	for _, p := range pools {
+		if !p.Forbidden {
+			return p, true
+		}
+	}
+	return Pool{}, false
+}
+
+// BuildRoute follows the most liquid allowed pools of @asset, then of their base tokens,
+// until USD is reached.
+func BuildRoute(datastore models.Datastore, asset string) (Route, error) {
+	route := Route{Asset: asset, Time: time.Now()}
+	visited := map[string]bool{}
+	price := float64(1)
+	symbol := asset
+
+	for len(route.Hops) < MaxHops {
+		if symbol == "USD" {
+			break
+		}
+		if visited[symbol] {
+			route.Reason = "cycle at " + symbol
+			return route, nil
+		}
+		visited[symbol] = true
+
+		rules, err := datastore.GetRouteRules(symbol)
+		if err != nil && err != redis.Nil {
+			return route, err
+		}
+		trades, err := datastore.GetLastTradesAllExchanges(symbol, sampleSize)
+		if err != nil {
+			return route, err
+		}
+		pools := Pools(symbol, trades, rules)
+		if len(route.Hops) == 0 && len(pools) == 0 {
+			return route, errors.New("no trades found for " + asset)
+		}
+		pool, ok := SelectPool(pools)
+		route.Hops = append(route.Hops, Hop{Symbol: symbol, Pool: pool, Candidates: pools})
+		if !ok {
+			route.Reason = "no allowed pool for " + symbol
+			return route, nil
+		}
+		price *= pool.Price
+		symbol = pool.BaseToken
+	}
+	if symbol == "USD" {
+		route.Complete = true
+		route.PriceUSD = price
+	} else {
+		route.Reason = "maximal number of hops reached"
+	}
+	return route, nil
+}
@@ -0,0 +1,38 @@
+package dexRouting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestPools(t *testing.T) {
+	now := time.Now()
+	trades := []dia.Trade{
+		{Symbol: "XYZ", Pair: "XYZ-WETH", Source: dia.UniswapExchange, Price: 0.001, Volume: 10, EstimatedUSDPrice: 2, Time: now},
+		{Symbol: "XYZ", Pair: "XYZ-WETH", Source: dia.UniswapExchange, Price: 0.002, Volume: -10, EstimatedUSDPrice: 2, Time: now.Add(-time.Minute)},
+		{Symbol: "XYZ", Pair: "XYZ-USDC", Source: dia.BalancerExchange, Price: 2, Volume: 5, EstimatedUSDPrice: 2, Time: now},
+	}
+	rules := dia.RouteRules{{Asset: "XYZ", Exchange: dia.UniswapExchange, Pair: "XYZ-WETH", Action: dia.RouteForbid}}
+
+	pools := Pools("XYZ", trades, rules)
+	if len(pools) != 2 {
+		t.Fatalf("expected 2 pools, got %d", len(pools))
+	}
+	if pools[0].Exchange != dia.UniswapExchange || pools[0].Liquidity != 40 || pools[0].Trades != 2 {
+		t.Errorf("unexpected first pool %+v", pools[0])
+	}
+	if pools[0].Price != 0.001 || pools[0].BaseToken != "WETH" || !pools[0].Forbidden {
+		t.Errorf("unexpected first pool %+v", pools[0])
+	}
+
+	pool, ok := SelectPool(pools)
+	if !ok || pool.Exchange != dia.BalancerExchange {
+		t.Errorf("expected balancer pool, got %+v", pool)
+	}
+	_, ok = SelectPool(pools[:1])
+	if ok {
+		t.Errorf("selected forbidden pool")
+	}
+}
@@ -2,6 +2,7 @@ package scrapers
 
 import (
 	"io"
+	"os"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -49,7 +50,8 @@ func init() {
 	Exchanges[dia.FilterKing] = dia.Exchange{Name: dia.FilterKing, Centralized: true, WatchdogDelay: watchdogDelay}
 	Exchanges[dia.BancorExchange] = dia.Exchange{Name: dia.BancorExchange, Centralized: false, BlockChain: blockchains[dia.Ethereum], WatchdogDelay: watchdogDelayLong} //API is used instead of contracts
 	Exchanges[dia.UniswapExchange] = dia.Exchange{Name: dia.UniswapExchange, Centralized: false, BlockChain: blockchains[dia.Ethereum], Contract: common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"), WatchdogDelay: watchdogDelay}
-	Exchanges[dia.UniswapExchangeV3] = dia.Exchange{Name: dia.UniswapExchangeV3, Centralized: false, BlockChain: blockchains[dia.Ethereum], Contract: common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"), WatchdogDelay: watchdogDelay}
+	Exchanges[dia.UniswapExchangeV3] = dia.Exchange{Name: dia.UniswapExchangeV3, Centralized: false, BlockChain: blockchains[dia.Ethereum], Contract: common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"), WatchdogDelay: watchdogDelay}
+	Exchanges[dia.UniswapExchangeV3Polygon] = dia.Exchange{Name: dia.UniswapExchangeV3Polygon, Centralized: false, BlockChain: blockchains[dia.Ethereum], Contract: common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"), WatchdogDelay: watchdogDelay}
 	Exchanges[dia.LoopringExchange] = dia.Exchange{Name: dia.LoopringExchange, Centralized: false, BlockChain: blockchains[dia.Ethereum], WatchdogDelay: watchdogDelayLong} //API is used instead of contracts
 	Exchanges[dia.CurveFIExchange] = dia.Exchange{Name: dia.CurveFIExchange, Centralized: false, BlockChain: blockchains[dia.Ethereum], Contract: common.HexToAddress("0x7002B727Ef8F5571Cb5F9D70D13DBEEb4dFAe9d1"), WatchdogDelay: watchdogDelay}
 	Exchanges[dia.MakerExchange] = dia.Exchange{Name: dia.MakerExchange, Centralized: false, BlockChain: blockchains[dia.Ethereum], WatchdogDelay: watchdogDelay} //API is used instead of contracts
@@ -61,6 +63,8 @@ func init() {
 	Exchanges[dia.KyberExchange] = dia.Exchange{Name: dia.KyberExchange, Centralized: true, WatchdogDelay: watchdogDelay}
 	Exchanges[dia.BitMaxExchange] = dia.Exchange{Name: dia.BitMaxExchange, Centralized: true, WatchdogDelay: watchdogDelay}
 	Exchanges[dia.STEXExchange] = dia.Exchange{Name: dia.STEXExchange, Centralized: true, WatchdogDelay: watchdogDelay}
+	Exchanges[dia.DydxExchange] = dia.Exchange{Name: dia.DydxExchange, Centralized: false, WatchdogDelay: watchdogDelay}     //API of the indexer is used instead of the chain
+	Exchanges[dia.VertexExchange] = dia.Exchange{Name: dia.VertexExchange, Centralized: false, WatchdogDelay: watchdogDelay} //API is used instead of contracts
 	Exchanges[dia.DfynNetwork] = dia.Exchange{Name: dia.DfynNetwork, Centralized: false, BlockChain: blockchains[dia.Ethereum], Contract: common.HexToAddress("0xe7fb3e833efe5f9c441105eb65ef8b261266423b"), WatchdogDelay: watchdogDelay}
 }
 
@@ -78,6 +82,19 @@ type APIScraper interface {
 	Channel() chan *dia.Trade
 }
 
+// PairMetaScraper is implemented by scrapers of exchanges which publish the trading conditions
+// of their pairs, i.e. tick sizes, minimal order sizes and fee tiers.
+type PairMetaScraper interface {
+	// FetchPairMeta returns the trading conditions of all available pairs
+	FetchPairMeta() (meta []dia.PairMeta, err error)
+}
+
+// PoolVolumeScraper is implemented by DEX scrapers which report the volume of each of their pools.
+type PoolVolumeScraper interface {
+	// PoolVolumeChannel returns a channel of the volumes of all pools in regular intervals
+	PoolVolumeChannel() chan *dia.PoolVolume
+}
+
 // PairScraper receives trades for a single pc.Pair from a single exchange.
 type PairScraper interface {
 	io.Closer
@@ -100,7 +117,7 @@ func NewAPIScraper(exchange string, key string, secret string) APIScraper {
 	case dia.BittrexExchange:
 		return NewBittrexScraper(Exchanges[dia.BittrexExchange])
 	case dia.CoinBaseExchange:
-		return NewCoinBaseScraper(Exchanges[dia.CoinBaseExchange])
+		return NewCoinBaseScraper(key, secret, Exchanges[dia.CoinBaseExchange])
 	case dia.CREX24Exchange:
 		return NewCREX24Scraper(Exchanges[dia.CREX24Exchange])
 	case dia.KrakenExchange:
@@ -153,11 +170,25 @@ func NewAPIScraper(exchange string, key string, secret string) APIScraper {
 		return NewSTEXScraper(Exchanges[dia.STEXExchange])
 	case dia.UniswapExchangeV3:
 		return NewUniswapV3Scraper(Exchanges[dia.UniswapExchangeV3])
+	case dia.UniswapExchangeV3Polygon:
+		return NewUniswapV3Scraper(Exchanges[dia.UniswapExchangeV3Polygon])
 	case dia.DfynNetwork:
 		return NewUniswapScraper(Exchanges[dia.DfynNetwork])
+	case dia.DydxExchange:
+		return NewDydxScraper(Exchanges[dia.DydxExchange])
+	case dia.VertexExchange:
+		return NewVertexScraper(Exchanges[dia.VertexExchange])
 
 	default:
-		return nil
+		// Exchanges without Go code may be described by a generic scraper config.
+		config, err := LoadGenericScraperConfig(exchange)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Errorf("generic scraper config of %s: %v", exchange, err)
+			}
+			return nil
+		}
+		return NewGenericScraper(config)
 	}
 
 }
@@ -62,6 +62,8 @@ type BalancerScraper struct {
 	pairScrapers      map[string]*BalancerPairScraper
 	productPairIds    map[string]int
 	chanTrades        chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 
 	WsClient    *ethclient.Client
 	RestClient  *ethclient.Client
@@ -93,6 +95,7 @@ func NewBalancerScraper(exchange dia.Exchange) *BalancerScraper {
 		log.Fatal(err)
 	}
 	scraper.RestClient = restClient
+	scraper.confirmations = newConfirmationBuffer(restClient, scraper.chanTrades, scraper.shutdown)
 
 	go scraper.mainLoop()
 	return scraper
@@ -213,6 +216,10 @@ func (scraper *BalancerScraper) subscribeToNewSwaps(poolToSub string) error {
 					scraper.resubscribe <- poolToSub
 				}
 			case vLog := <-sink:
+				if vLog.Raw.Removed {
+					scraper.confirmations.Retract(vLog.Raw)
+					continue
+				}
 
 				decimalsIn := int(scraper.balancerTokensMap[vLog.TokenIn.Hex()].Decimals)
 				decimalsOut := int(scraper.balancerTokensMap[vLog.TokenOut.Hex()].Decimals)
@@ -249,7 +256,7 @@ func (scraper *BalancerScraper) subscribeToNewSwaps(poolToSub string) error {
 					ForeignTradeID: swap.ID,
 					Source:         scraper.exchangeName,
 				}
-				pairScraper.parent.chanTrades <- trade
+				pairScraper.parent.confirmations.Add(vLog.Raw, trade)
 				fmt.Println("got trade: ", trade)
 
 			}
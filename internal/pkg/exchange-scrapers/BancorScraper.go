@@ -101,6 +101,8 @@ type BancorScraper struct {
 	pairScrapers   map[string]*BancorPairScraper
 	productPairIds map[string]int
 	chanTrades     chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 }
 
 func NewBancorScraper(exchange dia.Exchange) *BancorScraper {
@@ -127,6 +129,7 @@ func NewBancorScraper(exchange dia.Exchange) *BancorScraper {
 		pairScrapers:   make(map[string]*BancorPairScraper),
 		chanTrades:     make(chan *dia.Trade),
 	}
+	scraper.confirmations = newConfirmationBuffer(restClient, scraper.chanTrades, scraper.shutdown)
 
 	go scraper.mainLoop()
 	return scraper
@@ -145,6 +148,10 @@ func (scraper *BancorScraper) mainLoop() {
 		for {
 
 			rawSwap := <-sink
+			if rawSwap.Raw.Removed {
+				scraper.confirmations.Retract(rawSwap.Raw)
+				continue
+			}
 			revRawSwap := reverseBNTSwap(*rawSwap)
 
 			var address []common.Address
@@ -171,7 +178,7 @@ func (scraper *BancorScraper) mainLoop() {
 			}
 
 			log.Info("Got Trade: ", trade)
-			scraper.chanTrades <- trade
+			scraper.confirmations.Add(revRawSwap.Raw, trade)
 
 		}
 	}()
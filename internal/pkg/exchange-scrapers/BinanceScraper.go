@@ -3,6 +3,7 @@ package scrapers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
 	"sync"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/adshao/go-binance"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/verificationHelper"
 	utils "github.com/diadata-org/diadata/pkg/utils"
 )
 
@@ -34,6 +36,8 @@ type BinanceScraper struct {
 	pairLocks         sync.Map // dia.Pair -> sync.Mutex
 	exchangeName      string
 	chanTrades        chan *dia.Trade
+	// aggregated trade ids are consecutive per pair
+	sequences *verificationHelper.SequenceTracker
 }
 
 // NewBinanceScraper returns a new BinanceScraper for the given pair
@@ -47,6 +51,7 @@ func NewBinanceScraper(apiKey string, secretKey string, exchange dia.Exchange) *
 		exchangeName: exchange.Name,
 		error:        nil,
 		chanTrades:   make(chan *dia.Trade),
+		sequences:    verificationHelper.NewSequenceTracker(),
 	}
 
 	// establish connection in the background
@@ -135,18 +140,24 @@ func (s *BinanceScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		price, err2 := strconv.ParseFloat(event.Price, 64)
 
 		if err == nil && err2 == nil && event.Event == "aggTrade" {
+			status, err := s.sequences.Check(pair.ForeignName, event.AggTradeID)
+			if err != nil {
+				log.Warnf("rejecting trade on %s: %v", s.exchangeName, err)
+				return
+			}
 			if !event.IsBuyerMaker {
 				volume = -volume
 			}
 			pairNormalized, _ := s.NormalizePair(pair)
 			t := &dia.Trade{
-				Symbol:         pairNormalized.Symbol,
-				Pair:           pairNormalized.ForeignName,
-				Price:          price,
-				Volume:         volume,
-				Time:           time.Unix(event.TradeTime/1000, (event.TradeTime%1000)*int64(time.Millisecond)),
-				ForeignTradeID: strconv.FormatInt(event.AggTradeID, 16),
-				Source:         s.exchangeName,
+				Symbol:             pairNormalized.Symbol,
+				Pair:               pairNormalized.ForeignName,
+				Price:              price,
+				Volume:             volume,
+				Time:               time.Unix(event.TradeTime/1000, (event.TradeTime%1000)*int64(time.Millisecond)),
+				ForeignTradeID:     strconv.FormatInt(event.AggTradeID, 16),
+				Source:             s.exchangeName,
+				VerificationStatus: status,
 			}
 			ps.parent.chanTrades <- t
 			// log.Info("got trade: ", t)
@@ -219,6 +230,152 @@ func (s *BinanceScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
 	return
 }
 
+// FetchPairMeta returns tick size, lot size and minimal order sizes of all pairs traded on Binance.
+// Fee tiers are only available on the authenticated API and hence left empty.
+func (s *BinanceScraper) FetchPairMeta() (meta []dia.PairMeta, err error) {
+	type binanceFilter struct {
+		FilterType  string `json:"filterType"`
+		TickSize    string `json:"tickSize"`
+		MinQty      string `json:"minQty"`
+		StepSize    string `json:"stepSize"`
+		MinNotional string `json:"minNotional"`
+	}
+	type binanceSymbol struct {
+		Symbol  string          `json:"symbol"`
+		Status  string          `json:"status"`
+		Filters []binanceFilter `json:"filters"`
+	}
+	var ar struct {
+		Symbols []binanceSymbol `json:"symbols"`
+	}
+
+	data, err := utils.GetRequest("https://api.binance.com/api/v1/exchangeInfo")
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &ar)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, p := range ar.Symbols {
+		if p.Status != "TRADING" {
+			continue
+		}
+		pm := dia.PairMeta{
+			Exchange:    s.exchangeName,
+			ForeignName: p.Symbol,
+			Time:        now,
+		}
+		for _, f := range p.Filters {
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				pm.TickSize, _ = strconv.ParseFloat(f.TickSize, 64)
+			case "LOT_SIZE":
+				pm.LotSize, _ = strconv.ParseFloat(f.StepSize, 64)
+				pm.MinOrderSize, _ = strconv.ParseFloat(f.MinQty, 64)
+			case "MIN_NOTIONAL":
+				pm.MinNotional, _ = strconv.ParseFloat(f.MinNotional, 64)
+			}
+		}
+		meta = append(meta, pm)
+	}
+	return
+}
+
+// binanceAggTradesURL serves the aggregated trades of a pair, the trades of the live stream.
+const binanceAggTradesURL = "https://api.binance.com/api/v3/aggTrades"
+
+type binanceAggTrade struct {
+	ID           int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	Time         int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+}
+
+// FetchHistoricalTrades calls @handle with the aggregated trades of @pair in [@from, @to). The
+// first trade is searched by time in windows of an hour, the longest Binance accepts, all
+// further trades are paged by id.
+func (s *BinanceScraper) FetchHistoricalTrades(pair dia.Pair, from time.Time, to time.Time, handle func([]dia.Trade) error) error {
+	pairNormalized, _ := s.NormalizePair(pair)
+	fromID := int64(-1)
+	windowStart := from
+	for {
+		var url string
+		if fromID < 0 {
+			if !windowStart.Before(to) {
+				return nil
+			}
+			windowEnd := windowStart.Add(time.Hour)
+			if windowEnd.After(to) {
+				windowEnd = to
+			}
+			// endTime is inclusive.
+			url = fmt.Sprintf("%s?symbol=%s&startTime=%d&endTime=%d&limit=1000", binanceAggTradesURL, pair.ForeignName, windowStart.UnixNano()/1e6, windowEnd.UnixNano()/1e6-1)
+			windowStart = windowEnd
+		} else {
+			url = fmt.Sprintf("%s?symbol=%s&fromId=%d&limit=1000", binanceAggTradesURL, pair.ForeignName, fromID)
+		}
+		data, err := requestSchedulerFor(s.exchangeName).Get(url)
+		if err != nil {
+			return err
+		}
+		var aggTrades []binanceAggTrade
+		err = json.Unmarshal(data, &aggTrades)
+		if err != nil {
+			return err
+		}
+		if len(aggTrades) == 0 {
+			if fromID < 0 {
+				continue
+			}
+			return nil
+		}
+
+		var trades []dia.Trade
+		done := false
+		for _, aggTrade := range aggTrades {
+			timestamp := time.Unix(aggTrade.Time/1000, (aggTrade.Time%1000)*int64(time.Millisecond))
+			if !timestamp.Before(to) {
+				done = true
+				break
+			}
+			price, err := strconv.ParseFloat(aggTrade.Price, 64)
+			if err != nil {
+				return err
+			}
+			volume, err := strconv.ParseFloat(aggTrade.Quantity, 64)
+			if err != nil {
+				return err
+			}
+			// The sign of the volume follows the live scraper.
+			if !aggTrade.IsBuyerMaker {
+				volume = -volume
+			}
+			trades = append(trades, dia.Trade{
+				Symbol:         pairNormalized.Symbol,
+				Pair:           pairNormalized.ForeignName,
+				Price:          price,
+				Volume:         volume,
+				Time:           timestamp,
+				ForeignTradeID: strconv.FormatInt(aggTrade.ID, 16),
+				Source:         s.exchangeName,
+			})
+		}
+		if len(trades) > 0 {
+			err = handle(trades)
+			if err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+		fromID = aggTrades[len(aggTrades)-1].ID + 1
+	}
+}
+
 // BinancePairScraper implements PairScraper for Binance
 type BinancePairScraper struct {
 	parent *BinanceScraper
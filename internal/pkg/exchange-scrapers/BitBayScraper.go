@@ -8,9 +8,8 @@ import (
 	"sync"
 	"time"
 
-	ws "github.com/gorilla/websocket"
-
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	"github.com/diadata-org/diadata/pkg/utils"
 )
 
@@ -28,6 +27,8 @@ const waitForScrapers = 10
 
 var BitBaySocketURL string = "wss://api.bitbay.net/websocket/"
 
+const bitBayPingInterval = 10 * time.Second
+
 type BitBaySubscribe struct {
 	Action string `json:"action"`
 	Module string `json:"module"`
@@ -52,8 +53,8 @@ type BitBayTrade struct {
 // BitBayScraper provides  methods needed to get Trade information from BitBay
 type BitBayScraper struct {
 	// control flag for main loop
-	run      bool
-	wsClient *ws.Conn
+	run  bool
+	conn *wsHelper.Connection
 
 	// signaling channels for session initialization and finishing
 	shutdown     chan nothing
@@ -84,14 +85,13 @@ func NewBitBayScraper(exchange dia.Exchange) *BitBayScraper {
 		closed:       false,
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(BitBaySocketURL, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:         exchange.Name,
+		URL:          BitBaySocketURL,
+		Subscribe:    s.subscribe,
+		Ping:         s.ping,
+		PingInterval: bitBayPingInterval,
+	})
 
 	go s.mainLoop()
 
@@ -115,7 +115,7 @@ func (s *BitBayScraper) getMarkets() (markets []string) {
 	return
 }
 
-func (s *BitBayScraper) ping() {
+func (s *BitBayScraper) ping(conn *wsHelper.Connection) error {
 
 	a := &BitBaySubscribe{
 		Action: "ping",
@@ -123,12 +123,11 @@ func (s *BitBayScraper) ping() {
 
 	log.Infoln("Ping: ", a.Action)
 
-	if err := s.wsClient.WriteJSON(a); err != nil {
-		log.Println(err.Error())
-	}
+	return conn.WriteJSON(a)
 }
 
-func (s *BitBayScraper) subscribe() {
+// subscribe to the transactions of all markets, again after reconnecting.
+func (s *BitBayScraper) subscribe(conn *wsHelper.Connection) error {
 
 	markets := s.getMarkets()
 
@@ -142,32 +141,28 @@ func (s *BitBayScraper) subscribe() {
 
 		log.Println("subscribing", a)
 
-		if err := s.wsClient.WriteJSON(a); err != nil {
-			log.Println(err.Error())
+		if err := conn.WriteJSON(a); err != nil {
+			return err
 		}
 
 	}
+	return nil
 }
 
 // runs in a goroutine until s is closed
 func (s *BitBayScraper) mainLoop() {
 
-	s.subscribe()
-
-	pingTimer := time.NewTicker(10 * time.Second)
-	go func() {
-		for range pingTimer.C {
-			go s.ping()
-		}
-	}()
-
+	var err error
 	for {
 
 		var response BitBayWSResponse
 
-		if s.error = s.wsClient.ReadJSON(&response); s.error != nil {
-			log.Error("ws connection error: ", s.error.Error())
-			s.subscribe()
+		if err = s.conn.ReadJSON(&response); err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
+			log.Error("decode message: ", err.Error())
+			continue
 		}
 
 		//b,_ := json.Marshal(message)
@@ -197,6 +192,7 @@ func (s *BitBayScraper) mainLoop() {
 			log.Error("unknown pair: " + pair)
 			continue
 		}
+		s.conn.Received(ps.pair.ForeignName)
 
 		for _, trade := range response.Message.Transactions {
 
@@ -229,10 +225,7 @@ func (s *BitBayScraper) mainLoop() {
 			ps.parent.chanTrades <- t
 		}
 	}
-	if s.error == nil {
-		s.error = errors.New(s.exchangeName + "Scraper: terminated by Close()")
-	}
-	s.cleanup(s.error)
+	s.cleanup(errors.New(s.exchangeName + "Scraper: terminated by Close()"))
 }
 
 // Close channels for shutdown
@@ -253,6 +246,7 @@ func (s *BitBayScraper) Close() error {
 		return errors.New(s.exchangeName + "Scraper: Already closed")
 	}
 	s.run = false
+	s.conn.Close()
 	<-s.shutdownDone
 	s.errorLock.RLock()
 	defer s.errorLock.RUnlock()
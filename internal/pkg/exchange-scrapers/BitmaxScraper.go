@@ -10,9 +10,8 @@ import (
 	"sync"
 	"time"
 
-	ws "github.com/gorilla/websocket"
-
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 )
 
 type BitMaxPairResponse struct {
@@ -47,10 +46,11 @@ type BitMaxScraper struct {
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
 	// use sync.Maps to concurrently handle multiple pairs
-	pairScrapers map[string]*BitMaxPairScraper // dia.Pair -> BitMaxPairScraper
-	exchangeName string
-	chanTrades   chan *dia.Trade
-	wsClient     *ws.Conn
+	pairScrapers     map[string]*BitMaxPairScraper // dia.Pair -> BitMaxPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
+	conn             *wsHelper.Connection
 }
 
 func NewBitMaxScraper(exchange dia.Exchange) *BitMaxScraper {
@@ -65,14 +65,11 @@ func NewBitMaxScraper(exchange dia.Exchange) *BitMaxScraper {
 		chanTrades:   make(chan *dia.Trade),
 	}
 
-	// establish connection in the background
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(bitmaxSocketURL, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:      exchange.Name,
+		URL:       bitmaxSocketURL,
+		Subscribe: s.subscribeToALL,
+	})
 
 	go s.mainLoop()
 	return s
@@ -95,9 +92,12 @@ func (s *BitMaxScraper) mainLoop() {
 	var err error
 	for {
 		message := &BitMaxTradeResponse{}
-		if err = s.wsClient.ReadJSON(&message); err != nil {
+		if err = s.conn.ReadJSON(&message); err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
 			log.Error(err.Error())
-			break
+			continue
 		}
 		switch message.M {
 
@@ -117,6 +117,7 @@ func (s *BitMaxScraper) mainLoop() {
 						Source:         s.exchangeName,
 					}
 					log.Infoln("Got Trade", t)
+					s.conn.Received(message.Symbol)
 					s.chanTrades <- t
 				}
 
@@ -126,7 +127,7 @@ func (s *BitMaxScraper) mainLoop() {
 				a := &BitMaxRequest{
 					Op: "pong",
 				}
-				err := s.wsClient.WriteJSON(a)
+				err := s.conn.WriteJSON(a)
 				if err != nil {
 					log.Warn("send pong to server: ", err)
 				}
@@ -136,7 +137,17 @@ func (s *BitMaxScraper) mainLoop() {
 
 		}
 	}
+	s.cleanup(err)
+}
 
+func (s *BitMaxScraper) cleanup(err error) {
+	s.errorLock.Lock()
+	defer s.errorLock.Unlock()
+	if err != nil {
+		s.error = err
+	}
+	s.closed = true
+	close(s.shutdownDone)
 }
 
 func (s *BitMaxScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
@@ -150,6 +161,7 @@ func (s *BitMaxScraper) Close() error {
 		return errors.New("BitMaxScraper: Already closed")
 	}
 	close(s.shutdown)
+	s.conn.Close()
 	<-s.shutdownDone
 	s.errorLock.RLock()
 	defer s.errorLock.RUnlock()
@@ -181,22 +193,40 @@ func (s *BitMaxScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		pair:   pair,
 	}
 
-	a := &BitMaxRequest{
-		Op: "sub",
-		Ch: "trades:" + pair.ForeignName,
-		ID: string(time.Now().Unix()),
-	}
+	s.pairScrapersLock.Lock()
+	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
 
-	if err := s.wsClient.WriteJSON(a); err != nil {
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, pair.ForeignName); err != nil {
 		log.Error(err.Error())
 	}
 	log.Info("Subscribed to get trades for ", pair.ForeignName)
 
-	s.pairScrapers[pair.ForeignName] = ps
-
 	return ps, nil
 }
 
+// subscribeToALL subscribes again to the trades of all scraped pairs.
+func (s *BitMaxScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	defer s.pairScrapersLock.RUnlock()
+	for foreignName := range s.pairScrapers {
+		if err := s.subscribe(conn, foreignName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BitMaxScraper) subscribe(conn *wsHelper.Connection, foreignName string) error {
+	a := &BitMaxRequest{
+		Op: "sub",
+		Ch: "trades:" + foreignName,
+		ID: strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	return conn.WriteJSON(a)
+}
+
 func (s *BitMaxScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
 	var bitmaxResponse BitMaxPairResponse
 	response, err := http.Get("https://ascendex.com/api/pro/v1/products")
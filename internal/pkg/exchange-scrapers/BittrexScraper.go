@@ -11,7 +11,6 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
-	utils "github.com/diadata-org/diadata/pkg/utils"
 )
 
 type ConfirmData struct {
@@ -67,9 +66,6 @@ func (s *BittrexScraper) mainLoop() {
 		}
 		for key, el := range s.pairScrapers {
 
-			// more or less 60 calls per minute, the limit is 60
-			time.Sleep(1 * time.Second)
-
 			//swap the pairs name, necessary for api call
 			sPairs := strings.Split(key, "-")
 			sLeft, sRight := sPairs[0], sPairs[1]
@@ -124,7 +120,7 @@ func (s *BittrexScraper) mainLoop() {
 
 func getAPICallBittrex(params ...string) []interface{} {
 
-	body, err := utils.GetRequest(_bittrexapiurl + params[0])
+	body, err := requestSchedulerFor(dia.BittrexExchange).Get(_bittrexapiurl + params[0])
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -0,0 +1,189 @@
+package scrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Default routes of a CCXT gateway. {exchange} is replaced by the CCXT id of the exchange and
+// {symbol} by the unified symbol of a market, e.g. BTC/USDT.
+const (
+	ccxtMarketsRoute = "/{exchange}/markets"
+	ccxtTradesRoute  = "/{exchange}/trades?symbol={symbol}"
+	ccxtTickerRoute  = "/{exchange}/ticker?symbol={symbol}"
+)
+
+// GenericCCXTConfig describes an exchange scraped through a gateway serving the unified API of
+// CCXT over REST, e.g. a small sidecar around the ccxt library. This covers exchanges without a
+// native scraper quickly. Routes are relative to URL and return the unified structures of CCXT
+// as json, so that other gateways such as ccxt-rest can be used by adapting them.
+type GenericCCXTConfig struct {
+	URL string `yaml:"url"`
+	// Exchange is the CCXT id of the exchange. Defaults to the lower case name.
+	Exchange string `yaml:"exchange"`
+	// Markets, Trades and Ticker are the routes of fetchMarkets, fetchTrades and fetchTicker.
+	// They default to ccxtMarketsRoute, ccxtTradesRoute and ccxtTickerRoute.
+	Markets string `yaml:"markets"`
+	Trades  string `yaml:"trades"`
+	Ticker  string `yaml:"ticker"`
+	// Interval is the time between two requests of the trades of a pair.
+	Interval time.Duration `yaml:"interval"`
+	// RequestsPerSecond limits the requests to the gateway. If 0, the default limit applies.
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	// Tickers derives trades from tickers, for exchanges whose trades CCXT doesn't fetch. Each
+	// ticker is a trade at the last price with the increase of the 24h volume since the
+	// previous ticker as volume.
+	Tickers bool `yaml:"tickers"`
+}
+
+// ccxtMarket is a market in the unified structure of CCXT.
+type ccxtMarket struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Base   string `json:"base"`
+	Quote  string `json:"quote"`
+	// Active is null if the exchange doesn't tell.
+	Active *bool  `json:"active"`
+	Type   string `json:"type"`
+}
+
+// ccxtTrade is a trade in the unified structure of CCXT. Timestamps are in milliseconds.
+type ccxtTrade struct {
+	ID        string  `json:"id"`
+	Timestamp int64   `json:"timestamp"`
+	Symbol    string  `json:"symbol"`
+	Side      string  `json:"side"`
+	Price     float64 `json:"price"`
+	Amount    float64 `json:"amount"`
+}
+
+// ccxtTicker is a ticker in the unified structure of CCXT. BaseVolume is the volume of the last
+// 24 hours.
+type ccxtTicker struct {
+	Symbol     string   `json:"symbol"`
+	Timestamp  int64    `json:"timestamp"`
+	Last       *float64 `json:"last"`
+	BaseVolume *float64 `json:"baseVolume"`
+}
+
+// ccxtAPI implements pollingAPI for an exchange served by a CCXT gateway. Pairs are named by
+// their unified symbol.
+type ccxtAPI struct {
+	name   string
+	config *GenericCCXTConfig
+	// baseVolumes are the 24h volumes of the previous tickers by symbol, see Tickers.
+	baseVolumes map[string]float64
+}
+
+func newCCXTAPI(name string, config *GenericCCXTConfig) *ccxtAPI {
+	return &ccxtAPI{
+		name:        name,
+		config:      config,
+		baseVolumes: make(map[string]float64),
+	}
+}
+
+// route returns the URL of @route for the market @symbol.
+func (api *ccxtAPI) route(route string, symbol string) string {
+	return strings.TrimSuffix(api.config.URL, "/") + strings.NewReplacer(
+		"{exchange}", url.PathEscape(api.config.Exchange),
+		"{symbol}", url.QueryEscape(symbol),
+	).Replace(route)
+}
+
+// fetchPairs returns the active spot markets. Gateways return them as array, as fetchMarkets
+// does, or as object by symbol, as loadMarkets does.
+func (api *ccxtAPI) fetchPairs() (pairs []dia.Pair, err error) {
+	data, err := requestSchedulerFor(api.name).Get(api.route(api.config.Markets, ""))
+	if err != nil {
+		return
+	}
+	var markets []ccxtMarket
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '{' {
+		var bySymbol map[string]ccxtMarket
+		err = json.Unmarshal(data, &bySymbol)
+		for _, market := range bySymbol {
+			markets = append(markets, market)
+		}
+	} else {
+		err = json.Unmarshal(data, &markets)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: markets: %v", api.name, err)
+	}
+	for _, market := range markets {
+		if market.Active != nil && !*market.Active {
+			continue
+		}
+		if market.Type != "" && market.Type != "spot" {
+			continue
+		}
+		pairs = append(pairs, dia.Pair{
+			Symbol:      strings.ToUpper(market.Base),
+			ForeignName: market.Symbol,
+			Exchange:    api.name,
+		})
+	}
+	return
+}
+
+func (api *ccxtAPI) fetchTrades(foreignName string) ([]dia.Trade, error) {
+	if api.config.Tickers {
+		return api.fetchTicker(foreignName)
+	}
+	var ccxtTrades []ccxtTrade
+	err := getPollingJSON(api.name, api.route(api.config.Trades, foreignName), &ccxtTrades)
+	if err != nil {
+		return nil, err
+	}
+	trades := make([]dia.Trade, 0, len(ccxtTrades))
+	for _, t := range ccxtTrades {
+		volume := t.Amount
+		if t.Side == "sell" {
+			volume = -volume
+		}
+		trades = append(trades, dia.Trade{
+			Price:          t.Price,
+			Volume:         volume,
+			Time:           time.Unix(0, t.Timestamp*int64(time.Millisecond)),
+			ForeignTradeID: t.ID,
+		})
+	}
+	return trades, nil
+}
+
+// fetchTicker returns the trade derived from the ticker of @foreignName, if its 24h volume
+// increased since the previous ticker. The first ticker of a market only sets the volume.
+func (api *ccxtAPI) fetchTicker(foreignName string) ([]dia.Trade, error) {
+	var ticker ccxtTicker
+	err := getPollingJSON(api.name, api.route(api.config.Ticker, foreignName), &ticker)
+	if err != nil {
+		return nil, err
+	}
+	if ticker.Last == nil || ticker.BaseVolume == nil {
+		return nil, fmt.Errorf("ticker of %s has no last price or volume", foreignName)
+	}
+	previous, ok := api.baseVolumes[foreignName]
+	api.baseVolumes[foreignName] = *ticker.BaseVolume
+	// The volume drops when trades leave the 24h window faster than new ones arrive.
+	if !ok || *ticker.BaseVolume <= previous {
+		return nil, nil
+	}
+	tickerTime := time.Now()
+	if ticker.Timestamp > 0 {
+		tickerTime = time.Unix(0, ticker.Timestamp*int64(time.Millisecond))
+	}
+	return []dia.Trade{{
+		Price:          *ticker.Last,
+		Volume:         *ticker.BaseVolume - previous,
+		Time:           tickerTime,
+		ForeignTradeID: "ticker-" + strconv.FormatInt(tickerTime.UnixNano()/int64(time.Millisecond), 10),
+	}}, nil
+}
@@ -0,0 +1,81 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testCCXTGateway serves markets, trades and tickers of the CCXT exchange test. The 24h volume
+// of the ticker grows by 1.5 with each request.
+func testCCXTGateway(t *testing.T) *httptest.Server {
+	var tickers int
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/test/markets":
+			fmt.Fprint(w, `{
+				"BTC/USDT": {"id": "BTCUSDT", "symbol": "BTC/USDT", "base": "BTC", "quote": "USDT", "active": true, "type": "spot"},
+				"ETH/USDT": {"id": "ETHUSDT", "symbol": "ETH/USDT", "base": "ETH", "quote": "USDT", "active": false, "type": "spot"},
+				"BTC/USDT:USDT": {"id": "BTCUSDTPERP", "symbol": "BTC/USDT:USDT", "base": "BTC", "quote": "USDT", "active": true, "type": "swap"}
+			}`)
+		case "/test/trades":
+			if symbol := r.URL.Query().Get("symbol"); symbol != "BTC/USDT" {
+				t.Errorf("got symbol %s, expected BTC/USDT", symbol)
+			}
+			fmt.Fprint(w, `[
+				{"id": "1", "timestamp": 1700000000000, "symbol": "BTC/USDT", "side": "buy", "price": 37000, "amount": 0.5},
+				{"id": "2", "timestamp": 1700000001500, "symbol": "BTC/USDT", "side": "sell", "price": 36990, "amount": 0.25}
+			]`)
+		case "/test/ticker":
+			tickers++
+			fmt.Fprintf(w, `{"symbol": "BTC/USDT", "timestamp": %d, "last": 37010, "baseVolume": %v}`, 1700000000000+int64(tickers)*1000, 100+1.5*float64(tickers))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestCCXTAPI(t *testing.T) {
+	server := testCCXTGateway(t)
+	defer server.Close()
+
+	config, err := parseGenericScraperConfig([]byte(fmt.Sprintf("name: CCXTTest\nccxt:\n  url: %s/\n  exchange: test\n", server.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newCCXTAPI(config.Name, config.CCXT)
+
+	pairs, err := api.fetchPairs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 || pairs[0].Symbol != "BTC" || pairs[0].ForeignName != "BTC/USDT" {
+		t.Errorf("got pairs %v, expected the spot market BTC/USDT", pairs)
+	}
+
+	trades, err := api.fetchTrades("BTC/USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 2 {
+		t.Fatalf("got %d trades, expected 2", len(trades))
+	}
+	if trades[1].Price != 36990 || trades[1].Volume != -0.25 || trades[1].ForeignTradeID != "2" || !trades[1].Time.Equal(time.Unix(1700000001, 500000000)) {
+		t.Errorf("got trade %v, expected the sell of 0.25 at 36990", trades[1])
+	}
+
+	// The first ticker sets the volume, the second one is a trade of the increase.
+	config.CCXT.Tickers = true
+	if trades, err := api.fetchTrades("BTC/USDT"); err != nil || len(trades) != 0 {
+		t.Fatalf("got trades %v and error %v, expected none", trades, err)
+	}
+	trades, err = api.fetchTrades("BTC/USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 || trades[0].Price != 37010 || trades[0].Volume != 1.5 || !trades[0].Time.Equal(time.Unix(1700000002, 0)) {
+		t.Errorf("got trades %v, expected a trade of 1.5 at 37010", trades)
+	}
+}
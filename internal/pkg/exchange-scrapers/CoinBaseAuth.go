@@ -0,0 +1,87 @@
+package scrapers
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+)
+
+// coinBaseJWTLifetime is the validity of the tokens of the Advanced Trade API. Coinbase
+// rejects tokens valid for longer than two minutes.
+const coinBaseJWTLifetime = 2 * time.Minute
+
+// coinBaseSigner signs the tokens authenticating requests to the Advanced Trade API with the
+// key of a Coinbase Developer Platform API key.
+type coinBaseSigner struct {
+	// keyName is the name of the API key, e.g. organizations/{org_id}/apiKeys/{key_id}
+	keyName string
+	key     *ecdsa.PrivateKey
+}
+
+// newCoinBaseSigner returns a signer for the API key @keyName with the PEM encoded EC private
+// key @keySecret. Escaped newlines, as in json config files, are accepted.
+func newCoinBaseSigner(keyName string, keySecret string) (*coinBaseSigner, error) {
+	block, _ := pem.Decode([]byte(strings.Replace(keySecret, `\n`, "\n", -1)))
+	if block == nil {
+		return nil, errors.New("CoinBaseScraper: API secret is not a PEM encoded key")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &coinBaseSigner{keyName: keyName, key: key}, nil
+}
+
+// token returns a JWT signed with ES256 valid for coinBaseJWTLifetime. @uri is the method, host
+// and path of a REST request, e.g. GET api.coinbase.com/api/v3/brokerage/products, and empty
+// for the websocket.
+func (s *coinBaseSigner) token(uri string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	header, err := json.Marshal(map[string]string{
+		"alg":   "ES256",
+		"typ":   "JWT",
+		"kid":   s.keyName,
+		"nonce": hex.EncodeToString(nonce),
+	})
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss": "cdp",
+		"sub": s.keyName,
+		"nbf": now.Unix(),
+		"exp": now.Add(coinBaseJWTLifetime).Unix(),
+	}
+	if uri != "" {
+		claims["uri"] = uri
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// ES256 signatures are the concatenation of r and s, each padded to 32 bytes.
+	signature := make([]byte, 64)
+	rBytes, sBytes := r.Bytes(), sig.Bytes()
+	copy(signature[32-len(rBytes):32], rBytes)
+	copy(signature[64-len(sBytes):], sBytes)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -3,106 +3,223 @@ package scrapers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
-	"github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
-	gdax "github.com/preichenberger/go-coinbasepro/v2"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/verificationHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
+)
+
+const (
+	coinBaseSocketURL = "wss://advanced-trade-ws.coinbase.com"
+	coinBaseAPIHost   = "api.coinbase.com"
+	// coinBaseProductsPath lists the products to authenticated clients, coinBasePublicProductsPath
+	// to all others.
+	coinBaseProductsPath       = "/api/v3/brokerage/products"
+	coinBasePublicProductsPath = "/api/v3/brokerage/market/products"
+	// Heartbeats arrive every second, a silent connection is renewed.
+	coinBaseReadTimeout = 30 * time.Second
 )
 
 type CoinBaseScraper struct {
 	// signaling channels
+	run          bool
 	shutdown     chan nothing
 	shutdownDone chan nothing
 	// error handling; to read error or closed, first acquire read lock
 	// only cleanup method should hold write lock
-	errorLock    sync.RWMutex
-	error        error
-	closed       bool
-	pairScrapers map[string]*CoinBasePairScraper // pc.Pair -> pairScraperSet
-	wsConn       *ws.Conn
-	exchangeName string
-	chanTrades   chan *dia.Trade
+	errorLock        sync.RWMutex
+	error            error
+	closed           bool
+	pairScrapers     map[string]*CoinBasePairScraper // pc.Pair -> pairScraperSet
+	pairScrapersLock sync.RWMutex
+	conn             *wsHelper.Connection
+	exchangeName     string
+	chanTrades       chan *dia.Trade
+	// trade ids are consecutive per product
+	sequences *verificationHelper.SequenceTracker
+	// signer authenticates requests if an API key is configured, it is nil otherwise
+	signer *coinBaseSigner
 }
 
 const (
-	ChannelHeartbeat = "heartbeat"
-	ChannelTicker    = "ticker"
-	ChannelLevel2    = "level2"
-	ChannelUser      = "user"
-	ChannelMatches   = "matches"
-	ChannelFull      = "full"
+	ChannelHeartbeats   = "heartbeats"
+	ChannelMarketTrades = "market_trades"
 )
 
 // NewCoinBaseScraper returns a new CoinBaseScraper initialized with default values.
-// The instance is asynchronously scraping as soon as it is created.
-func NewCoinBaseScraper(exchange dia.Exchange) *CoinBaseScraper {
+// The instance is asynchronously scraping as soon as it is created. @key and @secret are the
+// name and private key of a Coinbase Developer Platform API key. Market data is public, so
+// requests are only authenticated if they are given.
+func NewCoinBaseScraper(key string, secret string, exchange dia.Exchange) *CoinBaseScraper {
 	s := &CoinBaseScraper{
+		run:          true,
 		shutdown:     make(chan nothing),
 		shutdownDone: make(chan nothing),
 		pairScrapers: make(map[string]*CoinBasePairScraper),
 		exchangeName: exchange.Name,
 		error:        nil,
 		chanTrades:   make(chan *dia.Trade),
+		sequences:    verificationHelper.NewSequenceTracker(),
 	}
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial("wss://ws-feed.pro.coinbase.com", nil)
-	if err != nil {
-		println(err.Error())
+	if key != "" && secret != "" {
+		signer, err := newCoinBaseSigner(key, secret)
+		if err != nil {
+			log.Error("CoinBaseScraper: unauthenticated, invalid API key: ", err)
+		} else {
+			s.signer = signer
+		}
 	}
-	s.wsConn = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:        exchange.Name,
+		URL:         coinBaseSocketURL,
+		Subscribe:   s.subscribeToALL,
+		ReadTimeout: coinBaseReadTimeout,
+	})
 	go s.mainLoop()
 	return s
 }
 
+type coinBaseSubscription struct {
+	Type       string   `json:"type"`
+	ProductIDs []string `json:"product_ids,omitempty"`
+	Channel    string   `json:"channel"`
+	JWT        string   `json:"jwt,omitempty"`
+}
+
+type coinBaseMessage struct {
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Events  []struct {
+		Type   string `json:"type"`
+		Trades []struct {
+			TradeID   string    `json:"trade_id"`
+			ProductID string    `json:"product_id"`
+			Price     string    `json:"price"`
+			Size      string    `json:"size"`
+			Side      string    `json:"side"`
+			Time      time.Time `json:"time"`
+		} `json:"trades"`
+	} `json:"events"`
+}
+
 // mainLoop runs in a goroutine until channel s is closed.
 func (s *CoinBaseScraper) mainLoop() {
-	var err error
-	for true {
-		message := gdax.Message{}
-		if err = s.wsConn.ReadJSON(&message); err != nil {
-			println(err.Error())
-			break
+	for s.run {
+		var message coinBaseMessage
+		if err := s.conn.ReadJSON(&message); err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
+			log.Error("decode message: ", err)
+			continue
+		}
+		if message.Type == "error" {
+			log.Error("CoinBaseScraper: ", message.Message)
+			continue
 		}
-		if message.Type == ChannelTicker {
-			ps, ok := s.pairScrapers[message.ProductID]
-			if ok {
-				f64Price, err := strconv.ParseFloat(message.Price, 64)
-				if err == nil {
-					f64Volume, err := strconv.ParseFloat(message.LastSize, 64)
-					if err == nil {
-						if message.TradeID != 0 {
-							if message.Side == "sell" {
-								f64Volume = -f64Volume
-							}
-							t := &dia.Trade{
-								Symbol:         ps.pair.Symbol,
-								Pair:           message.ProductID,
-								Price:          f64Price,
-								Volume:         f64Volume,
-								Time:           message.Time.Time(),
-								ForeignTradeID: strconv.FormatInt(int64(message.TradeID), 16),
-								Source:         s.exchangeName,
-							}
-							log.Info("go trade: ", t)
-							ps.parent.chanTrades <- t
-						}
-					} else {
-						log.Error("error parsing LastSize " + message.LastSize)
+		if message.Channel != ChannelMarketTrades {
+			continue
+		}
+		for _, event := range message.Events {
+			trades := event.Trades
+			// Snapshots and updates list the latest trade first.
+			sort.SliceStable(trades, func(i, j int) bool {
+				return len(trades[i].TradeID) < len(trades[j].TradeID) ||
+					(len(trades[i].TradeID) == len(trades[j].TradeID) && trades[i].TradeID < trades[j].TradeID)
+			})
+			for _, trade := range trades {
+				s.pairScrapersLock.RLock()
+				ps, ok := s.pairScrapers[trade.ProductID]
+				s.pairScrapersLock.RUnlock()
+				if !ok {
+					log.Error("unknown productError" + trade.ProductID)
+					continue
+				}
+				s.conn.Received(ps.pair.ForeignName)
+				f64Price, err := strconv.ParseFloat(trade.Price, 64)
+				if err != nil {
+					log.Error("error parsing price " + trade.Price)
+					continue
+				}
+				f64Volume, err := strconv.ParseFloat(trade.Size, 64)
+				if err != nil {
+					log.Error("error parsing size " + trade.Size)
+					continue
+				}
+				tradeID, err := strconv.ParseInt(trade.TradeID, 10, 64)
+				if err != nil {
+					log.Error("error parsing trade id " + trade.TradeID)
+					continue
+				}
+				// The snapshot after a reconnect repeats trades which were already sent.
+				status, err := s.sequences.Check(trade.ProductID, tradeID)
+				if err != nil {
+					if !errors.Is(err, verificationHelper.ErrReplay) || event.Type != "snapshot" {
+						log.Warnf("rejecting trade on %s: %v", s.exchangeName, err)
 					}
-				} else {
-					log.Error("error parsing price " + message.Price)
+					continue
 				}
-			} else {
-				log.Error("unknown productError" + message.ProductID)
+				if trade.Side == "SELL" {
+					f64Volume = -f64Volume
+				}
+				t := &dia.Trade{
+					Symbol:             ps.pair.Symbol,
+					Pair:               trade.ProductID,
+					Price:              f64Price,
+					Volume:             f64Volume,
+					Time:               trade.Time,
+					ForeignTradeID:     strconv.FormatInt(tradeID, 16),
+					Source:             s.exchangeName,
+					VerificationStatus: status,
+				}
+				log.Info("go trade: ", t)
+				ps.parent.chanTrades <- t
 			}
 		}
 	}
-	s.cleanup(err)
+	s.cleanup(errors.New("main loop terminated by Close()"))
+}
+
+// subscribe to @channel of @productIDs. Subscriptions are signed if an API key is configured.
+func (s *CoinBaseScraper) subscribe(conn *wsHelper.Connection, channel string, productIDs []string) error {
+	subscription := coinBaseSubscription{Type: "subscribe", Channel: channel, ProductIDs: productIDs}
+	if s.signer != nil {
+		token, err := s.signer.token("")
+		if err != nil {
+			return err
+		}
+		subscription.JWT = token
+	}
+	return conn.WriteJSON(subscription)
+}
+
+// subscribeToALL subscribes again to the heartbeats and the trades of all scraped products.
+// Without heartbeats, Coinbase closes connections to products without trades.
+func (s *CoinBaseScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	var productIDs []string
+	for productID := range s.pairScrapers {
+		productIDs = append(productIDs, productID)
+	}
+	s.pairScrapersLock.RUnlock()
+
+	if err := s.subscribe(conn, ChannelHeartbeats, nil); err != nil {
+		return err
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return s.subscribe(conn, ChannelMarketTrades, productIDs)
 }
 
 // closes all connected PairScrapers
@@ -123,8 +240,10 @@ func (s *CoinBaseScraper) Close() error {
 	if s.closed {
 		return errors.New("CoinBaseScraper: Already closed")
 	}
-	s.wsConn.Close()
+	// Set false first to prevent reconnect
+	s.run = false
 	close(s.shutdown)
+	s.conn.Close()
 	<-s.shutdownDone
 	s.errorLock.RLock()
 	defer s.errorLock.RUnlock()
@@ -156,39 +275,221 @@ func (s *CoinBaseScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
 
 }
 
+type coinBaseProduct struct {
+	ProductID       string `json:"product_id"`
+	ProductType     string `json:"product_type"`
+	Status          string `json:"status"`
+	BaseMinSize     string `json:"base_min_size"`
+	BaseIncrement   string `json:"base_increment"`
+	QuoteMinSize    string `json:"quote_min_size"`
+	PriceIncrement  string `json:"price_increment"`
+	TradingDisabled bool   `json:"trading_disabled"`
+}
+
+// fetchProducts returns all spot products which are traded. The authenticated endpoint is
+// used if an API key is configured, since its rate limits are higher.
+func (s *CoinBaseScraper) fetchProducts() ([]coinBaseProduct, error) {
+	path := coinBasePublicProductsPath
+	if s.signer != nil {
+		path = coinBaseProductsPath
+	}
+	request, err := http.NewRequest(http.MethodGet, "https://"+coinBaseAPIHost+path+"?product_type=SPOT", nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.signer != nil {
+		token, err := s.signer.token(http.MethodGet + " " + coinBaseAPIHost + path)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CoinBaseScraper: products returned status %d", response.StatusCode)
+	}
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Products []coinBaseProduct `json:"products"`
+	}
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	var products []coinBaseProduct
+	for _, p := range resp.Products {
+		if p.TradingDisabled || p.Status != "online" {
+			continue
+		}
+		products = append(products, p)
+	}
+	return products, nil
+}
+
 // FetchAvailablePairs returns a list with all available trade pairs
 func (s *CoinBaseScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
+	products, err := s.fetchProducts()
+	if err != nil {
+		return
+	}
+	for _, p := range products {
+		pairToNormalise := dia.Pair{
+			Symbol:      "",
+			ForeignName: p.ProductID,
+			Exchange:    s.exchangeName,
+		}
+		pair, serr := s.NormalizePair(pairToNormalise)
+		if serr == nil {
+			pairs = append(pairs, pair)
+		} else {
+			log.Error(serr)
+		}
+	}
+	return
+}
 
-	data, err := utils.GetRequest("https://api.pro.coinbase.com/products")
+// FetchPairMeta returns tick size, lot size and minimal order sizes of all pairs traded on CoinBase.
+// Fee tiers depend on the account and are left empty.
+func (s *CoinBaseScraper) FetchPairMeta() (meta []dia.PairMeta, err error) {
+	products, err := s.fetchProducts()
 	if err != nil {
 		return
 	}
-	var ar []gdax.Product
-	err = json.Unmarshal(data, &ar)
-	if err == nil {
-		for _, p := range ar {
-			pairToNormalise := dia.Pair{
-				Symbol:      "",
-				ForeignName: p.ID,
-				Exchange:    s.exchangeName,
+	now := time.Now()
+	for _, p := range products {
+		pm := dia.PairMeta{
+			Exchange:    s.exchangeName,
+			ForeignName: p.ProductID,
+			Time:        now,
+		}
+		pm.TickSize, _ = strconv.ParseFloat(p.PriceIncrement, 64)
+		pm.LotSize, _ = strconv.ParseFloat(p.BaseIncrement, 64)
+		pm.MinOrderSize, _ = strconv.ParseFloat(p.BaseMinSize, 64)
+		pm.MinNotional, _ = strconv.ParseFloat(p.QuoteMinSize, 64)
+		meta = append(meta, pm)
+	}
+	return
+}
+
+// coinBaseExchangeTradesURL serves the trades of a product newest first. The ids of the
+// trades are the ones of the live feed.
+const coinBaseExchangeTradesURL = "https://api.exchange.coinbase.com/products/%s/trades?limit=%d"
+
+type coinBaseExchangeTrade struct {
+	Time    time.Time `json:"time"`
+	TradeID int64     `json:"trade_id"`
+	Price   string    `json:"price"`
+	Size    string    `json:"size"`
+	Side    string    `json:"side"`
+}
+
+// fetchExchangeTrades returns at most @limit trades of @productID with ids below @before, newest
+// first. A @before of 0 returns the latest trades.
+func (s *CoinBaseScraper) fetchExchangeTrades(productID string, before int64, limit int) ([]coinBaseExchangeTrade, error) {
+	url := fmt.Sprintf(coinBaseExchangeTradesURL, productID, limit)
+	if before > 0 {
+		url += "&after=" + strconv.FormatInt(before, 10)
+	}
+	data, err := requestSchedulerFor(s.exchangeName).Get(url)
+	if err != nil {
+		return nil, err
+	}
+	var trades []coinBaseExchangeTrade
+	err = json.Unmarshal(data, &trades)
+	return trades, err
+}
+
+// FetchHistoricalTrades calls @handle with the trades of @pair in [@from, @to). Trades can
+// only be paged backwards from an id, so the first id at @to is searched by bisection over the
+// consecutive ids of the product, and pages are handled from @to back to @from.
+func (s *CoinBaseScraper) FetchHistoricalTrades(pair dia.Pair, from time.Time, to time.Time, handle func([]dia.Trade) error) error {
+	latest, err := s.fetchExchangeTrades(pair.ForeignName, 0, 1)
+	if err != nil || len(latest) == 0 {
+		return err
+	}
+	end := latest[0].TradeID + 1
+	if !latest[0].Time.Before(to) {
+		lo, hi := int64(1), latest[0].TradeID
+		for lo < hi {
+			mid := lo + (hi-lo)/2
+			trades, err := s.fetchExchangeTrades(pair.ForeignName, mid+1, 1)
+			if err != nil {
+				return err
 			}
-			pair, serr := s.NormalizePair(pairToNormalise)
-			if serr == nil {
-				pairs = append(pairs, pair)
+			if len(trades) == 0 || !trades[0].Time.Before(to) {
+				hi = mid
 			} else {
-				log.Error(serr)
+				lo = mid + 1
 			}
 		}
+		end = lo
 	}
-	return
+
+	for end > 1 {
+		page, err := s.fetchExchangeTrades(pair.ForeignName, end, 1000)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		var trades []dia.Trade
+		done := false
+		for _, trade := range page {
+			if trade.Time.Before(from) {
+				done = true
+				break
+			}
+			price, err := strconv.ParseFloat(trade.Price, 64)
+			if err != nil {
+				return err
+			}
+			volume, err := strconv.ParseFloat(trade.Size, 64)
+			if err != nil {
+				return err
+			}
+			// Side is the side of the maker here, the live feed gives the side of the taker.
+			if trade.Side == "buy" {
+				volume = -volume
+			}
+			trades = append(trades, dia.Trade{
+				Symbol:         pair.Symbol,
+				Pair:           pair.ForeignName,
+				Price:          price,
+				Volume:         volume,
+				Time:           trade.Time,
+				ForeignTradeID: strconv.FormatInt(trade.TradeID, 16),
+				Source:         s.exchangeName,
+			})
+		}
+		sortTradesByTime(trades)
+		if len(trades) > 0 {
+			err = handle(trades)
+			if err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+		end = page[len(page)-1].TradeID
+	}
+	return nil
 }
 
 // NewCoinBaseScraper implements PairScraper for GDax
 type CoinBasePairScraper struct {
-	parent     *CoinBaseScraper
-	pair       dia.Pair
-	closed     bool
-	lastRecord int64
+	parent *CoinBaseScraper
+	pair   dia.Pair
+	closed bool
 }
 
 // ScrapePair returns a PairScraper that can be used to get trades for a single pair from
@@ -204,32 +505,17 @@ func (s *CoinBaseScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		return nil, errors.New("CoinBaseScraper: Call ScrapePair on closed scraper")
 	}
 	ps := &CoinBasePairScraper{
-		parent:     s,
-		pair:       pair,
-		lastRecord: 0, //TODO FIX to figure out the last we got...
+		parent: s,
+		pair:   pair,
 	}
 
+	s.pairScrapersLock.Lock()
 	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
 
-	subscribe := gdax.Message{
-		Type: "subscribe",
-		Channels: []gdax.MessageChannel{
-			gdax.MessageChannel{
-				Name: ChannelHeartbeat,
-				ProductIds: []string{
-					pair.ForeignName,
-				},
-			},
-			gdax.MessageChannel{
-				Name: ChannelTicker,
-				ProductIds: []string{
-					pair.ForeignName,
-				},
-			},
-		},
-	}
-	if err := s.wsConn.WriteJSON(subscribe); err != nil {
-		println(err.Error())
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, ChannelMarketTrades, []string{pair.ForeignName}); err != nil {
+		log.Error("subscribe: ", err)
 	}
 
 	return ps, nil
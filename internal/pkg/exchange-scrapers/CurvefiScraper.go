@@ -6,13 +6,18 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
 
 	"github.com/diadata-org/diadata/internal/pkg/exchange-scrapers/curvefi"
 	"github.com/diadata-org/diadata/internal/pkg/exchange-scrapers/curvefi/curvepool"
@@ -24,34 +29,84 @@ const (
 	curveFiLookBackBlocks = 6 * 60 * 24 * 20
 	curveWsDial           = "ws://159.69.120.42:8546/"
 	curveRestDial         = "http://159.69.120.42:8545/"
+
+	// curveETHAddress stands for ether in the coins of pools holding native ETH.
+	curveETHAddress = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+	// curvePoolRefresh is the interval in which the registries are checked for new pools.
+	curvePoolRefresh = time.Hour
+	// curvePoolVolumeInterval is the interval in which the volumes of the pools are reported.
+	curvePoolVolumeInterval = time.Hour
+
+	// curveCryptoPoolABI holds the TokenExchange event of crypto pools, whose coin indices are
+	// uint256 instead of int128 as in stable pools.
+	curveCryptoPoolABI = `[{"name":"TokenExchange","inputs":[{"type":"address","name":"buyer","indexed":true},{"type":"uint256","name":"sold_id","indexed":false},{"type":"uint256","name":"tokens_sold","indexed":false},{"type":"uint256","name":"bought_id","indexed":false},{"type":"uint256","name":"tokens_bought","indexed":false}],"anonymous":false,"type":"event"}]`
+	// curveRegistryABI holds the pool list of the factories and the crypto registry, whose
+	// get_coins returns an array of %d coins.
+	curveRegistryABI = `[{"name":"pool_count","outputs":[{"type":"uint256","name":""}],"inputs":[],"stateMutability":"view","type":"function"},{"name":"pool_list","outputs":[{"type":"address","name":""}],"inputs":[{"type":"uint256","name":"arg0"}],"stateMutability":"view","type":"function"},{"name":"get_coins","outputs":[{"type":"address[%d]","name":""}],"inputs":[{"type":"address","name":"_pool"}],"stateMutability":"view","type":"function"},{"name":"get_underlying_coins","outputs":[{"type":"address[8]","name":""}],"inputs":[{"type":"address","name":"_pool"}],"stateMutability":"view","type":"function"}]`
 )
 
+// curveRegistry is a contract listing Curve pools other than the main registry.
+type curveRegistry struct {
+	address common.Address
+	// numCoins is the length of the coin array returned by get_coins.
+	numCoins int
+	// underlying registries list the underlying coins of their pools, which are metapools.
+	underlying bool
+	crypto     bool
+}
+
+// curveRegistries are the factories of metapools and the registry and factory of crypto pools.
+var curveRegistries = []curveRegistry{
+	{address: common.HexToAddress("0x0959158b6040D32d04c301A72CBFD6b39E21c9AE"), numCoins: 4, underlying: true},
+	{address: common.HexToAddress("0x8F942C20D02bEfc377D41445793068908E2250D0"), numCoins: 8, crypto: true},
+	{address: common.HexToAddress("0xF18056Bbd320E96A48e3Fbf8bC061322531aac99"), numCoins: 2, crypto: true},
+}
+
 type CurveCoin struct {
 	Symbol   string
 	Decimals uint8
 }
 
+// CurvePool holds the coins of a pool by index. Swaps of the wrapped coins, such as the LP token
+// of the base pool of a metapool, are indexed in Coins, swaps of the underlying coins in
+// UnderlyingCoins. Crypto pools index their coins with uint256 in their swaps.
+type CurvePool struct {
+	Coins           map[int]*CurveCoin
+	UnderlyingCoins map[int]*CurveCoin
+	Crypto          bool
+}
+
 type Pools struct {
-	pools     map[string]map[int]*CurveCoin
+	pools     map[string]*CurvePool
 	poolsLock sync.RWMutex
 }
 
-func (p *Pools) setPool(k string, v map[int]*CurveCoin) {
+func (p *Pools) setPool(k string, v *CurvePool) {
 	p.poolsLock.Lock()
 	defer p.poolsLock.Unlock()
 	p.pools[k] = v
 }
 
-func (p *Pools) getPool(k string) (map[int]*CurveCoin, bool) {
+func (p *Pools) getPool(k string) (*CurvePool, bool) {
 	p.poolsLock.RLock()
 	defer p.poolsLock.RUnlock()
 	r, ok := p.pools[k]
 	return r, ok
 }
-func (p *Pools) getPoolCoin(poolk string, coink int) (*CurveCoin, bool) {
+
+// getPoolCoin returns the coin with index @coink of a pool, or of its underlying coins.
+func (p *Pools) getPoolCoin(poolk string, coink int, underlying bool) (*CurveCoin, bool) {
 	p.poolsLock.RLock()
 	defer p.poolsLock.RUnlock()
-	r, ok := p.pools[poolk][coink]
+	pool, ok := p.pools[poolk]
+	if !ok {
+		return nil, false
+	}
+	if underlying {
+		r, ok := pool.UnderlyingCoins[coink]
+		return r, ok
+	}
+	r, ok := pool.Coins[coink]
 	return r, ok
 }
 
@@ -81,6 +136,8 @@ type CurveFIScraper struct {
 	pairScrapers   map[string]*CurveFIPairScraper
 	productPairIds map[string]int
 	chanTrades     chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 
 	WsClient    *ethclient.Client
 	RestClient  *ethclient.Client
@@ -88,6 +145,11 @@ type CurveFIScraper struct {
 	resubscribe chan string
 	pools       *Pools
 	contract    common.Address
+
+	// volumes accumulate the volume of each pool until it is reported on chanPoolVolumes.
+	volumes         map[string]*dia.PoolVolume
+	volumesLock     sync.Mutex
+	chanPoolVolumes chan *dia.PoolVolume
 }
 
 func NewCurveFIScraper(exchange dia.Exchange) *CurveFIScraper {
@@ -103,8 +165,10 @@ func NewCurveFIScraper(exchange dia.Exchange) *CurveFIScraper {
 		curveCoins:     make(map[string]*CurveCoin),
 		resubscribe:    make(chan string),
 		pools: &Pools{
-			pools: make(map[string]map[int]*CurveCoin),
+			pools: make(map[string]*CurvePool),
 		},
+		volumes:         make(map[string]*dia.PoolVolume),
+		chanPoolVolumes: make(chan *dia.PoolVolume),
 	}
 
 	wsClient, err := ethclient.Dial(curveWsDial)
@@ -117,8 +181,15 @@ func NewCurveFIScraper(exchange dia.Exchange) *CurveFIScraper {
 		log.Fatal(err)
 	}
 	scraper.RestClient = restClient
+	scraper.confirmations = newConfirmationBuffer(restClient, scraper.chanTrades, scraper.shutdown)
 
 	scraper.loadPoolsAndCoins()
+	for _, registry := range curveRegistries {
+		err = scraper.loadRegistryPools(registry, false)
+		if err != nil {
+			log.Errorf("load pools of registry %s: %v", registry.address.Hex(), err)
+		}
+	}
 
 	go scraper.mainLoop()
 	return scraper
@@ -131,6 +202,8 @@ func (scraper *CurveFIScraper) mainLoop() {
 		scraper.watchSwaps(pool)
 	}
 	scraper.watchNewPools()
+	go scraper.watchRegistries()
+	go scraper.reportPoolVolumes()
 
 	go func() {
 		for scraper.run {
@@ -210,6 +283,26 @@ func (scraper *CurveFIScraper) watchNewPools() {
 
 }
 
+// watchRegistries loads and watches the pools added to the factories and the crypto registry,
+// which don't emit events when pools are added.
+func (scraper *CurveFIScraper) watchRegistries() {
+	ticker := time.NewTicker(curvePoolRefresh)
+	defer ticker.Stop()
+	for scraper.run {
+		select {
+		case <-ticker.C:
+			for _, registry := range curveRegistries {
+				err := scraper.loadRegistryPools(registry, true)
+				if err != nil {
+					log.Errorf("load pools of registry %s: %v", registry.address.Hex(), err)
+				}
+			}
+		case <-scraper.shutdown:
+			return
+		}
+	}
+}
+
 // contract.poolList.map(contract.GetPoolCoins(pool).)
 func (scraper *CurveFIScraper) loadPoolsAndCoins() error {
 	contract, err := curvefi.NewCurvefiCaller(scraper.contract, scraper.RestClient)
@@ -238,14 +331,82 @@ func (scraper *CurveFIScraper) loadPoolData(pool string) error {
 		log.Error(err)
 	}
 
-	poolCoinsMap := make(map[int]*CurveCoin)
-
 	poolCoins, err := contract.GetPoolCoins(&bind.CallOpts{}, common.HexToAddress(pool))
 	if err != nil {
 		log.Error(err)
 	}
 
-	for cIdx, c := range poolCoins.Coins {
+	scraper.pools.setPool(pool, &CurvePool{
+		Coins:           scraper.loadCoins(poolCoins.Coins[:]),
+		UnderlyingCoins: scraper.loadCoins(poolCoins.UnderlyingCoins[:]),
+	})
+	return err
+}
+
+// loadRegistryPools loads the pools of @registry which aren't known yet. If @watch is true,
+// their swaps are watched.
+func (scraper *CurveFIScraper) loadRegistryPools(registry curveRegistry, watch bool) error {
+	parsedABI, err := abi.JSON(strings.NewReader(fmt.Sprintf(curveRegistryABI, registry.numCoins)))
+	if err != nil {
+		return err
+	}
+	contract := bind.NewBoundContract(registry.address, parsedABI, scraper.RestClient, nil, nil)
+
+	var out []interface{}
+	err = contract.Call(&bind.CallOpts{}, &out, "pool_count")
+	if err != nil {
+		return err
+	}
+	poolCount := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	for i := int64(0); i < poolCount.Int64(); i++ {
+		out = nil
+		err = contract.Call(&bind.CallOpts{}, &out, "pool_list", big.NewInt(i))
+		if err != nil {
+			return err
+		}
+		pool := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+		if _, ok := scraper.pools.getPool(pool.Hex()); ok {
+			continue
+		}
+
+		curvePool := &CurvePool{Crypto: registry.crypto}
+		out = nil
+		err = contract.Call(&bind.CallOpts{}, &out, "get_coins", pool)
+		if err != nil {
+			log.Errorf("get coins of pool %s: %v", pool.Hex(), err)
+			continue
+		}
+		curvePool.Coins = scraper.loadCoins(addressArray(out[0]))
+		if registry.underlying {
+			out = nil
+			err = contract.Call(&bind.CallOpts{}, &out, "get_underlying_coins", pool)
+			if err != nil {
+				log.Errorf("get underlying coins of pool %s: %v", pool.Hex(), err)
+				continue
+			}
+			curvePool.UnderlyingCoins = scraper.loadCoins(addressArray(out[0]))
+		}
+
+		scraper.pools.setPool(pool.Hex(), curvePool)
+		if watch {
+			scraper.watchSwaps(pool.Hex())
+		}
+	}
+	return nil
+}
+
+// loadCoins returns the symbols and decimals of @coins by index. The list of coins ends at the
+// first zero address.
+func (scraper *CurveFIScraper) loadCoins(coins []common.Address) map[int]*CurveCoin {
+	poolCoinsMap := make(map[int]*CurveCoin)
+	for cIdx, c := range coins {
+		if c == (common.Address{}) {
+			break
+		}
+		if c == common.HexToAddress(curveETHAddress) {
+			poolCoinsMap[cIdx] = &CurveCoin{Symbol: "ETH", Decimals: 18}
+			continue
+		}
 
 		coinCaller, err := token.NewTokenCaller(c, scraper.RestClient)
 		if err != nil {
@@ -271,18 +432,35 @@ func (scraper *CurveFIScraper) loadPoolData(pool string) error {
 			Symbol:   symbol,
 			Decimals: uint8(decimals.Uint64()),
 		}
+	}
+	return poolCoinsMap
+}
 
-		scraper.pools.setPool(pool, poolCoinsMap)
-
+// addressArray returns the addresses of a fixed size address array returned by a contract call.
+func addressArray(v interface{}) []common.Address {
+	array := reflect.ValueOf(v)
+	if array.Kind() != reflect.Array && array.Kind() != reflect.Slice {
+		return nil
 	}
-	return err
+	addresses := make([]common.Address, 0, array.Len())
+	for i := 0; i < array.Len(); i++ {
+		if address, ok := array.Index(i).Interface().(common.Address); ok {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
 }
 
-func (scraper *CurveFIScraper) processSwap(pool string, swp *curvepool.CurvepoolTokenExchange) {
+func (scraper *CurveFIScraper) processSwap(pool string, swp *curvepool.CurvepoolTokenExchange, underlying bool) {
+	if swp.Raw.Removed {
+		scraper.confirmations.Retract(swp.Raw)
+		return
+	}
 
-	foreignName, volume, price, err := scraper.getSwapDataCurve(pool, swp)
+	foreignName, volume, price, err := scraper.getSwapDataCurve(pool, swp, underlying)
 	if err != nil {
 		log.Error(err)
+		return
 	}
 	timestamp := time.Now().Unix()
 
@@ -299,17 +477,17 @@ func (scraper *CurveFIScraper) processSwap(pool string, swp *curvepool.Curvepool
 		}
 		log.Infoln("Got Trade  ", trade)
 
-		scraper.chanTrades <- trade
+		scraper.confirmations.Add(swp.Raw, trade)
 	}
 }
 
+// watchSwaps subscribes to the swaps of the coins and underlying coins of @pool. If one of the
+// subscriptions fails, all of them are renewed.
 func (scraper *CurveFIScraper) watchSwaps(pool string) error {
-
-	filterer, err := curvepool.NewCurvepoolFilterer(common.HexToAddress(pool), scraper.WsClient)
-	if err != nil {
-		log.Fatal(err)
+	curvePool, ok := scraper.pools.getPool(pool)
+	if !ok {
+		return fmt.Errorf("unknown pool %s", pool)
 	}
-	sink := make(chan *curvepool.CurvepoolTokenExchange)
 
 	header, err := scraper.RestClient.HeaderByNumber(context.Background(), nil)
 	if err != nil {
@@ -317,21 +495,66 @@ func (scraper *CurveFIScraper) watchSwaps(pool string) error {
 	}
 	startblock := header.Number.Uint64() - uint64(15250)
 
-	sub, err := filterer.WatchTokenExchange(&bind.WatchOpts{Start: &startblock}, sink, nil)
+	var subs []event.Subscription
+	sink := make(chan *curvepool.CurvepoolTokenExchange)
+	sinkUnderlying := make(chan *curvepool.CurvepoolTokenExchangeUnderlying)
+	var sinkCrypto chan types.Log
+	var cryptoContract *bind.BoundContract
 
-	if err != nil {
-		log.Error(err)
+	if curvePool.Crypto {
+		parsedABI, err := abi.JSON(strings.NewReader(curveCryptoPoolABI))
+		if err != nil {
+			return err
+		}
+		cryptoContract = bind.NewBoundContract(common.HexToAddress(pool), parsedABI, scraper.WsClient, scraper.WsClient, scraper.WsClient)
+		var sub event.Subscription
+		sinkCrypto, sub, err = cryptoContract.WatchLogs(&bind.WatchOpts{Start: &startblock}, "TokenExchange")
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		subs = append(subs, sub)
+	} else {
+		filterer, err := curvepool.NewCurvepoolFilterer(common.HexToAddress(pool), scraper.WsClient)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sub, err := filterer.WatchTokenExchange(&bind.WatchOpts{Start: &startblock}, sink, nil)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		subs = append(subs, sub)
+		if len(curvePool.UnderlyingCoins) > 0 {
+			sub, err = filterer.WatchTokenExchangeUnderlying(&bind.WatchOpts{Start: &startblock}, sinkUnderlying, nil)
+			if err != nil {
+				log.Error(err)
+			} else {
+				subs = append(subs, sub)
+			}
+		}
+	}
+
+	errs := make(chan error, len(subs))
+	for _, sub := range subs {
+		go func(sub event.Subscription) {
+			errs <- <-sub.Err()
+		}(sub)
 	}
 
 	go func() {
 		fmt.Println("Curvefi Subscribed to pool: " + pool)
 		defer fmt.Println("Curvefi UnSubscribed to pool: " + pool)
-		defer sub.Unsubscribe()
+		defer func() {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+		}()
 		subscribed := true
 
 		for scraper.run && subscribed {
 			select {
-			case err := <-sub.Err():
+			case err := <-errs:
 				if err != nil {
 					log.Error(err)
 				}
@@ -340,29 +563,37 @@ func (scraper *CurveFIScraper) watchSwaps(pool string) error {
 					scraper.resubscribe <- pool
 				}
 			case swp := <-sink:
-
-				// fmt.Println(swp)
-				scraper.processSwap(pool, swp)
+				scraper.processSwap(pool, swp, false)
+			case swp := <-sinkUnderlying:
+				scraper.processSwap(pool, (*curvepool.CurvepoolTokenExchange)(swp), true)
+			case vLog := <-sinkCrypto:
+				swp := &curvepool.CurvepoolTokenExchange{Raw: vLog}
+				err := cryptoContract.UnpackLog(swp, "TokenExchange", vLog)
+				if err != nil {
+					log.Error("unpack crypto swap: ", err)
+					continue
+				}
+				scraper.processSwap(pool, swp, false)
 			}
 		}
 	}()
-	return err
+	return nil
 
 }
 
-// getSwapDataCurve returns the foreign name, volume and price of a swap
-func (scraper *CurveFIScraper) getSwapDataCurve(pool string, s *curvepool.CurvepoolTokenExchange) (foreignName string, volume float64, price float64, err error) {
+// getSwapDataCurve returns the foreign name, volume and price of a swap of the coins of a pool,
+// or of its underlying coins if @underlying is true. The volume of the pool is updated.
+func (scraper *CurveFIScraper) getSwapDataCurve(pool string, s *curvepool.CurvepoolTokenExchange, underlying bool) (foreignName string, volume float64, price float64, err error) {
 
-	// fromToken, _ := scraper.curveCoins[s.TokenSold.Hex()]
-	// toToken, _ := scraper.curveCoins[s.TokenBought.Hex()]
-
-	fromToken, ok := scraper.pools.getPoolCoin(pool, int(s.SoldId.Int64()))
+	fromToken, ok := scraper.pools.getPoolCoin(pool, int(s.SoldId.Int64()), underlying)
 	if !ok {
 		err = fmt.Errorf("token not found: " + pool + "-" + s.SoldId.String())
+		return
 	}
-	toToken, ok := scraper.pools.getPoolCoin(pool, int(s.BoughtId.Int64()))
+	toToken, ok := scraper.pools.getPoolCoin(pool, int(s.BoughtId.Int64()), underlying)
 	if !ok {
-		err = fmt.Errorf("token not found: " + pool + "-" + s.SoldId.String())
+		err = fmt.Errorf("token not found: " + pool + "-" + s.BoughtId.String())
+		return
 	}
 
 	// amountIn := s.AmountSold. / math.Pow10( fromToken.Decimals )
@@ -370,15 +601,71 @@ func (scraper *CurveFIScraper) getSwapDataCurve(pool string, s *curvepool.Curvep
 
 	// amountOut := s.AmountBought / math.Pow10( toToken.Decimals )
 	amountOut, _ := new(big.Float).Quo(big.NewFloat(0).SetInt(s.TokensBought), new(big.Float).SetFloat64(math.Pow10(int(toToken.Decimals)))).Float64()
+	if amountIn == 0 || amountOut == 0 {
+		err = fmt.Errorf("empty swap %s in pool %s", s.Raw.TxHash.Hex(), pool)
+		return
+	}
 
 	volume = amountOut
 	price = amountIn / amountOut
 
 	foreignName = toToken.Symbol + "-" + fromToken.Symbol
 
+	scraper.addPoolVolume(pool, fromToken.Symbol, amountIn)
 	return
 }
 
+// addPoolVolume adds @amount of the coin @symbol sold to @pool to its volume.
+func (scraper *CurveFIScraper) addPoolVolume(pool string, symbol string, amount float64) {
+	scraper.volumesLock.Lock()
+	defer scraper.volumesLock.Unlock()
+	volume, ok := scraper.volumes[pool]
+	if !ok {
+		volume = &dia.PoolVolume{
+			Exchange:  scraper.exchangeName,
+			Pool:      pool,
+			Volumes:   make(map[string]float64),
+			StartTime: time.Now(),
+		}
+		scraper.volumes[pool] = volume
+	}
+	volume.Volumes[symbol] += amount
+	volume.Trades++
+}
+
+// reportPoolVolumes sends the volumes of all pools with swaps on chanPoolVolumes every
+// curvePoolVolumeInterval and starts new ones.
+func (scraper *CurveFIScraper) reportPoolVolumes() {
+	ticker := time.NewTicker(curvePoolVolumeInterval)
+	defer ticker.Stop()
+	for scraper.run {
+		select {
+		case <-ticker.C:
+			scraper.volumesLock.Lock()
+			volumes := scraper.volumes
+			scraper.volumes = make(map[string]*dia.PoolVolume)
+			scraper.volumesLock.Unlock()
+
+			now := time.Now()
+			for _, volume := range volumes {
+				volume.EndTime = now
+				select {
+				case scraper.chanPoolVolumes <- volume:
+				case <-scraper.shutdown:
+					return
+				}
+			}
+		case <-scraper.shutdown:
+			return
+		}
+	}
+}
+
+// PoolVolumeChannel implements PoolVolumeScraper.
+func (scraper *CurveFIScraper) PoolVolumeChannel() chan *dia.PoolVolume {
+	return scraper.chanPoolVolumes
+}
+
 func (scraper *CurveFIScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
 
 	pairSet := make(map[string]struct{})
@@ -46,6 +46,8 @@ type DforceScraper struct {
 	pairScrapers   map[string]*DforcePairScraper
 	productPairIds map[string]int
 	chanTrades     chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 
 	WsClient    *ethclient.Client
 	RestClient  *ethclient.Client
@@ -78,6 +80,7 @@ func NewDforceScraper(exchange dia.Exchange) *DforceScraper {
 		log.Fatal(err)
 	}
 	scraper.RestClient = restClient
+	scraper.confirmations = newConfirmationBuffer(restClient, scraper.chanTrades, scraper.shutdown)
 
 	scraper.loadTokens()
 
@@ -195,6 +198,11 @@ func (scraper *DforceScraper) subscribeToTrades() error {
 }
 
 func (scraper *DforceScraper) processTrade(trade *dforce.DforceSwap) {
+	if trade.Raw.Removed {
+		scraper.confirmations.Retract(trade.Raw)
+		return
+	}
+	raw := trade.Raw
 	symbol, foreignName, volume, price, err := scraper.getSwapDataDforce(trade)
 	timestamp := time.Now().Unix()
 	if err != nil {
@@ -211,7 +219,7 @@ func (scraper *DforceScraper) processTrade(trade *dforce.DforceSwap) {
 				ForeignTradeID: "",
 				Source:         scraper.exchangeName,
 			}
-			pairScraper.parent.chanTrades <- trade
+			pairScraper.parent.confirmations.Add(raw, trade)
 			fmt.Println("got trade: ", trade)
 		}
 	}
@@ -0,0 +1,90 @@
+package scrapers
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const dydxIndexerURL = "https://indexer.dydx.trade/v4"
+
+// dydxAPI is the indexer API of the dYdX v4 chain, whose orderbook is kept by its validators.
+type dydxAPI struct {
+	exchangeName string
+}
+
+// NewDydxScraper returns a scraper of the perpetual markets of dYdX v4.
+func NewDydxScraper(exchange dia.Exchange) *PollingScraper {
+	return newPollingScraper(exchange, &dydxAPI{exchangeName: exchange.Name}, defaultPollInterval)
+}
+
+type dydxMarkets struct {
+	Markets map[string]struct {
+		Ticker string `json:"ticker"`
+		Status string `json:"status"`
+	} `json:"markets"`
+}
+
+type dydxTrades struct {
+	Trades []struct {
+		ID        string    `json:"id"`
+		Side      string    `json:"side"`
+		Size      string    `json:"size"`
+		Price     string    `json:"price"`
+		CreatedAt time.Time `json:"createdAt"`
+	} `json:"trades"`
+}
+
+// fetchPairs returns the active perpetual markets, e.g. BTC-USD.
+func (api *dydxAPI) fetchPairs() (pairs []dia.Pair, err error) {
+	var markets dydxMarkets
+	err = getPollingJSON(api.exchangeName, dydxIndexerURL+"/perpetualMarkets", &markets)
+	if err != nil {
+		return
+	}
+	for ticker, market := range markets.Markets {
+		if market.Status != "ACTIVE" {
+			continue
+		}
+		pairs = append(pairs, dia.Pair{
+			Symbol:      strings.Split(ticker, "-")[0],
+			ForeignName: ticker,
+			Exchange:    api.exchangeName,
+		})
+	}
+	return
+}
+
+func (api *dydxAPI) fetchTrades(foreignName string) ([]dia.Trade, error) {
+	var response dydxTrades
+	err := getPollingJSON(api.exchangeName, dydxIndexerURL+"/trades/perpetualMarket/"+url.PathEscape(foreignName)+"?limit=100", &response)
+	if err != nil {
+		return nil, err
+	}
+	var trades []dia.Trade
+	for _, t := range response.Trades {
+		price, err := strconv.ParseFloat(t.Price, 64)
+		if err != nil {
+			log.Error("error parsing price: " + t.Price)
+			continue
+		}
+		volume, err := strconv.ParseFloat(t.Size, 64)
+		if err != nil {
+			log.Error("error parsing volume: " + t.Size)
+			continue
+		}
+		if t.Side == "SELL" {
+			volume = -volume
+		}
+		trades = append(trades, dia.Trade{
+			Price:          price,
+			Volume:         volume,
+			Time:           t.CreatedAt,
+			ForeignTradeID: t.ID,
+		})
+	}
+	return trades, nil
+}
@@ -10,8 +10,8 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _GateIOsocketurl string = "wss://api.gateio.ws/ws/v4/"
@@ -30,7 +30,7 @@ type SubscribeGate struct {
 }
 
 type GateIOScraper struct {
-	wsClient *ws.Conn
+	conn *wsHelper.Connection
 	// signaling channels for session initialization and finishing
 	//initDone     chan nothing
 	shutdown     chan nothing
@@ -41,9 +41,12 @@ type GateIOScraper struct {
 	error     error
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
-	pairScrapers map[string]*GateIOPairScraper
-	exchangeName string
-	chanTrades   chan *dia.Trade
+	pairScrapers     map[string]*GateIOPairScraper
+	pairScrapersLock sync.RWMutex
+	// currencyPairs are all pairs of Gate.io, whose trades are subscribed to
+	currencyPairs []string
+	exchangeName  string
+	chanTrades    chan *dia.Trade
 }
 
 // NewGateIOScraper returns a new GateIOScraper for the given pair
@@ -58,14 +61,11 @@ func NewGateIOScraper(exchange dia.Exchange) *GateIOScraper {
 		chanTrades:   make(chan *dia.Trade),
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_GateIOsocketurl, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:      exchange.Name,
+		URL:       _GateIOsocketurl,
+		Subscribe: s.subscribeToALL,
+	})
 	go s.mainLoop()
 	return s
 }
@@ -116,29 +116,28 @@ func (s *GateIOScraper) mainLoop() {
 	}
 
 	for _, v := range gresponse {
-
-		a := &SubscribeGate{
-			Event:   "subscribe",
-			Time:    time.Now().Unix(),
-			Channel: "spot.trades",
-			Payload: []string{v.ID},
-		}
-		log.Infof("Subscribed for Pair %v", v.ID)
-		if err = s.wsClient.WriteJSON(a); err != nil {
-			log.Error(err.Error())
-		}
+		s.currencyPairs = append(s.currencyPairs, v.ID)
+	}
+	if err = s.subscribeToALL(s.conn); err != nil {
+		log.Error(err.Error())
 	}
 
 	for {
 
 		var message GateIOResponseTrade
-		if err = s.wsClient.ReadJSON(&message); err != nil {
+		if err = s.conn.ReadJSON(&message); err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
 			log.Error(err.Error())
-			break
+			continue
 		}
 
+		s.pairScrapersLock.RLock()
 		ps, ok := s.pairScrapers[message.Result.CurrencyPair]
+		s.pairScrapersLock.RUnlock()
 		if ok {
+			s.conn.Received(ps.pair.ForeignName)
 
 			f64Price, err = strconv.ParseFloat(message.Result.Price, 64)
 			if err != nil {
@@ -174,6 +173,24 @@ func (s *GateIOScraper) mainLoop() {
 	s.cleanup(err)
 }
 
+// subscribeToALL subscribes to the trades of all pairs of Gate.io, again after reconnecting.
+// It is called from mainLoop only.
+func (s *GateIOScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	for _, currencyPair := range s.currencyPairs {
+		a := &SubscribeGate{
+			Event:   "subscribe",
+			Time:    time.Now().Unix(),
+			Channel: "spot.trades",
+			Payload: []string{currencyPair},
+		}
+		log.Infof("Subscribed for Pair %v", currencyPair)
+		if err := conn.WriteJSON(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *GateIOScraper) cleanup(err error) {
 	s.errorLock.Lock()
 	defer s.errorLock.Unlock()
@@ -193,7 +210,7 @@ func (s *GateIOScraper) Close() error {
 	if s.closed {
 		return errors.New("GateIOScraper: Already closed")
 	}
-	s.wsClient.Close()
+	s.conn.Close()
 	close(s.shutdown)
 	<-s.shutdownDone
 	s.errorLock.RLock()
@@ -221,7 +238,9 @@ func (s *GateIOScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		pair:   pair,
 	}
 
+	s.pairScrapersLock.Lock()
 	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
 
 	return ps, nil
 }
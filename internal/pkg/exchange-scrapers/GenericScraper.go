@@ -0,0 +1,413 @@
+package scrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
+	"gopkg.in/yaml.v2"
+)
+
+// GenericScraperConfig describes an exchange whose trades are scraped by the generic REST,
+// websocket or CCXT scraper, so that simple exchanges need no Go code. It is read from
+// config/generic-scrapers/<Name>.yaml. Paths address values in json documents, see jsonPath.
+type GenericScraperConfig struct {
+	Name        string `yaml:"name"`
+	Centralized bool   `yaml:"centralized"`
+	// WatchdogDelay is the number of seconds without trades after which the collector restarts.
+	WatchdogDelay int                     `yaml:"watchdogDelay"`
+	Pairs         GenericPairsConfig      `yaml:"pairs"`
+	REST          *GenericRESTConfig      `yaml:"rest"`
+	Websocket     *GenericWebsocketConfig `yaml:"websocket"`
+	// CCXT exchanges list their pairs through the CCXT gateway, Pairs is unused.
+	CCXT *GenericCCXTConfig `yaml:"ccxt"`
+}
+
+// GenericPairsConfig describes the endpoint listing the pairs of an exchange.
+type GenericPairsConfig struct {
+	URL string `yaml:"url"`
+	// List is the path of the array of pairs in the response.
+	List        string `yaml:"list"`
+	ForeignName string `yaml:"foreignName"`
+	// Symbol is the path of the base asset. If SymbolSeparator is set, the value is split and
+	// its first part is the symbol, e.g. BTC of BTC/USD.
+	Symbol          string `yaml:"symbol"`
+	SymbolSeparator string `yaml:"symbolSeparator"`
+	// Only pairs whose value at Filter is FilterValue are listed, e.g. status: online.
+	Filter      string `yaml:"filter"`
+	FilterValue string `yaml:"filterValue"`
+}
+
+// GenericTradesConfig describes the trades in a response or message.
+type GenericTradesConfig struct {
+	// List is the path of the array of trades. If it is a single object, it is one trade.
+	List      string `yaml:"list"`
+	Price     string `yaml:"price"`
+	Volume    string `yaml:"volume"`
+	Timestamp string `yaml:"timestamp"`
+	// TimestampUnit is one of s, ms, us, ns for numeric timestamps or rfc3339. Trades without
+	// timestamp are received now.
+	TimestampUnit string `yaml:"timestampUnit"`
+	ID            string `yaml:"id"`
+	// The volume of trades whose value at Side is SellValue, compared case insensitively, is
+	// negated. Without Side, the volume is taken as is.
+	Side      string `yaml:"side"`
+	SellValue string `yaml:"sellValue"`
+}
+
+// GenericRESTConfig describes the endpoint of the latest trades of a pair.
+type GenericRESTConfig struct {
+	// URL contains {pair}, which is replaced by the foreign name of the pair.
+	URL string `yaml:"url"`
+	// Interval is the time between two requests of the trades of a pair.
+	Interval time.Duration `yaml:"interval"`
+	// RequestsPerSecond limits the requests to the API. If 0, the limit of the exchange or the
+	// default limit applies.
+	RequestsPerSecond float64             `yaml:"requestsPerSecond"`
+	Trades            GenericTradesConfig `yaml:"trades"`
+}
+
+// GenericWebsocketConfig describes the trade stream of an exchange.
+type GenericWebsocketConfig struct {
+	URL string `yaml:"url"`
+	// Subscribe is the message subscribing to the trades of a pair. It contains {pair}, which
+	// is replaced by the foreign name of the pair.
+	Subscribe string `yaml:"subscribe"`
+	// Only messages whose value at Filter is FilterValue contain trades, e.g. event: trade.
+	Filter      string `yaml:"filter"`
+	FilterValue string `yaml:"filterValue"`
+	// Pair is the path of the foreign name of a trade, looked up in the trade and then in the
+	// message. PairPrefix is trimmed from it, e.g. live_trades_ from live_trades_btcusd.
+	Pair       string `yaml:"pair"`
+	PairPrefix string `yaml:"pairPrefix"`
+	// Ping is sent every PingInterval to keep the connection alive, if set.
+	Ping         string              `yaml:"ping"`
+	PingInterval time.Duration       `yaml:"pingInterval"`
+	Trades       GenericTradesConfig `yaml:"trades"`
+}
+
+// LoadGenericScraperConfig reads the generic scraper config of @exchange. The error satisfies
+// os.IsNotExist if there is none.
+func LoadGenericScraperConfig(exchange string) (*GenericScraperConfig, error) {
+	data, err := ioutil.ReadFile(configCollectors.ConfigFileConnectors("generic-scrapers/"+exchange, ".yaml"))
+	if err != nil {
+		return nil, err
+	}
+	return parseGenericScraperConfig(data)
+}
+
+func parseGenericScraperConfig(data []byte) (*GenericScraperConfig, error) {
+	var config GenericScraperConfig
+	err := yaml.UnmarshalStrict(data, &config)
+	if err != nil {
+		return nil, err
+	}
+	if config.Name == "" {
+		return nil, errors.New("generic scraper config needs name")
+	}
+	if config.WatchdogDelay == 0 {
+		config.WatchdogDelay = watchdogDelay
+	}
+	if config.CCXT != nil {
+		if config.CCXT.URL == "" {
+			return nil, fmt.Errorf("%s: ccxt needs url", config.Name)
+		}
+		if config.CCXT.Exchange == "" {
+			config.CCXT.Exchange = strings.ToLower(config.Name)
+		}
+		if config.CCXT.Markets == "" {
+			config.CCXT.Markets = ccxtMarketsRoute
+		}
+		if config.CCXT.Trades == "" {
+			config.CCXT.Trades = ccxtTradesRoute
+		}
+		if config.CCXT.Ticker == "" {
+			config.CCXT.Ticker = ccxtTickerRoute
+		}
+		if config.CCXT.Interval == 0 {
+			config.CCXT.Interval = defaultPollInterval
+		}
+		return &config, nil
+	}
+	if config.Pairs.URL == "" || config.Pairs.ForeignName == "" {
+		return nil, errors.New("generic scraper config needs pairs.url and pairs.foreignName")
+	}
+	var trades GenericTradesConfig
+	switch {
+	case config.Websocket != nil:
+		if config.Websocket.URL == "" || config.Websocket.Subscribe == "" || config.Websocket.Pair == "" {
+			return nil, fmt.Errorf("%s: websocket needs url, subscribe and pair", config.Name)
+		}
+		trades = config.Websocket.Trades
+	case config.REST != nil:
+		if !strings.Contains(config.REST.URL, "{pair}") {
+			return nil, fmt.Errorf("%s: rest.url needs {pair}", config.Name)
+		}
+		if config.REST.Interval == 0 {
+			config.REST.Interval = defaultPollInterval
+		}
+		trades = config.REST.Trades
+	default:
+		return nil, fmt.Errorf("%s: either rest, websocket or ccxt is needed", config.Name)
+	}
+	if trades.Price == "" || trades.Volume == "" {
+		return nil, fmt.Errorf("%s: trades need price and volume", config.Name)
+	}
+	switch trades.TimestampUnit {
+	case "", "s", "ms", "us", "ns", "rfc3339":
+	default:
+		return nil, fmt.Errorf("%s: unknown timestamp unit %s", config.Name, trades.TimestampUnit)
+	}
+	return &config, nil
+}
+
+// NewGenericScraper returns the websocket scraper of the exchange described by @config if it
+// has a websocket, a scraper polling a CCXT gateway if it has ccxt and a REST scraper otherwise.
+// The exchange is added to Exchanges.
+func NewGenericScraper(config *GenericScraperConfig) APIScraper {
+	exchange := dia.Exchange{Name: config.Name, Centralized: config.Centralized, WatchdogDelay: config.WatchdogDelay}
+	Exchanges[config.Name] = exchange
+	if config.CCXT != nil {
+		if config.CCXT.RequestsPerSecond > 0 {
+			setRateLimit(config.Name, rateLimit{Rate: config.CCXT.RequestsPerSecond, Burst: 1})
+		}
+		return newPollingScraper(exchange, newCCXTAPI(config.Name, config.CCXT), config.CCXT.Interval)
+	}
+	if config.Websocket != nil {
+		return newGenericWebsocketScraper(exchange, config)
+	}
+	if config.REST.RequestsPerSecond > 0 {
+		setRateLimit(config.Name, rateLimit{Rate: config.REST.RequestsPerSecond, Burst: 1})
+	}
+	return newPollingScraper(exchange, &genericRESTAPI{config: config}, config.REST.Interval)
+}
+
+// genericRESTAPI implements pollingAPI for a GenericScraperConfig.
+type genericRESTAPI struct {
+	config *GenericScraperConfig
+}
+
+func (api *genericRESTAPI) fetchPairs() ([]dia.Pair, error) {
+	return fetchGenericPairs(api.config)
+}
+
+func (api *genericRESTAPI) fetchTrades(foreignName string) ([]dia.Trade, error) {
+	doc, err := getGenericJSON(api.config.Name, strings.Replace(api.config.REST.URL, "{pair}", foreignName, -1))
+	if err != nil {
+		return nil, err
+	}
+	return parseGenericTrades(doc, api.config.REST.Trades, "", "")
+}
+
+// fetchGenericPairs returns the pairs listed by the exchange described by @config.
+func fetchGenericPairs(config *GenericScraperConfig) (pairs []dia.Pair, err error) {
+	doc, err := getGenericJSON(config.Name, config.Pairs.URL)
+	if err != nil {
+		return
+	}
+	list, ok := jsonPath(doc, config.Pairs.List)
+	if !ok {
+		return nil, fmt.Errorf("%s: no pairs at %s", config.Name, config.Pairs.List)
+	}
+	items, ok := list.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: pairs at %s are no array", config.Name, config.Pairs.List)
+	}
+	for _, item := range items {
+		if config.Pairs.Filter != "" {
+			value, ok := jsonPath(item, config.Pairs.Filter)
+			if !ok || jsonString(value) != config.Pairs.FilterValue {
+				continue
+			}
+		}
+		foreignName, ok := jsonPath(item, config.Pairs.ForeignName)
+		if !ok {
+			continue
+		}
+		symbol, _ := jsonPath(item, config.Pairs.Symbol)
+		pair := dia.Pair{
+			Symbol:      strings.ToUpper(jsonString(symbol)),
+			ForeignName: jsonString(foreignName),
+			Exchange:    config.Name,
+		}
+		if config.Pairs.SymbolSeparator != "" {
+			pair.Symbol = strings.Split(pair.Symbol, config.Pairs.SymbolSeparator)[0]
+		}
+		pairs = append(pairs, pair)
+	}
+	return
+}
+
+// parseGenericTrades returns the trades described by @config in @doc. If @pairPath is set, the
+// Pair of the trades is the foreign name at @pairPath without @pairPrefix.
+func parseGenericTrades(doc interface{}, config GenericTradesConfig, pairPath string, pairPrefix string) ([]dia.Trade, error) {
+	list, ok := jsonPath(doc, config.List)
+	if !ok {
+		return nil, nil
+	}
+	items, ok := list.([]interface{})
+	if !ok {
+		items = []interface{}{list}
+	}
+
+	var trades []dia.Trade
+	for _, item := range items {
+		var trade dia.Trade
+		value, _ := jsonPath(item, config.Price)
+		price, err := jsonFloat(value)
+		if err != nil {
+			return nil, fmt.Errorf("price: %v", err)
+		}
+		value, _ = jsonPath(item, config.Volume)
+		volume, err := jsonFloat(value)
+		if err != nil {
+			return nil, fmt.Errorf("volume: %v", err)
+		}
+		if config.Side != "" {
+			volume = math.Abs(volume)
+			if side, ok := jsonPath(item, config.Side); ok && strings.EqualFold(jsonString(side), config.SellValue) {
+				volume = -volume
+			}
+		}
+		trade.Price = price
+		trade.Volume = volume
+
+		trade.Time = time.Now()
+		if config.Timestamp != "" {
+			value, _ = jsonPath(item, config.Timestamp)
+			trade.Time, err = jsonTime(value, config.TimestampUnit)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp: %v", err)
+			}
+		}
+		if config.ID != "" {
+			if id, ok := jsonPath(item, config.ID); ok {
+				trade.ForeignTradeID = jsonString(id)
+			}
+		}
+		if pairPath != "" {
+			pair, ok := jsonPath(item, pairPath)
+			if !ok {
+				pair, ok = jsonPath(doc, pairPath)
+			}
+			if !ok {
+				return nil, fmt.Errorf("no pair at %s", pairPath)
+			}
+			trade.Pair = strings.TrimPrefix(jsonString(pair), pairPrefix)
+		}
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+// getGenericJSON returns the json response of a GET request to @url of @exchange. Numbers are
+// decoded as json.Number, so that ids keep all their digits.
+func getGenericJSON(exchange string, url string) (interface{}, error) {
+	data, err := requestSchedulerFor(exchange).Get(url)
+	if err != nil {
+		return nil, err
+	}
+	return decodeGenericJSON(data)
+}
+
+func decodeGenericJSON(data []byte) (interface{}, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(&doc)
+	return doc, err
+}
+
+// jsonPath returns the value at @path in @doc, a json document decoded into interface{}.
+// Segments of the path are separated by dots, numeric segments index arrays, e.g. data.0.price.
+// The empty path is @doc itself.
+func jsonPath(doc interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return doc, doc != nil
+	}
+	for _, segment := range strings.Split(path, ".") {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			value, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			doc = value
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			doc = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, doc != nil
+}
+
+// jsonString returns the string, number or bool @v as string.
+func jsonString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return ""
+	}
+}
+
+// jsonFloat returns the number or numeric string @v as float.
+func jsonFloat(v interface{}) (float64, error) {
+	switch value := v.(type) {
+	case json.Number:
+		return value.Float64()
+	case float64:
+		return value, nil
+	case string:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return 0, fmt.Errorf("%v is no number", v)
+	}
+}
+
+// jsonTime returns the timestamp @v in @unit, see GenericTradesConfig.TimestampUnit.
+func jsonTime(v interface{}, unit string) (time.Time, error) {
+	if unit == "rfc3339" {
+		return time.Parse(time.RFC3339Nano, jsonString(v))
+	}
+	var scale time.Duration
+	switch unit {
+	case "ms":
+		scale = time.Millisecond
+	case "us":
+		scale = time.Microsecond
+	case "ns":
+		scale = time.Nanosecond
+	default:
+		scale = time.Second
+	}
+	// Integers are converted exactly, as microseconds and nanoseconds exceed the precision of
+	// floats.
+	if timestamp, err := strconv.ParseInt(jsonString(v), 10, 64); err == nil {
+		return time.Unix(0, timestamp*int64(scale)), nil
+	}
+	timestamp, err := jsonFloat(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(timestamp*float64(scale))), nil
+}
@@ -0,0 +1,59 @@
+package scrapers
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseGenericTrades(t *testing.T) {
+	data, err := ioutil.ReadFile("../../../config/generic-scrapers/Bitstamp.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := parseGenericScraperConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Websocket == nil || config.WatchdogDelay != watchdogDelay {
+		t.Fatalf("unexpected config %+v", config)
+	}
+
+	doc, err := decodeGenericJSON([]byte(`{"data": {"id": 312553413, "timestamp": "1700000000", "amount": 0.02,
+		"amount_str": "0.02000000", "price": 37000, "price_str": "37000", "type": 1,
+		"microtimestamp": "1700000000123456"}, "channel": "live_trades_btcusd", "event": "trade"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trades, err := parseGenericTrades(doc, config.Websocket.Trades, config.Websocket.Pair, config.Websocket.PairPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 {
+		t.Fatalf("expected 1 trade, got %v", trades)
+	}
+	trade := trades[0]
+	if trade.Pair != "btcusd" || trade.Price != 37000 || trade.Volume != -0.02 || trade.ForeignTradeID != "312553413" {
+		t.Errorf("unexpected trade %v", trade)
+	}
+	if !trade.Time.Equal(time.Unix(1700000000, 123456000)) {
+		t.Errorf("unexpected time %v", trade.Time)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	doc, err := decodeGenericJSON([]byte(`{"result": {"trades": [{"p": "1.5"}, {"p": 2}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, ok := jsonPath(doc, "result.trades.1.p")
+	if !ok || jsonString(value) != "2" {
+		t.Errorf("expected 2, got %v", value)
+	}
+	if _, ok := jsonPath(doc, "result.trades.2.p"); ok {
+		t.Error("expected no value out of range")
+	}
+	if _, ok := jsonPath(doc, "result.price"); ok {
+		t.Error("expected no value for missing key")
+	}
+}
@@ -0,0 +1,221 @@
+package scrapers
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
+)
+
+// GenericWebsocketScraper streams the trades of an exchange described by a
+// GenericScraperConfig.
+type GenericWebsocketScraper struct {
+	config         *GenericWebsocketConfig
+	exchangeConfig *GenericScraperConfig
+	conn           *wsHelper.Connection
+	// signaling channels for session initialization and finishing
+	run          bool
+	shutdown     chan nothing
+	shutdownDone chan nothing
+	// error handling; to read error or closed, first acquire read lock
+	// only cleanup method should hold write lock
+	errorLock sync.RWMutex
+	error     error
+	closed    bool
+	// used to keep track of trading pairs that we subscribed to
+	pairScrapers     map[string]*GenericWebsocketPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
+}
+
+func newGenericWebsocketScraper(exchange dia.Exchange, config *GenericScraperConfig) *GenericWebsocketScraper {
+	s := &GenericWebsocketScraper{
+		config:         config.Websocket,
+		exchangeConfig: config,
+		run:            true,
+		shutdown:       make(chan nothing),
+		shutdownDone:   make(chan nothing),
+		pairScrapers:   make(map[string]*GenericWebsocketPairScraper),
+		exchangeName:   exchange.Name,
+		chanTrades:     make(chan *dia.Trade),
+	}
+
+	wsConfig := wsHelper.Config{
+		Name:      exchange.Name,
+		URL:       s.config.URL,
+		Subscribe: s.subscribeToALL,
+	}
+	if s.config.Ping != "" {
+		wsConfig.Ping = s.ping
+		wsConfig.PingInterval = s.config.PingInterval
+	}
+	s.conn = wsHelper.Dial(wsConfig)
+
+	go s.mainLoop()
+	return s
+}
+
+// runs in a goroutine until s is closed
+func (s *GenericWebsocketScraper) mainLoop() {
+	for s.run {
+		message, err := s.conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		doc, err := decodeGenericJSON(message)
+		if err != nil {
+			// e.g. answers to pings in plain text
+			continue
+		}
+		if s.config.Filter != "" {
+			value, ok := jsonPath(doc, s.config.Filter)
+			if !ok || jsonString(value) != s.config.FilterValue {
+				continue
+			}
+		}
+		trades, err := parseGenericTrades(doc, s.config.Trades, s.config.Pair, s.config.PairPrefix)
+		if err != nil {
+			log.Errorf("%s: parse trades: %v", s.exchangeName, err)
+			continue
+		}
+		for i := range trades {
+			trade := trades[i]
+			s.pairScrapersLock.RLock()
+			ps, ok := s.pairScrapers[trade.Pair]
+			s.pairScrapersLock.RUnlock()
+			if !ok {
+				continue
+			}
+			s.conn.Received(ps.pair.ForeignName)
+			trade.Symbol = ps.pair.Symbol
+			trade.Pair = ps.pair.ForeignName
+			trade.Source = s.exchangeName
+			log.Info("got trade: ", trade)
+			s.chanTrades <- &trade
+		}
+	}
+	s.cleanup(errors.New(s.exchangeName + "Scraper: terminated by Close()"))
+}
+
+// ping keeps the connection alive.
+func (s *GenericWebsocketScraper) ping(conn *wsHelper.Connection) error {
+	return conn.WriteMessage([]byte(s.config.Ping))
+}
+
+// Subscribe again to the trades of all scraped pairs
+func (s *GenericWebsocketScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	var foreignNames []string
+	for _, ps := range s.pairScrapers {
+		foreignNames = append(foreignNames, ps.pair.ForeignName)
+	}
+	s.pairScrapersLock.RUnlock()
+
+	for _, foreignName := range foreignNames {
+		if err := s.subscribe(conn, foreignName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GenericWebsocketScraper) subscribe(conn *wsHelper.Connection, foreignName string) error {
+	return conn.WriteMessage([]byte(strings.Replace(s.config.Subscribe, "{pair}", foreignName, -1)))
+}
+
+// Close channels for shutdown
+func (s *GenericWebsocketScraper) cleanup(err error) {
+	s.errorLock.Lock()
+	defer s.errorLock.Unlock()
+	if err != nil {
+		s.error = err
+	}
+	s.closed = true
+	close(s.shutdownDone)
+}
+
+// Close closes any existing API connections, as well as channels of
+// PairScrapers from calls to ScrapePair
+func (s *GenericWebsocketScraper) Close() error {
+	if s.closed {
+		return errors.New(s.exchangeName + "Scraper: Already closed")
+	}
+	// Set false first to prevent reconnect
+	s.run = false
+	close(s.shutdown)
+	s.conn.Close()
+	<-s.shutdownDone
+	s.errorLock.RLock()
+	defer s.errorLock.RUnlock()
+	return s.error
+}
+
+// ScrapePair returns a PairScraper that can be used to get trades for a single pair from
+// this APIScraper
+func (s *GenericWebsocketScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
+	s.errorLock.RLock()
+	defer s.errorLock.RUnlock()
+	if s.error != nil {
+		return nil, s.error
+	}
+	if s.closed {
+		return nil, errors.New(s.exchangeName + "Scraper: Call ScrapePair on closed scraper")
+	}
+	ps := &GenericWebsocketPairScraper{
+		parent: s,
+		pair:   pair,
+	}
+
+	s.pairScrapersLock.Lock()
+	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
+
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, pair.ForeignName); err != nil {
+		log.Error("subscribe: ", err)
+	}
+	return ps, nil
+}
+
+// FetchAvailablePairs returns a list with all available trade pairs
+func (s *GenericWebsocketScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
+	return fetchGenericPairs(s.exchangeConfig)
+}
+
+func (s *GenericWebsocketScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
+	return pair, nil
+}
+
+// Channel returns the channel to get trades
+func (s *GenericWebsocketScraper) Channel() chan *dia.Trade {
+	return s.chanTrades
+}
+
+// GenericWebsocketPairScraper implements PairScraper for a GenericWebsocketScraper
+type GenericWebsocketPairScraper struct {
+	parent *GenericWebsocketScraper
+	pair   dia.Pair
+	closed bool
+}
+
+// Close stops listening for trades of the pair associated
+func (ps *GenericWebsocketPairScraper) Close() error {
+	ps.closed = true
+	return nil
+}
+
+// Error returns an error when the channel Channel() is closed
+// and nil otherwise
+func (ps *GenericWebsocketPairScraper) Error() error {
+	ps.parent.errorLock.RLock()
+	defer ps.parent.errorLock.RUnlock()
+	return ps.parent.error
+}
+
+// Pair returns the pair this scraper is subscribed to
+func (ps *GenericWebsocketPairScraper) Pair() dia.Pair {
+	return ps.pair
+}
@@ -0,0 +1,48 @@
+package scrapers
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/recordHelper"
+)
+
+func TestGenericWebsocketScraperReplay(t *testing.T) {
+	recording, err := recordHelper.Load("testdata/Bitstamp.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordHelper.Replay(recording)
+	defer recordHelper.StopReplay()
+
+	data, err := ioutil.ReadFile("../../../config/generic-scrapers/Bitstamp.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := parseGenericScraperConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewGenericScraper(config)
+	if _, err := s.ScrapePair(dia.Pair{Symbol: "BTC", ForeignName: "btcusd"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []dia.Trade{
+		{Symbol: "BTC", Pair: "btcusd", Price: 37000, Volume: 0.02, Time: time.Unix(1700000000, 123456000), ForeignTradeID: "312553413", Source: "Bitstamp"},
+		{Symbol: "BTC", Pair: "btcusd", Price: 36998, Volume: -0.5, Time: time.Unix(1700000031, 500000000), ForeignTradeID: "312553420", Source: "Bitstamp"},
+	}
+	for _, e := range expected {
+		select {
+		case trade := <-s.Channel():
+			if trade.Symbol != e.Symbol || trade.Pair != e.Pair || trade.Price != e.Price || trade.Volume != e.Volume ||
+				!trade.Time.Equal(e.Time) || trade.ForeignTradeID != e.ForeignTradeID || trade.Source != e.Source {
+				t.Errorf("got trade %v, expected %v", trade, e)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no trade replayed, expected %v", e)
+		}
+	}
+}
@@ -46,6 +46,8 @@ type GnosisScraper struct {
 	pairScrapers   map[string]*GnosisPairScraper
 	productPairIds map[string]int
 	chanTrades     chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 
 	WsClient    *ethclient.Client
 	RestClient  *ethclient.Client
@@ -78,6 +80,7 @@ func NewGnosisScraper(exchange dia.Exchange) *GnosisScraper {
 		log.Fatal(err)
 	}
 	scraper.RestClient = restClient
+	scraper.confirmations = newConfirmationBuffer(restClient, scraper.chanTrades, scraper.shutdown)
 
 	scraper.loadTokens()
 
@@ -173,6 +176,11 @@ func (scraper *GnosisScraper) subscribeToTrades() error {
 }
 
 func (scraper *GnosisScraper) processTrade(trade *gnosis.GnosisTrade) {
+	if trade.Raw.Removed {
+		scraper.confirmations.Retract(trade.Raw)
+		return
+	}
+	raw := trade.Raw
 	symbol, foreignName, volume, price, err := scraper.getSwapDataGnosis(trade)
 	timestamp := time.Now().Unix()
 	if err != nil {
@@ -189,7 +197,7 @@ func (scraper *GnosisScraper) processTrade(trade *gnosis.GnosisTrade) {
 				ForeignTradeID: "",
 				Source:         scraper.exchangeName,
 			}
-			pairScraper.parent.chanTrades <- trade
+			pairScraper.parent.confirmations.Add(raw, trade)
 			fmt.Println("got trade: ", trade)
 		}
 	}
@@ -3,7 +3,6 @@ package scrapers
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,8 +10,8 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _socketurl string = "wss://api.hitbtc.com/api/2/ws"
@@ -26,7 +25,7 @@ type Event struct {
 }
 
 type HitBTCScraper struct {
-	wsClient *ws.Conn
+	conn *wsHelper.Connection
 	// signaling channels for session initialization and finishing
 	shutdown     chan nothing
 	shutdownDone chan nothing
@@ -36,9 +35,10 @@ type HitBTCScraper struct {
 	error     error
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
-	pairScrapers map[string]*HitBTCPairScraper
-	exchangeName string
-	chanTrades   chan *dia.Trade
+	pairScrapers     map[string]*HitBTCPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
 }
 
 // NewHitBTCScraper returns a new HitBTCScraper for the given pair
@@ -53,14 +53,11 @@ func NewHitBTCScraper(exchange dia.Exchange) *HitBTCScraper {
 		chanTrades:   make(chan *dia.Trade),
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_socketurl, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:      exchange.Name,
+		URL:       _socketurl,
+		Subscribe: s.subscribeToALL,
+	})
 	go s.mainLoop()
 	return s
 }
@@ -70,14 +67,20 @@ func (s *HitBTCScraper) mainLoop() {
 	var err error
 	for true {
 		message := &Event{}
-		if err = s.wsClient.ReadJSON(&message); err != nil {
+		if err = s.conn.ReadJSON(&message); err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
 			log.Error(err.Error())
-			break
+			continue
 		}
 		if message.Method == "updateTrades" {
 			md := message.Params.(map[string]interface{})
+			s.pairScrapersLock.RLock()
 			ps, ok := s.pairScrapers[md["symbol"].(string)]
+			s.pairScrapersLock.RUnlock()
 			if ok {
+				s.conn.Received(ps.pair.ForeignName)
 				mdData := md["data"].([]interface{})
 				for _, v := range mdData {
 					mdElement := v.(map[string]interface{})
@@ -138,7 +141,7 @@ func (s *HitBTCScraper) Close() error {
 		return errors.New("HitBTCScraper: Already closed")
 	}
 	close(s.shutdown)
-	s.wsClient.Close()
+	s.conn.Close()
 	<-s.shutdownDone
 	s.errorLock.RLock()
 	defer s.errorLock.RUnlock()
@@ -165,21 +168,39 @@ func (s *HitBTCScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		pair:   pair,
 	}
 
+	s.pairScrapersLock.Lock()
 	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
+
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, pair.ForeignName); err != nil {
+		log.Errorf("subscribe %s: %v", pair.ForeignName, err)
+	}
+
+	return ps, nil
+}
 
+// subscribeToALL subscribes again to the trades of all scraped pairs.
+func (s *HitBTCScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	defer s.pairScrapersLock.RUnlock()
+	for foreignName := range s.pairScrapers {
+		if err := s.subscribe(conn, foreignName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *HitBTCScraper) subscribe(conn *wsHelper.Connection, foreignName string) error {
 	a := &Event{
 		Method: "subscribeTrades",
 		Params: map[string]interface{}{
-			"symbol": pair.ForeignName,
+			"symbol": foreignName,
 		},
 		Id: int(time.Now().Unix()) * 1000,
 	}
-
-	if err := s.wsClient.WriteJSON(a); err != nil {
-		fmt.Println(err.Error())
-	}
-
-	return ps, nil
+	return conn.WriteJSON(a)
 }
 func (s *HitBTCScraper) normalizeSymbol(foreignName string, baseCurrency string) (symbol string, err error) {
 	symbol = strings.ToUpper(baseCurrency)
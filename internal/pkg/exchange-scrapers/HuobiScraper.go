@@ -1,6 +1,7 @@
 package scrapers
 
 import (
+	"bytes"
 	"compress/gzip"
 	"encoding/json"
 	"errors"
@@ -12,8 +13,8 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _HuobiSocketurl string = "wss://api.huobi.pro/ws"
@@ -35,7 +36,7 @@ type ResponseType struct {
 }
 
 type HuobiScraper struct {
-	wsClient *ws.Conn
+	conn *wsHelper.Connection
 	// signaling channels for session initialization and finishing
 	//TODO: Channel not used. Consider removing or refactoring
 	shutdown     chan nothing
@@ -46,9 +47,10 @@ type HuobiScraper struct {
 	error     error
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
-	pairScrapers map[string]*HuobiPairScraper
-	exchangeName string
-	chanTrades   chan *dia.Trade
+	pairScrapers     map[string]*HuobiPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
 }
 
 // NewHuobiScraper returns a new HuobiScraper for the given pair
@@ -63,14 +65,11 @@ func NewHuobiScraper(exchange dia.Exchange) *HuobiScraper {
 		chanTrades:   make(chan *dia.Trade),
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_HuobiSocketurl, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:      exchange.Name,
+		URL:       _HuobiSocketurl,
+		Subscribe: s.subscribeToALL,
+	})
 	go s.mainLoop()
 	return s
 }
@@ -79,17 +78,15 @@ func NewHuobiScraper(exchange dia.Exchange) *HuobiScraper {
 func (s *HuobiScraper) mainLoop() {
 	for true {
 		message := &ResponseType{}
-		_, testRead, err := s.wsClient.NextReader()
+		testRead, err := s.conn.ReadMessage()
 
 		if err != nil {
-			// Conn errors are non-recoverable.
-			// Terminate the routine if theres any error
-			fmt.Println(err.Error())
+			// The connection is renewed on errors, until it is closed.
 			break
 		} else {
 
 			//It has to gzip response data
-			reader, _ := gzip.NewReader(testRead)
+			reader, _ := gzip.NewReader(bytes.NewReader(testRead))
 			jsonBase := json.NewDecoder(reader)
 			jsonBase.Decode(message)
 
@@ -101,11 +98,8 @@ func (s *HuobiScraper) mainLoop() {
 					Pong: message.Ping,
 				}
 
-				if err := s.wsClient.WriteJSON(a); err != nil {
-					// Conn errors are non-recoverable.
-					// Terminate the routine if theres any error
+				if err := s.conn.WriteJSON(a); err != nil {
 					fmt.Println(err.Error())
-					break
 				}
 			} else {
 
@@ -113,9 +107,12 @@ func (s *HuobiScraper) mainLoop() {
 
 					var splitString = strings.Split(message.Ch, ".")
 					var forName = strings.ToUpper(splitString[1])
+					s.pairScrapersLock.RLock()
 					ps, ok := s.pairScrapers[forName]
+					s.pairScrapersLock.RUnlock()
 
 					if ok {
+						s.conn.Received(ps.pair.ForeignName)
 
 						md := message.Tick.(map[string]interface{})
 						md_data := md["data"].([]interface{})
@@ -174,7 +171,7 @@ func (s *HuobiScraper) Close() error {
 	if s.closed {
 		return errors.New("HuobiScraper: Already closed")
 	}
-	s.wsClient.Close()
+	s.conn.Close()
 	close(s.shutdown)
 	<-s.shutdownDone
 	s.errorLock.RLock()
@@ -202,20 +199,38 @@ func (s *HuobiScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		pair:   pair,
 	}
 
+	s.pairScrapersLock.Lock()
 	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
 
-	a := &EventType{
-		Sub: "market." + strings.ToLower(pair.ForeignName) + ".trade.detail",
-		Id:  "id1",
-	}
-
-	if err := s.wsClient.WriteJSON(a); err != nil {
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, pair.ForeignName); err != nil {
 		fmt.Println(err.Error())
 	}
 
 	return ps, nil
 }
 
+// subscribeToALL subscribes again to the trades of all scraped pairs.
+func (s *HuobiScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	defer s.pairScrapersLock.RUnlock()
+	for foreignName := range s.pairScrapers {
+		if err := s.subscribe(conn, foreignName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *HuobiScraper) subscribe(conn *wsHelper.Connection, foreignName string) error {
+	a := &EventType{
+		Sub: "market." + strings.ToLower(foreignName) + ".trade.detail",
+		Id:  "id1",
+	}
+	return conn.WriteJSON(a)
+}
+
 
 func (s *HuobiScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
 	symbol := strings.ToUpper(pair.Symbol)
@@ -1,50 +1,80 @@
 package scrapers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
-	krakenapi "github.com/beldur/kraken-go-api-client"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/verificationHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
+	"github.com/diadata-org/diadata/pkg/utils"
 )
 
 const (
-	krakenRefreshDelay = time.Second * 30 * 1
+	krakenSocketURL     = "wss://ws.kraken.com/v2"
+	krakenAssetPairsURL = "https://api.kraken.com/0/public/AssetPairs"
+	// Kraken closes connections without messages for a minute.
+	krakenPingInterval = 30 * time.Second
 )
 
+// krakenSymbols are the assets which Kraken names differently from DIA. The websocket v2 API
+// already uses the DIA names, but the REST API and the pairs in the config don't.
+var krakenSymbols = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
 type KrakenScraper struct {
+	conn *wsHelper.Connection
+	run  bool
 	// signaling channels
 	shutdown     chan nothing
 	shutdownDone chan nothing
 	// error handling; to read error or closed, first acquire read lock
 	// only cleanup method should hold write lock
-	errorLock    sync.RWMutex
-	error        error
-	closed       bool
-	pairScrapers map[string]*KrakenPairScraper // pc.Pair -> pairScraperSet
-	api          *krakenapi.KrakenApi
-	ticker       *time.Ticker
+	errorLock sync.RWMutex
+	error     error
+	closed    bool
+	// pairScrapers are keyed by the websocket symbol of their pair, e.g. BTC/USD
+	pairScrapers     map[string]*KrakenPairScraper
+	pairScrapersLock sync.RWMutex
+	// wsSymbols maps the REST names of all pairs, e.g. XXBTZUSD or XBTUSD, to their websocket symbol
+	wsSymbols    map[string]string
 	exchangeName string
 	chanTrades   chan *dia.Trade
+	// trade ids are consecutive per pair
+	sequences *verificationHelper.SequenceTracker
 }
 
 // NewKrakenScraper returns a new KrakenScraper initialized with default values.
-// The instance is asynchronously scraping as soon as it is created.
+// The instance is asynchronously scraping as soon as it is created. The public trade
+// channel needs no credentials, so @key and @secret are unused.
 func NewKrakenScraper(key string, secret string, exchange dia.Exchange) *KrakenScraper {
 	s := &KrakenScraper{
 		shutdown:     make(chan nothing),
 		shutdownDone: make(chan nothing),
 		pairScrapers: make(map[string]*KrakenPairScraper),
-		api:          krakenapi.New(key, secret),
-		ticker:       time.NewTicker(krakenRefreshDelay),
 		exchangeName: exchange.Name,
 		error:        nil,
 		chanTrades:   make(chan *dia.Trade),
+		sequences:    verificationHelper.NewSequenceTracker(),
+		run:          true,
 	}
+
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:         exchange.Name,
+		URL:          krakenSocketURL,
+		Subscribe:    s.subscribeToALL,
+		Ping:         s.ping,
+		PingInterval: krakenPingInterval,
+	})
+
 	go s.mainLoop()
 	return s
 }
@@ -65,18 +95,115 @@ func FloatToString(input_num float64) string {
 	return strconv.FormatFloat(input_num, 'f', -1, 64)
 }
 
+type krakenWSRequest struct {
+	Method string               `json:"method"`
+	Params *krakenWSRequestArgs `json:"params,omitempty"`
+}
+
+type krakenWSRequestArgs struct {
+	Channel  string   `json:"channel"`
+	Symbol   []string `json:"symbol"`
+	Snapshot bool     `json:"snapshot"`
+}
+
+type krakenWSMessage struct {
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+	Method  string `json:"method"`
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Data    []struct {
+		Symbol    string    `json:"symbol"`
+		Side      string    `json:"side"`
+		Price     float64   `json:"price"`
+		Qty       float64   `json:"qty"`
+		TradeID   int64     `json:"trade_id"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"data"`
+}
+
 // mainLoop runs in a goroutine until channel s is closed.
 func (s *KrakenScraper) mainLoop() {
-	for {
-		select {
-		case <-s.ticker.C:
-			s.Update()
-		case <-s.shutdown: // user requested shutdown
-			log.Printf("KrakenScraper shutting down")
-			s.cleanup(nil)
-			return
+	for s.run {
+		var message krakenWSMessage
+		err := s.conn.ReadJSON(&message)
+		if err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
+			log.Error("decode message: ", err)
+			continue
+		}
+
+		if message.Method == "subscribe" && !message.Success {
+			log.Errorf("KrakenScraper: subscribe: %s", message.Error)
+			continue
+		}
+		if message.Channel != "trade" {
+			// heartbeat, status and responses to requests
+			continue
 		}
+
+		for _, trade := range message.Data {
+			s.pairScrapersLock.RLock()
+			ps, ok := s.pairScrapers[trade.Symbol]
+			s.pairScrapersLock.RUnlock()
+			if !ok {
+				continue
+			}
+			s.conn.Received(ps.pair.ForeignName)
+			status, err := s.sequences.Check(trade.Symbol, trade.TradeID)
+			if err != nil {
+				log.Warnf("rejecting trade on %s: %v", s.exchangeName, err)
+				continue
+			}
+			volume := trade.Qty
+			if trade.Side == "sell" {
+				volume = -volume
+			}
+			t := &dia.Trade{
+				Symbol:             ps.pair.Symbol,
+				Pair:               strings.Replace(trade.Symbol, "/", "", 1),
+				Price:              trade.Price,
+				Volume:             volume,
+				Time:               trade.Timestamp,
+				ForeignTradeID:     strconv.FormatInt(trade.TradeID, 10),
+				Source:             s.exchangeName,
+				VerificationStatus: status,
+			}
+			s.chanTrades <- t
+			log.Info("got trade: ", t)
+		}
+	}
+	s.cleanup(errors.New("main loop terminated by Close()"))
+}
+
+// ping keeps the connection alive.
+func (s *KrakenScraper) ping(conn *wsHelper.Connection) error {
+	return conn.WriteJSON(&krakenWSRequest{Method: "ping"})
+}
+
+// subscribeToALL subscribes again to the trades of all scraped pairs. Trades missed while
+// disconnected show up as sequence gaps.
+func (s *KrakenScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	var symbols []string
+	for symbol := range s.pairScrapers {
+		symbols = append(symbols, symbol)
+	}
+	s.pairScrapersLock.RUnlock()
+
+	return s.subscribe(conn, symbols)
+}
+
+func (s *KrakenScraper) subscribe(conn *wsHelper.Connection, symbols []string) error {
+	if len(symbols) == 0 {
+		return nil
 	}
+	return conn.WriteJSON(&krakenWSRequest{
+		Method: "subscribe",
+		Params: &krakenWSRequestArgs{Channel: "trade", Symbol: symbols},
+	})
 }
 
 // closes all connected PairScrapers
@@ -100,7 +227,12 @@ func (s *KrakenScraper) Close() error {
 	if s.closed {
 		return errors.New("KrakenScraper: Already closed")
 	}
+	// Set false first to prevent reconnect
+	s.run = false
 	close(s.shutdown)
+	if err := s.conn.Close(); err != nil {
+		log.Error("close: ", err)
+	}
 	<-s.shutdownDone
 	s.errorLock.RLock()
 	defer s.errorLock.RUnlock()
@@ -109,14 +241,14 @@ func (s *KrakenScraper) Close() error {
 
 // KrakenPairScraper implements PairScraper for Kraken
 type KrakenPairScraper struct {
-	parent     *KrakenScraper
-	pair       dia.Pair
-	closed     bool
-	lastRecord int64
+	parent *KrakenScraper
+	pair   dia.Pair
+	closed bool
 }
 
 // ScrapePair returns a PairScraper that can be used to get trades for a single pair from
-// this APIScraper
+// this APIScraper. The foreign name of @pair is the REST name of the pair, e.g. XXBTZEUR or
+// ADAXBT.
 func (s *KrakenScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 
 	s.errorLock.RLock()
@@ -127,20 +259,273 @@ func (s *KrakenScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 	if s.closed {
 		return nil, errors.New("KrakenScraper: Call ScrapePair on closed scraper")
 	}
+	if s.wsSymbols == nil {
+		wsSymbols, err := s.fetchWSSymbols()
+		if err != nil {
+			return nil, err
+		}
+		s.wsSymbols = wsSymbols
+	}
+	symbol, ok := s.wsSymbols[pair.ForeignName]
+	if !ok {
+		return nil, errors.New("KrakenScraper: unknown pair " + pair.ForeignName)
+	}
 	ps := &KrakenPairScraper{
-		parent:     s,
-		pair:       pair,
-		lastRecord: 0, //TODO FIX to figure out the last we got...
+		parent: s,
+		pair:   pair,
 	}
 
-	s.pairScrapers[pair.Symbol] = ps
+	s.pairScrapersLock.Lock()
+	s.pairScrapers[symbol] = ps
+	s.pairScrapersLock.Unlock()
+
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, []string{symbol}); err != nil {
+		log.Error("subscribe: ", err)
+	}
 
 	return ps, nil
 }
 
-// FetchAvailablePairs returns a list with all available trade pairs
+type krakenAssetPairs struct {
+	Error  []string `json:"error"`
+	Result map[string]struct {
+		Altname string `json:"altname"`
+		Wsname  string `json:"wsname"`
+		Status  string `json:"status"`
+	} `json:"result"`
+}
+
+func (s *KrakenScraper) fetchAssetPairs() (*krakenAssetPairs, error) {
+	var resp krakenAssetPairs
+	data, err := utils.GetRequest(krakenAssetPairsURL)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, errors.New(strings.Join(resp.Error, ", "))
+	}
+	return &resp, nil
+}
+
+// fetchWSSymbols returns the websocket symbols of all pairs by their name and their altname.
+func (s *KrakenScraper) fetchWSSymbols() (map[string]string, error) {
+	resp, err := s.fetchAssetPairs()
+	if err != nil {
+		return nil, err
+	}
+	wsSymbols := make(map[string]string)
+	for name, p := range resp.Result {
+		if p.Wsname == "" {
+			continue
+		}
+		symbol := krakenWSSymbol(p.Wsname)
+		wsSymbols[name] = symbol
+		wsSymbols[p.Altname] = symbol
+	}
+	return wsSymbols, nil
+}
+
+// krakenWSSymbol returns the websocket v2 symbol of a pair with the websocket v1 name
+// @wsname, e.g. BTC/USD for XBT/USD.
+func krakenWSSymbol(wsname string) string {
+	assets := strings.Split(wsname, "/")
+	for i, asset := range assets {
+		if symbol, ok := krakenSymbols[asset]; ok {
+			assets[i] = symbol
+		}
+	}
+	return strings.Join(assets, "/")
+}
+
+// FetchAvailablePairs returns a list with all available trade pairs. The foreign names are
+// the altnames of the pairs, e.g. XBTUSD, the symbols are DIA's, e.g. BTC.
 func (s *KrakenScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
-	return []dia.Pair{}, errors.New("FetchAvailablePairs() not implemented")
+	resp, err := s.fetchAssetPairs()
+	if err != nil {
+		return
+	}
+	for _, p := range resp.Result {
+		if p.Wsname == "" || (p.Status != "" && p.Status != "online") {
+			continue
+		}
+		pairs = append(pairs, dia.Pair{
+			Symbol:      strings.Split(krakenWSSymbol(p.Wsname), "/")[0],
+			ForeignName: p.Altname,
+			Exchange:    s.exchangeName,
+		})
+	}
+	return
+}
+
+// krakenTradesURL serves the trades of a pair since a time in nanoseconds, oldest first.
+const krakenTradesURL = "https://api.kraken.com/0/public/Trades?pair=%s&since=%d"
+
+// FetchHistoricalTrades calls @handle with the trades of @pair in [@from, @to). Each response
+// holds up to 1000 trades and the cursor of the next one.
+func (s *KrakenScraper) FetchHistoricalTrades(pair dia.Pair, from time.Time, to time.Time, handle func([]dia.Trade) error) error {
+	wsSymbols, err := s.fetchWSSymbols()
+	if err != nil {
+		return err
+	}
+	wsSymbol, ok := wsSymbols[pair.ForeignName]
+	if !ok {
+		return fmt.Errorf("unknown pair %s", pair.ForeignName)
+	}
+
+	since := from.UnixNano()
+	for {
+		data, err := requestSchedulerFor(s.exchangeName).Get(fmt.Sprintf(krakenTradesURL, pair.ForeignName, since))
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Error  []string                   `json:"error"`
+			Result map[string]json.RawMessage `json:"result"`
+		}
+		err = json.Unmarshal(data, &resp)
+		if err != nil {
+			return err
+		}
+		if len(resp.Error) > 0 {
+			return errors.New(strings.Join(resp.Error, ", "))
+		}
+
+		var last string
+		var rows [][]interface{}
+		for key, value := range resp.Result {
+			if key == "last" {
+				err = json.Unmarshal(value, &last)
+			} else {
+				err = json.Unmarshal(value, &rows)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		var trades []dia.Trade
+		done := len(rows) == 0
+		for _, row := range rows {
+			// price, volume, time, side, order type, miscellaneous, trade id
+			if len(row) < 7 {
+				return fmt.Errorf("unexpected trade %v", row)
+			}
+			seconds, _ := row[2].(float64)
+			timestamp := time.Unix(0, int64(seconds*1e6)*int64(time.Microsecond))
+			if !timestamp.Before(to) {
+				done = true
+				break
+			}
+			price, err := strconv.ParseFloat(fmt.Sprint(row[0]), 64)
+			if err != nil {
+				return err
+			}
+			volume, err := strconv.ParseFloat(fmt.Sprint(row[1]), 64)
+			if err != nil {
+				return err
+			}
+			if row[3] == "s" {
+				volume = -volume
+			}
+			tradeID, _ := row[6].(float64)
+			trades = append(trades, dia.Trade{
+				Symbol:         pair.Symbol,
+				Pair:           strings.Replace(wsSymbol, "/", "", 1),
+				Price:          price,
+				Volume:         volume,
+				Time:           timestamp,
+				ForeignTradeID: strconv.FormatInt(int64(tradeID), 10),
+				Source:         s.exchangeName,
+			})
+		}
+		if len(trades) > 0 {
+			err = handle(trades)
+			if err != nil {
+				return err
+			}
+		}
+		next, err := strconv.ParseInt(last, 10, 64)
+		if done || err != nil || next <= since {
+			return nil
+		}
+		since = next
+	}
+}
+
+// FetchPairMeta returns tick size, lot size, minimal order sizes and the volume based
+// fee schedule of all pairs traded on Kraken.
+func (s *KrakenScraper) FetchPairMeta() (meta []dia.PairMeta, err error) {
+	type krakenAssetPair struct {
+		PairDecimals int         `json:"pair_decimals"`
+		LotDecimals  int         `json:"lot_decimals"`
+		Fees         [][]float64 `json:"fees"`
+		FeesMaker    [][]float64 `json:"fees_maker"`
+		OrderMin     string      `json:"ordermin"`
+		CostMin      string      `json:"costmin"`
+		TickSize     string      `json:"tick_size"`
+	}
+	var resp struct {
+		Error  []string                   `json:"error"`
+		Result map[string]krakenAssetPair `json:"result"`
+	}
+
+	data, err := utils.GetRequest(krakenAssetPairsURL)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return
+	}
+	if len(resp.Error) > 0 {
+		return nil, errors.New(strings.Join(resp.Error, ", "))
+	}
+
+	now := time.Now()
+	for name, p := range resp.Result {
+		// Kraken lists dark pool pairs with suffix .d
+		if strings.HasSuffix(name, ".d") || len(name) < 6 {
+			continue
+		}
+		pair, _ := s.NormalizePair(dia.Pair{ForeignName: name, Exchange: s.exchangeName})
+		pm := dia.PairMeta{
+			Exchange:    s.exchangeName,
+			ForeignName: pair.ForeignName,
+			LotSize:     math.Pow10(-p.LotDecimals),
+			Time:        now,
+		}
+		pm.TickSize, err = strconv.ParseFloat(p.TickSize, 64)
+		if err != nil {
+			pm.TickSize = math.Pow10(-p.PairDecimals)
+		}
+		pm.MinOrderSize, _ = strconv.ParseFloat(p.OrderMin, 64)
+		pm.MinNotional, _ = strconv.ParseFloat(p.CostMin, 64)
+
+		// Fees are given in percent as lists of [volume, fee].
+		makerFees := make(map[float64]float64)
+		for _, f := range p.FeesMaker {
+			if len(f) == 2 {
+				makerFees[f[0]] = f[1] / 100
+			}
+		}
+		for _, f := range p.Fees {
+			if len(f) != 2 {
+				continue
+			}
+			tier := dia.FeeTier{Volume: f[0], TakerFee: f[1] / 100, MakerFee: f[1] / 100}
+			if makerFee, ok := makerFees[f[0]]; ok {
+				tier.MakerFee = makerFee
+			}
+			pm.FeeTiers = append(pm.FeeTiers, tier)
+		}
+		meta = append(meta, pm)
+	}
+	return meta, nil
 }
 
 // NormalizePair accounts for the par
@@ -193,45 +578,3 @@ func (ps *KrakenPairScraper) Error() error {
 func (ps *KrakenPairScraper) Pair() dia.Pair {
 	return ps.pair
 }
-
-func NewTrade(pair dia.Pair, info krakenapi.TradeInfo, foreignTradeID string) *dia.Trade {
-	volume := info.VolumeFloat
-	if info.Sell {
-		volume = -volume
-	}
-	t := &dia.Trade{
-		Pair:           pair.ForeignName,
-		Price:          info.PriceFloat,
-		Symbol:         pair.Symbol,
-		Volume:         volume,
-		Time:           time.Unix(info.Time, 0),
-		ForeignTradeID: foreignTradeID,
-		Source:         dia.KrakenExchange,
-	}
-	return t
-}
-
-func (s *KrakenScraper) Update() {
-
-	for _, ps := range s.pairScrapers {
-
-		r, err := s.api.Trades(ps.pair.ForeignName, ps.lastRecord)
-
-		if err != nil {
-			log.Printf("err on collect trades %v %v", err, ps.pair.ForeignName)
-			time.Sleep(1 * time.Minute)
-		} else {
-			if r != nil {
-				ps.lastRecord = r.Last
-				for _, ti := range r.Trades {
-					p, _ := s.NormalizePair(ps.pair)
-					t := NewTrade(p, ti, strconv.FormatInt(r.Last, 16))
-					ps.parent.chanTrades <- t
-					log.Info("got trade: ", t)
-				}
-			} else {
-				log.Printf("r nil")
-			}
-		}
-	}
-}
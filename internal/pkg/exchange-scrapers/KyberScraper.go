@@ -47,6 +47,8 @@ type KyberScraper struct {
 	pairScrapers   map[string]*KyberPairScraper
 	productPairIds map[string]int
 	chanTrades     chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 
 	WsClient    *ethclient.Client
 	RestClient  *ethclient.Client
@@ -76,6 +78,7 @@ func NewKyberScraper(exchange dia.Exchange) *KyberScraper {
 		log.Fatal(err)
 	}
 	scraper.RestClient = restClient
+	scraper.confirmations = newConfirmationBuffer(restClient, scraper.chanTrades, scraper.shutdown)
 
 	scraper.loadTokens()
 
@@ -200,6 +203,11 @@ func (scraper *KyberScraper) subscribeToTrades() error {
 }
 
 func (scraper *KyberScraper) processTrade(trade *kyber.KyberExecuteTrade) {
+	if trade.Raw.Removed {
+		scraper.confirmations.Retract(trade.Raw)
+		return
+	}
+	raw := trade.Raw
 	symbol, foreignName, volume, price, err := scraper.getTradeDataKyber(trade)
 	timestamp := time.Now().Unix()
 	if err != nil {
@@ -216,7 +224,7 @@ func (scraper *KyberScraper) processTrade(trade *kyber.KyberExecuteTrade) {
 				ForeignTradeID: "",
 				Source:         scraper.exchangeName,
 			}
-			pairScraper.parent.chanTrades <- trade
+			pairScraper.parent.confirmations.Add(raw, trade)
 			fmt.Println("got trade: ", trade)
 		}
 	}
@@ -10,8 +10,8 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _LBankSocketurl string = "wss://api.lbkex.com/ws/V2/"
@@ -31,7 +31,7 @@ type SubscribeLBank struct {
 }
 
 type LBankScraper struct {
-	wsClient *ws.Conn
+	conn *wsHelper.Connection
 	// signaling channels for session initialization and finishing
 	shutdown     chan nothing
 	shutdownDone chan nothing
@@ -41,9 +41,10 @@ type LBankScraper struct {
 	error     error
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
-	pairScrapers map[string]*LBankPairScraper
-	exchangeName string
-	chanTrades   chan *dia.Trade
+	pairScrapers     map[string]*LBankPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
 }
 
 // NewLBankScraper returns a new LBankScraper for the given pair
@@ -58,14 +59,11 @@ func NewLBankScraper(exchange dia.Exchange) *LBankScraper {
 		chanTrades:   make(chan *dia.Trade),
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_LBankSocketurl, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:      exchange.Name,
+		URL:       _LBankSocketurl,
+		Subscribe: s.subscribeToALL,
+	})
 	go s.mainLoop()
 	return s
 }
@@ -76,13 +74,19 @@ func (s *LBankScraper) mainLoop() {
 
 	for true {
 		message := &ResponseLBank{}
-		if err = s.wsClient.ReadJSON(&message); err != nil {
+		if err = s.conn.ReadJSON(&message); err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
 			println(err.Error())
-			break
+			continue
 		}
+		s.pairScrapersLock.RLock()
 		ps, ok := s.pairScrapers[strings.ToUpper(message.Pair)]
+		s.pairScrapersLock.RUnlock()
 
 		if ok {
+			s.conn.Received(ps.pair.ForeignName)
 			var f64Price float64
 			var f64Volume float64
 
@@ -144,7 +148,7 @@ func (s *LBankScraper) Close() error {
 	if s.closed {
 		return errors.New("LBankScraper: Already closed")
 	}
-	s.wsClient.Close()
+	s.conn.Close()
 	close(s.shutdown)
 	<-s.shutdownDone
 	s.errorLock.RLock()
@@ -167,16 +171,35 @@ func (s *LBankScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		parent: s,
 		pair:   pair,
 	}
+	s.pairScrapersLock.Lock()
 	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, pair.ForeignName); err != nil {
+		log.Error("ScrapePair" + err.Error())
+	}
+	return ps, nil
+}
+
+// subscribeToALL subscribes again to the trades of all scraped pairs.
+func (s *LBankScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	defer s.pairScrapersLock.RUnlock()
+	for foreignName := range s.pairScrapers {
+		if err := s.subscribe(conn, foreignName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *LBankScraper) subscribe(conn *wsHelper.Connection, foreignName string) error {
 	a := &SubscribeLBank{
 		Action:    "subscribe",
 		Subscribe: "trade",
-		Pair:      strings.ToLower(pair.ForeignName),
-	}
-	if err := s.wsClient.WriteJSON(a); err != nil {
-		log.Error("ScrapePair" + err.Error())
+		Pair:      strings.ToLower(foreignName),
 	}
-	return ps, nil
+	return conn.WriteJSON(a)
 }
 
 func (s *LBankScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
@@ -10,8 +10,8 @@ import (
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
 var _LoopringSocketurl string = "wss://ws.api3.loopring.io/v3/ws"
@@ -60,7 +60,7 @@ type LoopringMarket struct {
 }
 
 type LoopringScraper struct {
-	wsClient      *ws.Conn
+	conn          *wsHelper.Connection
 	decimalsAsset map[string]float64
 	// signaling channels for session initialization and finishing
 	//TODO: Channel not used. Consider removing or refactoring
@@ -75,7 +75,6 @@ type LoopringScraper struct {
 	pairScrapers map[string]*LoopringPairScraper
 	exchangeName string
 	chanTrades   chan *dia.Trade
-	wsURL        string
 }
 
 type LoopringKey struct {
@@ -129,18 +128,11 @@ func NewLoopringScraper(exchange dia.Exchange) *LoopringScraper {
 		decimalsAsset: decimalAsset,
 	}
 
-	key, err := getAPIKey()
-	if err != nil {
-		log.Fatal("get api key: ", err)
-	}
-	s.wsURL = _LoopringSocketurl + "?wsApiKey=" + key
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(s.wsURL, nil)
-	if err != nil {
-		log.Error("Error connecting to ws: ", err.Error())
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:      exchange.Name,
+		DialURL:   loopringSocketURL,
+		Subscribe: s.subscribeToALL,
+	})
 
 	go s.mainLoop()
 	return s
@@ -151,16 +143,16 @@ func (s *LoopringScraper) mainLoop() {
 
 	// wait for all pairs have added into s.PairScrapers
 	time.Sleep(4 * time.Second)
-	s.subscribeToALL()
+	if err := s.subscribeToALL(s.conn); err != nil {
+		log.Error("subscribe: ", err)
+	}
 
 	for {
 
 		var makemap WebSocketResponse
-		messageType, message, err := s.wsClient.ReadMessage()
+		message, err := s.conn.ReadMessage()
 		if err != nil {
-			log.Error("reading websocket message: ", err)
-			s.reconnectToWS()
-			s.subscribeToALL()
+			break
 		}
 
 		err = json.Unmarshal(message, &makemap)
@@ -168,7 +160,7 @@ func (s *LoopringScraper) mainLoop() {
 		if err != nil {
 			message := string(message)
 			if message == "ping" {
-				e := s.Pong(messageType)
+				e := s.Pong()
 				if e != nil {
 					log.Error("send pong: ", err)
 				} else {
@@ -199,14 +191,26 @@ func (s *LoopringScraper) mainLoop() {
 					Volume: volume,
 					Source: s.exchangeName,
 				}
+				s.conn.Received(makemap.Topic.Market)
 				s.chanTrades <- t
 				log.Info("Got trade: ", t)
 			}
 		}
 	}
+	s.cleanup(errors.New("LoopringScraper: main loop terminated by Close()"))
+}
+
+func (s *LoopringScraper) cleanup(err error) {
+	s.errorLock.Lock()
+	defer s.errorLock.Unlock()
+	if err != nil {
+		s.error = err
+	}
+	s.closed = true
+	close(s.shutdownDone)
 }
 
-func (s *LoopringScraper) subscribeToALL() {
+func (s *LoopringScraper) subscribeToALL(conn *wsHelper.Connection) error {
 	log.Info("Subscribing To all pairs")
 
 	count := 0
@@ -219,44 +223,31 @@ func (s *LoopringScraper) subscribeToALL() {
 			break
 		}
 	}
+	if len(topics) == 0 {
+		return nil
+	}
 	log.Info("topics for sub: ", topics)
 	wr := &WebSocketRequest{
 		Op:       "sub",
 		Sequence: 1000,
 		Topics:   topics,
 	}
-	if err := s.wsClient.WriteJSON(wr); err != nil {
-		log.Error(err)
-	}
-
+	return conn.WriteJSON(wr)
 }
 
 // Pong sends the string "pong" to the server.
-func (s *LoopringScraper) Pong(messageType int) error {
-	err := s.wsClient.WriteMessage(messageType, []byte("pong"))
-	if err != nil {
-		return err
-	}
-	return nil
+func (s *LoopringScraper) Pong() error {
+	return s.conn.WriteMessage([]byte("pong"))
 }
 
-func (s *LoopringScraper) reconnectToWS() {
-
-	log.Info("Reconnecting ws")
+// loopringSocketURL returns the websocket URL with a new API key, which is required for each
+// connection.
+func loopringSocketURL() (string, error) {
 	key, err := getAPIKey()
 	if err != nil {
-		log.Fatal("fetching api key: ", err)
-	}
-
-	s.wsURL = _LoopringSocketurl + "?wsApiKey=" + key
-
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(s.wsURL, nil)
-
-	if err != nil {
-		println(err.Error())
+		return "", err
 	}
-	s.wsClient = SwConn
+	return _LoopringSocketurl + "?wsApiKey=" + key, nil
 }
 
 func getAPIKey() (string, error) {
@@ -283,8 +274,8 @@ func (s *LoopringScraper) Close() error {
 	if s.closed {
 		return errors.New("LoopringScraper: Already closed")
 	}
-	s.wsClient.Close()
 	close(s.shutdown)
+	s.conn.Close()
 	<-s.shutdownDone
 	s.errorLock.RLock()
 	defer s.errorLock.RUnlock()
@@ -95,7 +95,7 @@ func (scraper *MakerScraper) GetNewTrades(pair string, startTradeID string) ([]M
 		url = "https://api.oasisdex.com/v2/trades/" + pair + "?limit=100?fromId+" + strconv.Itoa(next)
 	}
 
-	bytes, err = utils.GetRequest(url)
+	bytes, err = requestSchedulerFor(scraper.exchangeName).Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"compress/flate"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"strconv"
 	"strings"
@@ -13,13 +14,21 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	utils "github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 )
 
-var _OKExSocketURL = "wss://ws.okex.com:8443/ws/v5/public"
+var (
+	_OKExSocketURL     = "wss://ws.okx.com:8443/ws/v5/public"
+	_OKExInstrumentURL = "https://www.okx.com/api/v5/public/instruments?instType=SPOT"
+)
 
-//var _OKExSocketURL = url.URL{Scheme: "wss", Host: "real.okex.com:10441", Path: "/ws/v1", RawQuery: "compress=true"}
+const (
+	// OKX closes connections without messages for 30 seconds.
+	okexPingInterval = 20 * time.Second
+	// okexMaxSubscriptions is the number of channels subscribed to with a single request.
+	okexMaxSubscriptions = 100
+)
 
 type Response struct {
 	Channel string     `json:"channel"`
@@ -40,7 +49,7 @@ type OKEXArgs struct {
 }
 
 type OKExScraper struct {
-	wsClient *ws.Conn
+	conn *wsHelper.Connection
 	// signaling channels for session initialization and finishing
 	run          bool
 	shutdown     chan nothing
@@ -51,9 +60,10 @@ type OKExScraper struct {
 	error     error
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
-	pairScrapers map[string]*OKExPairScraper
-	exchangeName string
-	chanTrades   chan *dia.Trade
+	pairScrapers     map[string]*OKExPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
 }
 
 // NewOKExScraper returns a new OKExScraper for the given pair
@@ -66,30 +76,23 @@ func NewOKExScraper(exchange dia.Exchange) *OKExScraper {
 		exchangeName: exchange.Name,
 		error:        nil,
 		chanTrades:   make(chan *dia.Trade),
+		run:          true,
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_OKExSocketURL, nil)
-	if err != nil {
-		log.Error("dial:", err)
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:         exchange.Name,
+		URL:          _OKExSocketURL,
+		Subscribe:    s.subscribeToALL,
+		Ping:         s.ping,
+		PingInterval: okexPingInterval,
+	})
 	go s.mainLoop()
 	return s
 }
 
-// Useful to reconnect to ws when the connection is down
-func (s *OKExScraper) reconnectToWS() {
-
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(_OKExSocketURL, nil)
-
-	if err != nil {
-		log.Error("dial:", err)
-	}
-
-	s.wsClient = SwConn
+// ping keeps the connection alive. OKX answers with pong.
+func (s *OKExScraper) ping(conn *wsHelper.Connection) error {
+	return conn.WriteMessage([]byte("ping"))
 }
 
 type OKEXMarket struct {
@@ -122,41 +125,59 @@ type AllOKEXMarketResponse struct {
 	Msg  string       `json:"msg"`
 }
 
-// Subscribe again to all channels
-func (s *OKExScraper) subscribeToALL() {
-
-	var (
-		resp     AllOKEXMarketResponse
-		allPairs []OKEXArgs
-	)
-
-	b, err := utils.GetRequest("https://aws.okex.com/api/v5/public/instruments?instType=SPOT")
+// fetchMarkets returns all spot instruments of OKX.
+func (s *OKExScraper) fetchMarkets() ([]OKEXMarket, error) {
+	var resp AllOKEXMarketResponse
+	b, err := utils.GetRequest(_OKExInstrumentURL)
 	if err != nil {
-		log.Errorln("Error getting OKex market", err)
+		return nil, err
 	}
-
 	err = json.Unmarshal(b, &resp)
 	if err != nil {
-		log.Errorln("Error Unmarshalling OKex market json", err)
+		return nil, err
 	}
-
-	for _, v := range resp.Data {
-		allPairs = append(allPairs, OKEXArgs{Channel: "trades", InstID: v.InstID})
+	if resp.Code != "0" {
+		return nil, fmt.Errorf("OKExScraper: instruments: %s", resp.Msg)
 	}
+	return resp.Data, nil
+}
 
-	a := &Subscribe{
-		OP:   "subscribe",
-		Args: allPairs,
+// Subscribe again to the trades of all scraped pairs
+func (s *OKExScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	var instIDs []string
+	for instID := range s.pairScrapers {
+		instIDs = append(instIDs, instID)
 	}
+	s.pairScrapersLock.RUnlock()
 
-	if err := s.wsClient.WriteJSON(a); err != nil {
-		log.Errorln(err.Error())
-	}
+	return s.subscribe(conn, instIDs)
+}
 
+// subscribe to the trades channel of the instruments @instIDs in batches.
+func (s *OKExScraper) subscribe(conn *wsHelper.Connection, instIDs []string) error {
+	for len(instIDs) > 0 {
+		n := len(instIDs)
+		if n > okexMaxSubscriptions {
+			n = okexMaxSubscriptions
+		}
+		a := &Subscribe{OP: "subscribe"}
+		for _, instID := range instIDs[:n] {
+			a.Args = append(a.Args, OKEXArgs{Channel: "trades", InstID: instID})
+		}
+		if err := conn.WriteJSON(a); err != nil {
+			return err
+		}
+		instIDs = instIDs[n:]
+	}
+	return nil
 }
 
 type OKEXWSResponse struct {
-	Arg struct {
+	Event string `json:"event"`
+	Code  string `json:"code"`
+	Msg   string `json:"msg"`
+	Arg   struct {
 		Channel string `json:"channel"`
 		InstID  string `json:"instId"`
 	} `json:"arg"`
@@ -172,65 +193,67 @@ type OKEXWSResponse struct {
 
 // runs in a goroutine until s is closed
 func (s *OKExScraper) mainLoop() {
-
-	s.run = true
 	for s.run {
 		var message OKEXWSResponse
-		messageType, messageTemp, err := s.wsClient.ReadMessage()
+		messageTemp, err := s.conn.ReadMessage()
 		if err != nil {
-			log.Warning("reconnect the scraping to ws, ", err, ":", message)
-			s.reconnectToWS()
-			s.subscribeToALL()
-		} else {
-			switch messageType {
-			case ws.TextMessage:
-				// no need uncompressed
-				err := json.Unmarshal(messageTemp, &message)
-				if err != nil {
-					log.Errorln("Error parsing response")
-				}
-				ps, ok := s.pairScrapers[message.Arg.InstID]
-
-				if ok && len(message.Data) > 0 {
-
-					f64PriceString := message.Data[0].Px
-					f64Price, err := strconv.ParseFloat(f64PriceString, 64)
-
-					if err == nil {
-
-						f64VolumeString := message.Data[0].Sz
-						f64Volume, err := strconv.ParseFloat(f64VolumeString, 64)
-
-						if err == nil {
-
-							ts, _ := strconv.ParseInt(message.Data[0].Ts, 10, 64)
-							timeStamp := time.Unix(int64(ts)/1e3, 0)
-							if message.Data[0].Side == "sell" {
-								f64Volume = -f64Volume
-							}
-
-							t := &dia.Trade{
-								Symbol:         ps.pair.Symbol,
-								Pair:           message.Arg.InstID,
-								Price:          f64Price,
-								Volume:         f64Volume,
-								Time:           timeStamp,
-								ForeignTradeID: message.Data[0].TradeID,
-								Source:         s.exchangeName,
-							}
-							ps.parent.chanTrades <- t
-							log.Infoln("Got trade", t)
-
-						} else {
-							log.Errorf("parsing volume %v", f64VolumeString)
-						}
-
-					} else {
-						log.Errorf("parsing price %v", f64PriceString)
-					}
-				}
+			break
+		}
+		if string(messageTemp) == "pong" {
+			continue
+		}
+		err = json.Unmarshal(messageTemp, &message)
+		if err != nil {
+			log.Errorln("Error parsing response: ", err)
+			continue
+		}
+		switch message.Event {
+		case "error":
+			log.Errorf("OKExScraper: error %s: %s", message.Code, message.Msg)
+			continue
+		case "subscribe":
+			log.Infof("subscribed to %s of %s", message.Arg.Channel, message.Arg.InstID)
+			continue
+		}
 
+		s.pairScrapersLock.RLock()
+		ps, ok := s.pairScrapers[message.Arg.InstID]
+		s.pairScrapersLock.RUnlock()
+		if !ok {
+			continue
+		}
+		s.conn.Received(ps.pair.ForeignName)
+		for _, trade := range message.Data {
+			f64Price, err := strconv.ParseFloat(trade.Px, 64)
+			if err != nil {
+				log.Errorf("parsing price %v", trade.Px)
+				continue
+			}
+			f64Volume, err := strconv.ParseFloat(trade.Sz, 64)
+			if err != nil {
+				log.Errorf("parsing volume %v", trade.Sz)
+				continue
 			}
+			ts, err := strconv.ParseInt(trade.Ts, 10, 64)
+			if err != nil {
+				log.Errorf("parsing timestamp %v", trade.Ts)
+				continue
+			}
+			if trade.Side == "sell" {
+				f64Volume = -f64Volume
+			}
+
+			t := &dia.Trade{
+				Symbol:         ps.pair.Symbol,
+				Pair:           message.Arg.InstID,
+				Price:          f64Price,
+				Volume:         f64Volume,
+				Time:           time.Unix(0, ts*int64(time.Millisecond)),
+				ForeignTradeID: trade.TradeID,
+				Source:         s.exchangeName,
+			}
+			ps.parent.chanTrades <- t
+			log.Infoln("Got trade", t)
 		}
 	}
 	s.cleanup(errors.New("main loop terminated by Close()"))
@@ -263,12 +286,11 @@ func (s *OKExScraper) Close() error {
 		return errors.New("OKExScraper: Already closed")
 	}
 
-	close(s.shutdown)
 	// Set false first to prevent reconnect
 	s.run = false
-	err := s.wsClient.Close()
-	if err != nil {
-		return err
+	close(s.shutdown)
+	if err := s.conn.Close(); err != nil {
+		log.Error("close: ", err)
 	}
 	<-s.shutdownDone
 	s.errorLock.RLock()
@@ -296,17 +318,14 @@ func (s *OKExScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		pair:   pair,
 	}
 
+	s.pairScrapersLock.Lock()
 	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
 
-	//a := &Subscribe{
-	//	OP:      "addChannel",
-	//	Args: "ok_sub_spot_" + strings.ToLower(pair.ForeignName) + "_deals",
-	//}
-	//
-	//subByteString := `{"channel":` + `"` + a.Channel + `"` + `,"event":` + `"` + a.Event + `"}`
-	//if err := s.wsClient.WriteMessage(ws.TextMessage, []byte(subByteString)); err != nil {
-	//	fmt.Println(err.Error())
-	//}
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, []string{pair.ForeignName}); err != nil {
+		log.Error("subscribe: ", err)
+	}
 
 	return ps, nil
 }
@@ -351,35 +370,52 @@ func (s *OKExScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
 
 }
 
-// FetchAvailablePairs returns a list with all available trade pairs
+// FetchAvailablePairs returns a list with all live spot pairs from the instruments endpoint
 func (s *OKExScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
-	type APIResponse struct {
-		Id           string `json:"instrument_id"`
-		BaseCurrency string `json:"base_currency"`
+	markets, err := s.fetchMarkets()
+	if err != nil {
+		return
 	}
+	for _, market := range markets {
+		if market.State != "live" {
+			continue
+		}
+		pairToNormalize := dia.Pair{
+			Symbol:      market.BaseCcy,
+			ForeignName: market.InstID,
+			Exchange:    s.exchangeName,
+		}
+		pair, serr := s.NormalizePair(pairToNormalize)
+		if serr == nil {
+			pairs = append(pairs, pair)
+		} else {
+			log.Error(serr)
+		}
+	}
+	return
+}
 
-	data, err := utils.GetRequest("https://www.okex.com/api/spot/v3/products")
-
+// FetchPairMeta returns tick size, lot size and minimal order size of all spot pairs traded on OKEx.
+// Fee tiers are only available on the authenticated API and hence left empty.
+func (s *OKExScraper) FetchPairMeta() (meta []dia.PairMeta, err error) {
+	markets, err := s.fetchMarkets()
 	if err != nil {
 		return
 	}
-
-	var ar []APIResponse
-	err = json.Unmarshal(data, &ar)
-	if err == nil {
-		for _, p := range ar {
-			pairToNormalize := dia.Pair{
-				Symbol:      p.BaseCurrency,
-				ForeignName: p.Id,
-				Exchange:    s.exchangeName,
-			}
-			pair, serr := s.NormalizePair(pairToNormalize)
-			if serr == nil {
-				pairs = append(pairs, pair)
-			} else {
-				log.Error(serr)
-			}
+	now := time.Now()
+	for _, v := range markets {
+		if v.State != "live" {
+			continue
+		}
+		pm := dia.PairMeta{
+			Exchange:    s.exchangeName,
+			ForeignName: v.InstID,
+			Time:        now,
 		}
+		pm.TickSize, _ = strconv.ParseFloat(v.TickSz, 64)
+		pm.LotSize, _ = strconv.ParseFloat(v.LotSz, 64)
+		pm.MinOrderSize, _ = strconv.ParseFloat(v.MinSz, 64)
+		meta = append(meta, pm)
 	}
 	return
 }
@@ -0,0 +1,227 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// defaultPollInterval is the time between two requests of the latest trades of a pair.
+const defaultPollInterval = 5 * time.Second
+
+// pollingAPI is a REST API returning the latest trades of a pair, such as the indexer of dYdX
+// v4 or an exchange described in a generic scraper config.
+type pollingAPI interface {
+	// fetchPairs returns all markets of the exchange.
+	fetchPairs() ([]dia.Pair, error)
+	// fetchTrades returns the latest trades of the market @foreignName in any order. Price,
+	// Volume, Time and ForeignTradeID of the trades are set.
+	fetchTrades(foreignName string) ([]dia.Trade, error)
+}
+
+// PollingScraper polls the trades of all scraped pairs from a pollingAPI.
+type PollingScraper struct {
+	api pollingAPI
+	// interval is the time between two requests of the trades of a pair. Requests are spaced
+	// by the requestScheduler of the exchange to respect the rate limit of the API.
+	interval time.Duration
+	// signaling channels for session initialization and finishing
+	shutdown     chan nothing
+	shutdownDone chan nothing
+	// error handling; to read error or closed, first acquire read lock
+	// only cleanup method should hold write lock
+	errorLock sync.RWMutex
+	error     error
+	closed    bool
+	// used to keep track of trading pairs that we subscribed to
+	pairScrapers     map[string]*PollingPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
+}
+
+func newPollingScraper(exchange dia.Exchange, api pollingAPI, interval time.Duration) *PollingScraper {
+	s := &PollingScraper{
+		api:          api,
+		interval:     interval,
+		shutdown:     make(chan nothing),
+		shutdownDone: make(chan nothing),
+		pairScrapers: make(map[string]*PollingPairScraper),
+		exchangeName: exchange.Name,
+		chanTrades:   make(chan *dia.Trade),
+	}
+	go s.mainLoop()
+	return s
+}
+
+// runs in a goroutine until s is closed
+func (s *PollingScraper) mainLoop() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.pairScrapersLock.RLock()
+			pairScrapers := make([]*PollingPairScraper, 0, len(s.pairScrapers))
+			for _, ps := range s.pairScrapers {
+				pairScrapers = append(pairScrapers, ps)
+			}
+			s.pairScrapersLock.RUnlock()
+
+			for _, ps := range pairScrapers {
+				if ps.closed {
+					continue
+				}
+				trades, err := s.api.fetchTrades(ps.pair.ForeignName)
+				if err != nil {
+					log.Errorf("%s: fetch trades of %s: %v", s.exchangeName, ps.pair.ForeignName, err)
+					continue
+				}
+				for _, trade := range ps.newTrades(trades) {
+					trade.Symbol = ps.pair.Symbol
+					trade.Pair = ps.pair.ForeignName
+					trade.Source = s.exchangeName
+					log.Info("got trade: ", trade)
+					select {
+					case s.chanTrades <- trade:
+					case <-s.shutdown:
+						s.cleanup(errors.New(s.exchangeName + "Scraper: terminated by Close()"))
+						return
+					}
+				}
+			}
+		case <-s.shutdown:
+			s.cleanup(errors.New(s.exchangeName + "Scraper: terminated by Close()"))
+			return
+		}
+	}
+}
+
+// Close channels for shutdown
+func (s *PollingScraper) cleanup(err error) {
+	s.errorLock.Lock()
+	defer s.errorLock.Unlock()
+	if err != nil {
+		s.error = err
+	}
+	s.closed = true
+	close(s.chanTrades)
+	close(s.shutdownDone)
+}
+
+// Close terminates the main loop and closes the channel of trades
+func (s *PollingScraper) Close() error {
+	if s.closed {
+		return errors.New(s.exchangeName + "Scraper: Already closed")
+	}
+	close(s.shutdown)
+	<-s.shutdownDone
+	s.errorLock.RLock()
+	defer s.errorLock.RUnlock()
+	return s.error
+}
+
+// ScrapePair returns a PairScraper that can be used to get trades for a single pair from
+// this APIScraper
+func (s *PollingScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
+	s.errorLock.RLock()
+	defer s.errorLock.RUnlock()
+	if s.error != nil {
+		return nil, s.error
+	}
+	if s.closed {
+		return nil, errors.New(s.exchangeName + "Scraper: Call ScrapePair on closed scraper")
+	}
+	ps := &PollingPairScraper{
+		parent: s,
+		pair:   pair,
+		// Only trades after the start of the scraper are sent.
+		latestTime: time.Now(),
+		latestIDs:  make(map[string]struct{}),
+	}
+	s.pairScrapersLock.Lock()
+	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
+	return ps, nil
+}
+
+// FetchAvailablePairs returns a list with all available trade pairs
+func (s *PollingScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
+	return s.api.fetchPairs()
+}
+
+func (s *PollingScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
+	return pair, nil
+}
+
+// Channel returns the channel to get trades
+func (s *PollingScraper) Channel() chan *dia.Trade {
+	return s.chanTrades
+}
+
+// PollingPairScraper implements PairScraper for an PollingScraper
+type PollingPairScraper struct {
+	parent *PollingScraper
+	pair   dia.Pair
+	closed bool
+	// latestTime is the time of the latest trade sent, latestIDs are the ids of the trades sent
+	// at latestTime.
+	latestTime time.Time
+	latestIDs  map[string]struct{}
+}
+
+// newTrades returns the trades of @trades which weren't sent yet, in chronological order.
+func (ps *PollingPairScraper) newTrades(trades []dia.Trade) []*dia.Trade {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+	var newTrades []*dia.Trade
+	for i := range trades {
+		trade := trades[i]
+		if trade.Time.Before(ps.latestTime) {
+			continue
+		}
+		if trade.Time.After(ps.latestTime) {
+			ps.latestTime = trade.Time
+			ps.latestIDs = make(map[string]struct{})
+		}
+		if _, ok := ps.latestIDs[trade.ForeignTradeID]; ok {
+			continue
+		}
+		ps.latestIDs[trade.ForeignTradeID] = struct{}{}
+		newTrades = append(newTrades, &trade)
+	}
+	return newTrades
+}
+
+// Close stops listening for trades of the pair associated
+func (ps *PollingPairScraper) Close() error {
+	ps.closed = true
+	return nil
+}
+
+// Error returns an error when the channel Channel() is closed
+// and nil otherwise
+func (ps *PollingPairScraper) Error() error {
+	ps.parent.errorLock.RLock()
+	defer ps.parent.errorLock.RUnlock()
+	return ps.parent.error
+}
+
+// Pair returns the pair this scraper is subscribed to
+func (ps *PollingPairScraper) Pair() dia.Pair {
+	return ps.pair
+}
+
+// getPollingJSON decodes the response of a GET request to @url of @exchange into @v.
+func getPollingJSON(exchange string, url string, v interface{}) error {
+	data, err := requestSchedulerFor(exchange).Get(url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
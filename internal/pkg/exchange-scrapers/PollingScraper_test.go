@@ -0,0 +1,32 @@
+package scrapers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestPollingNewTrades(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	ps := &PollingPairScraper{latestTime: start, latestIDs: make(map[string]struct{})}
+
+	trades := ps.newTrades([]dia.Trade{
+		{ForeignTradeID: "3", Time: start.Add(2 * time.Second)},
+		{ForeignTradeID: "1", Time: start.Add(-time.Second)},
+		{ForeignTradeID: "2", Time: start.Add(time.Second)},
+	})
+	if len(trades) != 2 || trades[0].ForeignTradeID != "2" || trades[1].ForeignTradeID != "3" {
+		t.Fatalf("expected trades 2 and 3, got %v", trades)
+	}
+
+	// The next response overlaps with the previous one.
+	trades = ps.newTrades([]dia.Trade{
+		{ForeignTradeID: "4", Time: start.Add(2 * time.Second)},
+		{ForeignTradeID: "3", Time: start.Add(2 * time.Second)},
+		{ForeignTradeID: "2", Time: start.Add(time.Second)},
+	})
+	if len(trades) != 1 || trades[0].ForeignTradeID != "4" {
+		t.Fatalf("expected trade 4, got %v", trades)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/diadata-org/diadata/pkg/utils"
-	ws "github.com/gorilla/websocket"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 )
 
 var pingPeriod = 60*time.Second*2 - 1
@@ -74,7 +74,7 @@ const (
 )
 
 type QuoineScraper struct {
-	wsClient *ws.Conn
+	conn *wsHelper.Connection
 
 	exchangeName string
 
@@ -88,8 +88,9 @@ type QuoineScraper struct {
 	error     error
 	closed    bool
 
-	pairScrapers   map[string]*QuoinePairScraper
-	productPairIds map[string]string
+	pairScrapers     map[string]*QuoinePairScraper
+	pairScrapersLock sync.RWMutex
+	productPairIds   map[string]string
 
 	chanTrades chan *dia.Trade
 }
@@ -108,39 +109,28 @@ func NewQuoineScraper(exchange dia.Exchange) *QuoineScraper {
 	}
 
 	err = scraper.readProductIds()
-
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(LiquidSocketURL, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-
-	scraper.wsClient = SwConn
 	if err != nil {
 		log.Error("Couldn't obtain Quoine product ids:", err)
 	}
-	go scraper.sendPing()
+
+	scraper.conn = wsHelper.Dial(wsHelper.Config{
+		Name:         exchange.Name,
+		URL:          LiquidSocketURL,
+		Subscribe:    scraper.subscribeToALL,
+		Ping:         scraper.sendPing,
+		PingInterval: pingPeriod,
+	})
 
 	go scraper.mainLoop()
 
 	return scraper
 }
 
-func (scraper *QuoineScraper) sendPing() {
-	ticker := time.NewTicker(pingPeriod)
-	defer ticker.Stop()
-	for {
-		select {
-		case <-ticker.C:
-			ls := &LiquidSubscribe{
-				Event: "pusher:ping",
-			}
-			scraper.wsClient.WriteJSON(ls)
-
-		}
+func (scraper *QuoineScraper) sendPing(conn *wsHelper.Connection) error {
+	ls := &LiquidSubscribe{
+		Event: "pusher:ping",
 	}
-
+	return conn.WriteJSON(ls)
 }
 
 type LiquidResponseTrade struct {
@@ -163,9 +153,13 @@ func (scraper *QuoineScraper) mainLoop() {
 
 		var message LiquidResponse
 
-		err := scraper.wsClient.ReadJSON(&message)
+		err := scraper.conn.ReadJSON(&message)
 		if err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
 			log.Errorln("Error reading JSON", err)
+			continue
 		}
 		switch message.Event {
 
@@ -177,7 +171,13 @@ func (scraper *QuoineScraper) mainLoop() {
 				continue
 			}
 
-			pairScraper := scraper.pairScrapers[message.Channel]
+			scraper.pairScrapersLock.RLock()
+			pairScraper, ok := scraper.pairScrapers[message.Channel]
+			scraper.pairScrapersLock.RUnlock()
+			if !ok {
+				continue
+			}
+			scraper.conn.Received(pairScraper.pair.ForeignName)
 
 			volume := data.Quantity
 
@@ -290,22 +290,39 @@ func (scraper *QuoineScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 
 	channelName := "executions_cash_" + strings.ToLower(pair.ForeignName)
 
-	channel := LiquidChannel{
-		Channel: channelName,
-	}
-
-	a := &LiquidSubscribe{
-		Event: "pusher:subscribe",
-		Data:  channel,
-	}
+	scraper.pairScrapersLock.Lock()
+	scraper.pairScrapers[channelName] = pairScraper
+	scraper.pairScrapersLock.Unlock()
 
-	if err := scraper.wsClient.WriteJSON(a); err != nil {
+	// If the connection is down, the channel is subscribed to after reconnecting.
+	if err := scraper.subscribe(scraper.conn, channelName); err != nil {
 		log.Errorln(err.Error())
 	}
-	scraper.pairScrapers[channelName] = pairScraper
 
 	return pairScraper, nil
 }
+
+// subscribeToALL subscribes again to the executions of all scraped pairs.
+func (scraper *QuoineScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	scraper.pairScrapersLock.RLock()
+	defer scraper.pairScrapersLock.RUnlock()
+	for channelName := range scraper.pairScrapers {
+		if err := scraper.subscribe(conn, channelName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (scraper *QuoineScraper) subscribe(conn *wsHelper.Connection, channelName string) error {
+	a := &LiquidSubscribe{
+		Event: "pusher:subscribe",
+		Data: LiquidChannel{
+			Channel: channelName,
+		},
+	}
+	return conn.WriteJSON(a)
+}
 func (s *QuoineScraper) cleanup(err error) {
 	s.errorLock.Lock()
 	defer s.errorLock.Unlock()
@@ -324,6 +341,7 @@ func (scraper *QuoineScraper) Close() error {
 	}
 
 	close(scraper.shutdown)
+	scraper.conn.Close()
 	<-scraper.shutdownDone
 	return nil
 }
@@ -12,7 +12,6 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
-	utils "github.com/diadata-org/diadata/pkg/utils"
 	gosocketio "github.com/graarh/golang-socketio"
 	"github.com/graarh/golang-socketio/transport"
 )
@@ -120,18 +119,9 @@ func (s *STEXScraper) mainLoop() {
 }
 
 func (s *STEXScraper) scrapeTrades() {
-	var numRequests int
 	s.FetchAvailablePairs()
 	for _, pairScraper := range s.pairScrapers {
-		if numRequests > 180 {
-			// API limit is 180 requests per min.
-			log.Info("sleep for a minute due to STEX API rate limit")
-			time.Sleep(1 * time.Minute)
-			numRequests = 0
-		} else {
-			s.scrapePair(pairScraper.pair)
-			numRequests++
-		}
+		s.scrapePair(pairScraper.pair)
 	}
 	// Sleep after getting trades for all pairs
 	log.Info("Scraped all pairs. Wait for next iteration.")
@@ -185,7 +175,7 @@ func (s *STEXScraper) GetNewTrades(pairID string, fromTimestamp time.Time) ([]ST
 		url = apiBaseURL + "/trades/" + pairID + "?sort=DESC&from=" + unixTime + "&limit=100"
 	}
 
-	bytes, err = utils.GetRequest(url)
+	bytes, err = requestSchedulerFor(s.exchangeName).Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -299,7 +289,7 @@ func (s *STEXScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
 			AmountMultiplier  int    `json:"amount_multiplier"`
 		} `json:"data"`
 	}
-	data, err := utils.GetRequest("https://api3.stex.com/public/currency_pairs/list/ALL")
+	data, err := requestSchedulerFor(s.exchangeName).Get("https://api3.stex.com/public/currency_pairs/list/ALL")
 	if err != nil {
 		return
 	}
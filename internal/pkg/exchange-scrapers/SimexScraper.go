@@ -11,7 +11,6 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
-	utils "github.com/diadata-org/diadata/pkg/utils"
 )
 
 type PairIdMap struct {
@@ -88,9 +87,6 @@ func (s *SimexScraper) mainLoop() {
 			break
 		}
 		for key, el := range s.pairScrapers {
-			// more or less 60 calls per minute, the limit is 300
-			time.Sleep(1 * time.Second)
-
 			if s.pairIdTrade[key] == nil {
 				log.Error(key, "s.pairIdTrade[key] == nil")
 				continue
@@ -155,7 +151,7 @@ func (s *SimexScraper) mainLoop() {
 
 func getAPICall(params ...string) []interface{} {
 
-	body, err := utils.GetRequest(_apiurl + params[0])
+	body, err := requestSchedulerFor(dia.SimexExchange).Get(_apiurl + params[0])
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -250,7 +246,7 @@ func (s *SimexScraper) FetchAvailablePairs() (pairs []dia.Pair, err error) {
 		Data []DataT `json:"data"`
 	}
 
-	data, err := utils.GetRequest("https://simex.global/api/pairs")
+	data, err := requestSchedulerFor(s.exchangeName).Get("https://simex.global/api/pairs")
 
 	if err != nil {
 		return
@@ -79,6 +79,8 @@ type UniswapScraper struct {
 	pairScrapers map[string]*UniswapPairScraper
 	exchangeName string
 	chanTrades   chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 }
 
 // NewUniswapScraper returns a new UniswapScraper for the given pair
@@ -149,6 +151,7 @@ func NewUniswapScraper(exchange dia.Exchange) *UniswapScraper {
 
 	s.WsClient = wsClient
 	s.RestClient = restClient
+	s.confirmations = newConfirmationBuffer(restClient, s.chanTrades, s.shutdown)
 
 	go s.mainLoop()
 	return s
@@ -234,6 +237,10 @@ func (s *UniswapScraper) mainLoop() {
 				for {
 					rawSwap, ok := <-sink
 					if ok {
+						if rawSwap.Raw.Removed {
+							s.confirmations.Retract(rawSwap.Raw)
+							continue
+						}
 						swap, err := s.normalizeUniswapSwap(*rawSwap)
 						if err != nil {
 							log.Error("error normalizing swap: ", err)
@@ -261,7 +268,7 @@ func (s *UniswapScraper) mainLoop() {
 						}
 						if price > 0 {
 							log.Info("Got trade: ", t)
-							ps.parent.chanTrades <- t
+							ps.parent.confirmations.Add(rawSwap.Raw, t)
 						}
 						if price == 0 {
 							log.Info("Got zero trade: ", t)
@@ -22,6 +22,35 @@ import (
 
 var (
 	UniswapV3FactoryContractAddress = "0x1F98431c8aD98523631AE4a59f267346ea31F984"
+
+	// uniswapV3StartBlocks are the blocks in which the factories of the Uniswap v3 deployments
+	// were created. Pools are collected from their PoolCreated events from there on.
+	uniswapV3StartBlocks = map[string]uint64{
+		dia.UniswapExchangeV3:        12369621,
+		dia.UniswapExchangeV3Polygon: 22757547,
+	}
+
+	// uniswapV3MinReserves is the least amount of a reference token a pool must hold in its active
+	// liquidity for its swaps to be emitted as trades. Pools without a reference token can't be
+	// valued and aren't scraped.
+	uniswapV3MinReserves = map[common.Address]float64{
+		// Ethereum: WETH, WBTC, USDC, USDT, DAI
+		common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"): 10,
+		common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599"): 0.5,
+		common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"): 25000,
+		common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"): 25000,
+		common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"): 25000,
+		// Polygon: WETH, WBTC, USDC, USDT, DAI, WMATIC
+		common.HexToAddress("0x7ceB23fD6bC0adD59E62ac25578270cFf1b9f619"): 10,
+		common.HexToAddress("0x1BFD67037B42Cf73acF2047067bd4F2C47D9BfD6"): 0.5,
+		common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"): 25000,
+		common.HexToAddress("0xc2132D05D31c914a87C6611C10748AEb04B58e8F"): 25000,
+		common.HexToAddress("0x8f3Cf7ad23Cd3CaDbD9735AFf958023239c6A063"): 25000,
+		common.HexToAddress("0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"): 20000,
+	}
+
+	// q96 is 2^96, the scale of sqrtPriceX96.
+	q96 = new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))
 )
 
 type UniswapV3Swap struct {
@@ -30,6 +59,16 @@ type UniswapV3Swap struct {
 	Pair      UniswapPair
 	Amount0   float64
 	Amount1   float64
+	// Price is the price of token0 in token1 after the swap.
+	Price float64
+}
+
+// UniswapV3Pool is a pool along with the state of its current tick, which is updated from its
+// swaps. Liquidity is the liquidity of the positions in range of the current tick.
+type UniswapV3Pool struct {
+	UniswapPair
+	SqrtPriceX96 *big.Int
+	Liquidity    *big.Int
 }
 
 type UniswapV3Scraper struct {
@@ -50,10 +89,15 @@ type UniswapV3Scraper struct {
 	pairRecieved chan *UniswapPair
 
 	exchangeName string
+	factory      common.Address
+	startBlock   uint64
 	chanTrades   chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 }
 
-// NewUniswapV3Scraper returns a new UniswapV3Scraper
+// NewUniswapV3Scraper returns a new UniswapV3Scraper for Uniswap v3 or a deployment or fork of it
+// with the same pool interface, whose factory is the contract of @exchange.
 func NewUniswapV3Scraper(exchange dia.Exchange) *UniswapV3Scraper {
 	log.Info("NewUniswapScraper ", exchange.Name)
 	var wsClient, restClient *ethclient.Client
@@ -61,7 +105,6 @@ func NewUniswapV3Scraper(exchange dia.Exchange) *UniswapV3Scraper {
 
 	switch exchange.Name {
 	case dia.UniswapExchangeV3:
-		exchangeFactoryContractAddress = exchange.Contract.String()
 		wsClient, err = ethclient.Dial(wsDial)
 		if err != nil {
 			log.Fatal(err)
@@ -72,7 +115,16 @@ func NewUniswapV3Scraper(exchange dia.Exchange) *UniswapV3Scraper {
 			log.Fatal(err)
 		}
 
-		break
+	case dia.UniswapExchangeV3Polygon:
+		log.Infoln("Init ws and rest client for Polygon chain")
+		wsClient, err = ethclient.Dial(wsDialPolygon)
+		if err != nil {
+			log.Fatal(err)
+		}
+		restClient, err = ethclient.Dial(restDialPolygon)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	s := &UniswapV3Scraper{
@@ -80,6 +132,8 @@ func NewUniswapV3Scraper(exchange dia.Exchange) *UniswapV3Scraper {
 		shutdownDone: make(chan nothing),
 		pairScrapers: make(map[string]*UniswapPairV3Scraper),
 		exchangeName: exchange.Name,
+		factory:      exchange.Contract,
+		startBlock:   uniswapV3StartBlocks[exchange.Name],
 		pairRecieved: make(chan *UniswapPair),
 		error:        nil,
 		chanTrades:   make(chan *dia.Trade),
@@ -87,6 +141,7 @@ func NewUniswapV3Scraper(exchange dia.Exchange) *UniswapV3Scraper {
 
 	s.WsClient = wsClient
 	s.RestClient = restClient
+	s.confirmations = newConfirmationBuffer(restClient, s.chanTrades, s.shutdown)
 
 	go s.mainLoop()
 	return s
@@ -137,50 +192,70 @@ func (s *UniswapV3Scraper) mainLoop() {
 			log.Info("skip pair ", pair.ForeignName, ", address is blacklisted")
 			continue
 		}
-		pair.normalizeUniPair()
-		if true {
-			log.Info(": found pair scraper for: ", pair.ForeignName, " with address ", pair.Address.Hex())
-			sink, err := s.GetSwapsChannel(pair.Address)
-			if err != nil {
-				log.Error("error fetching swaps channel: ", err)
-			}
+		if !hasReferenceToken(*pair) {
+			log.Info("skip pair ", pair.ForeignName, ", no reference token to value its liquidity")
+			continue
+		}
+		pool, err := s.GetPool(*pair)
+		if err != nil {
+			log.Error("error getting pool state: ", err)
+			continue
+		}
+		if pool.Liquidity.Sign() == 0 {
+			log.Info("skip pair ", pair.ForeignName, ", pool has no liquidity in range")
+			continue
+		}
+		pool.normalizeUniPair()
 
-			go func() {
-				for {
-					rawSwap, ok := <-sink
-					if ok {
-						swap, err := s.normalizeUniswapSwap(*rawSwap)
-						if err != nil {
-							log.Error("error normalizing swap: ", err)
-						}
-						price, volume := s.getSwapData(swap)
-
-						t := &dia.Trade{
-							Symbol:         pair.Token0.Symbol,
-							Pair:           pair.ForeignName,
-							Price:          price,
-							Volume:         volume,
-							Time:           time.Unix(swap.Timestamp, 0),
-							ForeignTradeID: swap.ID,
-							Source:         s.exchangeName,
-						}
-						// If we need quotation of a base token, reverse pair
-						if utils.Contains(reversePairs, strings.ToLower(pair.Token1.Address.Hex())) {
-							tSwapped, err := dia.SwapTrade(*t)
-							if err == nil {
-								t = &tSwapped
-							}
-						}
-						if price > 0 {
-							log.Info("Got trade: ", t)
-							s.chanTrades <- t
-						}
+		log.Info(": found pair scraper for: ", pool.ForeignName, " with address ", pool.Address.Hex())
+		sink, err := s.GetSwapsChannel(pool.Address)
+		if err != nil {
+			log.Error("error fetching swaps channel: ", err)
+			continue
+		}
+
+		go func() {
+			for {
+				rawSwap, ok := <-sink
+				if !ok {
+					return
+				}
+				if rawSwap.Raw.Removed {
+					s.confirmations.Retract(rawSwap.Raw)
+					continue
+				}
+				swap, ok := pool.update(*rawSwap)
+				if !ok {
+					continue
+				}
+				if !pool.liquid() {
+					log.Debugf("skip swap %s in pool %s with low liquidity", swap.ID, pool.ForeignName)
+					continue
+				}
+				price, volume := s.getSwapData(swap)
+
+				t := &dia.Trade{
+					Symbol:         pool.Token0.Symbol,
+					Pair:           pool.ForeignName,
+					Price:          price,
+					Volume:         volume,
+					Time:           time.Unix(swap.Timestamp, 0),
+					ForeignTradeID: swap.ID,
+					Source:         s.exchangeName,
+				}
+				// If we need quotation of a base token, reverse pair
+				if utils.Contains(reversePairs, strings.ToLower(pool.Token1.Address.Hex())) {
+					tSwapped, err := dia.SwapTrade(*t)
+					if err == nil {
+						t = &tSwapped
 					}
 				}
-			}()
-		} else {
-			log.Info("Skipping pair due to no pairScraper being available")
-		}
+				if price > 0 {
+					log.Info("Got trade: ", t)
+					s.confirmations.Add(rawSwap.Raw, t)
+				}
+			}
+		}()
 	}
 }
 
@@ -191,52 +266,107 @@ func (s *UniswapV3Scraper) GetSwapsChannel(pairAddress common.Address) (chan *Un
 
 	pairFiltererContract, err := UniswapV3Pair.NewUniswapV3PairFilterer(pairAddress, s.WsClient)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	_, err = pairFiltererContract.WatchSwap(&bind.WatchOpts{}, sink, []common.Address{}, []common.Address{})
 	if err != nil {
-		log.Error("error in get swaps channel: ", err)
+		return nil, err
 	}
 
 	return sink, nil
 
 }
 
+// getSwapData returns the price of token0 in token1 after @swap and its volume in token0,
+// which is negative if token0 was sold to the pool.
 func (s *UniswapV3Scraper) getSwapData(swap UniswapV3Swap) (price float64, volume float64) {
-	if swap.Amount0 > float64(0) {
-		// Amount0In is positive
-		volume = swap.Amount0
-		price = swap.Amount1 / swap.Amount0
-	} else {
-		// Amount0In is Negative
-		volume = swap.Amount0
-		price = swap.Amount1 / swap.Amount0
-	}
-	price = math.Abs(price)
-	return
+	return swap.Price, -swap.Amount0
 }
 
-// normalizeUniswapSwap takes a swap as returned by the swap contract's channel and converts it to a UniswapSwap type
-func (s *UniswapV3Scraper) normalizeUniswapSwap(swap UniswapV3Pair.UniswapV3PairSwap) (normalizedSwap UniswapV3Swap, err error) {
-
-	pair, err := s.GetPairByAddress(swap.Raw.Address)
+// GetPool returns the pool of @pair with the liquidity currently in range.
+func (s *UniswapV3Scraper) GetPool(pair UniswapPair) (pool *UniswapV3Pool, err error) {
+	pairContract, err := UniswapV3Pair.NewUniswapV3PairCaller(pair.Address, s.RestClient)
 	if err != nil {
-		log.Error("error getting pair by address: ", err)
 		return
 	}
-	decimals0 := int(pair.Token0.Decimals)
-	decimals1 := int(pair.Token1.Decimals)
-	amount0, _ := new(big.Float).Quo(big.NewFloat(0).SetInt(swap.Amount0), new(big.Float).SetFloat64(math.Pow10(decimals0))).Float64()
-	amount1, _ := new(big.Float).Quo(big.NewFloat(0).SetInt(swap.Amount1), new(big.Float).SetFloat64(math.Pow10(decimals1))).Float64()
+	liquidity, err := pairContract.Liquidity(&bind.CallOpts{})
+	if err != nil {
+		return
+	}
+	return &UniswapV3Pool{UniswapPair: pair, Liquidity: liquidity}, nil
+}
+
+// update sets the state of @pool after @swap and returns the normalized swap. Swaps which
+// don't move both tokens are dust and not returned.
+func (pool *UniswapV3Pool) update(swap UniswapV3Pair.UniswapV3PairSwap) (normalizedSwap UniswapV3Swap, ok bool) {
+	pool.SqrtPriceX96 = swap.SqrtPriceX96
+	pool.Liquidity = swap.Liquidity
+	if swap.Amount0.Sign() == 0 || swap.Amount1.Sign() == 0 {
+		return
+	}
+	amount0, _ := new(big.Float).Quo(big.NewFloat(0).SetInt(swap.Amount0), new(big.Float).SetFloat64(math.Pow10(int(pool.Token0.Decimals)))).Float64()
+	amount1, _ := new(big.Float).Quo(big.NewFloat(0).SetInt(swap.Amount1), new(big.Float).SetFloat64(math.Pow10(int(pool.Token1.Decimals)))).Float64()
 
 	normalizedSwap = UniswapV3Swap{
 		ID:        swap.Raw.TxHash.Hex(),
 		Timestamp: time.Now().Unix(),
-		Pair:      pair,
+		Pair:      pool.UniswapPair,
 		Amount0:   amount0,
 		Amount1:   amount1,
+		Price:     sqrtPriceX96ToPrice(swap.SqrtPriceX96, pool.Token0.Decimals, pool.Token1.Decimals),
+	}
+	return normalizedSwap, true
+}
+
+// liquid returns true if the reserves of a reference token in the liquidity in range of @pool
+// reach its minimum. The state of the pool must be known from a swap.
+func (pool *UniswapV3Pool) liquid() bool {
+	if pool.SqrtPriceX96 == nil || pool.Liquidity == nil {
+		return false
 	}
+	reserve0, reserve1 := virtualReserves(pool.Liquidity, pool.SqrtPriceX96, pool.Token0.Decimals, pool.Token1.Decimals)
+	if minimum, ok := uniswapV3MinReserves[pool.Token0.Address]; ok && reserve0 >= minimum {
+		return true
+	}
+	if minimum, ok := uniswapV3MinReserves[pool.Token1.Address]; ok && reserve1 >= minimum {
+		return true
+	}
+	return false
+}
+
+// hasReferenceToken returns true if one of the tokens of @pair has a minimum reserve.
+func hasReferenceToken(pair UniswapPair) bool {
+	_, ok0 := uniswapV3MinReserves[pair.Token0.Address]
+	_, ok1 := uniswapV3MinReserves[pair.Token1.Address]
+	return ok0 || ok1
+}
+
+// sqrtPriceX96ToPrice returns the price of token0 in token1 of a pool at @sqrtPriceX96, that is
+// (sqrtPriceX96 / 2^96)^2 scaled from the smallest units of the tokens to whole tokens.
+func sqrtPriceX96ToPrice(sqrtPriceX96 *big.Int, decimals0 uint8, decimals1 uint8) float64 {
+	sqrtPrice := new(big.Float).Quo(new(big.Float).SetInt(sqrtPriceX96), q96)
+	price := new(big.Float).Mul(sqrtPrice, sqrtPrice)
+	price.Mul(price, big.NewFloat(math.Pow10(int(decimals0)-int(decimals1))))
+	p, _ := price.Float64()
+	return p
+}
+
+// virtualReserves returns the amounts of token0 and token1 which @liquidity is equivalent to at
+// @sqrtPriceX96, that is L/sqrt(P) and L*sqrt(P), in whole tokens. They measure how deep the
+// pool is around its current price.
+func virtualReserves(liquidity *big.Int, sqrtPriceX96 *big.Int, decimals0 uint8, decimals1 uint8) (reserve0 float64, reserve1 float64) {
+	if sqrtPriceX96.Sign() == 0 {
+		return 0, 0
+	}
+	sqrtPrice := new(big.Float).Quo(new(big.Float).SetInt(sqrtPriceX96), q96)
+	l := new(big.Float).SetInt(liquidity)
+	r0 := new(big.Float).Quo(l, sqrtPrice)
+	r0.Quo(r0, big.NewFloat(math.Pow10(int(decimals0))))
+	r1 := new(big.Float).Mul(l, sqrtPrice)
+	r1.Quo(r1, big.NewFloat(math.Pow10(int(decimals1))))
+	reserve0, _ = r0.Float64()
+	reserve1, _ = r1.Float64()
 	return
 }
 
@@ -394,15 +524,12 @@ func (s *UniswapV3Scraper) getAllPairs() (pairs []UniswapPair, err error) {
 	// filter from contract created https://etherscan.io/tx/0x1e20cd6d47d7021ae7e437792823517eeadd835df09dde17ab45afd7a5df4603
 
 	poolsCount := 0
-	contract, err := uniswapcontractv3.NewUniswapV3Filterer(common.HexToAddress(UniswapV3FactoryContractAddress), s.WsClient)
+	contract, err := uniswapcontractv3.NewUniswapV3Filterer(s.factory, s.WsClient)
 	if err != nil {
 		log.Error(err)
 	}
 
-	var startBlock uint64
-	startBlock = 12369621
-
-	poolCreated, err := contract.FilterPoolCreated(&bind.FilterOpts{Start: startBlock}, []common.Address{}, []common.Address{}, []*big.Int{})
+	poolCreated, err := contract.FilterPoolCreated(&bind.FilterOpts{Start: s.startBlock}, []common.Address{}, []common.Address{}, []*big.Int{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,56 @@
+package scrapers
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// sqrtPriceX96 returns the sqrtPriceX96 of a pool at @price of token0 in token1.
+func sqrtPriceX96(price float64, decimals0 uint8, decimals1 uint8) *big.Int {
+	sqrtPrice := new(big.Float).SetFloat64(math.Sqrt(price * math.Pow10(int(decimals1)-int(decimals0))))
+	result, _ := sqrtPrice.Mul(sqrtPrice, q96).Int(nil)
+	return result
+}
+
+func TestSqrtPriceX96ToPrice(t *testing.T) {
+	// USDC-WETH pool at 2000 USDC per ETH.
+	price := sqrtPriceX96ToPrice(sqrtPriceX96(0.0005, 6, 18), 6, 18)
+	if math.Abs(price-0.0005)/0.0005 > 1e-9 {
+		t.Errorf("expected price 0.0005, got %v", price)
+	}
+	// WBTC-WETH pool at 15 ETH per BTC.
+	price = sqrtPriceX96ToPrice(sqrtPriceX96(15, 8, 18), 8, 18)
+	if math.Abs(price-15)/15 > 1e-9 {
+		t.Errorf("expected price 15, got %v", price)
+	}
+}
+
+func TestUniswapV3PoolLiquid(t *testing.T) {
+	usdc := UniswapToken{Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Symbol: "USDC", Decimals: 6}
+	token := UniswapToken{Address: common.HexToAddress("0x0000000000000000000000000000000000000001"), Symbol: "TKN", Decimals: 18}
+	pool := UniswapV3Pool{UniswapPair: UniswapPair{Token0: usdc, Token1: token}}
+	if pool.liquid() {
+		t.Errorf("expected pool without known state not to be liquid")
+	}
+
+	// At 1 TKN per USDC, a liquidity of 1e17 holds sqrt(1e-12)*1e17 = 1e11, i.e. 100000 USDC.
+	pool.SqrtPriceX96 = sqrtPriceX96(1, 6, 18)
+	pool.Liquidity = big.NewInt(1e17)
+	reserve0, reserve1 := virtualReserves(pool.Liquidity, pool.SqrtPriceX96, 6, 18)
+	if math.Abs(reserve0-100000) > 1e-3 || math.Abs(reserve1-100000) > 1e-3 {
+		t.Errorf("expected reserves of 100000, got %v %v", reserve0, reserve1)
+	}
+	if !pool.liquid() {
+		t.Errorf("expected pool with 100000 USDC to be liquid")
+	}
+	pool.Liquidity = big.NewInt(1e15)
+	if pool.liquid() {
+		t.Errorf("expected pool with 1000 USDC not to be liquid")
+	}
+	if hasReferenceToken(UniswapPair{Token0: token, Token1: token}) {
+		t.Errorf("expected pair without reference token")
+	}
+}
@@ -0,0 +1,83 @@
+package scrapers
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	vertexGatewayURL = "https://gateway.prod.vertexprotocol.com/v2"
+	vertexArchiveURL = "https://archive.prod.vertexprotocol.com/v2"
+)
+
+// vertexAPI is the API of Vertex, whose orderbook is matched offchain by its sequencer and
+// settled on Arbitrum.
+type vertexAPI struct {
+	exchangeName string
+}
+
+// NewVertexScraper returns a scraper of the spot and perpetual markets of Vertex.
+func NewVertexScraper(exchange dia.Exchange) *PollingScraper {
+	return newPollingScraper(exchange, &vertexAPI{exchangeName: exchange.Name}, defaultPollInterval)
+}
+
+type vertexPair struct {
+	ProductID int    `json:"product_id"`
+	TickerID  string `json:"ticker_id"`
+	Base      string `json:"base"`
+	Quote     string `json:"quote"`
+}
+
+type vertexTrade struct {
+	TickerID   string  `json:"ticker_id"`
+	TradeID    int64   `json:"trade_id"`
+	Price      float64 `json:"price"`
+	BaseFilled float64 `json:"base_filled"`
+	Timestamp  int64   `json:"timestamp"`
+	TradeType  string  `json:"trade_type"`
+}
+
+// fetchPairs returns the spot markets, e.g. BTC_USDC, and the perpetual markets, e.g.
+// BTC-PERP_USDC, both with the symbol BTC.
+func (api *vertexAPI) fetchPairs() (pairs []dia.Pair, err error) {
+	var vertexPairs []vertexPair
+	err = getPollingJSON(api.exchangeName, vertexGatewayURL+"/pairs", &vertexPairs)
+	if err != nil {
+		return
+	}
+	for _, pair := range vertexPairs {
+		pairs = append(pairs, dia.Pair{
+			Symbol:      strings.TrimSuffix(pair.Base, "-PERP"),
+			ForeignName: pair.TickerID,
+			Exchange:    api.exchangeName,
+		})
+	}
+	return
+}
+
+func (api *vertexAPI) fetchTrades(foreignName string) ([]dia.Trade, error) {
+	var vertexTrades []vertexTrade
+	err := getPollingJSON(api.exchangeName, vertexArchiveURL+"/trades?limit=100&ticker_id="+url.QueryEscape(foreignName), &vertexTrades)
+	if err != nil {
+		return nil, err
+	}
+	var trades []dia.Trade
+	for _, t := range vertexTrades {
+		volume := math.Abs(t.BaseFilled)
+		if t.TradeType == "sell" {
+			volume = -volume
+		}
+		trades = append(trades, dia.Trade{
+			Price:          t.Price,
+			Volume:         volume,
+			Time:           time.Unix(t.Timestamp, 0),
+			ForeignTradeID: strconv.FormatInt(t.TradeID, 10),
+		})
+	}
+	return trades, nil
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/diadata-org/diadata/pkg/dia"
-	ws "github.com/gorilla/websocket"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/wsHelper"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,7 +32,7 @@ type ZBTradeResponse struct {
 }
 
 type ZBScraper struct {
-	wsClient *ws.Conn
+	conn *wsHelper.Connection
 	// signaling channels for session initialization and finishing
 	//initDone     chan nothing
 	shutdown     chan nothing
@@ -43,9 +43,10 @@ type ZBScraper struct {
 	error     error
 	closed    bool
 	// used to keep track of trading pairs that we subscribed to
-	pairScrapers map[string]*ZBPairScraper
-	exchangeName string
-	chanTrades   chan *dia.Trade
+	pairScrapers     map[string]*ZBPairScraper
+	pairScrapersLock sync.RWMutex
+	exchangeName     string
+	chanTrades       chan *dia.Trade
 }
 
 // NewZBScraper returns a new ZBScraper for the given pair
@@ -60,14 +61,11 @@ func NewZBScraper(exchange dia.Exchange) *ZBScraper {
 		chanTrades:   make(chan *dia.Trade),
 	}
 
-	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(ZBSocketURL, nil)
-
-	if err != nil {
-		println(err.Error())
-	}
-
-	s.wsClient = SwConn
+	s.conn = wsHelper.Dial(wsHelper.Config{
+		Name:      exchange.Name,
+		URL:       ZBSocketURL,
+		Subscribe: s.subscribeToALL,
+	})
 	go s.mainLoop()
 	return s
 }
@@ -75,21 +73,28 @@ func NewZBScraper(exchange dia.Exchange) *ZBScraper {
 // runs in a goroutine until s is closed
 func (s *ZBScraper) mainLoop() {
 
+	var err error
 	for true {
 
 		message := &ZBTradeResponse{}
 
-		if s.error = s.wsClient.ReadJSON(&message); s.error != nil {
-			log.Error(s.error.Error())
-			break
+		if err = s.conn.ReadJSON(&message); err != nil {
+			if errors.Is(err, wsHelper.ErrClosed) {
+				break
+			}
+			log.Error(err.Error())
+			continue
 		}
 
 		for _, trade := range message.Data {
+			s.pairScrapersLock.RLock()
 			ps, ok := s.pairScrapers[strings.TrimSuffix(message.Channel, "_trades")]
+			s.pairScrapersLock.RUnlock()
 			if !ok {
 				log.Error("unknown pair: " + message.Channel)
 				continue
 			}
+			s.conn.Received(ps.pair.ForeignName)
 
 			f64Price, err := strconv.ParseFloat(trade.Price, 64)
 			if err != nil {
@@ -121,7 +126,7 @@ func (s *ZBScraper) mainLoop() {
 
 		}
 	}
-	s.cleanup(s.error)
+	s.cleanup(err)
 }
 
 func (s *ZBScraper) NormalizePair(pair dia.Pair) (dia.Pair, error) {
@@ -149,7 +154,7 @@ func (s *ZBScraper) Close() error {
 	}
 
 	close(s.shutdown)
-	s.wsClient.Close()
+	s.conn.Close()
 	<-s.shutdownDone
 	s.errorLock.RLock()
 	defer s.errorLock.RUnlock()
@@ -175,18 +180,36 @@ func (s *ZBScraper) ScrapePair(pair dia.Pair) (PairScraper, error) {
 		pair:   pair,
 	}
 
+	s.pairScrapersLock.Lock()
 	s.pairScrapers[pair.ForeignName] = ps
+	s.pairScrapersLock.Unlock()
 
-	a := &ZBSubscribe{
-		Event:   "addChannel",
-		Channel: pair.ForeignName + "_trades",
+	// If the connection is down, the pair is subscribed to after reconnecting.
+	if err := s.subscribe(s.conn, pair.ForeignName); err != nil {
+		log.Errorf("subscribe %s: %v", pair.ForeignName, err)
 	}
 
-	if err := s.wsClient.WriteJSON(a); err != nil {
-		fmt.Println(err.Error())
+	return ps, nil
+}
+
+// subscribeToALL subscribes again to the trades of all scraped pairs.
+func (s *ZBScraper) subscribeToALL(conn *wsHelper.Connection) error {
+	s.pairScrapersLock.RLock()
+	defer s.pairScrapersLock.RUnlock()
+	for foreignName := range s.pairScrapers {
+		if err := s.subscribe(conn, foreignName); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return ps, nil
+func (s *ZBScraper) subscribe(conn *wsHelper.Connection, foreignName string) error {
+	a := &ZBSubscribe{
+		Event:   "addChannel",
+		Channel: foreignName + "_trades",
+	}
+	return conn.WriteJSON(a)
 }
 
 // FetchAvailablePairs returns a list with all available trade pairs
@@ -47,6 +47,8 @@ type ZeroxScraper struct {
 	pairScrapers   map[string]*ZeroxPairScraper
 	productPairIds map[string]int
 	chanTrades     chan *dia.Trade
+	// trades wait for confirmations before they are sent to chanTrades
+	confirmations *confirmationBuffer
 
 	WsClient    *ethclient.Client
 	RestClient  *ethclient.Client
@@ -76,6 +78,7 @@ func NewZeroxScraper(exchange dia.Exchange) *ZeroxScraper {
 		log.Fatal(err)
 	}
 	scraper.RestClient = restClient
+	scraper.confirmations = newConfirmationBuffer(restClient, scraper.chanTrades, scraper.shutdown)
 
 	scraper.loadTokens()
 
@@ -199,6 +202,11 @@ func (scraper *ZeroxScraper) subscribeToTrades() error {
 }
 
 func (scraper *ZeroxScraper) processTrade(trade *zerox.ZeroxFill) {
+	if trade.Raw.Removed {
+		scraper.confirmations.Retract(trade.Raw)
+		return
+	}
+	raw := trade.Raw
 	symbol, foreignName, volume, price, err := scraper.getFillDataZerox(trade)
 	timestamp := time.Now().Unix()
 	if err != nil {
@@ -215,7 +223,7 @@ func (scraper *ZeroxScraper) processTrade(trade *zerox.ZeroxFill) {
 				ForeignTradeID: "",
 				Source:         scraper.exchangeName,
 			}
-			pairScraper.parent.chanTrades <- trade
+			pairScraper.parent.confirmations.Add(raw, trade)
 			fmt.Println("got trade: ", trade)
 		}
 	}
@@ -0,0 +1,137 @@
+package scrapers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// backfillPriceFilter is the filter whose values convert backfilled prices in a base token to USD.
+const backfillPriceFilter = "MAIR120"
+
+// HistoricalScraper is implemented by scrapers of exchanges whose REST API serves past trades,
+// so that gaps from outages can be backfilled.
+type HistoricalScraper interface {
+	// FetchHistoricalTrades calls @handle with the trades of @pair in the time range
+	// [@from, @to), page by page in ascending order. Trades are set up as the scraper sends
+	// them live, in particular with the same Pair and ForeignTradeID.
+	FetchHistoricalTrades(pair dia.Pair, from time.Time, to time.Time, handle func([]dia.Trade) error) error
+}
+
+// backfillStore is the part of the datastore a backfill writes to.
+type backfillStore interface {
+	GetForeignTradeIDs(exchange string, pair string, starttime time.Time, endtime time.Time) (map[string]struct{}, error)
+	GetLastPriceBefore(symbol string, filter string, exchange string, timestamp time.Time) (models.Price, error)
+	SaveTradeInflux(t *dia.Trade) error
+	Flush() error
+}
+
+// BackfillResult counts the trades of a pair handled by a backfill.
+type BackfillResult struct {
+	Pair string
+	// Saved trades were missing in the trade store, Duplicates were stored already and Unpriced
+	// had no USD price of their base token.
+	Saved      int
+	Duplicates int
+	Unpriced   int
+}
+
+// Backfill stores the trades of @pairs in the time range [@from, @to) which are missing in the
+// trade store. Trades are identified by exchange, pair and foreign trade id, so running a
+// backfill again or over a range the live scraper covered doesn't store any trade twice.
+func Backfill(scraper HistoricalScraper, store backfillStore, exchange string, pairs []dia.Pair, from time.Time, to time.Time) ([]BackfillResult, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("backfill range from %v to %v is empty", from, to)
+	}
+	prices := newBackfillPrices(store)
+	var results []BackfillResult
+	for _, pair := range pairs {
+		result := BackfillResult{Pair: pair.ForeignName}
+		var stored map[string]struct{}
+		err := scraper.FetchHistoricalTrades(pair, from, to, func(trades []dia.Trade) error {
+			for i := range trades {
+				t := trades[i]
+				if t.Time.Before(from) || !t.Time.Before(to) {
+					continue
+				}
+				// Pair can differ from the foreign name, so the stored ids are loaded with the
+				// first trade.
+				if stored == nil {
+					var err error
+					stored, err = store.GetForeignTradeIDs(exchange, t.Pair, from, to)
+					if err != nil {
+						return err
+					}
+				}
+				if _, ok := stored[t.ForeignTradeID]; ok {
+					result.Duplicates++
+					continue
+				}
+				usdPrice, ok := prices.estimate(t)
+				if !ok {
+					result.Unpriced++
+					continue
+				}
+				t.EstimatedUSDPrice = usdPrice
+				err := store.SaveTradeInflux(&t)
+				if err != nil {
+					return err
+				}
+				stored[t.ForeignTradeID] = struct{}{}
+				result.Saved++
+			}
+			return store.Flush()
+		})
+		if err != nil {
+			return results, fmt.Errorf("backfill %s: %v", pair.ForeignName, err)
+		}
+		log.Infof("backfilled %s on %s: %d trades saved, %d already stored, %d without USD price", pair.ForeignName, exchange, result.Saved, result.Duplicates, result.Unpriced)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// backfillPrices looks up the USD prices of base tokens at the time of past trades. Prices are
+// cached by minute, the resolution of the filters.
+type backfillPrices struct {
+	store  backfillStore
+	prices map[string]float64
+}
+
+func newBackfillPrices(store backfillStore) *backfillPrices {
+	return &backfillPrices{store: store, prices: make(map[string]float64)}
+}
+
+// estimate returns the price of @t in USD, or false if the price of its base token is unknown.
+func (p *backfillPrices) estimate(t dia.Trade) (float64, bool) {
+	baseToken := t.BaseToken()
+	if baseToken == "USD" {
+		return t.Price, true
+	}
+	minute := t.Time.Truncate(time.Minute)
+	key := baseToken + minute.String()
+	price, ok := p.prices[key]
+	if !ok {
+		quotation, err := p.store.GetLastPriceBefore(baseToken, backfillPriceFilter, "", minute)
+		if err != nil {
+			log.Errorf("price of %s at %v: %v", baseToken, minute, err)
+		}
+		price = quotation.Price
+		p.prices[key] = price
+	}
+	if price == 0 {
+		return 0, false
+	}
+	return t.Price * price, true
+}
+
+// sortTradesByTime sorts @trades in ascending order of time, keeping the order of the
+// exchange for trades at the same time.
+func sortTradesByTime(trades []dia.Trade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+}
@@ -0,0 +1,80 @@
+package scrapers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+type testHistoricalScraper struct {
+	pages [][]dia.Trade
+}
+
+func (s *testHistoricalScraper) FetchHistoricalTrades(pair dia.Pair, from time.Time, to time.Time, handle func([]dia.Trade) error) error {
+	for _, page := range s.pages {
+		if err := handle(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type testBackfillStore struct {
+	stored map[string]struct{}
+	saved  []dia.Trade
+	prices map[string]float64
+}
+
+func (s *testBackfillStore) GetForeignTradeIDs(exchange string, pair string, starttime time.Time, endtime time.Time) (map[string]struct{}, error) {
+	ids := make(map[string]struct{})
+	for id := range s.stored {
+		ids[id] = struct{}{}
+	}
+	return ids, nil
+}
+
+func (s *testBackfillStore) GetLastPriceBefore(symbol string, filter string, exchange string, timestamp time.Time) (models.Price, error) {
+	return models.Price{Symbol: symbol, Price: s.prices[symbol]}, nil
+}
+
+func (s *testBackfillStore) SaveTradeInflux(t *dia.Trade) error {
+	s.saved = append(s.saved, *t)
+	return nil
+}
+
+func (s *testBackfillStore) Flush() error {
+	return nil
+}
+
+func TestBackfill(t *testing.T) {
+	from := time.Unix(1700000000, 0)
+	to := from.Add(time.Hour)
+	trade := func(id string, pair string, offset time.Duration) dia.Trade {
+		return dia.Trade{Symbol: "ETH", Pair: pair, Price: 2, Time: from.Add(offset), ForeignTradeID: id, Source: dia.BinanceExchange}
+	}
+	scraper := &testHistoricalScraper{pages: [][]dia.Trade{
+		{trade("1", "ETHBTC", time.Minute), trade("2", "ETHBTC", 2*time.Minute), trade("0", "ETHBTC", -time.Minute)},
+		// Pages may overlap.
+		{trade("2", "ETHBTC", 2*time.Minute), trade("3", "ETHBTC", 3*time.Minute), trade("4", "ETHBTC", time.Hour)},
+	}}
+	store := &testBackfillStore{
+		stored: map[string]struct{}{"1": {}},
+		prices: map[string]float64{"BTC": 30000},
+	}
+
+	results, err := Backfill(scraper, store, dia.BinanceExchange, []dia.Pair{{Symbol: "ETH", ForeignName: "ETHBTC"}}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.saved) != 2 || store.saved[0].ForeignTradeID != "2" || store.saved[1].ForeignTradeID != "3" {
+		t.Fatalf("expected trades 2 and 3 to be saved, got %v", store.saved)
+	}
+	if store.saved[0].EstimatedUSDPrice != 60000 {
+		t.Errorf("got estimated USD price %v, expected 60000", store.saved[0].EstimatedUSDPrice)
+	}
+	if results[0].Saved != 2 || results[0].Duplicates != 2 || results[0].Unpriced != 0 {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+}
@@ -0,0 +1,168 @@
+package scrapers
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// defaultConfirmationDepth is the number of blocks on top of the block of a swap before its
+	// trade is emitted. It can be overwritten by CONFIRMATION_DEPTH.
+	defaultConfirmationDepth = 12
+	// confirmationPollInterval is the time between two checks of the head of the blockchain.
+	confirmationPollInterval = 5 * time.Second
+)
+
+// headerReader is the part of an ethclient.Client which confirms blocks.
+type headerReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+type pendingTrade struct {
+	trade     *dia.Trade
+	blockHash common.Hash
+	txHash    common.Hash
+	index     uint
+}
+
+// confirmationBuffer holds the trades of swaps from logs of EVM blockchains until their block is
+// confirmationDepth blocks deep. Trades of logs which the node retracts, or whose block is no longer
+// part of the canonical chain once it is confirmed, are dropped, so that trades of orphaned blocks
+// never reach the trades channel.
+type confirmationBuffer struct {
+	client headerReader
+	depth  uint64
+	out    chan *dia.Trade
+	done   chan nothing
+
+	mu sync.Mutex
+	// pending trades by block number
+	pending map[uint64][]pendingTrade
+}
+
+// newConfirmationBuffer returns a confirmationBuffer which sends confirmed trades to @out until
+// @done is closed.
+func newConfirmationBuffer(client headerReader, out chan *dia.Trade, done chan nothing) *confirmationBuffer {
+	b := &confirmationBuffer{
+		client:  client,
+		depth:   confirmationDepth(),
+		out:     out,
+		done:    done,
+		pending: make(map[uint64][]pendingTrade),
+	}
+	go b.mainLoop()
+	return b
+}
+
+// confirmationDepth returns the depth of CONFIRMATION_DEPTH, or the default depth.
+func confirmationDepth() uint64 {
+	depth, err := strconv.ParseUint(os.Getenv("CONFIRMATION_DEPTH"), 10, 64)
+	if err != nil {
+		return defaultConfirmationDepth
+	}
+	return depth
+}
+
+// Add buffers @trade of the swap logged in @raw until its block is confirmed.
+func (b *confirmationBuffer) Add(raw types.Log, trade *dia.Trade) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending[raw.BlockNumber] = append(b.pending[raw.BlockNumber], pendingTrade{
+		trade:     trade,
+		blockHash: raw.BlockHash,
+		txHash:    raw.TxHash,
+		index:     raw.Index,
+	})
+}
+
+// Retract drops the trade of @raw, a log which was removed by a reorganization.
+func (b *confirmationBuffer) Retract(raw types.Log) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	trades := b.pending[raw.BlockNumber]
+	for i, pending := range trades {
+		if pending.blockHash == raw.BlockHash && pending.txHash == raw.TxHash && pending.index == raw.Index {
+			log.Warnf("retract trade %s of reorganized block %d", pending.trade.ForeignTradeID, raw.BlockNumber)
+			b.pending[raw.BlockNumber] = append(trades[:i], trades[i+1:]...)
+			return
+		}
+	}
+	log.Warnf("log %d of tx %s in block %d removed after its trade was confirmed", raw.Index, raw.TxHash.Hex(), raw.BlockNumber)
+}
+
+func (b *confirmationBuffer) mainLoop() {
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := b.confirm(context.Background())
+			if err != nil {
+				log.Error("confirm trades: ", err)
+			}
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// confirm sends the trades of the blocks which are confirmed at the current head to b.out in
+// the order of the blocks. Trades are stamped with the time of their block, as they reach the
+// trades block service late by the confirmation depth anyway.
+func (b *confirmationBuffer) confirm(ctx context.Context) error {
+	head, err := b.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < b.depth {
+		return nil
+	}
+
+	b.mu.Lock()
+	var blocks []uint64
+	for number := range b.pending {
+		if number <= head-b.depth {
+			blocks = append(blocks, number)
+		}
+	}
+	b.mu.Unlock()
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+
+	for _, number := range blocks {
+		header, err := b.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		canonical := header.Hash()
+		blockTime := time.Unix(int64(header.Time), 0)
+
+		b.mu.Lock()
+		trades := b.pending[number]
+		delete(b.pending, number)
+		b.mu.Unlock()
+
+		for _, pending := range trades {
+			if pending.blockHash != canonical {
+				log.Warnf("drop trade %s of orphaned block %d %s", pending.trade.ForeignTradeID, number, pending.blockHash.Hex())
+				continue
+			}
+			pending.trade.Time = blockTime
+			select {
+			case b.out <- pending.trade:
+			case <-b.done:
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package scrapers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testChain struct {
+	head    uint64
+	headers map[uint64]*types.Header
+}
+
+func (c *testChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.head, nil
+}
+
+func (c *testChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.headers[number.Uint64()], nil
+}
+
+func testHeader(number uint64, fork byte) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Time: 1600000000 + 12*number, Extra: []byte{fork}}
+}
+
+func testSwapLog(header *types.Header, index uint) types.Log {
+	return types.Log{
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		TxHash:      common.BigToHash(big.NewInt(int64(index))),
+		Index:       index,
+	}
+}
+
+func TestConfirmationBuffer(t *testing.T) {
+	chain := &testChain{head: 102, headers: make(map[uint64]*types.Header)}
+	for number := uint64(100); number <= 102; number++ {
+		chain.headers[number] = testHeader(number, 0)
+	}
+	out := make(chan *dia.Trade, 10)
+	b := &confirmationBuffer{
+		client:  chain,
+		depth:   2,
+		out:     out,
+		done:    make(chan nothing),
+		pending: make(map[uint64][]pendingTrade),
+	}
+
+	// 101 is replaced by a block of another fork, the log of 102 is removed by the node.
+	orphaned := testSwapLog(testHeader(101, 1), 2)
+	removed := testSwapLog(chain.headers[102], 3)
+	b.Add(testSwapLog(chain.headers[100], 1), &dia.Trade{ForeignTradeID: "confirmed"})
+	b.Add(orphaned, &dia.Trade{ForeignTradeID: "orphaned"})
+	b.Add(removed, &dia.Trade{ForeignTradeID: "removed"})
+	b.Add(testSwapLog(chain.headers[102], 4), &dia.Trade{ForeignTradeID: "pending"})
+	removed.Removed = true
+	b.Retract(removed)
+
+	err := b.confirm(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("expected only the trade of block 100 to be confirmed at head 102")
+	}
+	trade := <-out
+	if trade.ForeignTradeID != "confirmed" || trade.Time.Unix() != 1600001200 {
+		t.Fatalf("got trade %s at %v, expected the trade of block 100 at its time", trade.ForeignTradeID, trade.Time)
+	}
+
+	chain.head = 104
+	err = b.confirm(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || (<-out).ForeignTradeID != "pending" {
+		t.Fatalf("expected only the trade of block 102 to be confirmed at head 104")
+	}
+	if len(b.pending) != 0 {
+		t.Errorf("got %d blocks pending, expected none", len(b.pending))
+	}
+}
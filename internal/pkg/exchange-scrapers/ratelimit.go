@@ -0,0 +1,236 @@
+package scrapers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/proxyHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/recordHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+// rateLimit is the sustained number of requests per second to the REST API of an exchange and
+// the number of requests which may be sent at once after a pause.
+type rateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// defaultRateLimit applies to exchanges without documented limits.
+var defaultRateLimit = rateLimit{Rate: 2, Burst: 5}
+
+// rateLimits are the documented limits of the public REST APIs of exchanges, with a margin for
+// the other requests of a scraper sharing the IP.
+var rateLimits = map[string]rateLimit{
+	// 6000 weight per minute, 25 for the recent trades of a pair
+	dia.BinanceExchange: {Rate: 3, Burst: 10},
+	// 10 requests per second
+	dia.CoinBaseExchange: {Rate: 8, Burst: 15},
+	// 1 request per second
+	dia.KrakenExchange: {Rate: 1, Burst: 1},
+	// 60 requests per minute
+	dia.BittrexExchange: {Rate: 1, Burst: 1},
+	// 300 requests per minute
+	dia.SimexExchange: {Rate: 4, Burst: 5},
+	// 180 requests per minute
+	dia.STEXExchange: {Rate: 2.5, Burst: 5},
+	// 100 requests per 10 seconds
+	dia.DydxExchange: {Rate: 8, Burst: 10},
+	// 600 requests per 10 seconds
+	dia.VertexExchange: {Rate: 20, Burst: 20},
+}
+
+var rateLimitedCounter = metrics.NewCounter("scrapers", "rate_limited_responses", "Number of responses of exchange APIs refusing requests for exceeding their rate limit.", "exchange")
+
+// requestScheduler spaces the REST requests of all scrapers of an exchange with a token bucket.
+// When the exchange answers 429 or 418 nonetheless, all requests pause for its Retry-After, or
+// for a growing backoff if it sends none. Requests are spread over the proxies configured for
+// the exchange, if any.
+type requestScheduler struct {
+	exchange string
+	limit    rateLimit
+	backoff  utils.Backoff
+	now      func() time.Time
+	client   *http.Client
+	proxies  *proxyHelper.Rotation
+
+	mu sync.Mutex
+	// tokens may become negative, they are the requests reserved ahead of the rate then
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	// rateLimited counts the rate limited responses since the last success
+	rateLimited int
+}
+
+var (
+	requestSchedulers     = make(map[string]*requestScheduler)
+	requestSchedulersLock sync.Mutex
+)
+
+// requestSchedulerFor returns the scheduler shared by all scrapers of @exchange.
+func requestSchedulerFor(exchange string) *requestScheduler {
+	requestSchedulersLock.Lock()
+	defer requestSchedulersLock.Unlock()
+	s, ok := requestSchedulers[exchange]
+	if !ok {
+		limit, ok := rateLimits[exchange]
+		if !ok {
+			limit = defaultRateLimit
+		}
+		s = newRequestScheduler(exchange, limit)
+		requestSchedulers[exchange] = s
+	}
+	return s
+}
+
+// setRateLimit overwrites the limit of @exchange, e.g. from the config of a generic scraper.
+func setRateLimit(exchange string, limit rateLimit) {
+	requestSchedulersLock.Lock()
+	defer requestSchedulersLock.Unlock()
+	requestSchedulers[exchange] = newRequestScheduler(exchange, limit)
+}
+
+func newRequestScheduler(exchange string, limit rateLimit) *requestScheduler {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	proxies := proxyHelper.For(exchange)
+	if n := proxies.Len(); n > 1 {
+		// Exchanges limit the requests per IP, each proxy adds its own limit.
+		limit.Rate *= float64(n)
+		limit.Burst *= n
+	}
+	return &requestScheduler{
+		exchange: exchange,
+		limit:    limit,
+		backoff:  utils.Backoff{Initial: 5 * time.Second, Max: 5 * time.Minute, Factor: 2, Attempts: 5},
+		now:      time.Now,
+		client:   &http.Client{Timeout: 30 * time.Second, Transport: proxies.Transport()},
+		proxies:  proxies,
+		tokens:   float64(limit.Burst),
+	}
+}
+
+// reserve takes a token and returns the time until it may be used.
+func (s *requestScheduler) reserve() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if !s.last.IsZero() && s.limit.Rate > 0 {
+		s.tokens += now.Sub(s.last).Seconds() * s.limit.Rate
+		if s.tokens > float64(s.limit.Burst) {
+			s.tokens = float64(s.limit.Burst)
+		}
+	}
+	s.last = now
+	s.tokens--
+
+	var wait time.Duration
+	if s.tokens < 0 && s.limit.Rate > 0 {
+		wait = time.Duration(-s.tokens / s.limit.Rate * float64(time.Second))
+	}
+	if pause := s.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+	return wait
+}
+
+// Wait blocks until the next request to the exchange may be sent.
+func (s *requestScheduler) Wait() {
+	if wait := s.reserve(); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// pause stops all requests for @retryAfter, or the backoff delay if it is 0, and returns the
+// pause.
+func (s *requestScheduler) pause(retryAfter time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if retryAfter <= 0 {
+		retryAfter = s.backoff.Delay(s.rateLimited)
+	}
+	s.rateLimited++
+	if until := s.now().Add(retryAfter); until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+	return retryAfter
+}
+
+func (s *requestScheduler) succeeded() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimited = 0
+}
+
+// Get returns the body of a GET request to @url once the rate limit admits it. Rate limited
+// responses and server errors are retried up to the attempts of the backoff, other responses
+// than 200 are returned as errors. While a recording is replayed, the recorded responses are
+// returned without any request.
+func (s *requestScheduler) Get(url string) ([]byte, error) {
+	if recording := recordHelper.Active(); recording != nil {
+		return recording.Response(url)
+	}
+	var err error
+	for attempt := 0; attempt < s.backoff.Attempts; attempt++ {
+		s.Wait()
+		proxy := s.proxies.Next()
+		var request *http.Request
+		request, err = http.NewRequestWithContext(proxyHelper.WithProxy(context.Background(), proxy), http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response *http.Response
+		response, err = s.client.Do(request)
+		if err != nil {
+			s.proxies.Park(proxy, 0)
+			continue
+		}
+		body, readErr := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+
+		switch {
+		case response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusTeapot:
+			// Binance answers 418 once an IP ignored its 429s and is banned.
+			rateLimitedCounter.Inc(s.exchange)
+			err = fmt.Errorf("%s returned status %d", url, response.StatusCode)
+			if s.proxies.Len() > 1 {
+				// Only the IP of the proxy is limited, requests go on through the others.
+				s.proxies.Park(proxy, retryAfter(response.Header.Get("Retry-After")))
+				log.Warnf("%s: rate limited through proxy %s", s.exchange, proxy.Host)
+				break
+			}
+			pause := s.pause(retryAfter(response.Header.Get("Retry-After")))
+			log.Warnf("%s: rate limited, pausing requests for %v", s.exchange, pause)
+		case response.StatusCode >= 500:
+			err = fmt.Errorf("%s returned status %d", url, response.StatusCode)
+			time.Sleep(s.backoff.Delay(attempt))
+		case response.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("HTTP Response Error %d", response.StatusCode)
+		case readErr != nil:
+			return nil, readErr
+		default:
+			s.succeeded()
+			recordHelper.Record(recordHelper.KindResponse, s.exchange, url, body)
+			return body, nil
+		}
+	}
+	return nil, err
+}
+
+// retryAfter parses a Retry-After header in seconds. Dates and missing headers return 0.
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,55 @@
+package scrapers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+func TestRequestSchedulerReserve(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newRequestScheduler("test", rateLimit{Rate: 2, Burst: 2})
+	s.now = func() time.Time { return now }
+
+	// The burst is sent at once, further requests are spaced by the rate.
+	for i, expected := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if wait := s.reserve(); wait != expected {
+			t.Errorf("request %d waits %v, expected %v", i, wait, expected)
+		}
+	}
+
+	now = now.Add(10 * time.Second)
+	s.pause(time.Minute)
+	if wait := s.reserve(); wait != time.Minute {
+		t.Errorf("request after rate limit waits %v, expected the pause of 1m0s", wait)
+	}
+}
+
+func TestRequestSchedulerGet(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("trades"))
+	}))
+	defer server.Close()
+
+	s := newRequestScheduler("test", rateLimit{Rate: 100, Burst: 1})
+	s.backoff = utils.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Factor: 2, Attempts: 3}
+	body, err := s.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); string(body) != "trades" || n != 2 {
+		t.Errorf("got %s after %d requests, expected trades after 2", body, n)
+	}
+	if s.rateLimited != 0 {
+		t.Errorf("got %d rate limited responses after success, expected 0", s.rateLimited)
+	}
+}
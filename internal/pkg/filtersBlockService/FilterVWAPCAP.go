@@ -0,0 +1,202 @@
+// FilterVWAPCAP implements a volume weighted average price across exchange pairs in which the
+// weight of a single exchange pair is capped, so that one manipulated venue can only move the
+// price by a limited amount. The volume of a DEX pool counts at most up to the TVL of the pool, so
+// that wash trading in a thin pool doesn't buy weight. Named dia.FilterKing, it publishes the
+// aggregated price of an asset.
+package filters
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+var volumeCapsBound = metrics.NewCounter("filters", "volume_caps_bound", "Blocks in which the volume share of an exchange pair was capped.", "symbol", "source")
+
+// FilterVWAPCAP contains the configuration parameters of the filter
+type FilterVWAPCAP struct {
+	symbol      string
+	exchange    string
+	currentTime time.Time
+	maxShare    float64
+	// volumes and turnovers hold the USD volume and the sum of price times volume of each
+	// exchange pair in the current block.
+	volumes   map[string]float64
+	turnovers map[string]float64
+	// poolTVL returns the TVL in USD of the pool of an exchange pair of a DEX. It is nil if
+	// volumes aren't bounded by liquidity.
+	poolTVL    func(source string) (float64, bool)
+	value      float64
+	filterName string
+	modified   bool
+}
+
+// NewFilterVWAPCAP creates a FilterVWAPCAP called @filterName in which no exchange pair has a
+// weight above @maxShare, e.g. 0.4. A @maxShare of 1 or above disables the cap. The volume of an
+// exchange pair with a TVL from @poolTVL is bounded by it; @poolTVL may be nil.
+func NewFilterVWAPCAP(symbol string, exchange string, filterName string, maxShare float64, poolTVL func(source string) (float64, bool)) *FilterVWAPCAP {
+	s := &FilterVWAPCAP{
+		symbol:     symbol,
+		exchange:   exchange,
+		maxShare:   maxShare,
+		volumes:    make(map[string]float64),
+		turnovers:  make(map[string]float64),
+		poolTVL:    poolTVL,
+		filterName: filterName,
+	}
+	return s
+}
+
+func (s *FilterVWAPCAP) compute(trade dia.Trade) {
+	volume := math.Abs(trade.Volume) * trade.EstimatedUSDPrice
+	if volume <= 0 {
+		return
+	}
+	source := trade.Source + ":" + trade.Pair
+	s.volumes[source] += volume
+	s.turnovers[source] += volume * trade.EstimatedUSDPrice
+	s.currentTime = trade.Time
+	s.modified = true
+}
+
+func (s *FilterVWAPCAP) finalCompute(t time.Time) float64 {
+	if len(s.volumes) == 0 {
+		return s.value
+	}
+	var sources []string
+	for source := range s.volumes {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	var total float64
+	weights := make([]float64, len(sources))
+	for i, source := range sources {
+		weights[i] = s.volumes[source]
+		if s.poolTVL != nil {
+			if tvl, ok := s.poolTVL(source); ok && tvl < weights[i] {
+				weights[i] = tvl
+			}
+		}
+		total += weights[i]
+	}
+	if total == 0 {
+		s.volumes = make(map[string]float64)
+		s.turnovers = make(map[string]float64)
+		return s.value
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	weights, capped := capWeights(weights, s.maxShare)
+	for _, i := range capped {
+		log.Debugf("FilterVWAPCAP: capped volume share of %s in %s at %v", sources[i], s.symbol, s.maxShare)
+		volumeCapsBound.Inc(s.symbol, sources[i])
+	}
+
+	s.value = 0
+	for i, source := range sources {
+		s.value += weights[i] * s.turnovers[source] / s.volumes[source]
+	}
+	s.volumes = make(map[string]float64)
+	s.turnovers = make(map[string]float64)
+	return s.value
+}
+
+func (s *FilterVWAPCAP) filterPointForBlock() *dia.FilterPoint {
+	if s.exchange != "" || s.filterName != dia.FilterKing {
+		return nil
+	}
+	return &dia.FilterPoint{
+		Symbol: s.symbol,
+		Value:  s.value,
+		Name:   s.filterName,
+		Time:   s.currentTime,
+	}
+}
+
+func (s *FilterVWAPCAP) save(ds models.Datastore) error {
+	if !s.modified {
+		return nil
+	}
+	s.modified = false
+	if s.filterName != dia.FilterKing {
+		err := ds.SetFilter(s.filterName, s.symbol, s.exchange, s.value, s.currentTime)
+		if err != nil {
+			log.Errorln("FilterVWAPCAP: Error:", err)
+		}
+		return err
+	}
+	// As king filter, the value is the price of the asset, as written by FilterMAIR otherwise.
+	err := ds.SetPriceZSET(s.symbol, s.exchange, s.value, s.currentTime)
+	if err != nil {
+		log.Errorln("FilterVWAPCAP: Error:", err)
+	}
+	if s.exchange == "" {
+		err = ds.SetPriceUSD(s.symbol, s.value)
+		if err != nil {
+			log.Errorln("FilterVWAPCAP: Error:", err)
+		}
+	}
+	return err
+}
+
+// capWeights limits the normalized @weights to @maxShare each and redistributes the excess
+// proportionally across the weights below the cap. It returns the new weights and the indices
+// of the capped ones. If the cap can't be met, i.e. there are no more than 1/@maxShare weights,
+// the weights are returned unchanged, as equal weights would give a thin venue as much say as
+// a deep one.
+func capWeights(weights []float64, maxShare float64) ([]float64, []int) {
+	if maxShare >= 1 || float64(len(weights))*maxShare <= 1 {
+		return weights, nil
+	}
+	result := make([]float64, len(weights))
+	copy(result, weights)
+
+	isCapped := make([]bool, len(weights))
+	for {
+		var excess, free float64
+		for i, w := range result {
+			if isCapped[i] {
+				continue
+			}
+			if w > maxShare {
+				excess += w - maxShare
+				result[i] = maxShare
+				isCapped[i] = true
+			} else {
+				free += w
+			}
+		}
+		if excess == 0 {
+			break
+		}
+		var uncapped int
+		for i := range result {
+			if !isCapped[i] {
+				uncapped++
+			}
+		}
+		for i, w := range result {
+			if isCapped[i] {
+				continue
+			}
+			if free > 0 {
+				result[i] = w + excess*w/free
+			} else {
+				result[i] = w + excess/float64(uncapped)
+			}
+		}
+	}
+	var capped []int
+	for i := range isCapped {
+		if isCapped[i] {
+			capped = append(capped, i)
+		}
+	}
+	return result, capped
+}
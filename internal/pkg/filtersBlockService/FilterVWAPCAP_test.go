@@ -0,0 +1,71 @@
+package filters
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestCapWeights(t *testing.T) {
+	cases := []struct {
+		weights  []float64
+		maxShare float64
+		expected []float64
+		capped   int
+	}{
+		{[]float64{0.2, 0.3, 0.5}, 1, []float64{0.2, 0.3, 0.5}, 0},
+		{[]float64{0.2, 0.2, 0.2, 0.4}, 0.4, []float64{0.2, 0.2, 0.2, 0.4}, 0},
+		// the excess of 0.3 is redistributed proportionally
+		{[]float64{0.7, 0.1, 0.2}, 0.4, []float64{0.4, 0.2, 0.4}, 1},
+		{[]float64{0.8, 0.05, 0.05, 0.1}, 0.4, []float64{0.4, 0.15, 0.15, 0.3}, 1},
+		// the cap can't be met by two sources, which keep their weights
+		{[]float64{0.9, 0.1}, 0.4, []float64{0.9, 0.1}, 0},
+		{[]float64{0.6, 0.3, 0.1}, 0.3, []float64{0.6, 0.3, 0.1}, 0},
+	}
+	for i, c := range cases {
+		result, capped := capWeights(c.weights, c.maxShare)
+		if len(capped) != c.capped {
+			t.Errorf("case %d: expected %d capped weights, got %v", i, c.capped, capped)
+		}
+		for j := range result {
+			if math.Abs(result[j]-c.expected[j]) > 1e-9 {
+				t.Errorf("case %d: expected %v, got %v", i, c.expected, result)
+				break
+			}
+		}
+	}
+}
+
+func TestFilterVWAPCAP(t *testing.T) {
+	d := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFilterVWAPCAP("BTC", "", dia.FilterKing, 0.5, nil)
+	// A manipulated venue with most of the volume only gets half of the weight.
+	f.compute(dia.Trade{Source: "A", Pair: "BTCUSDT", EstimatedUSDPrice: 200, Volume: 9, Time: d})
+	f.compute(dia.Trade{Source: "B", Pair: "BTCUSDT", EstimatedUSDPrice: 100, Volume: 1, Time: d})
+	f.compute(dia.Trade{Source: "C", Pair: "BTCUSD", EstimatedUSDPrice: 100, Volume: 1, Time: d})
+	if v := f.finalCompute(d); math.Abs(v-150) > 1e-9 {
+		t.Errorf("expected 150, got %v", v)
+	}
+	// Without trades the last value is kept.
+	if v := f.finalCompute(d.Add(time.Minute)); math.Abs(v-150) > 1e-9 {
+		t.Errorf("expected 150 without trades, got %v", v)
+	}
+}
+
+func TestFilterVWAPCAPPoolTVL(t *testing.T) {
+	d := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tvls := map[string]float64{"Uniswap:ETH-USDC": 100}
+	poolTVL := func(source string) (float64, bool) {
+		tvl, ok := tvls[source]
+		return tvl, ok
+	}
+	f := NewFilterVWAPCAP("ETH", "", dia.FilterKing, 1, poolTVL)
+	// The volume of the thin pool only counts up to its TVL of 100 USD.
+	f.compute(dia.Trade{Source: "Uniswap", Pair: "ETH-USDC", EstimatedUSDPrice: 200, Volume: 10, Time: d})
+	f.compute(dia.Trade{Source: "B", Pair: "ETHUSDT", EstimatedUSDPrice: 100, Volume: 1, Time: d})
+	if v := f.finalCompute(d); math.Abs(v-150) > 1e-9 {
+		t.Errorf("expected 150, got %v", v)
+	}
+}
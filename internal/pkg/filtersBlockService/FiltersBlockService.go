@@ -13,10 +13,20 @@ import (
 
 const (
 	filtersParam = dia.BlockSizeSeconds
+	// poolTVLRefresh is the time after which the TVLs of DEX pools are read again.
+	poolTVLRefresh = 10 * time.Minute
+	// equivalenceRefresh is the time after which the representations of canonical assets are
+	// read again.
+	equivalenceRefresh = 10 * time.Minute
 )
 
 type nothing struct{}
 
+// EquivalenceStore holds the bridged and wrapped representations of canonical assets.
+type EquivalenceStore interface {
+	GetAssetEquivalences(canonical string) ([]models.AssetEquivalence, error)
+}
+
 type FiltersBlockService struct {
 	shutdown             chan nothing
 	shutdownDone         chan nothing
@@ -32,9 +42,24 @@ type FiltersBlockService struct {
 	calculationValues    []int
 	previousBlockFilters []dia.FilterPoint
 	datastore            models.Datastore
+	// maxVolumeShare caps the weight of a single exchange pair in the price across exchanges.
+	maxVolumeShare float64
+	// poolTVLs holds the TVL in USD of the pools of DEX exchange pairs by exchange:pair, in
+	// both orders of the pair.
+	poolTVLs        map[string]float64
+	poolTVLsUpdated time.Time
+	// equivalences are read into canonicals, the canonical symbols of assets by blockchain:address.
+	equivalences        EquivalenceStore
+	canonicals          map[string]string
+	equivalencesUpdated time.Time
 }
 
-func NewFiltersBlockService(previousBlockFilters []dia.FilterPoint, datastore models.Datastore, chanFiltersBlock chan *dia.FiltersBlock) *FiltersBlockService {
+// NewFiltersBlockService returns a service computing the filters of trades blocks. In the price
+// across exchanges, no exchange pair gets a weight above @maxVolumeShare; a @maxVolumeShare of 1
+// or above keeps the moving average MAIR as price instead. Trades of assets
+// declared equivalent in @equivalences, which may be nil, feed the filters of their canonical
+// symbol, too.
+func NewFiltersBlockService(previousBlockFilters []dia.FilterPoint, datastore models.Datastore, chanFiltersBlock chan *dia.FiltersBlock, maxVolumeShare float64, equivalences EquivalenceStore) *FiltersBlockService {
 	s := &FiltersBlockService{
 		shutdown:             make(chan nothing),
 		shutdownDone:         make(chan nothing),
@@ -47,6 +72,8 @@ func NewFiltersBlockService(previousBlockFilters []dia.FilterPoint, datastore mo
 		calculationValues:    make([]int, 0),
 		previousBlockFilters: previousBlockFilters,
 		datastore:            datastore,
+		maxVolumeShare:       maxVolumeShare,
+		equivalences:         equivalences,
 	}
 	s.calculationValues = append(s.calculationValues, dia.BlockSizeSeconds)
 
@@ -115,12 +142,18 @@ func addMissingPoints(previousBlockFilters []dia.FilterPoint, newFilters []dia.F
 func (s *FiltersBlockService) createFilters(symbol string, exchange string, BeginTime time.Time) {
 	_, ok := s.filters[symbol+exchange]
 	if !ok {
+		// The price across exchanges is published by the capped volume weighted average unless
+		// the cap is disabled, so that a single exchange pair can't dominate it.
+		var king Filter = NewFilterMAIR(symbol, exchange, BeginTime, dia.BlockSizeSeconds)
+		if exchange == "" && s.maxVolumeShare < 1 {
+			king = NewFilterVWAPCAP(symbol, exchange, dia.FilterKing, s.maxVolumeShare, s.poolTVL)
+		}
 		s.filters[symbol+exchange] = []Filter{
 			// Prices are written into redis in MA filter
 			NewFilterMA(symbol, exchange, BeginTime, dia.BlockSizeSeconds),
 			NewFilterTLT(symbol, exchange),
 			NewFilterVOL(symbol, exchange, dia.BlockSizeSeconds),
-			NewFilterMAIR(symbol, exchange, BeginTime, dia.BlockSizeSeconds),
+			king,
 			NewFilterMEDIR(symbol, exchange, BeginTime, dia.BlockSizeSeconds),
 		}
 	}
@@ -137,12 +170,25 @@ func (s *FiltersBlockService) computeFilters(t dia.Trade, key string) {
 func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 
 	log.Infoln("processTradesBlock starting")
+	if time.Since(s.poolTVLsUpdated) > poolTVLRefresh {
+		s.refreshPoolTVLs()
+	}
+	if s.equivalences != nil && time.Since(s.equivalencesUpdated) > equivalenceRefresh {
+		s.refreshEquivalences()
+	}
 
 	for _, trade := range tb.TradesBlockData.Trades {
 		s.createFilters(trade.Symbol, "", tb.TradesBlockData.BeginTime)
 		s.createFilters(trade.Symbol, trade.Source, tb.TradesBlockData.BeginTime)
 		s.computeFilters(trade, trade.Symbol)
 		s.computeFilters(trade, trade.Symbol+trade.Source)
+		// Representations keep their own price and feed the price of their canonical asset.
+		if canonical := s.canonicalSymbol(trade); canonical != "" && canonical != trade.Symbol {
+			s.createFilters(canonical, "", tb.TradesBlockData.BeginTime)
+			s.createFilters(canonical, trade.Source, tb.TradesBlockData.BeginTime)
+			s.computeFilters(trade, canonical)
+			s.computeFilters(trade, canonical+trade.Source)
+		}
 	}
 
 	resultFilters := []dia.FilterPoint{}
@@ -196,6 +242,54 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 	// }
 }
 
+// refreshPoolTVLs reads the current TVLs of DEX pools. If there are none, volumes of DEX pairs
+// aren't bounded.
+func (s *FiltersBlockService) refreshPoolTVLs() {
+	s.poolTVLsUpdated = time.Now()
+	liquidities, err := s.datastore.GetLatestPoolLiquidity(nil, "")
+	if err != nil {
+		log.Errorln("refreshPoolTVLs:", err)
+		return
+	}
+	s.poolTVLs = make(map[string]float64)
+	for _, liquidity := range liquidities {
+		s.poolTVLs[liquidity.Exchange+":"+liquidity.Token0.Symbol+"-"+liquidity.Token1.Symbol] += liquidity.TVLUSD
+		s.poolTVLs[liquidity.Exchange+":"+liquidity.Token1.Symbol+"-"+liquidity.Token0.Symbol] += liquidity.TVLUSD
+	}
+	log.Infof("refreshPoolTVLs: got TVL of %d pools", len(liquidities))
+}
+
+// poolTVL returns the TVL of the pool of the exchange pair @source of a DEX, if it is known.
+func (s *FiltersBlockService) poolTVL(source string) (float64, bool) {
+	tvl, ok := s.poolTVLs[source]
+	return tvl, ok
+}
+
+// refreshEquivalences reads the representations of canonical assets. If reading fails, the
+// previous ones are kept.
+func (s *FiltersBlockService) refreshEquivalences() {
+	s.equivalencesUpdated = time.Now()
+	equivalences, err := s.equivalences.GetAssetEquivalences("")
+	if err != nil {
+		log.Errorln("refreshEquivalences:", err)
+		return
+	}
+	s.canonicals = make(map[string]string)
+	for _, equivalence := range equivalences {
+		s.canonicals[equivalence.Asset.Blockchain+":"+equivalence.Asset.Address] = equivalence.Canonical
+	}
+	log.Infof("refreshEquivalences: got %d representations of canonical assets", len(equivalences))
+}
+
+// canonicalSymbol returns the canonical symbol of the asset traded in @trade, if the asset is
+// a declared representation. Only trades of pairs mapped to assets are considered.
+func (s *FiltersBlockService) canonicalSymbol(trade dia.Trade) string {
+	if trade.QuoteAsset == nil {
+		return ""
+	}
+	return s.canonicals[trade.QuoteAsset.Blockchain+":"+trade.QuoteAsset.Address]
+}
+
 // runs in a goroutine until s is closed
 func (s *FiltersBlockService) mainLoop() {
 	for {
@@ -0,0 +1,41 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+type testEquivalences []models.AssetEquivalence
+
+func (e testEquivalences) GetAssetEquivalences(canonical string) ([]models.AssetEquivalence, error) {
+	return e, nil
+}
+
+func TestCanonicalSymbol(t *testing.T) {
+	weth := dia.Asset{Symbol: "WETH", Blockchain: "Polygon", Address: "0x7ceb23fd6bc0add59e62ac25578270cff1b9f619"}
+	wethE := dia.Asset{Symbol: "WETH.e", Blockchain: "Avalanche", Address: "0x49d5c2bdffac6ce2bfdb6640f4f80f226bc10bab"}
+	s := &FiltersBlockService{equivalences: testEquivalences{
+		{Canonical: "ETH", Asset: weth},
+		{Canonical: "ETH", Asset: wethE},
+	}}
+	s.refreshEquivalences()
+
+	cases := []struct {
+		trade    dia.Trade
+		expected string
+	}{
+		{dia.Trade{Symbol: "WETH", QuoteAsset: &weth}, "ETH"},
+		{dia.Trade{Symbol: "WETH.e", QuoteAsset: &wethE}, "ETH"},
+		// WETH on Ethereum isn't declared a representation.
+		{dia.Trade{Symbol: "WETH", QuoteAsset: &dia.Asset{Symbol: "WETH", Blockchain: "Ethereum", Address: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"}}, ""},
+		// Trades of unmapped pairs have no assets.
+		{dia.Trade{Symbol: "WETH"}, ""},
+	}
+	for i, c := range cases {
+		if canonical := s.canonicalSymbol(c.trade); canonical != c.expected {
+			t.Errorf("case %d: got %q, expected %q", i, canonical, c.expected)
+		}
+	}
+}
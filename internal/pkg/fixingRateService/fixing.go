@@ -0,0 +1,96 @@
+package fixingRateService
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// Methodology of the fixing: volume-weighted median of the USD prices of all trades
+// in a 60 minute window centered around the fixing time.
+const (
+	Methodology  = "VWMEDIAN-60M-1600UTC"
+	FixingHour   = 16
+	WindowLength = 60 * time.Minute
+)
+
+// ErrNoTrades is returned if there were no trades in the fixing window.
+var ErrNoTrades = errors.New("no trades in fixing window")
+
+// FixingTime returns the fixing time on the day of @t.
+func FixingTime(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), FixingHour, 0, 0, 0, time.UTC)
+}
+
+// LastFixingTime returns the most recent fixing time whose window is closed at @now.
+func LastFixingTime(now time.Time) time.Time {
+	fixingTime := FixingTime(now)
+	if now.Before(fixingTime.Add(WindowLength / 2)) {
+		fixingTime = fixingTime.AddDate(0, 0, -1)
+	}
+	return fixingTime
+}
+
+// Window returns the trade window of the fixing at @fixingTime.
+func Window(fixingTime time.Time) (starttime time.Time, endtime time.Time) {
+	return fixingTime.Add(-WindowLength / 2), fixingTime.Add(WindowLength / 2)
+}
+
+// VolumeWeightedMedian returns the USD price at which the cumulated absolute volume of all
+// trades sorted by price reaches half of the total volume, along with the total volume.
+func VolumeWeightedMedian(trades []dia.Trade) (price float64, volume float64, err error) {
+	type sample struct {
+		price  float64
+		volume float64
+	}
+	var samples []sample
+	for _, t := range trades {
+		if t.EstimatedUSDPrice <= 0 || t.Volume == 0 {
+			continue
+		}
+		samples = append(samples, sample{price: t.EstimatedUSDPrice, volume: math.Abs(t.Volume)})
+		volume += math.Abs(t.Volume)
+	}
+	if len(samples) == 0 {
+		return 0, 0, ErrNoTrades
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].price < samples[j].price })
+
+	var cumulated float64
+	for _, s := range samples {
+		cumulated += s.volume
+		if cumulated >= volume/2 {
+			return s.price, volume, nil
+		}
+	}
+	return samples[len(samples)-1].price, volume, nil
+}
+
+// ComputeFixingRate computes the fixing rate of @symbol at @fixingTime from the trades in influx.
+func ComputeFixingRate(ds models.Datastore, symbol string, fixingTime time.Time) (models.FixingRate, error) {
+	starttime, endtime := Window(fixingTime)
+	trades, err := ds.GetTradesByTimerange(symbol, starttime, endtime)
+	if err != nil {
+		return models.FixingRate{}, err
+	}
+	price, volume, err := VolumeWeightedMedian(trades)
+	if err != nil {
+		return models.FixingRate{}, err
+	}
+	return models.FixingRate{
+		Symbol:      symbol,
+		Price:       price,
+		Volume:      volume,
+		NumTrades:   len(trades),
+		Methodology: Methodology,
+		WindowStart: starttime,
+		WindowEnd:   endtime,
+		FixingTime:  fixingTime,
+		ComputedAt:  time.Now(),
+	}, nil
+}
@@ -0,0 +1,49 @@
+package fixingRateService
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestVolumeWeightedMedian(t *testing.T) {
+	trades := []dia.Trade{
+		{EstimatedUSDPrice: 101, Volume: 1},
+		{EstimatedUSDPrice: 99, Volume: -1},
+		{EstimatedUSDPrice: 100, Volume: 5},
+		{EstimatedUSDPrice: 150, Volume: 0.5},
+		{EstimatedUSDPrice: 0, Volume: 10},
+	}
+	price, volume, err := VolumeWeightedMedian(trades)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != 100 {
+		t.Errorf("expected price 100, got %v", price)
+	}
+	if volume != 7.5 {
+		t.Errorf("expected volume 7.5, got %v", volume)
+	}
+
+	_, _, err = VolumeWeightedMedian(nil)
+	if err != ErrNoTrades {
+		t.Errorf("expected ErrNoTrades, got %v", err)
+	}
+}
+
+func TestLastFixingTime(t *testing.T) {
+	cases := []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		{time.Date(2021, 9, 1, 16, 29, 0, 0, time.UTC), time.Date(2021, 8, 31, 16, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 9, 1, 16, 30, 0, 0, time.UTC), time.Date(2021, 9, 1, 16, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 9, 1, 23, 0, 0, 0, time.UTC), time.Date(2021, 9, 1, 16, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		if result := LastFixingTime(c.now); !result.Equal(c.expected) {
+			t.Errorf("LastFixingTime(%v): expected %v, got %v", c.now, c.expected, result)
+		}
+	}
+}
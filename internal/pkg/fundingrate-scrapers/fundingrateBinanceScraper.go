@@ -0,0 +1,84 @@
+package fundingratescrapers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	binancePremiumIndexURL = "https://fapi.binance.com/fapi/v1/premiumIndex"
+	binanceFundingInfoURL  = "https://fapi.binance.com/fapi/v1/fundingInfo"
+	// binanceFundingInterval is the funding interval of instruments without own interval.
+	binanceFundingInterval = 8 * time.Hour
+)
+
+// BinanceFundingRateScraper fetches funding rates from the Binance USDⓈ-M futures API.
+type BinanceFundingRateScraper struct{}
+
+// NewBinanceFundingRateScraper returns a BinanceFundingRateScraper.
+func NewBinanceFundingRateScraper() *BinanceFundingRateScraper {
+	return &BinanceFundingRateScraper{}
+}
+
+type binancePremiumIndex struct {
+	Symbol          string `json:"symbol"`
+	LastFundingRate string `json:"lastFundingRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+	Time            int64  `json:"time"`
+}
+
+// binanceFundingInfo lists the instruments whose funding differs from the default, e.g. with an
+// interval of 4 hours.
+type binanceFundingInfo struct {
+	Symbol               string `json:"symbol"`
+	FundingIntervalHours int    `json:"fundingIntervalHours"`
+}
+
+// FetchFundingRates implements FundingRateScraper.
+func (s *BinanceFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	var indices []binancePremiumIndex
+	err := utils.GetJSONWithBackoff(binancePremiumIndexURL, &indices)
+	if err != nil {
+		return nil, err
+	}
+	var infos []binanceFundingInfo
+	err = utils.GetJSONWithBackoff(binanceFundingInfoURL, &infos)
+	if err != nil {
+		return nil, err
+	}
+	intervals := make(map[string]time.Duration)
+	for _, info := range infos {
+		intervals[info.Symbol] = time.Duration(info.FundingIntervalHours) * time.Hour
+	}
+
+	var rates []dia.FundingRate
+	for _, index := range indices {
+		symbol, ok := perpetualAsset(index.Symbol)
+		if !ok {
+			// Delivery contracts, e.g. BTCUSDT_230331, have no funding.
+			continue
+		}
+		rate, err := strconv.ParseFloat(index.LastFundingRate, 64)
+		if err != nil {
+			log.Errorf("funding rate of %s: %v", index.Symbol, err)
+			continue
+		}
+		interval, ok := intervals[index.Symbol]
+		if !ok || interval == 0 {
+			interval = binanceFundingInterval
+		}
+		rates = append(rates, dia.FundingRate{
+			Symbol:          symbol,
+			Instrument:      index.Symbol,
+			Exchange:        dia.BinanceExchange,
+			Rate:            rate,
+			Interval:        interval,
+			NextFundingTime: time.Unix(0, index.NextFundingTime*int64(time.Millisecond)),
+			Time:            time.Unix(0, index.Time*int64(time.Millisecond)),
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,74 @@
+package fundingratescrapers
+
+import (
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	bitmexActiveInstrumentsURL = "https://www.bitmex.com/api/v1/instrument/active"
+	// bitmexPerpetualType is the CFI code of perpetual swaps on BitMEX.
+	bitmexPerpetualType = "FFWCSX"
+)
+
+// bitmexEpoch is the origin of the funding intervals of BitMEX, which are given as timestamps,
+// e.g. 2000-01-01T08:00:00.000Z for 8 hours.
+var bitmexEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// bitmexSymbols maps the symbols of BitMEX to the common ones.
+var bitmexSymbols = map[string]string{"XBT": "BTC"}
+
+// BitMEXFundingRateScraper fetches funding rates from the BitMEX API.
+type BitMEXFundingRateScraper struct{}
+
+// NewBitMEXFundingRateScraper returns a BitMEXFundingRateScraper.
+func NewBitMEXFundingRateScraper() *BitMEXFundingRateScraper {
+	return &BitMEXFundingRateScraper{}
+}
+
+type bitmexInstrument struct {
+	Symbol                string    `json:"symbol"`
+	Typ                   string    `json:"typ"`
+	Underlying            string    `json:"underlying"`
+	FundingRate           *float64  `json:"fundingRate"`
+	IndicativeFundingRate *float64  `json:"indicativeFundingRate"`
+	FundingInterval       time.Time `json:"fundingInterval"`
+	FundingTimestamp      time.Time `json:"fundingTimestamp"`
+	Timestamp             time.Time `json:"timestamp"`
+}
+
+// FetchFundingRates implements FundingRateScraper. On BitMEX, the rate of the current interval
+// is fixed at its start and the indicative rate is the predicted rate of the next interval.
+func (s *BitMEXFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	var instruments []bitmexInstrument
+	err := utils.GetJSONWithBackoff(bitmexActiveInstrumentsURL, &instruments)
+	if err != nil {
+		return nil, err
+	}
+	var rates []dia.FundingRate
+	for _, instrument := range instruments {
+		if instrument.Typ != bitmexPerpetualType || instrument.FundingRate == nil {
+			continue
+		}
+		symbol := instrument.Underlying
+		if common, ok := bitmexSymbols[symbol]; ok {
+			symbol = common
+		}
+		rate := dia.FundingRate{
+			Symbol:          symbol,
+			Instrument:      instrument.Symbol,
+			Exchange:        dia.BitMEXExchange,
+			Rate:            *instrument.FundingRate,
+			Interval:        instrument.FundingInterval.Sub(bitmexEpoch),
+			NextFundingTime: instrument.FundingTimestamp,
+			Time:            instrument.Timestamp,
+		}
+		if instrument.IndicativeFundingRate != nil {
+			rate.PredictedRate = *instrument.IndicativeFundingRate
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -0,0 +1,118 @@
+package fundingratescrapers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	bybitTickersURL     = "https://api.bybit.com/v5/market/tickers?category=linear"
+	bybitInstrumentsURL = "https://api.bybit.com/v5/market/instruments-info?category=linear&limit=1000"
+)
+
+// BybitFundingRateScraper fetches funding rates from the Bybit API.
+type BybitFundingRateScraper struct{}
+
+// NewBybitFundingRateScraper returns a BybitFundingRateScraper.
+func NewBybitFundingRateScraper() *BybitFundingRateScraper {
+	return &BybitFundingRateScraper{}
+}
+
+type bybitTickers struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol          string `json:"symbol"`
+			FundingRate     string `json:"fundingRate"`
+			NextFundingTime string `json:"nextFundingTime"`
+		} `json:"list"`
+	} `json:"result"`
+	Time int64 `json:"time"`
+}
+
+type bybitInstruments struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol string `json:"symbol"`
+			// FundingInterval is in minutes.
+			FundingInterval int `json:"fundingInterval"`
+		} `json:"list"`
+		NextPageCursor string `json:"nextPageCursor"`
+	} `json:"result"`
+}
+
+// fetchFundingIntervals returns the funding interval of each linear instrument.
+func (s *BybitFundingRateScraper) fetchFundingIntervals() (map[string]time.Duration, error) {
+	intervals := make(map[string]time.Duration)
+	cursor := ""
+	for {
+		var instruments bybitInstruments
+		err := utils.GetJSONWithBackoff(bybitInstrumentsURL+"&cursor="+url.QueryEscape(cursor), &instruments)
+		if err != nil {
+			return nil, err
+		}
+		if instruments.RetCode != 0 {
+			return nil, fmt.Errorf("bybit instruments: %s", instruments.RetMsg)
+		}
+		for _, instrument := range instruments.Result.List {
+			intervals[instrument.Symbol] = time.Duration(instrument.FundingInterval) * time.Minute
+		}
+		cursor = instruments.Result.NextPageCursor
+		if cursor == "" {
+			return intervals, nil
+		}
+	}
+}
+
+// FetchFundingRates implements FundingRateScraper.
+func (s *BybitFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	var tickers bybitTickers
+	err := utils.GetJSONWithBackoff(bybitTickersURL, &tickers)
+	if err != nil {
+		return nil, err
+	}
+	if tickers.RetCode != 0 {
+		return nil, fmt.Errorf("bybit tickers: %s", tickers.RetMsg)
+	}
+	intervals, err := s.fetchFundingIntervals()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Unix(0, tickers.Time*int64(time.Millisecond))
+	var rates []dia.FundingRate
+	for _, ticker := range tickers.Result.List {
+		symbol, ok := perpetualAsset(ticker.Symbol)
+		// Delivery contracts have no funding rate.
+		if !ok || ticker.FundingRate == "" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(ticker.FundingRate, 64)
+		if err != nil {
+			log.Errorf("funding rate of %s: %v", ticker.Symbol, err)
+			continue
+		}
+		nextFundingTime, err := strconv.ParseInt(ticker.NextFundingTime, 10, 64)
+		if err != nil {
+			log.Errorf("next funding time of %s: %v", ticker.Symbol, err)
+			continue
+		}
+		rates = append(rates, dia.FundingRate{
+			Symbol:          symbol,
+			Instrument:      ticker.Symbol,
+			Exchange:        dia.BybitExchange,
+			Rate:            rate,
+			Interval:        intervals[ticker.Symbol],
+			NextFundingTime: time.Unix(0, nextFundingTime*int64(time.Millisecond)),
+			Time:            now,
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,59 @@
+package fundingratescrapers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const dydxPerpetualMarketsURL = "https://indexer.dydx.trade/v4/perpetualMarkets"
+
+// DydxFundingRateScraper fetches funding rates from the indexer of dYdX v4.
+type DydxFundingRateScraper struct{}
+
+// NewDydxFundingRateScraper returns a DydxFundingRateScraper.
+func NewDydxFundingRateScraper() *DydxFundingRateScraper {
+	return &DydxFundingRateScraper{}
+}
+
+type dydxPerpetualMarkets struct {
+	Markets map[string]struct {
+		Ticker          string `json:"ticker"`
+		Status          string `json:"status"`
+		NextFundingRate string `json:"nextFundingRate"`
+	} `json:"markets"`
+}
+
+// FetchFundingRates implements FundingRateScraper. Funding on dYdX v4 is paid every hour.
+func (s *DydxFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	var markets dydxPerpetualMarkets
+	err := utils.GetJSONWithBackoff(dydxPerpetualMarketsURL, &markets)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var rates []dia.FundingRate
+	for ticker, market := range markets.Markets {
+		if market.Status != "ACTIVE" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(market.NextFundingRate, 64)
+		if err != nil {
+			log.Errorf("funding rate of %s: %v", ticker, err)
+			continue
+		}
+		rates = append(rates, dia.FundingRate{
+			Symbol:          strings.Split(ticker, "-")[0],
+			Instrument:      ticker,
+			Exchange:        dia.DydxExchange,
+			Rate:            rate,
+			Interval:        time.Hour,
+			NextFundingTime: now.Truncate(time.Hour).Add(time.Hour),
+			Time:            now,
+		})
+	}
+	return rates, nil
+}
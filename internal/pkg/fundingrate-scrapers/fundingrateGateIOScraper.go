@@ -0,0 +1,67 @@
+package fundingratescrapers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const gateIOContractsURL = "https://api.gateio.ws/api/v4/futures/usdt/contracts"
+
+// GateIOFundingRateScraper fetches funding rates from the Gate.io USDT futures API.
+type GateIOFundingRateScraper struct{}
+
+// NewGateIOFundingRateScraper returns a GateIOFundingRateScraper.
+func NewGateIOFundingRateScraper() *GateIOFundingRateScraper {
+	return &GateIOFundingRateScraper{}
+}
+
+type gateIOContract struct {
+	Name                  string  `json:"name"`
+	FundingRate           string  `json:"funding_rate"`
+	FundingRateIndicative string  `json:"funding_rate_indicative"`
+	FundingInterval       int64   `json:"funding_interval"`
+	FundingNextApply      float64 `json:"funding_next_apply"`
+	InDelisting           bool    `json:"in_delisting"`
+}
+
+// FetchFundingRates implements FundingRateScraper. The indicative rate of Gate.io is the
+// predicted rate of the next interval.
+func (s *GateIOFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	var contracts []gateIOContract
+	err := utils.GetJSONWithBackoff(gateIOContractsURL, &contracts)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var rates []dia.FundingRate
+	for _, contract := range contracts {
+		if contract.InDelisting {
+			continue
+		}
+		rate, err := strconv.ParseFloat(contract.FundingRate, 64)
+		if err != nil {
+			log.Errorf("funding rate of %s: %v", contract.Name, err)
+			continue
+		}
+		predictedRate, err := strconv.ParseFloat(contract.FundingRateIndicative, 64)
+		if err != nil {
+			log.Errorf("indicative funding rate of %s: %v", contract.Name, err)
+			continue
+		}
+		rates = append(rates, dia.FundingRate{
+			Symbol:          strings.Split(contract.Name, "_")[0],
+			Instrument:      contract.Name,
+			Exchange:        dia.GateIOExchange,
+			Rate:            rate,
+			PredictedRate:   predictedRate,
+			Interval:        time.Duration(contract.FundingInterval) * time.Second,
+			NextFundingTime: time.Unix(int64(contract.FundingNextApply), 0),
+			Time:            now,
+		})
+	}
+	return rates, nil
+}
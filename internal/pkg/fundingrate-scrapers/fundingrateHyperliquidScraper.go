@@ -0,0 +1,100 @@
+package fundingratescrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const hyperliquidInfoURL = "https://api.hyperliquid.xyz/info"
+
+// HyperliquidFundingRateScraper fetches funding rates from the info API of Hyperliquid.
+type HyperliquidFundingRateScraper struct{}
+
+// NewHyperliquidFundingRateScraper returns a HyperliquidFundingRateScraper.
+func NewHyperliquidFundingRateScraper() *HyperliquidFundingRateScraper {
+	return &HyperliquidFundingRateScraper{}
+}
+
+type hyperliquidMeta struct {
+	Universe []struct {
+		Name       string `json:"name"`
+		IsDelisted bool   `json:"isDelisted"`
+	} `json:"universe"`
+}
+
+type hyperliquidAssetContext struct {
+	Funding string `json:"funding"`
+}
+
+// FetchFundingRates implements FundingRateScraper. Funding on Hyperliquid is paid every hour.
+func (s *HyperliquidFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	// The response is the meta data of all perpetuals and their contexts in the same order.
+	var response []json.RawMessage
+	err := postHyperliquidInfo(map[string]string{"type": "metaAndAssetCtxs"}, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) != 2 {
+		return nil, errors.New("hyperliquid: unexpected response to metaAndAssetCtxs")
+	}
+	var meta hyperliquidMeta
+	err = json.Unmarshal(response[0], &meta)
+	if err != nil {
+		return nil, err
+	}
+	var contexts []hyperliquidAssetContext
+	err = json.Unmarshal(response[1], &contexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(contexts) != len(meta.Universe) {
+		return nil, errors.New("hyperliquid: perpetuals and contexts differ")
+	}
+
+	now := time.Now()
+	var rates []dia.FundingRate
+	for i, perpetual := range meta.Universe {
+		if perpetual.IsDelisted {
+			continue
+		}
+		rate, err := strconv.ParseFloat(contexts[i].Funding, 64)
+		if err != nil {
+			log.Errorf("funding rate of %s: %v", perpetual.Name, err)
+			continue
+		}
+		rates = append(rates, dia.FundingRate{
+			Symbol:          perpetual.Name,
+			Instrument:      perpetual.Name,
+			Exchange:        dia.HyperliquidExchange,
+			Rate:            rate,
+			Interval:        time.Hour,
+			NextFundingTime: now.Truncate(time.Hour).Add(time.Hour),
+			Time:            now,
+		})
+	}
+	return rates, nil
+}
+
+// postHyperliquidInfo decodes the response of the info API to @request into @v.
+func postHyperliquidInfo(request interface{}, v interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	response, err := http.Post(hyperliquidInfoURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", hyperliquidInfoURL, response.StatusCode)
+	}
+	return json.NewDecoder(response.Body).Decode(v)
+}
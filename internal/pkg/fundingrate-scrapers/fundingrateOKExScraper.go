@@ -0,0 +1,125 @@
+package fundingratescrapers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	okexSwapInstrumentsURL = "https://www.okx.com/api/v5/public/instruments?instType=SWAP"
+	okexFundingRateURL     = "https://www.okx.com/api/v5/public/funding-rate?instId="
+	// okexRequestDelay keeps the requests of funding rates within the limit of 20 per 2 seconds.
+	okexRequestDelay = 100 * time.Millisecond
+)
+
+// OKExFundingRateScraper fetches funding rates from the OKX API.
+type OKExFundingRateScraper struct{}
+
+// NewOKExFundingRateScraper returns an OKExFundingRateScraper.
+func NewOKExFundingRateScraper() *OKExFundingRateScraper {
+	return &OKExFundingRateScraper{}
+}
+
+type okexSwapInstruments struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		InstID string `json:"instId"`
+		Uly    string `json:"uly"`
+		State  string `json:"state"`
+	} `json:"data"`
+}
+
+type okexFundingRates struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		InstID          string `json:"instId"`
+		FundingRate     string `json:"fundingRate"`
+		NextFundingRate string `json:"nextFundingRate"`
+		FundingTime     string `json:"fundingTime"`
+		NextFundingTime string `json:"nextFundingTime"`
+		Ts              string `json:"ts"`
+	} `json:"data"`
+}
+
+// FetchFundingRates implements FundingRateScraper. OKX returns the funding rate of one swap per
+// request, so this takes about a tenth of a second per swap.
+func (s *OKExFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	var instruments okexSwapInstruments
+	err := utils.GetJSONWithBackoff(okexSwapInstrumentsURL, &instruments)
+	if err != nil {
+		return nil, err
+	}
+	if instruments.Code != "0" {
+		return nil, fmt.Errorf("okex instruments: %s", instruments.Msg)
+	}
+	var rates []dia.FundingRate
+	for _, instrument := range instruments.Data {
+		if instrument.State != "live" {
+			continue
+		}
+		time.Sleep(okexRequestDelay)
+		rate, err := s.fetchFundingRate(instrument.InstID)
+		if err != nil {
+			log.Errorf("funding rate of %s: %v", instrument.InstID, err)
+			continue
+		}
+		rate.Symbol = strings.Split(instrument.Uly, "-")[0]
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (s *OKExFundingRateScraper) fetchFundingRate(instID string) (rate dia.FundingRate, err error) {
+	var response okexFundingRates
+	err = utils.GetJSONWithBackoff(okexFundingRateURL+url.QueryEscape(instID), &response)
+	if err != nil {
+		return
+	}
+	if response.Code != "0" || len(response.Data) == 0 {
+		err = fmt.Errorf("okex funding rate: %s", response.Msg)
+		return
+	}
+	data := response.Data[0]
+	rate.Instrument = data.InstID
+	rate.Exchange = dia.OKExExchange
+	rate.Rate, err = strconv.ParseFloat(data.FundingRate, 64)
+	if err != nil {
+		return
+	}
+	// The rate of the next interval is only predicted for some swaps.
+	if data.NextFundingRate != "" {
+		rate.PredictedRate, err = strconv.ParseFloat(data.NextFundingRate, 64)
+		if err != nil {
+			return
+		}
+	}
+	fundingTime, err := okexMilliseconds(data.FundingTime)
+	if err != nil {
+		return
+	}
+	nextFundingTime, err := okexMilliseconds(data.NextFundingTime)
+	if err != nil {
+		return
+	}
+	// The current interval ends at fundingTime, the next one at nextFundingTime.
+	rate.NextFundingTime = fundingTime
+	rate.Interval = nextFundingTime.Sub(fundingTime)
+	rate.Time, err = okexMilliseconds(data.Ts)
+	return
+}
+
+func okexMilliseconds(s string) (time.Time, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
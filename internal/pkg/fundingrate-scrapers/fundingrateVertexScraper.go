@@ -0,0 +1,54 @@
+package fundingratescrapers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const vertexContractsURL = "https://archive.prod.vertexprotocol.com/v2/contracts"
+
+// VertexFundingRateScraper fetches funding rates from the Vertex API.
+type VertexFundingRateScraper struct{}
+
+// NewVertexFundingRateScraper returns a VertexFundingRateScraper.
+func NewVertexFundingRateScraper() *VertexFundingRateScraper {
+	return &VertexFundingRateScraper{}
+}
+
+type vertexContract struct {
+	TickerID                 string  `json:"ticker_id"`
+	BaseCurrency             string  `json:"base_currency"`
+	ProductType              string  `json:"product_type"`
+	FundingRate              float64 `json:"funding_rate"`
+	NextFundingRateTimestamp int64   `json:"next_funding_rate_timestamp"`
+}
+
+// FetchFundingRates implements FundingRateScraper. Vertex quotes the funding rate for 24 hours,
+// but pays funding every hour, so the rate is divided by 24.
+func (s *VertexFundingRateScraper) FetchFundingRates() ([]dia.FundingRate, error) {
+	var contracts map[string]vertexContract
+	err := utils.GetJSONWithBackoff(vertexContractsURL, &contracts)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var rates []dia.FundingRate
+	for ticker, contract := range contracts {
+		if contract.ProductType != "perpetual" {
+			continue
+		}
+		rates = append(rates, dia.FundingRate{
+			Symbol:          strings.TrimSuffix(contract.BaseCurrency, "-PERP"),
+			Instrument:      ticker,
+			Exchange:        dia.VertexExchange,
+			Rate:            contract.FundingRate / 24,
+			Interval:        time.Hour,
+			NextFundingTime: time.Unix(contract.NextFundingRateTimestamp, 0),
+			Time:            now,
+		})
+	}
+	return rates, nil
+}
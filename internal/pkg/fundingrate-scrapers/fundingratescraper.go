@@ -0,0 +1,64 @@
+package fundingratescrapers
+
+import (
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// FundingRateScraper fetches the funding rates of perpetual futures from the API of a
+// derivatives exchange.
+type FundingRateScraper interface {
+	// FetchFundingRates returns the current funding rates of all perpetual instruments.
+	FetchFundingRates() ([]dia.FundingRate, error)
+}
+
+// New returns the funding rate scraper of @exchange, or nil if there is none.
+func New(exchange string) FundingRateScraper {
+	switch exchange {
+	case dia.BinanceExchange:
+		return NewBinanceFundingRateScraper()
+	case dia.BybitExchange:
+		return NewBybitFundingRateScraper()
+	case dia.DydxExchange:
+		return NewDydxFundingRateScraper()
+	case dia.VertexExchange:
+		return NewVertexFundingRateScraper()
+	case dia.OKExExchange:
+		return NewOKExFundingRateScraper()
+	case dia.GateIOExchange:
+		return NewGateIOFundingRateScraper()
+	case dia.BitMEXExchange:
+		return NewBitMEXFundingRateScraper()
+	case dia.HyperliquidExchange:
+		return NewHyperliquidFundingRateScraper()
+	default:
+		return nil
+	}
+}
+
+// quoteCurrencies are the margin currencies of the linear perpetuals whose funding rates are
+// collected.
+var quoteCurrencies = []string{"USDT", "USDC", "BUSD"}
+
+// perpetualAsset returns the symbol of the underlying asset of a linear perpetual @instrument,
+// e.g. BTC for BTCUSDT or SHIB for 1000SHIBUSDT. It returns false for other instruments.
+func perpetualAsset(instrument string) (string, bool) {
+	for _, quote := range quoteCurrencies {
+		if strings.HasSuffix(instrument, quote) && len(instrument) > len(quote) {
+			asset := instrument[:len(instrument)-len(quote)]
+			// Instruments of assets with tiny prices are quoted in multiples, e.g. of 1000.
+			for _, multiple := range []string{"1000000", "10000", "1000"} {
+				if strings.HasPrefix(asset, multiple) && len(asset) > len(multiple) {
+					asset = asset[len(multiple):]
+					break
+				}
+			}
+			return asset, true
+		}
+	}
+	return "", false
+}
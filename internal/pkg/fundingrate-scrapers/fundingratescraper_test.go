@@ -0,0 +1,34 @@
+package fundingratescrapers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPerpetualAsset(t *testing.T) {
+	for instrument, expected := range map[string]string{"BTCUSDT": "BTC", "ETHBUSD": "ETH", "1000SHIBUSDT": "SHIB", "1000000MOGUSDT": "MOG", "1INCHUSDT": "1INCH"} {
+		if asset, ok := perpetualAsset(instrument); !ok || asset != expected {
+			t.Errorf("expected %s for %s, got %s", expected, instrument, asset)
+		}
+	}
+	for _, instrument := range []string{"BTCUSD", "USDT", "BTCUSDT_230331"} {
+		if _, ok := perpetualAsset(instrument); ok {
+			t.Errorf("expected no asset for %s", instrument)
+		}
+	}
+}
+
+func TestBitMEXFundingInterval(t *testing.T) {
+	var instrument bitmexInstrument
+	err := json.Unmarshal([]byte(`{"symbol":"XBTUSD","typ":"FFWCSX","underlying":"XBT","fundingRate":0.0001,"indicativeFundingRate":null,"fundingInterval":"2000-01-01T08:00:00.000Z","fundingTimestamp":"2023-11-15T04:00:00.000Z"}`), &instrument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interval := instrument.FundingInterval.Sub(bitmexEpoch); interval != 8*time.Hour {
+		t.Errorf("expected interval of 8h, got %v", interval)
+	}
+	if instrument.IndicativeFundingRate != nil {
+		t.Errorf("expected no indicative funding rate, got %v", *instrument.IndicativeFundingRate)
+	}
+}
@@ -0,0 +1,85 @@
+package gasPriceService
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// rewardPercentiles are the percentiles of the priority fees of a block which are sampled.
+var rewardPercentiles = []float64{10, 50, 90}
+
+// feeHistory is the result of eth_feeHistory.
+type feeHistory struct {
+	OldestBlock hexutil.Uint64 `json:"oldestBlock"`
+	// BaseFeePerGas has one more entry than blocks, the base fee of the next block.
+	BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64        `json:"gasUsedRatio"`
+	Reward        [][]*hexutil.Big `json:"reward"`
+}
+
+// SampleGasPrice returns the current gas prices of @blockchain. Priority fee percentiles are
+// averaged over the last @blocks blocks. Base and priority fees are left at zero if the node
+// doesn't support eth_feeHistory, e.g. on blockchains without EIP-1559 fees.
+func SampleGasPrice(ctx context.Context, client *rpc.Client, blockchain string, blocks int) (models.GasPrice, error) {
+	gasPrice := models.GasPrice{Blockchain: blockchain, Time: time.Now()}
+
+	var suggested hexutil.Big
+	err := client.CallContext(ctx, &suggested, "eth_gasPrice")
+	if err != nil {
+		return gasPrice, err
+	}
+	gasPrice.GasPrice = gwei((*big.Int)(&suggested))
+
+	var history feeHistory
+	err = client.CallContext(ctx, &history, "eth_feeHistory", hexutil.Uint(blocks), "latest", rewardPercentiles)
+	if err != nil {
+		log.Warnf("eth_feeHistory on %s: %v", blockchain, err)
+		var block hexutil.Uint64
+		err = client.CallContext(ctx, &block, "eth_blockNumber")
+		gasPrice.Block = uint64(block)
+		return gasPrice, err
+	}
+	n := len(history.BaseFeePerGas) - 1
+	if n < 1 {
+		return gasPrice, nil
+	}
+	gasPrice.Block = uint64(history.OldestBlock) + uint64(n) - 1
+	gasPrice.BaseFee = gwei((*big.Int)(history.BaseFeePerGas[n-1]))
+	gasPrice.NextBaseFee = gwei((*big.Int)(history.BaseFeePerGas[n]))
+
+	var sums [3]float64
+	var count int
+	for i, rewards := range history.Reward {
+		// The rewards of empty blocks are zero, or missing on some nodes.
+		if i >= len(history.GasUsedRatio) || history.GasUsedRatio[i] == 0 || len(rewards) != len(rewardPercentiles) {
+			continue
+		}
+		for j, reward := range rewards {
+			sums[j] += gwei((*big.Int)(reward))
+		}
+		count++
+	}
+	if count > 0 {
+		gasPrice.PriorityFee10 = sums[0] / float64(count)
+		gasPrice.PriorityFee50 = sums[1] / float64(count)
+		gasPrice.PriorityFee90 = sums[2] / float64(count)
+	}
+	return gasPrice, nil
+}
+
+// gwei converts @wei to gwei.
+func gwei(wei *big.Int) float64 {
+	if wei == nil {
+		return 0
+	}
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9)).Float64()
+	return f
+}
@@ -0,0 +1,304 @@
+package liquidityscrapers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	uniswapcontract "github.com/diadata-org/diadata/internal/pkg/exchange-scrapers/uniswap"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// uniswapV2Timeout bounds the calls of one snapshot, including the discovery of new pools.
+const uniswapV2Timeout = 10 * time.Minute
+
+var (
+	uniswapV2FactoryABI, _ = abi.JSON(strings.NewReader(uniswapcontract.IUniswapV2FactoryABI))
+	uniswapV2PairABI, _    = abi.JSON(strings.NewReader(uniswapcontract.IUniswapV2PairABI))
+	erc20ABI, _            = abi.JSON(strings.NewReader(uniswapcontract.IERC20ABI))
+)
+
+type uniswapV2Pool struct {
+	address common.Address
+	token0  common.Address
+	token1  common.Address
+}
+
+// UniswapV2LiquidityScraper snapshots the reserves of the pairs of a Uniswap v2 deployment through
+// multicalls. Pairs are discovered from the factory once and new pairs are added on each snapshot.
+type UniswapV2LiquidityScraper struct {
+	exchange   string
+	blockchain string
+	factory    common.Address
+	client     *ethclient.Client
+	price      PriceFunc
+	minTVL     float64
+	pools      []uniswapV2Pool
+	// numPairs is the number of pairs of the factory which were discovered, including the pairs
+	// with tokens which can't be read.
+	numPairs int
+	tokens   map[common.Address]dia.Asset
+}
+
+// NewUniswapV2LiquidityScraper returns a UniswapV2LiquidityScraper of the deployment of @exchange
+// on @blockchain with the factory @factory.
+func NewUniswapV2LiquidityScraper(exchange string, blockchain string, factory common.Address, price PriceFunc, minTVL float64) (*UniswapV2LiquidityScraper, error) {
+	node := ethhelper.RPCNode(blockchain)
+	if node == "" {
+		return nil, fmt.Errorf("no RPC node of %s, set %s_RPC_NODE", blockchain, strings.ToUpper(blockchain))
+	}
+	client, err := ethclient.Dial(node)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV2LiquidityScraper{
+		exchange:   exchange,
+		blockchain: blockchain,
+		factory:    factory,
+		client:     client,
+		price:      price,
+		minTVL:     minTVL,
+		tokens:     make(map[common.Address]dia.Asset),
+	}, nil
+}
+
+// FetchPoolLiquidity implements LiquidityScraper.
+func (s *UniswapV2LiquidityScraper) FetchPoolLiquidity() ([]dia.PoolLiquidity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uniswapV2Timeout)
+	defer cancel()
+
+	err := s.discoverPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	getReserves, err := uniswapV2PairABI.Pack("getReserves")
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]ethhelper.Call, len(s.pools))
+	for i, pool := range s.pools {
+		calls[i] = ethhelper.Call{Target: pool.address, CallData: getReserves}
+	}
+	results, err := ethhelper.Multicall(ctx, s.client, calls, multicallBatchSize, new(big.Int).SetUint64(block))
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	prices := make(map[string]float64)
+	var liquidities []dia.PoolLiquidity
+	for i, pool := range s.pools {
+		if !results[i].Success {
+			continue
+		}
+		reserves, err := uniswapV2PairABI.Unpack("getReserves", results[i].ReturnData)
+		if err != nil || len(reserves) < 2 {
+			log.Errorf("reserves of pool %s: %v", pool.address.Hex(), err)
+			continue
+		}
+		token0, token1 := s.tokens[pool.token0], s.tokens[pool.token1]
+		reserve0 := amount(reserves[0].(*big.Int), token0.Decimals)
+		reserve1 := amount(reserves[1].(*big.Int), token1.Decimals)
+		tvl := poolTVL(reserve0, s.cachedPrice(prices, token0.Symbol), reserve1, s.cachedPrice(prices, token1.Symbol))
+		if tvl < s.minTVL || tvl == 0 {
+			continue
+		}
+		liquidities = append(liquidities, dia.PoolLiquidity{
+			Exchange:   s.exchange,
+			Blockchain: s.blockchain,
+			Pool:       pool.address.Hex(),
+			Pair:       token0.Symbol + "-" + token1.Symbol,
+			Token0:     token0,
+			Token1:     token1,
+			Reserve0:   reserve0,
+			Reserve1:   reserve1,
+			TVLUSD:     tvl,
+			Block:      block,
+			Time:       t,
+		})
+	}
+	return liquidities, nil
+}
+
+// cachedPrice returns the price of @symbol, which is looked up once per snapshot in @prices. It
+// is zero if there is no price of @symbol.
+func (s *UniswapV2LiquidityScraper) cachedPrice(prices map[string]float64, symbol string) float64 {
+	price, ok := prices[symbol]
+	if !ok {
+		price, _ = s.price(symbol)
+		prices[symbol] = price
+	}
+	return price
+}
+
+// discoverPools adds the pairs created since the last call to s.pools. Pairs with a blacklisted
+// token or a token without symbol or decimals are skipped.
+func (s *UniswapV2LiquidityScraper) discoverPools(ctx context.Context) error {
+	input, err := uniswapV2FactoryABI.Pack("allPairsLength")
+	if err != nil {
+		return err
+	}
+	results, err := ethhelper.Multicall(ctx, s.client, []ethhelper.Call{{Target: s.factory, CallData: input}}, 1, nil)
+	if err != nil {
+		return err
+	}
+	length, err := uniswapV2FactoryABI.Unpack("allPairsLength", results[0].ReturnData)
+	if err != nil || len(length) == 0 {
+		return fmt.Errorf("number of pairs of %s: %v", s.exchange, err)
+	}
+	numPairs := int(length[0].(*big.Int).Int64())
+	if numPairs <= s.numPairs {
+		return nil
+	}
+	log.Infof("discovering pairs %d to %d of %s", s.numPairs, numPairs, s.exchange)
+
+	var calls []ethhelper.Call
+	for i := s.numPairs; i < numPairs; i++ {
+		input, err := uniswapV2FactoryABI.Pack("allPairs", big.NewInt(int64(i)))
+		if err != nil {
+			return err
+		}
+		calls = append(calls, ethhelper.Call{Target: s.factory, CallData: input})
+	}
+	results, err = ethhelper.Multicall(ctx, s.client, calls, multicallBatchSize, nil)
+	if err != nil {
+		return err
+	}
+	var addresses []common.Address
+	for _, result := range results {
+		address, err := unpackAddress(uniswapV2FactoryABI, "allPairs", result)
+		if err != nil {
+			return err
+		}
+		addresses = append(addresses, address)
+	}
+
+	token0, err := uniswapV2PairABI.Pack("token0")
+	if err != nil {
+		return err
+	}
+	token1, err := uniswapV2PairABI.Pack("token1")
+	if err != nil {
+		return err
+	}
+	calls = calls[:0]
+	for _, address := range addresses {
+		calls = append(calls, ethhelper.Call{Target: address, CallData: token0}, ethhelper.Call{Target: address, CallData: token1})
+	}
+	results, err = ethhelper.Multicall(ctx, s.client, calls, multicallBatchSize, nil)
+	if err != nil {
+		return err
+	}
+	var pools []uniswapV2Pool
+	for i, address := range addresses {
+		pool := uniswapV2Pool{address: address}
+		pool.token0, err = unpackAddress(uniswapV2PairABI, "token0", results[2*i])
+		if err != nil {
+			continue
+		}
+		pool.token1, err = unpackAddress(uniswapV2PairABI, "token1", results[2*i+1])
+		if err != nil {
+			continue
+		}
+		pools = append(pools, pool)
+	}
+
+	err = s.fetchTokens(ctx, pools)
+	if err != nil {
+		return err
+	}
+	for _, pool := range pools {
+		token0, ok0 := s.tokens[pool.token0]
+		token1, ok1 := s.tokens[pool.token1]
+		if !ok0 || !ok1 || helpers.SymbolIsBlackListed(token0.Symbol) || helpers.SymbolIsBlackListed(token1.Symbol) {
+			continue
+		}
+		s.pools = append(s.pools, pool)
+	}
+	s.numPairs = numPairs
+	return nil
+}
+
+// fetchTokens adds the symbol and decimals of the unknown tokens of @pools to s.tokens. Tokens
+// whose symbol or decimals can't be read are left out. WETH is identified with ETH as in the
+// trades of the Uniswap scrapers.
+func (s *UniswapV2LiquidityScraper) fetchTokens(ctx context.Context, pools []uniswapV2Pool) error {
+	symbol, err := erc20ABI.Pack("symbol")
+	if err != nil {
+		return err
+	}
+	decimals, err := erc20ABI.Pack("decimals")
+	if err != nil {
+		return err
+	}
+	var addresses []common.Address
+	var calls []ethhelper.Call
+	seen := make(map[common.Address]bool)
+	for _, pool := range pools {
+		for _, address := range []common.Address{pool.token0, pool.token1} {
+			if _, ok := s.tokens[address]; ok || seen[address] {
+				continue
+			}
+			seen[address] = true
+			addresses = append(addresses, address)
+			calls = append(calls, ethhelper.Call{Target: address, CallData: symbol}, ethhelper.Call{Target: address, CallData: decimals})
+		}
+	}
+	results, err := ethhelper.Multicall(ctx, s.client, calls, multicallBatchSize, nil)
+	if err != nil {
+		return err
+	}
+	for i, address := range addresses {
+		if !results[2*i].Success || !results[2*i+1].Success {
+			continue
+		}
+		symbolValues, err := erc20ABI.Unpack("symbol", results[2*i].ReturnData)
+		if err != nil || len(symbolValues) == 0 {
+			continue
+		}
+		decimalsValues, err := erc20ABI.Unpack("decimals", results[2*i+1].ReturnData)
+		if err != nil || len(decimalsValues) == 0 {
+			continue
+		}
+		asset := dia.Asset{
+			Symbol:     symbolValues[0].(string),
+			Address:    address.Hex(),
+			Decimals:   decimalsValues[0].(uint8),
+			Blockchain: s.blockchain,
+		}
+		if asset.Symbol == "WETH" {
+			asset.Symbol = "ETH"
+		}
+		if len(asset.Symbol) < 2 {
+			continue
+		}
+		s.tokens[address] = asset
+	}
+	return nil
+}
+
+// unpackAddress returns the address returned by the call of @method in @result.
+func unpackAddress(contractABI abi.ABI, method string, result ethhelper.CallResult) (common.Address, error) {
+	if !result.Success {
+		return common.Address{}, fmt.Errorf("call of %s reverted", method)
+	}
+	values, err := contractABI.Unpack(method, result.ReturnData)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(values) == 0 {
+		return common.Address{}, fmt.Errorf("no result of %s", method)
+	}
+	return values[0].(common.Address), nil
+}
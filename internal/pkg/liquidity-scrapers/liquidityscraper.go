@@ -0,0 +1,72 @@
+package liquidityscrapers
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// multicallBatchSize is the number of calls in one multicall. It keeps the gas of a batch well
+// below the eth_call gas cap of public nodes.
+const multicallBatchSize = 500
+
+// LiquidityScraper snapshots the reserves of the liquidity pools of a DEX.
+type LiquidityScraper interface {
+	// FetchPoolLiquidity returns the current reserves and TVL of all pools of the DEX with a
+	// TVL of at least the minimum TVL of the scraper.
+	FetchPoolLiquidity() ([]dia.PoolLiquidity, error)
+}
+
+// PriceFunc returns the price of the asset @symbol in USD.
+type PriceFunc func(symbol string) (float64, error)
+
+// uniswapV2Deployment is a deployment of Uniswap v2 or a fork of it with the same factory and
+// pair interface.
+type uniswapV2Deployment struct {
+	blockchain string
+	factory    common.Address
+}
+
+var uniswapV2Deployments = map[string]uniswapV2Deployment{
+	dia.UniswapExchange:   {dia.ETHEREUM, common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f")},
+	dia.SushiSwapExchange: {dia.ETHEREUM, common.HexToAddress("0xC0AEe478e3658e2610c5F7A4A2E1777cE9e4f2Ac")},
+	dia.PanCakeSwap:       {dia.BINANCESMARTCHAIN, common.HexToAddress("0xcA143Ce32Fe78f1f7019d7d551a6402fC5350c73")},
+	dia.DfynNetwork:       {dia.POLYGON, common.HexToAddress("0xE7Fb3e833eFE5F9c441105EB65Ef8b261266423B")},
+}
+
+// New returns the liquidity scraper of @exchange, which values reserves with @price and skips
+// pools with a TVL below @minTVL USD.
+func New(exchange string, price PriceFunc, minTVL float64) (LiquidityScraper, error) {
+	if deployment, ok := uniswapV2Deployments[exchange]; ok {
+		return NewUniswapV2LiquidityScraper(exchange, deployment.blockchain, deployment.factory, price, minTVL)
+	}
+	return nil, fmt.Errorf("no liquidity scraper for exchange %s", exchange)
+}
+
+// poolTVL returns the value of the reserves @reserve0 and @reserve1 of a pool in USD, given the
+// prices @price0 and @price1 of its tokens. A price of zero is unknown. The reserves of a constant
+// product pool have the same value, so a single known price values the whole pool.
+func poolTVL(reserve0, price0, reserve1, price1 float64) float64 {
+	switch {
+	case price0 > 0 && price1 > 0:
+		return reserve0*price0 + reserve1*price1
+	case price0 > 0:
+		return 2 * reserve0 * price0
+	case price1 > 0:
+		return 2 * reserve1 * price1
+	default:
+		return 0
+	}
+}
+
+// amount returns the raw token amount @raw in units of a token with @decimals.
+func amount(raw *big.Int, decimals uint8) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(raw), big.NewFloat(math.Pow10(int(decimals)))).Float64()
+	return f
+}
@@ -0,0 +1,35 @@
+package liquidityscrapers
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestPoolTVL(t *testing.T) {
+	cases := []struct {
+		reserve0, price0, reserve1, price1 float64
+		expected                           float64
+	}{
+		{10, 2000, 20000, 1, 40000},
+		// A single known price values both reserves.
+		{10, 2000, 5e6, 0, 40000},
+		{1e9, 0, 20000, 1, 40000},
+		{10, 0, 20000, 0, 0},
+	}
+	for i, c := range cases {
+		if tvl := poolTVL(c.reserve0, c.price0, c.reserve1, c.price1); math.Abs(tvl-c.expected) > 1e-9 {
+			t.Errorf("case %d: expected %v, got %v", i, c.expected, tvl)
+		}
+	}
+}
+
+func TestAmount(t *testing.T) {
+	raw, _ := new(big.Int).SetString("1234500000000000000000", 10)
+	if a := amount(raw, 18); math.Abs(a-1234.5) > 1e-9 {
+		t.Errorf("expected 1234.5, got %v", a)
+	}
+	if a := amount(big.NewInt(2500000), 6); math.Abs(a-2.5) > 1e-12 {
+		t.Errorf("expected 2.5, got %v", a)
+	}
+}
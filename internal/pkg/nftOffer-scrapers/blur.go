@@ -0,0 +1,219 @@
+package nftofferscrapers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	BlurRefreshDelay = time.Minute * 10
+	blurAPIURL       = "https://core-api.prod.blur.io/v1"
+	// blurListingsFilter restricts the tokens of a collection to the listed ones.
+	blurListingsFilter = `{"traits":[],"hasAsks":true}`
+)
+
+// blurCollections are the collections whose listings are scraped by default, BAYC, MAYC, Azuki
+// and Pudgy Penguins. They can be overwritten by a comma separated list in BLUR_COLLECTIONS, and
+// the API by BLUR_API_URL.
+var blurCollections = []string{
+	"0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D",
+	"0x60E4d786628Fea6478F785A6d7e704777c86a7c6",
+	"0xED5AF388653567Af2F388E6224dC7C4b3241C544",
+	"0xBd3531dA5CF5857e7CfAA92426877b022e612cf8",
+}
+
+type blurTokensResponse struct {
+	Success bool `json:"success"`
+	Tokens  []struct {
+		TokenID string `json:"tokenId"`
+		Owner   struct {
+			Address string `json:"address"`
+		} `json:"owner"`
+		Price *struct {
+			Amount      string    `json:"amount"`
+			Unit        string    `json:"unit"`
+			ListedAt    time.Time `json:"listedAt"`
+			Marketplace string    `json:"marketplace"`
+		} `json:"price"`
+	} `json:"tokens"`
+}
+
+// BlurScraper polls the listings of collections on Blur from its public API. Listings are
+// off-chain orders, so they have no block or transaction.
+type BlurScraper struct {
+	offerScraper OfferScraper
+	apiURL       string
+	collections  []string
+	ticker       *time.Ticker
+}
+
+func NewBlurScraper(rdb *models.RelDB) *BlurScraper {
+	offerScraper := OfferScraper{
+		shutdown:     make(chan nothing),
+		shutdownDone: make(chan nothing),
+		errorLock:    &sync.RWMutex{},
+		datastore:    rdb,
+		chanOffer:    make(chan dia.NFTOffer),
+	}
+	s := &BlurScraper{
+		offerScraper: offerScraper,
+		apiURL:       blurAPIURL,
+		collections:  blurCollections,
+		ticker:       time.NewTicker(BlurRefreshDelay),
+	}
+	if apiURL := os.Getenv("BLUR_API_URL"); apiURL != "" {
+		s.apiURL = apiURL
+	}
+	if collections := os.Getenv("BLUR_COLLECTIONS"); collections != "" {
+		s.collections = strings.Split(collections, ",")
+	}
+	go s.mainLoop()
+	return s
+}
+
+// mainLoop runs in a goroutine until channel s is closed.
+func (scraper *BlurScraper) mainLoop() {
+	err := scraper.FetchOffers()
+	if err != nil {
+		log.Error("fetching blur listings: ", err)
+	}
+	for {
+		select {
+		case <-scraper.ticker.C:
+			err := scraper.FetchOffers()
+			if err != nil {
+				log.Error("fetching blur listings: ", err)
+			}
+		case <-scraper.offerScraper.shutdown: // user requested shutdown
+			log.Printf("Blur scraper shutting down")
+			scraper.cleanup(nil)
+			return
+		}
+	}
+}
+
+// FetchOffers sends the current listings of all collections to the offer channel. Collections
+// which fail are logged and skipped.
+func (scraper *BlurScraper) FetchOffers() error {
+	var failed []string
+	for _, collection := range scraper.collections {
+		err := scraper.fetchListings(collection)
+		if err != nil {
+			log.Errorf("blur listings of %s: %v", collection, err)
+			failed = append(failed, collection)
+		}
+	}
+	if len(failed) == len(scraper.collections) && len(failed) > 0 {
+		return fmt.Errorf("no listings of any collection")
+	}
+	return nil
+}
+
+func (scraper *BlurScraper) fetchListings(collection string) error {
+	nftclass, err := scraper.offerScraper.datastore.GetNFTClass(collection, dia.ETHEREUM)
+	if err != nil {
+		return fmt.Errorf("nft class: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/collections/%s/tokens?filters=%s", scraper.apiURL, strings.ToLower(collection), url.QueryEscape(blurListingsFilter))
+	response, err := utils.GetWithBackoff(endpoint)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", endpoint, response.StatusCode)
+	}
+	var tokens blurTokensResponse
+	err = json.NewDecoder(response.Body).Decode(&tokens)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens.Tokens {
+		// Blur aggregates the listings of other marketplaces, which are scraped there.
+		if token.Price == nil || token.Price.Unit != "ETH" || !strings.EqualFold(token.Price.Marketplace, "BLUR") {
+			continue
+		}
+		amount, err := decimal.NewFromString(token.Price.Amount)
+		if err != nil {
+			log.Errorf("price of token %s of %s: %v", token.TokenID, collection, err)
+			continue
+		}
+		err = scraper.ensureNFT(nftclass, token.TokenID)
+		if err != nil {
+			log.Errorf("nft %s of %s: %v", token.TokenID, collection, err)
+			continue
+		}
+
+		scraper.GetOfferChannel() <- dia.NFTOffer{
+			NFT: dia.NFT{
+				NFTClass: nftclass,
+				TokenID:  token.TokenID,
+			},
+			StartValue:       amount.Shift(18).BigInt(),
+			FromAddress:      token.Owner.Address,
+			AuctionType:      "Listing",
+			CurrencySymbol:   "ETH",
+			CurrencyAddress:  "0x0000000000000000000000000000000000000000",
+			CurrencyDecimals: int32(18),
+			Timestamp:        token.Price.ListedAt,
+			Exchange:         "Blur",
+		}
+	}
+	return nil
+}
+
+// ensureNFT stores the nft @tokenID of @nftclass unless it is known, since offers reference it.
+func (scraper *BlurScraper) ensureNFT(nftclass dia.NFTClass, tokenID string) error {
+	_, err := scraper.offerScraper.datastore.GetNFT(nftclass.Address, nftclass.Blockchain, tokenID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return scraper.offerScraper.datastore.SetNFT(dia.NFT{NFTClass: nftclass, TokenID: tokenID})
+}
+
+// GetDataChannel returns the scrapers data channel.
+func (scraper *BlurScraper) GetOfferChannel() chan dia.NFTOffer {
+	return scraper.offerScraper.chanOffer
+}
+
+// closes all connected Scrapers. Must only be called from mainLoop
+func (scraper *BlurScraper) cleanup(err error) {
+	scraper.offerScraper.errorLock.Lock()
+	defer scraper.offerScraper.errorLock.Unlock()
+	scraper.ticker.Stop()
+	if err != nil {
+		scraper.offerScraper.error = err
+	}
+	scraper.offerScraper.closed = true
+	close(scraper.offerScraper.shutdownDone) // signal that shutdown is complete
+}
+
+// Close closes any existing API connections
+func (scraper *BlurScraper) Close() error {
+	if scraper.offerScraper.closed {
+		return errors.New("scraper already closed")
+	}
+	close(scraper.offerScraper.shutdown)
+	<-scraper.offerScraper.shutdownDone
+	scraper.offerScraper.errorLock.RLock()
+	defer scraper.offerScraper.errorLock.RUnlock()
+	return scraper.offerScraper.error
+}
@@ -0,0 +1,255 @@
+package nfttradescrapers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// Blur is the name of the scraper of the Blur marketplace, under which its config and state
+	// are stored, and the exchange of its trades.
+	Blur = "Blur"
+
+	blurExchangeABI = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"maker","type":"address"},{"indexed":true,"internalType":"address","name":"taker","type":"address"},{"components":[{"internalType":"address","name":"trader","type":"address"},{"internalType":"enum Side","name":"side","type":"uint8"},{"internalType":"address","name":"matchingPolicy","type":"address"},{"internalType":"address","name":"collection","type":"address"},{"internalType":"uint256","name":"tokenId","type":"uint256"},{"internalType":"uint256","name":"amount","type":"uint256"},{"internalType":"address","name":"paymentToken","type":"address"},{"internalType":"uint256","name":"price","type":"uint256"},{"internalType":"uint256","name":"listingTime","type":"uint256"},{"internalType":"uint256","name":"expirationTime","type":"uint256"},{"components":[{"internalType":"uint16","name":"rate","type":"uint16"},{"internalType":"address payable","name":"recipient","type":"address"}],"internalType":"struct Fee[]","name":"fees","type":"tuple[]"},{"internalType":"uint256","name":"salt","type":"uint256"},{"internalType":"bytes","name":"extraParams","type":"bytes"}],"indexed":false,"internalType":"struct Order","name":"sell","type":"tuple"},{"indexed":false,"internalType":"bytes32","name":"sellHash","type":"bytes32"},{"components":[{"internalType":"address","name":"trader","type":"address"},{"internalType":"enum Side","name":"side","type":"uint8"},{"internalType":"address","name":"matchingPolicy","type":"address"},{"internalType":"address","name":"collection","type":"address"},{"internalType":"uint256","name":"tokenId","type":"uint256"},{"internalType":"uint256","name":"amount","type":"uint256"},{"internalType":"address","name":"paymentToken","type":"address"},{"internalType":"uint256","name":"price","type":"uint256"},{"internalType":"uint256","name":"listingTime","type":"uint256"},{"internalType":"uint256","name":"expirationTime","type":"uint256"},{"components":[{"internalType":"uint16","name":"rate","type":"uint16"},{"internalType":"address payable","name":"recipient","type":"address"}],"internalType":"struct Fee[]","name":"fees","type":"tuple[]"},{"internalType":"uint256","name":"salt","type":"uint256"},{"internalType":"bytes","name":"extraParams","type":"bytes"}],"indexed":false,"internalType":"struct Order","name":"buy","type":"tuple"},{"indexed":false,"internalType":"bytes32","name":"buyHash","type":"bytes32"}],"name":"OrdersMatched","type":"event"},` +
+		`{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes32","name":"orderHash","type":"bytes32"},{"indexed":false,"internalType":"uint256","name":"tokenIdListingIndexTrader","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"collectionPriceSide","type":"uint256"}],"name":"Execution721Packed","type":"event"},` +
+		`{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes32","name":"orderHash","type":"bytes32"},{"indexed":false,"internalType":"uint256","name":"tokenIdListingIndexTrader","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"collectionPriceSide","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"takerFeeRecipientRate","type":"uint256"}],"name":"Execution721TakerFeePacked","type":"event"},` +
+		`{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes32","name":"orderHash","type":"bytes32"},{"indexed":false,"internalType":"uint256","name":"tokenIdListingIndexTrader","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"collectionPriceSide","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"makerFeeRecipientRate","type":"uint256"}],"name":"Execution721MakerFeePacked","type":"event"}]`
+
+	// sides of blur v1 orders
+	blurSideBuy  uint8 = 0
+	blurSideSell uint8 = 1
+
+	// order types of the packed blur v2 executions
+	blurOrderTypeAsk uint8 = 0
+	blurOrderTypeBid uint8 = 1
+)
+
+// BlurScraper scrapes the sales of Blur from the OrdersMatched events of BlurExchange and the
+// packed execution events of BlurExchangeV2. Sales of v2 include accepted collection bids.
+type BlurScraper struct {
+	*marketplaceScraper
+}
+
+type blurFee struct {
+	Rate      uint16
+	Recipient common.Address
+}
+
+type blurOrder struct {
+	Trader         common.Address
+	Side           uint8
+	MatchingPolicy common.Address
+	Collection     common.Address
+	TokenId        *big.Int
+	Amount         *big.Int
+	PaymentToken   common.Address
+	Price          *big.Int
+	ListingTime    *big.Int
+	ExpirationTime *big.Int
+	Fees           []blurFee
+	Salt           *big.Int
+	ExtraParams    []byte
+}
+
+type blurOrdersMatched struct {
+	Sell     blurOrder
+	SellHash [32]byte
+	Buy      blurOrder
+	BuyHash  [32]byte
+}
+
+var (
+	defBlurConf = &MarketplaceScraperConfig{
+		OpenSeaScraperConfig: OpenSeaScraperConfig{
+			BatchSize:       500,
+			WaitPeriod:      5 * time.Second,
+			FollowDist:      blockDelayEthereum,
+			UseArchiveNode:  false,
+			MaxRetry:        10,
+			SkipOnErr:       true,
+			MaxMetadataSize: 50 * 1024,
+			MetadataTimeout: 30 * time.Second,
+		},
+		ContractAddrs: []string{
+			"0x000000000000Ad05Ccc4F10045630fb830B95127", // BlurExchange
+			"0xb2ecfE4E4D61f8790bbb9DE2D1259B9e2410CEA5", // BlurExchangeV2
+		},
+	}
+
+	// BlurExchange has been deployed on the mainnet at block num 15779579,
+	// so scraper starts from this block
+	defBlurState = &OpenSeaScraperState{LastBlockNum: 15779579}
+
+	// bids on blur are paid in blur pool eth, which is redeemable 1:1 for eth
+	blurPoolAddress = common.HexToAddress("0x0000000000A39bb272e79075ade125fd351887Ac")
+
+	blurABI abi.ABI
+
+	blurPackedEvents = map[common.Hash]string{}
+)
+
+func init() {
+	var err error
+
+	blurABI, err = abi.JSON(strings.NewReader(blurExchangeABI))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, name := range []string{"Execution721Packed", "Execution721TakerFeePacked", "Execution721MakerFeePacked"} {
+		blurPackedEvents[blurABI.Events[name].ID] = name
+	}
+}
+
+func NewBlurScraper(rdb *models.RelDB) *BlurScraper {
+	events := []common.Hash{blurABI.Events["OrdersMatched"].ID}
+	for id := range blurPackedEvents {
+		events = append(events, id)
+	}
+
+	m, err := newMarketplaceScraper(rdb, Blur, Blur, defBlurConf, defBlurState, events)
+	if err != nil {
+		return nil
+	}
+
+	s := &BlurScraper{marketplaceScraper: m}
+	m.sales = s.txSales
+
+	go m.mainLoop()
+
+	return s
+}
+
+// it returns the nft sales of the orders matched and executed in the transaction
+func (s *BlurScraper) txSales(ctx context.Context, tx *utils.EthFilteredTx) ([]*marketplaceSale, error) {
+	var sales []*marketplaceSale
+	var receipt *types.Receipt
+
+	for _, txLog := range tx.Logs {
+		if len(txLog.Topics) == 0 {
+			continue
+		}
+
+		if txLog.Topics[0] == blurABI.Events["OrdersMatched"].ID {
+			ev := &blurOrdersMatched{}
+			if err := blurABI.UnpackIntoInterface(ev, "OrdersMatched", txLog.Data); err != nil {
+				log.Errorf("unable to decode blur OrdersMatched event(tx: %s, logIndex: %d) (SKIPPED!): %s", tx.TXHash, txLog.Index, err.Error())
+				continue
+			}
+			if sale := blurOrdersMatchedSale(ev); sale != nil {
+				sales = append(sales, sale)
+			}
+			continue
+		}
+
+		name, ok := blurPackedEvents[txLog.Topics[0]]
+		if !ok {
+			continue
+		}
+		values, err := blurABI.Unpack(name, txLog.Data)
+		if err != nil || len(values) < 3 {
+			log.Errorf("unable to decode blur %s event(tx: %s, logIndex: %d) (SKIPPED!): %v", name, tx.TXHash, txLog.Index, err)
+			continue
+		}
+
+		// the taker of a packed execution is only known from the transfer of the nft
+		if receipt == nil {
+			receipt, err = s.tradeScraper.ethConnection.TransactionReceipt(ctx, tx.TXHash)
+			if err != nil {
+				log.Errorf("unable to read transaction(%s) receipt: %s", tx.TXHash, err.Error())
+				return nil, err
+			}
+		}
+		if sale := blurPackedSale(values[1].(*big.Int), values[2].(*big.Int), receipt.Logs); sale != nil {
+			sales = append(sales, sale)
+		}
+	}
+
+	return sales, nil
+}
+
+// blurOrdersMatchedSale returns the sale of the matched orders, at the price of the maker's order
+// which is the earlier listed one
+func blurOrdersMatchedSale(ev *blurOrdersMatched) *marketplaceSale {
+	if ev.Sell.Side != blurSideSell || ev.Buy.Side != blurSideBuy {
+		return nil
+	}
+
+	price := ev.Buy.Price
+	if ev.Sell.ListingTime.Cmp(ev.Buy.ListingTime) <= 0 {
+		price = ev.Sell.Price
+	}
+
+	return &marketplaceSale{
+		NFTs:     []marketplaceNFT{{Token: ev.Sell.Collection, Identifier: ev.Sell.TokenId}},
+		Seller:   ev.Sell.Trader,
+		Buyer:    ev.Buy.Trader,
+		Currency: ev.Sell.PaymentToken,
+		Native:   ev.Sell.PaymentToken == (common.Address{}) || ev.Sell.PaymentToken == blurPoolAddress,
+		Price:    new(big.Int).Set(price),
+	}
+}
+
+// blurPackedSale returns the sale of a packed execution of blur v2
+//
+// tokenIdListingIndexTrader packs the token id above bit 168, the listing index and the maker in
+// the lower 160 bits. collectionPriceSide packs the order type in the top byte, the price in wei
+// as uint88 and the collection in the lower 160 bits. asks are paid in eth and bids in blur pool
+// eth, the taker is the counterparty of the maker in the transfer of the nft in logs.
+func blurPackedSale(tokenIdListingIndexTrader *big.Int, collectionPriceSide *big.Int, logs []*types.Log) *marketplaceSale {
+	addressMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+	priceMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 88), big.NewInt(1))
+
+	maker := common.BigToAddress(new(big.Int).And(tokenIdListingIndexTrader, addressMask))
+	tokenID := new(big.Int).Rsh(tokenIdListingIndexTrader, 168)
+	collection := common.BigToAddress(new(big.Int).And(collectionPriceSide, addressMask))
+	price := new(big.Int).And(new(big.Int).Rsh(collectionPriceSide, 160), priceMask)
+	orderType := uint8(new(big.Int).Rsh(collectionPriceSide, 248).Uint64())
+
+	from, to, err := findERC721Transfer(logs, collection, tokenID)
+	if err != nil {
+		log.Tracef("blur execution of token %s of %s skipped: %s", tokenID, collection.Hex(), err.Error())
+		return nil
+	}
+
+	sale := &marketplaceSale{
+		NFTs:   []marketplaceNFT{{Token: collection, Identifier: tokenID}},
+		Native: true,
+		Price:  price,
+	}
+
+	switch orderType {
+	case blurOrderTypeAsk:
+		sale.Seller, sale.Buyer = maker, to
+	case blurOrderTypeBid:
+		sale.Seller, sale.Buyer, sale.Currency = from, maker, blurPoolAddress
+	default:
+		return nil
+	}
+
+	return sale
+}
+
+// it returns the sender and recipient of the erc721 transfer of the token tokenID of collection
+func findERC721Transfer(logs []*types.Log, collection common.Address, tokenID *big.Int) (from common.Address, to common.Address, err error) {
+	for _, txLog := range logs {
+		if txLog.Address != collection || len(txLog.Topics) != 4 || txLog.Topics[0] != erc721ABI.Events["Transfer"].ID {
+			continue
+		}
+		if txLog.Topics[3].Big().Cmp(tokenID) != 0 {
+			continue
+		}
+		return common.BytesToAddress(txLog.Topics[1].Bytes()), common.BytesToAddress(txLog.Topics[2].Bytes()), nil
+	}
+
+	return common.Address{}, common.Address{}, errors.New("no erc721 transfer of the token")
+}
@@ -0,0 +1,61 @@
+package nfttradescrapers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBlurPackedSale(t *testing.T) {
+	maker := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	taker := common.HexToAddress("0x0000000000000000000000000000000000000def")
+	collection := common.HexToAddress("0xBd3531dA5CF5857e7CfAA92426877b022e612cf8")
+	tokenID := big.NewInt(1234)
+	price, _ := new(big.Int).SetString("11500000000000000000", 10)
+
+	// (tokenId << 168) | (listingIndex << 160) | trader
+	tokenIdListingIndexTrader := new(big.Int).Lsh(tokenID, 168)
+	tokenIdListingIndexTrader.Or(tokenIdListingIndexTrader, new(big.Int).Lsh(big.NewInt(3), 160))
+	tokenIdListingIndexTrader.Or(tokenIdListingIndexTrader, maker.Big())
+
+	// (orderType << 248) | (price << 160) | collection
+	packCollectionPriceSide := func(orderType int64) *big.Int {
+		v := new(big.Int).Lsh(big.NewInt(orderType), 248)
+		v.Or(v, new(big.Int).Lsh(price, 160))
+		return v.Or(v, collection.Big())
+	}
+
+	transfer := func(from, to common.Address) []*types.Log {
+		return []*types.Log{{
+			Address: collection,
+			Topics: []common.Hash{
+				erc721ABI.Events["Transfer"].ID,
+				common.BytesToHash(from.Bytes()),
+				common.BytesToHash(to.Bytes()),
+				common.BigToHash(tokenID),
+			},
+		}}
+	}
+
+	ask := blurPackedSale(tokenIdListingIndexTrader, packCollectionPriceSide(0), transfer(maker, taker))
+	if ask == nil {
+		t.Fatal("no sale of ask")
+	}
+	if ask.Seller != maker || ask.Buyer != taker || ask.Price.Cmp(price) != 0 || ask.NFTs[0].Token != collection || ask.NFTs[0].Identifier.Cmp(tokenID) != 0 {
+		t.Errorf("ask: got %+v", ask)
+	}
+
+	bid := blurPackedSale(tokenIdListingIndexTrader, packCollectionPriceSide(1), transfer(taker, maker))
+	if bid == nil {
+		t.Fatal("no sale of bid")
+	}
+	if bid.Seller != taker || bid.Buyer != maker || bid.Currency != blurPoolAddress || !bid.Native {
+		t.Errorf("bid: got %+v", bid)
+	}
+
+	if sale := blurPackedSale(tokenIdListingIndexTrader, packCollectionPriceSide(0), nil); sale != nil {
+		t.Errorf("sale without transfer: got %+v", sale)
+	}
+}
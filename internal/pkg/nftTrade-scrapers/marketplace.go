@@ -0,0 +1,419 @@
+package nfttradescrapers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/diadata-org/diadata/config/nftContracts/erc20"
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+// MarketplaceScraperConfig is the config of a scraper of the sales of an nft marketplace from the
+// events of its contracts
+type MarketplaceScraperConfig struct {
+	OpenSeaScraperConfig
+
+	// marketplace's contract addresses on connected blockchain network, one per deployed version
+	ContractAddrs []string `json:"contract_addrs"`
+}
+
+// marketplaceSale is the sale of nfts in an order, paid with the total price in a single currency
+type marketplaceSale struct {
+	NFTs     []marketplaceNFT
+	Seller   common.Address
+	Buyer    common.Address
+	Currency common.Address
+	Native   bool
+	Price    *big.Int
+}
+
+type marketplaceNFT struct {
+	Token      common.Address
+	Identifier *big.Int
+}
+
+type marketplaceCurrency struct {
+	Symbol   string
+	Decimals int
+}
+
+// marketplaceScraper scrapes the sales of an nft marketplace from the events of its contracts, it
+// shares the handling of nft classes, nfts and prices with the opensea scraper
+type marketplaceScraper struct {
+	OpenSeaScraper
+
+	// name under which the config and state of the scraper are stored
+	name string
+	// exchange of the trades
+	exchange string
+
+	// conf points to the embedded config of marketConf
+	marketConf *MarketplaceScraperConfig
+	defConf    *MarketplaceScraperConfig
+	defState   *OpenSeaScraperState
+
+	// events of the marketplace contracts emitted by sales
+	events []common.Hash
+
+	// it returns the nft sales of a transaction from its logs of the events
+	sales func(ctx context.Context, tx *utils.EthFilteredTx) ([]*marketplaceSale, error)
+
+	// erc20 currencies of trades by address
+	currencies map[common.Address]marketplaceCurrency
+}
+
+func newMarketplaceScraper(rdb *models.RelDB, name string, exchange string, defConf *MarketplaceScraperConfig, defState *OpenSeaScraperState, events []common.Hash) (*marketplaceScraper, error) {
+	eth, err := ethclient.Dial(alchemyapi)
+	if err != nil {
+		log.Errorf("unable to get ethereum client: %s", err.Error())
+		return nil, err
+	}
+
+	s := &marketplaceScraper{
+		OpenSeaScraper: OpenSeaScraper{
+			state: &OpenSeaScraperState{},
+			tradeScraper: TradeScraper{
+				shutdown:      make(chan nothing),
+				shutdownDone:  make(chan nothing),
+				datastore:     rdb,
+				chanTrade:     make(chan dia.NFTTrade),
+				source:        name,
+				ethConnection: eth,
+			},
+		},
+		name:       name,
+		exchange:   exchange,
+		marketConf: &MarketplaceScraperConfig{},
+		defConf:    defConf,
+		defState:   defState,
+		events:     events,
+		currencies: make(map[common.Address]marketplaceCurrency),
+	}
+	s.conf = &s.marketConf.OpenSeaScraperConfig
+
+	if err := s.initScraper(context.Background()); err != nil {
+		log.Errorf("%s scraper could not be initialized: %s", name, err.Error())
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// init scraper
+// if there are no values stored previously, use defaults and store them
+func (s *marketplaceScraper) initScraper(ctx context.Context) error {
+	if err := s.loadConfig(ctx); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Errorf("unable to read scraper config from rdb: %s", err.Error())
+			return err
+		}
+
+		// use & store defaults if there is no record in the scraper table
+
+		*s.marketConf = *s.defConf // copy
+		if err := s.tradeScraper.datastore.SetScraperConfig(ctx, s.name, s.marketConf); err != nil {
+			log.Errorf("unable to store scraper config on rdb: %s", err.Error())
+			return err
+		}
+
+		defState := *s.defState // copy
+		s.state = &defState
+		if err := s.storeState(ctx); err != nil {
+			log.Errorf("unable to store scraper state on rdb: %s", err.Error())
+			return err
+		}
+
+		return nil
+	}
+
+	return s.loadState(ctx)
+}
+
+func (s *marketplaceScraper) loadConfig(ctx context.Context) error {
+	return s.tradeScraper.datastore.GetScraperConfig(ctx, s.name, s.marketConf)
+}
+
+func (s *marketplaceScraper) loadState(ctx context.Context) error {
+	return s.tradeScraper.datastore.GetScraperState(ctx, s.name, s.state)
+}
+
+func (s *marketplaceScraper) storeState(ctx context.Context) error {
+	return s.tradeScraper.datastore.SetScraperState(ctx, s.name, s.state)
+}
+
+func (s *marketplaceScraper) mainLoop() {
+	defer func() {
+		s.tradeScraper.closed = true
+
+		close(s.tradeScraper.chanTrade)
+		close(s.tradeScraper.shutdownDone)
+	}()
+
+	log.Infof("%s scraper has been started (batch: %d, period: %s)", s.name, s.conf.BatchSize, s.conf.WaitPeriod.String())
+
+	for stop := false; !stop; {
+		if err := s.FetchTrades(); err != nil {
+			if errors.Is(err, errOpenSeaShutdownRequest) {
+				stop = true
+				continue
+			}
+		}
+
+		log.Debugf("wait for %s", s.conf.WaitPeriod)
+
+		select {
+		case <-time.After(s.conf.WaitPeriod):
+		case <-s.tradeScraper.shutdown:
+			stop = true
+		}
+	}
+}
+
+// FetchTrades searches for the sale events of the marketplace contracts by the next block range
+func (s *marketplaceScraper) FetchTrades() error {
+	var err error
+
+	ctx := context.Background()
+
+	// it must be run once at a time
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// read config
+	if err = s.loadConfig(ctx); err != nil {
+		log.Warnf("unable to load scraper config: %s", err.Error())
+		return err
+	}
+
+	// read state
+	if err = s.loadState(ctx); err != nil {
+		log.Warnf("unable to load scraper state: %s", err.Error())
+		return err
+	}
+
+	log.Infof("fetching %s trade transactions from block %d(+%d)", s.name, s.state.LastBlockNum, s.conf.BatchSize)
+
+	contractAddrs := make([]common.Address, 0, len(s.marketConf.ContractAddrs))
+	for _, addr := range s.marketConf.ContractAddrs {
+		contractAddrs = append(contractAddrs, common.HexToAddress(addr))
+	}
+
+	// fetch trade transactions
+	res, err := utils.EthFilterTXs(ctx, s.tradeScraper.ethConnection, utils.EthTxFilterCriteria{
+		StartBlockNum:      s.state.LastBlockNum,
+		StartTxIndex:       s.state.LastTxIndex,
+		LimitBlocks:        s.conf.BatchSize,
+		BehindHighestBlock: s.conf.FollowDist,
+		EvAddrs:            contractAddrs,
+		Events:             s.events,
+	})
+
+	if err != nil {
+		log.Warnf("unable to filter %s trades: %s", s.name, err.Error())
+		return err
+	}
+
+	log.Infof("found %d trade(logs: %d) transactions in %d blocks(from %d [tx index offset: %d] to %d, sync: %t[stay behind: -%d]), exploring details...", res.NumTXs, res.NumLogs, res.NumBlocks, s.state.LastBlockNum, s.state.LastTxIndex, res.LastBlockNum, res.Synced, s.conf.FollowDist)
+
+	numTrades := 0
+	blockTimes := make(map[uint64]time.Time)
+
+	// process trade transactions
+	for _, tx := range res.TXs {
+		s.state.LastBlockNum = tx.BlockNum
+		s.state.LastTxIndex = tx.TXIndex
+		s.state.LastErr = ""
+
+		n, err := s.processTx(ctx, tx, blockTimes)
+		if err != nil {
+			if errors.Is(err, errOpenSeaShutdownRequest) {
+				return err
+			}
+
+			s.state.ErrCounter++
+
+			if s.state.ErrCounter <= s.conf.MaxRetry {
+				s.state.LastErr = fmt.Sprintf("unable to process trade transaction(%s): %s", tx.TXHash.Hex(), err.Error())
+				log.Error(s.state.LastErr)
+				return err
+			}
+
+			log.Warnf("SKIPPING PERMANENTLY! block: %d, tx index: %d - error: %s", s.state.LastBlockNum, s.state.LastTxIndex, err.Error())
+		}
+
+		numTrades += n
+
+		// reset consecutive error counter
+		s.state.ErrCounter = 0
+
+		// move next
+		s.state.LastTxIndex = tx.TXIndex + 1
+
+		// store state
+		if err := s.storeState(ctx); err != nil {
+			log.Warnf("unable to store scraper state: %s", err.Error())
+			return err
+		}
+	}
+
+	s.state.LastBlockNum = res.LastBlockNum + 1
+	s.state.LastTxIndex = 0
+
+	if err := s.storeState(ctx); err != nil {
+		log.Warnf("unable to store scraper state: %s", err.Error())
+		return err
+	}
+
+	log.Infof("processed %d trades", numTrades)
+
+	return nil
+}
+
+// it notifies the trades of all sales in the transaction and returns their number
+func (s *marketplaceScraper) processTx(ctx context.Context, tx *utils.EthFilteredTx, blockTimes map[uint64]time.Time) (int, error) {
+	log.Tracef("process tx -> block: %d, tx index: %d, tx hash: %s", tx.BlockNum, tx.TXIndex, tx.TXHash.Hex())
+
+	sales, err := s.sales(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if len(sales) == 0 {
+		log.Tracef("tx(block: %d, tx index: %d, tx: %s) skipped due to it has no nft sale", tx.BlockNum, tx.TXIndex, tx.TXHash.Hex())
+		return 0, nil
+	}
+
+	blockTime, ok := blockTimes[tx.BlockNum]
+	if !ok {
+		header, err := s.tradeScraper.ethConnection.HeaderByNumber(ctx, new(big.Int).SetUint64(tx.BlockNum))
+		if err != nil {
+			log.Errorf("unable to read block(%d) header: %s", tx.BlockNum, err.Error())
+			return 0, err
+		}
+		blockTime = time.Unix(int64(header.Time), 0).UTC()
+		blockTimes[tx.BlockNum] = blockTime
+	}
+
+	numTrades := 0
+	for _, sale := range sales {
+		currSymbol, currDecimals := "ETH", 18
+		if !sale.Native {
+			currency, err := s.currency(ctx, sale.Currency)
+			if err != nil {
+				log.Errorf("unable to read currency(%s) of tx(%s): %s", sale.Currency.Hex(), tx.TXHash.Hex(), err.Error())
+				return numTrades, err
+			}
+			currSymbol, currDecimals = currency.Symbol, currency.Decimals
+		}
+
+		// the price of a bundle is split evenly among its nfts
+		price := new(big.Int).Div(sale.Price, big.NewInt(int64(len(sale.NFTs))))
+		normPrice := decimal.NewFromBigInt(price, 0).Div(decimal.NewFromInt(10).Pow(decimal.NewFromInt(int64(currDecimals))))
+
+		usdPrice, err := s.calcUSDPrice(tx.BlockNum, sale.Currency, currSymbol, normPrice)
+		if err != nil {
+			log.Errorf("unable to calculate usd price of the tx(block: %d, tx: %s): %s", tx.BlockNum, tx.TXHash.Hex(), err.Error())
+			return numTrades, err
+		}
+
+		for _, item := range sale.NFTs {
+			transfer := &erc721Transfer{
+				NFTAddress: item.Token,
+				From:       sale.Seller,
+				To:         sale.Buyer,
+				TokenID:    item.Identifier,
+				TokenAttrs: make(map[string]interface{}),
+			}
+			s.readERC721Metadata(ctx, transfer, tx.BlockNum)
+
+			trade, err := s.marketplaceTrade(transfer, price, usdPrice, currSymbol, sale.Currency, currDecimals)
+			if err != nil {
+				return numTrades, err
+			}
+			trade.BlockNumber = tx.BlockNum
+			trade.Timestamp = blockTime
+			trade.TxHash = tx.TXHash.Hex()
+
+			// handle close request if the chanTrade not consumed immediately
+			select {
+			case s.tradeScraper.chanTrade <- trade:
+			case <-s.tradeScraper.shutdown:
+				return numTrades, errOpenSeaShutdownRequest
+			}
+			numTrades++
+		}
+	}
+
+	return numTrades, nil
+}
+
+func (s *marketplaceScraper) marketplaceTrade(transfer *erc721Transfer, price *big.Int, usdPrice float64, currSymbol string, currAddr common.Address, currDecimals int) (dia.NFTTrade, error) {
+	nftClass, err := s.createOrReadNFTClass(transfer)
+	if err != nil {
+		return dia.NFTTrade{}, err
+	}
+
+	nft, err := s.createOrReadNFT(nftClass, transfer)
+	if err != nil {
+		return dia.NFTTrade{}, err
+	}
+
+	return dia.NFTTrade{
+		NFT:              *nft,
+		Price:            price,
+		PriceUSD:         usdPrice,
+		FromAddress:      transfer.From.Hex(),
+		ToAddress:        transfer.To.Hex(),
+		CurrencySymbol:   currSymbol,
+		CurrencyAddress:  currAddr.Hex(),
+		CurrencyDecimals: int32(currDecimals),
+		Exchange:         s.exchange,
+	}, nil
+}
+
+// it returns the symbol and decimals of the erc20 token at addr, which are read once
+func (s *marketplaceScraper) currency(ctx context.Context, addr common.Address) (marketplaceCurrency, error) {
+	if currency, ok := s.currencies[addr]; ok {
+		return currency, nil
+	}
+
+	metadata, err := erc20.NewERC20Metadata(addr, s.tradeScraper.ethConnection)
+	if err != nil {
+		return marketplaceCurrency{}, err
+	}
+
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	symbol, err := metadata.Symbol(callOpts)
+	if err != nil {
+		return marketplaceCurrency{}, err
+	}
+
+	decimals, err := metadata.Decimals(callOpts)
+	if err != nil {
+		return marketplaceCurrency{}, err
+	}
+
+	currency := marketplaceCurrency{Symbol: symbol, Decimals: int(decimals)}
+	s.currencies[addr] = currency
+
+	return currency, nil
+}
+
+// GetDataChannel returns the scrapers data channel.
+func (s *marketplaceScraper) GetTradeChannel() chan dia.NFTTrade {
+	return s.tradeScraper.chanTrade
+}
+
+func marketplaceNFTKey(nft marketplaceNFT) string {
+	return nft.Token.Hex() + "-" + nft.Identifier.String()
+}
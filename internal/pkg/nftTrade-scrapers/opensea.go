@@ -793,41 +793,49 @@ func (s *OpenSeaScraper) findERC721Transfers(ctx context.Context, receipt *types
 			TokenAttrs: make(map[string]interface{}),
 		}
 
-		callOpts := &bind.CallOpts{Context: ctx}
+		s.readERC721Metadata(ctx, transfer, txLog.BlockNumber)
 
-		if s.conf.UseArchiveNode {
-			callOpts.BlockNumber = new(big.Int).SetUint64(txLog.BlockNumber)
-		}
+		transfers = append(transfers, transfer)
+	}
 
-		if md, err := erc721.NewERC721Metadata(txLog.Address, s.tradeScraper.ethConnection); err != nil {
-			log.Warnf("unable to bind erc721 metadata contract at address %s: %s", txLog.Address.Hex(), err.Error())
-		} else {
-			if nftName, err := md.Name(callOpts); err != nil {
-				log.Warnf("unable to read nft name from metadata interface of erc721(addr: %s): %s", txLog.Address.Hex(), err.Error())
-			} else {
-				transfer.Name = &nftName
-			}
+	return transfers, nil
+}
 
-			if nftSymbol, err := md.Symbol(callOpts); err != nil {
-				log.Warnf("unable to read nft symbol from metadata interface of nft(addr: %s): %s", txLog.Address.Hex(), err.Error())
-			} else {
-				transfer.Symbol = &nftSymbol
-			}
+// it reads the name and symbol of the nft contract and the uri and attributes of the token of the
+// given transfer at the block blockNum, if UseArchiveNode is set, on a best effort basis
+func (s *OpenSeaScraper) readERC721Metadata(ctx context.Context, transfer *erc721Transfer, blockNum uint64) {
+	callOpts := &bind.CallOpts{Context: ctx}
 
-			if tokenURI, err := md.TokenURI(callOpts, transfer.TokenID); err != nil {
-				log.Warnf("unable to find token(%s) uri: %s", transfer.TokenID.String(), err.Error())
-			} else if attrs, err := s.readNFTAttr(ctx, tokenURI); err != nil {
-				log.Warnf("unable to read token(%s) attributes: %s", transfer.TokenID.String(), err.Error())
-			} else {
-				transfer.TokenURI = &tokenURI
-				transfer.TokenAttrs = attrs
-			}
-		}
+	if s.conf.UseArchiveNode {
+		callOpts.BlockNumber = new(big.Int).SetUint64(blockNum)
+	}
 
-		transfers = append(transfers, transfer)
+	md, err := erc721.NewERC721Metadata(transfer.NFTAddress, s.tradeScraper.ethConnection)
+	if err != nil {
+		log.Warnf("unable to bind erc721 metadata contract at address %s: %s", transfer.NFTAddress.Hex(), err.Error())
+		return
 	}
 
-	return transfers, nil
+	if nftName, err := md.Name(callOpts); err != nil {
+		log.Warnf("unable to read nft name from metadata interface of erc721(addr: %s): %s", transfer.NFTAddress.Hex(), err.Error())
+	} else {
+		transfer.Name = &nftName
+	}
+
+	if nftSymbol, err := md.Symbol(callOpts); err != nil {
+		log.Warnf("unable to read nft symbol from metadata interface of nft(addr: %s): %s", transfer.NFTAddress.Hex(), err.Error())
+	} else {
+		transfer.Symbol = &nftSymbol
+	}
+
+	if tokenURI, err := md.TokenURI(callOpts, transfer.TokenID); err != nil {
+		log.Warnf("unable to find token(%s) uri: %s", transfer.TokenID.String(), err.Error())
+	} else if attrs, err := s.readNFTAttr(ctx, tokenURI); err != nil {
+		log.Warnf("unable to read token(%s) attributes: %s", transfer.TokenID.String(), err.Error())
+	} else {
+		transfer.TokenURI = &tokenURI
+		transfer.TokenAttrs = attrs
+	}
 }
 
 func (s *OpenSeaScraper) readNFTAttr(ctx context.Context, uri string) (map[string]interface{}, error) {
@@ -0,0 +1,227 @@
+package nfttradescrapers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// Seaport is the name of the scraper of the Seaport protocol of OpenSea, under which its
+	// config and state are stored. Its trades are recorded on the exchange OpenSea.
+	Seaport = "Seaport"
+
+	seaportOrderFulfilledABI = `[{"anonymous":false,"inputs":[{"indexed":false,"internalType":"bytes32","name":"orderHash","type":"bytes32"},{"indexed":true,"internalType":"address","name":"offerer","type":"address"},{"indexed":true,"internalType":"address","name":"zone","type":"address"},{"indexed":false,"internalType":"address","name":"recipient","type":"address"},{"components":[{"internalType":"enum ItemType","name":"itemType","type":"uint8"},{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"identifier","type":"uint256"},{"internalType":"uint256","name":"amount","type":"uint256"}],"indexed":false,"internalType":"struct SpentItem[]","name":"offer","type":"tuple[]"},{"components":[{"internalType":"enum ItemType","name":"itemType","type":"uint8"},{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"identifier","type":"uint256"},{"internalType":"uint256","name":"amount","type":"uint256"},{"internalType":"address payable","name":"recipient","type":"address"}],"indexed":false,"internalType":"struct ReceivedItem[]","name":"consideration","type":"tuple[]"}],"name":"OrderFulfilled","type":"event"}]`
+)
+
+// item types of seaport offer and consideration items
+const (
+	seaportItemNative uint8 = iota
+	seaportItemERC20
+	seaportItemERC721
+	seaportItemERC1155
+	seaportItemERC721WithCriteria
+	seaportItemERC1155WithCriteria
+)
+
+// SeaportScraper scrapes the sales of OpenSea from the OrderFulfilled events of the Seaport
+// contracts
+type SeaportScraper struct {
+	*marketplaceScraper
+}
+
+// seaportItem is an offer or consideration item of the OrderFulfilled event, recipient is only
+// set for consideration items
+type seaportItem struct {
+	ItemType   uint8
+	Token      common.Address
+	Identifier *big.Int
+	Amount     *big.Int
+	Recipient  common.Address
+}
+
+type seaportOrderFulfilled struct {
+	OrderHash     [32]byte
+	Offerer       common.Address
+	Recipient     common.Address
+	Offer         []seaportItem
+	Consideration []seaportItem
+}
+
+var (
+	defSeaportConf = &MarketplaceScraperConfig{
+		OpenSeaScraperConfig: OpenSeaScraperConfig{
+			BatchSize:       500,
+			WaitPeriod:      5 * time.Second,
+			FollowDist:      blockDelayEthereum,
+			UseArchiveNode:  false,
+			MaxRetry:        10,
+			SkipOnErr:       true,
+			MaxMetadataSize: 50 * 1024,
+			MetadataTimeout: 30 * time.Second,
+		},
+		ContractAddrs: []string{
+			"0x00000000006c3852cbEf3e08E8dF289169EdE581", // v1.1
+			"0x00000000000001ad428e4906aE43D8F9852d0dD6", // v1.4
+			"0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC", // v1.5
+			"0x0000000000000068F116a894984e2DB1123eB395", // v1.6
+		},
+	}
+
+	// Seaport v1.1 has been deployed on the mainnet at block num 14946474,
+	// so scraper starts from this block
+	defSeaportState = &OpenSeaScraperState{LastBlockNum: 14946474}
+
+	seaportABI abi.ABI
+)
+
+func init() {
+	var err error
+
+	seaportABI, err = abi.JSON(strings.NewReader(seaportOrderFulfilledABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSeaportScraper(rdb *models.RelDB) *SeaportScraper {
+	m, err := newMarketplaceScraper(rdb, Seaport, OpenSea, defSeaportConf, defSeaportState, []common.Hash{seaportABI.Events["OrderFulfilled"].ID})
+	if err != nil {
+		return nil
+	}
+	m.sales = seaportTxSales
+
+	go m.mainLoop()
+
+	return &SeaportScraper{marketplaceScraper: m}
+}
+
+// it returns the nft sales of the orders fulfilled in the transaction
+func seaportTxSales(ctx context.Context, tx *utils.EthFilteredTx) ([]*marketplaceSale, error) {
+	var events []*seaportOrderFulfilled
+	for _, txLog := range tx.Logs {
+		ev, err := parseSeaportOrderFulfilled(txLog)
+		if err != nil {
+			log.Errorf("unable to decode seaport OrderFulfilled event(tx: %s, logIndex: %d) (SKIPPED!): %s", tx.TXHash, txLog.Index, err.Error())
+			continue
+		}
+		events = append(events, ev)
+	}
+
+	return seaportSales(events), nil
+}
+
+func parseSeaportOrderFulfilled(txLog types.Log) (*seaportOrderFulfilled, error) {
+	if len(txLog.Topics) < 3 || txLog.Topics[0] != seaportABI.Events["OrderFulfilled"].ID {
+		return nil, errors.New("log is not an OrderFulfilled event")
+	}
+
+	ev := &seaportOrderFulfilled{}
+	if err := seaportABI.UnpackIntoInterface(ev, "OrderFulfilled", txLog.Data); err != nil {
+		return nil, err
+	}
+	ev.Offerer = common.BytesToAddress(txLog.Topics[1].Bytes())
+
+	return ev, nil
+}
+
+// seaportSales returns the nft sales of the orders fulfilled in a transaction
+//
+// an order sells the nfts of its offer for the payments of its consideration, i.e. a listing, or
+// buys the nfts of its consideration with the payments of its offer, i.e. an accepted bid. the
+// payments sum up to the price including fees and royalties. orders swapping nfts, paid in several
+// currencies or without payment are skipped. when orders are matched, the two sides of a sale are
+// both fulfilled in the transaction, so each nft is only sold once per transaction, completed with
+// the parties known from the other side.
+func seaportSales(events []*seaportOrderFulfilled) []*marketplaceSale {
+	var sales []*marketplaceSale
+	sold := make(map[string]*marketplaceSale)
+
+	for _, ev := range events {
+		sale := seaportSaleOf(ev)
+		if sale == nil {
+			continue
+		}
+
+		var known *marketplaceSale
+		for _, nft := range sale.NFTs {
+			if known = sold[marketplaceNFTKey(nft)]; known != nil {
+				break
+			}
+		}
+		if known != nil {
+			if known.Seller == (common.Address{}) {
+				known.Seller = sale.Seller
+			}
+			if known.Buyer == (common.Address{}) {
+				known.Buyer = sale.Buyer
+			}
+			continue
+		}
+
+		for _, nft := range sale.NFTs {
+			sold[marketplaceNFTKey(nft)] = sale
+		}
+		sales = append(sales, sale)
+	}
+
+	return sales
+}
+
+func seaportSaleOf(ev *seaportOrderFulfilled) *marketplaceSale {
+	offerNFTs, offerPayments := splitSeaportItems(ev.Offer)
+	considerationNFTs, considerationPayments := splitSeaportItems(ev.Consideration)
+
+	sale := &marketplaceSale{}
+	var payments []seaportItem
+
+	switch {
+	case len(offerNFTs) > 0 && len(considerationNFTs) > 0:
+		return nil
+	case len(offerNFTs) > 0:
+		sale.NFTs, payments = offerNFTs, considerationPayments
+		sale.Seller, sale.Buyer = ev.Offerer, ev.Recipient
+	case len(considerationNFTs) > 0:
+		sale.NFTs, payments = considerationNFTs, offerPayments
+		sale.Seller, sale.Buyer = ev.Recipient, ev.Offerer
+	default:
+		return nil
+	}
+
+	if len(payments) == 0 {
+		return nil
+	}
+
+	sale.Currency = payments[0].Token
+	sale.Native = payments[0].ItemType == seaportItemNative
+	sale.Price = new(big.Int)
+	for _, payment := range payments {
+		if payment.ItemType != payments[0].ItemType || payment.Token != sale.Currency {
+			return nil
+		}
+		sale.Price.Add(sale.Price, payment.Amount)
+	}
+
+	return sale
+}
+
+// it splits items into nfts and payments
+func splitSeaportItems(items []seaportItem) (nfts []marketplaceNFT, payments []seaportItem) {
+	for _, item := range items {
+		switch item.ItemType {
+		case seaportItemNative, seaportItemERC20:
+			payments = append(payments, item)
+		case seaportItemERC721, seaportItemERC1155, seaportItemERC721WithCriteria, seaportItemERC1155WithCriteria:
+			nfts = append(nfts, marketplaceNFT{Token: item.Token, Identifier: item.Identifier})
+		}
+	}
+	return
+}
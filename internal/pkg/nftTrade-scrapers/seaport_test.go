@@ -0,0 +1,93 @@
+package nfttradescrapers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	testSeller = common.HexToAddress("0x01")
+	testBuyer  = common.HexToAddress("0x02")
+	testFee    = common.HexToAddress("0x03")
+	testNFT    = common.HexToAddress("0x04")
+	testWETH   = common.HexToAddress("0x05")
+)
+
+func testSeaportItem(itemType uint8, token common.Address, identifier int64, amount int64, recipient common.Address) seaportItem {
+	return seaportItem{ItemType: itemType, Token: token, Identifier: big.NewInt(identifier), Amount: big.NewInt(amount), Recipient: recipient}
+}
+
+func TestSeaportSales(t *testing.T) {
+	listing := &seaportOrderFulfilled{
+		Offerer:   testSeller,
+		Recipient: testBuyer,
+		Offer:     []seaportItem{testSeaportItem(seaportItemERC721, testNFT, 1, 1, common.Address{})},
+		Consideration: []seaportItem{
+			testSeaportItem(seaportItemNative, common.Address{}, 0, 975, testSeller),
+			testSeaportItem(seaportItemNative, common.Address{}, 0, 25, testFee),
+		},
+	}
+	bid := &seaportOrderFulfilled{
+		Offerer:   testBuyer,
+		Recipient: testSeller,
+		Offer:     []seaportItem{testSeaportItem(seaportItemERC20, testWETH, 0, 1000, common.Address{})},
+		Consideration: []seaportItem{
+			testSeaportItem(seaportItemERC721, testNFT, 2, 1, testBuyer),
+			testSeaportItem(seaportItemERC20, testWETH, 0, 25, testFee),
+		},
+	}
+	bundle := &seaportOrderFulfilled{
+		Offerer:   testSeller,
+		Recipient: testBuyer,
+		Offer: []seaportItem{
+			testSeaportItem(seaportItemERC721, testNFT, 3, 1, common.Address{}),
+			testSeaportItem(seaportItemERC721, testNFT, 4, 1, common.Address{}),
+		},
+		Consideration: []seaportItem{testSeaportItem(seaportItemNative, common.Address{}, 0, 2000, testSeller)},
+	}
+	mixedCurrencies := &seaportOrderFulfilled{
+		Offerer:   testSeller,
+		Recipient: testBuyer,
+		Offer:     []seaportItem{testSeaportItem(seaportItemERC721, testNFT, 5, 1, common.Address{})},
+		Consideration: []seaportItem{
+			testSeaportItem(seaportItemNative, common.Address{}, 0, 1000, testSeller),
+			testSeaportItem(seaportItemERC20, testWETH, 0, 1000, testSeller),
+		},
+	}
+	// the buyer's side of a matched listing, whose seller side has no recipient
+	matchedSellerSide := &seaportOrderFulfilled{
+		Offerer:       testSeller,
+		Offer:         []seaportItem{testSeaportItem(seaportItemERC721, testNFT, 6, 1, common.Address{})},
+		Consideration: []seaportItem{testSeaportItem(seaportItemNative, common.Address{}, 0, 500, testSeller)},
+	}
+	matchedBuyerSide := &seaportOrderFulfilled{
+		Offerer:       testBuyer,
+		Offer:         []seaportItem{testSeaportItem(seaportItemNative, common.Address{}, 0, 500, common.Address{})},
+		Consideration: []seaportItem{testSeaportItem(seaportItemERC721, testNFT, 6, 1, testBuyer)},
+	}
+
+	sales := seaportSales([]*seaportOrderFulfilled{listing, bid, bundle, mixedCurrencies, matchedSellerSide, matchedBuyerSide})
+	expected := []struct {
+		nfts   int
+		native bool
+		price  int64
+	}{
+		{1, true, 1000},
+		{1, false, 1000},
+		{2, true, 2000},
+		{1, true, 500},
+	}
+	if len(sales) != len(expected) {
+		t.Fatalf("got %d sales, expected %d", len(sales), len(expected))
+	}
+	for i, sale := range sales {
+		if len(sale.NFTs) != expected[i].nfts || sale.Native != expected[i].native || sale.Price.Int64() != expected[i].price {
+			t.Errorf("sale %d: got %d nfts, native %t, price %s, expected %+v", i, len(sale.NFTs), sale.Native, sale.Price, expected[i])
+		}
+		if sale.Seller != testSeller || sale.Buyer != testBuyer {
+			t.Errorf("sale %d: got seller %s and buyer %s", i, sale.Seller.Hex(), sale.Buyer.Hex())
+		}
+	}
+}
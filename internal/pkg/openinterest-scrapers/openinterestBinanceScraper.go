@@ -0,0 +1,99 @@
+package openinterestscrapers
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	binanceExchangeInfoURL = "https://fapi.binance.com/fapi/v1/exchangeInfo"
+	binancePremiumIndexURL = "https://fapi.binance.com/fapi/v1/premiumIndex"
+	binanceOpenInterestURL = "https://fapi.binance.com/fapi/v1/openInterest?symbol="
+	// binanceRequestDelay keeps the requests of open interest well within the weight limit.
+	binanceRequestDelay = 50 * time.Millisecond
+)
+
+// BinanceOpenInterestScraper fetches open interest from the Binance USDⓈ-M futures API.
+type BinanceOpenInterestScraper struct{}
+
+// NewBinanceOpenInterestScraper returns a BinanceOpenInterestScraper.
+func NewBinanceOpenInterestScraper() *BinanceOpenInterestScraper {
+	return &BinanceOpenInterestScraper{}
+}
+
+type binanceExchangeInfo struct {
+	Symbols []struct {
+		Symbol       string `json:"symbol"`
+		ContractType string `json:"contractType"`
+		Status       string `json:"status"`
+		BaseAsset    string `json:"baseAsset"`
+	} `json:"symbols"`
+}
+
+type binancePremiumIndex struct {
+	Symbol    string `json:"symbol"`
+	MarkPrice string `json:"markPrice"`
+}
+
+type binanceOpenInterest struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"`
+	Time         int64  `json:"time"`
+}
+
+// FetchOpenInterest implements OpenInterestScraper. Binance returns the open interest of one
+// instrument per request, so this takes about a twentieth of a second per instrument.
+func (s *BinanceOpenInterestScraper) FetchOpenInterest() ([]dia.OpenInterest, error) {
+	var info binanceExchangeInfo
+	err := utils.GetJSONWithBackoff(binanceExchangeInfoURL, &info)
+	if err != nil {
+		return nil, err
+	}
+	var indices []binancePremiumIndex
+	err = utils.GetJSONWithBackoff(binancePremiumIndexURL, &indices)
+	if err != nil {
+		return nil, err
+	}
+	markPrices := make(map[string]float64)
+	for _, index := range indices {
+		markPrice, err := strconv.ParseFloat(index.MarkPrice, 64)
+		if err != nil {
+			log.Errorf("mark price of %s: %v", index.Symbol, err)
+			continue
+		}
+		markPrices[index.Symbol] = markPrice
+	}
+
+	var openInterests []dia.OpenInterest
+	for _, symbol := range info.Symbols {
+		if symbol.Status != "TRADING" || symbol.ContractType == "" {
+			continue
+		}
+		time.Sleep(binanceRequestDelay)
+		var response binanceOpenInterest
+		err := utils.GetJSONWithBackoff(binanceOpenInterestURL+url.QueryEscape(symbol.Symbol), &response)
+		if err != nil {
+			log.Errorf("open interest of %s: %v", symbol.Symbol, err)
+			continue
+		}
+		// The open interest of USDⓈ-M futures is in units of the base asset.
+		openInterest, err := strconv.ParseFloat(response.OpenInterest, 64)
+		if err != nil {
+			log.Errorf("open interest of %s: %v", symbol.Symbol, err)
+			continue
+		}
+		openInterests = append(openInterests, dia.OpenInterest{
+			Symbol:          symbol.BaseAsset,
+			Instrument:      symbol.Symbol,
+			Exchange:        dia.BinanceExchange,
+			OpenInterest:    openInterest,
+			OpenInterestUSD: openInterest * markPrices[symbol.Symbol],
+			Time:            time.Unix(0, response.Time*int64(time.Millisecond)),
+		})
+	}
+	return openInterests, nil
+}
@@ -0,0 +1,119 @@
+package openinterestscrapers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	bybitTickersURL     = "https://api.bybit.com/v5/market/tickers?category=linear"
+	bybitInstrumentsURL = "https://api.bybit.com/v5/market/instruments-info?category=linear&limit=1000"
+)
+
+// BybitOpenInterestScraper fetches open interest from the Bybit API.
+type BybitOpenInterestScraper struct{}
+
+// NewBybitOpenInterestScraper returns a BybitOpenInterestScraper.
+func NewBybitOpenInterestScraper() *BybitOpenInterestScraper {
+	return &BybitOpenInterestScraper{}
+}
+
+type bybitTickers struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol            string `json:"symbol"`
+			OpenInterest      string `json:"openInterest"`
+			OpenInterestValue string `json:"openInterestValue"`
+		} `json:"list"`
+	} `json:"result"`
+	Time int64 `json:"time"`
+}
+
+type bybitInstruments struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol   string `json:"symbol"`
+			Status   string `json:"status"`
+			BaseCoin string `json:"baseCoin"`
+		} `json:"list"`
+		NextPageCursor string `json:"nextPageCursor"`
+	} `json:"result"`
+}
+
+// fetchBaseCoins returns the underlying asset of each traded linear instrument, including dated
+// futures such as BTCUSDT-27DEC24.
+func (s *BybitOpenInterestScraper) fetchBaseCoins() (map[string]string, error) {
+	baseCoins := make(map[string]string)
+	cursor := ""
+	for {
+		var instruments bybitInstruments
+		err := utils.GetJSONWithBackoff(bybitInstrumentsURL+"&cursor="+url.QueryEscape(cursor), &instruments)
+		if err != nil {
+			return nil, err
+		}
+		if instruments.RetCode != 0 {
+			return nil, fmt.Errorf("bybit instruments: %s", instruments.RetMsg)
+		}
+		for _, instrument := range instruments.Result.List {
+			if instrument.Status == "Trading" {
+				baseCoins[instrument.Symbol] = instrument.BaseCoin
+			}
+		}
+		cursor = instruments.Result.NextPageCursor
+		if cursor == "" {
+			return baseCoins, nil
+		}
+	}
+}
+
+// FetchOpenInterest implements OpenInterestScraper.
+func (s *BybitOpenInterestScraper) FetchOpenInterest() ([]dia.OpenInterest, error) {
+	var tickers bybitTickers
+	err := utils.GetJSONWithBackoff(bybitTickersURL, &tickers)
+	if err != nil {
+		return nil, err
+	}
+	if tickers.RetCode != 0 {
+		return nil, fmt.Errorf("bybit tickers: %s", tickers.RetMsg)
+	}
+	baseCoins, err := s.fetchBaseCoins()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Unix(0, tickers.Time*int64(time.Millisecond))
+	var openInterests []dia.OpenInterest
+	for _, ticker := range tickers.Result.List {
+		baseCoin, ok := baseCoins[ticker.Symbol]
+		if !ok {
+			continue
+		}
+		openInterest, err := strconv.ParseFloat(ticker.OpenInterest, 64)
+		if err != nil {
+			log.Errorf("open interest of %s: %v", ticker.Symbol, err)
+			continue
+		}
+		openInterestUSD, err := strconv.ParseFloat(ticker.OpenInterestValue, 64)
+		if err != nil {
+			log.Errorf("open interest value of %s: %v", ticker.Symbol, err)
+			continue
+		}
+		openInterests = append(openInterests, dia.OpenInterest{
+			Symbol:          baseCoin,
+			Instrument:      ticker.Symbol,
+			Exchange:        dia.BybitExchange,
+			OpenInterest:    openInterest,
+			OpenInterestUSD: openInterestUSD,
+			Time:            now,
+		})
+	}
+	return openInterests, nil
+}
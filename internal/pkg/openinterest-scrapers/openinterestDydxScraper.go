@@ -0,0 +1,64 @@
+package openinterestscrapers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const dydxPerpetualMarketsURL = "https://indexer.dydx.trade/v4/perpetualMarkets"
+
+// DydxOpenInterestScraper fetches open interest from the indexer of dYdX v4.
+type DydxOpenInterestScraper struct{}
+
+// NewDydxOpenInterestScraper returns a DydxOpenInterestScraper.
+func NewDydxOpenInterestScraper() *DydxOpenInterestScraper {
+	return &DydxOpenInterestScraper{}
+}
+
+type dydxPerpetualMarkets struct {
+	Markets map[string]struct {
+		Status       string `json:"status"`
+		OraclePrice  string `json:"oraclePrice"`
+		OpenInterest string `json:"openInterest"`
+	} `json:"markets"`
+}
+
+// FetchOpenInterest implements OpenInterestScraper. The value of open interest on dYdX is
+// computed at the oracle price, which is also the mark price.
+func (s *DydxOpenInterestScraper) FetchOpenInterest() ([]dia.OpenInterest, error) {
+	var markets dydxPerpetualMarkets
+	err := utils.GetJSONWithBackoff(dydxPerpetualMarketsURL, &markets)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var openInterests []dia.OpenInterest
+	for ticker, market := range markets.Markets {
+		if market.Status != "ACTIVE" {
+			continue
+		}
+		openInterest, err := strconv.ParseFloat(market.OpenInterest, 64)
+		if err != nil {
+			log.Errorf("open interest of %s: %v", ticker, err)
+			continue
+		}
+		oraclePrice, err := strconv.ParseFloat(market.OraclePrice, 64)
+		if err != nil {
+			log.Errorf("oracle price of %s: %v", ticker, err)
+			continue
+		}
+		openInterests = append(openInterests, dia.OpenInterest{
+			Symbol:          strings.Split(ticker, "-")[0],
+			Instrument:      ticker,
+			Exchange:        dia.DydxExchange,
+			OpenInterest:    openInterest,
+			OpenInterestUSD: openInterest * oraclePrice,
+			Time:            now,
+		})
+	}
+	return openInterests, nil
+}
@@ -0,0 +1,65 @@
+package openinterestscrapers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const gateIOContractsURL = "https://api.gateio.ws/api/v4/futures/usdt/contracts"
+
+// GateIOOpenInterestScraper fetches open interest from the Gate.io USDT futures API.
+type GateIOOpenInterestScraper struct{}
+
+// NewGateIOOpenInterestScraper returns a GateIOOpenInterestScraper.
+func NewGateIOOpenInterestScraper() *GateIOOpenInterestScraper {
+	return &GateIOOpenInterestScraper{}
+}
+
+type gateIOContract struct {
+	Name             string `json:"name"`
+	MarkPrice        string `json:"mark_price"`
+	QuantoMultiplier string `json:"quanto_multiplier"`
+	PositionSize     int64  `json:"position_size"`
+	InDelisting      bool   `json:"in_delisting"`
+}
+
+// FetchOpenInterest implements OpenInterestScraper. Gate.io gives the open interest as a number
+// of contracts, each of which is worth quanto_multiplier of the underlying asset.
+func (s *GateIOOpenInterestScraper) FetchOpenInterest() ([]dia.OpenInterest, error) {
+	var contracts []gateIOContract
+	err := utils.GetJSONWithBackoff(gateIOContractsURL, &contracts)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var openInterests []dia.OpenInterest
+	for _, contract := range contracts {
+		if contract.InDelisting {
+			continue
+		}
+		multiplier, err := strconv.ParseFloat(contract.QuantoMultiplier, 64)
+		if err != nil {
+			log.Errorf("contract size of %s: %v", contract.Name, err)
+			continue
+		}
+		markPrice, err := strconv.ParseFloat(contract.MarkPrice, 64)
+		if err != nil {
+			log.Errorf("mark price of %s: %v", contract.Name, err)
+			continue
+		}
+		openInterest := float64(contract.PositionSize) * multiplier
+		openInterests = append(openInterests, dia.OpenInterest{
+			Symbol:          strings.Split(contract.Name, "_")[0],
+			Instrument:      contract.Name,
+			Exchange:        dia.GateIOExchange,
+			OpenInterest:    openInterest,
+			OpenInterestUSD: openInterest * markPrice,
+			Time:            now,
+		})
+	}
+	return openInterests, nil
+}
@@ -0,0 +1,77 @@
+package openinterestscrapers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const okexOpenInterestURL = "https://www.okx.com/api/v5/public/open-interest?instType="
+
+// okexInstrumentTypes are the types of instruments whose open interest is collected.
+var okexInstrumentTypes = []string{"SWAP", "FUTURES"}
+
+// OKExOpenInterestScraper fetches open interest from the OKX API.
+type OKExOpenInterestScraper struct{}
+
+// NewOKExOpenInterestScraper returns an OKExOpenInterestScraper.
+func NewOKExOpenInterestScraper() *OKExOpenInterestScraper {
+	return &OKExOpenInterestScraper{}
+}
+
+type okexOpenInterest struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		InstID string `json:"instId"`
+		OiCcy  string `json:"oiCcy"`
+		OiUsd  string `json:"oiUsd"`
+		Ts     string `json:"ts"`
+	} `json:"data"`
+}
+
+// FetchOpenInterest implements OpenInterestScraper. The open interest of OKX is given both in
+// contracts and in the underlying currency, of which the latter is collected.
+func (s *OKExOpenInterestScraper) FetchOpenInterest() ([]dia.OpenInterest, error) {
+	var openInterests []dia.OpenInterest
+	for _, instType := range okexInstrumentTypes {
+		var response okexOpenInterest
+		err := utils.GetJSONWithBackoff(okexOpenInterestURL+instType, &response)
+		if err != nil {
+			return nil, err
+		}
+		if response.Code != "0" {
+			return nil, fmt.Errorf("okex open interest: %s", response.Msg)
+		}
+		for _, data := range response.Data {
+			openInterest, err := strconv.ParseFloat(data.OiCcy, 64)
+			if err != nil {
+				log.Errorf("open interest of %s: %v", data.InstID, err)
+				continue
+			}
+			openInterestUSD, err := strconv.ParseFloat(data.OiUsd, 64)
+			if err != nil {
+				log.Errorf("open interest in USD of %s: %v", data.InstID, err)
+				continue
+			}
+			ts, err := strconv.ParseInt(data.Ts, 10, 64)
+			if err != nil {
+				log.Errorf("timestamp of %s: %v", data.InstID, err)
+				continue
+			}
+			openInterests = append(openInterests, dia.OpenInterest{
+				Symbol:          strings.Split(data.InstID, "-")[0],
+				Instrument:      data.InstID,
+				Exchange:        dia.OKExExchange,
+				OpenInterest:    openInterest,
+				OpenInterestUSD: openInterestUSD,
+				Time:            time.Unix(0, ts*int64(time.Millisecond)),
+			})
+		}
+	}
+	return openInterests, nil
+}
@@ -0,0 +1,33 @@
+package openinterestscrapers
+
+import (
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// OpenInterestScraper fetches the open interest of perpetual and dated futures from the API of a
+// derivatives exchange.
+type OpenInterestScraper interface {
+	// FetchOpenInterest returns the current open interest of all futures instruments.
+	FetchOpenInterest() ([]dia.OpenInterest, error)
+}
+
+// New returns the open interest scraper of @exchange, or nil if there is none.
+func New(exchange string) OpenInterestScraper {
+	switch exchange {
+	case dia.BinanceExchange:
+		return NewBinanceOpenInterestScraper()
+	case dia.BybitExchange:
+		return NewBybitOpenInterestScraper()
+	case dia.OKExExchange:
+		return NewOKExOpenInterestScraper()
+	case dia.GateIOExchange:
+		return NewGateIOOpenInterestScraper()
+	case dia.DydxExchange:
+		return NewDydxOpenInterestScraper()
+	default:
+		return nil
+	}
+}
@@ -2,17 +2,32 @@ package optionscrapers
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/diadata-org/diadata/pkg/dia"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/diadata-org/diadata/pkg/utils"
 	ws "github.com/gorilla/websocket"
-	"golang.org/x/time/rate"
-	"sync"
-	"time"
 )
 
-const clientID = "2fSbOqqC"
-const clientSecret = "78gpYOitaZkn3y39XboRAWgDrtocdtHVWOz1_IlaN74"
+const (
+	deribitWSURL  = "wss://www.deribit.com/ws/api/v2/"
+	deribitAPIURL = "https://www.deribit.com/api/v2"
+	// deribitSnapshotInterval is the minimal time between two snapshots of the market of an
+	// instrument, as tickers are streamed every second.
+	deribitSnapshotInterval = time.Minute
+	// deribitInstrumentsInterval is the time between two updates of the instruments, as
+	// Deribit lists new expiries daily.
+	deribitInstrumentsInterval = time.Hour
+	// deribitHeartbeatInterval is the interval in seconds of the heartbeats requested from
+	// Deribit, which have to be answered to keep the connection.
+	deribitHeartbeatInterval = 30
+	// deribitMaxChannels is the number of channels subscribed to per request.
+	deribitMaxChannels = 100
+)
 
 type DeribitInstrumentsResponse struct {
 	Jsonrpc string              `json:"jsonrpc"`
@@ -30,7 +45,7 @@ type DeribitInstrument struct {
 	SettlementPeriod         string  `json:"settlement_period"`
 	QuoteCurrency            string  `json:"quote_currency"`
 	OptionType               string  `json:"option_type,omitempty"`
-	MinTradeAmount           int     `json:"min_trade_amount"`
+	MinTradeAmount           float64 `json:"min_trade_amount"`
 	MakerCommission          float64 `json:"maker_commission"`
 	Kind                     string  `json:"kind"`
 	IsActive                 bool    `json:"is_active"`
@@ -44,19 +59,6 @@ type DeribitInstrument struct {
 	MaxLeverage              int     `json:"max_leverage,omitempty"`
 }
 
-type DeribitETHOptionScraper struct {
-	markets            []string
-	wsClient           *ws.Conn
-	PollFrequency      int8
-	ScraperIsRunning   bool
-	ScraperIsRunningMu sync.Mutex
-	optionsWaitGroup   *sync.WaitGroup
-	DataStore          *models.DB
-	chanOrderBook      chan *dia.OptionOrderbookDatum
-	Ratelimiter        *rate.Limiter
-	refreshToken       string
-}
-
 type DeribitRequest struct {
 	Jsonrpc string      `json:"jsonrpc"`
 	ID      int         `json:"id"`
@@ -68,306 +70,428 @@ type DeribitParam struct {
 	Channels []string `json:"channels"`
 }
 
-func NewDeribitETHOptionScraper() *DeribitETHOptionScraper {
+// DeribitMessage is a notification of a subscription, a heartbeat or the answer to a request.
+type DeribitMessage struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  struct {
+		Channel string          `json:"channel"`
+		Type    string          `json:"type"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"params"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type DeribitOptionTrade struct {
+	TradeID        string  `json:"trade_id"`
+	Timestamp      int64   `json:"timestamp"`
+	Price          float64 `json:"price"`
+	IV             float64 `json:"iv"`
+	InstrumentName string  `json:"instrument_name"`
+	IndexPrice     float64 `json:"index_price"`
+	Direction      string  `json:"direction"`
+	Amount         float64 `json:"amount"`
+}
+
+type DeribitOptionTicker struct {
+	Timestamp       int64   `json:"timestamp"`
+	InstrumentName  string  `json:"instrument_name"`
+	MarkPrice       float64 `json:"mark_price"`
+	MarkIV          float64 `json:"mark_iv"`
+	BidIV           float64 `json:"bid_iv"`
+	AskIV           float64 `json:"ask_iv"`
+	BestBidPrice    float64 `json:"best_bid_price"`
+	BestBidAmount   float64 `json:"best_bid_amount"`
+	BestAskPrice    float64 `json:"best_ask_price"`
+	BestAskAmount   float64 `json:"best_ask_amount"`
+	OpenInterest    float64 `json:"open_interest"`
+	UnderlyingPrice float64 `json:"underlying_price"`
+	IndexPrice      float64 `json:"index_price"`
+	Greeks          struct {
+		Delta float64 `json:"delta"`
+		Gamma float64 `json:"gamma"`
+		Vega  float64 `json:"vega"`
+		Theta float64 `json:"theta"`
+	} `json:"greeks"`
+}
+
+// DeribitOptionScraper streams the order books, market data and trades of the options of
+// Deribit on @currencies. Prices are in the base currency, as Deribit options are inverse.
+type DeribitOptionScraper struct {
+	currencies []string
+	// instruments are the scraped options by name
+	instruments     map[string]DeribitInstrument
+	instrumentsLock sync.RWMutex
+	// lastSnapshot is the time of the latest market snapshot of an instrument, only used by
+	// mainLoop
+	lastSnapshot map[string]time.Time
+	wsClient     *ws.Conn
+	// wsLock serializes writes to wsClient, its replacement on reconnects and requestID
+	wsLock    sync.Mutex
+	requestID int
+	run       bool
+	DataStore *models.DB
+
+	chanOrderBook  chan *dia.OptionOrderbookDatum
+	chanTrades     chan *dia.OptionTrade
+	chanMarketData chan *dia.OptionMarketDatum
+}
 
-	deribitAPI := "wss://www.deribit.com/ws/api/v2/"
+// NewDeribitOptionScraper returns a scraper of the options on @currencies, e.g. BTC and ETH.
+func NewDeribitOptionScraper(currencies []string) *DeribitOptionScraper {
 	ds, err := models.NewDataStore()
 	if err != nil {
 		log.Errorln("error getting modelstore")
 	}
 
-	s := &DeribitETHOptionScraper{
-		chanOrderBook: make(chan *dia.OptionOrderbookDatum),
-		DataStore:     ds,
+	s := &DeribitOptionScraper{
+		currencies:     currencies,
+		instruments:    make(map[string]DeribitInstrument),
+		lastSnapshot:   make(map[string]time.Time),
+		run:            true,
+		DataStore:      ds,
+		chanOrderBook:  make(chan *dia.OptionOrderbookDatum),
+		chanTrades:     make(chan *dia.OptionTrade),
+		chanMarketData: make(chan *dia.OptionMarketDatum),
 	}
-	s.GetAndStoreOptionsMeta()
 
 	var wsDialer ws.Dialer
-	SwConn, _, err := wsDialer.Dial(deribitAPI, nil)
-
+	SwConn, _, err := wsDialer.Dial(deribitWSURL, nil)
 	if err != nil {
-		println(err.Error())
+		log.Error("dial:", err)
 	}
 	s.wsClient = SwConn
 
 	return s
+}
 
+// FetchInstruments updates the scraped options and stores the meta data of new ones.
+func (scraper *DeribitOptionScraper) FetchInstruments() {
+	scraper.fetchInstruments()
 }
 
-func (scraper *DeribitETHOptionScraper) subscribe() {
-
-	id := 0
-	request := &DeribitRequest{
-		Jsonrpc: "2.0",
-		ID:      id,
-		Method:  "/public/subscribe",
-	}
-	params := DeribitParam{}
-	for _, market := range scraper.markets {
-		params.Channels = append(params.Channels, "book."+market+".none.1.100ms")
+// fetchInstruments updates the scraped options and returns the names of the new ones. Expired
+// options are removed.
+func (scraper *DeribitOptionScraper) fetchInstruments() (newInstruments []string) {
+	scraper.instrumentsLock.Lock()
+	defer scraper.instrumentsLock.Unlock()
 
+	for name, instrument := range scraper.instruments {
+		if time.Unix(0, instrument.ExpirationTimestamp*int64(time.Millisecond)).Before(time.Now()) {
+			delete(scraper.instruments, name)
+		}
 	}
-	//params.Channels = append(params.Channels, "book.ETH-25JUN21-2240-P.none.1.100ms")
 
-	request.Params = params
-	log.Info("Subscribing to Instrument ", request)
-	scraper.wsClient.WriteJSON(request)
-	id++
+	for _, currency := range scraper.currencies {
+		var response DeribitInstrumentsResponse
+		body, err := utils.GetRequest(deribitAPIURL + "/public/get_instruments?currency=" + currency + "&expired=false&kind=option")
+		if err != nil {
+			log.Errorf("get instruments of %s: %v", currency, err)
+			continue
+		}
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			log.Errorf("get instruments of %s: %v", currency, err)
+			continue
+		}
 
-}
+		for _, instrument := range response.Result {
+			if _, ok := scraper.instruments[instrument.InstrumentName]; ok {
+				continue
+			}
+			scraper.instruments[instrument.InstrumentName] = instrument
+			newInstruments = append(newInstruments, instrument.InstrumentName)
+			scraper.storeOptionMeta(instrument)
+		}
 
-type DeribitOptionResponse struct {
-	Jsonrpc string `json:"jsonrpc"`
-	Method  string `json:"method"`
-	Params  struct {
-		Channel string `json:"channel"`
-		Data    struct {
-			Asks           [][]interface{} `json:"asks,omitempty"`
-			Bids           [][]interface{} `json:"bids,omitempty"`
-			ChangeID       int64           `json:"change_id"`
-			InstrumentName string          `json:"instrument_name"`
-			PrevChangeID   int64           `json:"prev_change_id"`
-			Timestamp      int64           `json:"timestamp"`
-			Type           string          `json:"type"`
-		} `json:"data"`
-	} `json:"params"`
+		if scraper.DataStore != nil {
+			err = scraper.DataStore.RemoveExpiredOptionMeta(currency)
+			if err != nil {
+				log.Errorf("remove expired option meta of %s: %v", currency, err)
+			}
+		}
+	}
+	return
 }
 
-func (scraper *DeribitETHOptionScraper) handleWSMessage() {
-	var response DeribitOptionResponse
-	var err error
-
-	err = scraper.wsClient.ReadJSON(&response)
-	if err != nil {
-		log.Errorln("Error reading wsclient", err)
+func (scraper *DeribitOptionScraper) storeOptionMeta(instrument DeribitInstrument) {
+	if scraper.DataStore == nil {
 		return
-
 	}
-
-	var (
-		resolvedAskPX, resolvedAskSize, resolvedBidSize, resolvedBidPX float64
-	)
-
-	everything := "new"
-
-	b, _ := json.Marshal(response)
-	log.Infoln("Message Received", string(b[:]))
-	for _, askOut := range response.Params.Data.Asks {
-		switch everything {
-		case "new":
-			resolvedAskSize = askOut[1].(float64)
-			resolvedAskPX = askOut[0].(float64)
-		default:
-
-		}
-
+	optionMeta := dia.OptionMeta{
+		InstrumentName: instrument.InstrumentName,
+		BaseCurrency:   instrument.BaseCurrency,
+		ExpirationTime: time.Unix(instrument.ExpirationTimestamp/1e3, 0),
+		StrikePrice:    instrument.Strike,
+		OptionType:     deribitOptionType(instrument.OptionType),
+	}
+	err := scraper.DataStore.SetOptionMeta(&optionMeta)
+	if err != nil {
+		log.Errorf("set option meta of %s: %v", instrument.InstrumentName, err)
 	}
+}
 
-	for _, bidOut := range response.Params.Data.Bids {
-		switch everything {
-		case "delete":
-		case "new":
-			resolvedBidSize = bidOut[1].(float64)
-			resolvedBidPX = bidOut[0].(float64)
-		default:
+// Scrape streams the options fetched by FetchInstruments and subscribes to new options as they
+// are listed.
+func (scraper *DeribitOptionScraper) Scrape() {
+	go scraper.mainLoop()
+	go scraper.updateInstruments()
+}
 
+// runs in a goroutine and reconnects if the connection is lost
+func (scraper *DeribitOptionScraper) mainLoop() {
+	if scraper.wsClient == nil {
+		if err := scraper.reconnectToWS(); err != nil {
+			return
 		}
-
 	}
-
-	var o = dia.OptionOrderbookDatum{
-		InstrumentName:  response.Params.Data.InstrumentName,
-		ObservationTime: time.Unix(response.Params.Data.Timestamp/1e3, 0),
-		AskSize:         resolvedAskSize,
-		AskPrice:        resolvedAskPX,
-		BidPrice:        resolvedBidPX,
-		BidSize:         resolvedBidSize,
+	scraper.subscribeToALL()
+	for scraper.run {
+		_, message, err := scraper.wsClient.ReadMessage()
+		if err != nil {
+			log.Warning("reconnect the scraping to ws, ", err)
+			if err := scraper.reconnectToWS(); err != nil {
+				return
+			}
+			scraper.subscribeToALL()
+			continue
+		}
+		scraper.handleWSMessage(message)
 	}
-	log.Infoln("Got trade", o)
-
-	scraper.chanOrderBook <- &o
-
-	//optionMeta := dia.OptionMeta{
-	//	InstrumentName: response.Params.Data.InstrumentName,
-	//	BaseCurrency:   instrument.SettlementCurrency,
-	//	ExpirationTime: expTime,
-	//	StrikePrice:    strikePrice,
-	//	OptionType:     optionType,
-	//}
-	//
-	//scrapper.DataStore.SetOptionMeta(&optionMeta)
-
 }
-func (scraper *DeribitETHOptionScraper) Scrape() {
-
-	go scraper.heartBeat()
-	scraper.subscribe()
-	go func() {
-		for {
-			scraper.handleWSMessage()
 
+// updateInstruments periodically subscribes to new options.
+func (scraper *DeribitOptionScraper) updateInstruments() {
+	ticker := time.NewTicker(deribitInstrumentsInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		var channels []string
+		for _, name := range scraper.fetchInstruments() {
+			channels = append(channels, deribitTickerChannel(name))
 		}
-
-	}()
-
+		if len(channels) > 0 {
+			log.Infof("subscribing to %d new options", len(channels))
+			scraper.subscribe(channels)
+		}
+	}
 }
 
-func (scraper *DeribitETHOptionScraper) heartBeat() {
-
-	t := time.NewTicker(3 * time.Second)
-	for {
-		select {
-		case <-t.C:
-			{
-				var params map[string]int
-
-				id := 5600000
-				request := &DeribitRequest{
-					Jsonrpc: "2.0",
-					ID:      id,
-					Method:  "/public/set_heartbeat",
-				}
-				params = make(map[string]int)
-				params["interval"] = 30
-
-				request.Params = params
-				log.Errorln("set_heartbeat ", request)
-				scraper.wsClient.WriteJSON(request)
-				id++
-
-				request = &DeribitRequest{
-					Jsonrpc: "2.0",
-					ID:      id,
-					Method:  "public/test",
-				}
-
-				log.Errorln("public/test ", request)
-				scraper.wsClient.WriteJSON(request)
-				id++
+// Useful to reconnect to ws when the connection is down. It retries with increasing delays.
+func (scraper *DeribitOptionScraper) reconnectToWS() error {
+	var wsDialer ws.Dialer
+	for attempt := 0; scraper.run; attempt++ {
+		SwConn, _, err := wsDialer.Dial(deribitWSURL, nil)
+		if err == nil {
+			scraper.wsLock.Lock()
+			if scraper.wsClient != nil {
+				scraper.wsClient.Close()
 			}
+			scraper.wsClient = SwConn
+			scraper.wsLock.Unlock()
+			return nil
 		}
+		log.Error("dial:", err)
+		time.Sleep(utils.DefaultBackoff.Delay(attempt))
 	}
+	return errors.New("DeribitOptionScraper: stopped while reconnecting")
 }
 
-func (s *DeribitETHOptionScraper) send(message *map[string]interface{}, websocketConn *ws.Conn) error {
-	err := websocketConn.WriteJSON(*message)
+// subscribeToALL requests heartbeats and subscribes to the trades of all currencies and to the
+// tickers of all options.
+func (scraper *DeribitOptionScraper) subscribeToALL() {
+	err := scraper.request("public/set_heartbeat", map[string]int{"interval": deribitHeartbeatInterval})
 	if err != nil {
-		return err
+		log.Error("set_heartbeat: ", err)
 	}
-	return nil
-}
 
-func (s *DeribitETHOptionScraper) handleRefreshToken(previousToken string, websocketConn *ws.Conn) (bool, error) {
-	if previousToken == "" {
-		return false, nil
+	var channels []string
+	for _, currency := range scraper.currencies {
+		channels = append(channels, "trades.option."+currency+".100ms")
 	}
-	// refresh
-	err := s.send(&map[string]interface{}{
-		"method": "public/auth",
-		"params": &map[string]string{
-			"grant_type":    "refresh_token",
-			"refresh_token": previousToken,
-		},
-		"jsonrpc": "2.0",
-	}, websocketConn)
-	if err != nil {
-		return false, err
+	scraper.instrumentsLock.RLock()
+	for name := range scraper.instruments {
+		channels = append(channels, deribitTickerChannel(name))
 	}
-	return true, nil
+	scraper.instrumentsLock.RUnlock()
+	log.Infof("subscribing to %d channels", len(channels))
+	scraper.subscribe(channels)
 }
 
-type DeribitOptionsResponse struct {
-	Jsonrpc string              `json:"jsonrpc"`
-	Result  []DeribitInstrument `json:"result"`
-	UsIn    int64               `json:"usIn"`
-	UsOut   int64               `json:"usOut"`
-	UsDiff  int                 `json:"usDiff"`
-	Testnet bool                `json:"testnet"`
-}
-
-func (scraper *DeribitETHOptionScraper) FetchInstruments() {
-
-	var response DeribitOptionsResponse
-	rawResponse, err := utils.GetRequest("https://www.deribit.com/api/v2/public/get_instruments?currency=ETH&expired=false&kind=option")
-	if err != nil {
-		log.Errorln("Error Getting markets", err)
-
-	}
-
-	json.Unmarshal(rawResponse, &response)
-
-	for _, instrument := range response.Result {
-		scraper.markets = append(scraper.markets, instrument.InstrumentName)
+func (scraper *DeribitOptionScraper) subscribe(channels []string) {
+	for i := 0; i < len(channels); i += deribitMaxChannels {
+		end := i + deribitMaxChannels
+		if end > len(channels) {
+			end = len(channels)
+		}
+		err := scraper.request("public/subscribe", DeribitParam{Channels: channels[i:end]})
+		if err != nil {
+			log.Error("subscribe: ", err)
+			return
+		}
 	}
-
 }
 
-func (scraper *DeribitETHOptionScraper) MetaOnOptionIsAvailable(option DeribitInstrument) (available bool, err error) {
-	available = false
-	err = nil
-
-	// TODO: can make this faster by specifying BaseCurrency/QuoteCurrency instead
-	optionMetas, err := scraper.DataStore.GetOptionMeta(option.BaseCurrency)
-	if err != nil {
-		return
-	}
-
-	for _, optionMeta := range optionMetas {
-		if optionMeta.InstrumentName == option.InstrumentName {
-			return true, nil
-		}
+func (scraper *DeribitOptionScraper) request(method string, params interface{}) error {
+	scraper.wsLock.Lock()
+	defer scraper.wsLock.Unlock()
+	if scraper.wsClient == nil {
+		return errors.New("DeribitOptionScraper: not connected")
 	}
-
-	return
+	scraper.requestID++
+	return scraper.wsClient.WriteJSON(&DeribitRequest{
+		Jsonrpc: "2.0",
+		ID:      scraper.requestID,
+		Method:  method,
+		Params:  params,
+	})
 }
 
-func (scraper *DeribitETHOptionScraper) GetAndStoreOptionsMeta() (err error) {
-	body, err := utils.GetRequest("https://www.deribit.com/api/v2/public/get_instruments?currency=ETH&expired=false&kind=option")
+func (scraper *DeribitOptionScraper) handleWSMessage(message []byte) {
+	var response DeribitMessage
+	err := json.Unmarshal(message, &response)
 	if err != nil {
+		log.Errorln("Error reading wsclient", err)
 		return
 	}
-
-	var decodedMsg DeribitOptionsResponse
-	err = json.Unmarshal(body, &decodedMsg)
-
-	if err != nil {
+	if response.Error != nil {
+		log.Errorf("deribit error %d: %s", response.Error.Code, response.Error.Message)
 		return
 	}
 
-	for _, instrument := range decodedMsg.Result {
-		var available bool
-		available, err = scraper.MetaOnOptionIsAvailable(instrument)
-
-		if err != nil {
-			return
+	switch response.Method {
+	case "heartbeat":
+		// Deribit closes the connection if test requests are not answered.
+		if response.Params.Type == "test_request" {
+			if err := scraper.request("public/test", map[string]string{}); err != nil {
+				log.Error("public/test: ", err)
+			}
 		}
-
-		if !available {
-
-			optionType := dia.CallOption
-			if instrument.OptionType == "put" {
-				optionType = dia.PutOption
+	case "subscription":
+		channel := response.Params.Channel
+		switch {
+		case strings.HasPrefix(channel, "trades."):
+			var trades []DeribitOptionTrade
+			err = json.Unmarshal(response.Params.Data, &trades)
+			if err != nil {
+				log.Errorf("parse trades of %s: %v", channel, err)
+				return
 			}
-
-			var expTime time.Time
-			expTime = time.Unix(instrument.ExpirationTimestamp/1e3, 0)
-
-			optionMeta := dia.OptionMeta{
-				InstrumentName: instrument.InstrumentName,
-				BaseCurrency:   instrument.BaseCurrency,
-				ExpirationTime: expTime,
-				StrikePrice:    instrument.Strike,
-				OptionType:     optionType,
+			for _, trade := range trades {
+				if optionTrade, ok := scraper.optionTrade(trade); ok {
+					scraper.chanTrades <- optionTrade
+				}
+			}
+		case strings.HasPrefix(channel, "ticker."):
+			var ticker DeribitOptionTicker
+			err = json.Unmarshal(response.Params.Data, &ticker)
+			if err != nil {
+				log.Errorf("parse ticker of %s: %v", channel, err)
+				return
+			}
+			timestamp := time.Unix(0, ticker.Timestamp*int64(time.Millisecond))
+			if timestamp.Sub(scraper.lastSnapshot[ticker.InstrumentName]) < deribitSnapshotInterval {
+				return
 			}
+			datum, ok := scraper.optionMarketDatum(ticker)
+			if !ok {
+				return
+			}
+			scraper.lastSnapshot[ticker.InstrumentName] = timestamp
+			scraper.chanOrderBook <- &dia.OptionOrderbookDatum{
+				InstrumentName:  ticker.InstrumentName,
+				ObservationTime: timestamp,
+				AskPrice:        ticker.BestAskPrice,
+				BidPrice:        ticker.BestBidPrice,
+				AskSize:         ticker.BestAskAmount,
+				BidSize:         ticker.BestBidAmount,
+				StrikePrice:     datum.StrikePrice,
+				ExpirationTime:  datum.ExpirationTime,
+			}
+			scraper.chanMarketData <- datum
+		}
+	}
+}
 
-			scraper.DataStore.SetOptionMeta(&optionMeta)
+// optionTrade returns the trade @trade of a scraped option.
+func (scraper *DeribitOptionScraper) optionTrade(trade DeribitOptionTrade) (*dia.OptionTrade, bool) {
+	scraper.instrumentsLock.RLock()
+	instrument, ok := scraper.instruments[trade.InstrumentName]
+	scraper.instrumentsLock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	volume := trade.Amount
+	if trade.Direction == "sell" {
+		volume = -volume
+	}
+	return &dia.OptionTrade{
+		InstrumentName: trade.InstrumentName,
+		BaseCurrency:   instrument.BaseCurrency,
+		Exchange:       dia.Deribit,
+		Price:          trade.Price,
+		Volume:         volume,
+		// Deribit quotes implied volatilities in percent.
+		ImpliedVolatility: trade.IV / 100,
+		IndexPrice:        trade.IndexPrice,
+		ForeignTradeID:    trade.TradeID,
+		Time:              time.Unix(0, trade.Timestamp*int64(time.Millisecond)),
+	}, true
+}
 
-		}
+// optionMarketDatum returns the market snapshot in @ticker of a scraped option.
+func (scraper *DeribitOptionScraper) optionMarketDatum(ticker DeribitOptionTicker) (*dia.OptionMarketDatum, bool) {
+	scraper.instrumentsLock.RLock()
+	instrument, ok := scraper.instruments[ticker.InstrumentName]
+	scraper.instrumentsLock.RUnlock()
+	if !ok {
+		return nil, false
 	}
+	return &dia.OptionMarketDatum{
+		InstrumentName:  ticker.InstrumentName,
+		BaseCurrency:    instrument.BaseCurrency,
+		Exchange:        dia.Deribit,
+		StrikePrice:     instrument.Strike,
+		ExpirationTime:  time.Unix(0, instrument.ExpirationTimestamp*int64(time.Millisecond)),
+		OptionType:      deribitOptionType(instrument.OptionType),
+		MarkPrice:       ticker.MarkPrice,
+		MarkIV:          ticker.MarkIV / 100,
+		BidIV:           ticker.BidIV / 100,
+		AskIV:           ticker.AskIV / 100,
+		OpenInterest:    ticker.OpenInterest,
+		UnderlyingPrice: ticker.UnderlyingPrice,
+		IndexPrice:      ticker.IndexPrice,
+		Delta:           ticker.Greeks.Delta,
+		Gamma:           ticker.Greeks.Gamma,
+		Vega:            ticker.Greeks.Vega,
+		Theta:           ticker.Greeks.Theta,
+		Time:            time.Unix(0, ticker.Timestamp*int64(time.Millisecond)),
+	}, true
+}
+
+func deribitTickerChannel(instrumentName string) string {
+	return "ticker." + instrumentName + ".agg2"
+}
 
-	return nil
+func deribitOptionType(optionType string) dia.OptionType {
+	if optionType == "put" {
+		return dia.PutOption
+	}
+	return dia.CallOption
 }
 
-func (scraper *DeribitETHOptionScraper) Channel() chan *dia.OptionOrderbookDatum {
+func (scraper *DeribitOptionScraper) Channel() chan *dia.OptionOrderbookDatum {
 	return scraper.chanOrderBook
 }
+
+// TradeChannel implements OptionMarketScraper.
+func (scraper *DeribitOptionScraper) TradeChannel() chan *dia.OptionTrade {
+	return scraper.chanTrades
+}
+
+// MarketChannel implements OptionMarketScraper.
+func (scraper *DeribitOptionScraper) MarketChannel() chan *dia.OptionMarketDatum {
+	return scraper.chanMarketData
+}
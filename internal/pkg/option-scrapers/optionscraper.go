@@ -34,12 +34,19 @@ type OptionsScraper interface {
 	Channel() chan *dia.OptionOrderbookDatum
 }
 
+// OptionMarketScraper is implemented by OptionsScrapers which also stream the trades and the
+// market data of options, i.e. implied volatilities, open interest and greeks.
+type OptionMarketScraper interface {
+	TradeChannel() chan *dia.OptionTrade
+	MarketChannel() chan *dia.OptionMarketDatum
+}
+
 func New(exchange string, key string, secret string) OptionsScraper {
 	switch exchange {
 	case dia.OKExExchange:
 		return NewOKExOptionsScraper(int8(30))
 	case dia.Deribit:
-		return NewDeribitETHOptionScraper()
+		return NewDeribitOptionScraper([]string{"BTC", "ETH"})
 	case dia.Opyn:
 		return NewOpynETHOptionScraper()
 	case dia.Premia:
@@ -0,0 +1,232 @@
+package oracleFeeder
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Feed is the state of one symbol of a feeder as listed by the admin API.
+type Feed struct {
+	Symbol            string    `json:"symbol"`
+	LastPrice         float64   `json:"lastPrice"`
+	LastUpdate        time.Time `json:"lastUpdate"`
+	AbsoluteDeviation float64   `json:"absoluteDeviation,omitempty"`
+}
+
+// Feeds is the response of the admin API listing the feeds and the update policy.
+type Feeds struct {
+	DeviationPermille int    `json:"deviationPermille"`
+	HeartbeatSeconds  int64  `json:"heartbeatSeconds"`
+	Feeds             []Feed `json:"feeds"`
+}
+
+// Feeds returns the current feeds and update policy.
+func (f *Feeder) Feeds() Feeds {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	feeds := Feeds{
+		DeviationPermille: f.config.DeviationPermille,
+		HeartbeatSeconds:  int64(f.config.Heartbeat / time.Second),
+		Feeds:             []Feed{},
+	}
+	for _, symbol := range f.config.Symbols {
+		feeds.Feeds = append(feeds.Feeds, Feed{
+			Symbol:            symbol,
+			LastPrice:         f.lastPrices[symbol],
+			LastUpdate:        f.lastUpdates[symbol],
+			AbsoluteDeviation: f.config.AbsoluteDeviations[symbol],
+		})
+	}
+	return feeds
+}
+
+// AddSymbol starts feeding @symbol in the next cycle. A positive @absoluteDeviation sets its
+// absolute deviation.
+func (f *Feeder) AddSymbol(symbol string, absoluteDeviation float64) error {
+	if symbol == "" || strings.Contains(symbol, ",") {
+		return fmt.Errorf("invalid symbol %q", symbol)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.feeds(symbol) {
+		return fmt.Errorf("%s is already fed", symbol)
+	}
+	f.config.Symbols = append(f.config.Symbols, symbol)
+	if absoluteDeviation > 0 {
+		if f.config.AbsoluteDeviations == nil {
+			f.config.AbsoluteDeviations = make(map[string]float64)
+		}
+		f.config.AbsoluteDeviations[symbol] = absoluteDeviation
+	}
+	return nil
+}
+
+// RemoveSymbol stops feeding @symbol. Pending updates of the symbol are dropped.
+func (f *Feeder) RemoveSymbol(symbol string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, s := range f.config.Symbols {
+		if s == symbol {
+			f.config.Symbols = append(f.config.Symbols[:i:i], f.config.Symbols[i+1:]...)
+			delete(f.lastPrices, symbol)
+			delete(f.lastUpdates, symbol)
+			delete(f.lastSourceTimes, symbol)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not fed", symbol)
+}
+
+// SetPolicy changes the deviation and the heartbeat of all symbols. Negative values keep the
+// current setting.
+func (f *Feeder) SetPolicy(deviationPermille int, heartbeat time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if deviationPermille >= 0 {
+		f.config.DeviationPermille = deviationPermille
+	}
+	if heartbeat >= 0 {
+		f.config.Heartbeat = heartbeat
+	}
+}
+
+// AdminHandler serves the admin API of the feeder under /admin/ to requests carrying
+// "Authorization: Bearer @token":
+//
+//	GET    /admin/feeds                                    lists the feeds and the update policy
+//	POST   /admin/feeds?symbol=BTC&absoluteDeviation=0.002 adds a symbol
+//	DELETE /admin/feeds?symbol=BTC                         removes a symbol
+//	POST   /admin/policy?deviationPermille=5&heartbeatSeconds=3600
+//	POST   /admin/update?symbol=BTC                        forces an update of a symbol
+//
+// Changes apply from the next cycle on and are lost on restart.
+func (f *Feeder) AdminHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/feeds", func(w http.ResponseWriter, req *http.Request) {
+		symbol := req.URL.Query().Get("symbol")
+		switch req.Method {
+		case http.MethodGet:
+			writeAdminResponse(w, f.Feeds())
+		case http.MethodPost:
+			var absoluteDeviation float64
+			if s := req.URL.Query().Get("absoluteDeviation"); s != "" {
+				var err error
+				absoluteDeviation, err = strconv.ParseFloat(s, 64)
+				if err != nil || absoluteDeviation < 0 {
+					http.Error(w, "invalid absoluteDeviation", http.StatusBadRequest)
+					return
+				}
+			}
+			if err := f.AddSymbol(symbol, absoluteDeviation); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Infof("admin: added %s", symbol)
+			writeAdminResponse(w, f.Feeds())
+		case http.MethodDelete:
+			if err := f.RemoveSymbol(symbol); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			log.Infof("admin: removed %s", symbol)
+			writeAdminResponse(w, f.Feeds())
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/admin/policy", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		deviationPermille, heartbeatSeconds := -1, -1
+		for _, p := range []struct {
+			name  string
+			value *int
+		}{{"deviationPermille", &deviationPermille}, {"heartbeatSeconds", &heartbeatSeconds}} {
+			if s := req.URL.Query().Get(p.name); s != "" {
+				v, err := strconv.Atoi(s)
+				if err != nil || v < 0 {
+					http.Error(w, "invalid "+p.name, http.StatusBadRequest)
+					return
+				}
+				*p.value = v
+			}
+		}
+		heartbeat := time.Duration(-1)
+		if heartbeatSeconds >= 0 {
+			heartbeat = time.Duration(heartbeatSeconds) * time.Second
+		}
+		f.SetPolicy(deviationPermille, heartbeat)
+		log.Infof("admin: set deviationPermille %d and heartbeatSeconds %d", deviationPermille, heartbeatSeconds)
+		writeAdminResponse(w, f.Feeds())
+	})
+	mux.HandleFunc("/admin/update", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		symbol := req.URL.Query().Get("symbol")
+		if !f.Fed(symbol) {
+			http.Error(w, fmt.Sprintf("%s is not fed", symbol), http.StatusNotFound)
+			return
+		}
+		log.Infof("admin: forcing update of %s", symbol)
+		if err := f.ForceUpdate(symbol); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		writeAdminResponse(w, f.Feeds())
+	})
+	return authorize(token, mux)
+}
+
+// Fed returns true if the feeder currently feeds @symbol.
+func (f *Feeder) Fed(symbol string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.feeds(symbol)
+}
+
+// authorize passes requests to @next if they carry the bearer @token. An empty @token
+// rejects all requests.
+func authorize(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		given := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func writeAdminResponse(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// ReadAdminToken reads the token of the admin API from @path. An empty @path or a missing
+// file disables the admin API and returns an empty token.
+func ReadAdminToken(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,58 @@
+package oracleFeeder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdminHandler(t *testing.T) {
+	feeder := NewFeeder(Config{Symbols: []string{"BTC", "ETH"}, DeviationPermille: 10}, nil)
+	handler := feeder.AdminHandler("secret")
+
+	request := func(method string, target string, token string) (int, Feeds) {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		var feeds Feeds
+		if recorder.Code == http.StatusOK {
+			if err := json.Unmarshal(recorder.Body.Bytes(), &feeds); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return recorder.Code, feeds
+	}
+
+	if code, _ := request("GET", "/admin/feeds", ""); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", code)
+	}
+	if code, _ := request("GET", "/admin/feeds", "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with wrong token, got %d", code)
+	}
+	code, feeds := request("POST", "/admin/feeds?symbol=USDT&absoluteDeviation=0.002", "secret")
+	if code != http.StatusOK || len(feeds.Feeds) != 3 || feeds.Feeds[2].AbsoluteDeviation != 0.002 {
+		t.Errorf("adding USDT: got %d %+v", code, feeds)
+	}
+	if code, _ := request("POST", "/admin/feeds?symbol=USDT", "secret"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 adding USDT twice, got %d", code)
+	}
+	code, feeds = request("DELETE", "/admin/feeds?symbol=BTC", "secret")
+	if code != http.StatusOK || len(feeds.Feeds) != 2 || feeds.Feeds[0].Symbol != "ETH" {
+		t.Errorf("removing BTC: got %d %+v", code, feeds)
+	}
+	if code, _ := request("POST", "/admin/update?symbol=BTC", "secret"); code != http.StatusNotFound {
+		t.Errorf("expected 404 forcing update of removed BTC, got %d", code)
+	}
+	code, feeds = request("POST", "/admin/policy?heartbeatSeconds=3600", "secret")
+	if code != http.StatusOK || feeds.DeviationPermille != 10 || feeds.HeartbeatSeconds != 3600 {
+		t.Errorf("setting heartbeat: got %d %+v", code, feeds)
+	}
+	if feeder.config.Heartbeat != time.Hour {
+		t.Errorf("expected heartbeat of 1h, got %v", feeder.config.Heartbeat)
+	}
+}
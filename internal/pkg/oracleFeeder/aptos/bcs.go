@@ -0,0 +1,65 @@
+package aptos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+)
+
+// Serializer writes values in Binary Canonical Serialization (BCS), the encoding of
+// transactions and entry function arguments on Move chains.
+type Serializer struct {
+	buf bytes.Buffer
+}
+
+// Bytes returns the serialized data.
+func (s *Serializer) Bytes() []byte {
+	return s.buf.Bytes()
+}
+
+// U8 writes a single byte.
+func (s *Serializer) U8(v uint8) {
+	s.buf.WriteByte(v)
+}
+
+// U64 writes @v as 8 bytes little endian.
+func (s *Serializer) U64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	s.buf.Write(b[:])
+}
+
+// U128 writes @v, which must fit into 128 bits, as 16 bytes little endian.
+func (s *Serializer) U128(v *big.Int) {
+	var b [16]byte
+	be := v.Bytes()
+	for i := range be {
+		b[i] = be[len(be)-1-i]
+	}
+	s.buf.Write(b[:])
+}
+
+// Uleb128 writes the length prefix of sequences and enum variant indices.
+func (s *Serializer) Uleb128(v uint32) {
+	for v >= 0x80 {
+		s.buf.WriteByte(byte(v&0x7f) | 0x80)
+		v >>= 7
+	}
+	s.buf.WriteByte(byte(v))
+}
+
+// FixedBytes writes @b without length prefix, e.g. an account address.
+func (s *Serializer) FixedBytes(b []byte) {
+	s.buf.Write(b)
+}
+
+// ByteVector writes @b with its length prefix, i.e. as vector<u8>.
+func (s *Serializer) ByteVector(b []byte) {
+	s.Uleb128(uint32(len(b)))
+	s.buf.Write(b)
+}
+
+// String writes @v as a Move string, i.e. as its utf-8 bytes with length prefix.
+func (s *Serializer) String(v string) {
+	s.ByteVector([]byte(v))
+}
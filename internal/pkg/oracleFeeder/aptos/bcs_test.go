@@ -0,0 +1,34 @@
+package aptos
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestSerializer(t *testing.T) {
+	var s Serializer
+	s.Uleb128(300)
+	s.U64(1)
+	s.U128(big.NewInt(0x0102))
+	s.String("BTC/USD")
+	expected := []byte{
+		0xac, 0x02,
+		1, 0, 0, 0, 0, 0, 0, 0,
+		0x02, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		7, 'B', 'T', 'C', '/', 'U', 'S', 'D',
+	}
+	if !bytes.Equal(s.Bytes(), expected) {
+		t.Errorf("expected %x, got %x", expected, s.Bytes())
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	a, err := ParseAddress("0x1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != "0x0000000000000000000000000000000000000000000000000000000000000001" {
+		t.Errorf("unexpected address %s", a)
+	}
+}
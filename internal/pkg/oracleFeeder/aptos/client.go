@@ -0,0 +1,113 @@
+package aptos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const signedTransactionContentType = "application/x.aptos.signed_transaction+bcs"
+
+// Client talks to the REST API of an Aptos full node.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the REST API at @url, e.g. https://fullnode.mainnet.aptoslabs.com/v1.
+func NewClient(url string) *Client {
+	return &Client{
+		url:        strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request to @path and unmarshals the json response into @response.
+func (c *Client) do(method string, path string, contentType string, body io.Reader, response interface{}) error {
+	req, err := http.NewRequest(method, c.url+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	contents, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("aptos node returned status %d: %s", resp.StatusCode, string(contents))
+	}
+	return json.Unmarshal(contents, response)
+}
+
+// ChainID returns the id of the chain the node belongs to.
+func (c *Client) ChainID() (uint8, error) {
+	var resp struct {
+		ChainID uint8 `json:"chain_id"`
+	}
+	err := c.do(http.MethodGet, "", "", nil, &resp)
+	return resp.ChainID, err
+}
+
+// SequenceNumber returns the sequence number of the next transaction of @address.
+func (c *Client) SequenceNumber(address Address) (uint64, error) {
+	var resp struct {
+		SequenceNumber string `json:"sequence_number"`
+	}
+	err := c.do(http.MethodGet, "/accounts/"+address.String(), "", nil, &resp)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(resp.SequenceNumber, 10, 64)
+}
+
+// GasUnitPrice returns the gas unit price estimated to get a transaction included.
+func (c *Client) GasUnitPrice() (uint64, error) {
+	var resp struct {
+		GasEstimate uint64 `json:"gas_estimate"`
+	}
+	err := c.do(http.MethodGet, "/estimate_gas_price", "", nil, &resp)
+	return resp.GasEstimate, err
+}
+
+// Simulate executes the BCS encoded transaction @signedTx, which must carry a zero signature,
+// and returns the gas it used.
+func (c *Client) Simulate(signedTx []byte) (uint64, error) {
+	var resp []struct {
+		Success  bool   `json:"success"`
+		VMStatus string `json:"vm_status"`
+		GasUsed  string `json:"gas_used"`
+	}
+	err := c.do(http.MethodPost, "/transactions/simulate", signedTransactionContentType, bytes.NewReader(signedTx), &resp)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp) != 1 {
+		return 0, fmt.Errorf("expected one simulated transaction, got %d", len(resp))
+	}
+	if !resp[0].Success {
+		return 0, fmt.Errorf("simulation failed: %s", resp[0].VMStatus)
+	}
+	return strconv.ParseUint(resp[0].GasUsed, 10, 64)
+}
+
+// Submit submits the BCS encoded transaction @signedTx and returns its hash.
+func (c *Client) Submit(signedTx []byte) (string, error) {
+	var resp struct {
+		Hash string `json:"hash"`
+	}
+	err := c.do(http.MethodPost, "/transactions", signedTransactionContentType, bytes.NewReader(signedTx), &resp)
+	return resp.Hash, err
+}
@@ -0,0 +1,112 @@
+package aptos
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// expiration is the time after which a submitted transaction is discarded by the chain.
+const expiration = 60 * time.Second
+
+// Oracle writes quotations into a DIA oracle module on Aptos. It implements oracleFeeder.Updater.
+// The module exposes the entry function set_value(account: &signer, key: String, value: u128, timestamp: u64).
+type Oracle struct {
+	client     *Client
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	sender     Address
+	chainID    uint8
+	module     Address
+	moduleName string
+	// maxGasAmount bounds the gas units of a single update. It is also the gas limit used
+	// when simulating the update.
+	maxGasAmount uint64
+	// sequenceNumber of the next transaction. It is reset after failures so that it is
+	// read from the chain again.
+	sequenceNumber *uint64
+	mu             sync.Mutex
+}
+
+// NewOracle returns an oracle calling @moduleName at @module from the account @sender.
+func NewOracle(client *Client, privateKey ed25519.PrivateKey, sender Address, module Address, moduleName string, maxGasAmount uint64) (*Oracle, error) {
+	chainID, err := client.ChainID()
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle{
+		client:       client,
+		privateKey:   privateKey,
+		publicKey:    privateKey.Public().(ed25519.PublicKey),
+		sender:       sender,
+		chainID:      chainID,
+		module:       module,
+		moduleName:   moduleName,
+		maxGasAmount: maxGasAmount,
+	}, nil
+}
+
+// Update sets the price of @quotation with 8 decimals under its key, e.g. BTC/USD or ETH/EUR.
+func (o *Oracle) Update(quotation *models.Quotation) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := oracleFeeder.Key(quotation)
+	value := big.NewInt(int64(quotation.Price * 100000000))
+	var keyArg, valueArg, timestampArg Serializer
+	keyArg.String(key)
+	valueArg.U128(value)
+	timestampArg.U64(uint64(time.Now().Unix()))
+
+	if o.sequenceNumber == nil {
+		seq, err := o.client.SequenceNumber(o.sender)
+		if err != nil {
+			return err
+		}
+		o.sequenceNumber = &seq
+	}
+	gasUnitPrice, err := o.client.GasUnitPrice()
+	if err != nil {
+		return err
+	}
+	tx := &RawTransaction{
+		Sender:         o.sender,
+		SequenceNumber: *o.sequenceNumber,
+		Payload: EntryFunction{
+			Module:   o.module,
+			Name:     o.moduleName,
+			Function: "set_value",
+			Args:     [][]byte{keyArg.Bytes(), valueArg.Bytes(), timestampArg.Bytes()},
+		},
+		MaxGasAmount: o.maxGasAmount,
+		GasUnitPrice: gasUnitPrice,
+		Expiration:   uint64(time.Now().Add(expiration).Unix()),
+		ChainID:      o.chainID,
+	}
+
+	gasUsed, err := o.client.Simulate(SignedTransaction(tx, o.publicKey, nil))
+	if err != nil {
+		o.sequenceNumber = nil
+		return fmt.Errorf("simulate update: %v", err)
+	}
+	// Leave headroom for state changes between simulation and execution.
+	tx.MaxGasAmount = gasUsed * 3 / 2
+	if tx.MaxGasAmount > o.maxGasAmount {
+		tx.MaxGasAmount = o.maxGasAmount
+	}
+
+	hash, err := o.client.Submit(SignedTransaction(tx, o.publicKey, o.privateKey))
+	if err != nil {
+		o.sequenceNumber = nil
+		return err
+	}
+	*o.sequenceNumber++
+	log.WithFields(log.Fields{"chain": "aptos", "key": key, "value": value, "gasUsed": gasUsed, "maxGas": tx.MaxGasAmount, "gasPrice": gasUnitPrice, "txHash": hash}).Info("oracle updated")
+	return nil
+}
@@ -0,0 +1,131 @@
+package aptos
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// payloadEntryFunction is the variant index of EntryFunction in TransactionPayload.
+	payloadEntryFunction = 2
+	// authenticatorEd25519 is the variant index of Ed25519 in TransactionAuthenticator.
+	authenticatorEd25519 = 0
+	// schemeEd25519 is appended to the public key when deriving the authentication key.
+	schemeEd25519 = 0
+)
+
+// Address is a 32 byte account address.
+type Address [32]byte
+
+// ParseAddress parses a hex address with or without 0x prefix. Short addresses such as 0x1
+// are padded with leading zeros.
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	s = strings.TrimPrefix(s, "0x")
+	if len(s) > 64 {
+		return a, fmt.Errorf("address %s exceeds 32 bytes", s)
+	}
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return a, err
+	}
+	copy(a[32-len(b):], b)
+	return a, nil
+}
+
+// String returns the address in hex with 0x prefix.
+func (a Address) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// AddressFromPublicKey returns the address of an account which never rotated its ed25519 key.
+func AddressFromPublicKey(publicKey ed25519.PublicKey) Address {
+	return sha3.Sum256(append(append([]byte{}, publicKey...), schemeEd25519))
+}
+
+// ParsePrivateKey parses a hex encoded ed25519 private key as exported by the aptos cli.
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "ed25519-priv-"), "0x")
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	}
+	return nil, fmt.Errorf("invalid private key length %d", len(b))
+}
+
+// EntryFunction is a call of a public entry function of a Move module. Args are the BCS
+// encoded arguments.
+type EntryFunction struct {
+	Module   Address
+	Name     string
+	Function string
+	Args     [][]byte
+}
+
+// RawTransaction is an unsigned transaction calling an entry function.
+type RawTransaction struct {
+	Sender         Address
+	SequenceNumber uint64
+	Payload        EntryFunction
+	MaxGasAmount   uint64
+	GasUnitPrice   uint64
+	Expiration     uint64
+	ChainID        uint8
+}
+
+// Serialize returns the BCS encoding of the transaction.
+func (tx *RawTransaction) Serialize() []byte {
+	var s Serializer
+	s.FixedBytes(tx.Sender[:])
+	s.U64(tx.SequenceNumber)
+	s.Uleb128(payloadEntryFunction)
+	s.FixedBytes(tx.Payload.Module[:])
+	s.String(tx.Payload.Name)
+	s.String(tx.Payload.Function)
+	// No type arguments.
+	s.Uleb128(0)
+	s.Uleb128(uint32(len(tx.Payload.Args)))
+	for _, arg := range tx.Payload.Args {
+		s.ByteVector(arg)
+	}
+	s.U64(tx.MaxGasAmount)
+	s.U64(tx.GasUnitPrice)
+	s.U64(tx.Expiration)
+	s.U8(tx.ChainID)
+	return s.Bytes()
+}
+
+// SigningMessage returns the message signed by the sender, i.e. the serialized transaction
+// prefixed with the hash of its type name.
+func (tx *RawTransaction) SigningMessage() []byte {
+	prefix := sha3.Sum256([]byte("APTOS::RawTransaction"))
+	return append(prefix[:], tx.Serialize()...)
+}
+
+// SignedTransaction returns the BCS encoding of @tx along with an ed25519 authenticator.
+// A nil @privateKey yields an all zero signature as required for simulations.
+func SignedTransaction(tx *RawTransaction, publicKey ed25519.PublicKey, privateKey ed25519.PrivateKey) []byte {
+	signature := make([]byte, ed25519.SignatureSize)
+	if privateKey != nil {
+		signature = ed25519.Sign(privateKey, tx.SigningMessage())
+	}
+	var s Serializer
+	s.FixedBytes(tx.Serialize())
+	s.Uleb128(authenticatorEd25519)
+	s.ByteVector(publicKey)
+	s.ByteVector(signature)
+	return s.Bytes()
+}
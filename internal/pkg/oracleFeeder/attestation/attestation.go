@@ -0,0 +1,134 @@
+// Package attestation publishes arbitrary key/value data, e.g. bridge state roots or supply
+// attestations, into oracle contracts. Values come from sources and are signed off-chain by an
+// attestor before being written, so that new attestation types only need a Source.
+package attestation
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	log "github.com/sirupsen/logrus"
+)
+
+var attestationsTotal = metrics.NewCounter("oraclefeeder", "attestations", "Published attestations by source and result.", "source", "result")
+
+// Attestation is a value published under a key.
+type Attestation struct {
+	Key   string
+	Value []byte
+	// Timestamp is the time the value refers to. Contracts reject attestations which aren't
+	// newer than the stored one.
+	Timestamp time.Time
+}
+
+// Source provides the current attestations of one type.
+type Source interface {
+	Name() string
+	Attestations() ([]Attestation, error)
+}
+
+// Publisher signs and writes an attestation on-chain.
+type Publisher interface {
+	Publish(a Attestation) error
+}
+
+// Feeder periodically collects the attestations of all sources and publishes those which
+// changed or whose last publication is older than the heartbeat.
+type Feeder struct {
+	sources   []Source
+	publisher Publisher
+	frequency time.Duration
+	heartbeat time.Duration
+	// last holds the last published attestation of each key.
+	last map[string]Attestation
+	// published holds the time of the last publication of each key.
+	published map[string]time.Time
+}
+
+// NewFeeder returns a feeder publishing the attestations of @sources through @publisher every
+// @frequency. A zero @heartbeat disables forced publications of unchanged values.
+func NewFeeder(sources []Source, publisher Publisher, frequency time.Duration, heartbeat time.Duration) *Feeder {
+	return &Feeder{
+		sources:   sources,
+		publisher: publisher,
+		frequency: frequency,
+		heartbeat: heartbeat,
+		last:      make(map[string]Attestation),
+		published: make(map[string]time.Time),
+	}
+}
+
+// Checks implements selftest.Checker. It verifies that all sources provide attestations and
+// adds the checks of the publisher.
+func (f *Feeder) Checks() []selftest.Check {
+	var checks []selftest.Check
+	for _, source := range f.sources {
+		source := source
+		checks = append(checks, selftest.Check{Name: "source " + source.Name(), Run: func() error {
+			_, err := source.Attestations()
+			return err
+		}})
+	}
+	return append(checks, selftest.Collect("", f.publisher)...)
+}
+
+// Run publishes the attestations of all sources every frequency. It blocks forever.
+func (f *Feeder) Run() {
+	f.Update(time.Now())
+	ticker := time.NewTicker(f.frequency)
+	for now := range ticker.C {
+		f.Update(now)
+	}
+}
+
+// Update collects the attestations of all sources and publishes them if necessary. Failures
+// of single sources or attestations are logged and retried in the next cycle.
+func (f *Feeder) Update(now time.Time) {
+	for _, source := range f.sources {
+		attestations, err := source.Attestations()
+		if err != nil {
+			log.Errorf("source %s: %v", source.Name(), err)
+			attestationsTotal.Inc(source.Name(), "source_error")
+			continue
+		}
+		for _, a := range attestations {
+			if !f.needsUpdate(a, now) {
+				continue
+			}
+			err = f.publisher.Publish(a)
+			if errors.Is(err, oracleFeeder.ErrSkipped) {
+				log.Warnf("publishing %s of source %s: %v", a.Key, source.Name(), err)
+				attestationsTotal.Inc(source.Name(), "skipped")
+				continue
+			}
+			if err != nil {
+				log.Errorf("publishing %s of source %s: %v", a.Key, source.Name(), err)
+				attestationsTotal.Inc(source.Name(), "error")
+				continue
+			}
+			f.last[a.Key] = a
+			f.published[a.Key] = now
+			attestationsTotal.Inc(source.Name(), "success")
+		}
+	}
+}
+
+// needsUpdate returns true if @a is newer than the last published attestation of its key and
+// either its value changed or the heartbeat elapsed.
+func (f *Feeder) needsUpdate(a Attestation, now time.Time) bool {
+	last, ok := f.last[a.Key]
+	if !ok {
+		return true
+	}
+	if !a.Timestamp.After(last.Timestamp) {
+		return false
+	}
+	if !bytes.Equal(a.Value, last.Value) {
+		return true
+	}
+	return f.heartbeat > 0 && now.Sub(f.published[a.Key]) >= f.heartbeat
+}
@@ -0,0 +1,75 @@
+package attestation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	attestations []Attestation
+	err          error
+}
+
+func (s *fakeSource) Name() string {
+	return "fake"
+}
+
+func (s *fakeSource) Attestations() ([]Attestation, error) {
+	return s.attestations, s.err
+}
+
+type fakePublisher struct {
+	published []Attestation
+	err       error
+}
+
+func (p *fakePublisher) Publish(a Attestation) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, a)
+	return nil
+}
+
+func TestFeederUpdate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	source := &fakeSource{}
+	publisher := &fakePublisher{}
+	f := NewFeeder([]Source{source}, publisher, time.Minute, time.Hour)
+
+	steps := []struct {
+		attestation       Attestation
+		now               time.Time
+		publishErr        error
+		expectedPublished int
+	}{
+		// first attestation of a key
+		{Attestation{"A", []byte{1}, now}, now, nil, 1},
+		// same value, heartbeat not elapsed
+		{Attestation{"A", []byte{1}, now.Add(time.Minute)}, now.Add(time.Minute), nil, 1},
+		// changed value
+		{Attestation{"A", []byte{2}, now.Add(2 * time.Minute)}, now.Add(2 * time.Minute), nil, 2},
+		// changed value, but not newer than the published one
+		{Attestation{"A", []byte{3}, now.Add(2 * time.Minute)}, now.Add(3 * time.Minute), nil, 2},
+		// failed publication is retried in the next cycle
+		{Attestation{"A", []byte{4}, now.Add(4 * time.Minute)}, now.Add(4 * time.Minute), errors.New("reverted"), 2},
+		{Attestation{"A", []byte{4}, now.Add(4 * time.Minute)}, now.Add(5 * time.Minute), nil, 3},
+		// same value after the heartbeat
+		{Attestation{"A", []byte{4}, now.Add(2 * time.Hour)}, now.Add(2 * time.Hour), nil, 4},
+	}
+	for i, s := range steps {
+		source.attestations = []Attestation{s.attestation}
+		publisher.err = s.publishErr
+		f.Update(s.now)
+		if len(publisher.published) != s.expectedPublished {
+			t.Errorf("step %d: expected %d publications, got %d", i, s.expectedPublished, len(publisher.published))
+		}
+	}
+
+	source.err = errors.New("unreachable")
+	f.Update(now.Add(3 * time.Hour))
+	if len(publisher.published) != 4 {
+		t.Errorf("published despite source error")
+	}
+}
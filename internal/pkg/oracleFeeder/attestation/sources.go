@@ -0,0 +1,124 @@
+package attestation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaAggregatorV3Adapter"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// supplyDecimals are the decimals of supplies in supply attestations.
+const supplyDecimals = 18
+
+// SupplySource attests the supplies of assets as served by the DIA API under the keys
+// SUPPLY/SYMBOL. Values are the total and the circulating supply with 18 decimals, each
+// encoded as uint256.
+type SupplySource struct {
+	Symbols []string
+}
+
+// Name implements Source.
+func (s *SupplySource) Name() string {
+	return "supply"
+}
+
+// Attestations implements Source.
+func (s *SupplySource) Attestations() ([]Attestation, error) {
+	var attestations []Attestation
+	for _, symbol := range s.Symbols {
+		supply, err := getSupplyFromDia(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("supply of %s: %v", symbol, err)
+		}
+		total, err := diaAggregatorV3Adapter.ScaleAnswer(supply.Supply, supplyDecimals)
+		if err != nil {
+			return nil, err
+		}
+		circulating, err := diaAggregatorV3Adapter.ScaleAnswer(supply.CirculatingSupply, supplyDecimals)
+		if err != nil {
+			return nil, err
+		}
+		attestations = append(attestations, Attestation{
+			Key:       "SUPPLY/" + strings.ToUpper(symbol),
+			Value:     append(common.LeftPadBytes(total.Bytes(), 32), common.LeftPadBytes(circulating.Bytes(), 32)...),
+			Timestamp: supply.Time,
+		})
+	}
+	return attestations, nil
+}
+
+func getSupplyFromDia(symbol string) (*dia.Supply, error) {
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/supply/" + strings.ToUpper(symbol))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Error on dia api with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var supply dia.Supply
+	err = json.Unmarshal(contents, &supply)
+	if err != nil {
+		return nil, err
+	}
+	return &supply, nil
+}
+
+// StateRootSource attests the state root of a confirmed block of an EVM chain under the key
+// STATEROOT/CHAIN, e.g. for bridges verifying proofs against it. Values are the block number
+// encoded as uint256 followed by the 32 byte state root.
+type StateRootSource struct {
+	chain         string
+	client        *ethclient.Client
+	confirmations uint64
+}
+
+// NewStateRootSource connects to @blockchainNode of @chain. Blocks are attested once they
+// have @confirmations blocks on top.
+func NewStateRootSource(chain string, blockchainNode string, confirmations uint64) (*StateRootSource, error) {
+	client, err := ethclient.Dial(blockchainNode)
+	if err != nil {
+		return nil, err
+	}
+	return &StateRootSource{chain: chain, client: client, confirmations: confirmations}, nil
+}
+
+// Name implements Source.
+func (s *StateRootSource) Name() string {
+	return "stateroot-" + s.chain
+}
+
+// Attestations implements Source.
+func (s *StateRootSource) Attestations() ([]Attestation, error) {
+	ctx := context.Background()
+	latest, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	number := new(big.Int).Sub(latest.Number, new(big.Int).SetUint64(s.confirmations))
+	if number.Sign() < 0 {
+		return nil, nil
+	}
+	header, err := s.client.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return []Attestation{{
+		Key:       "STATEROOT/" + strings.ToUpper(s.chain),
+		Value:     append(common.LeftPadBytes(header.Number.Bytes(), 32), header.Root.Bytes()...),
+		Timestamp: time.Unix(int64(header.Time), 0),
+	}}, nil
+}
@@ -0,0 +1,221 @@
+package oracleFeeder
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Actions of feeder decisions.
+const (
+	// ActionUpdated is an update written to the oracle.
+	ActionUpdated = "updated"
+	// ActionSkippedDeviation is a check in which neither the deviation nor the heartbeat
+	// condition was met.
+	ActionSkippedDeviation = "skipped_deviation"
+	// ActionSkippedStale is a check of a fiat feed whose source published no new rate since the
+	// last update, e.g. because FX markets are closed on weekends and holidays.
+	ActionSkippedStale = "skipped_stale"
+	// ActionSkippedGas is an update the updater didn't send, e.g. because its gas couldn't be
+	// estimated.
+	ActionSkippedGas = "skipped_gas"
+	// ActionSourceError is a check in which the price couldn't be retrieved from the DIA API.
+	ActionSourceError = "source_error"
+	// ActionFailed is an update which was sent but failed.
+	ActionFailed = "failed"
+)
+
+// Decision is the outcome of one check of a symbol along with the inputs that led to it.
+type Decision struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Action string    `json:"action"`
+	// OldPrice is the last price written to the oracle, NewPrice the candidate.
+	OldPrice float64 `json:"oldPrice"`
+	NewPrice float64 `json:"newPrice"`
+	// Deviation is the deviation of NewPrice from OldPrice in permille.
+	Deviation         float64   `json:"deviation"`
+	DeviationPermille int       `json:"deviationPermille"`
+	AbsoluteDeviation float64   `json:"absoluteDeviation,omitempty"`
+	LastUpdate        time.Time `json:"lastUpdate"`
+	HeartbeatSeconds  int64     `json:"heartbeatSeconds,omitempty"`
+	TWAPWindowSeconds int64     `json:"twapWindowSeconds,omitempty"`
+	Error             string    `json:"error,omitempty"`
+}
+
+// Explanation summarizes the decisions of a symbol in a time range.
+type Explanation struct {
+	Symbol    string         `json:"symbol"`
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	Summary   string         `json:"summary"`
+	Actions   map[string]int `json:"actions"`
+	Decisions []Decision     `json:"decisions"`
+}
+
+// Explain summarizes why @symbol was or wasn't updated between @from and @to given its
+// @decisions in that range.
+func Explain(symbol string, from time.Time, to time.Time, decisions []Decision) Explanation {
+	e := Explanation{Symbol: symbol, From: from, To: to, Actions: make(map[string]int), Decisions: decisions}
+	var lastUpdated, lastError, lastStale *Decision
+	var maxDeviation float64
+	for i := range decisions {
+		d := &decisions[i]
+		e.Actions[d.Action]++
+		switch d.Action {
+		case ActionUpdated:
+			lastUpdated = d
+		case ActionSkippedDeviation:
+			maxDeviation = math.Max(maxDeviation, d.Deviation)
+		case ActionSkippedStale:
+			lastStale = d
+		case ActionSkippedGas, ActionSourceError, ActionFailed:
+			lastError = d
+		}
+	}
+
+	switch {
+	case len(decisions) == 0:
+		e.Summary = fmt.Sprintf("no decisions recorded for %s; the feeder wasn't running or doesn't feed the symbol", symbol)
+	case lastUpdated != nil:
+		e.Summary = fmt.Sprintf("%s was updated %d time(s), last at %s to %v", symbol, e.Actions[ActionUpdated], lastUpdated.Time.Format(time.RFC3339), lastUpdated.NewPrice)
+	case lastError != nil:
+		e.Summary = fmt.Sprintf("%s wasn't updated; last problem at %s: %s: %s", symbol, lastError.Time.Format(time.RFC3339), lastError.Action, lastError.Error)
+	case lastStale != nil:
+		e.Summary = fmt.Sprintf("%s wasn't updated; its source published no new rate until %s, e.g. because FX markets were closed", symbol, lastStale.Time.Format(time.RFC3339))
+	default:
+		last := decisions[len(decisions)-1]
+		e.Summary = fmt.Sprintf("%s wasn't updated; the largest deviation was %.3f permille, below the threshold of %d permille, and the heartbeat wasn't due", symbol, maxDeviation, last.DeviationPermille)
+	}
+	return e
+}
+
+// DecisionLog records the decisions of a feeder for a retention period. If it is backed by a
+// file, decisions are appended to it as json lines so that they survive restarts.
+type DecisionLog struct {
+	retention time.Duration
+	file      *os.File
+	decisions []Decision
+	mu        sync.Mutex
+}
+
+// NewDecisionLog returns a log keeping decisions for @retention. A non-empty @path is loaded
+// and compacted to the retained decisions, and all further decisions are appended to it.
+func NewDecisionLog(path string, retention time.Duration) (*DecisionLog, error) {
+	l := &DecisionLog{retention: retention}
+	if path == "" {
+		return l, nil
+	}
+	cutoff := time.Now().Add(-retention)
+	file, err := os.Open(path)
+	if err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var d Decision
+			if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+				log.Warnf("skipping invalid line of decision log %s: %v", path, err)
+				continue
+			}
+			if d.Time.After(cutoff) {
+				l.decisions = append(l.decisions, d)
+			}
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	l.file, err = os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range l.decisions {
+		if err := l.write(d); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Record adds @d to the log and drops decisions older than the retention.
+func (l *DecisionLog) Record(d Decision) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decisions = append(l.decisions, d)
+	cutoff := d.Time.Add(-l.retention)
+	i := 0
+	for i < len(l.decisions) && !l.decisions[i].Time.After(cutoff) {
+		i++
+	}
+	l.decisions = l.decisions[i:]
+	if l.file != nil {
+		if err := l.write(d); err != nil {
+			log.Errorf("writing decision log: %v", err)
+		}
+	}
+}
+
+func (l *DecisionLog) write(d Decision) error {
+	line, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+// Query returns the decisions of @symbol between @from and @to. An empty @symbol matches all
+// symbols.
+func (l *DecisionLog) Query(symbol string, from time.Time, to time.Time) []Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	decisions := []Decision{}
+	for _, d := range l.decisions {
+		if (symbol == "" || d.Symbol == symbol) && !d.Time.Before(from) && !d.Time.After(to) {
+			decisions = append(decisions, d)
+		}
+	}
+	return decisions
+}
+
+// Handler serves the explanation of the decisions of a symbol, e.g.
+// /decisions?symbol=BTC&starttime=1700000000&endtime=1700003600. Times are unix seconds and
+// default to the last hour.
+func (l *DecisionLog) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		symbol := query.Get("symbol")
+		if symbol == "" {
+			http.Error(w, "symbol is required", http.StatusBadRequest)
+			return
+		}
+		to := time.Now()
+		from := to.Add(-time.Hour)
+		for _, p := range []struct {
+			name string
+			t    *time.Time
+		}{{"starttime", &from}, {"endtime", &to}} {
+			if s := query.Get(p.name); s != "" {
+				unix, err := strconv.ParseInt(s, 10, 64)
+				if err != nil {
+					http.Error(w, "invalid "+p.name, http.StatusBadRequest)
+					return
+				}
+				*p.t = time.Unix(unix, 0)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Explain(symbol, from, to, l.Query(symbol, from, to)))
+	})
+}
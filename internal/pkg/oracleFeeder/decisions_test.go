@@ -0,0 +1,72 @@
+package oracleFeeder
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecisionLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "decisions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "decisions.log")
+
+	now := time.Now()
+	l, err := NewDecisionLog(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Record(Decision{Time: now.Add(-2 * time.Hour), Symbol: "BTC", Action: ActionUpdated})
+	l.Record(Decision{Time: now.Add(-time.Minute), Symbol: "BTC", Action: ActionSkippedDeviation, Deviation: 3, DeviationPermille: 10})
+	l.Record(Decision{Time: now, Symbol: "ETH", Action: ActionUpdated})
+
+	// Decisions beyond the retention are dropped, also when the file is loaded again.
+	for _, log := range []*DecisionLog{l, reload(t, path)} {
+		if decisions := log.Query("", now.Add(-3*time.Hour), now); len(decisions) != 2 {
+			t.Errorf("expected 2 retained decisions, got %d", len(decisions))
+		}
+		if decisions := log.Query("BTC", now.Add(-3*time.Hour), now); len(decisions) != 1 || decisions[0].Deviation != 3 {
+			t.Errorf("unexpected decisions of BTC: %+v", decisions)
+		}
+	}
+}
+
+func reload(t *testing.T, path string) *DecisionLog {
+	l, err := NewDecisionLog(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestExplain(t *testing.T) {
+	now := time.Now()
+	skipped := Decision{Time: now, Symbol: "BTC", Action: ActionSkippedDeviation, Deviation: 4.5, DeviationPermille: 10}
+	failed := Decision{Time: now, Symbol: "BTC", Action: ActionSkippedGas, Error: errors.New("execution reverted").Error()}
+	updated := Decision{Time: now, Symbol: "BTC", Action: ActionUpdated, NewPrice: 100}
+	stale := Decision{Time: now, Symbol: "BTC", Action: ActionSkippedStale}
+
+	cases := []struct {
+		decisions []Decision
+		contains  string
+	}{
+		{nil, "no decisions recorded"},
+		{[]Decision{skipped, skipped}, "4.500 permille, below the threshold of 10 permille"},
+		{[]Decision{skipped, failed}, "skipped_gas: execution reverted"},
+		{[]Decision{failed, updated, skipped}, "updated 1 time(s)"},
+		{[]Decision{skipped, stale}, "no new rate"},
+	}
+	for i, c := range cases {
+		e := Explain("BTC", now.Add(-time.Hour), now, c.decisions)
+		if !strings.Contains(e.Summary, c.contains) {
+			t.Errorf("case %d: expected summary containing %q, got %q", i, c.contains, e.Summary)
+		}
+	}
+}
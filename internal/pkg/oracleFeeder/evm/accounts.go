@@ -0,0 +1,127 @@
+package evm
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// accountPause is the time a drained or stuck account is skipped.
+	accountPause = 10 * time.Minute
+	// stuckAfter is the time after which pending transactions of an account are considered
+	// stuck if none of them was mined.
+	stuckAfter = 5 * time.Minute
+)
+
+var (
+	accountPaused = metrics.NewGauge("oraclefeeder", "account_paused", "1 if a feeder wallet is skipped because it is drained or stuck.", "account")
+
+	// errAccountUnavailable is returned if a transaction couldn't be sent from an account, but
+	// may succeed from another one.
+	errAccountUnavailable = errors.New("wallet unavailable")
+)
+
+// Wallet is a json key of a feeder wallet along with its password.
+type Wallet struct {
+	Key      string
+	Password string
+}
+
+// ReadWallets reads the wallets from the secrets file at @path, which holds the json key and
+// the password of each wallet on consecutive lines.
+func ReadWallets(path string) ([]Wallet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	// json keys are longer than the default limit of bufio.Scanner on some systems.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || len(lines)%2 != 0 {
+		return nil, fmt.Errorf("secrets file should have a key and a password line per wallet, got %d lines", len(lines))
+	}
+	var wallets []Wallet
+	for i := 0; i < len(lines); i += 2 {
+		wallets = append(wallets, Wallet{Key: lines[i], Password: lines[i+1]})
+	}
+	return wallets, nil
+}
+
+// account is a wallet sending transactions on a chain.
+type account struct {
+	auth *bind.TransactOpts
+	// nonce of the next transaction. It is reset after failures so that it is
+	// read from the chain again.
+	nonce *uint64
+	// confirmed is the last seen nonce of the latest block and confirmedAt the time it was
+	// first seen.
+	confirmed   uint64
+	confirmedAt time.Time
+	// pausedUntil is guarded by the mutex of the chain.
+	pausedUntil time.Time
+	mu          sync.Mutex
+}
+
+// candidates returns the accounts which aren't paused at @now, starting with the next one in
+// round-robin order.
+func (c *Chain) candidates(now time.Time) []*account {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	start := c.next
+	c.next = (c.next + 1) % len(c.accounts)
+	var candidates []*account
+	for i := range c.accounts {
+		a := c.accounts[(start+i)%len(c.accounts)]
+		if now.Before(a.pausedUntil) {
+			continue
+		}
+		accountPaused.Set(0, a.auth.From.Hex())
+		candidates = append(candidates, a)
+	}
+	return candidates
+}
+
+// pause skips @a for accountPause after @now.
+func (c *Chain) pause(a *account, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a.pausedUntil = now.Add(accountPause)
+	accountPaused.Set(1, a.auth.From.Hex())
+	log.Warnf("pausing wallet %s until %v", a.auth.From.Hex(), a.pausedUntil)
+}
+
+// stuck returns true if @a has pending transactions and none of them was mined for stuckAfter.
+// It must be called with the mutex of @a held.
+func (c *Chain) stuck(ctx context.Context, a *account, now time.Time) bool {
+	confirmed, err := c.client.NonceAt(ctx, a.auth.From, nil)
+	if err != nil {
+		log.Warnf("reading nonce of %s: %v", a.auth.From.Hex(), err)
+		return false
+	}
+	// The clock only runs while transactions are pending and none of them gets mined.
+	if confirmed != a.confirmed || *a.nonce <= confirmed || a.confirmedAt.IsZero() {
+		a.confirmed = confirmed
+		a.confirmedAt = now
+		return false
+	}
+	return now.Sub(a.confirmedAt) > stuckAfter
+}
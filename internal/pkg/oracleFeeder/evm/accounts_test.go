@@ -0,0 +1,57 @@
+package evm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestReadWallets(t *testing.T) {
+	file, err := ioutil.TempFile("", "oracle_keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("{\"address\":\"a\"}\npassword a\n{\"address\":\"b\"}\npassword b\n")
+	file.Close()
+
+	wallets, err := ReadWallets(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wallets) != 2 || wallets[1].Password != "password b" {
+		t.Errorf("unexpected wallets %+v", wallets)
+	}
+
+	ioutil.WriteFile(file.Name(), []byte("{\"address\":\"a\"}\n"), 0600)
+	if _, err := ReadWallets(file.Name()); err == nil {
+		t.Errorf("expected error for missing password")
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	var accounts []*account
+	for i := 0; i < 3; i++ {
+		accounts = append(accounts, &account{auth: &bind.TransactOpts{From: common.BigToAddress(common.Big1)}})
+	}
+	c := &Chain{accounts: accounts}
+	now := time.Now()
+
+	first := c.candidates(now)
+	second := c.candidates(now)
+	if len(first) != 3 || first[0] != accounts[0] || second[0] != accounts[1] {
+		t.Errorf("expected round-robin order")
+	}
+
+	c.pause(accounts[2], now)
+	if candidates := c.candidates(now); len(candidates) != 2 || candidates[0] != accounts[0] {
+		t.Errorf("expected the paused account to be skipped")
+	}
+	if candidates := c.candidates(now.Add(accountPause + time.Second)); len(candidates) != 3 {
+		t.Errorf("expected the account to be available after the pause")
+	}
+}
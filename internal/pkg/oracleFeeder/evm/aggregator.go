@@ -0,0 +1,111 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaAggregatorV3Adapter"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// adapter is a deployed DIAAggregatorV3Adapter holding the feed of one symbol.
+type adapter struct {
+	address  common.Address
+	contract *diaAggregatorV3Adapter.DIAAggregatorV3Adapter
+	decimals uint8
+}
+
+// Aggregator writes quotations into AggregatorV3Interface compatible adapter contracts, one per
+// symbol, so that protocols built against Chainlink's interface can read DIA prices. It
+// implements oracleFeeder.Updater.
+type Aggregator struct {
+	chain    *Chain
+	adapters map[string]adapter
+}
+
+// NewAggregator binds the adapter contracts in @adapters, which maps feeds to addresses, e.g.
+// BTC or ETH/EUR. The decimals of each feed are read from its contract.
+func NewAggregator(chain *Chain, adapters map[string]common.Address) (*Aggregator, error) {
+	a := &Aggregator{chain: chain, adapters: make(map[string]adapter)}
+	for feed, address := range adapters {
+		contract, err := diaAggregatorV3Adapter.NewDIAAggregatorV3Adapter(address, chain.client)
+		if err != nil {
+			return nil, err
+		}
+		decimals, err := contract.Decimals(&bind.CallOpts{})
+		if err != nil {
+			return nil, err
+		}
+		base, quote := oracleFeeder.ParseFeed(feed)
+		a.adapters[base+"/"+quote] = adapter{address: address, contract: contract, decimals: decimals}
+	}
+	return a, nil
+}
+
+// Checks implements selftest.Checker. Besides the chain it verifies that each adapter answers
+// with the expected interface version.
+func (a *Aggregator) Checks() []selftest.Check {
+	checks := a.chain.Checks()
+	var symbols []string
+	for symbol := range a.adapters {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		adapter := a.adapters[symbol]
+		checks = append(checks, selftest.Check{Name: "adapter " + symbol + " " + adapter.address.Hex(), Run: func() error {
+			version, err := adapter.contract.Version(&bind.CallOpts{})
+			if err != nil {
+				return err
+			}
+			if version.Int64() != 1 {
+				return fmt.Errorf("unsupported adapter version %s", version)
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// Update starts a new round with the price of @quotation in the adapter of its feed. Feeds
+// without an adapter are skipped.
+func (a *Aggregator) Update(quotation *models.Quotation) error {
+	key := oracleFeeder.Key(quotation)
+	adapter, ok := a.adapters[key]
+	if !ok {
+		log.Debugf("no aggregator adapter for %s", key)
+		return nil
+	}
+	answer, err := diaAggregatorV3Adapter.ScaleAnswer(quotation.Price, adapter.decimals)
+	if err != nil {
+		return err
+	}
+	updatedAt := big.NewInt(time.Now().Unix())
+
+	data, err := diaAggregatorV3Adapter.PackUpdateAnswer(answer, updatedAt)
+	if err != nil {
+		return err
+	}
+	tx, fee, err := a.chain.transact(adapter.address, data, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return adapter.contract.UpdateAnswer(opts, answer, updatedAt)
+	})
+	a.chain.recordUpdate(models.OracleUpdate{
+		Contract:  adapter.address.Hex(),
+		Key:       key,
+		Value:     quotation.Price,
+		Timestamp: time.Unix(updatedAt.Int64(), 0),
+	}, tx, err)
+	if err != nil {
+		return err
+	}
+	a.chain.txLogger(tx, fee).WithFields(log.Fields{"key": key, "answer": answer, "contract": adapter.address.Hex()}).Info("aggregator adapter updated")
+	return nil
+}
@@ -0,0 +1,69 @@
+package evm
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaAttestationOracle"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/attestation"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+// Attestor signs attestations with the attestor key and writes them into a DIAAttestationOracle
+// contract. It implements attestation.Publisher.
+type Attestor struct {
+	chain    *Chain
+	address  common.Address
+	contract *diaAttestationOracle.DIAAttestationOracle
+	key      *ecdsa.PrivateKey
+}
+
+// NewAttestor binds the DIAAttestationOracle contract at @address on @chain. Attestations are
+// signed with @key, which must belong to the attestor configured in the contract.
+func NewAttestor(chain *Chain, address common.Address, key *ecdsa.PrivateKey) (*Attestor, error) {
+	contract, err := diaAttestationOracle.NewDIAAttestationOracle(address, chain.client)
+	if err != nil {
+		return nil, err
+	}
+	return &Attestor{chain: chain, address: address, contract: contract, key: key}, nil
+}
+
+// Signer returns the address of the attestor key.
+func (a *Attestor) Signer() common.Address {
+	return crypto.PubkeyToAddress(a.key.PublicKey)
+}
+
+// Publish implements attestation.Publisher.
+func (a *Attestor) Publish(at attestation.Attestation) error {
+	timestamp := big.NewInt(at.Timestamp.Unix())
+	signature, err := diaAttestationOracle.Sign(a.address, at.Key, at.Value, timestamp, a.key)
+	if err != nil {
+		return err
+	}
+	data, err := diaAttestationOracle.PackSetValue(at.Key, at.Value, timestamp, signature)
+	if err != nil {
+		return err
+	}
+	tx, fee, err := a.chain.transact(a.address, data, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return a.contract.SetValue(opts, at.Key, at.Value, timestamp, signature)
+	})
+	if err != nil {
+		return err
+	}
+	a.chain.txLogger(tx, fee).WithFields(log.Fields{"key": at.Key, "value": common.Bytes2Hex(at.Value), "contract": a.address.Hex()}).Info("attestation published")
+	return nil
+}
+
+// Checks implements selftest.Checker. Besides the chain it verifies that the contract answers
+// getValue.
+func (a *Attestor) Checks() []selftest.Check {
+	return append(a.chain.Checks(), selftest.Check{Name: "attestation oracle " + a.address.Hex() + " getValue", Run: func() error {
+		_, _, err := a.contract.GetValue(&bind.CallOpts{}, "")
+		return err
+	}})
+}
@@ -0,0 +1,107 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// auditQueueSize is the number of records buffered while the store is slow.
+	auditQueueSize = 1000
+	// receiptTimeout is the time after which an update without receipt is recorded as failed.
+	receiptTimeout = 10 * time.Minute
+)
+
+// OracleUpdateStore stores the audit trail of oracle updates. It is implemented by models.RelDB.
+type OracleUpdateStore interface {
+	SetOracleUpdate(update models.OracleUpdate) error
+}
+
+// AuditTrail records all attempted updates of the chains using it along with the receipts of
+// their transactions. Records are written by a single goroutine, so that chains can share a
+// store which isn't safe for concurrent use, and a slow store doesn't delay updates.
+type AuditTrail struct {
+	store   OracleUpdateStore
+	records chan models.OracleUpdate
+}
+
+// NewAuditTrail returns an audit trail writing to @store.
+func NewAuditTrail(store OracleUpdateStore) *AuditTrail {
+	a := &AuditTrail{store: store, records: make(chan models.OracleUpdate, auditQueueSize)}
+	go func() {
+		for update := range a.records {
+			if err := a.store.SetOracleUpdate(update); err != nil {
+				log.Errorf("recording %s update of %s in tx %s: %v", update.Status, update.Key, update.TxHash, err)
+			}
+		}
+	}()
+	return a
+}
+
+// record queues @update. It drops the record if the queue is full.
+func (a *AuditTrail) record(update models.OracleUpdate) {
+	update.Time = time.Now()
+	select {
+	case a.records <- update:
+	default:
+		log.Errorf("audit trail queue is full, dropping %s update of %s in tx %s", update.Status, update.Key, update.TxHash)
+	}
+}
+
+// SetAuditTrail makes the oracles on @c record their updates in @trail.
+func (c *Chain) SetAuditTrail(trail *AuditTrail) {
+	c.audit = trail
+}
+
+// recordUpdate records the outcome @err of sending @update in @tx, if the chain has an audit
+// trail. Sent transactions are recorded again once their receipt arrived.
+func (c *Chain) recordUpdate(update models.OracleUpdate, tx *types.Transaction, err error) {
+	if c.audit == nil {
+		return
+	}
+	update.ChainID = c.chainID
+	switch {
+	case errors.Is(err, oracleFeeder.ErrSkipped):
+		update.Status = models.OracleUpdateSkipped
+		update.Error = err.Error()
+	case err != nil:
+		update.Status = models.OracleUpdateFailed
+		update.Error = err.Error()
+	case tx == nil:
+		update.Status = models.OracleUpdateProposed
+	default:
+		update.Status = models.OracleUpdateSubmitted
+		update.TxHash = tx.Hash().Hex()
+		go c.recordReceipt(update, tx)
+	}
+	c.audit.record(update)
+}
+
+// recordReceipt waits for the receipt of @tx and records @update as confirmed or reverted.
+func (c *Chain) recordReceipt(update models.OracleUpdate, tx *types.Transaction) {
+	ctx, cancel := context.WithTimeout(context.Background(), receiptTimeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, c.client, tx)
+	if err != nil {
+		update.Status = models.OracleUpdateFailed
+		update.Error = "no receipt: " + err.Error()
+		c.audit.record(update)
+		return
+	}
+	update.Status = models.OracleUpdateConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		update.Status = models.OracleUpdateReverted
+	}
+	update.GasUsed = receipt.GasUsed
+	if receipt.BlockNumber != nil {
+		update.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	c.audit.record(update)
+}
@@ -0,0 +1,118 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	heartbeatsTotal = metrics.NewCounter("oraclefeeder", "heartbeats", "Keeper heartbeats by tenant and result.", "tenant", "result")
+	lastHeartbeat   = metrics.NewGauge("oraclefeeder", "last_heartbeat_timestamp_seconds", "Time of the last successful keeper heartbeat.", "tenant")
+)
+
+// Heartbeat periodically calls a liveness method of a consumer contract, e.g. heartbeat(), as
+// a dead man's switch: consumers fall back to a backup oracle once the calls stop. Calls are
+// only sent while the feeder is healthy, so that a feeder which stopped updating also stops
+// signaling liveness.
+type Heartbeat struct {
+	tenant   string
+	chain    *Chain
+	address  common.Address
+	method   string
+	interval time.Duration
+	contract *bind.BoundContract
+	healthy  func() error
+}
+
+// NewHeartbeat returns the heartbeat of @tenant calling @method of the contract at @address on
+// @chain every @interval while @healthy returns nil. @method is the signature of the method,
+// which takes either no argument or the current unix time, e.g. heartbeat() or ping(uint256).
+func NewHeartbeat(tenant string, chain *Chain, address common.Address, method string, interval time.Duration, healthy func() error) (*Heartbeat, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid interval %v of heartbeat of %s", interval, tenant)
+	}
+	if _, err := PackHeartbeat(method, time.Now()); err != nil {
+		return nil, err
+	}
+	return &Heartbeat{
+		tenant:   tenant,
+		chain:    chain,
+		address:  address,
+		method:   method,
+		interval: interval,
+		contract: bind.NewBoundContract(address, abi.ABI{}, chain.client, chain.client, chain.client),
+		healthy:  healthy,
+	}, nil
+}
+
+// PackHeartbeat returns the call data of the heartbeat @method at @now.
+func PackHeartbeat(method string, now time.Time) ([]byte, error) {
+	selector := crypto.Keccak256([]byte(method))[:4]
+	switch {
+	case strings.HasSuffix(method, "()"):
+		return selector, nil
+	case strings.HasSuffix(method, "(uint256)"):
+		return append(selector, common.LeftPadBytes(big.NewInt(now.Unix()).Bytes(), 32)...), nil
+	}
+	return nil, fmt.Errorf("unsupported heartbeat method %s, expected name() or name(uint256)", method)
+}
+
+// Run calls the heartbeat method every interval. It blocks forever.
+func (h *Heartbeat) Run() {
+	ticker := time.NewTicker(h.interval)
+	for range ticker.C {
+		err := h.Beat()
+		if err != nil {
+			log.WithField("tenant", h.tenant).Errorf("heartbeat failed: %v", err)
+		}
+	}
+}
+
+// Beat calls the heartbeat method once, unless the feeder is unhealthy.
+func (h *Heartbeat) Beat() error {
+	if err := h.healthy(); err != nil {
+		heartbeatsTotal.Inc(h.tenant, "unhealthy")
+		return fmt.Errorf("withholding heartbeat: %v", err)
+	}
+	data, err := PackHeartbeat(h.method, time.Now())
+	if err != nil {
+		return err
+	}
+	tx, fee, err := h.chain.transact(h.address, data, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.contract.RawTransact(opts, data)
+	})
+	if err != nil {
+		heartbeatsTotal.Inc(h.tenant, "error")
+		return err
+	}
+	heartbeatsTotal.Inc(h.tenant, "success")
+	lastHeartbeat.Set(float64(time.Now().Unix()), h.tenant)
+	h.chain.txLogger(tx, fee).WithFields(log.Fields{"tenant": h.tenant, "method": h.method, "contract": h.address.Hex()}).Info("heartbeat sent")
+	return nil
+}
+
+// FeederHealth returns a health check for heartbeats which fails if @lastCycle returns a time
+// older than @maxAge, e.g. twice the frequency of the feeder.
+func FeederHealth(lastCycle func() time.Time, maxAge time.Duration) func() error {
+	return func() error {
+		last := lastCycle()
+		if last.IsZero() {
+			return errors.New("feeder didn't complete a cycle yet")
+		}
+		if age := time.Since(last); age > maxAge {
+			return fmt.Errorf("feeder didn't complete a cycle for %v", age.Round(time.Second))
+		}
+		return nil
+	}
+}
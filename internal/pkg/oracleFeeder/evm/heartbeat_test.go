@@ -0,0 +1,46 @@
+package evm
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestPackHeartbeat(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cases := []struct {
+		method   string
+		expected string
+	}{
+		{"heartbeat()", "3defb962"},
+		{"ping(uint256)", "773acdef" + "000000000000000000000000000000000000000000000000000000006553f100"},
+	}
+	for _, c := range cases {
+		data, err := PackHeartbeat(c.method, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(data) != c.expected {
+			t.Errorf("%s: expected %s, got %x", c.method, c.expected, data)
+		}
+	}
+	if _, err := PackHeartbeat("ping(address)", now); err == nil {
+		t.Errorf("expected error for unsupported arguments")
+	}
+}
+
+func TestFeederHealth(t *testing.T) {
+	var last time.Time
+	healthy := FeederHealth(func() time.Time { return last }, time.Minute)
+	if healthy() == nil {
+		t.Errorf("expected error before the first cycle")
+	}
+	last = time.Now().Add(-30 * time.Second)
+	if err := healthy(); err != nil {
+		t.Errorf("expected healthy feeder, got %v", err)
+	}
+	last = time.Now().Add(-2 * time.Minute)
+	if healthy() == nil {
+		t.Errorf("expected error for stale feeder")
+	}
+}
@@ -0,0 +1,262 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaOracleServiceV2"
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// Chain is a connection to an EVM chain along with the feeder wallets. Oracles on the same chain
+// share it, so that the transactions of each wallet get consecutive nonces. Transactions are
+// spread round-robin across the wallets.
+type Chain struct {
+	client    *backend
+	accounts  []*account
+	estimator *feeEstimation.Estimator
+	chainID   int64
+	// audit records the updates of the oracles on the chain if non-nil.
+	audit *AuditTrail
+	// safe submits all transactions if non-nil.
+	safe *Safe
+	// next is the index of the account sending the next transaction.
+	next int
+	mu   sync.Mutex
+}
+
+// NewChain connects to @blockchainNode and unlocks @wallets, which must not be empty.
+// An empty @chainType is detected from @chainID. Gas limits of transactions are derived from
+// the estimated gas by @gasLimit.
+func NewChain(blockchainNode string, wallets []Wallet, chainID int64, chainType string, maxFee *big.Int, gasLimit feeEstimation.GasLimit) (*Chain, error) {
+	if len(wallets) == 0 {
+		return nil, errors.New("no wallets configured")
+	}
+	node, err := ethclient.Dial(blockchainNode)
+	if err != nil {
+		return nil, err
+	}
+	var accounts []*account
+	for _, w := range wallets {
+		auth, err := bind.NewTransactorWithChainID(strings.NewReader(w.Key), w.Password, big.NewInt(chainID))
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, &account{auth: auth})
+	}
+	if chainType == "" {
+		chainType = feeEstimation.ChainType(chainID)
+	}
+	estimator, err := feeEstimation.NewEstimator(node, chainType, maxFee, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &Chain{client: &backend{Client: node}, accounts: accounts, estimator: estimator, chainID: chainID}, nil
+}
+
+// Checks implements selftest.Checker. It verifies that the node serves the configured chain
+// and that all wallets can pay for updates.
+func (c *Chain) Checks() []selftest.Check {
+	checks := []selftest.Check{
+		{Name: "chain id", Run: func() error {
+			chainID, err := c.client.ChainID(context.Background())
+			if err != nil {
+				return err
+			}
+			if chainID.Int64() != c.chainID {
+				return fmt.Errorf("node serves chain %s, configured is %d", chainID, c.chainID)
+			}
+			return nil
+		}},
+	}
+	for _, a := range c.accounts {
+		a := a
+		checks = append(checks, selftest.Check{Name: "wallet " + a.auth.From.Hex(), Run: func() error {
+			balance, err := c.client.BalanceAt(context.Background(), a.auth.From, nil)
+			if err != nil {
+				return err
+			}
+			if balance.Sign() == 0 {
+				return fmt.Errorf("wallet has no funds")
+			}
+			return nil
+		}})
+	}
+	checks = append(checks, c.relayChecks()...)
+	if c.safe != nil {
+		checks = append(checks, c.safe.Checks()...)
+	}
+	return checks
+}
+
+// Oracle writes quotations into a DIAOracleV2 contract. It implements oracleFeeder.Updater.
+type Oracle struct {
+	chain    *Chain
+	address  common.Address
+	contract *diaOracleServiceV2.DIAOracleV2
+	watcher  *diaOracleServiceV2.ProxyWatcher
+}
+
+// NewOracle binds the DIAOracleV2 contract at @address on @chain.
+func NewOracle(chain *Chain, address common.Address) (*Oracle, error) {
+	contract, err := diaOracleServiceV2.NewDIAOracleV2(address, chain.client)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := diaOracleServiceV2.NewProxyWatcher(chain.client, address)
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle{chain: chain, address: address, contract: contract, watcher: watcher}, nil
+}
+
+// Checks implements selftest.Checker. Besides the chain it verifies that the implementation
+// of the oracle is compatible and answers getValue.
+func (o *Oracle) Checks() []selftest.Check {
+	return append(o.chain.Checks(),
+		selftest.Check{Name: "oracle " + o.address.Hex() + " implementation", Run: o.watcher.Check},
+		selftest.Check{Name: "oracle " + o.address.Hex() + " getValue", Run: func() error {
+			_, _, err := o.contract.GetValue(&bind.CallOpts{}, "BTC/USD")
+			return err
+		}},
+	)
+}
+
+// Update sets the price of @quotation with 8 decimals under its key, e.g. BTC/USD or ETH/EUR.
+func (o *Oracle) Update(quotation *models.Quotation) error {
+	err := o.watcher.Check()
+	if err != nil {
+		return err
+	}
+	key := oracleFeeder.Key(quotation)
+	value := big.NewInt(int64(quotation.Price * 100000000))
+	timestamp := big.NewInt(time.Now().Unix())
+
+	data, err := diaOracleServiceV2.PackSetValue(key, value, timestamp)
+	if err != nil {
+		return err
+	}
+	tx, fee, err := o.chain.transact(o.address, data, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return o.contract.SetValue(opts, key, value, timestamp)
+	})
+	o.chain.recordUpdate(models.OracleUpdate{
+		Contract:  o.address.Hex(),
+		Key:       key,
+		Value:     quotation.Price,
+		Timestamp: time.Unix(timestamp.Int64(), 0),
+	}, tx, err)
+	if err != nil {
+		return err
+	}
+	o.chain.txLogger(tx, fee).WithFields(log.Fields{"key": key, "contract": o.address.Hex()}).Info("oracle updated")
+	return nil
+}
+
+// txLogger returns a logger with the fields of @tx on the chain and its estimated @fee. A nil
+// @tx is a call proposed to the owners of the Safe of the chain.
+func (c *Chain) txLogger(tx *types.Transaction, fee feeEstimation.Fee) *log.Entry {
+	if tx == nil {
+		return log.WithFields(log.Fields{"chainID": c.chainID, "safe": c.safe.address.Hex(), "proposed": true})
+	}
+	return log.WithFields(log.Fields{
+		"chainID":  c.chainID,
+		"txHash":   tx.Hash().Hex(),
+		"gasPrice": tx.GasPrice(),
+		"fee":      fee.String(),
+	})
+}
+
+// transact sends the call of @to with @data, through the Safe of the chain if it has one. The
+// returned transaction is nil if the call was proposed to the owners of the Safe.
+func (c *Chain) transact(to common.Address, data []byte, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, feeEstimation.Fee, error) {
+	if c.safe != nil {
+		return c.safe.submit(c, to, data)
+	}
+	return c.transactDirect(to, data, send)
+}
+
+// transactDirect estimates and caps the fee of the call of @to with @data, and sends it through
+// @send with the next nonce of the next available wallet. Failed gas estimations are returned
+// as oracleFeeder.ErrSkipped, as the transaction would most likely revert, and so is the case
+// in which no wallet is available.
+func (c *Chain) transactDirect(to common.Address, data []byte, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, feeEstimation.Fee, error) {
+	ctx := context.Background()
+	gasPrice, err := c.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, feeEstimation.Fee{}, err
+	}
+	// Get 110% of the gas price
+	gasPrice = new(big.Int).Div(new(big.Int).Mul(gasPrice, big.NewInt(110)), big.NewInt(100))
+
+	for _, a := range c.candidates(time.Now()) {
+		tx, fee, err := c.transactFrom(ctx, a, to, data, gasPrice, send)
+		if errors.Is(err, errAccountUnavailable) {
+			log.WithField("chainID", c.chainID).Warnf("trying next wallet: %v", err)
+			continue
+		}
+		return tx, fee, err
+	}
+	return nil, feeEstimation.Fee{}, fmt.Errorf("%w: none of the %d wallets is available", oracleFeeder.ErrSkipped, len(c.accounts))
+}
+
+// transactFrom sends the call of @to with @data from @a. It returns errAccountUnavailable and
+// pauses @a if it is drained or its pending transactions are stuck.
+func (c *Chain) transactFrom(ctx context.Context, a *account, to common.Address, data []byte, gasPrice *big.Int, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, feeEstimation.Fee, error) {
+	fee, err := c.estimator.Estimate(ctx, a.auth.From, to, data, gasPrice)
+	if errors.Is(err, feeEstimation.ErrEstimateGas) {
+		return nil, fee, fmt.Errorf("%w: %v", oracleFeeder.ErrSkipped, err)
+	}
+	if err != nil {
+		return nil, fee, err
+	}
+	err = c.estimator.CheckFee(fee)
+	if err != nil {
+		return nil, fee, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if a.nonce != nil && c.stuck(ctx, a, now) {
+		a.nonce = nil
+		c.pause(a, now)
+		return nil, fee, fmt.Errorf("%w: pending transactions of %s are stuck", errAccountUnavailable, a.auth.From.Hex())
+	}
+	if a.nonce == nil {
+		nonce, err := c.client.PendingNonceAt(ctx, a.auth.From)
+		if err != nil {
+			return nil, fee, err
+		}
+		a.nonce = &nonce
+	}
+	tx, err := send(&bind.TransactOpts{
+		From:     a.auth.From,
+		Signer:   a.auth.Signer,
+		Nonce:    new(big.Int).SetUint64(*a.nonce),
+		GasLimit: fee.GasLimit,
+		GasPrice: gasPrice,
+	})
+	if err != nil {
+		a.nonce = nil
+		if strings.Contains(err.Error(), "insufficient funds") {
+			c.pause(a, now)
+			return nil, fee, fmt.Errorf("%w: %s is drained: %v", errAccountUnavailable, a.auth.From.Hex(), err)
+		}
+		return nil, fee, err
+	}
+	*a.nonce++
+	return tx, fee, nil
+}
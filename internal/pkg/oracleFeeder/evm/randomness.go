@@ -0,0 +1,67 @@
+package evm
+
+import (
+	"math/big"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaRandomOracle"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder/randomness"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// RandomOracle writes drand rounds into a DIARandomOracle contract. It implements
+// randomness.Publisher.
+type RandomOracle struct {
+	chain    *Chain
+	address  common.Address
+	contract *diaRandomOracle.DIARandomOracle
+}
+
+// NewRandomOracle binds the DIARandomOracle contract at @address on @chain.
+func NewRandomOracle(chain *Chain, address common.Address) (*RandomOracle, error) {
+	contract, err := diaRandomOracle.NewDIARandomOracle(address, chain.client)
+	if err != nil {
+		return nil, err
+	}
+	return &RandomOracle{chain: chain, address: address, contract: contract}, nil
+}
+
+// LastRound implements randomness.Publisher.
+func (r *RandomOracle) LastRound() (uint64, error) {
+	round, err := r.contract.GetLastRound(&bind.CallOpts{})
+	if err != nil {
+		return 0, err
+	}
+	return round.Uint64(), nil
+}
+
+// Publish implements randomness.Publisher.
+func (r *RandomOracle) Publish(b randomness.Beacon) error {
+	round := new(big.Int).SetUint64(b.Round)
+	var value [32]byte
+	copy(value[:], b.Randomness)
+	data, err := diaRandomOracle.PackSetRandomValue(round, value, b.Signature)
+	if err != nil {
+		return err
+	}
+	tx, fee, err := r.chain.transact(r.address, data, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return r.contract.SetRandomValue(opts, round, value, b.Signature)
+	})
+	if err != nil {
+		return err
+	}
+	r.chain.txLogger(tx, fee).WithFields(log.Fields{"round": b.Round, "randomness": common.Bytes2Hex(b.Randomness), "contract": r.address.Hex()}).Info("randomness updated")
+	return nil
+}
+
+// Checks implements selftest.Checker. Besides the chain it verifies that the contract answers
+// getLastRound.
+func (r *RandomOracle) Checks() []selftest.Check {
+	return append(r.chain.Checks(), selftest.Check{Name: "random oracle " + r.address.Hex() + " getLastRound", Run: func() error {
+		_, err := r.LastRound()
+		return err
+	}})
+}
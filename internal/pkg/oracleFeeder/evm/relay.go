@@ -0,0 +1,60 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Private relays of Ethereum mainnet, which keep transactions out of the public mempool until
+// they are mined, so that large price updates can't be sandwiched or frontrun.
+const (
+	FlashbotsProtectURL = "https://rpc.flashbots.net"
+	MEVBlockerURL       = "https://rpc.mevblocker.io"
+)
+
+// backend is the client of a chain. It sends transactions to the private relay of the chain,
+// if any, and everything else to the node.
+type backend struct {
+	*ethclient.Client
+	relay *ethclient.Client
+}
+
+// SendTransaction implements bind.ContractTransactor.
+func (b *backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.relay != nil {
+		return b.relay.SendTransaction(ctx, tx)
+	}
+	return b.Client.SendTransaction(ctx, tx)
+}
+
+// SetPrivateRelay makes @c send its transactions to the private relay at @url, e.g.
+// FlashbotsProtectURL, instead of the node. All reads still go to the node.
+func (c *Chain) SetPrivateRelay(url string) error {
+	relay, err := ethclient.Dial(url)
+	if err != nil {
+		return err
+	}
+	c.client.relay = relay
+	return nil
+}
+
+// relayChecks returns the checks of the private relay of @c, if any.
+func (c *Chain) relayChecks() []selftest.Check {
+	if c.client.relay == nil {
+		return nil
+	}
+	return []selftest.Check{{Name: "private relay chain id", Run: func() error {
+		chainID, err := c.client.relay.ChainID(context.Background())
+		if err != nil {
+			return err
+		}
+		if chainID.Int64() != c.chainID {
+			return fmt.Errorf("relay serves chain %s, configured is %d", chainID, c.chainID)
+		}
+		return nil
+	}}}
+}
@@ -0,0 +1,298 @@
+package evm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/feeEstimation"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+// Modes of submitting transactions through a Safe.
+const (
+	// SafeExecute signs Safe transactions with enough owner keys to meet the threshold of the
+	// Safe and executes them from the feeder wallets.
+	SafeExecute = "execute"
+	// SafePropose signs Safe transactions with one owner key and proposes them to the Safe
+	// Transaction Service, where the other owners confirm and execute them.
+	SafePropose = "propose"
+)
+
+// safeABI is the part of the ABI of Safe contracts, version 1.3.0 and later, used by the feeder.
+const safeABI = `[
+{"inputs":[],"name":"nonce","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"getThreshold","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"isOwner","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"enum Enum.Operation","name":"operation","type":"uint8"},{"internalType":"uint256","name":"safeTxGas","type":"uint256"},{"internalType":"uint256","name":"baseGas","type":"uint256"},{"internalType":"uint256","name":"gasPrice","type":"uint256"},{"internalType":"address","name":"gasToken","type":"address"},{"internalType":"address payable","name":"refundReceiver","type":"address"},{"internalType":"bytes","name":"signatures","type":"bytes"}],"name":"execTransaction","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"payable","type":"function"}
+]`
+
+var (
+	// safeDomainTypehash is keccak256("EIP712Domain(uint256 chainId,address verifyingContract)").
+	safeDomainTypehash = common.HexToHash("0x47e79534a245952e8b16893a336b85a3d9ea9fa8c573f3d803afb92a79469218")
+	// safeTxTypehash is keccak256("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)").
+	safeTxTypehash = common.HexToHash("0xbb8310d486368db6bd6f849402fdd73ad53d316b5a4b2644ad6efe0f941286d8")
+)
+
+// safeNonceTimeout is the time after which an executed Safe transaction which didn't increase
+// the nonce of the Safe is considered lost, and its nonce is reused.
+const safeNonceTimeout = 10 * time.Minute
+
+// Safe submits the transactions of a chain through a Safe multisig, so that the Safe rather
+// than a feeder wallet is the sender of oracle updates. Transactions are plain calls without
+// value or gas refunds.
+type Safe struct {
+	address    common.Address
+	mode       string
+	owners     []*ecdsa.PrivateKey
+	serviceURL string
+	chainID    int64
+	abi        abi.ABI
+	contract   *bind.BoundContract
+	// next is the nonce of the next executed Safe transaction, sent the latest at sentAt.
+	next   uint64
+	sentAt time.Time
+	mu     sync.Mutex
+}
+
+// NewSafe returns the Safe at @address on @chain, which signs with @owners and submits in
+// @mode. @serviceURL is the Safe Transaction Service of the chain, required in SafePropose.
+func NewSafe(chain *Chain, address common.Address, mode string, owners []*ecdsa.PrivateKey, serviceURL string) (*Safe, error) {
+	if mode != SafeExecute && mode != SafePropose {
+		return nil, fmt.Errorf("unknown safe mode %s, expected %s or %s", mode, SafeExecute, SafePropose)
+	}
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("no owner keys of safe %s", address.Hex())
+	}
+	if mode == SafePropose && serviceURL == "" {
+		return nil, fmt.Errorf("safe mode %s requires the url of the transaction service", SafePropose)
+	}
+	parsed, err := abi.JSON(strings.NewReader(safeABI))
+	if err != nil {
+		return nil, err
+	}
+	// The Safe requires signatures sorted by owner.
+	sorted := append([]*ecdsa.PrivateKey{}, owners...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(crypto.PubkeyToAddress(sorted[i].PublicKey).Bytes(), crypto.PubkeyToAddress(sorted[j].PublicKey).Bytes()) < 0
+	})
+	return &Safe{
+		address:    address,
+		mode:       mode,
+		owners:     sorted,
+		serviceURL: strings.TrimSuffix(serviceURL, "/"),
+		chainID:    chain.chainID,
+		abi:        parsed,
+		contract:   bind.NewBoundContract(address, parsed, chain.client, chain.client, chain.client),
+	}, nil
+}
+
+// SetSafe makes @c submit all transactions through @safe.
+func (c *Chain) SetSafe(safe *Safe) {
+	c.safe = safe
+}
+
+// ReadSafeOwners reads the hex encoded private keys of Safe owners from @path, one per line.
+func ReadSafeOwners(path string) ([]*ecdsa.PrivateKey, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var owners []*ecdsa.PrivateKey
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(line, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("owner key %d: %v", len(owners)+1, err)
+		}
+		owners = append(owners, key)
+	}
+	return owners, scanner.Err()
+}
+
+// Checks implements selftest.Checker. It verifies that all keys belong to owners of the Safe
+// and, in SafeExecute, that they meet its threshold.
+func (s *Safe) Checks() []selftest.Check {
+	checks := []selftest.Check{{Name: "safe " + s.address.Hex() + " threshold", Run: func() error {
+		threshold, err := s.call("getThreshold")
+		if err != nil {
+			return err
+		}
+		if s.mode == SafeExecute && threshold.(*big.Int).Cmp(big.NewInt(int64(len(s.owners)))) > 0 {
+			return fmt.Errorf("threshold %s exceeds the %d owner keys", threshold, len(s.owners))
+		}
+		return nil
+	}}}
+	for _, key := range s.owners {
+		owner := crypto.PubkeyToAddress(key.PublicKey)
+		checks = append(checks, selftest.Check{Name: "safe owner " + owner.Hex(), Run: func() error {
+			isOwner, err := s.call("isOwner", owner)
+			if err != nil {
+				return err
+			}
+			if !isOwner.(bool) {
+				return fmt.Errorf("%s isn't an owner of safe %s", owner.Hex(), s.address.Hex())
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// submit sends the call of @to with @data through the Safe. In SafePropose it returns a nil
+// transaction, as the call is only executed once the other owners confirmed it.
+func (s *Safe) submit(c *Chain, to common.Address, data []byte) (*types.Transaction, feeEstimation.Fee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	onchain, err := s.call("nonce")
+	if err != nil {
+		return nil, feeEstimation.Fee{}, fmt.Errorf("safe nonce: %v", err)
+	}
+	nonce := onchain.(*big.Int).Uint64()
+
+	if s.mode == SafePropose {
+		// Proposals reuse the pending nonce, so that the owners only confirm the latest update.
+		hash := safeTxHash(s.chainID, s.address, to, data, nonce)
+		signature, err := signSafeTx(hash, s.owners[:1])
+		if err != nil {
+			return nil, feeEstimation.Fee{}, err
+		}
+		err = s.propose(to, data, nonce, hash, crypto.PubkeyToAddress(s.owners[0].PublicKey), signature)
+		if err != nil {
+			return nil, feeEstimation.Fee{}, fmt.Errorf("proposing safe transaction: %v", err)
+		}
+		log.WithFields(log.Fields{"chainID": s.chainID, "safe": s.address.Hex(), "safeTxHash": hash.Hex(), "nonce": nonce}).Info("safe transaction proposed")
+		return nil, feeEstimation.Fee{}, nil
+	}
+
+	// Executed transactions may still be pending, in which case the Safe didn't increase its
+	// nonce yet.
+	if nonce < s.next && time.Since(s.sentAt) < safeNonceTimeout {
+		nonce = s.next
+	}
+	hash := safeTxHash(s.chainID, s.address, to, data, nonce)
+	signatures, err := signSafeTx(hash, s.owners)
+	if err != nil {
+		return nil, feeEstimation.Fee{}, err
+	}
+	execData, err := s.abi.Pack("execTransaction", to, big.NewInt(0), data, uint8(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, signatures)
+	if err != nil {
+		return nil, feeEstimation.Fee{}, err
+	}
+	tx, fee, err := c.transactDirect(s.address, execData, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.contract.RawTransact(opts, execData)
+	})
+	if err != nil {
+		return tx, fee, err
+	}
+	s.next = nonce + 1
+	s.sentAt = time.Now()
+	return tx, fee, nil
+}
+
+// propose posts the Safe transaction calling @to with @data to the transaction service.
+func (s *Safe) propose(to common.Address, data []byte, nonce uint64, hash common.Hash, sender common.Address, signature []byte) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"to":                      to.Hex(),
+		"value":                   "0",
+		"data":                    hexutil.Encode(data),
+		"operation":               0,
+		"safeTxGas":               "0",
+		"baseGas":                 "0",
+		"gasPrice":                "0",
+		"gasToken":                common.Address{}.Hex(),
+		"refundReceiver":          common.Address{}.Hex(),
+		"nonce":                   nonce,
+		"contractTransactionHash": hash.Hex(),
+		"sender":                  sender.Hex(),
+		"signature":               hexutil.Encode(signature),
+		"origin":                  "DIA oracle feeder",
+	})
+	if err != nil {
+		return err
+	}
+	response, err := http.Post(s.serviceURL+"/api/v1/safes/"+s.address.Hex()+"/multisig-transactions/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		contents, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("transaction service returned %d: %s", response.StatusCode, contents)
+	}
+	return nil
+}
+
+// call calls the view @method of the Safe and returns its single result.
+func (s *Safe) call(method string, params ...interface{}) (interface{}, error) {
+	var out []interface{}
+	err := s.contract.Call(&bind.CallOpts{Context: context.Background()}, &out, method, params...)
+	if err != nil {
+		return nil, err
+	}
+	return out[0], nil
+}
+
+// safeTxHash returns the EIP-712 hash of the Safe transaction calling @to with @data at @nonce,
+// without value, gas refunds or delegate call.
+func safeTxHash(chainID int64, safe common.Address, to common.Address, data []byte, nonce uint64) common.Hash {
+	zero := make([]byte, 32)
+	domainSeparator := crypto.Keccak256(
+		safeDomainTypehash.Bytes(),
+		common.LeftPadBytes(big.NewInt(chainID).Bytes(), 32),
+		common.LeftPadBytes(safe.Bytes(), 32),
+	)
+	structHash := crypto.Keccak256(
+		safeTxTypehash.Bytes(),
+		common.LeftPadBytes(to.Bytes(), 32),
+		zero, // value
+		crypto.Keccak256(data),
+		zero, // operation
+		zero, // safeTxGas
+		zero, // baseGas
+		zero, // gasPrice
+		zero, // gasToken
+		zero, // refundReceiver
+		common.LeftPadBytes(new(big.Int).SetUint64(nonce).Bytes(), 32),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator, structHash)
+}
+
+// signSafeTx returns the concatenated signatures of @hash by @owners, which must be sorted by
+// address.
+func signSafeTx(hash common.Hash, owners []*ecdsa.PrivateKey) ([]byte, error) {
+	var signatures []byte
+	for _, key := range owners {
+		signature, err := crypto.Sign(hash.Bytes(), key)
+		if err != nil {
+			return nil, err
+		}
+		// The Safe expects v as 27 or 28 for signatures of the plain hash.
+		signature[64] += 27
+		signatures = append(signatures, signature...)
+	}
+	return signatures, nil
+}
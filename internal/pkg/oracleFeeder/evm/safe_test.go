@@ -0,0 +1,50 @@
+package evm
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSafeTypehashes(t *testing.T) {
+	if crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)")) != safeDomainTypehash {
+		t.Error("wrong domain typehash")
+	}
+	if crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)")) != safeTxTypehash {
+		t.Error("wrong transaction typehash")
+	}
+}
+
+func TestSafeTxHash(t *testing.T) {
+	hash := safeTxHash(137, common.HexToAddress("0x1111111111111111111111111111111111111111"), common.HexToAddress("0x2222222222222222222222222222222222222222"), []byte{0x12, 0x34}, 5)
+	expected := common.HexToHash("0x2804296cc8abdf7bfbaafeb4ea7a21f7113c5c4b69a49cb147a383512a30aaf4")
+	if hash != expected {
+		t.Errorf("expected %s, got %s", expected.Hex(), hash.Hex())
+	}
+}
+
+func TestSignSafeTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := crypto.Keccak256Hash([]byte("update"))
+	signatures, err := signSafeTx(hash, []*ecdsa.PrivateKey{key, key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 130 || signatures[64] < 27 || signatures[129] < 27 {
+		t.Fatalf("unexpected signatures %x", signatures)
+	}
+	signature := append([]byte{}, signatures[:65]...)
+	signature[64] -= 27
+	pub, err := crypto.SigToPub(hash.Bytes(), signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*pub) != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Error("signature doesn't recover the owner")
+	}
+}
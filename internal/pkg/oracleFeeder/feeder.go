@@ -0,0 +1,431 @@
+package oracleFeeder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	updatesTotal = metrics.NewCounter("oraclefeeder", "updates", "Oracle updates by symbol and result.", "symbol", "result")
+	lastUpdate   = metrics.NewGauge("oraclefeeder", "last_update_timestamp_seconds", "Time of the last successful oracle update.", "symbol")
+	lastPrice    = metrics.NewGauge("oraclefeeder", "last_price", "Price written by the last successful oracle update.", "symbol")
+)
+
+// Updater writes a quotation into an oracle contract on a specific blockchain.
+type Updater interface {
+	Update(quotation *models.Quotation) error
+}
+
+// ErrSkipped is wrapped by updaters which didn't send an update because it would most likely
+// fail, e.g. if its gas can't be estimated. The feeder logs it and tries again in the next cycle.
+var ErrSkipped = errors.New("update skipped")
+
+// Config holds the update policy of a feeder. It mirrors the flags of the EVM oracle services.
+type Config struct {
+	Symbols []string
+	// Frequency is the time between two checks of all symbols.
+	Frequency time.Duration
+	// Sleep is the pause between the updates of two symbols.
+	Sleep time.Duration
+	// DeviationPermille is the price deviation which triggers an update.
+	DeviationPermille int
+	// AbsoluteDeviations are price deviations in USD per symbol which trigger an update in
+	// addition to DeviationPermille, e.g. 0.002 for stablecoins.
+	AbsoluteDeviations map[string]float64
+	// Heartbeat forces an update if a symbol wasn't updated for this long. Zero disables it.
+	Heartbeat time.Duration
+	// TWAPWindow makes the feeder push the time-weighted average of TWAPFilter over this
+	// window instead of the latest quotation. Zero pushes the quotation.
+	TWAPWindow time.Duration
+	// TWAPFilter is the filter of the filters service the average is taken of, e.g. MA120.
+	TWAPFilter string
+	// FiatSymbols are fiat currencies such as EUR, GBP and JPY, which are priced by the foreign
+	// quotations of FiatSource instead of the quotations of the DIA API.
+	FiatSymbols []string
+	FiatSource  string
+	// FiatMaxAge is the age after which a fiat rate is stale and no longer pushed. It should
+	// span weekends and holidays, in which FX markets publish no rates. Zero disables it.
+	FiatMaxAge time.Duration
+	// Decisions records the outcome of every check if non-nil.
+	Decisions *DecisionLog
+}
+
+// Feeder periodically fetches quotations from the DIA API and hands them to an Updater
+// whenever the deviation or the heartbeat condition is met.
+type Feeder struct {
+	config      Config
+	updater     Updater
+	lastPrices  map[string]float64
+	lastUpdates map[string]time.Time
+	// lastSourceTimes holds the time of the fiat rates of the last update of fiat feeds.
+	lastSourceTimes map[string]time.Time
+	// lastCycle is the time UpdateAll last completed.
+	lastCycle time.Time
+	// prefetched holds the quotations fetched in a batch at the start of the running cycle.
+	prefetched map[string]*models.Quotation
+	// mu guards config, lastPrices, lastUpdates, lastSourceTimes, lastCycle and prefetched. The
+	// admin API changes the config at runtime.
+	mu sync.Mutex
+}
+
+// NewFeeder returns a feeder pushing the symbols in @config through @updater.
+func NewFeeder(config Config, updater Updater) *Feeder {
+	return &Feeder{
+		config:          config,
+		updater:         updater,
+		lastPrices:      make(map[string]float64),
+		lastUpdates:     make(map[string]time.Time),
+		lastSourceTimes: make(map[string]time.Time),
+	}
+}
+
+// Run checks all symbols every config.Frequency. It blocks forever.
+func (f *Feeder) Run() {
+	ticker := time.NewTicker(f.config.Frequency)
+	for range ticker.C {
+		f.UpdateAll()
+	}
+}
+
+// UpdateAll checks all symbols and then sends the necessary updates, the most urgent first, so
+// that the stalest and most deviating feeds are updated first when many breach at once.
+func (f *Feeder) UpdateAll() {
+	f.prefetch()
+	var pending []*pendingUpdate
+	for _, symbol := range f.Symbols() {
+		f.mu.Lock()
+		p, err := f.check(symbol, false)
+		f.mu.Unlock()
+		if err != nil {
+			log.WithField("symbol", symbol).Errorf("update failed: %v", err)
+			continue
+		}
+		if p != nil {
+			pending = append(pending, p)
+		}
+	}
+	f.mu.Lock()
+	config := f.config
+	f.mu.Unlock()
+	sortByPriority(pending, config, time.Now())
+	for i, p := range pending {
+		if i > 0 {
+			time.Sleep(config.Sleep)
+		}
+		f.mu.Lock()
+		var err error
+		if f.feeds(p.decision.Symbol) {
+			err = f.apply(p)
+		}
+		f.mu.Unlock()
+		if err != nil {
+			log.WithField("symbol", p.decision.Symbol).Errorf("update failed: %v", err)
+		}
+	}
+	f.mu.Lock()
+	f.lastCycle = time.Now()
+	f.prefetched = nil
+	f.mu.Unlock()
+}
+
+// LastCycle returns the time the feeder last completed checking and updating all symbols, or
+// the zero time if it didn't complete a cycle yet.
+func (f *Feeder) LastCycle() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastCycle
+}
+
+// Checks implements selftest.Checker. It verifies that the DIA API serves the base and quote
+// currencies of all feeds and adds the checks of the updater.
+func (f *Feeder) Checks() []selftest.Check {
+	var checks []selftest.Check
+	var symbols []string
+	checked := map[string]bool{DefaultQuote: true}
+	for _, feed := range f.Symbols() {
+		base, quote := ParseFeed(feed)
+		for _, symbol := range []string{base, quote} {
+			if !checked[symbol] {
+				checked[symbol] = true
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	for _, symbol := range symbols {
+		symbol := symbol
+		checks = append(checks, selftest.Check{Name: "dia api " + symbol, Run: func() error {
+			_, err := f.price(symbol, time.Now())
+			return err
+		}})
+	}
+	return append(checks, selftest.Collect("", f.updater)...)
+}
+
+// UpdateSymbol fetches the current quotation of @symbol and updates the oracle if necessary.
+func (f *Feeder) UpdateSymbol(symbol string) error {
+	return f.updateSymbol(symbol, false)
+}
+
+// ForceUpdate fetches the current quotation of @symbol and updates the oracle regardless of
+// the deviation and heartbeat conditions.
+func (f *Feeder) ForceUpdate(symbol string) error {
+	return f.updateSymbol(symbol, true)
+}
+
+func (f *Feeder) updateSymbol(symbol string, force bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, err := f.check(symbol, force)
+	if err != nil || p == nil {
+		return err
+	}
+	return f.apply(p)
+}
+
+// Symbols returns the symbols the feeder currently feeds.
+func (f *Feeder) Symbols() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.config.Symbols...)
+}
+
+// feeds returns true if @symbol is one of the fed symbols. f.mu must be held.
+func (f *Feeder) feeds(symbol string) bool {
+	for _, s := range f.config.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingUpdate is a quotation which meets the update conditions.
+type pendingUpdate struct {
+	quotation *models.Quotation
+	decision  Decision
+	// sourceTime is the time of the fiat rates of fiat feeds, zero for all other feeds.
+	sourceTime time.Time
+}
+
+// priority returns the urgency of @p at @now: its deviation relative to the configured
+// deviation plus its on-chain age relative to the heartbeat, or to stalenessUnit if the
+// heartbeat is disabled. Symbols which were never updated come first.
+func (p *pendingUpdate) priority(config Config, now time.Time) float64 {
+	if p.decision.LastUpdate.IsZero() {
+		return math.Inf(1)
+	}
+	deviation := p.decision.Deviation
+	if config.DeviationPermille > 0 {
+		deviation /= float64(config.DeviationPermille)
+	}
+	unit := config.Heartbeat
+	if unit <= 0 {
+		unit = stalenessUnit
+	}
+	return deviation + float64(now.Sub(p.decision.LastUpdate))/float64(unit)
+}
+
+// stalenessUnit is the age which weighs as much as the configured deviation in the priority of
+// updates of feeders without heartbeat.
+const stalenessUnit = time.Hour
+
+// sortByPriority orders @pending by descending priority at @now.
+func sortByPriority(pending []*pendingUpdate, config Config, now time.Time) {
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].priority(config, now) > pending[j].priority(config, now)
+	})
+}
+
+// check fetches the current quotation of @symbol and returns it as pending update if it meets
+// the update conditions or @force is set, nil otherwise. f.mu must be held.
+func (f *Feeder) check(symbol string, force bool) (*pendingUpdate, error) {
+	if retrier, ok := f.updater.(Retrier); ok {
+		retried, err := retrier.Retry(symbol)
+		if err != nil {
+			log.WithField("symbol", symbol).Errorf("retry failed: %v", err)
+		} else if retried != nil {
+			f.lastPrices[symbol] = retried.Price
+			f.lastUpdates[symbol] = time.Now()
+		}
+	}
+
+	now := time.Now()
+	decision := f.newDecision(symbol, now)
+	base, quote := ParseFeed(symbol)
+	quotation, err := f.price(base, now)
+	if err != nil {
+		updatesTotal.Inc(symbol, "source_error")
+		f.record(decision, ActionSourceError, err)
+		return nil, fmt.Errorf("failed to retrieve %s quotation data from DIA: %v", base, err)
+	}
+	quotation.Name = symbol
+	// Feeds of fiat currencies only change when FX markets publish a new rate.
+	var sourceTime time.Time
+	fiat := f.isFiat(base)
+	if fiat {
+		sourceTime = quotation.Time
+	}
+
+	if quote != DefaultQuote {
+		quoteQuotation, err := f.price(quote, now)
+		if err != nil {
+			updatesTotal.Inc(symbol, "source_error")
+			f.record(decision, ActionSourceError, err)
+			return nil, fmt.Errorf("failed to retrieve price of quote currency %s from DIA: %v", quote, err)
+		}
+		quotation.Price /= quoteQuotation.Price
+		fiat = fiat && f.isFiat(quote)
+		if quoteQuotation.Time.After(sourceTime) {
+			sourceTime = quoteQuotation.Time
+		}
+	}
+	if !fiat {
+		sourceTime = time.Time{}
+	}
+	decision.NewPrice = quotation.Price
+	if decision.OldPrice != 0 {
+		decision.Deviation = math.Abs(quotation.Price-decision.OldPrice) / decision.OldPrice * 1000
+	}
+	if !force && !NeedsUpdate(f.lastPrices[symbol], quotation.Price, f.config.DeviationPermille, f.config.AbsoluteDeviations[symbol], f.lastUpdates[symbol], f.config.Heartbeat, now) {
+		f.record(decision, ActionSkippedDeviation, nil)
+		return nil, nil
+	}
+	if !force && !sourceTime.IsZero() && !sourceTime.After(f.lastSourceTimes[symbol]) {
+		f.record(decision, ActionSkippedStale, nil)
+		return nil, nil
+	}
+	return &pendingUpdate{quotation: quotation, decision: decision, sourceTime: sourceTime}, nil
+}
+
+// apply writes the pending update @p through the updater. f.mu must be held.
+func (f *Feeder) apply(p *pendingUpdate) error {
+	symbol, quotation, decision := p.decision.Symbol, p.quotation, p.decision
+	logger := log.WithFields(log.Fields{
+		"symbol":    symbol,
+		"oldPrice":  decision.OldPrice,
+		"newPrice":  quotation.Price,
+		"deviation": decision.Deviation,
+	})
+	logger.Info("updating")
+	err := f.updater.Update(quotation)
+	if errors.Is(err, ErrSkipped) {
+		logger.Warnf("update skipped: %v", err)
+		updatesTotal.Inc(symbol, "skipped")
+		f.record(decision, ActionSkippedGas, err)
+		return nil
+	}
+	if err != nil {
+		updatesTotal.Inc(symbol, "error")
+		f.record(decision, ActionFailed, err)
+		return err
+	}
+	now := time.Now()
+	f.lastPrices[symbol] = quotation.Price
+	f.lastUpdates[symbol] = now
+	if !p.sourceTime.IsZero() {
+		f.lastSourceTimes[symbol] = p.sourceTime
+	}
+	updatesTotal.Inc(symbol, "success")
+	lastUpdate.Set(float64(now.Unix()), symbol)
+	lastPrice.Set(quotation.Price, symbol)
+	f.record(decision, ActionUpdated, nil)
+	return nil
+}
+
+// newDecision returns the decision of a check of @symbol at @now with the current state and
+// update policy filled in.
+func (f *Feeder) newDecision(symbol string, now time.Time) Decision {
+	return Decision{
+		Time:              now,
+		Symbol:            symbol,
+		OldPrice:          f.lastPrices[symbol],
+		DeviationPermille: f.config.DeviationPermille,
+		AbsoluteDeviation: f.config.AbsoluteDeviations[symbol],
+		LastUpdate:        f.lastUpdates[symbol],
+		HeartbeatSeconds:  int64(f.config.Heartbeat / time.Second),
+		TWAPWindowSeconds: int64(f.config.TWAPWindow / time.Second),
+	}
+}
+
+// record adds @decision with @action and @err to the decision log, if any.
+func (f *Feeder) record(decision Decision, action string, err error) {
+	if f.config.Decisions == nil {
+		return
+	}
+	decision.Action = action
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	f.config.Decisions.Record(decision)
+}
+
+// NeedsUpdate returns true if @newPrice deviates from @oldPrice by more than @deviationPermille
+// or by more than @absoluteDeviation, or if the last update is older than @heartbeat. A zero
+// @absoluteDeviation disables the absolute condition.
+func NeedsUpdate(oldPrice float64, newPrice float64, deviationPermille int, absoluteDeviation float64, lastUpdate time.Time, heartbeat time.Duration, now time.Time) bool {
+	if newPrice > oldPrice*(1+float64(deviationPermille)/1000) || newPrice < oldPrice*(1-float64(deviationPermille)/1000) {
+		return true
+	}
+	if absoluteDeviation > 0 && math.Abs(newPrice-oldPrice) > absoluteDeviation {
+		return true
+	}
+	return heartbeat > 0 && now.Sub(lastUpdate) >= heartbeat
+}
+
+// ParseAbsoluteDeviations parses absolute deviations given as comma separated SYMBOL:VALUE
+// pairs, e.g. "USDT:0.002,USDC:0.002".
+func ParseAbsoluteDeviations(s string) (map[string]float64, error) {
+	deviations := make(map[string]float64)
+	if s == "" {
+		return deviations, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid absolute deviation %q, expected SYMBOL:VALUE", entry)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid absolute deviation %q", entry)
+		}
+		deviations[strings.TrimSpace(parts[0])] = value
+	}
+	return deviations, nil
+}
+
+// GetQuotationFromDia returns the latest quotation of @symbol from the DIA API.
+func GetQuotationFromDia(symbol string) (*models.Quotation, error) {
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotation/" + strings.ToUpper(symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Error on dia api with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var quotation models.Quotation
+	err = quotation.UnmarshalBinary(contents)
+	if err != nil {
+		return nil, err
+	}
+	return &quotation, nil
+}
@@ -0,0 +1,79 @@
+package oracleFeeder
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func TestNeedsUpdate(t *testing.T) {
+	now := time.Unix(1630000000, 0)
+	cases := []struct {
+		oldPrice          float64
+		newPrice          float64
+		deviationPermille int
+		absoluteDeviation float64
+		lastUpdate        time.Time
+		heartbeat         time.Duration
+		expected          bool
+	}{
+		{0, 100, 10, 0, time.Time{}, 0, true},
+		{100, 100.5, 10, 0, now, 0, false},
+		{100, 101.5, 10, 0, now, 0, true},
+		{100, 98.5, 10, 0, now, 0, true},
+		{100, 100.5, 10, 0, now.Add(-2 * time.Hour), time.Hour, true},
+		{100, 100.5, 10, 0, now.Add(-30 * time.Minute), time.Hour, false},
+		{1, 1.003, 10, 0.002, now, 0, true},
+		{1, 0.997, 10, 0.002, now, 0, true},
+		{1, 1.001, 10, 0.002, now, 0, false},
+		{100, 101.5, 10, 5, now, 0, true},
+	}
+	for i, c := range cases {
+		result := NeedsUpdate(c.oldPrice, c.newPrice, c.deviationPermille, c.absoluteDeviation, c.lastUpdate, c.heartbeat, now)
+		if result != c.expected {
+			t.Errorf("case %d: expected %v, got %v", i, c.expected, result)
+		}
+	}
+}
+
+func TestParseAbsoluteDeviations(t *testing.T) {
+	deviations, err := ParseAbsoluteDeviations("USDT:0.002, USDC:0.001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deviations["USDT"] != 0.002 || deviations["USDC"] != 0.001 || len(deviations) != 2 {
+		t.Errorf("unexpected deviations %v", deviations)
+	}
+	for _, s := range []string{"USDT", "USDT:abc", "USDT:-1"} {
+		if _, err := ParseAbsoluteDeviations(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	now := time.Now()
+	config := Config{DeviationPermille: 10, Heartbeat: 24 * time.Hour}
+	pending := func(symbol string, deviation float64, age time.Duration) *pendingUpdate {
+		decision := Decision{Symbol: symbol, Deviation: deviation}
+		if age > 0 {
+			decision.LastUpdate = now.Add(-age)
+		}
+		return &pendingUpdate{quotation: &models.Quotation{Symbol: symbol}, decision: decision}
+	}
+	updates := []*pendingUpdate{
+		pending("FRESH", 11, time.Minute),
+		pending("DEVIATING", 50, time.Minute),
+		pending("STALE", 0, 25*time.Hour),
+		pending("NEW", 0, 0),
+		pending("BOTH", 15, 12*time.Hour),
+	}
+	sortByPriority(updates, config, now)
+	expected := []string{"NEW", "DEVIATING", "BOTH", "FRESH", "STALE"}
+	for i, symbol := range expected {
+		if updates[i].decision.Symbol != symbol {
+			t.Errorf("position %d: expected %s, got %s", i, symbol, updates[i].decision.Symbol)
+		}
+	}
+}
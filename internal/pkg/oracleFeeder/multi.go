@@ -0,0 +1,188 @@
+package oracleFeeder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	targetUpdatesTotal = metrics.NewCounter("oraclefeeder", "target_updates", "Updates of a single target of a multi-target feeder by result.", "target", "result")
+	targetFailures     = metrics.NewGauge("oraclefeeder", "target_consecutive_failures", "Consecutive failed updates of a target.", "target")
+)
+
+// Retrier is implemented by updaters which can complete an update that failed on some of
+// their targets. The feeder retries before checking for new deviations.
+type Retrier interface {
+	// Retry re-sends the pending update of @symbol to the targets on which it failed. It returns
+	// the quotation once all targets hold it, and nil if nothing is pending.
+	Retry(symbol string) (*models.Quotation, error)
+}
+
+// Target is an oracle contract written by a MultiUpdater.
+type Target struct {
+	Name    string
+	Updater Updater
+}
+
+// TargetStatus is the state of a target as tracked by a MultiUpdater.
+type TargetStatus struct {
+	Name                string
+	LastSuccess         time.Time
+	LastError           string
+	LastErrorTime       time.Time
+	ConsecutiveFailures int
+	// Pending are the symbols whose last update failed on this target.
+	Pending []string
+}
+
+type targetState struct {
+	Target
+	status  TargetStatus
+	pending map[string]bool
+}
+
+// MultiUpdater pushes each quotation to several targets in parallel, e.g. a main and a backup
+// contract or the same contract on two chains. For the feeder an update only succeeds once it
+// reached all targets; targets which failed are retried with the same quotation, so that all
+// targets end up with identical values.
+type MultiUpdater struct {
+	targets []*targetState
+	// last holds the quotation of the last update of each symbol.
+	last map[string]*models.Quotation
+	mu   sync.Mutex
+}
+
+// NewMultiUpdater returns an updater writing to all @targets.
+func NewMultiUpdater(targets []Target) *MultiUpdater {
+	m := &MultiUpdater{last: make(map[string]*models.Quotation)}
+	for _, t := range targets {
+		m.targets = append(m.targets, &targetState{
+			Target:  t,
+			status:  TargetStatus{Name: t.Name},
+			pending: make(map[string]bool),
+		})
+	}
+	return m
+}
+
+// PartialError lists the targets on which an update failed.
+type PartialError struct {
+	Symbol string
+	Errors map[string]error
+}
+
+func (e *PartialError) Error() string {
+	var parts []string
+	for name, err := range e.Errors {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, err))
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("update of %s failed on %d target(s): %s", e.Symbol, len(e.Errors), strings.Join(parts, "; "))
+}
+
+// Update sends @quotation to all targets. It supersedes pending retries of the same symbol.
+func (m *MultiUpdater) Update(quotation *models.Quotation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.last[quotation.Symbol] = quotation
+	return m.send(quotation, m.targets)
+}
+
+// Retry implements Retrier.
+func (m *MultiUpdater) Retry(symbol string) (*models.Quotation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var failed []*targetState
+	for _, t := range m.targets {
+		if t.pending[symbol] {
+			failed = append(failed, t)
+		}
+	}
+	if len(failed) == 0 {
+		return nil, nil
+	}
+	quotation := m.last[symbol]
+	log.WithFields(log.Fields{"symbol": symbol, "targets": len(failed)}).Info("retrying update")
+	err := m.send(quotation, failed)
+	if err != nil {
+		return nil, err
+	}
+	return quotation, nil
+}
+
+// send writes @quotation to @targets in parallel and records the outcome per target.
+func (m *MultiUpdater) send(quotation *models.Quotation, targets []*targetState) error {
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t *targetState) {
+			defer wg.Done()
+			errs[i] = t.Updater.Update(quotation)
+		}(i, t)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	partial := &PartialError{Symbol: quotation.Symbol, Errors: make(map[string]error)}
+	for i, t := range targets {
+		if errs[i] != nil {
+			t.pending[quotation.Symbol] = true
+			t.status.LastError = errs[i].Error()
+			t.status.LastErrorTime = now
+			t.status.ConsecutiveFailures++
+			partial.Errors[t.Name] = errs[i]
+			if errors.Is(errs[i], ErrSkipped) {
+				targetUpdatesTotal.Inc(t.Name, "skipped")
+			} else {
+				targetUpdatesTotal.Inc(t.Name, "error")
+			}
+			targetFailures.Set(float64(t.status.ConsecutiveFailures), t.Name)
+			continue
+		}
+		delete(t.pending, quotation.Symbol)
+		t.status.LastSuccess = now
+		t.status.ConsecutiveFailures = 0
+		targetUpdatesTotal.Inc(t.Name, "success")
+		targetFailures.Set(0, t.Name)
+	}
+	if len(partial.Errors) > 0 {
+		return partial
+	}
+	return nil
+}
+
+// Checks implements selftest.Checker with the checks of all targets.
+func (m *MultiUpdater) Checks() []selftest.Check {
+	var checks []selftest.Check
+	for _, t := range m.targets {
+		checks = append(checks, selftest.Collect("target "+t.Name+": ", t.Updater)...)
+	}
+	return checks
+}
+
+// Status returns the state of all targets.
+func (m *MultiUpdater) Status() []TargetStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var status []TargetStatus
+	for _, t := range m.targets {
+		s := t.status
+		s.Pending = nil
+		for symbol := range t.pending {
+			s.Pending = append(s.Pending, symbol)
+		}
+		sort.Strings(s.Pending)
+		status = append(status, s)
+	}
+	return status
+}
@@ -0,0 +1,50 @@
+package oracleFeeder
+
+import (
+	"errors"
+	"testing"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+type mockUpdater struct {
+	fail    bool
+	updates []float64
+}
+
+func (m *mockUpdater) Update(quotation *models.Quotation) error {
+	if m.fail {
+		return errors.New("unavailable")
+	}
+	m.updates = append(m.updates, quotation.Price)
+	return nil
+}
+
+func TestMultiUpdater(t *testing.T) {
+	main := &mockUpdater{}
+	backup := &mockUpdater{fail: true}
+	m := NewMultiUpdater([]Target{{Name: "main", Updater: main}, {Name: "backup", Updater: backup}})
+
+	err := m.Update(&models.Quotation{Symbol: "BTC", Price: 100})
+	if _, ok := err.(*PartialError); !ok {
+		t.Fatalf("expected partial error, got %v", err)
+	}
+	status := m.Status()
+	if status[1].ConsecutiveFailures != 1 || len(status[1].Pending) != 1 || len(status[0].Pending) != 0 {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// Only the failed target is retried, with the same quotation.
+	backup.fail = false
+	q, err := m.Retry("BTC")
+	if err != nil || q == nil || q.Price != 100 {
+		t.Fatalf("unexpected retry result %v, %v", q, err)
+	}
+	if len(main.updates) != 1 || len(backup.updates) != 1 || backup.updates[0] != 100 {
+		t.Errorf("unexpected updates main %v, backup %v", main.updates, backup.updates)
+	}
+	q, err = m.Retry("BTC")
+	if err != nil || q != nil {
+		t.Errorf("expected nothing pending, got %v, %v", q, err)
+	}
+}
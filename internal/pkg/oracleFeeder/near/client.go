@@ -0,0 +1,209 @@
+package near
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+// KeyFile is the credentials file format written by near-cli.
+type KeyFile struct {
+	AccountID  string `json:"account_id"`
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+}
+
+// Account is a NEAR account along with the full access key used for signing.
+type Account struct {
+	AccountID  string
+	privateKey ed25519.PrivateKey
+}
+
+// LoadAccount reads a near-cli credentials file from @path.
+func LoadAccount(path string) (*Account, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var kf KeyFile
+	err = json.Unmarshal(data, &kf)
+	if err != nil {
+		return nil, err
+	}
+	if kf.AccountID == "" {
+		return nil, errors.New("key file has no account_id")
+	}
+	if !strings.HasPrefix(kf.PrivateKey, "ed25519:") {
+		return nil, errors.New("only ed25519 keys are supported")
+	}
+	raw, err := utils.Base58Decode(strings.TrimPrefix(kf.PrivateKey, "ed25519:"))
+	if err != nil {
+		return nil, err
+	}
+	var privateKey ed25519.PrivateKey
+	switch len(raw) {
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.PrivateKey(raw)
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(raw)
+	default:
+		return nil, fmt.Errorf("invalid private key length %d", len(raw))
+	}
+	return &Account{AccountID: kf.AccountID, privateKey: privateKey}, nil
+}
+
+// PublicKey returns the public key of the account in NEAR's textual format.
+func (a *Account) PublicKey() string {
+	return "ed25519:" + utils.Base58Encode(a.privateKey.Public().(ed25519.PublicKey))
+}
+
+// Client signs and submits transactions of an account through a NEAR JSON-RPC node.
+type Client struct {
+	url        string
+	account    *Account
+	httpClient *http.Client
+	// nonce of the last submitted transaction. It is reset after failures
+	// so that it is read from the chain again.
+	nonce   uint64
+	nonceMu sync.Mutex
+}
+
+// NewClient returns a client for the JSON-RPC node at @url.
+func NewClient(url string, account *Account) *Client {
+	return &Client{
+		url:        url,
+		account:    account,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      string      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Name  string          `json:"name"`
+	Cause json.RawMessage `json:"cause"`
+	Data  json.RawMessage `json:"data"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("near rpc error %s: %s %s", e.Name, string(e.Cause), string(e.Data))
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// call performs the JSON-RPC call @method and unmarshals its result into @result.
+func (c *Client) call(method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: "dia", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	response, err := c.httpClient.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	var resp rpcResponse
+	err = json.Unmarshal(contents, &resp)
+	if err != nil {
+		return fmt.Errorf("near rpc returned status %d: %v", response.StatusCode, err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+type accessKeyView struct {
+	Nonce     uint64 `json:"nonce"`
+	BlockHash string `json:"block_hash"`
+}
+
+// accessKey returns the current nonce of the account's access key and a recent block hash.
+func (c *Client) accessKey() (accessKeyView, error) {
+	var view accessKeyView
+	params := map[string]string{
+		"request_type": "view_access_key",
+		"finality":     "final",
+		"account_id":   c.account.AccountID,
+		"public_key":   c.account.PublicKey(),
+	}
+	err := c.call("query", params, &view)
+	return view, err
+}
+
+type txOutcome struct {
+	Status      map[string]json.RawMessage `json:"status"`
+	Transaction struct {
+		Hash string `json:"hash"`
+	} `json:"transaction"`
+}
+
+// FunctionCall signs a transaction calling @call on the contract @receiverID, submits it
+// and waits for its execution. It returns the transaction hash.
+func (c *Client) FunctionCall(receiverID string, call FunctionCall) (string, error) {
+	c.nonceMu.Lock()
+	defer c.nonceMu.Unlock()
+
+	view, err := c.accessKey()
+	if err != nil {
+		return "", err
+	}
+	blockHash, err := utils.Base58Decode(view.BlockHash)
+	if err != nil {
+		return "", err
+	}
+	// The node may not yet reflect our last transaction, so never reuse a nonce.
+	nonce := view.Nonce
+	if c.nonce > nonce {
+		nonce = c.nonce
+	}
+	nonce++
+
+	tx := Transaction{
+		SignerID:   c.account.AccountID,
+		PublicKey:  c.account.privateKey.Public().(ed25519.PublicKey),
+		Nonce:      nonce,
+		ReceiverID: receiverID,
+		BlockHash:  blockHash,
+		Actions:    []FunctionCall{call},
+	}
+	serialized := tx.Serialize()
+	hash := sha256.Sum256(serialized)
+	signature := ed25519.Sign(c.account.privateKey, hash[:])
+	signed := base64.StdEncoding.EncodeToString(serializeSigned(serialized, signature))
+
+	var outcome txOutcome
+	err = c.call("broadcast_tx_commit", []string{signed}, &outcome)
+	if err != nil {
+		c.nonce = 0
+		return utils.Base58Encode(hash[:]), err
+	}
+	c.nonce = nonce
+	if failure, ok := outcome.Status["Failure"]; ok {
+		return outcome.Transaction.Hash, fmt.Errorf("transaction failed: %s", string(failure))
+	}
+	return outcome.Transaction.Hash, nil
+}
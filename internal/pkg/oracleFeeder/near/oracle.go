@@ -0,0 +1,60 @@
+package near
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Oracle writes quotations into a DIA oracle contract on NEAR. It implements oracleFeeder.Updater.
+type Oracle struct {
+	client     *Client
+	contractID string
+	method     string
+	gas        uint64
+	deposit    *big.Int
+}
+
+// NewOracle returns an oracle calling @method on @contractID with the given gas and
+// deposit (in yoctoNEAR) attached to each call.
+func NewOracle(client *Client, contractID string, method string, gas uint64, deposit *big.Int) *Oracle {
+	return &Oracle{
+		client:     client,
+		contractID: contractID,
+		method:     method,
+		gas:        gas,
+		deposit:    deposit,
+	}
+}
+
+// Update sets the price of @quotation with 8 decimals under its key, e.g. BTC/USD or ETH/EUR.
+func (o *Oracle) Update(quotation *models.Quotation) error {
+	args, err := json.Marshal(struct {
+		Key       string `json:"key"`
+		Value     string `json:"value"`
+		Timestamp int64  `json:"timestamp"`
+	}{
+		Key:       oracleFeeder.Key(quotation),
+		Value:     strconv.FormatInt(int64(quotation.Price*100000000), 10),
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	txHash, err := o.client.FunctionCall(o.contractID, FunctionCall{
+		MethodName: o.method,
+		Args:       args,
+		Gas:        o.gas,
+		Deposit:    o.deposit,
+	})
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"chain": "near", "key": oracleFeeder.Key(quotation), "txHash": txHash}).Info("oracle updated")
+	return nil
+}
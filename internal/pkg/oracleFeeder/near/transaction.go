@@ -0,0 +1,93 @@
+package near
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+)
+
+// Borsh serialization of the NEAR transaction types needed for function calls.
+// See https://nomicon.io/RuntimeSpec/Transactions for the layout.
+
+const (
+	keyTypeED25519     = 0
+	actionFunctionCall = 2
+)
+
+// FunctionCall is a call of a method on a NEAR contract.
+type FunctionCall struct {
+	MethodName string
+	Args       []byte
+	Gas        uint64
+	// Deposit is the amount of yoctoNEAR attached to the call.
+	Deposit *big.Int
+}
+
+// Transaction is a NEAR transaction consisting of function call actions only.
+type Transaction struct {
+	SignerID   string
+	PublicKey  []byte
+	Nonce      uint64
+	ReceiverID string
+	BlockHash  []byte
+	Actions    []FunctionCall
+}
+
+// Serialize returns the borsh encoding of @tx.
+func (tx *Transaction) Serialize() []byte {
+	var buf bytes.Buffer
+	writeString(&buf, tx.SignerID)
+	buf.WriteByte(keyTypeED25519)
+	buf.Write(tx.PublicKey)
+	writeUint64(&buf, tx.Nonce)
+	writeString(&buf, tx.ReceiverID)
+	buf.Write(tx.BlockHash)
+	writeUint32(&buf, uint32(len(tx.Actions)))
+	for _, action := range tx.Actions {
+		buf.WriteByte(actionFunctionCall)
+		writeString(&buf, action.MethodName)
+		writeUint32(&buf, uint32(len(action.Args)))
+		buf.Write(action.Args)
+		writeUint64(&buf, action.Gas)
+		writeUint128(&buf, action.Deposit)
+	}
+	return buf.Bytes()
+}
+
+// serializeSigned returns the borsh encoding of the signed transaction.
+func serializeSigned(serializedTx []byte, signature []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(serializedTx)
+	buf.WriteByte(keyTypeED25519)
+	buf.Write(signature)
+	return buf.Bytes()
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeUint32(buf, uint32(len(s)))
+	buf.WriteString(s)
+}
+
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	buf.Write(b)
+}
+
+func writeUint64(buf *bytes.Buffer, v uint64) {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	buf.Write(b)
+}
+
+// writeUint128 writes @v as 16 byte little endian integer. nil is written as zero.
+func writeUint128(buf *bytes.Buffer, v *big.Int) {
+	b := make([]byte, 16)
+	if v != nil {
+		be := v.Bytes()
+		for i := 0; i < len(be) && i < 16; i++ {
+			b[i] = be[len(be)-1-i]
+		}
+	}
+	buf.Write(b)
+}
@@ -0,0 +1,207 @@
+package oracleFeeder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultQuote is the quote currency of feeds which don't name one.
+const DefaultQuote = "USD"
+
+// ParseFeed splits a feed as given in the symbols of the feeder, e.g. ETH or MATIC/ETH, into
+// its base and quote currency.
+func ParseFeed(feed string) (base string, quote string) {
+	parts := strings.SplitN(feed, "/", 2)
+	if len(parts) == 1 || parts[1] == "" {
+		return parts[0], DefaultQuote
+	}
+	return parts[0], strings.ToUpper(parts[1])
+}
+
+// Key returns the oracle key of @quotation, e.g. BTC/USD or ETH/EUR. The feeder sets the name
+// of quotations to their feed.
+func Key(quotation *models.Quotation) string {
+	if quotation.Name == "" {
+		return quotation.Symbol + "/" + DefaultQuote
+	}
+	base, quote := ParseFeed(quotation.Name)
+	return base + "/" + quote
+}
+
+// GetFeedQuotation returns the quotation of @feed, e.g. ETH or MATIC/ETH, with the USD
+// quotations of its base and quote currency from @getQuotation. The quotation is named after the
+// feed, so that Key returns its oracle key. Unlike in the Feeder, the quote currency is priced by
+// its DIA quotation, so fiat currencies can't be quote currencies.
+func GetFeedQuotation(feed string, getQuotation func(symbol string) (*models.Quotation, error)) (*models.Quotation, error) {
+	base, quote := ParseFeed(feed)
+	quotation, err := getQuotation(base)
+	if err != nil {
+		return nil, err
+	}
+	quotation.Name = quotation.Symbol + "/" + quote
+	if quote == DefaultQuote {
+		return quotation, nil
+	}
+	quoteQuotation, err := getQuotation(quote)
+	if err != nil {
+		return nil, fmt.Errorf("quote currency %s: %v", quote, err)
+	}
+	if quoteQuotation.Price <= 0 {
+		return nil, fmt.Errorf("no price for quote currency %s", quote)
+	}
+	quotation.Price /= quoteQuotation.Price
+	return quotation, nil
+}
+
+// isFiat returns true if @symbol is one of the fiat currencies of the feeder.
+func (f *Feeder) isFiat(symbol string) bool {
+	for _, s := range f.config.FiatSymbols {
+		if strings.EqualFold(s, symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// price returns the USD quotation of @symbol at @now. Fiat currencies are priced by the foreign
+// quotations of the fiat source and fail if their rate is older than the maximum age. All other
+// symbols are priced by the DIA quotation, or its time-weighted average if the feeder pushes
+// averages.
+func (f *Feeder) price(symbol string, now time.Time) (*models.Quotation, error) {
+	if f.isFiat(symbol) {
+		fq, err := GetForeignQuotationFromDia(f.config.FiatSource, symbol)
+		if err != nil {
+			return nil, err
+		}
+		if fq.Price <= 0 {
+			return nil, fmt.Errorf("no rate for %s", symbol)
+		}
+		if f.config.FiatMaxAge > 0 && now.Sub(fq.Time) > f.config.FiatMaxAge {
+			return nil, fmt.Errorf("rate of %s from %s is stale, last published at %s", symbol, f.config.FiatSource, fq.Time.Format(time.RFC3339))
+		}
+		return &models.Quotation{Symbol: fq.Symbol, Name: fq.Name, Price: fq.Price, Source: fq.Source, Time: fq.Time, ITIN: fq.ITIN}, nil
+	}
+
+	var quotation *models.Quotation
+	if prefetched, ok := f.prefetched[strings.ToUpper(symbol)]; ok {
+		copied := *prefetched
+		quotation = &copied
+	} else {
+		var err error
+		quotation, err = GetQuotationFromDia(symbol)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if f.config.TWAPWindow > 0 {
+		twap, err := GetTWAPFromDia(symbol, f.config.TWAPFilter, f.config.TWAPWindow, now)
+		if err != nil {
+			return nil, fmt.Errorf("twap: %v", err)
+		}
+		quotation.Price = twap
+	}
+	if quotation.Price <= 0 {
+		return nil, fmt.Errorf("no price for %s", symbol)
+	}
+	return quotation, nil
+}
+
+// prefetch fetches the quotations of all base and quote currencies of the fed symbols in
+// batches, which price then uses during the cycle instead of requesting each symbol. Symbols
+// missing from the batch response and failed batches fall back to single requests.
+func (f *Feeder) prefetch() {
+	f.mu.Lock()
+	seen := map[string]bool{DefaultQuote: true}
+	var symbols []string
+	for _, feed := range f.config.Symbols {
+		base, quote := ParseFeed(feed)
+		for _, symbol := range []string{base, quote} {
+			if seen[symbol] || f.isFiat(symbol) {
+				continue
+			}
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	f.mu.Unlock()
+
+	prefetched := make(map[string]*models.Quotation)
+	for start := 0; start < len(symbols); start += quotationBatchSize {
+		end := start + quotationBatchSize
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+		quotations, err := GetQuotationsFromDia(symbols[start:end])
+		if err != nil {
+			log.Warnf("batch quotations failed, requesting symbols one by one: %v", err)
+			continue
+		}
+		for _, quotation := range quotations {
+			prefetched[strings.ToUpper(quotation.Symbol)] = quotation
+		}
+	}
+	f.mu.Lock()
+	f.prefetched = prefetched
+	f.mu.Unlock()
+}
+
+// quotationBatchSize is the maximal number of symbols of a request to the batch quotation
+// endpoint of the DIA API.
+const quotationBatchSize = 100
+
+// GetQuotationsFromDia returns the latest quotations of @symbols from the batch quotation
+// endpoint of the DIA API. Symbols unknown to the API are missing from the result.
+func GetQuotationsFromDia(symbols []string) ([]*models.Quotation, error) {
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/quotations?symbols=" + url.QueryEscape(strings.ToUpper(strings.Join(symbols, ","))))
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Error on dia api with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var quotations []*models.Quotation
+	err = json.Unmarshal(contents, &quotations)
+	if err != nil {
+		return nil, err
+	}
+	return quotations, nil
+}
+
+// GetForeignQuotationFromDia returns the latest quotation of @symbol from @source as served by
+// the foreign quotation endpoint of the DIA API.
+func GetForeignQuotationFromDia(source string, symbol string) (*models.ForeignQuotation, error) {
+	response, err := utils.GetWithBackoff(dia.BaseUrl + "/v1/foreignQuotation/" + source + "/" + strings.ToUpper(symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("Error on dia api with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	var quotation models.ForeignQuotation
+	err = quotation.UnmarshalBinary(contents)
+	if err != nil {
+		return nil, err
+	}
+	return &quotation, nil
+}
@@ -0,0 +1,54 @@
+package oracleFeeder
+
+import (
+	"math"
+	"testing"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func TestKey(t *testing.T) {
+	cases := []struct {
+		quotation models.Quotation
+		expected  string
+	}{
+		{models.Quotation{Symbol: "BTC"}, "BTC/USD"},
+		{models.Quotation{Symbol: "BTC", Name: "BTC"}, "BTC/USD"},
+		{models.Quotation{Symbol: "ETH", Name: "ETH/eur"}, "ETH/EUR"},
+		{models.Quotation{Symbol: "MATIC", Name: "MATIC/ETH"}, "MATIC/ETH"},
+		{models.Quotation{Symbol: "DIA", Name: "DIA/"}, "DIA/USD"},
+	}
+	for _, c := range cases {
+		key := Key(&c.quotation)
+		if key != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, key)
+		}
+	}
+}
+
+func TestGetFeedQuotation(t *testing.T) {
+	prices := map[string]float64{"MATIC": 2, "ETH": 4000}
+	getQuotation := func(symbol string) (*models.Quotation, error) {
+		return &models.Quotation{Symbol: symbol, Price: prices[symbol]}, nil
+	}
+	cases := []struct {
+		feed     string
+		key      string
+		expected float64
+	}{
+		{"MATIC", "MATIC/USD", 2},
+		{"MATIC/eth", "MATIC/ETH", 0.0005},
+	}
+	for _, c := range cases {
+		quotation, err := GetFeedQuotation(c.feed, getQuotation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Key(quotation) != c.key || math.Abs(quotation.Price-c.expected) > 1e-12 {
+			t.Errorf("expected %s at %v, got %s at %v", c.key, c.expected, Key(quotation), quotation.Price)
+		}
+	}
+	if _, err := GetFeedQuotation("MATIC/DIA", getQuotation); err == nil {
+		t.Errorf("expected error for quote currency without price")
+	}
+}
@@ -0,0 +1,148 @@
+package randomness
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+// Flags of the first byte of points in the compressed encoding of zcash, which drand uses.
+const (
+	flagCompressed = 0x80
+	flagInfinity   = 0x40
+	flagLargestY   = 0x20
+)
+
+// curveB is the coefficient b of y^2 = x^3 + b of G1, G2 uses b*(1+i).
+var curveB = big.NewInt(4)
+
+// fp2 is an element c0 + c1*i of the quadratic extension of the base field, with i^2 = -1.
+type fp2 struct {
+	c0, c1 *big.Int
+}
+
+func fpMod(x *big.Int) *big.Int {
+	return x.Mod(x, fieldModulus)
+}
+
+func (a fp2) mul(b fp2) fp2 {
+	return fp2{
+		c0: fpMod(new(big.Int).Sub(new(big.Int).Mul(a.c0, b.c0), new(big.Int).Mul(a.c1, b.c1))),
+		c1: fpMod(new(big.Int).Add(new(big.Int).Mul(a.c0, b.c1), new(big.Int).Mul(a.c1, b.c0))),
+	}
+}
+
+func (a fp2) add(b fp2) fp2 {
+	return fp2{c0: fpMod(new(big.Int).Add(a.c0, b.c0)), c1: fpMod(new(big.Int).Add(a.c1, b.c1))}
+}
+
+// sqrt returns a square root of @a, or false if @a is no square. As p = 3 mod 4, the root of
+// c0 + c1*i is x0 + x1*i with x0^2 = (c0 + sqrt(c0^2 + c1^2)) / 2 and x1 = c1 / (2*x0).
+func (a fp2) sqrt() (fp2, bool) {
+	if a.c1.Sign() == 0 {
+		if root := new(big.Int).ModSqrt(a.c0, fieldModulus); root != nil {
+			return fp2{c0: root, c1: new(big.Int)}, true
+		}
+		root := new(big.Int).ModSqrt(fpMod(new(big.Int).Neg(a.c0)), fieldModulus)
+		if root == nil {
+			return fp2{}, false
+		}
+		return fp2{c0: new(big.Int), c1: root}, true
+	}
+	norm := fpMod(new(big.Int).Add(new(big.Int).Mul(a.c0, a.c0), new(big.Int).Mul(a.c1, a.c1)))
+	gamma := new(big.Int).ModSqrt(norm, fieldModulus)
+	if gamma == nil {
+		return fp2{}, false
+	}
+	half := new(big.Int).ModInverse(big.NewInt(2), fieldModulus)
+	delta := fpMod(new(big.Int).Mul(new(big.Int).Add(a.c0, gamma), half))
+	x0 := new(big.Int).ModSqrt(delta, fieldModulus)
+	if x0 == nil {
+		delta = fpMod(new(big.Int).Mul(new(big.Int).Sub(a.c0, gamma), half))
+		x0 = new(big.Int).ModSqrt(delta, fieldModulus)
+		if x0 == nil {
+			return fp2{}, false
+		}
+	}
+	x1 := fpMod(new(big.Int).Mul(a.c1, new(big.Int).ModInverse(new(big.Int).Lsh(x0, 1), fieldModulus)))
+	return fp2{c0: x0, c1: x1}, true
+}
+
+// fpLargest returns true if @y is larger than its negation.
+func fpLargest(y *big.Int) bool {
+	return y.Cmp(new(big.Int).Sub(fieldModulus, y)) > 0
+}
+
+// fpBytes returns the 48 bytes big endian encoding of @x.
+func fpBytes(x *big.Int) []byte {
+	out := make([]byte, 48)
+	b := x.Bytes()
+	copy(out[48-len(b):], b)
+	return out
+}
+
+// decompressedX returns the x coordinate of the compressed point @in of @size bytes, which is
+// split into elements of 48 bytes, and its flags.
+func decompressedX(in []byte, size int) (x []*big.Int, largestY bool, err error) {
+	if len(in) != size {
+		return nil, false, errors.New("invalid length of compressed point")
+	}
+	if in[0]&flagCompressed == 0 {
+		return nil, false, errors.New("point isn't compressed")
+	}
+	if in[0]&flagInfinity != 0 {
+		return nil, false, errors.New("point at infinity")
+	}
+	raw := append([]byte{in[0] &^ (flagCompressed | flagInfinity | flagLargestY)}, in[1:]...)
+	for i := 0; i < size; i += 48 {
+		element := new(big.Int).SetBytes(raw[i : i+48])
+		if element.Cmp(fieldModulus) >= 0 {
+			return nil, false, errors.New("coordinate isn't in the field")
+		}
+		x = append(x, element)
+	}
+	return x, in[0]&flagLargestY != 0, nil
+}
+
+// decompressG1 decodes a point of G1 in the compressed encoding of zcash, y^2 = x^3 + 4.
+func decompressG1(g *bls12381.G1, in []byte) (*bls12381.PointG1, error) {
+	coordinates, largestY, err := decompressedX(in, 48)
+	if err != nil {
+		return nil, err
+	}
+	x := coordinates[0]
+	y2 := fpMod(new(big.Int).Add(new(big.Int).Exp(x, big.NewInt(3), fieldModulus), curveB))
+	y := new(big.Int).ModSqrt(y2, fieldModulus)
+	if y == nil {
+		return nil, errors.New("point is not on curve")
+	}
+	if fpLargest(y) != largestY {
+		y.Sub(fieldModulus, y)
+	}
+	return g.FromBytes(append(fpBytes(x), fpBytes(y)...))
+}
+
+// decompressG2 decodes a point of G2 in the compressed encoding of zcash, which stores x1
+// before x0, y^2 = x^3 + 4(1+i).
+func decompressG2(g *bls12381.G2, in []byte) (*bls12381.PointG2, error) {
+	coordinates, largestY, err := decompressedX(in, 96)
+	if err != nil {
+		return nil, err
+	}
+	x := fp2{c0: coordinates[1], c1: coordinates[0]}
+	y, ok := x.mul(x).mul(x).add(fp2{c0: curveB, c1: curveB}).sqrt()
+	if !ok {
+		return nil, errors.New("point is not on curve")
+	}
+	largest := fpLargest(y.c1)
+	if y.c1.Sign() == 0 {
+		largest = fpLargest(y.c0)
+	}
+	if largest != largestY {
+		y = fp2{c0: fpMod(new(big.Int).Neg(y.c0)), c1: fpMod(new(big.Int).Neg(y.c1))}
+	}
+	out := append(fpBytes(x.c1), fpBytes(x.c0)...)
+	out = append(out, fpBytes(y.c1)...)
+	return g.FromBytes(append(out, fpBytes(y.c0)...))
+}
@@ -0,0 +1,215 @@
+// Package randomness publishes verifiable randomness from a drand beacon into oracle
+// contracts. Each round is verified against the public key of the drand network before it is
+// written, so that a compromised relay can't inject randomness.
+package randomness
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+const (
+	// DefaultDrandURL is the public relay of the League of Entropy.
+	DefaultDrandURL = "https://api.drand.sh"
+	// QuicknetChainHash is the chain hash of the quicknet network of the League of Entropy,
+	// which publishes a round every 3 seconds.
+	QuicknetChainHash = "52db9ba70e0cc0f6eaf7803dd07447a1f5477735fd3f661792ba94600c84e971"
+	// SchemeUnchainedG1 is the scheme of networks signing the round number only, with
+	// signatures on G1 and public keys on G2, as quicknet does.
+	SchemeUnchainedG1 = "bls-unchained-g1-rfc9380"
+
+	// signatureDST is the domain separation tag of signatures of SchemeUnchainedG1.
+	signatureDST = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"
+)
+
+// ChainInfo describes a drand network.
+type ChainInfo struct {
+	PublicKey   []byte
+	Period      time.Duration
+	GenesisTime time.Time
+	Hash        string
+	Scheme      string
+}
+
+// Beacon is one round of a drand network.
+type Beacon struct {
+	Round      uint64
+	Randomness []byte
+	Signature  []byte
+}
+
+// Drand reads a drand network from an HTTP relay.
+type Drand struct {
+	url       string
+	chainHash string
+}
+
+// NewDrand returns a client of the network with @chainHash served by the relay at @url.
+func NewDrand(url string, chainHash string) *Drand {
+	return &Drand{url: strings.TrimSuffix(url, "/"), chainHash: chainHash}
+}
+
+// Info returns the parameters of the network.
+func (d *Drand) Info() (ChainInfo, error) {
+	var raw struct {
+		PublicKey   string `json:"public_key"`
+		Period      int64  `json:"period"`
+		GenesisTime int64  `json:"genesis_time"`
+		Hash        string `json:"hash"`
+		SchemeID    string `json:"schemeID"`
+	}
+	err := d.get("/info", &raw)
+	if err != nil {
+		return ChainInfo{}, err
+	}
+	if raw.Hash != d.chainHash {
+		return ChainInfo{}, fmt.Errorf("relay serves chain %s instead of %s", raw.Hash, d.chainHash)
+	}
+	publicKey, err := hex.DecodeString(raw.PublicKey)
+	if err != nil {
+		return ChainInfo{}, fmt.Errorf("public key: %v", err)
+	}
+	return ChainInfo{
+		PublicKey:   publicKey,
+		Period:      time.Duration(raw.Period) * time.Second,
+		GenesisTime: time.Unix(raw.GenesisTime, 0),
+		Hash:        raw.Hash,
+		Scheme:      raw.SchemeID,
+	}, nil
+}
+
+// Latest returns the latest round of the network.
+func (d *Drand) Latest() (Beacon, error) {
+	return d.beacon("/public/latest")
+}
+
+// Round returns @round of the network.
+func (d *Drand) Round(round uint64) (Beacon, error) {
+	return d.beacon(fmt.Sprintf("/public/%d", round))
+}
+
+func (d *Drand) beacon(path string) (Beacon, error) {
+	var raw struct {
+		Round      uint64 `json:"round"`
+		Randomness string `json:"randomness"`
+		Signature  string `json:"signature"`
+	}
+	err := d.get(path, &raw)
+	if err != nil {
+		return Beacon{}, err
+	}
+	randomness, err := hex.DecodeString(raw.Randomness)
+	if err != nil {
+		return Beacon{}, fmt.Errorf("randomness of round %d: %v", raw.Round, err)
+	}
+	signature, err := hex.DecodeString(raw.Signature)
+	if err != nil {
+		return Beacon{}, fmt.Errorf("signature of round %d: %v", raw.Round, err)
+	}
+	return Beacon{Round: raw.Round, Randomness: randomness, Signature: signature}, nil
+}
+
+func (d *Drand) get(path string, v interface{}) error {
+	response, err := utils.GetWithBackoff(d.url + "/" + d.chainHash + path)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return fmt.Errorf("Error on drand relay with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(contents, v)
+}
+
+// Verifier verifies rounds of a drand network of SchemeUnchainedG1.
+type Verifier struct {
+	g1        *bls12381.G1
+	g2        *bls12381.G2
+	publicKey *bls12381.PointG2
+}
+
+// NewVerifier returns the verifier of rounds of the network described by @info.
+func NewVerifier(info ChainInfo) (*Verifier, error) {
+	if info.Scheme != SchemeUnchainedG1 {
+		return nil, fmt.Errorf("unsupported drand scheme %s, expected %s", info.Scheme, SchemeUnchainedG1)
+	}
+	g2 := bls12381.NewG2()
+	publicKey, err := decompressG2(g2, info.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %v", err)
+	}
+	if !g2.InCorrectSubgroup(publicKey) {
+		return nil, errors.New("public key isn't in the G2 subgroup")
+	}
+	return &Verifier{g1: bls12381.NewG1(), g2: g2, publicKey: publicKey}, nil
+}
+
+// Verify returns nil if @b is signed by the network and its randomness is derived from the
+// signature.
+func (v *Verifier) Verify(b Beacon) error {
+	digest := sha256.Sum256(b.Signature)
+	if !bytes.Equal(digest[:], b.Randomness) {
+		return fmt.Errorf("randomness of round %d isn't the hash of its signature", b.Round)
+	}
+	signature, err := decompressG1(v.g1, b.Signature)
+	if err != nil {
+		return fmt.Errorf("signature of round %d: %v", b.Round, err)
+	}
+	if !v.g1.InCorrectSubgroup(signature) {
+		return fmt.Errorf("signature of round %d isn't in the G1 subgroup", b.Round)
+	}
+	message, err := v.hashToG1(roundMessage(b.Round))
+	if err != nil {
+		return err
+	}
+	// e(signature, g2) == e(H(m), publicKey)
+	engine := bls12381.NewPairingEngine()
+	engine.AddPair(signature, v.g2.One())
+	engine.AddPairInv(message, v.publicKey)
+	if !engine.Check() {
+		return fmt.Errorf("invalid signature of round %d", b.Round)
+	}
+	return nil
+}
+
+// hashToG1 implements hash_to_curve of the suite BLS12381G1_XMD:SHA-256_SSWU_RO_ of RFC 9380.
+// MapToCurve clears the cofactor of each point, which is linear and thus equivalent to
+// clearing it once for their sum.
+func (v *Verifier) hashToG1(msg []byte) (*bls12381.PointG1, error) {
+	u, err := hashToField(msg, []byte(signatureDST), 2)
+	if err != nil {
+		return nil, err
+	}
+	q0, err := v.g1.MapToCurve(u[0])
+	if err != nil {
+		return nil, err
+	}
+	q1, err := v.g1.MapToCurve(u[1])
+	if err != nil {
+		return nil, err
+	}
+	return v.g1.Add(v.g1.New(), q0, q1), nil
+}
+
+// roundMessage returns the message signed in @round by networks of SchemeUnchainedG1.
+func roundMessage(round uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], round)
+	digest := sha256.Sum256(b[:])
+	return digest[:]
+}
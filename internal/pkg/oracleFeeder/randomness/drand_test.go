@@ -0,0 +1,46 @@
+package randomness
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Public key and round 1000 of quicknet, as served by https://api.drand.sh.
+const (
+	quicknetPublicKey  = "83cf0f2896adee7eb8b5f01fcad3912212c437e0073e911fb90022d3e760183c8c4b450b6a0a6c3ac6a5776a2d1064510d1fec758c921cc22b0e17e63aaf4bcb5ed66304de9cf809bd274ca73bab4af5a6e9c76a4bc09e76eae8991ef5ece45a"
+	quicknetSignature  = "b44679b9a59af2ec876b1a6b1ad52ea9b1615fc3982b19576350f93447cb1125e342b73a8dd2bacbe47e4b6b63ed5e39"
+	quicknetRandomness = "fe290beca10872ef2fb164d2aa4442de4566183ec51c56ff3cd603d930e54fdd"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestVerifyQuicknetRound(t *testing.T) {
+	verifier, err := NewVerifier(ChainInfo{
+		PublicKey: mustDecodeHex(t, quicknetPublicKey),
+		Hash:      QuicknetChainHash,
+		Scheme:    SchemeUnchainedG1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	beacon := Beacon{
+		Round:      1000,
+		Randomness: mustDecodeHex(t, quicknetRandomness),
+		Signature:  mustDecodeHex(t, quicknetSignature),
+	}
+	if err := verifier.Verify(beacon); err != nil {
+		t.Errorf("got %v, expected round %d to verify", err, beacon.Round)
+	}
+
+	// The same signature must not verify another round.
+	beacon.Round = 1001
+	if err := verifier.Verify(beacon); err == nil {
+		t.Errorf("got no error, expected round %d to fail", beacon.Round)
+	}
+}
@@ -0,0 +1,112 @@
+package randomness
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/diadata-org/diadata/pkg/selftest"
+	log "github.com/sirupsen/logrus"
+)
+
+var roundsTotal = metrics.NewCounter("oraclefeeder", "randomness_rounds", "Published drand rounds by result.", "result")
+
+// Publisher writes a verified round on-chain.
+type Publisher interface {
+	Publish(b Beacon) error
+	// LastRound returns the latest round written on-chain, 0 if there is none.
+	LastRound() (uint64, error)
+}
+
+// Feeder periodically publishes the latest round of a drand network.
+type Feeder struct {
+	drand     *Drand
+	verifier  *Verifier
+	publisher Publisher
+	frequency time.Duration
+	// last is the latest round written on-chain.
+	last uint64
+}
+
+// NewFeeder returns a feeder publishing the latest round of @drand through @publisher every
+// @frequency. A non-empty @publicKey pins the public key of the network, which is otherwise
+// taken from the relay.
+func NewFeeder(drand *Drand, publicKey []byte, publisher Publisher, frequency time.Duration) (*Feeder, error) {
+	info, err := drand.Info()
+	if err != nil {
+		return nil, err
+	}
+	if len(publicKey) > 0 && !bytes.Equal(publicKey, info.PublicKey) {
+		return nil, fmt.Errorf("relay serves public key %x instead of %x", info.PublicKey, publicKey)
+	}
+	verifier, err := NewVerifier(info)
+	if err != nil {
+		return nil, err
+	}
+	return &Feeder{drand: drand, verifier: verifier, publisher: publisher, frequency: frequency}, nil
+}
+
+// Checks implements selftest.Checker. It verifies the latest round of the relay and adds the
+// checks of the publisher.
+func (f *Feeder) Checks() []selftest.Check {
+	checks := []selftest.Check{{Name: "drand latest round", Run: func() error {
+		b, err := f.drand.Latest()
+		if err != nil {
+			return err
+		}
+		return f.verifier.Verify(b)
+	}}}
+	return append(checks, selftest.Collect("", f.publisher)...)
+}
+
+// Run publishes the latest round every frequency. It blocks forever.
+func (f *Feeder) Run() {
+	ticker := time.NewTicker(f.frequency)
+	for {
+		err := f.Update()
+		if err != nil {
+			log.Errorf("publishing randomness: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+// Update publishes the latest round unless it is already on-chain.
+func (f *Feeder) Update() error {
+	if f.last == 0 {
+		last, err := f.publisher.LastRound()
+		if err != nil {
+			return fmt.Errorf("last round: %v", err)
+		}
+		f.last = last
+	}
+	b, err := f.drand.Latest()
+	if err != nil {
+		roundsTotal.Inc("source_error")
+		return err
+	}
+	if b.Round <= f.last {
+		return nil
+	}
+	err = f.verifier.Verify(b)
+	if err != nil {
+		roundsTotal.Inc("invalid")
+		return err
+	}
+	err = f.publisher.Publish(b)
+	if errors.Is(err, oracleFeeder.ErrSkipped) {
+		roundsTotal.Inc("skipped")
+		log.WithField("round", b.Round).Warnf("publishing skipped: %v", err)
+		return nil
+	}
+	if err != nil {
+		roundsTotal.Inc("error")
+		return err
+	}
+	roundsTotal.Inc("success")
+	f.last = b.Round
+	return nil
+}
@@ -0,0 +1,73 @@
+package randomness
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// fieldModulus is the modulus p of the base field of BLS12-381.
+var fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+const (
+	// fieldElementSize is the size of an encoded element of the base field.
+	fieldElementSize = 48
+	// hashToFieldSize is the number of uniform bytes reduced to one field element, which is
+	// L = ceil((ceil(log2(p)) + k) / 8) for the security level k = 128 of RFC 9380.
+	hashToFieldSize = 64
+)
+
+// expandMessageXMD implements expand_message_xmd of RFC 9380, section 5.3.1, with SHA-256.
+func expandMessageXMD(msg []byte, dst []byte, length int) ([]byte, error) {
+	ell := (length + sha256.Size - 1) / sha256.Size
+	if ell > 255 || length > 65535 || len(dst) > 255 {
+		return nil, errors.New("expand_message_xmd: invalid length")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	uniform := append(make([]byte, 0, ell*sha256.Size), bi...)
+	for i := 2; i <= ell; i++ {
+		xored := make([]byte, sha256.Size)
+		for j := range xored {
+			xored[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(xored)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		uniform = append(uniform, bi...)
+	}
+	return uniform[:length], nil
+}
+
+// hashToField implements hash_to_field of RFC 9380, section 5.2, for @count elements of the
+// base field of BLS12-381. The elements are returned big-endian encoded.
+func hashToField(msg []byte, dst []byte, count int) ([][]byte, error) {
+	uniform, err := expandMessageXMD(msg, dst, count*hashToFieldSize)
+	if err != nil {
+		return nil, err
+	}
+	elements := make([][]byte, count)
+	for i := range elements {
+		e := new(big.Int).SetBytes(uniform[i*hashToFieldSize : (i+1)*hashToFieldSize])
+		e.Mod(e, fieldModulus)
+		elements[i] = make([]byte, fieldElementSize)
+		b := e.Bytes()
+		copy(elements[i][fieldElementSize-len(b):], b)
+	}
+	return elements, nil
+}
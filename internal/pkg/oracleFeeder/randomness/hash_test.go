@@ -0,0 +1,39 @@
+package randomness
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors of RFC 9380, appendices K.1 and J.9.1.
+func TestExpandMessageXMD(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	for msg, expected := range map[string]string{
+		"":    "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		"abc": "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+	} {
+		uniform, err := expandMessageXMD([]byte(msg), dst, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(uniform) != expected {
+			t.Errorf("expandMessageXMD(%q) = %x, expected %s", msg, uniform, expected)
+		}
+	}
+}
+
+func TestHashToField(t *testing.T) {
+	elements, err := hashToField([]byte(""), []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"0ba14bd907ad64a016293ee7c2d276b8eae71f25a4b941eece7b0d89f17f75cb3ae5438a614fb61d6835ad59f29c564f",
+		"019b9bd7979f12657976de2884c7cce192b82c177c80e0ec604436a7f538d231552f0d96d9f7babe5fa3b19b3ff25ac9",
+	}
+	for i := range expected {
+		if hex.EncodeToString(elements[i]) != expected[i] {
+			t.Errorf("element %d = %x, expected %s", i, elements[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package starknet
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+var (
+	invokePrefix = new(big.Int).SetBytes([]byte("invoke"))
+	// queryVersionOffset is added to the transaction version for fee estimations, so that
+	// the signature of an estimation can't be replayed as a transaction.
+	queryVersionOffset = new(big.Int).Lsh(big.NewInt(1), 128)
+	transactionVersion = big.NewInt(1)
+)
+
+// Call is a function call executed through the account contract.
+type Call struct {
+	To       *big.Int
+	Selector *big.Int
+	Calldata []*big.Int
+}
+
+// Account signs invoke transactions of an account contract. StarkNet has no externally
+// owned accounts, so all calls are executed through the __execute__ entry point of the
+// account, which checks the signature in __validate__.
+type Account struct {
+	client     *Client
+	address    *big.Int
+	privateKey *big.Int
+	chainID    *big.Int
+	// cairoVersion of the account contract determines the encoding of the calls.
+	cairoVersion int
+	// feeMultiplierPercent is applied to the estimated fee to get the maximal fee.
+	feeMultiplierPercent int64
+	// maxFee is the upper bound of the fee paid by a single transaction, nil for no bound.
+	maxFee *big.Int
+	// nonce of the last submitted transaction. It is reset after failures
+	// so that it is read from the chain again.
+	nonce   *big.Int
+	nonceMu sync.Mutex
+}
+
+// NewAccount returns the account at @address signing with @privateKey.
+func NewAccount(client *Client, address *big.Int, privateKey *big.Int, cairoVersion int, maxFee *big.Int) (*Account, error) {
+	if cairoVersion != 0 && cairoVersion != 1 {
+		return nil, errors.New("cairo version of the account must be 0 or 1")
+	}
+	chainID, err := client.ChainID()
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		client:               client,
+		address:              address,
+		privateKey:           privateKey,
+		chainID:              chainID,
+		cairoVersion:         cairoVersion,
+		feeMultiplierPercent: 150,
+		maxFee:               maxFee,
+	}, nil
+}
+
+// ExecuteCalldata encodes @calls as calldata of __execute__ for an account written in
+// Cairo @cairoVersion.
+func ExecuteCalldata(calls []Call, cairoVersion int) []*big.Int {
+	calldata := []*big.Int{big.NewInt(int64(len(calls)))}
+	if cairoVersion == 1 {
+		for _, call := range calls {
+			calldata = append(calldata, call.To, call.Selector, big.NewInt(int64(len(call.Calldata))))
+			calldata = append(calldata, call.Calldata...)
+		}
+		return calldata
+	}
+	// Cairo 0 accounts take an array of call descriptions followed by the concatenated calldata.
+	var data []*big.Int
+	for _, call := range calls {
+		calldata = append(calldata, call.To, call.Selector, big.NewInt(int64(len(data))), big.NewInt(int64(len(call.Calldata))))
+		data = append(data, call.Calldata...)
+	}
+	calldata = append(calldata, big.NewInt(int64(len(data))))
+	return append(calldata, data...)
+}
+
+// InvokeHash returns the hash of a version @version invoke transaction.
+func InvokeHash(sender *big.Int, calldata []*big.Int, maxFee *big.Int, chainID *big.Int, nonce *big.Int, version *big.Int) *big.Int {
+	return PedersenArray(
+		invokePrefix,
+		version,
+		sender,
+		big.NewInt(0),
+		PedersenArray(calldata...),
+		maxFee,
+		chainID,
+		nonce,
+	)
+}
+
+// signedTransaction returns the invoke transaction of @calldata signed by the account.
+func (a *Account) signedTransaction(calldata []*big.Int, maxFee *big.Int, nonce *big.Int, version *big.Int) (InvokeTransaction, error) {
+	hash := InvokeHash(a.address, calldata, maxFee, a.chainID, nonce, version)
+	r, s, err := Sign(hash, a.privateKey)
+	if err != nil {
+		return InvokeTransaction{}, err
+	}
+	return InvokeTransaction{
+		Type:          "INVOKE",
+		SenderAddress: toHex(a.address),
+		Calldata:      toHexSlice(calldata),
+		MaxFee:        toHex(maxFee),
+		Version:       toHex(version),
+		Signature:     toHexSlice([]*big.Int{r, s}),
+		Nonce:         toHex(nonce),
+	}, nil
+}
+
+// Execute estimates the fee of @calls, then signs and submits them in one transaction.
+// It returns the transaction hash and the maximal fee of the transaction.
+func (a *Account) Execute(calls []Call) (string, *big.Int, error) {
+	a.nonceMu.Lock()
+	defer a.nonceMu.Unlock()
+
+	nonce, err := a.client.GetNonce(a.address)
+	if err != nil {
+		return "", nil, err
+	}
+	// The pending block may not yet contain our last transaction, so never reuse a nonce.
+	if a.nonce != nil && a.nonce.Cmp(nonce) >= 0 {
+		nonce = new(big.Int).Add(a.nonce, big.NewInt(1))
+	}
+	calldata := ExecuteCalldata(calls, a.cairoVersion)
+
+	queryVersion := new(big.Int).Add(queryVersionOffset, transactionVersion)
+	query, err := a.signedTransaction(calldata, big.NewInt(0), nonce, queryVersion)
+	if err != nil {
+		return "", nil, err
+	}
+	fee, err := a.client.EstimateFee(query)
+	if err != nil {
+		return "", nil, err
+	}
+	maxFee := new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(a.feeMultiplierPercent)), big.NewInt(100))
+	if a.maxFee != nil && maxFee.Cmp(a.maxFee) > 0 {
+		if fee.Cmp(a.maxFee) > 0 {
+			return "", nil, errors.New("estimated fee " + fee.String() + " exceeds maximal fee " + a.maxFee.String())
+		}
+		maxFee = a.maxFee
+	}
+
+	tx, err := a.signedTransaction(calldata, maxFee, nonce, transactionVersion)
+	if err != nil {
+		return "", nil, err
+	}
+	hash, err := a.client.AddInvokeTransaction(tx)
+	if err != nil {
+		a.nonce = nil
+		return hash, maxFee, err
+	}
+	a.nonce = nonce
+	return hash, maxFee, nil
+}
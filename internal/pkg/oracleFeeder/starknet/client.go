@@ -0,0 +1,133 @@
+package starknet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// Client talks to the JSON-RPC API of a StarkNet full node.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the JSON-RPC node at @url.
+func NewClient(url string) *Client {
+	return &Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("starknet rpc error %d: %s %s", e.Code, e.Message, string(e.Data))
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// call performs the JSON-RPC call @method and unmarshals its result into @result.
+func (c *Client) call(method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	response, err := c.httpClient.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	var resp rpcResponse
+	err = json.Unmarshal(contents, &resp)
+	if err != nil {
+		return fmt.Errorf("starknet rpc returned status %d: %v", response.StatusCode, err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// callFelt performs a call whose result is a single hex encoded felt.
+func (c *Client) callFelt(method string, params interface{}) (*big.Int, error) {
+	var result string
+	err := c.call(method, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFelt(result)
+}
+
+// ChainID returns the chain id of the network, e.g. SN_MAIN encoded as felt.
+func (c *Client) ChainID() (*big.Int, error) {
+	return c.callFelt("starknet_chainId", []interface{}{})
+}
+
+// GetNonce returns the nonce of the account at @address in the pending block.
+func (c *Client) GetNonce(address *big.Int) (*big.Int, error) {
+	return c.callFelt("starknet_getNonce", []interface{}{"pending", toHex(address)})
+}
+
+// InvokeTransaction is a version 1 invoke transaction of an account contract.
+type InvokeTransaction struct {
+	Type          string   `json:"type"`
+	SenderAddress string   `json:"sender_address"`
+	Calldata      []string `json:"calldata"`
+	MaxFee        string   `json:"max_fee"`
+	Version       string   `json:"version"`
+	Signature     []string `json:"signature"`
+	Nonce         string   `json:"nonce"`
+}
+
+// FeeEstimate is the estimated fee of a transaction in wei.
+type FeeEstimate struct {
+	GasConsumed string `json:"gas_consumed"`
+	GasPrice    string `json:"gas_price"`
+	OverallFee  string `json:"overall_fee"`
+}
+
+// EstimateFee returns the overall fee of @tx, which has to be signed with the query version.
+func (c *Client) EstimateFee(tx InvokeTransaction) (*big.Int, error) {
+	var estimates []FeeEstimate
+	err := c.call("starknet_estimateFee", []interface{}{[]InvokeTransaction{tx}, "pending"}, &estimates)
+	if err != nil {
+		return nil, err
+	}
+	if len(estimates) != 1 {
+		return nil, fmt.Errorf("expected one fee estimate, got %d", len(estimates))
+	}
+	return ParseFelt(estimates[0].OverallFee)
+}
+
+// AddInvokeTransaction submits the signed transaction @tx and returns its hash.
+func (c *Client) AddInvokeTransaction(tx InvokeTransaction) (string, error) {
+	var result struct {
+		TransactionHash string `json:"transaction_hash"`
+	}
+	err := c.call("starknet_addInvokeTransaction", []interface{}{tx}, &result)
+	return result.TransactionHash, err
+}
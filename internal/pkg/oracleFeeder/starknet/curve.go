@@ -0,0 +1,154 @@
+package starknet
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// Parameters of the STARK curve y^2 = x^3 + alpha*x + beta over the field of felts.
+var (
+	FieldPrime = hexInt("0x800000000000011000000000000000000000000000000000000000000000001")
+	curveAlpha = big.NewInt(1)
+	curveBeta  = hexInt("0x6f21413efbe40de150e596d72f7a8c5609ad26c15c915c1f4cdfcb99cee9e89")
+	curveOrder = hexInt("0x800000000000010ffffffffffffffffb781126dcae7b2321e66a241adc64d2f")
+	generator  = point{
+		hexInt("0x1ef15c18599971b7beced415a40f0c7deacfd9b0d1819e03d723d8bc943cfca"),
+		hexInt("0x5668060aa49730b7be4801df46ec62de53ecd11abe43a32873000c36e8dc1f"),
+	}
+	// maxECDSAValue bounds r, w and the signed hash.
+	maxECDSAValue = new(big.Int).Lsh(big.NewInt(1), 251)
+)
+
+// Constant points of the Pedersen hash: the shift point and P0 to P3.
+var (
+	pedersenShift = point{
+		hexInt("0x49ee3eba8c1600700ee1b87eb599f16716b0b1022947733551fde4050ca6804"),
+		hexInt("0x3ca0cfe4b3bc6ddf346d49d06ea0ed34e621062c0e056c1d0405d266e10268a"),
+	}
+	pedersenPoints = []point{
+		{hexInt("0x234287dcbaffe7f969c748655fca9e58fa8120b6d56eb0c1080d17957ebe47b"), hexInt("0x3b056f100f96fb21e889527d41f4e39940135dd7a6c94cc6ed0268ee89e5615")},
+		{hexInt("0x4fa56f376c83db33f9dab2656558f3399099ec1de5e3018b7a6932dba8aa378"), hexInt("0x3fa0984c931c9e38113e0c0e47e4401562761f92a7a23b45168f4e80ff5b54d")},
+		{hexInt("0x4ba4cc166be8dec764910f75b45f74b40c690c74709e90f3aa372f0bd2d6997"), hexInt("0x40301cf5c1751f4b971e46c4ede85fcac5c59a5ce5ae7c48151f27b24b219c")},
+		{hexInt("0x54302dcb0e6cc1c6e44cca8f61a63bb2ca65048d53fb325d36ff12c49a58202"), hexInt("0x1b77b3e37d13504b348046268d8ae25ce98ad783c25561a879dcc77e99c2426")},
+	}
+	lowPartMask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 248), big.NewInt(1))
+)
+
+func hexInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		panic("invalid constant " + s)
+	}
+	return i
+}
+
+// point is an affine point of the curve. The point at infinity has a nil x.
+type point struct {
+	x *big.Int
+	y *big.Int
+}
+
+func (a point) add(b point) point {
+	if a.x == nil {
+		return b
+	}
+	if b.x == nil {
+		return a
+	}
+	var m *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		if a.y.Cmp(b.y) != 0 {
+			return point{}
+		}
+		num := new(big.Int).Mul(a.x, a.x)
+		num.Mul(num, big.NewInt(3))
+		num.Add(num, curveAlpha)
+		den := new(big.Int).Lsh(a.y, 1)
+		m = num.Mul(num, den.ModInverse(den.Mod(den, FieldPrime), FieldPrime))
+	} else {
+		num := new(big.Int).Sub(b.y, a.y)
+		den := new(big.Int).Sub(b.x, a.x)
+		m = num.Mul(num, den.ModInverse(den.Mod(den, FieldPrime), FieldPrime))
+	}
+	m.Mod(m, FieldPrime)
+	x := new(big.Int).Mul(m, m)
+	x.Sub(x, a.x)
+	x.Sub(x, b.x)
+	x.Mod(x, FieldPrime)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, m)
+	y.Sub(y, a.y)
+	y.Mod(y, FieldPrime)
+	return point{x, y}
+}
+
+func (a point) mul(k *big.Int) point {
+	r := point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = r.add(r)
+		if k.Bit(i) == 1 {
+			r = r.add(a)
+		}
+	}
+	return r
+}
+
+// Pedersen returns the Pedersen hash of the felts @a and @b.
+func Pedersen(a *big.Int, b *big.Int) *big.Int {
+	r := pedersenShift
+	for i, x := range []*big.Int{a, b} {
+		r = r.add(pedersenPoints[2*i].mul(new(big.Int).And(x, lowPartMask)))
+		r = r.add(pedersenPoints[2*i+1].mul(new(big.Int).Rsh(x, 248)))
+	}
+	return r.x
+}
+
+// PedersenArray hashes @elements in the way of compute_hash_on_elements, i.e. the length
+// of the array is hashed last.
+func PedersenArray(elements ...*big.Int) *big.Int {
+	h := big.NewInt(0)
+	for _, e := range elements {
+		h = Pedersen(h, e)
+	}
+	return Pedersen(h, big.NewInt(int64(len(elements))))
+}
+
+// PublicKey returns the public key, i.e. the x coordinate of @privateKey * G.
+func PublicKey(privateKey *big.Int) *big.Int {
+	return generator.mul(privateKey).x
+}
+
+// Sign returns the ECDSA signature (r, s) of @hash with @privateKey.
+func Sign(hash *big.Int, privateKey *big.Int) (*big.Int, *big.Int, error) {
+	if hash.Sign() < 0 || hash.Cmp(maxECDSAValue) >= 0 {
+		return nil, nil, errors.New("message hash out of range")
+	}
+	for {
+		k, err := rand.Int(rand.Reader, curveOrder)
+		if err != nil {
+			return nil, nil, err
+		}
+		if k.Sign() == 0 {
+			continue
+		}
+		r := generator.mul(k).x
+		if r.Sign() == 0 || r.Cmp(maxECDSAValue) >= 0 {
+			continue
+		}
+		// s = (hash + r*privateKey) / k and w = 1/s must both be below 2^251.
+		s := new(big.Int).Mul(r, privateKey)
+		s.Add(s, hash)
+		s.Mod(s, curveOrder)
+		if s.Sign() == 0 {
+			continue
+		}
+		s.Mul(s, new(big.Int).ModInverse(k, curveOrder))
+		s.Mod(s, curveOrder)
+		w := new(big.Int).ModInverse(s, curveOrder)
+		if w.Cmp(maxECDSAValue) >= 0 {
+			continue
+		}
+		return r, s, nil
+	}
+}
@@ -0,0 +1,72 @@
+package starknet
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPedersen(t *testing.T) {
+	h := Pedersen(
+		hexInt("0x3d937c035c878245caf64531a5756109c53068da139362728feb561405371cb"),
+		hexInt("0x208a0a10250e382e1e4bbe2880906c2791bf6275695e02fbbc6aeff9cd8b31a"),
+	)
+	if h.Cmp(hexInt("0x30e480bed5fe53fa909cc0f8c4d99b8f9f2c016be4c41e13a4848797979c662")) != 0 {
+		t.Errorf("unexpected pedersen hash %s", toHex(h))
+	}
+}
+
+func TestSign(t *testing.T) {
+	privateKey := hexInt("0x3c1e9550e66958296d11b60f8e8e7a7ad990d07fa65d5f7652c4a6c87d4e3cc")
+	publicKey := PublicKey(privateKey)
+	if publicKey.Cmp(hexInt("0x77a3b314db07c45076d11f62b6f9e748a39790441823307743cf00d6597ea43")) != 0 {
+		t.Fatalf("unexpected public key %s", toHex(publicKey))
+	}
+
+	hash := hexInt("0x397e76d1667c4454bfb83514e120583af836f8e32a516765497823eabe16a3f")
+	r, s, err := Sign(hash, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Verify that (hash*G + r*Q) / s has the x coordinate r.
+	w := new(big.Int).ModInverse(s, curveOrder)
+	u1 := new(big.Int).Mod(new(big.Int).Mul(hash, w), curveOrder)
+	u2 := new(big.Int).Mod(new(big.Int).Mul(r, w), curveOrder)
+	q := generator.mul(privateKey)
+	if generator.mul(u1).add(q.mul(u2)).x.Cmp(r) != 0 {
+		t.Errorf("invalid signature")
+	}
+}
+
+func TestShortString(t *testing.T) {
+	f, err := ShortString("BTC/USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toHex(f) != "0x4254432f555344" {
+		t.Errorf("unexpected encoding %s", toHex(f))
+	}
+	_, err = ShortString("THIS/KEY/IS/LONGER/THAN/31/CHARS")
+	if err == nil {
+		t.Errorf("expected error for long string")
+	}
+}
+
+func TestExecuteCalldata(t *testing.T) {
+	calls := []Call{
+		{To: big.NewInt(10), Selector: big.NewInt(11), Calldata: []*big.Int{big.NewInt(1), big.NewInt(2)}},
+		{To: big.NewInt(20), Selector: big.NewInt(21), Calldata: []*big.Int{big.NewInt(3)}},
+	}
+	check := func(cairoVersion int, expected []int64) {
+		calldata := ExecuteCalldata(calls, cairoVersion)
+		if len(calldata) != len(expected) {
+			t.Fatalf("cairo %d: expected %v, got %v", cairoVersion, expected, calldata)
+		}
+		for i := range expected {
+			if calldata[i].Int64() != expected[i] {
+				t.Errorf("cairo %d: expected %v, got %v", cairoVersion, expected, calldata)
+			}
+		}
+	}
+	check(0, []int64{2, 10, 11, 0, 2, 20, 21, 2, 1, 3, 1, 2, 3})
+	check(1, []int64{2, 10, 11, 2, 1, 2, 20, 21, 1, 3})
+}
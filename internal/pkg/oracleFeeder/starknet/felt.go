@@ -0,0 +1,51 @@
+package starknet
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var selectorMask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 250), big.NewInt(1))
+
+// Selector returns the entry point selector of the function @name, i.e. its starknet keccak.
+func Selector(name string) *big.Int {
+	h := new(big.Int).SetBytes(crypto.Keccak256([]byte(name)))
+	return h.And(h, selectorMask)
+}
+
+// ShortString encodes @s, an ASCII string of at most 31 characters, into a felt.
+func ShortString(s string) (*big.Int, error) {
+	if len(s) > 31 {
+		return nil, fmt.Errorf("short string %s exceeds 31 characters", s)
+	}
+	for _, c := range []byte(s) {
+		if c > 127 {
+			return nil, fmt.Errorf("short string %s is not ASCII", s)
+		}
+	}
+	return new(big.Int).SetBytes([]byte(s)), nil
+}
+
+// ParseFelt parses a hex or decimal felt.
+func ParseFelt(s string) (*big.Int, error) {
+	f, ok := new(big.Int).SetString(s, 0)
+	if !ok || f.Sign() < 0 || f.Cmp(FieldPrime) >= 0 {
+		return nil, fmt.Errorf("invalid felt %s", s)
+	}
+	return f, nil
+}
+
+// toHex formats a felt as expected by the JSON-RPC API.
+func toHex(f *big.Int) string {
+	return "0x" + f.Text(16)
+}
+
+func toHexSlice(felts []*big.Int) []string {
+	s := make([]string, len(felts))
+	for i, f := range felts {
+		s[i] = toHex(f)
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package starknet
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Oracle writes quotations into a Cairo oracle contract through an account. It implements
+// oracleFeeder.Updater. The contract exposes set_value(key: felt252, value: u128, timestamp: u64),
+// where key is the short string BASE/QUOTE, e.g. BTC/USD.
+type Oracle struct {
+	account  *Account
+	contract *big.Int
+	selector *big.Int
+}
+
+// NewOracle returns an oracle updating the contract at @contract through @account.
+func NewOracle(account *Account, contract *big.Int) *Oracle {
+	return &Oracle{
+		account:  account,
+		contract: contract,
+		selector: Selector("set_value"),
+	}
+}
+
+// Update sets the price of @quotation with 8 decimals under its key, e.g. BTC/USD or ETH/EUR.
+func (o *Oracle) Update(quotation *models.Quotation) error {
+	key := oracleFeeder.Key(quotation)
+	keyFelt, err := ShortString(key)
+	if err != nil {
+		return err
+	}
+	value := big.NewInt(int64(quotation.Price * 100000000))
+	call := Call{
+		To:       o.contract,
+		Selector: o.selector,
+		Calldata: []*big.Int{keyFelt, value, big.NewInt(time.Now().Unix())},
+	}
+	hash, maxFee, err := o.account.Execute([]Call{call})
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"chain": "starknet", "key": key, "value": value, "maxFeeWei": maxFee, "txHash": hash}).Info("oracle updated")
+	return nil
+}
@@ -0,0 +1,156 @@
+package substrate
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// CoinInfo is the price record of the DIA oracle pallet.
+type CoinInfo struct {
+	Symbol              []byte
+	Name                []byte
+	Supply              types.U128
+	LastUpdateTimestamp types.U64
+	Price               types.U128
+}
+
+// coinInfoEntry is the (key, CoinInfo) tuple taken by set_updated_coin_infos.
+type coinInfoEntry struct {
+	Key  []byte
+	Info CoinInfo
+}
+
+// Oracle submits signed extrinsics with DIA quotations to an oracle pallet. Call indices,
+// storage keys and the runtime version are read from the chain's metadata, so the same
+// binary works on all parachains which include the pallet. It implements oracleFeeder.Updater.
+type Oracle struct {
+	api      *gsrpc.SubstrateAPI
+	keypair  signature.KeyringPair
+	call     string
+	decimals int
+
+	genesisHash types.Hash
+	// nonce of the next extrinsic. It is reset after failures so that it is read from the chain again.
+	nonce   uint64
+	nonceMu sync.Mutex
+}
+
+// NewOracle connects to the node at @url. @call is the pallet call in the form Pallet.call_name,
+// @secret a secret seed or mnemonic of the signing account.
+func NewOracle(url string, secret string, network uint8, call string, decimals int) (*Oracle, error) {
+	if !strings.Contains(call, ".") {
+		return nil, errors.New("call must be given as Pallet.call_name")
+	}
+	api, err := gsrpc.NewSubstrateAPI(url)
+	if err != nil {
+		return nil, err
+	}
+	keypair, err := signature.KeyringPairFromSecret(secret, network)
+	if err != nil {
+		return nil, err
+	}
+	genesisHash, err := api.RPC.Chain.GetBlockHash(0)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("signing extrinsics as %s", keypair.Address)
+	return &Oracle{
+		api:         api,
+		keypair:     keypair,
+		call:        call,
+		decimals:    decimals,
+		genesisHash: genesisHash,
+	}, nil
+}
+
+// Update submits the price of @quotation under its key, e.g. BTC/USD or ETH/EUR.
+func (o *Oracle) Update(quotation *models.Quotation) error {
+	o.nonceMu.Lock()
+	defer o.nonceMu.Unlock()
+
+	// Metadata and runtime version are fetched for every extrinsic, since runtime upgrades
+	// can change call indices and the transaction version at any time.
+	meta, err := o.api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return err
+	}
+	runtimeVersion, err := o.api.RPC.State.GetRuntimeVersionLatest()
+	if err != nil {
+		return err
+	}
+
+	price, _ := new(big.Float).Mul(big.NewFloat(quotation.Price), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(o.decimals)), nil))).Int(nil)
+	entries := []coinInfoEntry{{
+		Key: []byte(oracleFeeder.Key(quotation)),
+		Info: CoinInfo{
+			Symbol:              []byte(quotation.Symbol),
+			Name:                []byte(quotation.Name),
+			Supply:              types.NewU128(*big.NewInt(0)),
+			LastUpdateTimestamp: types.NewU64(uint64(time.Now().Unix())),
+			Price:               types.NewU128(*price),
+		},
+	}}
+	call, err := types.NewCall(meta, o.call, entries)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := o.nextNonce(meta)
+	if err != nil {
+		return err
+	}
+	ext := types.NewExtrinsic(call)
+	err = ext.Sign(o.keypair, types.SignatureOptions{
+		BlockHash:          o.genesisHash,
+		Era:                types.ExtrinsicEra{IsMortalEra: false},
+		GenesisHash:        o.genesisHash,
+		Nonce:              types.NewUCompactFromUInt(nonce),
+		SpecVersion:        runtimeVersion.SpecVersion,
+		Tip:                types.NewUCompactFromUInt(0),
+		TransactionVersion: runtimeVersion.TransactionVersion,
+	})
+	if err != nil {
+		return err
+	}
+
+	hash, err := o.api.RPC.Author.SubmitExtrinsic(ext)
+	if err != nil {
+		o.nonce = 0
+		return err
+	}
+	o.nonce = nonce + 1
+	log.WithFields(log.Fields{"chain": "substrate", "key": oracleFeeder.Key(quotation), "txHash": hash.Hex()}).Info("oracle updated")
+	return nil
+}
+
+// nextNonce returns the nonce for the next extrinsic. The chain's account nonce doesn't
+// include extrinsics still in the pool, so the local counter wins if it is ahead.
+func (o *Oracle) nextNonce(meta *types.Metadata) (uint64, error) {
+	key, err := types.CreateStorageKey(meta, "System", "Account", o.keypair.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+	var accountInfo types.AccountInfo
+	ok, err := o.api.RPC.State.GetStorageLatest(key, &accountInfo)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.New("signing account does not exist on chain")
+	}
+	nonce := uint64(accountInfo.Nonce)
+	if o.nonce > nonce {
+		nonce = o.nonce
+	}
+	return nonce, nil
+}
@@ -0,0 +1,200 @@
+package sui
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// schemeEd25519 is the signature scheme flag of ed25519 keys.
+const schemeEd25519 = 0x00
+
+// intentTransaction prefixes a transaction before hashing: scope TransactionData, version 0, app id Sui.
+var intentTransaction = []byte{0, 0, 0}
+
+// ParsePrivateKey parses an ed25519 key as stored in sui.keystore, i.e. base64 of the scheme
+// flag followed by the 32 byte private key.
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != ed25519.SeedSize+1 || b[0] != schemeEd25519 {
+		return nil, errors.New("only ed25519 keystore entries are supported")
+	}
+	return ed25519.NewKeyFromSeed(b[1:]), nil
+}
+
+// AddressFromPublicKey returns the address of the account owning @publicKey.
+func AddressFromPublicKey(publicKey ed25519.PublicKey) string {
+	h := blake2b.Sum256(append([]byte{schemeEd25519}, publicKey...))
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// SignTransaction returns the serialized signature of the base64 encoded transaction @txBytes.
+func SignTransaction(txBytes string, privateKey ed25519.PrivateKey) (string, error) {
+	tx, err := base64.StdEncoding.DecodeString(txBytes)
+	if err != nil {
+		return "", err
+	}
+	digest := blake2b.Sum256(append(append([]byte{}, intentTransaction...), tx...))
+	signature := ed25519.Sign(privateKey, digest[:])
+	serialized := append([]byte{schemeEd25519}, signature...)
+	serialized = append(serialized, privateKey.Public().(ed25519.PublicKey)...)
+	return base64.StdEncoding.EncodeToString(serialized), nil
+}
+
+// Client talks to the JSON-RPC API of a Sui full node.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the JSON-RPC node at @url.
+func NewClient(url string) *Client {
+	return &Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("sui rpc error %d: %s", e.Code, e.Message)
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// call performs the JSON-RPC call @method and unmarshals its result into @result.
+func (c *Client) call(method string, result interface{}, params ...interface{}) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	response, err := c.httpClient.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	var resp rpcResponse
+	err = json.Unmarshal(contents, &resp)
+	if err != nil {
+		return fmt.Errorf("sui rpc returned status %d: %v", response.StatusCode, err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// MoveCall is a call of an entry function. Arguments are object ids or pure values in their
+// json representation, which the node encodes in BCS according to the function signature.
+type MoveCall struct {
+	Package   string
+	Module    string
+	Function  string
+	Arguments []interface{}
+}
+
+// BuildMoveCall returns the base64 encoded transaction data of @call sent by @signer with
+// @gasBudget. The node selects the gas coin and uses the reference gas price.
+func (c *Client) BuildMoveCall(signer string, call MoveCall, gasBudget uint64) (string, error) {
+	var resp struct {
+		TxBytes string `json:"txBytes"`
+	}
+	err := c.call("unsafe_moveCall", &resp, signer, call.Package, call.Module, call.Function, []string{}, call.Arguments, nil, strconv.FormatUint(gasBudget, 10))
+	return resp.TxBytes, err
+}
+
+type executionStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+func (s executionStatus) err() error {
+	if s.Status != "success" {
+		return fmt.Errorf("execution %s: %s", s.Status, s.Error)
+	}
+	return nil
+}
+
+// GasCost is the gas used by a transaction in MIST.
+type GasCost struct {
+	ComputationCost string `json:"computationCost"`
+	StorageCost     string `json:"storageCost"`
+	StorageRebate   string `json:"storageRebate"`
+}
+
+// Budget returns the gas budget needed by the transaction. The storage rebate is only paid
+// out after execution, so it doesn't reduce the budget.
+func (g GasCost) Budget() (uint64, error) {
+	computation, err := strconv.ParseUint(g.ComputationCost, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	storage, err := strconv.ParseUint(g.StorageCost, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return computation + storage, nil
+}
+
+// DryRun executes @txBytes without committing it and returns its gas cost.
+func (c *Client) DryRun(txBytes string) (GasCost, error) {
+	var resp struct {
+		Effects struct {
+			Status  executionStatus `json:"status"`
+			GasUsed GasCost         `json:"gasUsed"`
+		} `json:"effects"`
+	}
+	err := c.call("sui_dryRunTransactionBlock", &resp, txBytes)
+	if err != nil {
+		return GasCost{}, err
+	}
+	return resp.Effects.GasUsed, resp.Effects.Status.err()
+}
+
+// Execute submits @txBytes with @signature, waits for its local execution and returns its digest.
+func (c *Client) Execute(txBytes string, signature string) (string, error) {
+	var resp struct {
+		Digest  string `json:"digest"`
+		Effects struct {
+			Status executionStatus `json:"status"`
+		} `json:"effects"`
+	}
+	options := map[string]bool{"showEffects": true}
+	err := c.call("sui_executeTransactionBlock", &resp, txBytes, []string{signature}, options, "WaitForLocalExecution")
+	if err != nil {
+		return "", err
+	}
+	return resp.Digest, resp.Effects.Status.err()
+}
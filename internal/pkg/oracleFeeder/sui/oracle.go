@@ -0,0 +1,99 @@
+package sui
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// clockObject is the shared clock object of Sui.
+const clockObject = "0x6"
+
+// Oracle writes quotations into a DIA oracle module on Sui. It implements oracleFeeder.Updater.
+// The entry function is called with the configured objects, usually the admin capability
+// and the shared oracle object, followed by key: String, value: u128, timestamp: u64 and
+// optionally the clock.
+type Oracle struct {
+	client     *Client
+	privateKey ed25519.PrivateKey
+	sender     string
+	call       MoveCall
+	objects    []string
+	withClock  bool
+	// maxGasBudget bounds the MIST spent by a single update. It is also the budget used
+	// when dry running the update.
+	maxGasBudget uint64
+}
+
+// NewOracle returns an oracle calling @module::@function of @pkg with the objects @objects.
+func NewOracle(client *Client, privateKey ed25519.PrivateKey, pkg string, module string, function string, objects []string, withClock bool, maxGasBudget uint64) *Oracle {
+	return &Oracle{
+		client:       client,
+		privateKey:   privateKey,
+		sender:       AddressFromPublicKey(privateKey.Public().(ed25519.PublicKey)),
+		call:         MoveCall{Package: pkg, Module: module, Function: function},
+		objects:      objects,
+		withClock:    withClock,
+		maxGasBudget: maxGasBudget,
+	}
+}
+
+// Sender returns the address of the updating account.
+func (o *Oracle) Sender() string {
+	return o.sender
+}
+
+// Update sets the price of @quotation with 8 decimals under its key, e.g. BTC/USD or ETH/EUR.
+func (o *Oracle) Update(quotation *models.Quotation) error {
+	key := oracleFeeder.Key(quotation)
+	value := strconv.FormatInt(int64(quotation.Price*100000000), 10)
+
+	call := o.call
+	for _, object := range o.objects {
+		call.Arguments = append(call.Arguments, object)
+	}
+	// u128 and u64 are passed as strings, as json numbers lose precision.
+	call.Arguments = append(call.Arguments, key, value, strconv.FormatInt(time.Now().Unix(), 10))
+	if o.withClock {
+		call.Arguments = append(call.Arguments, clockObject)
+	}
+
+	txBytes, err := o.client.BuildMoveCall(o.sender, call, o.maxGasBudget)
+	if err != nil {
+		return err
+	}
+	cost, err := o.client.DryRun(txBytes)
+	if err != nil {
+		return fmt.Errorf("dry run update: %v", err)
+	}
+	budget, err := cost.Budget()
+	if err != nil {
+		return err
+	}
+	// Leave headroom for state changes between dry run and execution.
+	budget = budget * 6 / 5
+	if budget < o.maxGasBudget {
+		txBytes, err = o.client.BuildMoveCall(o.sender, call, budget)
+		if err != nil {
+			return err
+		}
+	} else {
+		budget = o.maxGasBudget
+	}
+
+	signature, err := SignTransaction(txBytes, o.privateKey)
+	if err != nil {
+		return err
+	}
+	digest, err := o.client.Execute(txBytes, signature)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"chain": "sui", "key": key, "value": value, "gasBudgetMist": budget, "txHash": digest}).Info("oracle updated")
+	return nil
+}
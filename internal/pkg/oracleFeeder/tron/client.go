@@ -0,0 +1,225 @@
+package tron
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const addressPrefix = 0x41
+
+// AddressToHex converts a base58check Tron address (T...) into its hex representation (41...).
+func AddressToHex(address string) (string, error) {
+	b, err := utils.Base58CheckDecode(address)
+	if err != nil {
+		return "", err
+	}
+	if len(b) != 21 || b[0] != addressPrefix {
+		return "", fmt.Errorf("invalid tron address %s", address)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// AddressFromKey returns the hex and base58check address of @key.
+func AddressFromKey(key *ecdsa.PrivateKey) (hexAddress string, base58Address string) {
+	b := append([]byte{addressPrefix}, crypto.PubkeyToAddress(key.PublicKey).Bytes()...)
+	return hex.EncodeToString(b), utils.Base58CheckEncode(b)
+}
+
+// Client talks to the HTTP API of a Tron full node.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the full node HTTP API at @url.
+func NewClient(url string) *Client {
+	return &Client{
+		url:        strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// post sends @request as json to @path and unmarshals the response into @response.
+func (c *Client) post(path string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Post(c.url+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	contents, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tron node returned status %d: %s", resp.StatusCode, string(contents))
+	}
+	return json.Unmarshal(contents, response)
+}
+
+// TriggerRequest is a call of a smart contract function. Addresses are in hex format.
+type TriggerRequest struct {
+	OwnerAddress     string `json:"owner_address"`
+	ContractAddress  string `json:"contract_address"`
+	FunctionSelector string `json:"function_selector"`
+	Parameter        string `json:"parameter"`
+	FeeLimit         int64  `json:"fee_limit,omitempty"`
+	CallValue        int64  `json:"call_value"`
+}
+
+type returnResult struct {
+	Result  bool   `json:"result"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// error decodes the hex encoded message of a failed call.
+func (r returnResult) error() error {
+	msg, err := hex.DecodeString(r.Message)
+	if err != nil {
+		msg = []byte(r.Message)
+	}
+	return fmt.Errorf("%s: %s", r.Code, string(msg))
+}
+
+// Transaction is an unsigned or signed transaction as returned by the node.
+type Transaction struct {
+	TxID       string          `json:"txID"`
+	RawData    json.RawMessage `json:"raw_data"`
+	RawDataHex string          `json:"raw_data_hex"`
+	Signature  []string        `json:"signature,omitempty"`
+	Visible    bool            `json:"visible"`
+}
+
+// EstimateEnergy simulates @req and returns the energy it consumes.
+func (c *Client) EstimateEnergy(req TriggerRequest) (int64, error) {
+	var resp struct {
+		Result     returnResult `json:"result"`
+		EnergyUsed int64        `json:"energy_used"`
+	}
+	err := c.post("/wallet/triggerconstantcontract", req, &resp)
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Result.Result {
+		return 0, resp.Result.error()
+	}
+	return resp.EnergyUsed, nil
+}
+
+// TriggerSmartContract builds the unsigned transaction for @req.
+func (c *Client) TriggerSmartContract(req TriggerRequest) (*Transaction, error) {
+	var resp struct {
+		Result      returnResult `json:"result"`
+		Transaction *Transaction `json:"transaction"`
+	}
+	err := c.post("/wallet/triggersmartcontract", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Result.Result || resp.Transaction == nil {
+		return nil, resp.Result.error()
+	}
+	return resp.Transaction, nil
+}
+
+// SignTransaction signs the txID of @tx with @key.
+func SignTransaction(tx *Transaction, key *ecdsa.PrivateKey) error {
+	txID, err := hex.DecodeString(tx.TxID)
+	if err != nil {
+		return err
+	}
+	signature, err := crypto.Sign(txID, key)
+	if err != nil {
+		return err
+	}
+	signature[64] += 27
+	tx.Signature = append(tx.Signature, hex.EncodeToString(signature))
+	return nil
+}
+
+// BroadcastTransaction submits the signed transaction @tx.
+func (c *Client) BroadcastTransaction(tx *Transaction) error {
+	var resp returnResult
+	err := c.post("/wallet/broadcasttransaction", tx, &resp)
+	if err != nil {
+		return err
+	}
+	if !resp.Result {
+		return resp.error()
+	}
+	return nil
+}
+
+// ChainFees are the current prices of energy and bandwidth in sun.
+type ChainFees struct {
+	EnergyFee    int64
+	BandwidthFee int64
+}
+
+// GetChainFees reads the energy and bandwidth prices from the chain parameters.
+func (c *Client) GetChainFees() (ChainFees, error) {
+	var resp struct {
+		ChainParameter []struct {
+			Key   string `json:"key"`
+			Value int64  `json:"value"`
+		} `json:"chainParameter"`
+	}
+	err := c.post("/wallet/getchainparameters", struct{}{}, &resp)
+	if err != nil {
+		return ChainFees{}, err
+	}
+	var fees ChainFees
+	for _, p := range resp.ChainParameter {
+		switch p.Key {
+		case "getEnergyFee":
+			fees.EnergyFee = p.Value
+		case "getTransactionFee":
+			fees.BandwidthFee = p.Value
+		}
+	}
+	if fees.EnergyFee == 0 {
+		return fees, errors.New("chain parameters contain no energy fee")
+	}
+	return fees, nil
+}
+
+// AccountResources are the energy and bandwidth available to an account without burning TRX.
+type AccountResources struct {
+	Energy    int64
+	Bandwidth int64
+}
+
+// GetAccountResources returns the staked and free resources left to @hexAddress.
+func (c *Client) GetAccountResources(hexAddress string) (AccountResources, error) {
+	var resp struct {
+		FreeNetLimit int64 `json:"freeNetLimit"`
+		FreeNetUsed  int64 `json:"freeNetUsed"`
+		NetLimit     int64 `json:"NetLimit"`
+		NetUsed      int64 `json:"NetUsed"`
+		EnergyLimit  int64 `json:"EnergyLimit"`
+		EnergyUsed   int64 `json:"EnergyUsed"`
+	}
+	err := c.post("/wallet/getaccountresource", map[string]string{"address": hexAddress}, &resp)
+	if err != nil {
+		return AccountResources{}, err
+	}
+	return AccountResources{
+		Energy:    resp.EnergyLimit - resp.EnergyUsed,
+		Bandwidth: resp.FreeNetLimit - resp.FreeNetUsed + resp.NetLimit - resp.NetUsed,
+	}, nil
+}
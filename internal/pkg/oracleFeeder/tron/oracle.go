@@ -0,0 +1,125 @@
+package tron
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/blockchain-scrapers/blockchains/ethereum/diaOracleServiceV2"
+	"github.com/diadata-org/diadata/internal/pkg/oracleFeeder"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	log "github.com/sirupsen/logrus"
+)
+
+const setValueSelector = "setValue(string,uint128,uint128)"
+
+// signatureSize is added to the raw transaction when estimating its bandwidth.
+const signatureSize = 65 + 64
+
+// Oracle writes quotations into a DIAOracleV2 contract deployed on Tron. It implements oracleFeeder.Updater.
+type Oracle struct {
+	client          *Client
+	key             *ecdsa.PrivateKey
+	ownerAddress    string
+	contractAddress string
+	abi             abi.ABI
+	// maxFeeLimit is the maximal amount of sun burnt by a single update.
+	maxFeeLimit int64
+}
+
+// NewOracle returns an oracle updating the contract at the base58 address @contract with @key.
+func NewOracle(client *Client, key *ecdsa.PrivateKey, contract string, maxFeeLimit int64) (*Oracle, error) {
+	contractAddress, err := AddressToHex(contract)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := abi.JSON(strings.NewReader(diaOracleServiceV2.DIAOracleV2ABI))
+	if err != nil {
+		return nil, err
+	}
+	ownerAddress, base58Address := AddressFromKey(key)
+	log.Infof("updating %s from %s", contract, base58Address)
+	return &Oracle{
+		client:          client,
+		key:             key,
+		ownerAddress:    ownerAddress,
+		contractAddress: contractAddress,
+		abi:             parsed,
+		maxFeeLimit:     maxFeeLimit,
+	}, nil
+}
+
+// Update sets the price of @quotation with 8 decimals under its key, e.g. BTC/USD or ETH/EUR.
+func (o *Oracle) Update(quotation *models.Quotation) error {
+	key := oracleFeeder.Key(quotation)
+	packed, err := o.abi.Pack("setValue", key, big.NewInt(int64(quotation.Price*100000000)), big.NewInt(time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	req := TriggerRequest{
+		OwnerAddress:     o.ownerAddress,
+		ContractAddress:  o.contractAddress,
+		FunctionSelector: setValueSelector,
+		// The node prepends the function selector itself.
+		Parameter: hex.EncodeToString(packed[4:]),
+	}
+
+	energy, err := o.client.EstimateEnergy(req)
+	if err != nil {
+		return fmt.Errorf("estimate energy: %v", err)
+	}
+	req.FeeLimit = o.maxFeeLimit
+	tx, err := o.client.TriggerSmartContract(req)
+	if err != nil {
+		return err
+	}
+	fee, err := o.estimateFee(energy, int64(len(tx.RawDataHex)/2+signatureSize))
+	if err != nil {
+		return err
+	}
+	if fee > o.maxFeeLimit {
+		return fmt.Errorf("estimated fee of %d sun for %d energy exceeds fee limit of %d sun", fee, energy, o.maxFeeLimit)
+	}
+
+	err = SignTransaction(tx, o.key)
+	if err != nil {
+		return err
+	}
+	err = o.client.BroadcastTransaction(tx)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"chain": "tron", "key": key, "energy": energy, "feeSun": fee, "txHash": tx.TxID}).Info("oracle updated")
+	return nil
+}
+
+// estimateFee returns the amount of sun burnt for @energy and @bandwidth, given the resources
+// currently available to the owner account.
+func (o *Oracle) estimateFee(energy int64, bandwidth int64) (int64, error) {
+	fees, err := o.client.GetChainFees()
+	if err != nil {
+		return 0, err
+	}
+	resources, err := o.client.GetAccountResources(o.ownerAddress)
+	if err != nil {
+		return 0, err
+	}
+	return EstimateFee(energy, bandwidth, resources, fees), nil
+}
+
+// EstimateFee returns the sun burnt for the part of @energy and @bandwidth not covered by @resources.
+// Bandwidth is either fully covered or fully paid for, as the node doesn't split it.
+func EstimateFee(energy int64, bandwidth int64, resources AccountResources, fees ChainFees) int64 {
+	var fee int64
+	if energy > resources.Energy {
+		fee += (energy - resources.Energy) * fees.EnergyFee
+	}
+	if bandwidth > resources.Bandwidth {
+		fee += bandwidth * fees.BandwidthFee
+	}
+	return fee
+}
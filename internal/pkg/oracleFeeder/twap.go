@@ -0,0 +1,107 @@
+package oracleFeeder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+// PricePoint is a value of a filter at a point in time.
+type PricePoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// TWAP returns the time-weighted average of @points until @end. Each value holds until the
+// next point, the last one until @end.
+func TWAP(points []PricePoint, end time.Time) (float64, error) {
+	sorted := make([]PricePoint, 0, len(points))
+	for _, p := range points {
+		if p.Time.Before(end) {
+			sorted = append(sorted, p)
+		}
+	}
+	if len(sorted) == 0 {
+		return 0, errors.New("no price points in window")
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+	var sum, duration float64
+	for i, p := range sorted {
+		next := end
+		if i+1 < len(sorted) {
+			next = sorted[i+1].Time
+		}
+		d := next.Sub(p.Time).Seconds()
+		sum += p.Value * d
+		duration += d
+	}
+	if duration == 0 {
+		return sorted[len(sorted)-1].Value, nil
+	}
+	return sum / duration, nil
+}
+
+// GetTWAPFromDia returns the time-weighted average of the values of @filter for @symbol over
+// all exchanges in the @window before @end, as served by the filters service through the DIA API.
+func GetTWAPFromDia(symbol string, filter string, window time.Duration, end time.Time) (float64, error) {
+	url := fmt.Sprintf("%s/v1/chartPointsAllExchanges/%s/%s?starttime=%d&endtime=%d", dia.BaseUrl, filter, strings.ToUpper(symbol), end.Add(-window).Unix(), end.Unix())
+	response, err := utils.GetWithBackoff(url)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != 200 {
+		return 0, fmt.Errorf("Error on dia api with return code %d", response.StatusCode)
+	}
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return 0, err
+	}
+	var points models.Points
+	err = points.UnmarshalBinary(contents)
+	if err != nil {
+		return 0, err
+	}
+	var pricePoints []PricePoint
+	for _, result := range points.DataPoints {
+		for _, series := range result.Series {
+			timeIndex, valueIndex := -1, -1
+			for i, column := range series.Columns {
+				switch column {
+				case "time":
+					timeIndex = i
+				case "value":
+					valueIndex = i
+				}
+			}
+			if timeIndex < 0 || valueIndex < 0 {
+				return 0, fmt.Errorf("unexpected columns %v of filter points", series.Columns)
+			}
+			for _, row := range series.Values {
+				timeString, ok := row[timeIndex].(string)
+				if !ok {
+					continue
+				}
+				t, err := time.Parse(time.RFC3339, timeString)
+				if err != nil {
+					return 0, err
+				}
+				value, ok := row[valueIndex].(float64)
+				if !ok {
+					continue
+				}
+				pricePoints = append(pricePoints, PricePoint{Time: t, Value: value})
+			}
+		}
+	}
+	return TWAP(pricePoints, end)
+}
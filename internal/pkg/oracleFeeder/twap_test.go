@@ -0,0 +1,31 @@
+package oracleFeeder
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTWAP(t *testing.T) {
+	end := time.Unix(1630000000, 0)
+	points := []PricePoint{
+		{end.Add(-2 * time.Minute), 110},
+		{end.Add(-10 * time.Minute), 100},
+		{end.Add(-4 * time.Minute), 120},
+		// after the end of the window
+		{end.Add(time.Minute), 1000},
+	}
+	// 100 for 6 minutes, 120 for 2 minutes, 110 for 2 minutes
+	expected := (100*6 + 120*2 + 110*2) / 10.0
+	twap, err := TWAP(points, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(twap-expected) > 1e-9 {
+		t.Errorf("expected %v, got %v", expected, twap)
+	}
+
+	if _, err := TWAP(nil, end); err == nil {
+		t.Error("expected error without points")
+	}
+}
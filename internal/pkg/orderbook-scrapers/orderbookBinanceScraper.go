@@ -0,0 +1,37 @@
+package orderbookscrapers
+
+import (
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	binanceDepthURL = "https://api.binance.com/api/v3/depth?symbol=%s&limit=%d"
+	// binanceDepthLimit is the number of levels per side, which covers the band for all but the
+	// most liquid pairs. For those the depth is a lower bound.
+	binanceDepthLimit = 1000
+)
+
+// BinanceOrderBookScraper fetches order books from the Binance spot API.
+type BinanceOrderBookScraper struct{}
+
+// NewBinanceOrderBookScraper returns a BinanceOrderBookScraper.
+func NewBinanceOrderBookScraper() *BinanceOrderBookScraper {
+	return &BinanceOrderBookScraper{}
+}
+
+// FetchDepth implements OrderBookScraper. The foreign name of @pair is the Binance symbol,
+// e.g. BTCUSDT.
+func (s *BinanceOrderBookScraper) FetchDepth(pair dia.Pair) (*dia.OrderBookDepth, error) {
+	var book struct {
+		Bids [][]interface{} `json:"bids"`
+		Asks [][]interface{} `json:"asks"`
+	}
+	err := utils.GetJSONWithBackoff(fmt.Sprintf(binanceDepthURL, pair.ForeignName, binanceDepthLimit), &book)
+	if err != nil {
+		return nil, err
+	}
+	return newDepth(pair, dia.BinanceExchange, book.Bids, book.Asks)
+}
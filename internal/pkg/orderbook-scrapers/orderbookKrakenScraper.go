@@ -0,0 +1,46 @@
+package orderbookscrapers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	krakenDepthURL   = "https://api.kraken.com/0/public/Depth?pair=%s&count=%d"
+	krakenDepthLimit = 500
+)
+
+// KrakenOrderBookScraper fetches order books from the Kraken spot API.
+type KrakenOrderBookScraper struct{}
+
+// NewKrakenOrderBookScraper returns a KrakenOrderBookScraper.
+func NewKrakenOrderBookScraper() *KrakenOrderBookScraper {
+	return &KrakenOrderBookScraper{}
+}
+
+// FetchDepth implements OrderBookScraper. The foreign name of @pair is the Kraken pair name,
+// e.g. ADAUSD.
+func (s *KrakenOrderBookScraper) FetchDepth(pair dia.Pair) (*dia.OrderBookDepth, error) {
+	var response struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			Bids [][]interface{} `json:"bids"`
+			Asks [][]interface{} `json:"asks"`
+		} `json:"result"`
+	}
+	err := utils.GetJSONWithBackoff(fmt.Sprintf(krakenDepthURL, pair.ForeignName, krakenDepthLimit), &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Error) > 0 {
+		return nil, fmt.Errorf("depth of %s: %s", pair.ForeignName, strings.Join(response.Error, ", "))
+	}
+	// The result is keyed by the internal pair name, e.g. XXBTZUSD for XBTUSD.
+	for _, book := range response.Result {
+		return newDepth(pair, dia.KrakenExchange, book.Bids, book.Asks)
+	}
+	return nil, fmt.Errorf("no order book of %s", pair.ForeignName)
+}
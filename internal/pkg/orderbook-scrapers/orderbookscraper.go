@@ -0,0 +1,109 @@
+package orderbookscrapers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// OrderBookScraper fetches order book snapshots from the API of an exchange.
+type OrderBookScraper interface {
+	// FetchDepth returns the depth of the order book of @pair within dia.OrderBookDepthBand of
+	// its mid price.
+	FetchDepth(pair dia.Pair) (*dia.OrderBookDepth, error)
+}
+
+// New returns the order book scraper of @exchange, or nil if there is none.
+func New(exchange string) OrderBookScraper {
+	switch exchange {
+	case dia.BinanceExchange:
+		return NewBinanceOrderBookScraper()
+	case dia.KrakenExchange:
+		return NewKrakenOrderBookScraper()
+	default:
+		return nil
+	}
+}
+
+// Level is the total amount of the orders at a price.
+type Level struct {
+	Price  float64
+	Amount float64
+}
+
+// Depth returns the mid price of the book with @bids and @asks along with the amounts offered
+// within @band of the mid price. It returns false if either side of the book is empty.
+func Depth(bids []Level, asks []Level, band float64) (midPrice float64, bidDepth float64, askDepth float64, ok bool) {
+	if len(bids) == 0 || len(asks) == 0 {
+		return 0, 0, 0, false
+	}
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	midPrice = (bids[0].Price + asks[0].Price) / 2
+	for _, level := range bids {
+		if level.Price < midPrice*(1-band) {
+			break
+		}
+		bidDepth += level.Amount
+	}
+	for _, level := range asks {
+		if level.Price > midPrice*(1+band) {
+			break
+		}
+		askDepth += level.Amount
+	}
+	return midPrice, bidDepth, askDepth, true
+}
+
+// newDepth computes the depth of @pair on @exchange from the levels of its book.
+func newDepth(pair dia.Pair, exchange string, bidRows [][]interface{}, askRows [][]interface{}) (*dia.OrderBookDepth, error) {
+	bids, err := parseLevels(bidRows)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseLevels(askRows)
+	if err != nil {
+		return nil, err
+	}
+	midPrice, bidDepth, askDepth, ok := Depth(bids, asks, dia.OrderBookDepthBand)
+	if !ok {
+		return nil, fmt.Errorf("empty order book of %s on %s", pair.ForeignName, exchange)
+	}
+	return &dia.OrderBookDepth{
+		Symbol:   pair.Symbol,
+		Pair:     pair.ForeignName,
+		Exchange: exchange,
+		MidPrice: midPrice,
+		BidDepth: bidDepth,
+		AskDepth: askDepth,
+		Time:     time.Now(),
+	}, nil
+}
+
+// parseLevels parses levels given as arrays starting with price and amount strings, the format
+// of most exchange APIs.
+func parseLevels(rows [][]interface{}) ([]Level, error) {
+	levels := make([]Level, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("invalid level %v", row)
+		}
+		var level Level
+		for i, value := range []*float64{&level.Price, &level.Amount} {
+			s, ok := row[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid level %v", row)
+			}
+			var err error
+			*value, err = strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, err
+			}
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
@@ -0,0 +1,28 @@
+package orderbookscrapers
+
+import "testing"
+
+func TestDepth(t *testing.T) {
+	bids := []Level{{Price: 97, Amount: 4}, {Price: 99, Amount: 1}, {Price: 98, Amount: 2}}
+	asks := []Level{{Price: 103, Amount: 8}, {Price: 101, Amount: 3}}
+	midPrice, bidDepth, askDepth, ok := Depth(bids, asks, 0.02)
+	if !ok || midPrice != 100 || bidDepth != 3 || askDepth != 3 {
+		t.Errorf("unexpected depth %v %v %v %v", midPrice, bidDepth, askDepth, ok)
+	}
+	if _, _, _, ok := Depth(bids, nil, 0.02); ok {
+		t.Error("expected no depth of a book without asks")
+	}
+}
+
+func TestParseLevels(t *testing.T) {
+	levels, err := parseLevels([][]interface{}{{"50000.10", "0.25"}, {"49999.00", "1.5", 1617235200}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 2 || levels[0] != (Level{Price: 50000.10, Amount: 0.25}) || levels[1] != (Level{Price: 49999, Amount: 1.5}) {
+		t.Errorf("unexpected levels %v", levels)
+	}
+	if _, err := parseLevels([][]interface{}{{50000.10, "0.25"}}); err == nil {
+		t.Error("expected error for numeric price")
+	}
+}
@@ -0,0 +1,167 @@
+package perpetualscrapers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	binancePerpetualWSURL        = "wss://fstream.binance.com/ws"
+	binancePerpetualExchangeInfo = "https://fapi.binance.com/fapi/v1/exchangeInfo"
+	// binanceMaxStreams is the number of streams subscribed to per message.
+	binanceMaxStreams = 100
+)
+
+// binancePerpetualAPI streams the USD-M perpetuals of Binance, margined in stablecoins.
+type binancePerpetualAPI struct{}
+
+type binanceExchangeInfo struct {
+	Symbols []struct {
+		Symbol       string `json:"symbol"`
+		ContractType string `json:"contractType"`
+		Status       string `json:"status"`
+		BaseAsset    string `json:"baseAsset"`
+	} `json:"symbols"`
+}
+
+type binanceSubscription struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int      `json:"id"`
+}
+
+// Keys of Binance messages differ only in case, and json matches them case insensitively if there
+// is no field of their own.
+type binanceAggTrade struct {
+	Event        string `json:"e"`
+	EventTime    int64  `json:"E"`
+	Symbol       string `json:"s"`
+	ID           int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	TradeTime    int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+}
+
+type binanceMarkPrice struct {
+	Event      string `json:"e"`
+	EventTime  int64  `json:"E"`
+	Symbol     string `json:"s"`
+	MarkPrice  string `json:"p"`
+	IndexPrice string `json:"i"`
+	// EstimatedSettlePrice is only decoded to keep it out of MarkPrice.
+	EstimatedSettlePrice string `json:"P"`
+}
+
+func (api *binancePerpetualAPI) url() string {
+	return binancePerpetualWSURL
+}
+
+func (api *binancePerpetualAPI) fetchInstruments() (instruments []perpetualInstrument, err error) {
+	var info binanceExchangeInfo
+	err = utils.GetJSONWithBackoff(binancePerpetualExchangeInfo, &info)
+	if err != nil {
+		return
+	}
+	for _, symbol := range info.Symbols {
+		if symbol.ContractType != "PERPETUAL" || symbol.Status != "TRADING" {
+			continue
+		}
+		instruments = append(instruments, perpetualInstrument{
+			Name:         symbol.Symbol,
+			Symbol:       symbol.BaseAsset,
+			ContractSize: 1,
+		})
+	}
+	return
+}
+
+// subscriptions subscribes to the aggregated trades of each instrument and to the mark prices of
+// all instruments, which come in a single stream.
+func (api *binancePerpetualAPI) subscriptions(instruments []perpetualInstrument) []interface{} {
+	streams := []string{"!markPrice@arr@1s"}
+	for _, instrument := range instruments {
+		streams = append(streams, strings.ToLower(instrument.Name)+"@aggTrade")
+	}
+	var subscriptions []interface{}
+	for i := 0; i < len(streams); i += binanceMaxStreams {
+		end := i + binanceMaxStreams
+		if end > len(streams) {
+			end = len(streams)
+		}
+		subscriptions = append(subscriptions, binanceSubscription{
+			Method: "SUBSCRIBE",
+			Params: streams[i:end],
+			ID:     len(subscriptions) + 1,
+		})
+	}
+	return subscriptions
+}
+
+func (api *binancePerpetualAPI) parse(message []byte, instruments map[string]perpetualInstrument) (trades []dia.PerpetualTrade, prices []dia.PerpetualPrice, err error) {
+	// The mark prices of all instruments are an array, other messages are objects.
+	if len(message) > 0 && message[0] == '[' {
+		var markPrices []binanceMarkPrice
+		err = json.Unmarshal(message, &markPrices)
+		if err != nil {
+			return
+		}
+		for _, markPrice := range markPrices {
+			instrument, ok := instruments[markPrice.Symbol]
+			if !ok {
+				continue
+			}
+			price := dia.PerpetualPrice{
+				Symbol:     instrument.Symbol,
+				Instrument: instrument.Name,
+				Time:       time.Unix(0, markPrice.EventTime*int64(time.Millisecond)),
+			}
+			price.MarkPrice, _ = strconv.ParseFloat(markPrice.MarkPrice, 64)
+			price.IndexPrice, _ = strconv.ParseFloat(markPrice.IndexPrice, 64)
+			prices = append(prices, price)
+		}
+		return
+	}
+
+	var aggTrade binanceAggTrade
+	err = json.Unmarshal(message, &aggTrade)
+	if err != nil || aggTrade.Event != "aggTrade" {
+		// Answers to subscriptions
+		return nil, nil, err
+	}
+	instrument, ok := instruments[aggTrade.Symbol]
+	if !ok {
+		return
+	}
+	price, err := strconv.ParseFloat(aggTrade.Price, 64)
+	if err != nil {
+		return
+	}
+	volume, err := strconv.ParseFloat(aggTrade.Quantity, 64)
+	if err != nil {
+		return
+	}
+	// The taker sold if the buyer is the maker.
+	if aggTrade.IsBuyerMaker {
+		volume = -volume
+	}
+	trades = append(trades, dia.PerpetualTrade{
+		Symbol:         instrument.Symbol,
+		Instrument:     instrument.Name,
+		Price:          price,
+		Volume:         volume,
+		ForeignTradeID: strconv.FormatInt(aggTrade.ID, 10),
+		Time:           time.Unix(0, aggTrade.TradeTime*int64(time.Millisecond)),
+	})
+	return
+}
+
+// ping returns nil, as Binance pings and gorilla answers with pongs.
+func (api *binancePerpetualAPI) ping() []byte {
+	return nil
+}
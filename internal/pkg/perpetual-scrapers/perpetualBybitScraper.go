@@ -0,0 +1,202 @@
+package perpetualscrapers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	bybitPerpetualWSURL       = "wss://stream.bybit.com/v5/public/linear"
+	bybitPerpetualInstruments = "https://api.bybit.com/v5/market/instruments-info?category=linear&limit=1000"
+	// bybitMaxArgs is the number of topics subscribed to per message.
+	bybitMaxArgs = 10
+)
+
+// bybitPerpetualAPI streams the linear perpetuals of Bybit.
+type bybitPerpetualAPI struct{}
+
+type bybitInstruments struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol       string `json:"symbol"`
+			ContractType string `json:"contractType"`
+			Status       string `json:"status"`
+			BaseCoin     string `json:"baseCoin"`
+		} `json:"list"`
+		NextPageCursor string `json:"nextPageCursor"`
+	} `json:"result"`
+}
+
+type bybitSubscription struct {
+	Op   string   `json:"op"`
+	Args []string `json:"args"`
+}
+
+type bybitPerpetualMessage struct {
+	Op      string          `json:"op"`
+	Success *bool           `json:"success"`
+	RetMsg  string          `json:"ret_msg"`
+	Topic   string          `json:"topic"`
+	Ts      int64           `json:"ts"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type bybitPublicTrade struct {
+	Time   int64  `json:"T"`
+	Symbol string `json:"s"`
+	Side   string `json:"S"`
+	Volume string `json:"v"`
+	Price  string `json:"p"`
+	ID     string `json:"i"`
+}
+
+// bybitTicker is a snapshot or a delta of the ticker of an instrument, whose fields are empty if
+// they didn't change.
+type bybitTicker struct {
+	Symbol     string `json:"symbol"`
+	MarkPrice  string `json:"markPrice"`
+	IndexPrice string `json:"indexPrice"`
+}
+
+func (api *bybitPerpetualAPI) url() string {
+	return bybitPerpetualWSURL
+}
+
+func (api *bybitPerpetualAPI) fetchInstruments() (instruments []perpetualInstrument, err error) {
+	cursor := ""
+	for {
+		var response bybitInstruments
+		err = utils.GetJSONWithBackoff(bybitPerpetualInstruments+"&cursor="+url.QueryEscape(cursor), &response)
+		if err != nil {
+			return
+		}
+		if response.RetCode != 0 {
+			return nil, fmt.Errorf("bybit instruments: %s", response.RetMsg)
+		}
+		for _, instrument := range response.Result.List {
+			if instrument.ContractType != "LinearPerpetual" || instrument.Status != "Trading" {
+				continue
+			}
+			instruments = append(instruments, perpetualInstrument{
+				Name:         instrument.Symbol,
+				Symbol:       instrument.BaseCoin,
+				ContractSize: 1,
+			})
+		}
+		cursor = response.Result.NextPageCursor
+		if cursor == "" {
+			return
+		}
+	}
+}
+
+// subscriptions subscribes to the trades and the tickers of each instrument, which contain the
+// mark and index price.
+func (api *bybitPerpetualAPI) subscriptions(instruments []perpetualInstrument) []interface{} {
+	var args []string
+	for _, instrument := range instruments {
+		args = append(args, "publicTrade."+instrument.Name, "tickers."+instrument.Name)
+	}
+	var subscriptions []interface{}
+	for i := 0; i < len(args); i += bybitMaxArgs {
+		end := i + bybitMaxArgs
+		if end > len(args) {
+			end = len(args)
+		}
+		subscriptions = append(subscriptions, bybitSubscription{Op: "subscribe", Args: args[i:end]})
+	}
+	return subscriptions
+}
+
+func (api *bybitPerpetualAPI) parse(message []byte, instruments map[string]perpetualInstrument) (trades []dia.PerpetualTrade, prices []dia.PerpetualPrice, err error) {
+	var msg bybitPerpetualMessage
+	err = json.Unmarshal(message, &msg)
+	if err != nil {
+		return
+	}
+	if msg.Success != nil {
+		if !*msg.Success {
+			return nil, nil, fmt.Errorf("%s: %s", msg.Op, msg.RetMsg)
+		}
+		// Answers to subscriptions and pings
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(msg.Topic, "publicTrade."):
+		var publicTrades []bybitPublicTrade
+		err = json.Unmarshal(msg.Data, &publicTrades)
+		if err != nil {
+			return
+		}
+		for _, t := range publicTrades {
+			instrument, ok := instruments[t.Symbol]
+			if !ok {
+				continue
+			}
+			price, err := strconv.ParseFloat(t.Price, 64)
+			if err != nil {
+				return nil, nil, err
+			}
+			volume, err := strconv.ParseFloat(t.Volume, 64)
+			if err != nil {
+				return nil, nil, err
+			}
+			if t.Side == "Sell" {
+				volume = -volume
+			}
+			trades = append(trades, dia.PerpetualTrade{
+				Symbol:         instrument.Symbol,
+				Instrument:     instrument.Name,
+				Price:          price,
+				Volume:         volume,
+				ForeignTradeID: t.ID,
+				Time:           time.Unix(0, t.Time*int64(time.Millisecond)),
+			})
+		}
+	case strings.HasPrefix(msg.Topic, "tickers."):
+		var ticker bybitTicker
+		err = json.Unmarshal(msg.Data, &ticker)
+		if err != nil {
+			return
+		}
+		instrument, ok := instruments[ticker.Symbol]
+		if !ok {
+			return
+		}
+		price := dia.PerpetualPrice{
+			Symbol:     instrument.Symbol,
+			Instrument: instrument.Name,
+			Time:       time.Unix(0, msg.Ts*int64(time.Millisecond)),
+		}
+		if ticker.MarkPrice != "" {
+			price.MarkPrice, err = strconv.ParseFloat(ticker.MarkPrice, 64)
+			if err != nil {
+				return
+			}
+		}
+		if ticker.IndexPrice != "" {
+			price.IndexPrice, err = strconv.ParseFloat(ticker.IndexPrice, 64)
+			if err != nil {
+				return
+			}
+		}
+		if price.MarkPrice > 0 || price.IndexPrice > 0 {
+			prices = append(prices, price)
+		}
+	}
+	return
+}
+
+func (api *bybitPerpetualAPI) ping() []byte {
+	return []byte(`{"op":"ping"}`)
+}
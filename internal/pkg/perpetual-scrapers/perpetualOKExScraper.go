@@ -0,0 +1,209 @@
+package perpetualscrapers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	okexPerpetualWSURL       = "wss://ws.okx.com:8443/ws/v5/public"
+	okexPerpetualInstruments = "https://www.okx.com/api/v5/public/instruments?instType=SWAP"
+	// okexMaxArgs is the number of channels subscribed to per message.
+	okexMaxArgs = 100
+)
+
+// okexPerpetualAPI streams the linear perpetual swaps of OKX.
+type okexPerpetualAPI struct{}
+
+type okexInstruments struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		InstID    string `json:"instId"`
+		Uly       string `json:"uly"`
+		CtVal     string `json:"ctVal"`
+		CtValCcy  string `json:"ctValCcy"`
+		CtType    string `json:"ctType"`
+		SettleCcy string `json:"settleCcy"`
+		State     string `json:"state"`
+	} `json:"data"`
+}
+
+type okexArg struct {
+	Channel string `json:"channel"`
+	InstID  string `json:"instId"`
+}
+
+type okexSubscription struct {
+	Op   string    `json:"op"`
+	Args []okexArg `json:"args"`
+}
+
+type okexPerpetualMessage struct {
+	Event string  `json:"event"`
+	Code  string  `json:"code"`
+	Msg   string  `json:"msg"`
+	Arg   okexArg `json:"arg"`
+	Data  []struct {
+		InstID  string `json:"instId"`
+		TradeID string `json:"tradeId"`
+		Px      string `json:"px"`
+		Sz      string `json:"sz"`
+		Side    string `json:"side"`
+		MarkPx  string `json:"markPx"`
+		IdxPx   string `json:"idxPx"`
+		Ts      string `json:"ts"`
+	} `json:"data"`
+}
+
+func (api *okexPerpetualAPI) url() string {
+	return okexPerpetualWSURL
+}
+
+// fetchInstruments returns the live swaps settled in stablecoins, whose contracts are worth
+// ctVal of the underlying asset.
+func (api *okexPerpetualAPI) fetchInstruments() (instruments []perpetualInstrument, err error) {
+	var response okexInstruments
+	err = utils.GetJSONWithBackoff(okexPerpetualInstruments, &response)
+	if err != nil {
+		return
+	}
+	if response.Code != "0" {
+		return nil, fmt.Errorf("okex instruments: %s", response.Msg)
+	}
+	for _, instrument := range response.Data {
+		if instrument.CtType != "linear" || instrument.State != "live" {
+			continue
+		}
+		if instrument.SettleCcy != "USDT" && instrument.SettleCcy != "USDC" {
+			continue
+		}
+		contractSize, err := strconv.ParseFloat(instrument.CtVal, 64)
+		if err != nil {
+			log.Errorf("contract value of %s: %v", instrument.InstID, err)
+			continue
+		}
+		instruments = append(instruments, perpetualInstrument{
+			Name:         instrument.InstID,
+			Symbol:       instrument.CtValCcy,
+			Index:        instrument.Uly,
+			ContractSize: contractSize,
+		})
+	}
+	return instruments, nil
+}
+
+// subscriptions subscribes to the trades and mark prices of each instrument and to the prices of
+// their indices.
+func (api *okexPerpetualAPI) subscriptions(instruments []perpetualInstrument) []interface{} {
+	var args []okexArg
+	indices := make(map[string]bool)
+	for _, instrument := range instruments {
+		args = append(args, okexArg{Channel: "trades", InstID: instrument.Name}, okexArg{Channel: "mark-price", InstID: instrument.Name})
+		if !indices[instrument.Index] {
+			indices[instrument.Index] = true
+			args = append(args, okexArg{Channel: "index-tickers", InstID: instrument.Index})
+		}
+	}
+	var subscriptions []interface{}
+	for i := 0; i < len(args); i += okexMaxArgs {
+		end := i + okexMaxArgs
+		if end > len(args) {
+			end = len(args)
+		}
+		subscriptions = append(subscriptions, okexSubscription{Op: "subscribe", Args: args[i:end]})
+	}
+	return subscriptions
+}
+
+func (api *okexPerpetualAPI) parse(message []byte, instruments map[string]perpetualInstrument) (trades []dia.PerpetualTrade, prices []dia.PerpetualPrice, err error) {
+	if string(message) == "pong" {
+		return
+	}
+	var msg okexPerpetualMessage
+	err = json.Unmarshal(message, &msg)
+	if err != nil {
+		return
+	}
+	if msg.Event == "error" {
+		return nil, nil, fmt.Errorf("code %s: %s", msg.Code, msg.Msg)
+	}
+
+	for _, data := range msg.Data {
+		ts, err := strconv.ParseInt(data.Ts, 10, 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		timestamp := time.Unix(0, ts*int64(time.Millisecond))
+
+		switch msg.Arg.Channel {
+		case "trades":
+			instrument, ok := instruments[data.InstID]
+			if !ok {
+				continue
+			}
+			price, err := strconv.ParseFloat(data.Px, 64)
+			if err != nil {
+				return nil, nil, err
+			}
+			size, err := strconv.ParseFloat(data.Sz, 64)
+			if err != nil {
+				return nil, nil, err
+			}
+			// The size is the number of contracts.
+			volume := size * instrument.ContractSize
+			if data.Side == "sell" {
+				volume = -volume
+			}
+			trades = append(trades, dia.PerpetualTrade{
+				Symbol:         instrument.Symbol,
+				Instrument:     instrument.Name,
+				Price:          price,
+				Volume:         volume,
+				ForeignTradeID: data.TradeID,
+				Time:           timestamp,
+			})
+		case "mark-price":
+			instrument, ok := instruments[data.InstID]
+			if !ok {
+				continue
+			}
+			markPrice, err := strconv.ParseFloat(data.MarkPx, 64)
+			if err != nil {
+				return nil, nil, err
+			}
+			prices = append(prices, dia.PerpetualPrice{
+				Symbol:     instrument.Symbol,
+				Instrument: instrument.Name,
+				MarkPrice:  markPrice,
+				Time:       timestamp,
+			})
+		case "index-tickers":
+			indexPrice, err := strconv.ParseFloat(data.IdxPx, 64)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, instrument := range instruments {
+				if instrument.Index != data.InstID {
+					continue
+				}
+				prices = append(prices, dia.PerpetualPrice{
+					Symbol:     instrument.Symbol,
+					Instrument: instrument.Name,
+					IndexPrice: indexPrice,
+					Time:       timestamp,
+				})
+			}
+		}
+	}
+	return
+}
+
+func (api *okexPerpetualAPI) ping() []byte {
+	return []byte("ping")
+}
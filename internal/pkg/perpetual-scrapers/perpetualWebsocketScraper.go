@@ -0,0 +1,224 @@
+package perpetualscrapers
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+	ws "github.com/gorilla/websocket"
+)
+
+const (
+	// subscribeDelay is the time between two subscription messages, which exchanges limit to a
+	// few per second.
+	subscribeDelay = 250 * time.Millisecond
+	pingInterval   = 20 * time.Second
+)
+
+type nothing struct{}
+
+// perpetualWebsocketScraper implements PerpetualScraper for a perpetualAPI.
+type perpetualWebsocketScraper struct {
+	api      perpetualAPI
+	wsClient *ws.Conn
+	// wsLock serializes writes to wsClient and its replacement on reconnects
+	wsLock sync.Mutex
+	// instruments are the scraped instruments by name, only used by mainLoop
+	instruments map[string]perpetualInstrument
+	prices      *perpetualPrices
+	// signaling channels for session initialization and finishing
+	run          bool
+	shutdown     chan nothing
+	shutdownDone chan nothing
+	// error handling; to read error or closed, first acquire read lock
+	// only cleanup method should hold write lock
+	errorLock    sync.RWMutex
+	error        error
+	closed       bool
+	exchangeName string
+	chanTrades   chan *dia.PerpetualTrade
+	chanPrices   chan *dia.PerpetualPrice
+}
+
+func newPerpetualWebsocketScraper(exchange string, api perpetualAPI) *perpetualWebsocketScraper {
+	s := &perpetualWebsocketScraper{
+		api:          api,
+		prices:       newPerpetualPrices(),
+		run:          true,
+		shutdown:     make(chan nothing),
+		shutdownDone: make(chan nothing),
+		exchangeName: exchange,
+		chanTrades:   make(chan *dia.PerpetualTrade),
+		chanPrices:   make(chan *dia.PerpetualPrice),
+	}
+	go s.mainLoop()
+	if api.ping() != nil {
+		go s.ping()
+	}
+	return s
+}
+
+// runs in a goroutine until s is closed
+func (s *perpetualWebsocketScraper) mainLoop() {
+	defer s.cleanup(errors.New(s.exchangeName + "PerpetualScraper: terminated by Close()"))
+	if err := s.reconnectToWS(); err != nil {
+		return
+	}
+	for s.run {
+		_, message, err := s.wsClient.ReadMessage()
+		if err != nil {
+			if !s.run {
+				return
+			}
+			log.Warning("reconnect the scraping to ws, ", err)
+			if err := s.reconnectToWS(); err != nil {
+				return
+			}
+			continue
+		}
+		trades, prices, err := s.api.parse(message, s.instruments)
+		if err != nil {
+			log.Errorf("%s: %v", s.exchangeName, err)
+			continue
+		}
+		for i := range trades {
+			trade := trades[i]
+			trade.Exchange = s.exchangeName
+			select {
+			case s.chanTrades <- &trade:
+			case <-s.shutdown:
+				return
+			}
+		}
+		for _, update := range prices {
+			update.Exchange = s.exchangeName
+			price, ok := s.prices.update(update)
+			if !ok {
+				continue
+			}
+			select {
+			case s.chanPrices <- &price:
+			case <-s.shutdown:
+				return
+			}
+		}
+	}
+}
+
+// reconnectToWS fetches the instruments, connects and subscribes to them. It retries with
+// increasing delays until it succeeds or the scraper is closed, so that instruments listed in
+// the meantime are scraped after reconnecting.
+func (s *perpetualWebsocketScraper) reconnectToWS() error {
+	for attempt := 0; s.run; attempt++ {
+		err := s.connect()
+		if err == nil {
+			return nil
+		}
+		log.Errorf("%s: connect: %v", s.exchangeName, err)
+		select {
+		case <-time.After(utils.DefaultBackoff.Delay(attempt)):
+		case <-s.shutdown:
+		}
+	}
+	return errors.New(s.exchangeName + "PerpetualScraper: closed while reconnecting")
+}
+
+func (s *perpetualWebsocketScraper) connect() error {
+	instruments, err := s.api.fetchInstruments()
+	if err != nil {
+		return err
+	}
+	var wsDialer ws.Dialer
+	SwConn, _, err := wsDialer.Dial(s.api.url(), nil)
+	if err != nil {
+		return err
+	}
+	s.wsLock.Lock()
+	if s.wsClient != nil {
+		s.wsClient.Close()
+	}
+	s.wsClient = SwConn
+	s.wsLock.Unlock()
+
+	s.instruments = make(map[string]perpetualInstrument)
+	for _, instrument := range instruments {
+		s.instruments[instrument.Name] = instrument
+	}
+	for i, subscription := range s.api.subscriptions(instruments) {
+		if i > 0 {
+			time.Sleep(subscribeDelay)
+		}
+		s.wsLock.Lock()
+		err = s.wsClient.WriteJSON(subscription)
+		s.wsLock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	log.Infof("%s: subscribed to %d perpetuals", s.exchangeName, len(instruments))
+	return nil
+}
+
+// ping keeps the connection alive.
+func (s *perpetualWebsocketScraper) ping() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.wsLock.Lock()
+			if s.wsClient != nil {
+				if err := s.wsClient.WriteMessage(ws.TextMessage, s.api.ping()); err != nil {
+					log.Warn("ping: ", err)
+				}
+			}
+			s.wsLock.Unlock()
+		case <-s.shutdown:
+			return
+		}
+	}
+}
+
+// Close channels for shutdown
+func (s *perpetualWebsocketScraper) cleanup(err error) {
+	s.errorLock.Lock()
+	defer s.errorLock.Unlock()
+	if err != nil {
+		s.error = err
+	}
+	s.closed = true
+	close(s.chanTrades)
+	close(s.chanPrices)
+	close(s.shutdownDone)
+}
+
+// Close closes the connection and the channels of trades and prices.
+func (s *perpetualWebsocketScraper) Close() error {
+	if s.closed {
+		return errors.New(s.exchangeName + "PerpetualScraper: Already closed")
+	}
+	// Set false first to prevent reconnect
+	s.run = false
+	close(s.shutdown)
+	s.wsLock.Lock()
+	if s.wsClient != nil {
+		s.wsClient.Close()
+	}
+	s.wsLock.Unlock()
+	<-s.shutdownDone
+	s.errorLock.RLock()
+	defer s.errorLock.RUnlock()
+	return s.error
+}
+
+// Trades implements PerpetualScraper.
+func (s *perpetualWebsocketScraper) Trades() chan *dia.PerpetualTrade {
+	return s.chanTrades
+}
+
+// Prices implements PerpetualScraper.
+func (s *perpetualWebsocketScraper) Prices() chan *dia.PerpetualPrice {
+	return s.chanPrices
+}
@@ -0,0 +1,111 @@
+package perpetualscrapers
+
+import (
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// priceInterval is the minimal time between two prices of an instrument sent by a scraper, as
+// exchanges stream mark and index prices up to ten times per second.
+const priceInterval = time.Second
+
+// PerpetualScraper streams the trades and the mark and index prices of the perpetual futures
+// of a derivatives exchange. They are kept apart from the trades of spot markets.
+type PerpetualScraper interface {
+	// Trades returns the channel of trades, which is closed when the scraper is closed.
+	Trades() chan *dia.PerpetualTrade
+	// Prices returns the channel of mark and index prices, which is closed when the scraper is
+	// closed.
+	Prices() chan *dia.PerpetualPrice
+	Close() error
+}
+
+// New returns the perpetual futures scraper of @exchange, or nil if there is none.
+func New(exchange string) PerpetualScraper {
+	switch exchange {
+	case dia.BinanceExchange:
+		return newPerpetualWebsocketScraper(exchange, &binancePerpetualAPI{})
+	case dia.OKExExchange:
+		return newPerpetualWebsocketScraper(exchange, &okexPerpetualAPI{})
+	case dia.BybitExchange:
+		return newPerpetualWebsocketScraper(exchange, &bybitPerpetualAPI{})
+	default:
+		return nil
+	}
+}
+
+// perpetualInstrument is a perpetual future traded on an exchange.
+type perpetualInstrument struct {
+	// Name is the name of the instrument on the exchange, e.g. BTCUSDT.
+	Name string
+	// Symbol is the symbol of the underlying asset, e.g. BTC.
+	Symbol string
+	// Index is the name of the index of the instrument on exchanges streaming index prices by
+	// index instead of by instrument, e.g. BTC-USDT on OKX.
+	Index string
+	// ContractSize is the amount of the underlying asset of one contract.
+	ContractSize float64
+}
+
+// perpetualAPI is the websocket API of a derivatives exchange streaming the trades and prices of
+// its perpetual futures.
+type perpetualAPI interface {
+	url() string
+	// fetchInstruments returns the perpetual futures to be scraped.
+	fetchInstruments() ([]perpetualInstrument, error)
+	// subscriptions returns the messages subscribing to the trades and prices of @instruments.
+	subscriptions(instruments []perpetualInstrument) []interface{}
+	// parse returns the trades and prices in @message of the instruments in @instruments.
+	// Prices carry the mark price, the index price or both, the other one is zero. Exchange is
+	// not set.
+	parse(message []byte, instruments map[string]perpetualInstrument) ([]dia.PerpetualTrade, []dia.PerpetualPrice, error)
+	// ping returns the message keeping the connection alive, or nil if the exchange pings.
+	ping() []byte
+}
+
+// perpetualPrices merges the updates of the mark and the index price of instruments, which
+// some exchanges stream separately, and throttles them to one price per priceInterval.
+type perpetualPrices struct {
+	latest map[string]dia.PerpetualPrice
+	sent   map[string]time.Time
+}
+
+func newPerpetualPrices() *perpetualPrices {
+	return &perpetualPrices{
+		latest: make(map[string]dia.PerpetualPrice),
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// update merges @update into the latest price of its instrument. It returns the latest price
+// once both mark and index price are known and the last price of the instrument was returned at
+// least priceInterval earlier.
+func (p *perpetualPrices) update(update dia.PerpetualPrice) (dia.PerpetualPrice, bool) {
+	price := p.latest[update.Instrument]
+	price.Symbol = update.Symbol
+	price.Instrument = update.Instrument
+	price.Exchange = update.Exchange
+	if update.MarkPrice > 0 {
+		price.MarkPrice = update.MarkPrice
+	}
+	if update.IndexPrice > 0 {
+		price.IndexPrice = update.IndexPrice
+	}
+	if update.Time.After(price.Time) {
+		price.Time = update.Time
+	}
+	p.latest[update.Instrument] = price
+
+	if price.MarkPrice == 0 || price.IndexPrice == 0 {
+		return price, false
+	}
+	if sent, ok := p.sent[update.Instrument]; ok && price.Time.Sub(sent) < priceInterval {
+		return price, false
+	}
+	p.sent[update.Instrument] = price.Time
+	return price, true
+}
@@ -0,0 +1,102 @@
+package perpetualscrapers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestPerpetualPricesUpdate(t *testing.T) {
+	prices := newPerpetualPrices()
+	start := time.Unix(1700000000, 0)
+
+	if _, ok := prices.update(dia.PerpetualPrice{Instrument: "BTC-USDT-SWAP", MarkPrice: 37000, Time: start}); ok {
+		t.Fatal("expected no price without index price")
+	}
+	price, ok := prices.update(dia.PerpetualPrice{Instrument: "BTC-USDT-SWAP", IndexPrice: 36990, Time: start.Add(100 * time.Millisecond)})
+	if !ok || price.MarkPrice != 37000 || price.IndexPrice != 36990 {
+		t.Fatalf("expected merged price, got %v %v", price, ok)
+	}
+	if _, ok := prices.update(dia.PerpetualPrice{Instrument: "BTC-USDT-SWAP", MarkPrice: 37010, Time: start.Add(500 * time.Millisecond)}); ok {
+		t.Fatal("expected price to be throttled")
+	}
+	price, ok = prices.update(dia.PerpetualPrice{Instrument: "BTC-USDT-SWAP", IndexPrice: 37000, Time: start.Add(1100 * time.Millisecond)})
+	if !ok || price.MarkPrice != 37010 || price.IndexPrice != 37000 {
+		t.Fatalf("expected latest price, got %v %v", price, ok)
+	}
+}
+
+func TestBinancePerpetualParse(t *testing.T) {
+	api := &binancePerpetualAPI{}
+	instruments := map[string]perpetualInstrument{"BTCUSDT": {Name: "BTCUSDT", Symbol: "BTC", ContractSize: 1}}
+
+	trades, _, err := api.parse([]byte(`{"e":"aggTrade","E":1700000000100,"a":26129,"s":"BTCUSDT","p":"37000.10","q":"0.250","f":100,"l":105,"T":1700000000050,"m":true}`), instruments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 || trades[0].Symbol != "BTC" || trades[0].Price != 37000.1 || trades[0].Volume != -0.25 || trades[0].ForeignTradeID != "26129" {
+		t.Fatalf("unexpected trades %v", trades)
+	}
+
+	_, prices, err := api.parse([]byte(`[{"e":"markPriceUpdate","E":1700000001000,"s":"BTCUSDT","p":"37001.5","i":"36995.2","P":"37000","r":"0.0001","T":1700006400000},
+		{"e":"markPriceUpdate","E":1700000001000,"s":"ETHUSDT","p":"2000","i":"1999","P":"2000","r":"0.0001","T":1700006400000}]`), instruments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 1 || prices[0].MarkPrice != 37001.5 || prices[0].IndexPrice != 36995.2 {
+		t.Fatalf("unexpected prices %v", prices)
+	}
+}
+
+func TestOKExPerpetualParse(t *testing.T) {
+	api := &okexPerpetualAPI{}
+	instruments := map[string]perpetualInstrument{"BTC-USDT-SWAP": {Name: "BTC-USDT-SWAP", Symbol: "BTC", Index: "BTC-USDT", ContractSize: 0.01}}
+
+	trades, _, err := api.parse([]byte(`{"arg":{"channel":"trades","instId":"BTC-USDT-SWAP"},"data":[{"instId":"BTC-USDT-SWAP","tradeId":"130639474","px":"37000","sz":"30","side":"buy","ts":"1700000000000"}]}`), instruments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 || trades[0].Volume != 0.3 || trades[0].Time != time.Unix(1700000000, 0) {
+		t.Fatalf("unexpected trades %v", trades)
+	}
+
+	_, prices, err := api.parse([]byte(`{"arg":{"channel":"index-tickers","instId":"BTC-USDT"},"data":[{"instId":"BTC-USDT","idxPx":"36990","ts":"1700000000000"}]}`), instruments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 1 || prices[0].Instrument != "BTC-USDT-SWAP" || prices[0].IndexPrice != 36990 || prices[0].MarkPrice != 0 {
+		t.Fatalf("unexpected prices %v", prices)
+	}
+
+	if _, _, err := api.parse([]byte(`{"event":"error","code":"60012","msg":"Invalid request"}`), instruments); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestBybitPerpetualParse(t *testing.T) {
+	api := &bybitPerpetualAPI{}
+	instruments := map[string]perpetualInstrument{"BTCUSDT": {Name: "BTCUSDT", Symbol: "BTC", ContractSize: 1}}
+
+	trades, _, err := api.parse([]byte(`{"topic":"publicTrade.BTCUSDT","type":"snapshot","ts":1700000000100,"data":[{"T":1700000000050,"s":"BTCUSDT","S":"Sell","v":"0.001","p":"37000.00","L":"PlusTick","i":"20f43950-d8dd-5b31-9112-a178eb6023af","BT":false}]}`), instruments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 1 || trades[0].Volume != -0.001 || trades[0].ForeignTradeID != "20f43950-d8dd-5b31-9112-a178eb6023af" {
+		t.Fatalf("unexpected trades %v", trades)
+	}
+
+	// Deltas only contain the fields which changed.
+	_, prices, err := api.parse([]byte(`{"topic":"tickers.BTCUSDT","type":"delta","ts":1700000000200,"cs":24987956059,"data":{"symbol":"BTCUSDT","markPrice":"37001.20"}}`), instruments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 1 || prices[0].MarkPrice != 37001.2 || prices[0].IndexPrice != 0 {
+		t.Fatalf("unexpected prices %v", prices)
+	}
+
+	trades, prices, err = api.parse([]byte(`{"success":true,"ret_msg":"pong","conn_id":"0970e817-426e-429a-a679-ff7f55e0b16a","op":"ping"}`), instruments)
+	if err != nil || len(trades) != 0 || len(prices) != 0 {
+		t.Fatalf("expected pong to be ignored, got %v %v %v", trades, prices, err)
+	}
+}
@@ -0,0 +1,128 @@
+package stakingscrapers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/ethhelper"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// liquidStakingTimeout bounds the calls of one update of the rates.
+const liquidStakingTimeout = time.Minute
+
+// liquidStakingToken is a liquid staking token on Ethereum whose contract has a view function
+// without arguments which returns the underlying per token with 18 decimals.
+type liquidStakingToken struct {
+	underlying string
+	address    common.Address
+	method     string
+}
+
+// liquidStakingTokens are the liquid staking tokens with a ContractRateScraper by symbol.
+var liquidStakingTokens = map[string]liquidStakingToken{
+	// wstETH wraps stETH, whose balances rebase to stay 1:1 with the ETH pooled by Lido. The
+	// stETH per wstETH is the accrued staking yield.
+	"wstETH": {"stETH", common.HexToAddress("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0"), "stEthPerToken"},
+	"rETH":   {"ETH", common.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393"), "getExchangeRate"},
+	"cbETH":  {"ETH", common.HexToAddress("0xBe9895146f7AF43049ca1c1AE358B0541Ea49704"), "exchangeRate"},
+	// sfrxETH is the ERC-4626 vault of frxETH which accrues the staking yield of Frax.
+	"sfrxETH": {"frxETH", common.HexToAddress("0xac3E018457B222d93114458476f3E3416Abbe38F"), "pricePerShare"},
+	"swETH":   {"ETH", common.HexToAddress("0xf951E335afb289353dc249e82926178EaC7DEd78"), "getRate"},
+}
+
+// LiquidStakingTokens returns the symbols of the liquid staking tokens a ContractRateScraper reads.
+func LiquidStakingTokens() []string {
+	var tokens []string
+	for token := range liquidStakingTokens {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// ContractRateScraper reads the redemption rates of liquid staking tokens on Ethereum from their
+// contracts with a single multicall per block.
+type ContractRateScraper struct {
+	client *ethclient.Client
+	tokens []string
+	abi    abi.ABI
+}
+
+// NewContractRateScraper returns a ContractRateScraper of the liquid staking tokens @tokens.
+func NewContractRateScraper(tokens []string) (*ContractRateScraper, error) {
+	// All rate functions have the same signature, so that one ABI packs and unpacks all calls.
+	var methods []string
+	for _, token := range tokens {
+		lst, ok := liquidStakingTokens[token]
+		if !ok {
+			return nil, fmt.Errorf("no liquid staking scraper for token %s", token)
+		}
+		methods = append(methods, fmt.Sprintf(`{"inputs":[],"name":"%s","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}`, lst.method))
+	}
+	parsedABI, err := abi.JSON(strings.NewReader("[" + strings.Join(methods, ",") + "]"))
+	if err != nil {
+		return nil, err
+	}
+	client, err := ethhelper.NewETHClient()
+	if err != nil {
+		return nil, err
+	}
+	return &ContractRateScraper{client: client, tokens: tokens, abi: parsedABI}, nil
+}
+
+// BlockNumber returns the number of the latest block.
+func (s *ContractRateScraper) BlockNumber() (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), liquidStakingTimeout)
+	defer cancel()
+	return s.client.BlockNumber(ctx)
+}
+
+// FetchRates implements LiquidStakingScraper. Tokens whose call reverts are logged and left out.
+func (s *ContractRateScraper) FetchRates(block uint64) ([]dia.LiquidStakingRate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), liquidStakingTimeout)
+	defer cancel()
+
+	calls := make([]ethhelper.Call, len(s.tokens))
+	for i, token := range s.tokens {
+		lst := liquidStakingTokens[token]
+		input, err := s.abi.Pack(lst.method)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = ethhelper.Call{Target: lst.address, CallData: input}
+	}
+	results, err := ethhelper.Multicall(ctx, s.client, calls, len(calls), new(big.Int).SetUint64(block))
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	var rates []dia.LiquidStakingRate
+	for i, token := range s.tokens {
+		lst := liquidStakingTokens[token]
+		if !results[i].Success {
+			log.Errorf("rate of %s: call of %s reverted", token, lst.method)
+			continue
+		}
+		out, err := s.abi.Unpack(lst.method, results[i].ReturnData)
+		if err != nil || len(out) == 0 {
+			log.Errorf("rate of %s: %v", token, err)
+			continue
+		}
+		rate, _ := new(big.Float).Quo(new(big.Float).SetInt(out[0].(*big.Int)), big.NewFloat(1e18)).Float64()
+		rates = append(rates, dia.LiquidStakingRate{
+			Token:      token,
+			Underlying: lst.underlying,
+			Rate:       rate,
+			Block:      block,
+			Time:       t,
+		})
+	}
+	return rates, nil
+}
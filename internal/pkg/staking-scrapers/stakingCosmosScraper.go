@@ -0,0 +1,75 @@
+package stakingscrapers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const cosmosRESTURL = "https://cosmos-rest.publicnode.com"
+
+// CosmosStakingScraper computes the staking yield of ATOM from the inflation net of the community
+// tax, which is distributed to the bonded share of the supply.
+type CosmosStakingScraper struct {
+	url string
+}
+
+// NewCosmosStakingScraper returns a CosmosStakingScraper using the Cosmos Hub REST API at @url.
+func NewCosmosStakingScraper(url string) *CosmosStakingScraper {
+	return &CosmosStakingScraper{url: url}
+}
+
+// FetchYield implements StakingScraper.
+func (s *CosmosStakingScraper) FetchYield() (dia.StakingYield, error) {
+	var inflation struct {
+		Inflation string `json:"inflation"`
+	}
+	var pool struct {
+		Pool struct {
+			BondedTokens string `json:"bonded_tokens"`
+		} `json:"pool"`
+	}
+	var supply struct {
+		Amount struct {
+			Amount string `json:"amount"`
+		} `json:"amount"`
+	}
+	var distribution struct {
+		Params struct {
+			CommunityTax string `json:"community_tax"`
+		} `json:"params"`
+	}
+	for path, v := range map[string]interface{}{
+		"/cosmos/mint/v1beta1/inflation":                   &inflation,
+		"/cosmos/staking/v1beta1/pool":                     &pool,
+		"/cosmos/bank/v1beta1/supply/by_denom?denom=uatom": &supply,
+		"/cosmos/distribution/v1beta1/params":              &distribution,
+	} {
+		err := utils.GetJSONWithBackoff(s.url+path, v)
+		if err != nil {
+			return dia.StakingYield{}, err
+		}
+	}
+
+	var values [4]float64
+	for i, value := range []string{inflation.Inflation, pool.Pool.BondedTokens, supply.Amount.Amount, distribution.Params.CommunityTax} {
+		var err error
+		values[i], err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return dia.StakingYield{}, err
+		}
+	}
+	rate, bonded, total, communityTax := values[0], values[1], values[2], values[3]
+	if bonded == 0 {
+		return dia.StakingYield{}, errors.New("no bonded tokens")
+	}
+	return dia.StakingYield{
+		Asset:  "ATOM",
+		Source: "Cosmos Hub inflation",
+		APR:    rate * (1 - communityTax) * total / bonded * 100,
+		Time:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package stakingscrapers
+
+import (
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const beaconchainURL = "https://beaconcha.in"
+
+// EthereumStakingScraper fetches ETH.STORE, the daily staking yield of all active beacon chain
+// validators including execution layer rewards, from beaconcha.in.
+type EthereumStakingScraper struct {
+	url string
+}
+
+// NewEthereumStakingScraper returns an EthereumStakingScraper using the beaconcha.in API at @url.
+func NewEthereumStakingScraper(url string) *EthereumStakingScraper {
+	return &EthereumStakingScraper{url: url}
+}
+
+// FetchYield implements StakingScraper.
+func (s *EthereumStakingScraper) FetchYield() (dia.StakingYield, error) {
+	var response struct {
+		Data struct {
+			APR float64 `json:"apr"`
+		} `json:"data"`
+	}
+	err := utils.GetJSONWithBackoff(s.url+"/api/v1/ethstore/latest", &response)
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+	return dia.StakingYield{
+		Asset:  "ETH",
+		Source: "beaconcha.in ETH.STORE",
+		APR:    response.Data.APR * 100,
+		Time:   time.Now(),
+	}, nil
+}
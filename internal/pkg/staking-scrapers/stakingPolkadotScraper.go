@@ -0,0 +1,88 @@
+package stakingscrapers
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	polkadotRPCURL = "wss://rpc.polkadot.io"
+	// Polkadot eras last 24 hours.
+	polkadotErasPerYear = 365
+)
+
+// PolkadotStakingScraper computes the staking yield of DOT from the validator rewards of the last
+// completed era relative to the total stake of the era.
+type PolkadotStakingScraper struct {
+	url string
+}
+
+// NewPolkadotStakingScraper returns a PolkadotStakingScraper using the node at @url.
+func NewPolkadotStakingScraper(url string) *PolkadotStakingScraper {
+	return &PolkadotStakingScraper{url: url}
+}
+
+// activeEraInfo is the value of Staking.ActiveEra.
+type activeEraInfo struct {
+	Index types.U32
+	Start types.OptionU64
+}
+
+// FetchYield implements StakingScraper.
+func (s *PolkadotStakingScraper) FetchYield() (dia.StakingYield, error) {
+	api, err := gsrpc.NewSubstrateAPI(s.url)
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+	meta, err := api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+
+	key, err := types.CreateStorageKey(meta, "Staking", "ActiveEra")
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+	var activeEra activeEraInfo
+	ok, err := api.RPC.State.GetStorageLatest(key, &activeEra)
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+	if !ok || activeEra.Index == 0 {
+		return dia.StakingYield{}, errors.New("no completed era")
+	}
+	era, err := types.EncodeToBytes(activeEra.Index - 1)
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+
+	var reward, stake types.U128
+	for item, v := range map[string]*types.U128{"ErasValidatorReward": &reward, "ErasTotalStake": &stake} {
+		key, err = types.CreateStorageKey(meta, "Staking", item, era)
+		if err != nil {
+			return dia.StakingYield{}, err
+		}
+		ok, err = api.RPC.State.GetStorageLatest(key, v)
+		if err != nil {
+			return dia.StakingYield{}, err
+		}
+		if !ok {
+			return dia.StakingYield{}, errors.New("no " + item + " of the last era")
+		}
+	}
+	if stake.Sign() == 0 {
+		return dia.StakingYield{}, errors.New("no stake in the last era")
+	}
+	eraRate, _ := new(big.Float).Quo(new(big.Float).SetInt(reward.Int), new(big.Float).SetInt(stake.Int)).Float64()
+	return dia.StakingYield{
+		Asset:  "DOT",
+		Source: "Polkadot era rewards",
+		APR:    eraRate * polkadotErasPerYear * 100,
+		Time:   time.Now(),
+	}, nil
+}
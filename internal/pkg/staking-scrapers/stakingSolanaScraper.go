@@ -0,0 +1,66 @@
+package stakingscrapers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const solanaRPCURL = "https://api.mainnet-beta.solana.com"
+
+// SolanaStakingScraper computes the staking yield of SOL from the validator inflation, which is
+// distributed to the staked share of the supply.
+type SolanaStakingScraper struct {
+	url string
+}
+
+// NewSolanaStakingScraper returns a SolanaStakingScraper using the RPC node at @url.
+func NewSolanaStakingScraper(url string) *SolanaStakingScraper {
+	return &SolanaStakingScraper{url: url}
+}
+
+// FetchYield implements StakingScraper.
+func (s *SolanaStakingScraper) FetchYield() (dia.StakingYield, error) {
+	var inflation struct {
+		Validator float64 `json:"validator"`
+	}
+	err := callJSONRPC(s.url, "getInflationRate", nil, &inflation)
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+	var supply struct {
+		Value struct {
+			Total float64 `json:"total"`
+		} `json:"value"`
+	}
+	err = callJSONRPC(s.url, "getSupply", []interface{}{map[string]bool{"excludeNonCirculatingAccountsList": true}}, &supply)
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+	type voteAccount struct {
+		ActivatedStake float64 `json:"activatedStake"`
+	}
+	var voteAccounts struct {
+		Current    []voteAccount `json:"current"`
+		Delinquent []voteAccount `json:"delinquent"`
+	}
+	err = callJSONRPC(s.url, "getVoteAccounts", nil, &voteAccounts)
+	if err != nil {
+		return dia.StakingYield{}, err
+	}
+
+	var staked float64
+	for _, account := range append(voteAccounts.Current, voteAccounts.Delinquent...) {
+		staked += account.ActivatedStake
+	}
+	if staked == 0 {
+		return dia.StakingYield{}, errors.New("no stake in vote accounts")
+	}
+	return dia.StakingYield{
+		Asset:  "SOL",
+		Source: "Solana inflation",
+		APR:    inflation.Validator * supply.Value.Total / staked * 100,
+		Time:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package stakingscrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// StakingScraper computes the staking yield of a proof of stake asset from the state of its
+// blockchain.
+type StakingScraper interface {
+	FetchYield() (dia.StakingYield, error)
+}
+
+// LiquidStakingScraper reads the redemption rates of liquid staking tokens from their protocols.
+type LiquidStakingScraper interface {
+	// BlockNumber returns the number of the latest block of the blockchain of the tokens.
+	BlockNumber() (uint64, error)
+	// FetchRates returns the redemption rates of the tokens at @block.
+	FetchRates(block uint64) ([]dia.LiquidStakingRate, error)
+}
+
+// New returns the staking scraper of @asset, or nil if there is none.
+func New(asset string) StakingScraper {
+	switch asset {
+	case "ETH":
+		return NewEthereumStakingScraper(beaconchainURL)
+	case "SOL":
+		return NewSolanaStakingScraper(solanaRPCURL)
+	case "ATOM":
+		return NewCosmosStakingScraper(cosmosRESTURL)
+	case "DOT":
+		return NewPolkadotStakingScraper(polkadotRPCURL)
+	default:
+		return nil
+	}
+}
+
+// NewLiquidStaking returns the scraper of the liquid staking tokens @tokens.
+func NewLiquidStaking(tokens []string) (LiquidStakingScraper, error) {
+	scraper, err := NewContractRateScraper(tokens)
+	if err != nil {
+		return nil, err
+	}
+	return scraper, nil
+}
+
+// callJSONRPC decodes the result of the JSON-RPC call of @method with @params at @url into @result.
+func callJSONRPC(url string, method string, params []interface{}, result interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	response, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned status %d", url, method, response.StatusCode)
+	}
+	var rpcResponse struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&rpcResponse)
+	if err != nil {
+		return err
+	}
+	if rpcResponse.Error != nil {
+		return fmt.Errorf("%s %s: %s", url, method, rpcResponse.Error.Message)
+	}
+	return json.Unmarshal(rpcResponse.Result, result)
+}
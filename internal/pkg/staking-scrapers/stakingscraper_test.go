@@ -0,0 +1,54 @@
+package stakingscrapers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCosmosStakingScraper(t *testing.T) {
+	responses := map[string]string{
+		"/cosmos/mint/v1beta1/inflation":       `{"inflation":"0.100000000000000000"}`,
+		"/cosmos/staking/v1beta1/pool":         `{"pool":{"not_bonded_tokens":"1000","bonded_tokens":"250000000"}}`,
+		"/cosmos/bank/v1beta1/supply/by_denom": `{"amount":{"denom":"uatom","amount":"400000000"}}`,
+		"/cosmos/distribution/v1beta1/params":  `{"params":{"community_tax":"0.100000000000000000"}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	yield, err := NewCosmosStakingScraper(server.URL).FetchYield()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := 0.1 * 0.9 * 400 / 250 * 100; math.Abs(yield.APR-expected) > 1e-9 {
+		t.Errorf("expected APR %v, got %v", expected, yield.APR)
+	}
+}
+
+func TestSolanaStakingScraper(t *testing.T) {
+	results := map[string]string{
+		"getInflationRate": `{"epoch":500,"foundation":0,"total":0.05,"validator":0.05}`,
+		"getSupply":        `{"context":{"slot":1},"value":{"total":500000000000,"circulating":1,"nonCirculating":1,"nonCirculatingAccounts":[]}}`,
+		"getVoteAccounts":  `{"current":[{"activatedStake":300000000000}],"delinquent":[{"activatedStake":100000000000}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + results[request.Method] + `}`))
+	}))
+	defer server.Close()
+
+	yield, err := NewSolanaStakingScraper(server.URL).FetchYield()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := 0.05 * 500.0 / 400 * 100; math.Abs(yield.APR-expected) > 1e-9 {
+		t.Errorf("expected APR %v, got %v", expected, yield.APR)
+	}
+}
@@ -0,0 +1,179 @@
+package supplyservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxProofKeys is the number of storage keys requested in one eth_getProof call.
+	maxProofKeys = 200
+	// maxBalanceSlot is the highest storage slot searched for the balances mapping.
+	maxBalanceSlot = 64
+)
+
+// HolderBalance is the token balance of a holder along with the proof of its storage slot.
+type HolderBalance struct {
+	Holder  common.Address
+	Balance *big.Int
+	// Proof are the rlp encoded nodes from the storage root of the token to the slot.
+	Proof []string
+}
+
+// BalanceProof holds the balances of several holders of a token at one block.
+type BalanceProof struct {
+	Token       common.Address
+	BlockNumber *big.Int
+	// StorageHash is the storage root of the token and AccountProof proves it against the
+	// state root of the block.
+	StorageHash  common.Hash
+	AccountProof []string
+	Balances     []HolderBalance
+}
+
+// Total returns the sum of all balances.
+func (p *BalanceProof) Total() *big.Int {
+	total := big.NewInt(0)
+	for _, b := range p.Balances {
+		total.Add(total, b.Balance)
+	}
+	return total
+}
+
+// accountResult is the result of eth_getProof.
+type accountResult struct {
+	AccountProof []string       `json:"accountProof"`
+	StorageHash  common.Hash    `json:"storageHash"`
+	StorageProof []storageProof `json:"storageProof"`
+}
+
+type storageProof struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
+// BalanceSlot returns the storage slot of the entry of @holder in a mapping(address => uint256)
+// declared at @mappingSlot, as laid out by Solidity.
+func BalanceSlot(holder common.Address, mappingSlot uint64) common.Hash {
+	return crypto.Keccak256Hash(
+		common.LeftPadBytes(holder.Bytes(), 32),
+		common.LeftPadBytes(new(big.Int).SetUint64(mappingSlot).Bytes(), 32),
+	)
+}
+
+// HolderEnumerator reads the balances of many holders of ERC-20 tokens from storage proofs
+// instead of calling balanceOf per holder. All balances of a token are read at the same block
+// and come with proofs, so that supplies computed from them can be verified.
+type HolderEnumerator struct {
+	rpc    *rpc.Client
+	client *ethclient.Client
+	// slots caches the storage slot of the balances mapping of each token.
+	slots map[common.Address]uint64
+	mu    sync.Mutex
+}
+
+// NewHolderEnumerator returns an enumerator using @rpcClient, which must serve eth_getProof.
+func NewHolderEnumerator(rpcClient *rpc.Client) *HolderEnumerator {
+	return &HolderEnumerator{rpc: rpcClient, client: ethclient.NewClient(rpcClient), slots: make(map[common.Address]uint64)}
+}
+
+// Client returns an ethclient on the connection of the enumerator.
+func (e *HolderEnumerator) Client() *ethclient.Client {
+	return e.client
+}
+
+// Balances returns the balances of @holders of @token at @block, which must not be nil so that
+// all balances refer to the same state. The storage slot of the balances mapping is detected
+// from the first holder with a non-zero balance.
+func (e *HolderEnumerator) Balances(ctx context.Context, token common.Address, holders []common.Address, block *big.Int) (*BalanceProof, error) {
+	slot, err := e.balanceSlot(ctx, token, holders, block)
+	if err != nil {
+		return nil, err
+	}
+	proof := &BalanceProof{Token: token, BlockNumber: block}
+	for start := 0; start < len(holders); start += maxProofKeys {
+		end := start + maxProofKeys
+		if end > len(holders) {
+			end = len(holders)
+		}
+		result, err := e.getProof(ctx, token, holders[start:end], slot, block)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.StorageProof) != end-start {
+			return nil, fmt.Errorf("eth_getProof returned %d of %d storage proofs", len(result.StorageProof), end-start)
+		}
+		proof.StorageHash = result.StorageHash
+		proof.AccountProof = result.AccountProof
+		for i, p := range result.StorageProof {
+			balance := big.NewInt(0)
+			if p.Value != nil {
+				balance = p.Value.ToInt()
+			}
+			proof.Balances = append(proof.Balances, HolderBalance{Holder: holders[start+i], Balance: balance, Proof: p.Proof})
+		}
+	}
+	return proof, nil
+}
+
+func (e *HolderEnumerator) getProof(ctx context.Context, token common.Address, holders []common.Address, slot uint64, block *big.Int) (*accountResult, error) {
+	keys := make([]string, len(holders))
+	for i, holder := range holders {
+		keys[i] = BalanceSlot(holder, slot).Hex()
+	}
+	var result accountResult
+	err := e.rpc.CallContext(ctx, &result, "eth_getProof", token, keys, hexutil.EncodeBig(block))
+	if err != nil {
+		return nil, fmt.Errorf("eth_getProof: %v", err)
+	}
+	return &result, nil
+}
+
+// balanceSlot returns the cached storage slot of the balances mapping of @token or detects it
+// by comparing the storage of @holders with their balanceOf at @block.
+func (e *HolderEnumerator) balanceSlot(ctx context.Context, token common.Address, holders []common.Address, block *big.Int) (uint64, error) {
+	e.mu.Lock()
+	slot, ok := e.slots[token]
+	e.mu.Unlock()
+	if ok {
+		return slot, nil
+	}
+	instance, err := NewERC20(token, e.client)
+	if err != nil {
+		return 0, err
+	}
+	for _, holder := range holders {
+		balance, err := instance.BalanceOf(&bind.CallOpts{Context: ctx, BlockNumber: block}, holder)
+		if err != nil {
+			return 0, err
+		}
+		if balance.Sign() == 0 {
+			continue
+		}
+		for slot := uint64(0); slot <= maxBalanceSlot; slot++ {
+			value, err := e.client.StorageAt(ctx, token, BalanceSlot(holder, slot), block)
+			if err != nil {
+				return 0, err
+			}
+			if new(big.Int).SetBytes(value).Cmp(balance) == 0 {
+				e.mu.Lock()
+				e.slots[token] = slot
+				e.mu.Unlock()
+				return slot, nil
+			}
+		}
+		return 0, fmt.Errorf("no balances mapping of %s in the first %d storage slots", token.Hex(), maxBalanceSlot+1)
+	}
+	return 0, errors.New("no holder with a balance to detect the balances mapping from")
+}
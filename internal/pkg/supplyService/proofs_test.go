@@ -0,0 +1,31 @@
+package supplyservice
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBalanceSlot(t *testing.T) {
+	holder := common.HexToAddress("0xab5801a7d398351b8be11c439e05c5b3259aec9b")
+	cases := []struct {
+		mappingSlot uint64
+		expected    string
+	}{
+		{0, "0x7d7406cd34f2d90e1db6b7f68e39fd7d898223a271e556524121153aeb94e801"},
+		{3, "0xb1bbbef45f513c44e72efa75cfd0eb28e28e8ce68fbe4008ff8e3da60fc4b618"},
+	}
+	for _, c := range cases {
+		if slot := BalanceSlot(holder, c.mappingSlot); slot.Hex() != c.expected {
+			t.Errorf("slot %d: expected %s, got %s", c.mappingSlot, c.expected, slot.Hex())
+		}
+	}
+}
+
+func TestBalanceProofTotal(t *testing.T) {
+	proof := &BalanceProof{Balances: []HolderBalance{{Balance: big.NewInt(5)}, {Balance: big.NewInt(7)}}}
+	if proof.Total().Int64() != 12 {
+		t.Errorf("expected total of 12, got %s", proof.Total())
+	}
+}
@@ -92,8 +92,10 @@ func GetWalletBalance(walletAddr string, tokenAddr string, c *ethclient.Client)
 	return
 }
 
-// GetTotalSupplyfromMainNet returns total supply minus wallets' balances from list of wallets
-func GetTotalSupplyfromMainNet(tokenAddress string, lockedWallets []string, client *ethclient.Client) (supply dia.Supply, err error) {
+// GetTotalSupplyfromMainNet returns total supply minus wallets' balances from list of wallets.
+// If @enumerator is non-nil, the balances are read from storage proofs at the same block as the
+// total supply, falling back to balanceOf calls for tokens with a non-standard storage layout.
+func GetTotalSupplyfromMainNet(tokenAddress string, lockedWallets []string, client *ethclient.Client, enumerator *HolderEnumerator) (supply dia.Supply, err error) {
 
 	instance, err := NewERC20(common.HexToAddress(tokenAddress), client)
 	if err != nil {
@@ -101,19 +103,26 @@ func GetTotalSupplyfromMainNet(tokenAddress string, lockedWallets []string, clie
 		return
 	}
 
-	totalSupply, err := instance.TotalSupply(&bind.CallOpts{})
+	// All values refer to the same block, so that the balances fit the total supply.
+	header, err := client.HeaderByNumber(context.Background(), nil)
 	if err != nil {
 		return
 	}
-	symbol, err := instance.Symbol(&bind.CallOpts{})
+	callOpts := &bind.CallOpts{BlockNumber: header.Number}
+
+	totalSupply, err := instance.TotalSupply(callOpts)
 	if err != nil {
 		return
 	}
-	name, err := instance.Name(&bind.CallOpts{})
+	symbol, err := instance.Symbol(callOpts)
 	if err != nil {
 		return
 	}
-	decimals, err := instance.Decimals(&bind.CallOpts{})
+	name, err := instance.Name(callOpts)
+	if err != nil {
+		return
+	}
+	decimals, err := instance.Decimals(callOpts)
 	if err != nil {
 		return
 	}
@@ -126,17 +135,29 @@ func GetTotalSupplyfromMainNet(tokenAddress string, lockedWallets []string, clie
 
 	// Subtract locked wallets' balances from total supply for circulating supply
 	circulatingSupply := totalSupp
-	for _, walletAddress := range lockedWallets {
-		balance, err := GetWalletBalance(walletAddress, tokenAddress, client)
+	var proof *BalanceProof
+	if enumerator != nil && len(lockedWallets) > 0 {
+		var holders []common.Address
+		for _, walletAddress := range lockedWallets {
+			holders = append(holders, common.HexToAddress(walletAddress))
+		}
+		proof, err = enumerator.Balances(context.Background(), common.HexToAddress(tokenAddress), holders, header.Number)
 		if err != nil {
-			log.Errorf("error getting wallet balance for wallet %s \n", walletAddress)
+			log.Warnf("falling back to balanceOf for locked wallets of %s: %v", tokenAddress, err)
+			proof, err = nil, nil
 		}
-		circulatingSupply = circulatingSupply - balance
 	}
-
-	header, err := client.HeaderByNumber(context.Background(), nil)
-	if err != nil {
-		log.Fatal(err)
+	if proof != nil {
+		locked, _ := new(big.Float).Quo(new(big.Float).SetInt(proof.Total()), big.NewFloat(math.Pow10(int(decimals)))).Float64()
+		circulatingSupply -= locked
+	} else {
+		for _, walletAddress := range lockedWallets {
+			balance, err := GetWalletBalance(walletAddress, tokenAddress, client)
+			if err != nil {
+				log.Errorf("error getting wallet balance for wallet %s \n", walletAddress)
+			}
+			circulatingSupply = circulatingSupply - balance
+		}
 	}
 
 	supply = dia.Supply{
@@ -9,12 +9,21 @@ import (
 
 	"github.com/cnf/structhash"
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/dedupHelper"
+	"github.com/diadata-org/diadata/pkg/metrics"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/go-redis/redis"
 	log "github.com/sirupsen/logrus"
 )
 
 type nothing struct{}
 
+var (
+	tradesProcessed = metrics.NewCounter("tradesblock", "trades", "Trades processed by the tradesblock service by result.", "exchange", "result")
+	blocksFinalised = metrics.NewCounter("tradesblock", "blocks", "Trades blocks finalised.")
+	lastBlockTime   = metrics.NewGauge("tradesblock", "last_block_timestamp_seconds", "End time of the last finalised trades block.")
+)
+
 var (
 	stablecoins = map[string]interface{}{
 		"USDC": "",
@@ -25,8 +34,19 @@ var (
 		"BUSD": "",
 	}
 	tol = float64(0.1)
+	// routeRulesRefresh is the time after which pin and forbid rules are read again from redis.
+	routeRulesRefresh = time.Minute
 )
 
+// DefaultMaxLateness covers trades of on-chain exchanges, which are stamped with the time of
+// their block and only sent once it is confirmed, i.e. 12 blocks of about 12s later by default.
+const DefaultMaxLateness = 3 * time.Minute
+
+type cachedRouteRules struct {
+	rules     dia.RouteRules
+	fetchedAt time.Time
+}
+
 type TradesBlockService struct {
 	pair            string
 	shutdown        chan nothing
@@ -38,11 +58,22 @@ type TradesBlockService struct {
 	closed          bool
 	started         bool
 	BlockDuration   int64
-	currentBlock    *dia.TradesBlock
 	datastore       models.Datastore
+	routeRules      map[string]cachedRouteRules
+	// dedup drops trades which were already stored, e.g. resent by a restarted scraper
+	dedup *dedupHelper.Deduplicator
+	// MaxLateness is the time a block stays open for late trades after its end, measured by
+	// the time of the latest trade.
+	MaxLateness time.Duration
+	// blocks are the open blocks, oldest first
+	blocks []*dia.TradesBlock
+	// finalisedUntil is the end of the latest finalised block, earlier trades are ignored
+	finalisedUntil time.Time
 }
 
-func NewTradesBlockService(datastore models.Datastore, blockDuration int64) *TradesBlockService {
+// NewTradesBlockService returns a service collecting trades in blocks of @blockDuration seconds.
+// A block is finalised once a trade more than @maxLateness after its end arrives.
+func NewTradesBlockService(datastore models.Datastore, blockDuration int64, maxLateness time.Duration) *TradesBlockService {
 	s := &TradesBlockService{
 		shutdown:        make(chan nothing),
 		shutdownDone:    make(chan nothing),
@@ -50,9 +81,11 @@ func NewTradesBlockService(datastore models.Datastore, blockDuration int64) *Tra
 		chanTradesBlock: make(chan *dia.TradesBlock),
 		error:           nil,
 		started:         false,
-		currentBlock:    nil,
 		BlockDuration:   blockDuration,
+		MaxLateness:     maxLateness,
 		datastore:       datastore,
+		routeRules:      make(map[string]cachedRouteRules),
+		dedup:           dedupHelper.NewDeduplicator("influx", datastore, dedupHelper.DefaultTTL),
 	}
 	go s.mainLoop()
 	return s
@@ -86,26 +119,98 @@ func (ps *TradesBlockService) Channel() chan *dia.TradesBlock {
 	return ps.chanTradesBlock
 }
 
-func (s *TradesBlockService) finaliseCurrentBlock() {
+func (s *TradesBlockService) finaliseBlock(block *dia.TradesBlock) {
 
-	sort.Slice(s.currentBlock.TradesBlockData.Trades, func(i, j int) bool {
-		return s.currentBlock.TradesBlockData.Trades[i].Time.Before(s.currentBlock.TradesBlockData.Trades[j].Time)
+	sort.Slice(block.TradesBlockData.Trades, func(i, j int) bool {
+		return block.TradesBlockData.Trades[i].Time.Before(block.TradesBlockData.Trades[j].Time)
 	})
 
-	hash, err := structhash.Hash(s.currentBlock.TradesBlockData, 1)
+	hash, err := structhash.Hash(block.TradesBlockData, 1)
 	if err != nil {
 		log.Printf("error on hash")
 		hash = "hashError"
 	}
-	s.currentBlock.BlockHash = hash
-	s.currentBlock.TradesBlockData.TradesNumber = len(s.currentBlock.TradesBlockData.Trades)
-	s.chanTradesBlock <- s.currentBlock
+	block.BlockHash = hash
+	block.TradesBlockData.TradesNumber = len(block.TradesBlockData.Trades)
+	s.chanTradesBlock <- block
+	s.finalisedUntil = block.TradesBlockData.EndTime
+	blocksFinalised.Inc()
+	lastBlockTime.Set(float64(block.TradesBlockData.EndTime.Unix()))
+}
+
+// finaliseBlocks finalises the open blocks which ended more than MaxLateness before @now.
+func (s *TradesBlockService) finaliseBlocks(now time.Time) {
+	for len(s.blocks) > 0 && s.blocks[0].TradesBlockData.EndTime.Add(s.MaxLateness).Before(now) {
+		s.finaliseBlock(s.blocks[0])
+		s.blocks = s.blocks[1:]
+	}
+}
+
+// blockFor returns the open block of a trade at @t, which is created if needed, or nil if the
+// block was finalised already.
+func (s *TradesBlockService) blockFor(t time.Time) *dia.TradesBlock {
+	if t.Before(s.finalisedUntil) {
+		return nil
+	}
+	beginTime := time.Unix((t.Unix()/s.BlockDuration)*s.BlockDuration, 0)
+	i := sort.Search(len(s.blocks), func(i int) bool {
+		return !s.blocks[i].TradesBlockData.BeginTime.Before(beginTime)
+	})
+	if i < len(s.blocks) && s.blocks[i].TradesBlockData.BeginTime.Equal(beginTime) {
+		return s.blocks[i]
+	}
+
+	b := &dia.TradesBlock{
+		TradesBlockData: dia.TradesBlockData{
+			Trades:    []dia.Trade{},
+			EndTime:   beginTime.Add(time.Duration(s.BlockDuration) * time.Second),
+			BeginTime: beginTime,
+		},
+	}
+	if len(s.blocks) > 0 {
+		log.Info("created new block beginTime:", b.TradesBlockData.BeginTime, " open blocks:", len(s.blocks))
+	}
+	s.blocks = append(s.blocks, nil)
+	copy(s.blocks[i+1:], s.blocks[i:])
+	s.blocks[i] = b
+	s.datastore.Flush()
+	return b
+}
+
+// routeAllowed returns false if the pool of @t is forbidden or not pinned for the pricing of its asset.
+func (s *TradesBlockService) routeAllowed(t dia.Trade) bool {
+	cached, ok := s.routeRules[t.Symbol]
+	if !ok || time.Since(cached.fetchedAt) > routeRulesRefresh {
+		rules, err := s.datastore.GetRouteRules(t.Symbol)
+		if err != nil && err != redis.Nil {
+			log.Errorf("get route rules for %s: %v", t.Symbol, err)
+			// Keep the previous rules, if any, until redis is available again.
+			rules = cached.rules
+		}
+		cached = cachedRouteRules{rules: rules, fetchedAt: time.Now()}
+		s.routeRules[t.Symbol] = cached
+	}
+	return cached.rules.Allows(t.Source, t.Pair)
 }
 
 func (s *TradesBlockService) process(t dia.Trade) {
 
 	var ignoreTrade bool
+	if !s.routeAllowed(t) {
+		log.Debugf("route %s %s not allowed for %s, ignoring %v", t.Source, t.Pair, t.Symbol, t)
+		tradesProcessed.Inc(t.Source, "forbidden")
+		return
+	}
+	if s.dedup.Duplicate(&t) {
+		log.Debugf("ignore duplicate trade %v", t)
+		tradesProcessed.Inc(t.Source, "duplicate")
+		return
+	}
 	baseToken := t.BaseToken()
+	if t.BaseAsset != nil {
+		// The asset of a verified pair is preferred to the symbol guessed from the pair.
+		baseToken = t.BaseAsset.Symbol
+	}
 	if baseToken != "USD" {
 		val, err := s.datastore.GetPriceUSD(baseToken)
 		if err != nil {
@@ -133,36 +238,17 @@ func (s *TradesBlockService) process(t dia.Trade) {
 		s.datastore.SaveTradeInflux(&t)
 	}
 
-	if s.currentBlock != nil &&
-		s.currentBlock.TradesBlockData.BeginTime.After(t.Time) {
-		log.Debugf("ignore trade should be in previous block %v", t)
-		ignoreTrade = true
-	}
-
 	if !ignoreTrade {
-
-		if s.currentBlock == nil || s.currentBlock.TradesBlockData.EndTime.Before(t.Time) {
-			if s.currentBlock != nil {
-				s.finaliseCurrentBlock()
-			}
-
-			b := &dia.TradesBlock{
-				TradesBlockData: dia.TradesBlockData{
-					Trades:    []dia.Trade{},
-					EndTime:   time.Unix((t.Time.Unix()/s.BlockDuration)*s.BlockDuration+s.BlockDuration, 0),
-					BeginTime: time.Unix((t.Time.Unix()/s.BlockDuration)*s.BlockDuration, 0),
-				},
-			}
-			if s.currentBlock != nil {
-				log.Info("created new block beginTime:", b.TradesBlockData.BeginTime, "previous block nb trades:", len(s.currentBlock.TradesBlockData.Trades))
-			}
-			s.currentBlock = b
-			s.datastore.Flush()
+		s.finaliseBlocks(t.Time)
+		if block := s.blockFor(t.Time); block != nil {
+			block.TradesBlockData.Trades = append(block.TradesBlockData.Trades, t)
+			tradesProcessed.Inc(t.Source, "accepted")
+			return
 		}
-		s.currentBlock.TradesBlockData.Trades = append(s.currentBlock.TradesBlockData.Trades, t)
-	} else {
-		log.Debugf("ignore trade  %v", t)
+		log.Debugf("ignore trade should be in previous block %v", t)
 	}
+	log.Debugf("ignore trade  %v", t)
+	tradesProcessed.Inc(t.Source, "ignored")
 }
 
 // runs in a goroutine until s is closed
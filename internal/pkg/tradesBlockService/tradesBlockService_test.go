@@ -0,0 +1,53 @@
+package tradesBlockService
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// tradeStore accepts all trades. Other methods of the datastore are not implemented.
+type tradeStore struct {
+	models.Datastore
+}
+
+func (s *tradeStore) GetRouteRules(asset string) ([]dia.RouteRule, error) { return nil, nil }
+
+func (s *tradeStore) MarkTradeSeen(key string, ttl time.Duration) (bool, error) { return true, nil }
+
+func (s *tradeStore) GetPriceUSD(symbol string) (float64, error) { return 1, nil }
+
+func (s *tradeStore) SaveTradeInflux(t *dia.Trade) error { return nil }
+
+func (s *tradeStore) Flush() error { return nil }
+
+func TestConfirmedTradeReachesItsBlock(t *testing.T) {
+	s := NewTradesBlockService(&tradeStore{}, 120, DefaultMaxLateness)
+	defer s.Close()
+	begin := time.Unix(1600000080, 0)
+
+	// The trade of a swap confirmed 12 blocks later arrives after the trades of the next block.
+	s.ProcessTrade(&dia.Trade{Symbol: "BTC", Pair: "BTC-USD", Source: dia.BinanceExchange, Time: begin.Add(130 * time.Second), ForeignTradeID: "1"})
+	s.ProcessTrade(&dia.Trade{Symbol: "ETH", Pair: "ETH-USD", Source: dia.UniswapExchange, Time: begin.Add(10 * time.Second), ForeignTradeID: "2"})
+	s.ProcessTrade(&dia.Trade{Symbol: "BTC", Pair: "BTC-USD", Source: dia.BinanceExchange, Time: begin.Add(240*time.Second + DefaultMaxLateness + time.Second), ForeignTradeID: "3"})
+
+	for i, expected := range []string{"2", "1"} {
+		block := <-s.Channel()
+		if block.TradesBlockData.TradesNumber != 1 || block.TradesBlockData.Trades[0].ForeignTradeID != expected {
+			t.Fatalf("got %d trades in block %d, expected trade %s", block.TradesBlockData.TradesNumber, i, expected)
+		}
+		if trade := block.TradesBlockData.Trades[0]; trade.Time.Before(block.TradesBlockData.BeginTime) || !trade.Time.Before(block.TradesBlockData.EndTime) {
+			t.Errorf("got trade at %v in block from %v to %v", trade.Time, block.TradesBlockData.BeginTime, block.TradesBlockData.EndTime)
+		}
+	}
+
+	// Trades later than MaxLateness are still ignored.
+	s.ProcessTrade(&dia.Trade{Symbol: "ETH", Pair: "ETH-USD", Source: dia.UniswapExchange, Time: begin.Add(20 * time.Second), ForeignTradeID: "4"})
+	s.ProcessTrade(&dia.Trade{Symbol: "BTC", Pair: "BTC-USD", Source: dia.BinanceExchange, Time: begin.Add(time.Hour), ForeignTradeID: "5"})
+	block := <-s.Channel()
+	if len(block.TradesBlockData.Trades) != 1 || block.TradesBlockData.Trades[0].ForeignTradeID != "3" {
+		t.Errorf("got %d trades, expected only trade 3", len(block.TradesBlockData.Trades))
+	}
+}
@@ -0,0 +1,156 @@
+package webhookService
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// Headers of webhook callbacks.
+const (
+	HeaderWebhookID = "X-DIA-Webhook-ID"
+	HeaderTimestamp = "X-DIA-Timestamp"
+	HeaderSignature = "X-DIA-Signature"
+)
+
+// privateNetworks are the networks webhooks must not point to, as the service runs next to the
+// databases.
+var privateNetworks = parseNetworks("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7")
+
+// Sign returns the signature of the callback @body sent at the unix time @timestamp. Receivers
+// verify it by computing the hex encoded HMAC-SHA256 of the timestamp and the body joined by a
+// dot with the secret of their webhook.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher posts events to webhooks and retries failed callbacks with exponential backoff.
+type Dispatcher struct {
+	Client   *http.Client
+	Attempts int
+	Backoff  time.Duration
+}
+
+// NewDispatcher returns a dispatcher making up to @attempts callbacks per event, waiting
+// @backoff after the first failure and twice as long after each further failure. Its client
+// refuses to connect to loopback and private addresses.
+func NewDispatcher(attempts int, backoff time.Duration) *Dispatcher {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: publicOnly}
+	return &Dispatcher{
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		Attempts: attempts,
+		Backoff:  backoff,
+	}
+}
+
+// Deliver posts @event to @webhook until a callback succeeds or all attempts failed. Callbacks
+// are retried after network errors, server errors, 408 and 429. It returns the dead letter of
+// the event if it couldn't be delivered, and nil otherwise.
+func (d *Dispatcher) Deliver(webhook models.Webhook, event Event) *models.WebhookDeadLetter {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return &models.WebhookDeadLetter{WebhookID: webhook.ID, Error: err.Error(), Time: time.Now()}
+	}
+	deadLetter := &models.WebhookDeadLetter{WebhookID: webhook.ID, Payload: string(body)}
+	backoff := d.Backoff
+	for deadLetter.Attempts < d.Attempts {
+		if deadLetter.Attempts > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		deadLetter.Attempts++
+		deadLetter.StatusCode, err = d.post(webhook, body)
+		if err == nil {
+			return nil
+		}
+		deadLetter.Error = err.Error()
+		log.Warnf("callback %d of webhook %s failed: %v", deadLetter.Attempts, webhook.ID, err)
+		if !retryable(deadLetter.StatusCode) {
+			break
+		}
+	}
+	deadLetter.Time = time.Now()
+	return deadLetter
+}
+
+// post makes a single signed callback to @webhook.
+func (d *Dispatcher) post(webhook models.Webhook, body []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderWebhookID, webhook.ID)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	response, err := d.Client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(response.Body, 1<<16))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("status %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// retryable returns true if a callback which failed with @statusCode may succeed later. A zero
+// status code is a network error.
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// publicOnly is the dialer control of the dispatcher's client, which rejects connections to
+// addresses which aren't public.
+func publicOnly(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	for _, private := range privateNetworks {
+		if private.Contains(ip) {
+			return fmt.Errorf("webhook address %s is not public", host)
+		}
+	}
+	return nil
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
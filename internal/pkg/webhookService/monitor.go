@@ -0,0 +1,128 @@
+package webhookService
+
+import (
+	"math"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// MaxWindow bounds the window of move conditions and the prices kept by a Monitor.
+const MaxWindow = 24 * time.Hour
+
+// Event is the payload posted to a webhook when its condition triggers.
+type Event struct {
+	WebhookID string
+	Symbol    string
+	Condition string
+	Threshold float64
+	Price     float64
+	// ReferencePrice is the previous price for crosses and the price the move is measured from.
+	ReferencePrice float64
+	// Change is the change from the reference price in percent.
+	Change float64
+	Time   time.Time
+}
+
+type pricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// Monitor keeps the recent prices of assets and evaluates the conditions of webhooks on them.
+type Monitor struct {
+	prices map[string][]pricePoint
+	// triggered holds the time move webhooks last triggered, which they don't trigger again
+	// within their window.
+	triggered map[string]time.Time
+}
+
+// NewMonitor returns a monitor without prices.
+func NewMonitor() *Monitor {
+	return &Monitor{
+		prices:    make(map[string][]pricePoint),
+		triggered: make(map[string]time.Time),
+	}
+}
+
+// Update adds the price of @symbol at @t and drops its prices older than MaxWindow. It returns
+// false if the price isn't newer than the latest price of @symbol, as quotations are only
+// updated every few seconds.
+func (m *Monitor) Update(symbol string, price float64, t time.Time) bool {
+	points := m.prices[symbol]
+	if price <= 0 || (len(points) > 0 && !t.After(points[len(points)-1].Time)) {
+		return false
+	}
+	points = append(points, pricePoint{Time: t, Price: price})
+	i := 0
+	for i < len(points) && points[i].Time.Before(t.Add(-MaxWindow)) {
+		i++
+	}
+	m.prices[symbol] = points[i:]
+	return true
+}
+
+// Evaluate returns the event of @webhook if its condition triggers with the latest price of its
+// symbol. Prices from before the webhook was created are ignored.
+func (m *Monitor) Evaluate(webhook models.Webhook) (Event, bool) {
+	var points []pricePoint
+	for _, point := range m.prices[webhook.Symbol] {
+		if !point.Time.Before(webhook.CreatedAt) {
+			points = append(points, point)
+		}
+	}
+	if len(points) < 2 {
+		return Event{}, false
+	}
+	current := points[len(points)-1]
+	event := Event{
+		WebhookID: webhook.ID,
+		Symbol:    webhook.Symbol,
+		Condition: webhook.Condition,
+		Threshold: webhook.Threshold,
+		Price:     current.Price,
+		Time:      current.Time,
+	}
+
+	switch webhook.Condition {
+	case models.WebhookAbove, models.WebhookBelow:
+		previous := points[len(points)-2]
+		crossed := previous.Price < webhook.Threshold && current.Price >= webhook.Threshold
+		if webhook.Condition == models.WebhookBelow {
+			crossed = previous.Price > webhook.Threshold && current.Price <= webhook.Threshold
+		}
+		if !crossed {
+			return Event{}, false
+		}
+		event.ReferencePrice = previous.Price
+	case models.WebhookMove:
+		window := time.Duration(webhook.WindowMinutes) * time.Minute
+		if current.Time.Before(m.triggered[webhook.ID].Add(window)) {
+			return Event{}, false
+		}
+		// The move is measured from the price in the window farthest from the current price.
+		for _, point := range points[:len(points)-1] {
+			if point.Time.Before(current.Time.Add(-window)) {
+				continue
+			}
+			change := 100 * (current.Price/point.Price - 1)
+			if math.Abs(change) > math.Abs(event.Change) {
+				event.Change = change
+				event.ReferencePrice = point.Price
+			}
+		}
+		if math.Abs(event.Change) < webhook.Threshold {
+			return Event{}, false
+		}
+		m.triggered[webhook.ID] = current.Time
+		return event, true
+	default:
+		log.Warnf("webhook %s has unknown condition %q", webhook.ID, webhook.Condition)
+		return Event{}, false
+	}
+	event.Change = 100 * (current.Price/event.ReferencePrice - 1)
+	return event, true
+}
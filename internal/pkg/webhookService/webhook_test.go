@@ -0,0 +1,95 @@
+package webhookService
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func TestEvaluateCross(t *testing.T) {
+	start := time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC)
+	webhook := models.Webhook{ID: "a", Symbol: "BTC", Condition: models.WebhookAbove, Threshold: 100, CreatedAt: start}
+	m := NewMonitor()
+
+	prices := []float64{95, 99, 101, 102, 98, 100}
+	triggered := []bool{false, false, true, false, false, true}
+	for i, price := range prices {
+		m.Update("BTC", price, start.Add(time.Duration(i)*time.Minute))
+		event, ok := m.Evaluate(webhook)
+		if ok != triggered[i] {
+			t.Errorf("price %v: expected triggered %v", price, triggered[i])
+		}
+		if ok && event.Price != price {
+			t.Errorf("price %v: unexpected event %+v", price, event)
+		}
+	}
+	if m.Update("BTC", 120, start) {
+		t.Errorf("expected outdated price to be ignored")
+	}
+}
+
+func TestEvaluateMove(t *testing.T) {
+	start := time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC)
+	webhook := models.Webhook{ID: "m", Symbol: "ETH", Condition: models.WebhookMove, Threshold: 5, WindowMinutes: 10, CreatedAt: start}
+	m := NewMonitor()
+
+	m.Update("ETH", 100, start)
+	m.Update("ETH", 103, start.Add(5*time.Minute))
+	if _, ok := m.Evaluate(webhook); ok {
+		t.Errorf("expected no trigger for a move of 3%%")
+	}
+	m.Update("ETH", 94, start.Add(8*time.Minute))
+	event, ok := m.Evaluate(webhook)
+	if !ok || event.ReferencePrice != 103 {
+		t.Fatalf("expected trigger from 103, got %v %+v", ok, event)
+	}
+	m.Update("ETH", 80, start.Add(12*time.Minute))
+	if _, ok := m.Evaluate(webhook); ok {
+		t.Errorf("expected no trigger within the window of the last one")
+	}
+	m.Update("ETH", 70, start.Add(19*time.Minute))
+	if _, ok := m.Evaluate(webhook); !ok {
+		t.Errorf("expected trigger after the window of the last one")
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if r.Header.Get(HeaderSignature) != Sign("secret", timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := &Dispatcher{Client: server.Client(), Attempts: 3, Backoff: time.Millisecond}
+	webhook := models.Webhook{ID: "a", URL: server.URL, Secret: "secret"}
+	if deadLetter := d.Deliver(webhook, Event{WebhookID: "a"}); deadLetter != nil {
+		t.Fatalf("expected delivery on the third attempt, got %+v", deadLetter)
+	}
+
+	calls = 0
+	webhook.Secret = "wrong"
+	deadLetter := d.Deliver(webhook, Event{WebhookID: "a"})
+	if deadLetter == nil || deadLetter.Attempts != 1 || deadLetter.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected dead letter without retries, got %+v", deadLetter)
+	}
+
+	if deadLetter := NewDispatcher(1, 0).Deliver(webhook, Event{}); deadLetter == nil {
+		t.Errorf("expected callback to loopback address to fail")
+	}
+}
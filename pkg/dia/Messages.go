@@ -21,10 +21,26 @@ const (
 	ETHEREUM                                = "Ethereum"
 	FLOW                                    = "Flow"
 	BINANCESMARTCHAIN                       = "BinanceSmartChain"
+	POLYGON                                 = "Polygon"
+	ARBITRUM                                = "Arbitrum"
+	OPTIMISM                                = "Optimism"
+	BASE                                    = "Base"
+	AVALANCHE                               = "Avalanche"
+	GNOSIS                                  = "Gnosis"
 )
 
 type VerificationMechanism string
 
+// Asset is a token identified by its contract address on a blockchain. Native tokens have the
+// zero address of their blockchain.
+type Asset struct {
+	Symbol     string
+	Name       string
+	Address    string
+	Decimals   uint8
+	Blockchain string
+}
+
 // NFTClass is the container for an nft class defined by
 // a contract (address) on a blockchain.
 type NFTClass struct {
@@ -238,6 +254,58 @@ type Pair struct {
 
 type Pairs []Pair
 
+// FeeTier is the maker and taker fee charged by an exchange from a given 30 day trading volume on.
+// Fees are given as fractions, i.e. 0.001 corresponds to 0.1%.
+type FeeTier struct {
+	Volume   float64
+	MakerFee float64
+	TakerFee float64
+}
+
+// PairMeta holds the trading conditions of a pair on an exchange such as the tick size of the
+// price, the step size of the order quantity and minimal order sizes.
+type PairMeta struct {
+	Exchange     string
+	ForeignName  string
+	TickSize     float64
+	LotSize      float64
+	MinOrderSize float64
+	MinNotional  float64
+	FeeTiers     []FeeTier
+	Time         time.Time
+}
+
+type PairMetas []PairMeta
+
+// PoolVolume is the volume traded in a liquidity pool of a DEX from StartTime to EndTime.
+type PoolVolume struct {
+	Exchange string
+	Pool     string
+	// Volumes holds the amount of each coin sold to the pool by symbol.
+	Volumes   map[string]float64
+	Trades    int
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// PoolLiquidity is a snapshot of the reserves of a two-token liquidity pool of a DEX at Block.
+type PoolLiquidity struct {
+	Exchange   string
+	Blockchain string
+	Pool       string
+	// Pair is the foreign name of the pool in the trades of the exchange, e.g. ETH-USDC.
+	Pair     string
+	Token0   Asset
+	Token1   Asset
+	Reserve0 float64
+	Reserve1 float64
+	// TVLUSD is the value of both reserves in USD. If only one of the tokens has a price, it is
+	// twice the value of its reserve.
+	TVLUSD float64
+	Block  uint64
+	Time   time.Time
+}
+
 // Trade remark: In a pair A-B, we call A the Quote token and B the Base token
 type Trade struct {
 	Symbol            string
@@ -248,8 +316,25 @@ type Trade struct {
 	ForeignTradeID    string
 	EstimatedUSDPrice float64 // will be filled by the TradeBlock Service
 	Source            string
+	// VerificationStatus records how the integrity of the trade message was checked by the
+	// scraper. Empty if the exchange provides neither signatures nor sequence numbers.
+	VerificationStatus string
+	// QuoteAsset and BaseAsset are the assets the pair of the trade is mapped to by the pair
+	// discovery or by a curator. Both are nil unless the mapping is verified.
+	QuoteAsset *Asset `json:",omitempty"`
+	BaseAsset  *Asset `json:",omitempty"`
 }
 
+// Verification statuses of trades.
+const (
+	// VerificationSigned trades were received in a message with a valid signature.
+	VerificationSigned = "signed"
+	// VerificationSequenced trades were received in order without missing messages.
+	VerificationSequenced = "sequenced"
+	// VerificationSequenceGap trades were received in order, but messages before them are missing.
+	VerificationSequenceGap = "sequence_gap"
+)
+
 type ItinToken struct {
 	Itin               string
 	Symbol             string
@@ -291,6 +376,141 @@ type OptionMetaIndex struct {
 	OptionOrderbookDatum
 }
 
+// OptionTrade is a trade of an option on a derivatives exchange. Prices of inverse options, such
+// as those of Deribit, are in BaseCurrency and converted to USD with IndexPrice.
+type OptionTrade struct {
+	InstrumentName string
+	BaseCurrency   string
+	Exchange       string
+	Price          float64
+	// Volume is the number of contracts, negative for sells.
+	Volume float64
+	// ImpliedVolatility is the annualized volatility implied by Price, e.g. 0.55 for 55%.
+	ImpliedVolatility float64
+	IndexPrice        float64
+	ForeignTradeID    string
+	Time              time.Time
+}
+
+// OptionMarketDatum is a snapshot of the market of an option at Time. Together, the snapshots of
+// all options on an asset make up its implied volatility surface. Implied volatilities are
+// annualized, e.g. 0.55 for 55%, and zero if there is no bid or ask respectively.
+type OptionMarketDatum struct {
+	InstrumentName string
+	BaseCurrency   string
+	Exchange       string
+	StrikePrice    float64
+	ExpirationTime time.Time
+	OptionType     OptionType
+	MarkPrice      float64
+	MarkIV         float64
+	BidIV          float64
+	AskIV          float64
+	// OpenInterest is the number of open contracts.
+	OpenInterest    float64
+	UnderlyingPrice float64
+	IndexPrice      float64
+	Delta           float64
+	Gamma           float64
+	Vega            float64
+	Theta           float64
+	Time            time.Time
+}
+
+// OrderBookDepthBand is the distance from the mid price, relative to it, within which orders
+// count towards the depth of an order book.
+const OrderBookDepthBand = 0.02
+
+// OrderBookDepth is the liquidity of the order book of a pair on an exchange within
+// OrderBookDepthBand of the mid price.
+type OrderBookDepth struct {
+	Symbol   string
+	Pair     string
+	Exchange string
+	MidPrice float64
+	// BidDepth and AskDepth are the amounts of the base asset offered within the band below and
+	// above the mid price.
+	BidDepth float64
+	AskDepth float64
+	Time     time.Time
+}
+
+// FundingRate is the funding rate of a perpetual futures instrument on a derivatives exchange
+// at Time.
+type FundingRate struct {
+	Symbol     string
+	Instrument string
+	Exchange   string
+	// Rate is the rate of the current funding interval, e.g. 0.0001 for 0.01%, paid by longs to
+	// shorts if positive.
+	Rate float64
+	// PredictedRate is the rate of the next funding interval as predicted by the exchange, or zero
+	// if the exchange does not predict it.
+	PredictedRate float64
+	// Interval is the time between two fundings, e.g. 8 hours on most centralized exchanges and 1
+	// hour on most DEXes.
+	Interval        time.Duration
+	NextFundingTime time.Time
+	Time            time.Time
+}
+
+// OpenInterest is the open interest of a perpetual or dated futures instrument on a derivatives
+// exchange at Time.
+type OpenInterest struct {
+	Symbol     string
+	Instrument string
+	Exchange   string
+	// OpenInterest is the amount of the underlying asset in open contracts.
+	OpenInterest float64
+	// OpenInterestUSD is the value of OpenInterest in USD at the mark price of the instrument.
+	OpenInterestUSD float64
+	Time            time.Time
+}
+
+// PerpetualTrade is a trade of a perpetual futures instrument on a derivatives exchange. Volume
+// is in the underlying asset Symbol and negative for sells.
+type PerpetualTrade struct {
+	Symbol         string
+	Instrument     string
+	Exchange       string
+	Price          float64
+	Volume         float64
+	ForeignTradeID string
+	Time           time.Time
+}
+
+// PerpetualPrice is the mark price and the index price of a perpetual futures instrument on a
+// derivatives exchange at Time. The mark price is used for margining and liquidations, the
+// index price is the exchange's spot price of the underlying asset.
+type PerpetualPrice struct {
+	Symbol     string
+	Instrument string
+	Exchange   string
+	MarkPrice  float64
+	IndexPrice float64
+	Time       time.Time
+}
+
+// StakingYield is the yield of staking a proof of stake asset, as computed from the state of its
+// blockchain by Source.
+type StakingYield struct {
+	Asset  string
+	Source string
+	// APR is the annual rate in percent before validator commissions, e.g. 3.5.
+	APR  float64
+	Time time.Time
+}
+
+// LiquidStakingRate is the amount of Underlying a liquid staking token redeems for at its protocol.
+type LiquidStakingRate struct {
+	Token      string
+	Underlying string
+	Rate       float64
+	// Block is the block the rate was read at, zero for rates recorded before blocks were.
+	Block uint64
+	Time  time.Time
+}
+
 type OptionMetaForward struct {
 	GeneralizedInstrumentName string
 	StrikePrice               float64
@@ -324,6 +544,29 @@ type DefiRate struct {
 	BorrowingRate float64
 	Asset         string
 	Protocol      string
+	// Blockchain of the lending market. It is empty for protocols deployed on Ethereum only.
+	Blockchain string `json:",omitempty"`
+	// Utilization is the share of the supplied asset which is borrowed. It is zero for markets
+	// which don't report it.
+	Utilization float64 `json:",omitempty"`
+}
+
+// LendingCollateral holds the conditions under which Asset is accepted as collateral for borrowing
+// Market, the base asset of a lending market of Protocol on Blockchain.
+type LendingCollateral struct {
+	Protocol   string
+	Blockchain string
+	Market     string
+	Asset      string
+	Address    string
+	// BorrowCollateralFactor is the share of the value of the collateral which can be borrowed.
+	BorrowCollateralFactor float64
+	// LiquidateCollateralFactor is the share of the value of the collateral above which a
+	// position can be liquidated.
+	LiquidateCollateralFactor float64
+	// SupplyCap is the largest amount of Asset which can be supplied to the market.
+	SupplyCap float64
+	Time      time.Time
 }
 
 type TradesBlockData struct {
@@ -508,6 +751,32 @@ func (e *Pairs) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// MarshalBinary -
+func (e *PairMetas) MarshalBinary() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// UnmarshalBinary -
+func (e *PairMetas) UnmarshalBinary(data []byte) error {
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalBinary -
+func (e *PoolVolume) MarshalBinary() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// UnmarshalBinary -
+func (e *PoolVolume) UnmarshalBinary(data []byte) error {
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	return nil
+}
+
 // MarshalBinary -
 func (e *ItinToken) MarshalBinary() ([]byte, error) {
 	return json.Marshal(e)
@@ -0,0 +1,68 @@
+package dia
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// RoutePin restricts the pricing of an asset to the pinned pools.
+	RoutePin = "pin"
+	// RouteForbid excludes a pool from the pricing of an asset.
+	RouteForbid = "forbid"
+)
+
+// RouteRule pins or forbids a pool, given by exchange and pair, for the pricing of Asset.
+// An empty Pair matches all pairs of the exchange.
+type RouteRule struct {
+	Asset    string
+	Exchange string
+	Pair     string
+	Action   string
+	Comment  string
+	Time     time.Time
+}
+
+// RouteRules are the pin and forbid rules of an asset.
+type RouteRules []RouteRule
+
+// Matches returns true if the rule applies to trades of @pair on @exchange.
+func (r RouteRule) Matches(exchange string, pair string) bool {
+	if r.Exchange != exchange {
+		return false
+	}
+	return r.Pair == "" || strings.EqualFold(r.Pair, pair)
+}
+
+// Allows returns false if @pair on @exchange is forbidden or if other pools are pinned.
+func (rules RouteRules) Allows(exchange string, pair string) bool {
+	pinned := false
+	for _, r := range rules {
+		switch r.Action {
+		case RouteForbid:
+			if r.Matches(exchange, pair) {
+				return false
+			}
+		case RoutePin:
+			if r.Matches(exchange, pair) {
+				return true
+			}
+			pinned = true
+		}
+	}
+	return !pinned
+}
+
+// MarshalBinary -
+func (rules *RouteRules) MarshalBinary() ([]byte, error) {
+	return json.Marshal(rules)
+}
+
+// UnmarshalBinary -
+func (rules *RouteRules) UnmarshalBinary(data []byte) error {
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+	return nil
+}
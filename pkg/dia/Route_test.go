@@ -0,0 +1,29 @@
+package dia
+
+import (
+	"testing"
+)
+
+func TestRouteRulesAllows(t *testing.T) {
+	rules := RouteRules{
+		{Asset: "XYZ", Exchange: UniswapExchange, Pair: "XYZ-WETH", Action: RouteForbid},
+	}
+	if rules.Allows(UniswapExchange, "xyz-weth") {
+		t.Errorf("forbidden pool allowed")
+	}
+	if !rules.Allows(SushiSwapExchange, "XYZ-WETH") {
+		t.Errorf("pool on other exchange not allowed")
+	}
+
+	rules = append(rules, RouteRule{Asset: "XYZ", Exchange: SushiSwapExchange, Action: RoutePin})
+	if !rules.Allows(SushiSwapExchange, "XYZ-USDC") {
+		t.Errorf("pinned exchange not allowed")
+	}
+	if rules.Allows(BalancerExchange, "XYZ-WETH") {
+		t.Errorf("pool not pinned allowed")
+	}
+
+	if !(RouteRules{}).Allows(BalancerExchange, "XYZ-WETH") {
+		t.Errorf("pool not allowed without rules")
+	}
+}
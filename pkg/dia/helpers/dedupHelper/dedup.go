@@ -0,0 +1,108 @@
+// Package dedupHelper drops trades which were already handled, e.g. because a scraper sent them
+// again after a restart or a backfill overlapped with the live scraper. Trades are identified
+// by exchange, pair and foreign trade id, or by a hash of time, price and volume if the
+// exchange gives no id.
+package dedupHelper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultTTL is the time a trade is remembered, which covers restarts and catching up after
+	// outages.
+	DefaultTTL = 2 * time.Hour
+	// localSize is the number of keys remembered in memory, which catches repeated trades
+	// without a round trip to the store and while it is unavailable.
+	localSize = 100000
+)
+
+var dedupTrades = metrics.NewCounter("dedup", "trades", "Trades checked for duplicates by stage, exchange and result.", "stage", "exchange", "result")
+
+// SeenStore remembers keys across processes, e.g. in redis.
+type SeenStore interface {
+	// MarkTradeSeen stores @key for @ttl and returns true if it wasn't stored yet.
+	MarkTradeSeen(key string, ttl time.Duration) (bool, error)
+}
+
+// Key returns the key identifying @t.
+func Key(t *dia.Trade) string {
+	if t.ForeignTradeID != "" {
+		return t.Source + ":" + t.Pair + ":" + t.ForeignTradeID
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d|%g|%g", t.Time.UnixNano(), t.Price, t.Volume)))
+	return t.Source + ":" + t.Pair + ":h" + hex.EncodeToString(hash[:12])
+}
+
+// Deduplicator drops the trades seen before in a stage, such as the trades written to kafka.
+// Stages are independent, so a trade passes each of them once.
+type Deduplicator struct {
+	stage string
+	store SeenStore
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	local map[string]struct{}
+	// order holds the keys of local as ring buffer, next is the position of the oldest one
+	order []string
+	next  int
+}
+
+// NewDeduplicator returns a Deduplicator for @stage remembering trades for @ttl in @store. A nil
+// @store remembers trades in memory only.
+func NewDeduplicator(stage string, store SeenStore, ttl time.Duration) *Deduplicator {
+	return &Deduplicator{
+		stage: stage,
+		store: store,
+		ttl:   ttl,
+		local: make(map[string]struct{}),
+		order: make([]string, 0, localSize),
+	}
+}
+
+// Duplicate returns true if @t was seen before and marks it as seen otherwise. If the store
+// fails, trades are let through unless they are remembered in memory.
+func (d *Deduplicator) Duplicate(t *dia.Trade) bool {
+	key := Key(t)
+	if d.seenLocally(key) {
+		dedupTrades.Inc(d.stage, t.Source, "duplicate")
+		return true
+	}
+	if d.store != nil {
+		first, err := d.store.MarkTradeSeen(d.stage+":"+key, d.ttl)
+		if err != nil {
+			log.Errorf("dedup %s: %v", d.stage, err)
+		} else if !first {
+			dedupTrades.Inc(d.stage, t.Source, "duplicate")
+			return true
+		}
+	}
+	dedupTrades.Inc(d.stage, t.Source, "new")
+	return false
+}
+
+// seenLocally returns true if @key is remembered in memory and adds it otherwise.
+func (d *Deduplicator) seenLocally(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.local[key]; ok {
+		return true
+	}
+	if len(d.order) < localSize {
+		d.order = append(d.order, key)
+	} else {
+		delete(d.local, d.order[d.next])
+		d.order[d.next] = key
+		d.next = (d.next + 1) % localSize
+	}
+	d.local[key] = struct{}{}
+	return false
+}
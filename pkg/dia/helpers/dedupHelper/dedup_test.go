@@ -0,0 +1,60 @@
+package dedupHelper
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+type testStore map[string]struct{}
+
+func (s testStore) MarkTradeSeen(key string, ttl time.Duration) (bool, error) {
+	if _, ok := s[key]; ok {
+		return false, nil
+	}
+	s[key] = struct{}{}
+	return true, nil
+}
+
+func TestDuplicate(t *testing.T) {
+	store := testStore{}
+	trade := dia.Trade{Source: "Kraken", Pair: "BTCUSD", ForeignTradeID: "1", Price: 30000, Volume: 1, Time: time.Unix(1700000000, 0)}
+	withoutID := dia.Trade{Source: "Kraken", Pair: "BTCUSD", Price: 30000, Volume: 1, Time: time.Unix(1700000000, 0)}
+
+	d := NewDeduplicator("kafka", store, time.Hour)
+	if d.Duplicate(&trade) || d.Duplicate(&withoutID) {
+		t.Fatal("new trades reported as duplicates")
+	}
+	if !d.Duplicate(&trade) || !d.Duplicate(&withoutID) {
+		t.Fatal("repeated trades not reported as duplicates")
+	}
+
+	// A restarted process knows the trades from the store, other stages don't.
+	if !NewDeduplicator("kafka", store, time.Hour).Duplicate(&trade) {
+		t.Error("trade of a previous process not reported as duplicate")
+	}
+	if NewDeduplicator("influx", store, time.Hour).Duplicate(&trade) {
+		t.Error("trade reported as duplicate in another stage")
+	}
+
+	other := withoutID
+	other.Volume = 2
+	if Key(&other) == Key(&withoutID) {
+		t.Error("trades with different volumes have the same key")
+	}
+}
+
+func TestLocalEviction(t *testing.T) {
+	d := NewDeduplicator("kafka", nil, time.Hour)
+	for i := 0; i <= localSize; i++ {
+		d.seenLocally(strconv.Itoa(i))
+	}
+	if len(d.local) != localSize {
+		t.Fatalf("got %d keys in memory, expected %d", len(d.local), localSize)
+	}
+	if d.seenLocally(strconv.Itoa(0)) {
+		t.Error("oldest key not evicted")
+	}
+}
@@ -0,0 +1,74 @@
+package ethhelper
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Multicall3Address is the address of the Multicall3 contract, which is deployed at the same
+// address on all major EVM blockchains.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+const multicall3ABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+var multicall3, _ = abi.JSON(strings.NewReader(multicall3ABI))
+
+// Call is a call of a contract in a multicall. CallData is the packed call, e.g. the result of
+// abi.ABI.Pack.
+type Call struct {
+	Target   common.Address
+	CallData []byte
+}
+
+// CallResult is the result of a Call. ReturnData is empty if the call reverted.
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Multicall executes @calls through Multicall3 in batches of at most @batchSize calls, each batch
+// in a single eth_call at @block, or at the latest block if @block is nil. A reverting call
+// doesn't fail its batch but has Success false in the results, which are in the order of @calls.
+func Multicall(ctx context.Context, caller ethereum.ContractCaller, calls []Call, batchSize int, block *big.Int) ([]CallResult, error) {
+	results := make([]CallResult, 0, len(calls))
+	for start := 0; start < len(calls); start += batchSize {
+		end := start + batchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		batch := make([]multicall3Call, 0, end-start)
+		for _, call := range calls[start:end] {
+			batch = append(batch, multicall3Call{Target: call.Target, AllowFailure: true, CallData: call.CallData})
+		}
+		input, err := multicall3.Pack("aggregate3", batch)
+		if err != nil {
+			return nil, err
+		}
+		output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &Multicall3Address, Data: input}, block)
+		if err != nil {
+			return nil, fmt.Errorf("multicall of calls %d to %d: %v", start, end, err)
+		}
+		var batchResults []CallResult
+		err = multicall3.UnpackIntoInterface(&batchResults, "aggregate3", output)
+		if err != nil {
+			return nil, err
+		}
+		if len(batchResults) != end-start {
+			return nil, fmt.Errorf("multicall returned %d results of %d calls", len(batchResults), end-start)
+		}
+		results = append(results, batchResults...)
+	}
+	return results, nil
+}
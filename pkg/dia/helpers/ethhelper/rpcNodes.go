@@ -0,0 +1,29 @@
+package ethhelper
+
+import (
+	"os"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// publicRPCNodes are the default RPC nodes of EVM blockchains.
+var publicRPCNodes = map[string]string{
+	dia.ETHEREUM:          "https://ethereum-rpc.publicnode.com",
+	dia.POLYGON:           "https://polygon-rpc.com",
+	dia.ARBITRUM:          "https://arb1.arbitrum.io/rpc",
+	dia.OPTIMISM:          "https://mainnet.optimism.io",
+	dia.BASE:              "https://mainnet.base.org",
+	dia.AVALANCHE:         "https://api.avax.network/ext/bc/C/rpc",
+	dia.BINANCESMARTCHAIN: "https://bsc-dataseed.bnbchain.org",
+	dia.GNOSIS:            "https://rpc.gnosischain.com",
+}
+
+// RPCNode returns the URL of the RPC node of the EVM @blockchain. It is read from
+// <BLOCKCHAIN>_RPC_NODE, e.g. ARBITRUM_RPC_NODE, and defaults to a public node.
+func RPCNode(blockchain string) string {
+	if node := os.Getenv(strings.ToUpper(blockchain) + "_RPC_NODE"); node != "" {
+		return node
+	}
+	return publicRPCNodes[blockchain]
+}
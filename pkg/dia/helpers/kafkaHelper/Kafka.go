@@ -91,6 +91,23 @@ func init() {
 	log.Printf("brokers: %v", KafkaConfig.KafkaUrl)
 }
 
+// Ping returns an error unless the leader of @topic is reachable on one of the brokers.
+func Ping(topic int) error {
+	err := errors.New("no kafka brokers")
+	for _, ip := range KafkaConfig.KafkaUrl {
+		var conn *kafka.Conn
+		conn, err = kafka.DialLeader(context.Background(), "tcp", ip, getTopic(topic), 0)
+		if err == nil {
+			_, err = conn.ReadLastOffset()
+			conn.Close()
+			if err == nil {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("topic %s: %v", getTopic(topic), err)
+}
+
 // WithRetryOnError
 func ReadOffset(topic int) (int64, error) {
 	var err error
@@ -0,0 +1,154 @@
+// Package leaderHelper elects one of several replicas of a scraper as leader through a lease in
+// redis. All replicas scrape, but only the leader publishes its trades. The leader renews the
+// lease continuously. When it dies, the lease expires and a standby takes over, so trades
+// are published by one replica at any time without a single point of failure.
+package leaderHelper
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultTTL is the duration of the lease, i.e. the longest time without a leader after the
+// leader died.
+const DefaultTTL = 15 * time.Second
+
+var log = logrus.New()
+
+var (
+	leaderGauge       = metrics.NewGauge("leader", "is_leader", "Whether this replica holds the lease (1) or is on standby (0).", "lease")
+	transitionCounter = metrics.NewCounter("leader", "transitions", "Number of times this replica became leader or standby.", "lease", "to")
+)
+
+// LeaseStore holds leases, e.g. in redis.
+type LeaseStore interface {
+	// AcquireLease takes or renews the lease @name for @holder for @ttl and returns false if
+	// another holder has it.
+	AcquireLease(name string, holder string, ttl time.Duration) (bool, error)
+	// ReleaseLease frees the lease @name if @holder has it.
+	ReleaseLease(name string, holder string) error
+}
+
+// Elector campaigns for a lease on behalf of a replica. A nil Elector is always leader, which
+// suits processes running without replicas.
+type Elector struct {
+	name  string
+	id    string
+	store LeaseStore
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu     sync.Mutex
+	leader bool
+	// renewed is the time the lease was last acquired, it expires ttl later
+	renewed time.Time
+	stop    chan struct{}
+	stopped bool
+}
+
+// NewElector returns an Elector for the lease @name with a random id for this replica.
+func NewElector(name string, store LeaseStore, ttl time.Duration) *Elector {
+	host, _ := os.Hostname()
+	leaderGauge.Set(0, name)
+	return &Elector{
+		name:  name,
+		id:    fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano()),
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+		stop:  make(chan struct{}),
+	}
+}
+
+// Run campaigns for the lease every third of its duration until Stop is called.
+func (e *Elector) Run() {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	for {
+		e.campaign()
+		select {
+		case <-ticker.C:
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// IsLeader returns true if this replica holds the lease. A leader which couldn't renew the
+// lease steps down once it may have expired, even before its next campaign.
+func (e *Elector) IsLeader() bool {
+	if e == nil {
+		return true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader && e.now().Sub(e.renewed) < e.ttl
+}
+
+// Stop ends campaigning and releases the lease, so that a standby takes over right away.
+func (e *Elector) Stop() {
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return
+	}
+	e.stopped = true
+	close(e.stop)
+	wasLeader := e.leader
+	e.setLeader(false)
+	e.mu.Unlock()
+
+	if wasLeader {
+		if err := e.store.ReleaseLease(e.name, e.id); err != nil {
+			log.Errorf("release lease %s: %v", e.name, err)
+		}
+	}
+}
+
+// campaign acquires or renews the lease. If the store fails, a leader stays leader until its
+// lease may have expired, as no other replica can acquire it meanwhile.
+func (e *Elector) campaign() {
+	start := e.now()
+	acquired, err := e.store.AcquireLease(e.name, e.id, e.ttl)
+	if err != nil {
+		log.Errorf("acquire lease %s: %v", e.name, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return
+	}
+	switch {
+	case acquired:
+		// The lease expires ttl after it was requested at the latest.
+		e.renewed = start
+		e.setLeader(true)
+	case err != nil:
+		e.setLeader(e.leader && e.now().Sub(e.renewed) < e.ttl)
+	default:
+		e.setLeader(false)
+	}
+}
+
+// setLeader records a change of the role. Must be called with e.mu held.
+func (e *Elector) setLeader(leader bool) {
+	if leader == e.leader {
+		return
+	}
+	e.leader = leader
+	if leader {
+		log.Infof("%s: %s became leader", e.name, e.id)
+		leaderGauge.Set(1, e.name)
+		transitionCounter.Inc(e.name, "leader")
+	} else {
+		log.Infof("%s: %s is on standby", e.name, e.id)
+		leaderGauge.Set(0, e.name)
+		transitionCounter.Inc(e.name, "standby")
+	}
+}
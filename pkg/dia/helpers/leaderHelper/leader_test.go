@@ -0,0 +1,90 @@
+package leaderHelper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type testLease struct {
+	holder  string
+	expires time.Time
+}
+
+type testStore struct {
+	now    func() time.Time
+	leases map[string]testLease
+	err    error
+}
+
+func (s *testStore) AcquireLease(name string, holder string, ttl time.Duration) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	lease, ok := s.leases[name]
+	if ok && lease.holder != holder && s.now().Before(lease.expires) {
+		return false, nil
+	}
+	s.leases[name] = testLease{holder: holder, expires: s.now().Add(ttl)}
+	return true, nil
+}
+
+func (s *testStore) ReleaseLease(name string, holder string) error {
+	if s.leases[name].holder == holder {
+		delete(s.leases, name)
+	}
+	return nil
+}
+
+func TestFailover(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+	store := &testStore{now: clock, leases: make(map[string]testLease)}
+	a := NewElector("collector_Test", store, 15*time.Second)
+	b := NewElector("collector_Test", store, 15*time.Second)
+	a.id, b.id = "a", "b"
+	a.now, b.now = clock, clock
+
+	a.campaign()
+	b.campaign()
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("got leaders a %v b %v, expected only a", a.IsLeader(), b.IsLeader())
+	}
+
+	// a renews its lease, b stays on standby.
+	now = now.Add(5 * time.Second)
+	a.campaign()
+	b.campaign()
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("got leaders a %v b %v after renewal, expected only a", a.IsLeader(), b.IsLeader())
+	}
+
+	// a can't reach the store and steps down once its lease may have expired, then b takes over.
+	store.err = errors.New("unavailable")
+	now = now.Add(10 * time.Second)
+	a.campaign()
+	if !a.IsLeader() {
+		t.Error("a stepped down while its lease is valid")
+	}
+	now = now.Add(5 * time.Second)
+	if a.IsLeader() {
+		t.Error("a is leader after its lease expired")
+	}
+	store.err = nil
+	b.campaign()
+	if !b.IsLeader() {
+		t.Error("b didn't take over the expired lease")
+	}
+
+	// Stopping the leader hands over the lease right away.
+	b.Stop()
+	a.campaign()
+	if b.IsLeader() || !a.IsLeader() {
+		t.Errorf("got leaders a %v b %v after b stopped, expected only a", a.IsLeader(), b.IsLeader())
+	}
+
+	var single *Elector
+	if !single.IsLeader() {
+		t.Error("nil elector isn't leader")
+	}
+}
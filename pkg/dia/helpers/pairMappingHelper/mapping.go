@@ -0,0 +1,107 @@
+// Package pairMappingHelper maps the pairs of exchanges to assets. The pair discovery classifies
+// the symbols of exchanges by the assets matching them, curators resolve the ambiguous ones, and
+// scrapers attach the assets of verified pairs to their trades through a Resolver instead of
+// matching symbols themselves.
+package pairMappingHelper
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultRefresh is the time after which a Resolver reads the mappings again.
+const DefaultRefresh = 10 * time.Minute
+
+var mappedTrades = metrics.NewCounter("pairmapping", "trades", "Trades by exchange and status of the mapping of their pair.", "exchange", "status")
+
+// MappingStore holds the mappings of pairs to assets, e.g. in postgres.
+type MappingStore interface {
+	GetExchangePairMappings(exchange string, status string) ([]models.ExchangePairMapping, error)
+}
+
+// Classify returns the status of the mapping of a symbol matching the assets in @candidates,
+// along with the asset it is mapped to if the symbol matches exactly one asset.
+func Classify(candidates []dia.Asset) (string, dia.Asset) {
+	switch len(candidates) {
+	case 0:
+		return models.MappingUnverified, dia.Asset{}
+	case 1:
+		return models.MappingVerified, candidates[0]
+	default:
+		return models.MappingAmbiguous, dia.Asset{}
+	}
+}
+
+// BaseSymbol returns the symbol of the token the prices of @pair on @exchange are given in.
+func BaseSymbol(exchange string, pair dia.Pair) string {
+	t := dia.Trade{Symbol: pair.Symbol, Pair: pair.ForeignName, Source: exchange}
+	return t.BaseToken()
+}
+
+// Resolver attaches the assets of the verified pairs of an exchange to its trades.
+type Resolver struct {
+	exchange string
+	store    MappingStore
+	refresh  time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	mappings  map[string]models.ExchangePairMapping
+	fetchedAt time.Time
+}
+
+// NewResolver returns a Resolver for the pairs of @exchange, reading the mappings from @store
+// every @refresh. With a nil @store, no pair is verified.
+func NewResolver(exchange string, store MappingStore, refresh time.Duration) *Resolver {
+	return &Resolver{
+		exchange: exchange,
+		store:    store,
+		refresh:  refresh,
+		now:      time.Now,
+		mappings: make(map[string]models.ExchangePairMapping),
+	}
+}
+
+// Resolve sets the assets of @t if its pair is verified and returns the status of the mapping.
+func (r *Resolver) Resolve(t *dia.Trade) string {
+	r.mu.Lock()
+	r.update()
+	mapping, ok := r.mappings[strings.ToUpper(t.Pair)]
+	r.mu.Unlock()
+
+	status := models.MappingUnverified
+	if ok {
+		status = mapping.Status
+	}
+	if status == models.MappingVerified {
+		quoteAsset, baseAsset := mapping.QuoteAsset, mapping.BaseAsset
+		t.QuoteAsset = &quoteAsset
+		t.BaseAsset = &baseAsset
+	}
+	mappedTrades.Inc(r.exchange, status)
+	return status
+}
+
+// update reads the mappings again if they are older than the refresh duration. If the store
+// fails, the previous mappings are kept until the next refresh. Must be called with r.mu held.
+func (r *Resolver) update() {
+	if r.store == nil || r.now().Sub(r.fetchedAt) < r.refresh {
+		return
+	}
+	r.fetchedAt = r.now()
+	mappings, err := r.store.GetExchangePairMappings(r.exchange, "")
+	if err != nil {
+		log.Errorf("get pair mappings of %s: %v", r.exchange, err)
+		return
+	}
+	r.mappings = make(map[string]models.ExchangePairMapping, len(mappings))
+	for _, mapping := range mappings {
+		r.mappings[strings.ToUpper(mapping.ForeignName)] = mapping
+	}
+}
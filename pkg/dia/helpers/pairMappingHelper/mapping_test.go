@@ -0,0 +1,75 @@
+package pairMappingHelper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+type testStore struct {
+	mappings []models.ExchangePairMapping
+	err      error
+	calls    int
+}
+
+func (s *testStore) GetExchangePairMappings(exchange string, status string) ([]models.ExchangePairMapping, error) {
+	s.calls++
+	return s.mappings, s.err
+}
+
+func TestClassify(t *testing.T) {
+	eth := dia.Asset{Symbol: "ETH", Address: "0x0000000000000000000000000000000000000000", Blockchain: "Ethereum"}
+	status, asset := Classify([]dia.Asset{eth})
+	if status != models.MappingVerified || asset != eth {
+		t.Errorf("got %s %v, expected verified %v", status, asset, eth)
+	}
+	if status, _ := Classify(nil); status != models.MappingUnverified {
+		t.Errorf("got %s, expected unverified", status)
+	}
+	if status, _ := Classify([]dia.Asset{eth, {Symbol: "ETH", Blockchain: "BinanceSmartChain"}}); status != models.MappingAmbiguous {
+		t.Errorf("got %s, expected ambiguous", status)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	btc := dia.Asset{Symbol: "BTC", Address: "0x0000000000000000000000000000000000000000", Blockchain: "Bitcoin"}
+	usdt := dia.Asset{Symbol: "USDT", Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Blockchain: "Ethereum"}
+	store := &testStore{mappings: []models.ExchangePairMapping{
+		{ForeignName: "BTCUSDT", Symbol: "BTC", BaseSymbol: "USDT", Status: models.MappingVerified, QuoteAsset: btc, BaseAsset: usdt},
+		{ForeignName: "ETHUSDT", Symbol: "ETH", BaseSymbol: "USDT", Status: models.MappingAmbiguous},
+	}}
+	now := time.Unix(1700000000, 0)
+	r := NewResolver(dia.BinanceExchange, store, time.Minute)
+	r.now = func() time.Time { return now }
+
+	trade := dia.Trade{Symbol: "BTC", Pair: "btcusdt"}
+	if status := r.Resolve(&trade); status != models.MappingVerified {
+		t.Fatalf("got %s, expected verified", status)
+	}
+	if trade.QuoteAsset == nil || *trade.QuoteAsset != btc || trade.BaseAsset == nil || *trade.BaseAsset != usdt {
+		t.Errorf("got assets %v %v, expected %v %v", trade.QuoteAsset, trade.BaseAsset, btc, usdt)
+	}
+
+	trade = dia.Trade{Symbol: "ETH", Pair: "ETHUSDT"}
+	if status := r.Resolve(&trade); status != models.MappingAmbiguous || trade.QuoteAsset != nil {
+		t.Errorf("got %s %v, expected ambiguous without assets", status, trade.QuoteAsset)
+	}
+	if status := r.Resolve(&dia.Trade{Pair: "XRPUSDT"}); status != models.MappingUnverified {
+		t.Errorf("got %s, expected unverified", status)
+	}
+
+	// Mappings are read once per refresh and kept while the store fails.
+	store.err = errors.New("unavailable")
+	now = now.Add(2 * time.Minute)
+	r.Resolve(&trade)
+	r.Resolve(&trade)
+	if store.calls != 2 {
+		t.Errorf("got %d calls, expected 2", store.calls)
+	}
+	if status := r.Resolve(&dia.Trade{Pair: "BTCUSDT"}); status != models.MappingVerified {
+		t.Errorf("got %s after store failure, expected verified", status)
+	}
+}
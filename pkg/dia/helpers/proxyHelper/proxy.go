@@ -0,0 +1,180 @@
+// Package proxyHelper sends the requests of scrapers through the HTTP or SOCKS5 proxies configured
+// for their exchange, so that scrapers can run from restricted networks and spread their
+// requests across several egress IPs. Proxies are used in turn, proxies which failed or were
+// rate limited are skipped for a while.
+package proxyHelper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/metrics"
+	ws "github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// failureCooldown is the time a failed proxy is skipped.
+const failureCooldown = time.Minute
+
+var log = logrus.New()
+
+var proxyFailures = metrics.NewCounter("scrapers", "proxy_failures", "Requests of a scraper which failed or were rate limited through a proxy.", "exchange", "proxy")
+
+type proxyKey struct{}
+
+// Rotation hands out the proxies of an exchange in turn. A nil or empty Rotation sends all
+// requests directly.
+type Rotation struct {
+	exchange string
+	proxies  []*url.URL
+	now      func() time.Time
+
+	mu   sync.Mutex
+	next int
+	// parkedUntil is the time until which each proxy is skipped
+	parkedUntil []time.Time
+}
+
+// NewRotation returns a Rotation over @proxies, which are URLs with scheme http, https or socks5.
+func NewRotation(exchange string, proxies []string) (*Rotation, error) {
+	r := &Rotation{exchange: exchange, now: time.Now}
+	for i, proxy := range proxies {
+		u, err := url.Parse(proxy)
+		// The URL isn't part of the error, as it may contain credentials.
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %d of %s", i, exchange)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("proxy %s of %s: unsupported scheme %q", u.Host, exchange, u.Scheme)
+		}
+		r.proxies = append(r.proxies, u)
+	}
+	r.parkedUntil = make([]time.Time, len(r.proxies))
+	return r, nil
+}
+
+// Len returns the number of proxies.
+func (r *Rotation) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.proxies)
+}
+
+// Next returns the next proxy which isn't parked, or the one whose parking ends first if all
+// are. It returns nil if there are no proxies.
+func (r *Rotation) Next() *url.URL {
+	if r.Len() == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	chosen := -1
+	for i := range r.proxies {
+		j := (r.next + i) % len(r.proxies)
+		if !r.parkedUntil[j].After(now) {
+			chosen = j
+			break
+		}
+		if chosen < 0 || r.parkedUntil[j].Before(r.parkedUntil[chosen]) {
+			chosen = j
+		}
+	}
+	r.next = (chosen + 1) % len(r.proxies)
+	return r.proxies[chosen]
+}
+
+// Park skips @proxy for @d, or for a minute if @d is 0, e.g. after it failed or was rate
+// limited.
+func (r *Rotation) Park(proxy *url.URL, d time.Duration) {
+	if proxy == nil || r.Len() == 0 {
+		return
+	}
+	if d <= 0 {
+		d = failureCooldown
+	}
+	proxyFailures.Inc(r.exchange, proxy.Host)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, p := range r.proxies {
+		if p == proxy {
+			if until := r.now().Add(d); until.After(r.parkedUntil[i]) {
+				r.parkedUntil[i] = until
+			}
+		}
+	}
+}
+
+// WithProxy returns a copy of @ctx. Requests with the copy are sent through @proxy by Proxy,
+// or directly if @proxy is nil.
+func WithProxy(ctx context.Context, proxy *url.URL) context.Context {
+	return context.WithValue(ctx, proxyKey{}, proxy)
+}
+
+// Proxy returns the proxy set on @req by WithProxy, or the next proxy otherwise. It is meant
+// for http.Transport.Proxy and websocket.Dialer.Proxy.
+func (r *Rotation) Proxy(req *http.Request) (*url.URL, error) {
+	if proxy, ok := req.Context().Value(proxyKey{}).(*url.URL); ok {
+		return proxy, nil
+	}
+	return r.Next(), nil
+}
+
+// Transport returns a transport with the settings of http.DefaultTransport which sends
+// requests through the proxies.
+func (r *Rotation) Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = r.Proxy
+	return transport
+}
+
+var (
+	rotations     = make(map[string]*Rotation)
+	rotationsLock sync.Mutex
+)
+
+// For returns the rotation over the proxies in the api config of @exchange. If there is no
+// config or its proxies are invalid, the rotation is empty.
+func For(exchange string) *Rotation {
+	rotationsLock.Lock()
+	defer rotationsLock.Unlock()
+	if r, ok := rotations[exchange]; ok {
+		return r
+	}
+	var proxies []string
+	if config, err := dia.GetConfig(exchange); err == nil {
+		proxies = config.Proxies
+	}
+	r, err := NewRotation(exchange, proxies)
+	if err != nil {
+		log.Errorf("%v. Sending requests directly", err)
+		r, _ = NewRotation(exchange, nil)
+	}
+	if r.Len() > 0 {
+		log.Infof("%s: sending requests through %d proxies", exchange, r.Len())
+	}
+	rotations[exchange] = r
+	return r
+}
+
+// Install sends the requests of http.DefaultTransport and websocket.DefaultDialer through the
+// proxies of @exchange, which covers scrapers using neither a request scheduler nor wsHelper.
+// It must be called before the first request and does nothing if there are no proxies.
+func Install(exchange string) {
+	r := For(exchange)
+	if r.Len() == 0 {
+		return
+	}
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.Proxy = r.Proxy
+	}
+	ws.DefaultDialer.Proxy = r.Proxy
+}
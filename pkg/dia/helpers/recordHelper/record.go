@@ -0,0 +1,254 @@
+// Package recordHelper records the raw payloads scrapers exchange with exchanges and replays them,
+// so that changes to the parsing of scrapers can be tested against real traffic without
+// network access. The collector records with -record, tests replay the recordings through the
+// same websocket connections and request schedulers the scrapers use in production.
+package recordHelper
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Kinds of recorded payloads.
+const (
+	// KindReceived is a websocket message received from the exchange.
+	KindReceived = "received"
+	// KindSent is a websocket message sent to the exchange, such as a subscription.
+	KindSent = "sent"
+	// KindResponse is the body of a response to a REST request.
+	KindResponse = "response"
+)
+
+// ErrExhausted is returned for REST requests which have no recorded response left.
+var ErrExhausted = errors.New("no recorded response left")
+
+// Entry is a recorded payload. Text holds payloads which are valid UTF-8, Binary all others,
+// e.g. compressed websocket messages.
+type Entry struct {
+	Kind string
+	// Source is the name of the websocket connection or the exchange of the request.
+	Source string
+	URL    string `json:",omitempty"`
+	Time   time.Time
+	Text   string `json:",omitempty"`
+	Binary []byte `json:",omitempty"`
+}
+
+// NewEntry returns the entry of @payload received or sent at @t.
+func NewEntry(kind string, source string, url string, payload []byte, t time.Time) Entry {
+	entry := Entry{Kind: kind, Source: source, URL: url, Time: t}
+	if utf8.Valid(payload) {
+		entry.Text = string(payload)
+	} else {
+		entry.Binary = payload
+	}
+	return entry
+}
+
+// Payload returns the recorded payload.
+func (e Entry) Payload() []byte {
+	if e.Binary != nil {
+		return e.Binary
+	}
+	return []byte(e.Text)
+}
+
+var (
+	recordDir   string
+	recordFiles map[string]*os.File
+	recordLock  sync.Mutex
+	// unsafeChars are replaced in the names of recording files
+	unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// Start records all payloads to files in @dir, one file of JSON lines per source.
+func Start(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	recordDir = dir
+	recordFiles = make(map[string]*os.File)
+	return nil
+}
+
+// Stop ends recording and closes all files.
+func Stop() error {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	var err error
+	for _, f := range recordFiles {
+		if closeErr := f.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	recordDir = ""
+	recordFiles = nil
+	return err
+}
+
+// Recording returns true between Start and Stop.
+func Recording() bool {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	return recordDir != ""
+}
+
+// Record appends @payload to the recording of @source if recording. @url is the URL of REST
+// requests and empty for websocket messages. Failures are logged to stderr and don't affect
+// the scraper.
+func Record(kind string, source string, url string, payload []byte) {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	if recordDir == "" {
+		return
+	}
+	f, ok := recordFiles[source]
+	if !ok {
+		var err error
+		path := filepath.Join(recordDir, unsafeChars.ReplaceAllString(source, "_")+".jsonl")
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "record %s: %v\n", source, err)
+			return
+		}
+		recordFiles[source] = f
+	}
+	line, err := json.Marshal(NewEntry(kind, source, url, payload, time.Now()))
+	if err != nil {
+		return
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "record %s: %v\n", source, err)
+	}
+}
+
+// Recording holds recorded entries for replay.
+type Recording struct {
+	mu      sync.Mutex
+	entries []Entry
+	// responses are the REST responses which weren't replayed yet, by URL
+	responses map[string][]Entry
+}
+
+// Load reads the recordings in @paths, which are files written by Record.
+func Load(paths ...string) (*Recording, error) {
+	r := &Recording{responses: make(map[string][]Entry)}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			var entry Entry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+			}
+			r.entries = append(r.entries, entry)
+			if entry.Kind == KindResponse {
+				r.responses[entry.URL] = append(r.responses[entry.URL], entry)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Response returns the next recorded response to @url, or ErrExhausted if there is none left.
+func (r *Recording) Response(url string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	responses := r.responses[url]
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("%s: %w", url, ErrExhausted)
+	}
+	r.responses[url] = responses[1:]
+	return responses[0].Payload(), nil
+}
+
+// Stream returns the websocket messages received by @source in their recorded order.
+func (r *Recording) Stream(source string) *Stream {
+	s := &Stream{}
+	sent := 0
+	for _, entry := range r.entries {
+		if entry.Source != source {
+			continue
+		}
+		switch entry.Kind {
+		case KindSent:
+			sent++
+		case KindReceived:
+			s.messages = append(s.messages, entry.Payload())
+			s.sentBefore = append(s.sentBefore, sent)
+		}
+	}
+	return s
+}
+
+// Stream replays the messages of a websocket connection. A message recorded after the scraper
+// sent n messages is only replayed once the scraper sent n messages again, so that e.g. trades
+// don't arrive before the scraper subscribed to their pair.
+type Stream struct {
+	messages   [][]byte
+	sentBefore []int
+	next       int
+}
+
+// Next returns the next message if the scraper sent @sent messages so far. It returns false
+// for ready if the message has to wait for more messages of the scraper, and false for ok at
+// the end of the recording.
+func (s *Stream) Next(sent int) (message []byte, ready bool, ok bool) {
+	if s.next >= len(s.messages) {
+		return nil, false, false
+	}
+	if s.sentBefore[s.next] > sent {
+		return nil, false, true
+	}
+	message = s.messages[s.next]
+	s.next++
+	return message, true, true
+}
+
+var (
+	active     *Recording
+	activeLock sync.Mutex
+)
+
+// Replay serves all websocket connections and scheduled REST requests from @r until StopReplay
+// is called.
+func Replay(r *Recording) {
+	activeLock.Lock()
+	defer activeLock.Unlock()
+	active = r
+}
+
+// StopReplay ends the replay, scrapers created afterwards use the network again.
+func StopReplay() {
+	Replay(nil)
+}
+
+// Active returns the recording being replayed, or nil.
+func Active() *Recording {
+	activeLock.Lock()
+	defer activeLock.Unlock()
+	return active
+}
@@ -0,0 +1,62 @@
+package recordHelper
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	Record(KindReceived, "Test", "", []byte("not recording"))
+	if err := Start(dir); err != nil {
+		t.Fatal(err)
+	}
+	Record(KindReceived, "Test", "", []byte(`{"event":"info"}`))
+	Record(KindSent, "Test", "", []byte(`{"subscribe":"BTCUSD"}`))
+	Record(KindReceived, "Test", "", []byte{0x1f, 0x8b, 0xff})
+	Record(KindResponse, "Test", "https://api.test/trades", []byte(`[1]`))
+	Record(KindResponse, "Test", "https://api.test/trades", []byte(`[2]`))
+	Record(KindReceived, "Other", "", []byte(`other`))
+	if err := Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	recording, err := Load(filepath.Join(dir, "Test.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := recording.Stream("Test")
+	message, ready, ok := stream.Next(0)
+	if !ok || !ready || string(message) != `{"event":"info"}` {
+		t.Fatalf("got %s %v %v, expected the info message", message, ready, ok)
+	}
+	// The binary message was received after the subscription.
+	if _, ready, ok := stream.Next(0); !ok || ready {
+		t.Fatalf("got ready %v ok %v before the subscription, expected to wait", ready, ok)
+	}
+	message, ready, _ = stream.Next(1)
+	if !ready || string(message) != string([]byte{0x1f, 0x8b, 0xff}) {
+		t.Errorf("got %v, expected the binary message", message)
+	}
+	if _, _, ok := stream.Next(1); ok {
+		t.Error("expected the end of the stream")
+	}
+
+	for _, expected := range []string{`[1]`, `[2]`} {
+		body, err := recording.Response("https://api.test/trades")
+		if err != nil || string(body) != expected {
+			t.Errorf("got %s %v, expected %s", body, err, expected)
+		}
+	}
+	if _, err := recording.Response("https://api.test/trades"); !errors.Is(err, ErrExhausted) {
+		t.Errorf("got %v, expected ErrExhausted", err)
+	}
+}
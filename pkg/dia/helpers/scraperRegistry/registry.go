@@ -0,0 +1,279 @@
+// Package scraperRegistry collects the health of scrapers. Scrapers report heartbeats, the pairs
+// they subscribed to and their trades, the registry derives trade rates, lags and states from
+// them and exports all of it as metrics labeled by exchange and as a JSON status endpoint, so
+// that scrapers which are connected but silently stopped delivering trades stand out.
+package scraperRegistry
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+)
+
+// States of a scraper.
+const (
+	// StateOK is a scraper with recent heartbeats and trades.
+	StateOK = "ok"
+	// StateStalled is a scraper which is alive but delivered no trade for the stale duration.
+	StateStalled = "stalled"
+	// StateDead is a scraper without heartbeat for the stale duration.
+	StateDead = "dead"
+)
+
+const (
+	// DefaultStaleAfter is the stale duration of scrapers which weren't registered explicitly.
+	DefaultStaleAfter = 5 * time.Minute
+	// rateWindow is the number of seconds over which trades per second are averaged.
+	rateWindow = 60
+)
+
+var (
+	heartbeatGauge = metrics.NewGauge("scrapers", "heartbeat_timestamp_seconds", "Time of the last heartbeat of a scraper.", "exchange")
+	lastTradeGauge = metrics.NewGauge("scrapers", "last_trade_timestamp_seconds", "Time at which the last trade of a scraper was received.", "exchange")
+	tradeLagGauge  = metrics.NewGauge("scrapers", "trade_lag_seconds", "Time between the execution and the receipt of the last trade of a scraper.", "exchange")
+	pairsGauge     = metrics.NewGauge("scrapers", "pairs", "Number of pairs a scraper is subscribed to.", "exchange")
+	tradesCounter  = metrics.NewCounter("scrapers", "trades", "Number of trades received from a scraper.", "exchange")
+	upGauge        = metrics.NewGauge("scrapers", "up", "Whether a scraper is alive and delivers trades (1) or is stalled or dead (0).", "exchange")
+)
+
+// Status is the health of a scraper.
+type Status struct {
+	Exchange        string    `json:"exchange"`
+	State           string    `json:"state"`
+	Started         time.Time `json:"started"`
+	LastHeartbeat   time.Time `json:"lastHeartbeat"`
+	LastTrade       time.Time `json:"lastTrade"`
+	Pairs           []string  `json:"pairs"`
+	Trades          int64     `json:"trades"`
+	TradesPerSecond float64   `json:"tradesPerSecond"`
+	// TradeLagSeconds is the time between execution and receipt of the last trade.
+	TradeLagSeconds float64 `json:"tradeLagSeconds"`
+}
+
+type scraper struct {
+	staleAfter    time.Duration
+	started       time.Time
+	lastHeartbeat time.Time
+	lastTrade     time.Time
+	tradeLag      time.Duration
+	pairs         map[string]struct{}
+	trades        int64
+	// seconds and counts are the trades per second of the last rateWindow seconds, indexed by
+	// unix time modulo rateWindow.
+	seconds [rateWindow]int64
+	counts  [rateWindow]int64
+}
+
+// Registry holds the health of all scrapers of a process.
+type Registry struct {
+	mu       sync.Mutex
+	scrapers map[string]*scraper
+	now      func() time.Time
+}
+
+// Default is the registry the package functions report to.
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{
+		scrapers: make(map[string]*scraper),
+		now:      time.Now,
+	}
+}
+
+// get returns the scraper of @exchange and registers it if it's unknown. Must be called with
+// r.mu held.
+func (r *Registry) get(exchange string) *scraper {
+	s, ok := r.scrapers[exchange]
+	if !ok {
+		s = &scraper{
+			staleAfter: DefaultStaleAfter,
+			started:    r.now(),
+			pairs:      make(map[string]struct{}),
+		}
+		r.scrapers[exchange] = s
+	}
+	return s
+}
+
+// Register adds the scraper of @exchange, which is stalled or dead after @staleAfter without
+// trades or heartbeats.
+func (r *Registry) Register(exchange string, staleAfter time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(exchange)
+	if staleAfter > 0 {
+		s.staleAfter = staleAfter
+	}
+	upGauge.Set(0, exchange)
+}
+
+// Heartbeat reports that the scraper of @exchange is alive, e.g. because it received a message.
+func (r *Registry) Heartbeat(exchange string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	r.get(exchange).lastHeartbeat = now
+	heartbeatGauge.Set(float64(now.Unix()), exchange)
+}
+
+// SubscribePair reports that the scraper of @exchange scrapes the pair @foreignName.
+func (r *Registry) SubscribePair(exchange string, foreignName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(exchange)
+	s.pairs[foreignName] = struct{}{}
+	pairsGauge.Set(float64(len(s.pairs)), exchange)
+}
+
+// UnsubscribePair reports that the scraper of @exchange stopped scraping @foreignName.
+func (r *Registry) UnsubscribePair(exchange string, foreignName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(exchange)
+	delete(s.pairs, foreignName)
+	pairsGauge.Set(float64(len(s.pairs)), exchange)
+}
+
+// Trade reports a trade of the scraper of @exchange executed at @tradeTime. A trade is a
+// heartbeat, too.
+func (r *Registry) Trade(exchange string, tradeTime time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	s := r.get(exchange)
+	s.lastHeartbeat = now
+	s.lastTrade = now
+	s.tradeLag = now.Sub(tradeTime)
+	s.trades++
+	second := now.Unix()
+	i := second % rateWindow
+	if s.seconds[i] != second {
+		s.seconds[i] = second
+		s.counts[i] = 0
+	}
+	s.counts[i]++
+
+	heartbeatGauge.Set(float64(now.Unix()), exchange)
+	lastTradeGauge.Set(float64(now.Unix()), exchange)
+	tradeLagGauge.Set(s.tradeLag.Seconds(), exchange)
+	tradesCounter.Inc(exchange)
+}
+
+// status returns the status of @s at @now. Must be called with r.mu held.
+func (s *scraper) status(exchange string, now time.Time) Status {
+	status := Status{
+		Exchange:        exchange,
+		State:           StateOK,
+		Started:         s.started,
+		LastHeartbeat:   s.lastHeartbeat,
+		LastTrade:       s.lastTrade,
+		Pairs:           make([]string, 0, len(s.pairs)),
+		Trades:          s.trades,
+		TradeLagSeconds: s.tradeLag.Seconds(),
+	}
+	for pair := range s.pairs {
+		status.Pairs = append(status.Pairs, pair)
+	}
+	sort.Strings(status.Pairs)
+
+	var trades int64
+	for i := range s.seconds {
+		if now.Unix()-s.seconds[i] < rateWindow {
+			trades += s.counts[i]
+		}
+	}
+	status.TradesPerSecond = float64(trades) / rateWindow
+
+	// Scrapers which just started have the stale duration to report.
+	lastHeartbeat, lastTrade := s.lastHeartbeat, s.lastTrade
+	if lastHeartbeat.IsZero() {
+		lastHeartbeat = s.started
+	}
+	if lastTrade.IsZero() {
+		lastTrade = s.started
+	}
+	switch {
+	case now.Sub(lastHeartbeat) > s.staleAfter:
+		status.State = StateDead
+	case now.Sub(lastTrade) > s.staleAfter:
+		status.State = StateStalled
+	}
+	return status
+}
+
+// Statuses returns the status of all scrapers sorted by exchange and updates their up metric.
+func (r *Registry) Statuses() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	statuses := make([]Status, 0, len(r.scrapers))
+	for exchange, s := range r.scrapers {
+		status := s.status(exchange, now)
+		if status.State == StateOK {
+			upGauge.Set(1, exchange)
+		} else {
+			upGauge.Set(0, exchange)
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Exchange < statuses[j].Exchange
+	})
+	return statuses
+}
+
+// Run updates the up metric of all scrapers every @interval, so that it turns to 0 when a
+// scraper stops reporting.
+func (r *Registry) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		r.Statuses()
+	}
+}
+
+// Handler serves the statuses of all scrapers as JSON, e.g. /scrapers or /scrapers?state=stalled
+// for the scrapers in one state.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		state := req.URL.Query().Get("state")
+		statuses := []Status{}
+		for _, status := range r.Statuses() {
+			if state == "" || status.State == state {
+				statuses = append(statuses, status)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(statuses)
+	})
+}
+
+// Register adds the scraper of @exchange to the Default registry.
+func Register(exchange string, staleAfter time.Duration) {
+	Default.Register(exchange, staleAfter)
+}
+
+// Heartbeat reports a heartbeat of @exchange to the Default registry.
+func Heartbeat(exchange string) {
+	Default.Heartbeat(exchange)
+}
+
+// SubscribePair reports a pair of @exchange to the Default registry.
+func SubscribePair(exchange string, foreignName string) {
+	Default.SubscribePair(exchange, foreignName)
+}
+
+// UnsubscribePair removes a pair of @exchange from the Default registry.
+func UnsubscribePair(exchange string, foreignName string) {
+	Default.UnsubscribePair(exchange, foreignName)
+}
+
+// Trade reports a trade of @exchange to the Default registry.
+func Trade(exchange string, tradeTime time.Time) {
+	Default.Trade(exchange, tradeTime)
+}
@@ -0,0 +1,51 @@
+package scraperRegistry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatuses(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+
+	r.Register("Alive", time.Minute)
+	r.Register("Stalled", time.Minute)
+	r.Register("Dead", time.Minute)
+	r.SubscribePair("Alive", "BTC-USD")
+	r.SubscribePair("Alive", "ETH-USD")
+	r.SubscribePair("Alive", "XRP-USD")
+	r.UnsubscribePair("Alive", "XRP-USD")
+
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 30; i++ {
+		r.Trade("Alive", now.Add(-2*time.Second))
+	}
+	r.Heartbeat("Stalled")
+
+	expected := map[string]string{"Alive": StateOK, "Dead": StateDead, "Stalled": StateStalled}
+	statuses := r.Statuses()
+	if len(statuses) != 3 {
+		t.Fatalf("got %d statuses, expected 3", len(statuses))
+	}
+	for _, s := range statuses {
+		if s.State != expected[s.Exchange] {
+			t.Errorf("got state %s of %s, expected %s", s.State, s.Exchange, expected[s.Exchange])
+		}
+	}
+
+	alive := statuses[0]
+	if len(alive.Pairs) != 2 || alive.Pairs[0] != "BTC-USD" || alive.Pairs[1] != "ETH-USD" {
+		t.Errorf("got pairs %v, expected [BTC-USD ETH-USD]", alive.Pairs)
+	}
+	if alive.Trades != 30 || alive.TradesPerSecond != 0.5 || alive.TradeLagSeconds != 2 {
+		t.Errorf("got %d trades at %v/s with lag %vs, expected 30 at 0.5/s with lag 2s", alive.Trades, alive.TradesPerSecond, alive.TradeLagSeconds)
+	}
+
+	// Trades leave the rate after the rate window.
+	now = now.Add(rateWindow * time.Second)
+	if rate := r.Statuses()[0].TradesPerSecond; rate != 0 {
+		t.Errorf("got %v trades/s after the window, expected 0", rate)
+	}
+}
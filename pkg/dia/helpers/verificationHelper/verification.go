@@ -0,0 +1,90 @@
+// Package verificationHelper checks the integrity of exchange messages by their signatures and
+// sequence numbers, so that tampered or replayed messages are not stored as trades.
+package verificationHelper
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+var (
+	// ErrReplay is returned for messages whose sequence number was already seen.
+	ErrReplay = errors.New("replayed message")
+	// ErrSignature is returned for messages with an invalid signature.
+	ErrSignature = errors.New("invalid signature")
+)
+
+// SequenceTracker checks that the sequence numbers of each stream, e.g. the trade ids of a
+// pair, are increasing.
+type SequenceTracker struct {
+	last map[string]int64
+	mu   sync.Mutex
+}
+
+// NewSequenceTracker returns a tracker without any seen messages.
+func NewSequenceTracker() *SequenceTracker {
+	return &SequenceTracker{last: make(map[string]int64)}
+}
+
+// Check records @seq as the latest sequence number of @stream and returns the verification
+// status of the message. Messages with a sequence number not above the latest one are replays
+// and rejected with ErrReplay. The first message of a stream is accepted as sequenced.
+func (st *SequenceTracker) Check(stream string, seq int64) (string, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	last, ok := st.last[stream]
+	if ok && seq <= last {
+		return "", fmt.Errorf("%w: sequence %d of %s, last %d", ErrReplay, seq, stream, last)
+	}
+	st.last[stream] = seq
+	if ok && seq > last+1 {
+		return dia.VerificationSequenceGap, nil
+	}
+	return dia.VerificationSequenced, nil
+}
+
+// Reset forgets the sequence of @stream, e.g. after a reconnect which restarts the numbering.
+func (st *SequenceTracker) Reset(stream string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.last, stream)
+}
+
+// Verifier checks the signature of a message.
+type Verifier interface {
+	Verify(message []byte, signature []byte) error
+}
+
+// HMACVerifier verifies HMAC-SHA256 signatures made with a shared secret.
+type HMACVerifier struct {
+	Secret []byte
+}
+
+// Verify implements Verifier.
+func (v HMACVerifier) Verify(message []byte, signature []byte) error {
+	mac := hmac.New(sha256.New, v.Secret)
+	mac.Write(message)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// Ed25519Verifier verifies signatures made with the private key of an exchange.
+type Ed25519Verifier struct {
+	PublicKey ed25519.PublicKey
+}
+
+// Verify implements Verifier.
+func (v Ed25519Verifier) Verify(message []byte, signature []byte) error {
+	if len(v.PublicKey) != ed25519.PublicKeySize || !ed25519.Verify(v.PublicKey, message, signature) {
+		return ErrSignature
+	}
+	return nil
+}
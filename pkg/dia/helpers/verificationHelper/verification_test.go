@@ -0,0 +1,71 @@
+package verificationHelper
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestSequenceTracker(t *testing.T) {
+	st := NewSequenceTracker()
+	cases := []struct {
+		stream string
+		seq    int64
+		status string
+		replay bool
+	}{
+		{"BTC-USD", 10, dia.VerificationSequenced, false},
+		{"BTC-USD", 11, dia.VerificationSequenced, false},
+		{"BTC-USD", 11, "", true},
+		{"BTC-USD", 5, "", true},
+		{"BTC-USD", 14, dia.VerificationSequenceGap, false},
+		{"ETH-USD", 3, dia.VerificationSequenced, false},
+		{"BTC-USD", 15, dia.VerificationSequenced, false},
+	}
+	for i, c := range cases {
+		status, err := st.Check(c.stream, c.seq)
+		if c.replay != errors.Is(err, ErrReplay) {
+			t.Errorf("case %d: unexpected error %v", i, err)
+		}
+		if status != c.status {
+			t.Errorf("case %d: expected status %q, got %q", i, c.status, status)
+		}
+	}
+
+	st.Reset("BTC-USD")
+	if status, err := st.Check("BTC-USD", 1); err != nil || status != dia.VerificationSequenced {
+		t.Errorf("expected sequenced after reset, got %q, %v", status, err)
+	}
+}
+
+func TestVerifiers(t *testing.T) {
+	message := []byte(`{"price":"100.5"}`)
+	tampered := []byte(`{"price":"100.6"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(message)
+	hv := HMACVerifier{Secret: []byte("secret")}
+	if err := hv.Verify(message, mac.Sum(nil)); err != nil {
+		t.Errorf("hmac: valid signature rejected: %v", err)
+	}
+	if err := hv.Verify(tampered, mac.Sum(nil)); err != ErrSignature {
+		t.Errorf("hmac: tampered message accepted")
+	}
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := Ed25519Verifier{PublicKey: public}
+	signature := ed25519.Sign(private, message)
+	if err := ev.Verify(message, signature); err != nil {
+		t.Errorf("ed25519: valid signature rejected: %v", err)
+	}
+	if err := ev.Verify(tampered, signature); err != ErrSignature {
+		t.Errorf("ed25519: tampered message accepted")
+	}
+}
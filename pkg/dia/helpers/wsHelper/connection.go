@@ -0,0 +1,393 @@
+// Package wsHelper keeps websocket connections of scrapers alive. A Connection reconnects with
+// exponential backoff when reading fails or the stream or a liquid pair stalls, subscribes again
+// to all channels after each reconnect and reports the time it was disconnected as a gap.
+package wsHelper
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia/helpers/proxyHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/recordHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/scraperRegistry"
+	"github.com/diadata-org/diadata/pkg/utils"
+	ws "github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// ErrClosed is returned by reads and writes on a closed Connection.
+var ErrClosed = errors.New("connection closed")
+
+// DefaultBackoff grows the delay between reconnects up to a minute. Half of each delay is random,
+// so that the scrapers of an exchange don't reconnect in lockstep after an outage.
+var DefaultBackoff = utils.Backoff{
+	Initial: time.Second,
+	Max:     time.Minute,
+	Factor:  2,
+}
+
+// Config describes a websocket connection. Only Name and URL or DialURL are required.
+type Config struct {
+	// Name identifies the connection in logs, usually the name of the exchange.
+	Name string
+	URL  string
+	// DialURL returns the URL for each (re)connect, e.g. with a fresh token. It has precedence
+	// over URL.
+	DialURL func() (string, error)
+	// Backoff computes the delays between failed dials. Its attempts are ignored, reconnecting
+	// continues until the connection is closed. Defaults to DefaultBackoff.
+	Backoff *utils.Backoff
+	// Subscribe is called after each (re)connect to subscribe to all channels.
+	Subscribe func(c *Connection) error
+	// Ping is sent every PingInterval, if both are set.
+	Ping         func(c *Connection) error
+	PingInterval time.Duration
+	// ReadTimeout is the time without any message after which the stream is considered stalled
+	// and the connection renewed. Zero disables the timeout.
+	ReadTimeout time.Duration
+	// StallTimeout is the time without messages for a pair, as reported by Connection.Received,
+	// after which the connection is renewed. Only pairs with regular messages are watched.
+	// Defaults to DefaultStallTimeout, a negative timeout disables the watchdog.
+	StallTimeout time.Duration
+	// OnGap is called after a reconnect with the time span in which messages may have been missed.
+	OnGap func(from time.Time, to time.Time)
+}
+
+// Connection is a websocket connection which is renewed transparently. Messages must be read
+// from a single goroutine, writes are safe from any goroutine.
+type Connection struct {
+	config  Config
+	backoff utils.Backoff
+
+	conn *ws.Conn
+	// lock serializes writes to conn and its replacement on reconnects
+	lock   sync.Mutex
+	closed bool
+	done   chan struct{}
+
+	// disconnected is the time of the last failed read, zero while connected
+	disconnected time.Time
+	// attempt counts the dials since the last message was read
+	attempt    int
+	reconnects int
+
+	// replay serves the messages of a recording instead of the network, see recordHelper.
+	// sent counts the messages written since and written signals each write to the reader.
+	replay  *recordHelper.Stream
+	sent    int
+	written chan struct{}
+	// pinging is set while Config.Ping runs. Pings aren't recorded, as they aren't sent while
+	// replaying.
+	pinging bool
+	// activity holds the messages per pair on the current connection, see Received.
+	activity map[string]*pairActivity
+}
+
+// Dial returns a Connection for @config. If the first dial fails, the connection is established
+// by the first read. While a recording is replayed, the connection doesn't dial and replays the
+// messages recorded for Config.Name instead.
+func Dial(config Config) *Connection {
+	c := &Connection{
+		config:  config,
+		backoff: DefaultBackoff,
+		done:    make(chan struct{}),
+	}
+	if config.Backoff != nil {
+		c.backoff = *config.Backoff
+	}
+	if c.config.StallTimeout == 0 {
+		c.config.StallTimeout = DefaultStallTimeout
+	}
+	if recording := recordHelper.Active(); recording != nil {
+		c.replay = recording.Stream(config.Name)
+		c.written = make(chan struct{}, 1)
+		c.subscribe()
+		return c
+	}
+	if err := c.dial(); err != nil {
+		log.Errorf("%s: dial: %v", config.Name, err)
+		c.disconnected = time.Now()
+	} else {
+		c.subscribe()
+	}
+	if config.Ping != nil && config.PingInterval > 0 {
+		go c.ping()
+	}
+	if c.config.StallTimeout > 0 {
+		go c.watch()
+	}
+	return c
+}
+
+// ReadMessage returns the next message and reports it as heartbeat of Config.Name to the
+// scraper registry. Failed reads are not returned, instead the connection is renewed until a
+// message is read or the connection is closed, in which case ErrClosed is returned.
+func (c *Connection) ReadMessage() ([]byte, error) {
+	if c.replay != nil {
+		return c.readReplay()
+	}
+	for {
+		conn, err := c.current()
+		if err != nil {
+			return nil, err
+		}
+		if conn != nil {
+			if c.config.ReadTimeout > 0 {
+				_ = conn.SetReadDeadline(time.Now().Add(c.config.ReadTimeout))
+			}
+			_, message, err := conn.ReadMessage()
+			if err == nil {
+				c.attempt = 0
+				c.reportGap()
+				scraperRegistry.Heartbeat(c.config.Name)
+				recordHelper.Record(recordHelper.KindReceived, c.config.Name, "", message)
+				return message, nil
+			}
+			if c.isClosed() {
+				return nil, ErrClosed
+			}
+			log.Warnf("%s: read: %v. Reconnecting", c.config.Name, err)
+			if c.disconnected.IsZero() {
+				c.disconnected = time.Now()
+			}
+		}
+		err = c.reconnect()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readReplay returns the next replayed message once the messages sent before it in the
+// recording were written. At the end of the recording, it returns ErrClosed.
+func (c *Connection) readReplay() ([]byte, error) {
+	for {
+		c.lock.Lock()
+		closed, sent := c.closed, c.sent
+		c.lock.Unlock()
+		if closed {
+			return nil, ErrClosed
+		}
+		message, ready, ok := c.replay.Next(sent)
+		if !ok {
+			return nil, ErrClosed
+		}
+		if ready {
+			scraperRegistry.Heartbeat(c.config.Name)
+			return message, nil
+		}
+		select {
+		case <-c.written:
+		case <-c.done:
+			return nil, ErrClosed
+		}
+	}
+}
+
+// written counts a message written while replaying. Must be called with c.lock held.
+func (c *Connection) replayWritten() {
+	c.sent++
+	select {
+	case c.written <- struct{}{}:
+	default:
+	}
+}
+
+// ReadJSON reads the next message into @v. Messages which can't be decoded are returned as
+// errors, the connection stays usable.
+func (c *Connection) ReadJSON(v interface{}) error {
+	message, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(message, v)
+}
+
+// WriteMessage sends a text message. Messages written while disconnected fail, Config.Subscribe
+// restores subscriptions after reconnecting.
+func (c *Connection) WriteMessage(message []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	if c.replay != nil {
+		c.replayWritten()
+		return nil
+	}
+	if c.conn == nil {
+		return errors.New(c.config.Name + ": not connected")
+	}
+	if !c.pinging {
+		recordHelper.Record(recordHelper.KindSent, c.config.Name, "", message)
+	}
+	return c.conn.WriteMessage(ws.TextMessage, message)
+}
+
+// WriteJSON sends @v as a JSON text message.
+func (c *Connection) WriteJSON(v interface{}) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	if c.replay != nil {
+		c.replayWritten()
+		return nil
+	}
+	if c.conn == nil {
+		return errors.New(c.config.Name + ": not connected")
+	}
+	if !c.pinging && recordHelper.Recording() {
+		if message, err := json.Marshal(v); err == nil {
+			recordHelper.Record(recordHelper.KindSent, c.config.Name, "", message)
+		}
+	}
+	return c.conn.WriteJSON(v)
+}
+
+// Reconnects returns the number of times the connection was renewed.
+func (c *Connection) Reconnects() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.reconnects
+}
+
+// Close closes the connection and ends a blocked read.
+func (c *Connection) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	c.closed = true
+	close(c.done)
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+func (c *Connection) current() (*ws.Conn, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	return c.conn, nil
+}
+
+func (c *Connection) isClosed() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.closed
+}
+
+// dial replaces the connection by a new one. It connects through the next proxy configured for
+// Config.Name, if any, and skips the proxy for a while if the dial fails.
+func (c *Connection) dial() error {
+	url := c.config.URL
+	if c.config.DialURL != nil {
+		var err error
+		url, err = c.config.DialURL()
+		if err != nil {
+			return err
+		}
+	}
+	proxies := proxyHelper.For(c.config.Name)
+	proxy := proxies.Next()
+	dialer := ws.Dialer{Proxy: http.ProxyURL(proxy)}
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		proxies.Park(proxy, 0)
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		conn.Close()
+		return ErrClosed
+	}
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = conn
+	// Pairs count as liquid again once they receive messages on the new connection.
+	c.activity = nil
+	return nil
+}
+
+// reconnect waits for the backoff delay and dials again, until it succeeds or the connection
+// is closed. Attempts add up until a message is read, so that connections which are accepted
+// and dropped right away are retried with growing delays, too.
+func (c *Connection) reconnect() error {
+	for {
+		select {
+		case <-time.After(c.backoff.Delay(c.attempt)):
+		case <-c.done:
+			return ErrClosed
+		}
+		c.attempt++
+		err := c.dial()
+		if err == nil {
+			c.lock.Lock()
+			c.reconnects++
+			c.lock.Unlock()
+			c.subscribe()
+			return nil
+		}
+		if errors.Is(err, ErrClosed) {
+			return err
+		}
+		log.Errorf("%s: dial: %v", c.config.Name, err)
+	}
+}
+
+func (c *Connection) subscribe() {
+	if c.config.Subscribe == nil {
+		return
+	}
+	if err := c.config.Subscribe(c); err != nil {
+		log.Errorf("%s: subscribe: %v", c.config.Name, err)
+	}
+}
+
+// reportGap reports the time since the connection failed once messages arrive again.
+func (c *Connection) reportGap() {
+	if c.disconnected.IsZero() {
+		return
+	}
+	from, to := c.disconnected, time.Now()
+	c.disconnected = time.Time{}
+	log.Warnf("%s: reconnected, messages from %v to %v may be missing", c.config.Name, from, to)
+	if c.config.OnGap != nil {
+		c.config.OnGap(from, to)
+	}
+}
+
+func (c *Connection) ping() {
+	ticker := time.NewTicker(c.config.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.lock.Lock()
+			c.pinging = true
+			c.lock.Unlock()
+			err := c.config.Ping(c)
+			c.lock.Lock()
+			c.pinging = false
+			c.lock.Unlock()
+			if err != nil {
+				log.Warnf("%s: ping: %v", c.config.Name, err)
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package wsHelper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+	ws "github.com/gorilla/websocket"
+)
+
+// testServer answers each subscription with a message and drops the first connection after it.
+func testServer(t *testing.T) (*httptest.Server, *int32) {
+	var connections int32
+	upgrader := ws.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		n := atomic.AddInt32(&connections, 1)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(ws.TextMessage, append(message, byte('0'+n))); err != nil {
+				return
+			}
+			if n == 1 {
+				return
+			}
+		}
+	}))
+	return server, &connections
+}
+
+func TestConnectionReconnects(t *testing.T) {
+	server, connections := testServer(t)
+	defer server.Close()
+
+	var gaps int32
+	c := Dial(Config{
+		Name:    "test",
+		URL:     "ws" + strings.TrimPrefix(server.URL, "http"),
+		Backoff: &utils.Backoff{Initial: time.Millisecond, Max: 10 * time.Millisecond, Factor: 2},
+		Subscribe: func(c *Connection) error {
+			return c.WriteMessage([]byte("subscribed"))
+		},
+		OnGap: func(from time.Time, to time.Time) {
+			atomic.AddInt32(&gaps, 1)
+		},
+	})
+	defer c.Close()
+
+	for _, expected := range []string{"subscribed1", "subscribed2"} {
+		message, err := c.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(message) != expected {
+			t.Errorf("got message %s, expected %s", message, expected)
+		}
+	}
+	if atomic.LoadInt32(connections) != 2 || c.Reconnects() != 1 {
+		t.Errorf("got %d connections and %d reconnects, expected 2 and 1", atomic.LoadInt32(connections), c.Reconnects())
+	}
+	if n := atomic.LoadInt32(&gaps); n != 1 {
+		t.Errorf("got %d gaps, expected 1", n)
+	}
+}
+
+func TestConnectionClose(t *testing.T) {
+	server, _ := testServer(t)
+	defer server.Close()
+
+	c := Dial(Config{Name: "test", URL: "ws" + strings.TrimPrefix(server.URL, "http")})
+	done := make(chan error)
+	go func() {
+		_, err := c.ReadMessage()
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != ErrClosed {
+			t.Errorf("got error %v, expected ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("read not ended by close")
+	}
+	if err := c.WriteMessage([]byte("ping")); err != ErrClosed {
+		t.Errorf("got error %v writing to closed connection, expected ErrClosed", err)
+	}
+}
+
+func TestConnectionStall(t *testing.T) {
+	// The server answers each subscription with a burst of messages and stays silent afterwards.
+	var connections int32
+	upgrader := ws.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		n := atomic.AddInt32(&connections, 1)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+			for i := 0; i < liquidMessages; i++ {
+				if err := conn.WriteMessage(ws.TextMessage, []byte{byte('0' + n)}); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	defer server.Close()
+
+	c := Dial(Config{
+		Name:         "test",
+		URL:          "ws" + strings.TrimPrefix(server.URL, "http"),
+		Backoff:      &utils.Backoff{Initial: time.Millisecond, Max: 10 * time.Millisecond, Factor: 2},
+		StallTimeout: 100 * time.Millisecond,
+		Subscribe: func(c *Connection) error {
+			return c.WriteMessage([]byte("subscribe"))
+		},
+	})
+	defer c.Close()
+
+	for i := 0; i <= liquidMessages; i++ {
+		message, err := c.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Received("BTC-USD")
+		expected := "1"
+		if i == liquidMessages {
+			expected = "2"
+		}
+		if string(message) != expected {
+			t.Fatalf("got message %s, expected %s", message, expected)
+		}
+	}
+	if c.Reconnects() != 1 {
+		t.Errorf("got %d reconnects, expected 1", c.Reconnects())
+	}
+}
@@ -0,0 +1,86 @@
+package wsHelper
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+)
+
+// DefaultStallTimeout is the time without messages for a liquid pair after which the connection
+// is renewed, if Config.StallTimeout is zero.
+const DefaultStallTimeout = 5 * time.Minute
+
+// liquidMessages is the number of messages a pair needs on the current connection to be watched
+// for stalls. Illiquid pairs may be quiet for long on a healthy connection.
+const liquidMessages = 10
+
+var stallCounter = metrics.NewCounter("scrapers", "stalls", "Pairs of a scraper which went quiet on an open websocket connection, which was renewed.", "exchange", "pair")
+
+// pairActivity tracks the messages of a pair on the current connection.
+type pairActivity struct {
+	last     time.Time
+	messages int
+}
+
+// Received reports a message for @pair, usually a trade. Exchanges sometimes keep sending
+// heartbeats on half-open connections or stop serving single subscriptions. Then the connection
+// is renewed once a pair which received messages regularly is quiet for Config.StallTimeout.
+func (c *Connection) Received(pair string) {
+	if c.config.StallTimeout < 0 || c.replay != nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.activity == nil {
+		c.activity = make(map[string]*pairActivity)
+	}
+	activity, ok := c.activity[pair]
+	if !ok {
+		activity = &pairActivity{}
+		c.activity[pair] = activity
+	}
+	activity.last = time.Now()
+	activity.messages++
+}
+
+// watch checks for stalled pairs until the connection is closed.
+func (c *Connection) watch() {
+	ticker := time.NewTicker(c.config.StallTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.checkStalls(now)
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// checkStalls closes the current connection if a liquid pair was quiet for Config.StallTimeout.
+// The failing read then renews it.
+func (c *Connection) checkStalls(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed || c.conn == nil {
+		return
+	}
+	var stalled []string
+	for pair, activity := range c.activity {
+		if activity.messages >= liquidMessages && now.Sub(activity.last) > c.config.StallTimeout {
+			stalled = append(stalled, pair)
+		}
+	}
+	if len(stalled) == 0 {
+		return
+	}
+	sort.Strings(stalled)
+	log.Warnf("%s: no messages for %v on %s. Reconnecting", c.config.Name, c.config.StallTimeout, strings.Join(stalled, ", "))
+	for _, pair := range stalled {
+		stallCounter.Inc(c.config.Name, pair)
+	}
+	c.activity = nil
+	c.conn.Close()
+}
@@ -0,0 +1,229 @@
+// Package grpcApi serves quotations, trades, assets and supplies over gRPC, with the messages
+// and the service defined in api/proto/dia.proto.
+package grpcApi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	"github.com/diadata-org/diadata/pkg/http/grpcApi/diapb"
+	"github.com/diadata-org/diadata/pkg/http/restServer/streamApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/go-redis/redis"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	maxQuotations     = 100
+	defaultTradeLimit = 10
+	maxTradeLimit     = 1000
+)
+
+// Server implements diapb.DiaServiceServer.
+type Server struct {
+	dataStore models.Datastore
+	// hub feeds the server streams. Streams are unavailable if it is nil.
+	hub *streamApi.Hub
+}
+
+// NewServer returns a gRPC server with the DIA service over @dataStore, streaming the
+// messages of @hub.
+func NewServer(dataStore models.Datastore, hub *streamApi.Hub) *grpc.Server {
+	s := grpc.NewServer()
+	diapb.RegisterDiaServiceServer(s, &Server{dataStore: dataStore, hub: hub})
+	return s
+}
+
+func (s *Server) GetQuotation(ctx context.Context, req *diapb.GetQuotationRequest) (*diapb.Quotation, error) {
+	q, err := s.dataStore.GetQuotation(strings.ToUpper(req.Symbol))
+	if err == redis.Nil {
+		return nil, status.Errorf(codes.NotFound, "no quotation of %s", req.Symbol)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return quotationMessage(q), nil
+}
+
+func (s *Server) GetQuotations(ctx context.Context, req *diapb.GetQuotationsRequest) (*diapb.GetQuotationsResponse, error) {
+	if len(req.Symbols) == 0 || len(req.Symbols) > maxQuotations {
+		return nil, status.Errorf(codes.InvalidArgument, "between 1 and %d symbols required", maxQuotations)
+	}
+	response := &diapb.GetQuotationsResponse{}
+	for _, symbol := range req.Symbols {
+		q, err := s.dataStore.GetQuotation(strings.ToUpper(symbol))
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.Quotations = append(response.Quotations, quotationMessage(q))
+	}
+	return response, nil
+}
+
+func (s *Server) GetAsset(ctx context.Context, req *diapb.GetAssetRequest) (*diapb.Asset, error) {
+	symbol := strings.ToUpper(req.Symbol)
+	exchanges, err := s.dataStore.GetExchangesForSymbol(symbol)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &diapb.Asset{Symbol: symbol, Name: helpers.NameForSymbol(symbol), Exchanges: exchanges}, nil
+}
+
+func (s *Server) GetSupply(ctx context.Context, req *diapb.GetSupplyRequest) (*diapb.Supply, error) {
+	if !utils.QuerySafe(req.Symbol) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid symbol %q", req.Symbol)
+	}
+	supply, err := s.dataStore.GetLatestSupply(strings.ToUpper(req.Symbol))
+	if err == redis.Nil {
+		return nil, status.Errorf(codes.NotFound, "no supply of %s", req.Symbol)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &diapb.Supply{
+		Symbol:            supply.Symbol,
+		Name:              supply.Name,
+		Supply:            supply.Supply,
+		CirculatingSupply: supply.CirculatingSupply,
+		Source:            supply.Source,
+		Time:              timestamppb.New(supply.Time),
+	}, nil
+}
+
+func (s *Server) GetLastTrades(ctx context.Context, req *diapb.GetLastTradesRequest) (*diapb.GetLastTradesResponse, error) {
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultTradeLimit
+	}
+	if limit < 0 || limit > maxTradeLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxTradeLimit)
+	}
+	if !utils.QuerySafe(req.Symbol, req.Exchange) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid symbol %q or exchange %q", req.Symbol, req.Exchange)
+	}
+	symbol := strings.ToUpper(req.Symbol)
+	var trades []dia.Trade
+	var err error
+	if req.Exchange != "" {
+		trades, err = s.dataStore.GetLastTrades(symbol, req.Exchange, limit)
+	} else {
+		trades, err = s.dataStore.GetLastTradesAllExchanges(symbol, limit)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	response := &diapb.GetLastTradesResponse{Trades: make([]*diapb.Trade, len(trades))}
+	for i := range trades {
+		response.Trades[i] = tradeMessage(&trades[i])
+	}
+	return response, nil
+}
+
+func (s *Server) StreamTrades(req *diapb.StreamTradesRequest, stream diapb.DiaService_StreamTradesServer) error {
+	var subscriptions []streamApi.Subscription
+	for _, symbol := range orAll(req.Symbols) {
+		for _, exchange := range orAll(req.Exchanges) {
+			subscriptions = append(subscriptions, streamApi.Subscription{Channel: streamApi.ChannelTrades, Symbol: symbol, Exchange: exchange})
+		}
+	}
+	return s.stream(stream.Context(), subscriptions, func(data interface{}) error {
+		trade, ok := data.(dia.Trade)
+		if !ok {
+			return nil
+		}
+		return stream.Send(tradeMessage(&trade))
+	})
+}
+
+func (s *Server) StreamQuotations(req *diapb.StreamQuotationsRequest, stream diapb.DiaService_StreamQuotationsServer) error {
+	var subscriptions []streamApi.Subscription
+	for _, symbol := range orAll(req.Symbols) {
+		for _, filter := range orAll(req.Filters) {
+			subscriptions = append(subscriptions, streamApi.Subscription{Channel: streamApi.ChannelQuotations, Symbol: symbol, Filter: filter})
+		}
+	}
+	return s.stream(stream.Context(), subscriptions, func(data interface{}) error {
+		point, ok := data.(dia.FilterPoint)
+		if !ok {
+			return nil
+		}
+		return stream.Send(&diapb.FilterPoint{Symbol: point.Symbol, Filter: point.Name, Value: point.Value, Time: timestamppb.New(point.Time)})
+	})
+}
+
+// stream sends the data of the messages of @subscriptions through @send until @ctx ends or
+// sending fails.
+func (s *Server) stream(ctx context.Context, subscriptions []streamApi.Subscription, send func(data interface{}) error) error {
+	if s.hub == nil {
+		return status.Error(codes.Unavailable, "streaming is disabled")
+	}
+	subscriber, err := s.hub.Subscribe(subscriptions...)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer subscriber.Close()
+	for {
+		m, err := subscriber.Next(ctx)
+		if err == streamApi.ErrDropped {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if err != nil {
+			// The client cancelled the stream.
+			return status.Error(codes.Canceled, err.Error())
+		}
+		err = send(m.Data)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// orAll returns @values, or a single empty value matching all values if it is empty.
+func orAll(values []string) []string {
+	if len(values) == 0 {
+		return []string{""}
+	}
+	return values
+}
+
+func quotationMessage(q *models.Quotation) *diapb.Quotation {
+	m := &diapb.Quotation{
+		Symbol: q.Symbol,
+		Name:   q.Name,
+		Price:  q.Price,
+		Source: q.Source,
+		Time:   timestamppb.New(q.Time),
+		Itin:   q.ITIN,
+	}
+	if q.PriceYesterday != nil {
+		m.PriceYesterday = wrapperspb.Double(*q.PriceYesterday)
+	}
+	if q.VolumeYesterdayUSD != nil {
+		m.VolumeYesterdayUsd = wrapperspb.Double(*q.VolumeYesterdayUSD)
+	}
+	return m
+}
+
+func tradeMessage(t *dia.Trade) *diapb.Trade {
+	return &diapb.Trade{
+		Symbol:             t.Symbol,
+		Pair:               t.Pair,
+		Price:              t.Price,
+		Volume:             t.Volume,
+		Time:               timestamppb.New(t.Time),
+		ForeignTradeId:     t.ForeignTradeID,
+		EstimatedUsdPrice:  t.EstimatedUSDPrice,
+		Exchange:           t.Source,
+		VerificationStatus: t.VerificationStatus,
+	}
+}
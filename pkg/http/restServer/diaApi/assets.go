@@ -0,0 +1,78 @@
+package diaApi
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+)
+
+// GetAssets godoc
+// @Summary Get the asset catalogue
+// @Description GetAssets lists all assets known to DIA with their blockchain, contract address,
+// @Description decimals and the exchanges they are traded on, ordered by blockchain and address.
+// @Description The next page is requested by passing NextCursor of the response, which is also
+// @Description set in the X-Next-Cursor header, as cursor.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   limit      query   int        false       "Assets per page, default 100, at most 1000"
+// @Param   cursor     query   string     false       "NextCursor of the previous page"
+// @Success 200 {object} models.AssetsPage "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/assets [get]
+func (env *Env) GetAssets(c *gin.Context) {
+	limit, err := intQuery(c, "limit", defaultPageLimit, 1, maxPageLimit)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	var after models.AssetKey
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err = decodeAssetCursor(cursor)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// One asset more than requested tells whether there is a next page.
+	assets, err := env.RelDB.GetAssets(after, limit+1)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	page := models.AssetsPage{Assets: assets}
+	if len(assets) > limit {
+		page.Assets = assets[:limit]
+		last := assets[limit-1]
+		page.NextCursor = encodeAssetCursor(models.AssetKey{Blockchain: last.Blockchain, Address: last.Address})
+		setNextCursor(c, page.NextCursor)
+	}
+	if page.Assets == nil {
+		page.Assets = []models.AssetInfo{}
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// encodeAssetCursor returns an opaque cursor of the page following @key.
+func encodeAssetCursor(key models.AssetKey) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key.Blockchain + "\n" + key.Address))
+}
+
+func decodeAssetCursor(cursor string) (models.AssetKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return models.AssetKey{}, errors.New("invalid cursor")
+	}
+	fields := strings.SplitN(string(data), "\n", 2)
+	if len(fields) != 2 {
+		return models.AssetKey{}, errors.New("invalid cursor")
+	}
+	return models.AssetKey{Blockchain: fields[0], Address: fields[1]}, nil
+}
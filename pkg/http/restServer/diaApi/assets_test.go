@@ -0,0 +1,23 @@
+package diaApi
+
+import (
+	"testing"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func TestAssetCursor(t *testing.T) {
+	key := models.AssetKey{Blockchain: "Ethereum", Address: "0xdac17f958d2ee523a2206206994597c13d831ec7"}
+	decoded, err := decodeAssetCursor(encodeAssetCursor(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != key {
+		t.Errorf("expected %v, got %v", key, decoded)
+	}
+	for _, cursor := range []string{"not base64!", "bm8gc2VwYXJhdG9y"} {
+		if _, err := decodeAssetCursor(cursor); err == nil {
+			t.Errorf("expected error for cursor %s", cursor)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package diaApi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// maxBasketConstituents bounds the number of assets in a basket, whose prices are all
+	// queried for every value.
+	maxBasketConstituents = 50
+	defaultBasketValue    = 100
+	defaultBasketWindow   = "30d"
+	defaultBasketInterval = "1d"
+)
+
+// PostBasket godoc
+// @Summary Create a custom basket
+// @Description PostBasket stores a weighted basket of assets under a new ID. Weights are
+// @Description normalized to sum to one. The basket starts at BaseValue, default 100, at the
+// @Description current prices of its constituents.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} models.Basket "success"
+// @Failure 400 {object} restApi.APIError "Invalid basket"
+// @Failure 404 {object} restApi.APIError "No quotation of a constituent"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/basket [post]
+func (env *Env) PostBasket(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var basket models.Basket
+	err = json.Unmarshal(body, &basket)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	err = normalizeBasket(&basket)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	prices, ok := env.basketPrices(c, &basket)
+	if !ok {
+		return
+	}
+	basket.BaseTime = time.Now()
+	for i := range basket.Constituents {
+		basket.Constituents[i].BasePrice = prices[basket.Constituents[i].Symbol]
+	}
+	basket.Creator = currentAdminUser(c).Username
+
+	basket.ID, err = env.RelDB.SetBasket(basket)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, basket)
+}
+
+// GetBasket godoc
+// @Summary Get the index value of a custom basket
+// @Description GetBasket returns the current index value of a basket along with the prices of its
+// @Description constituents.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   id     path    string     true        "Basket ID"
+// @Success 200 {object} models.BasketValue "success"
+// @Failure 404 {object} restApi.APIError "Unknown basket or no quotation of a constituent"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/basket/:id [get]
+func (env *Env) GetBasket(c *gin.Context) {
+	basket, ok := env.basket(c)
+	if !ok {
+		return
+	}
+	prices, ok := env.basketPrices(c, &basket)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.BasketValue{
+		ID:     basket.ID,
+		Name:   basket.Name,
+		Value:  basket.Value(prices),
+		Time:   time.Now(),
+		Prices: prices,
+	})
+}
+
+// GetBasketHistory godoc
+// @Summary Get the historical index values of a custom basket
+// @Description GetBasketHistory returns the index value of a basket at each interval of a window
+// @Description until endtime. Intervals in which a constituent has no price are left out.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   id         path    string     true        "Basket ID"
+// @Param   window     query   string     false       "Window, e.g. 7d, 30d or 12w, default 30d, at most 365d"
+// @Param   interval   query   string     false       "5m 30m 1h 4h 1d 1w, default 1d"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {object} models.BasketHistory "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "Unknown basket"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/basket/:id/history [get]
+func (env *Env) GetBasketHistory(c *gin.Context) {
+	resolution, starttime, endtime, ok := windowQuery(c, defaultBasketWindow, defaultBasketInterval)
+	if !ok {
+		return
+	}
+	basket, ok := env.basket(c)
+	if !ok {
+		return
+	}
+
+	interval := models.QuotationResolutionDurations[resolution]
+	prices := make(map[time.Time]map[string]float64)
+	for _, constituent := range basket.Constituents {
+		points, err := env.DataStore.GetQuotationRange(constituent.Symbol, resolution, starttime, endtime, int(endtime.Sub(starttime)/interval)+1)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		for _, point := range points {
+			if point.Price <= 0 {
+				continue
+			}
+			if prices[point.Time] == nil {
+				prices[point.Time] = make(map[string]float64)
+			}
+			prices[point.Time][constituent.Symbol] = point.Price
+		}
+	}
+
+	history := models.BasketHistory{
+		ID:        basket.ID,
+		Name:      basket.Name,
+		Interval:  resolution,
+		StartTime: starttime,
+		EndTime:   endtime,
+		Values:    []models.BasketValue{},
+	}
+	for t, p := range prices {
+		if len(p) < len(basket.Constituents) {
+			continue
+		}
+		history.Values = append(history.Values, models.BasketValue{Value: basket.Value(p), Time: t})
+	}
+	sort.Slice(history.Values, func(i, j int) bool { return history.Values[i].Time.Before(history.Values[j].Time) })
+	c.JSON(http.StatusOK, history)
+}
+
+// basket returns the basket in the id path parameter, and sends an error response if it can't.
+func (env *Env) basket(c *gin.Context) (basket models.Basket, ok bool) {
+	basket, err := env.RelDB.GetBasket(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("unknown basket %q", c.Param("id")))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return basket, false
+	}
+	return basket, true
+}
+
+// basketPrices returns the current prices of the constituents of @basket by symbol, and sends an
+// error response if a constituent has no price.
+func (env *Env) basketPrices(c *gin.Context, basket *models.Basket) (map[string]float64, bool) {
+	prices := make(map[string]float64, len(basket.Constituents))
+	for _, constituent := range basket.Constituents {
+		q, err := env.DataStore.GetQuotation(constituent.Symbol)
+		if err != nil {
+			if err == redis.Nil {
+				restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no quotation of %s", constituent.Symbol))
+			} else {
+				restApi.SendError(c, http.StatusInternalServerError, err)
+			}
+			return nil, false
+		}
+		if q.Price <= 0 {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no price of %s", constituent.Symbol))
+			return nil, false
+		}
+		prices[constituent.Symbol] = q.Price
+	}
+	return prices, true
+}
+
+// normalizeBasket validates the name and constituents of a new basket, upper cases their symbols,
+// scales their weights to sum to one and sets the default base value.
+func normalizeBasket(basket *models.Basket) error {
+	if basket.Name == "" {
+		return errors.New("missing Name")
+	}
+	if len(basket.Constituents) == 0 || len(basket.Constituents) > maxBasketConstituents {
+		return fmt.Errorf("a basket must have between 1 and %d constituents", maxBasketConstituents)
+	}
+	if basket.BaseValue < 0 {
+		return errors.New("negative BaseValue")
+	}
+	if basket.BaseValue == 0 {
+		basket.BaseValue = defaultBasketValue
+	}
+
+	seen := make(map[string]bool)
+	var sum float64
+	for i := range basket.Constituents {
+		constituent := &basket.Constituents[i]
+		constituent.Symbol = strings.ToUpper(constituent.Symbol)
+		if constituent.Symbol == "" || seen[constituent.Symbol] {
+			return fmt.Errorf("missing or duplicate Symbol %q", constituent.Symbol)
+		}
+		if constituent.Weight <= 0 {
+			return fmt.Errorf("weight of %s must be positive", constituent.Symbol)
+		}
+		seen[constituent.Symbol] = true
+		sum += constituent.Weight
+	}
+	for i := range basket.Constituents {
+		basket.Constituents[i].Weight /= sum
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultCandles = 500
+	// maxCandles bounds the range of a request, as candles are aggregated from raw trades.
+	maxCandles = 1000
+)
+
+// GetCandles godoc
+// @Summary Get OHLCV candles
+// @Description GetCandles returns open, high, low and close USD prices and the volume of an
+// @Description asset per interval, computed from its trades. Intervals without trades are
+// @Description omitted. The range is limited to 1000 candles.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   interval   query   string     false       "1m 5m 15m 1h 4h 1d, default 1h"
+// @Param   exchanges  query   string     false       "Comma separated exchanges, default all"
+// @Param   starttime  query   int        false       "Unix timestamp, default 500 intervals before endtime"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {array} models.Candle "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/candles/:symbol [get]
+func (env *Env) GetCandles(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	interval := c.DefaultQuery("interval", "1h")
+	duration, ok := models.CandleIntervals[interval]
+	if !ok {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("interval must be one of 1m, 5m, 15m, 1h, 4h, 1d"))
+		return
+	}
+	exchanges, err := exchangesQuery(c, "exchanges")
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+
+	endtime, err := unixQuery(c, "endtime", time.Now())
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	starttime, err := unixQuery(c, "starttime", endtime.Add(-defaultCandles*duration))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !starttime.Before(endtime) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("starttime must be before endtime"))
+		return
+	}
+	if endtime.Sub(starttime) > maxCandles*duration {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("range exceeds %d candles of %s", maxCandles, interval))
+		return
+	}
+
+	candles, err := env.DataStore.GetCandles(symbol, exchanges, interval, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, candles)
+}
@@ -0,0 +1,193 @@
+package diaApi
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// compressor is a gzip or flate writer, which can be reset to write to another response.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressWriter compresses the response body once its status and content type are known. The
+// decision is taken on the first write or flush, so that handlers can still set headers.
+// Handlers see their own headers without the encoding, so that cache.CachePage doesn't store
+// the encoding along with the uncompressed body.
+type compressWriter struct {
+	gin.ResponseWriter
+	header     http.Header
+	encoding   string
+	pool       *sync.Pool
+	started    bool
+	compressor compressor
+}
+
+// Compression returns a middleware compressing responses with gzip or deflate at @level, as
+// negotiated with Accept-Encoding. Bodies are compressed while they are written, so streamed
+// responses such as exports stay streamed and are flushed chunk by chunk. Websocket upgrades,
+// server-sent events, empty responses and responses which are already encoded are left as is.
+func Compression(level int) gin.HandlerFunc {
+	pools := map[string]*sync.Pool{
+		encodingGzip: {New: func() interface{} {
+			w, err := gzip.NewWriterLevel(nil, level)
+			if err != nil {
+				log.Fatalf("Compression: gzip level %d: %v", level, err)
+			}
+			return w
+		}},
+		encodingDeflate: {New: func() interface{} {
+			w, err := flate.NewWriter(nil, level)
+			if err != nil {
+				log.Fatalf("Compression: deflate level %d: %v", level, err)
+			}
+			return w
+		}},
+	}
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			header:         c.Writer.Header().Clone(),
+			encoding:       encoding,
+			pool:           pools[encoding],
+		}
+		c.Writer = writer
+		defer writer.close()
+		c.Next()
+	}
+}
+
+func (w *compressWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *compressWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	header := w.copyHeader()
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" || strings.HasPrefix(header.Get("Content-Type"), "text/event-stream") {
+		return
+	}
+	header.Set("Content-Encoding", w.encoding)
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	// The encoded body differs from the one the ETag was computed from.
+	if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		header.Set("ETag", "W/"+etag)
+	}
+	w.compressor = w.pool.Get().(compressor)
+	w.compressor.Reset(w.ResponseWriter)
+}
+
+// copyHeader copies the headers set by the handlers to the response and returns its headers.
+func (w *compressWriter) copyHeader() http.Header {
+	header := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		header[key] = values
+	}
+	return header
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.start()
+	if w.compressor == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.compressor.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	w.start()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends the data compressed so far to the client.
+func (w *compressWriter) Flush() {
+	w.start()
+	if w.compressor != nil {
+		if err := w.compressor.Flush(); err != nil {
+			log.Errorf("Compression: flush: %v", err)
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close writes the end of the compressed stream and returns the compressor to its pool.
+func (w *compressWriter) close() {
+	if !w.started {
+		// Responses without body still need the headers of the handlers.
+		w.started = true
+		w.copyHeader()
+	}
+	if w.compressor == nil {
+		return
+	}
+	if err := w.compressor.Close(); err != nil {
+		log.Errorf("Compression: close: %v", err)
+	}
+	w.compressor.Reset(nil)
+	w.pool.Put(w.compressor)
+	w.compressor = nil
+}
+
+// negotiateEncoding returns the encoding of a response to a request with @acceptEncoding,
+// gzip if acceptable, else deflate, or an empty string if neither is acceptable. Codings which
+// aren't listed are acceptable if * is.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		qualities[coding] = q
+	}
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		q, listed := qualities[encoding]
+		if !listed {
+			q = qualities["*"]
+		}
+		if q > 0 {
+			return encoding
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package diaApi
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                         "",
+		"gzip, deflate, br":        "gzip",
+		"deflate;q=0.5, gzip;q=0":  "deflate",
+		"*":                        "gzip",
+		"*;q=0, deflate":           "deflate",
+		"identity":                 "",
+		" GZIP ; q=1.0 , identity": "gzip",
+	}
+	for acceptEncoding, expected := range cases {
+		if encoding := negotiateEncoding(acceptEncoding); encoding != expected {
+			t.Errorf("%q: expected %q, got %q", acceptEncoding, expected, encoding)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := strings.Repeat(`{"Symbol":"BTC","Price":50000}`, 100)
+	// headers records the headers seen by the handlers, which cache.CachePage stores.
+	var headers http.Header
+	r := gin.New()
+	r.Use(Compression(gzip.DefaultCompression))
+	r.GET("/json", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.String(http.StatusOK, body)
+		headers = c.Writer.Header()
+	})
+	r.GET("/empty", func(c *gin.Context) {
+		c.Header("X-Test", "1")
+		c.Status(http.StatusNoContent)
+	})
+
+	get := func(path string, acceptEncoding string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/json", "gzip")
+	if w.Header().Get("Content-Encoding") != "gzip" || w.Body.Len() >= len(body) {
+		t.Fatalf("expected compressed response, got %q with %d bytes", w.Header().Get("Content-Encoding"), w.Body.Len())
+	}
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil || string(decompressed) != body {
+		t.Errorf("unexpected decompressed body: %v", err)
+	}
+	if headers.Get("Content-Encoding") != "" {
+		t.Errorf("expected handlers not to see the encoding")
+	}
+
+	if w = get("/json", ""); w.Header().Get("Content-Encoding") != "" || w.Body.String() != body {
+		t.Errorf("expected uncompressed response without Accept-Encoding")
+	}
+	if w = get("/empty", "gzip"); w.Code != http.StatusNoContent || w.Header().Get("Content-Encoding") != "" || w.Header().Get("X-Test") != "1" || w.Body.Len() != 0 {
+		t.Errorf("expected empty response with the handler's headers, got %d %v", w.Code, w.Header())
+	}
+}
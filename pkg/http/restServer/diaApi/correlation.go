@@ -0,0 +1,131 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxCorrelationSymbols is the maximal number of symbols of a correlation matrix.
+const MaxCorrelationSymbols = 20
+
+// GetCorrelations godoc
+// @Summary Get the correlation matrix of assets
+// @Description GetCorrelations returns the pairwise correlations of the log returns of assets over
+// @Description a window until endtime, with prices sampled at interval.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbols    query   string     true        "Comma separated symbols, e.g. BTC,ETH,MATIC, at most 20"
+// @Param   window     query   string     false       "Window, e.g. 7d, 30d or 12w, default 30d, at most 365d"
+// @Param   interval   query   string     false       "5m 30m 1h 4h 1d 1w, default 1d"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {object} models.CorrelationMatrix "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/correlation [get]
+func (env *Env) GetCorrelations(c *gin.Context) {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, symbol := range strings.Split(c.Query("symbols"), ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		if !utils.QuerySafe(symbol) {
+			restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+			return
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	if len(symbols) < 2 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("at least two symbols required"))
+		return
+	}
+	if len(symbols) > MaxCorrelationSymbols {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("at most %d symbols per request", MaxCorrelationSymbols))
+		return
+	}
+	resolution, starttime, endtime, ok := windowQuery(c, defaultVolatilityWindow, defaultVolatilityInterval)
+	if !ok {
+		return
+	}
+
+	returns := make([][]logReturn, len(symbols))
+	returnsByTime := make([]map[time.Time]float64, len(symbols))
+	for i, symbol := range symbols {
+		var err error
+		returns[i], err = env.logReturns(symbol, resolution, starttime, endtime)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		returnsByTime[i] = make(map[time.Time]float64, len(returns[i]))
+		for _, r := range returns[i] {
+			returnsByTime[i][r.Time] = r.Value
+		}
+	}
+
+	matrix := models.CorrelationMatrix{
+		Symbols:      symbols,
+		Window:       c.DefaultQuery("window", defaultVolatilityWindow),
+		Interval:     resolution,
+		StartTime:    starttime,
+		EndTime:      endtime,
+		Correlations: make([][]*float64, len(symbols)),
+	}
+	for i := range symbols {
+		matrix.Correlations[i] = make([]*float64, len(symbols))
+	}
+	for i := range symbols {
+		for j := i; j < len(symbols); j++ {
+			var x, y []float64
+			for _, r := range returns[i] {
+				if v, ok := returnsByTime[j][r.Time]; ok {
+					x = append(x, r.Value)
+					y = append(y, v)
+				}
+			}
+			if correlation, ok := pearson(x, y); ok {
+				matrix.Correlations[i][j] = &correlation
+				matrix.Correlations[j][i] = &correlation
+			}
+		}
+	}
+	c.JSON(http.StatusOK, matrix)
+}
+
+// pearson returns the Pearson correlation of @x and @y, which have the same length. It returns
+// false if it is undefined.
+func pearson(x []float64, y []float64) (float64, bool) {
+	if len(x) < 2 {
+		return 0, false
+	}
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= float64(len(x))
+	meanY /= float64(len(y))
+	var covariance, varianceX, varianceY float64
+	for i := range x {
+		covariance += (x[i] - meanX) * (y[i] - meanY)
+		varianceX += (x[i] - meanX) * (x[i] - meanX)
+		varianceY += (y[i] - meanY) * (y[i] - meanY)
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return 0, false
+	}
+	// Rounding can exceed the bounds for perfectly correlated returns.
+	return math.Max(-1, math.Min(1, covariance/math.Sqrt(varianceX*varianceY))), true
+}
@@ -0,0 +1,67 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+)
+
+// GetCrossRate godoc
+// @Summary Get the cross rate of two assets
+// @Description GetCrossRate returns the price of base in units of quote, e.g. MATIC/ETH or BTC/EUR.
+// @Description It is computed from the USD quotations of both, which are fiat rates for fiat
+// @Description currencies, and returned along with them.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   base     path    string     true        "Some symbol, e.g. MATIC"
+// @Param   quote    path    string     true        "Some symbol or fiat currency, e.g. EUR"
+// @Success 200 {object} models.CrossRate "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No quotation of base or quote"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/crossrate/:base/:quote [get]
+func (env *Env) GetCrossRate(c *gin.Context) {
+	base := strings.ToUpper(c.Param("base"))
+	quote := strings.ToUpper(c.Param("quote"))
+
+	rates := make([]models.CrossRateConstituent, 0, 2)
+	for _, symbol := range []string{base, quote} {
+		q, err := env.DataStore.GetQuotation(symbol)
+		if err != nil {
+			if err == redis.Nil {
+				restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no quotation of %s", symbol))
+			} else {
+				restApi.SendError(c, http.StatusInternalServerError, err)
+			}
+			return
+		}
+		rates = append(rates, models.CrossRateConstituent{
+			Symbol: symbol,
+			Price:  q.Price,
+			Source: q.Source,
+			Time:   q.Time,
+		})
+	}
+	if rates[1].Price == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no price of %s", quote))
+		return
+	}
+
+	crossRate := models.CrossRate{
+		Base:  base,
+		Quote: quote,
+		Price: rates[0].Price / rates[1].Price,
+		Time:  rates[0].Time,
+		Rates: rates,
+	}
+	if rates[1].Time.Before(crossRate.Time) {
+		crossRate.Time = rates[1].Time
+	}
+	c.JSON(http.StatusOK, crossRate)
+}
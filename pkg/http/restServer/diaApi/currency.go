@@ -0,0 +1,52 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+)
+
+// fxQuotation returns the USD quotation of the currency in the currency query parameter, e.g.
+// EUR, which the ECB scraper stores from the ECB reference rates. It returns nil if prices stay
+// in USD, and sends an error response if the currency is unknown.
+func (env *Env) fxQuotation(c *gin.Context) (fx *models.Quotation, ok bool) {
+	currency := strings.ToUpper(c.Query("currency"))
+	if currency == "" || currency == "USD" {
+		return nil, true
+	}
+	fx, err := env.DataStore.GetQuotation(currency)
+	if err != nil {
+		if err == redis.Nil {
+			restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("no exchange rate of currency %q", currency))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return nil, false
+	}
+	if fx.Price == 0 {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("zero exchange rate of currency %s", currency))
+		return nil, false
+	}
+	return fx, true
+}
+
+// convertQuotation converts the prices of @q to the currency of @fx. Both prices are converted
+// at the latest rate.
+func convertQuotation(q *models.Quotation, fx *models.Quotation) models.ConvertedQuotation {
+	converted := models.ConvertedPrice{
+		Currency: fx.Symbol,
+		Price:    q.Price / fx.Price,
+		FXRate:   fx.Price,
+		FXTime:   fx.Time,
+	}
+	if q.PriceYesterday != nil {
+		priceYesterday := *q.PriceYesterday / fx.Price
+		converted.PriceYesterday = &priceYesterday
+	}
+	return models.ConvertedQuotation{Quotation: q, Converted: converted}
+}
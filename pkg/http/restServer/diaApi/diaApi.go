@@ -7,8 +7,10 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/diadata-org/diadata/internal/pkg/fixingRateService"
 	"github.com/diadata-org/diadata/internal/pkg/indexCalculationService"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
@@ -18,12 +20,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis"
+	"github.com/jackc/pgx/v4"
 	log "github.com/sirupsen/logrus"
 )
 
 type Env struct {
 	DataStore models.Datastore
 	RelDB     models.RelDB
+	// BootstrapAdmin is the username which is granted the admin role without a postgres entry.
+	BootstrapAdmin string
 }
 
 // PostSupply godoc
@@ -78,16 +83,24 @@ func (env *Env) PostSupply(c *gin.Context) {
 
 // GetQuotation godoc
 // @Summary Get quotation
-// @Description GetQuotation
+// @Description GetQuotation returns the USD quotation of a symbol. With currency, the prices are
+// @Description also converted to that fiat currency at its ECB rate.
 // @Tags dia
 // @Accept  json
 // @Produce  json
 // @Param   symbol     path    string     true        "Some symbol"
+// @Param   currency   query   string     false       "Fiat currency, e.g. EUR, GBP or JPY"
 // @Success 200 {object} models.Quotation "success"
+// @Success 200 {object} models.ConvertedQuotation "success with currency"
+// @Failure 400 {object} restApi.APIError "Unknown currency"
 // @Failure 404 {object} restApi.APIError "Symbol not found"
 // @Failure 500 {object} restApi.APIError "error"
 // @Router /v1/quotation/:symbol: [get]
 func (env *Env) GetQuotation(c *gin.Context) {
+	fx, ok := env.fxQuotation(c)
+	if !ok {
+		return
+	}
 	symbol := c.Param("symbol")
 	q, err := env.DataStore.GetQuotation(symbol)
 	if err != nil {
@@ -96,11 +109,83 @@ func (env *Env) GetQuotation(c *gin.Context) {
 		} else {
 			restApi.SendError(c, http.StatusInternalServerError, err)
 		}
+	} else if fx != nil {
+		c.JSON(http.StatusOK, convertQuotation(q, fx))
 	} else {
 		c.JSON(http.StatusOK, q)
 	}
 }
 
+// MaxBatchQuotations is the maximal number of symbols of a single request to GetQuotations.
+const MaxBatchQuotations = 100
+
+// GetQuotations godoc
+// @Summary Get quotations of several symbols
+// @Description GetQuotations returns the quotations of all requested symbols in one response.
+// @Description Unknown symbols are omitted, so clients should match quotations by their symbol.
+// @Description With currency, the prices are also converted to that fiat currency.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbols     query    string     true        "Comma separated symbols, e.g. BTC,ETH,MATIC"
+// @Param   currency    query    string     false       "Fiat currency, e.g. EUR, GBP or JPY"
+// @Success 200 {array} models.Quotation "success"
+// @Success 200 {array} models.ConvertedQuotation "success with currency"
+// @Failure 400 {object} restApi.APIError "Missing or too many symbols, or unknown currency"
+// @Failure 404 {object} restApi.APIError "No symbol found"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/quotations [get]
+func (env *Env) GetQuotations(c *gin.Context) {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, symbol := range strings.Split(c.Query("symbols"), ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	if len(symbols) == 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("symbols is required"))
+		return
+	}
+	if len(symbols) > MaxBatchQuotations {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("at most %d symbols per request", MaxBatchQuotations))
+		return
+	}
+	fx, ok := env.fxQuotation(c)
+	if !ok {
+		return
+	}
+
+	quotations := []*models.Quotation{}
+	for _, symbol := range symbols {
+		q, err := env.DataStore.GetQuotation(symbol)
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		quotations = append(quotations, q)
+	}
+	if len(quotations) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no quotation found"))
+		return
+	}
+	if fx != nil {
+		converted := make([]models.ConvertedQuotation, len(quotations))
+		for i, q := range quotations {
+			converted[i] = convertQuotation(q, fx)
+		}
+		c.JSON(http.StatusOK, converted)
+		return
+	}
+	c.JSON(http.StatusOK, quotations)
+}
+
 func (env *Env) GetPaxgQuotationOunces(c *gin.Context) {
 	q, err := env.DataStore.GetPaxgQuotationOunces()
 	if err != nil {
@@ -206,9 +291,30 @@ func (env *Env) GetLastPriceBefore(c *gin.Context) {
 	}
 }
 
-// GetSupply returns latest supply of token with @symbol
+// GetSupply godoc
+// @Summary Get supply
+// @Description GetSupply returns the latest supply of a symbol. With from, it returns the supplies
+// @Description from that time until to, which defaults to now, in ascending order. The first of
+// @Description them is the latest supply before from, so that market caps can be computed for the
+// @Description whole range.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   from       query   int        false       "Unix timestamp"
+// @Param   to         query   int        false       "Unix timestamp"
+// @Success 200 {object} dia.Supply "success"
+// @Success 200 {array} dia.Supply "success with from"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "Symbol not found"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/supply/:symbol [get]
 func (env *Env) GetSupply(c *gin.Context) {
 	symbol := c.Param("symbol")
+	if c.Query("from") != "" || c.Query("to") != "" {
+		env.getSupplyHistory(c, symbol)
+		return
+	}
 	s, err := env.DataStore.GetLatestSupply(symbol)
 	if err != nil {
 		if err == redis.Nil {
@@ -221,6 +327,40 @@ func (env *Env) GetSupply(c *gin.Context) {
 	}
 }
 
+// getSupplyHistory responds with the supplies of @symbol in the range of the from and to query
+// parameters.
+func (env *Env) getSupplyHistory(c *gin.Context, symbol string) {
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	if c.Query("from") == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("from is required with to"))
+		return
+	}
+	from, err := unixQuery(c, "from", time.Time{})
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	to, err := unixQuery(c, "to", time.Now())
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if to.Before(from) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("to precedes from"))
+		return
+	}
+
+	supplies, err := env.DataStore.GetSupplyHistoryInflux(symbol, from, to)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, supplies)
+}
+
 // GetSupplies returns a time range of supplies of token with @symbol
 func (env *Env) GetSupplies(c *gin.Context) {
 	symbol := c.Param("symbol")
@@ -395,16 +535,55 @@ func (env *Env) GetCoins(c *gin.Context) {
 	}
 }
 
-// GetExchanges is the delegate method for fetching all
-// available trading places.
+// GetExchanges godoc
+// @Summary Get exchanges
+// @Description GetExchanges returns all trading places with the time of their last trade, their
+// @Description number of trades in the last 24 hours and the state of their scraper: live if it
+// @Description delivered a trade in the last 15 minutes, stale if it did in the last 7 days and
+// @Description inactive otherwise.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.ExchangeStatus "success"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/exchanges [get]
 func (env *Env) GetExchanges(c *gin.Context) {
-	q := env.DataStore.GetExchanges()
-	if len(q) == 0 {
-		restApi.SendError(c, http.StatusInternalServerError, nil)
+	q, err := env.DataStore.GetExchangeStatus()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
 	}
 	c.JSON(http.StatusOK, q)
 }
 
+// GetPairMeta returns tick sizes, minimal order sizes and fee tiers of the pairs traded on
+// an exchange. If the optional path parameter pair is given, only this pair is returned.
+func (env *Env) GetPairMeta(c *gin.Context) {
+	exchange := c.Param("exchange")
+	foreignName := c.Param("pair")
+
+	meta, err := env.DataStore.GetPairMetaForExchange(exchange)
+	if err != nil {
+		if err == redis.Nil {
+			restApi.SendError(c, http.StatusNotFound, err)
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	if foreignName == "" {
+		c.JSON(http.StatusOK, meta)
+		return
+	}
+	for _, pm := range meta {
+		if strings.EqualFold(pm.ForeignName, foreignName) {
+			c.JSON(http.StatusOK, pm)
+			return
+		}
+	}
+	restApi.SendError(c, http.StatusNotFound, errors.New("pair not found"))
+}
+
 // GetChartPoints godoc
 // @Summary Get chart points for
 // @Description Get Symbol Details
@@ -1311,18 +1490,22 @@ func (env *Env) GetCryptoIndexMintAmounts(c *gin.Context) {
 	}
 }
 
-// Get last 1000 trades of an asset
+// GetLastTrades returns the last 1000 trades of an asset, paginated as described in listQuery.
 func (env *Env) GetLastTrades(c *gin.Context) {
 	symbol := c.Param("symbol")
-	q, err := env.DataStore.GetLastTradesAllExchanges(symbol, 1000)
+	q, err := env.DataStore.GetLastTradesAllExchanges(symbol, maxPageLimit)
 	if err != nil {
 		if err == redis.Nil {
 			restApi.SendError(c, http.StatusNotFound, err)
 		} else {
 			restApi.SendError(c, http.StatusInternalServerError, err)
 		}
-	} else {
-		c.JSON(http.StatusOK, q)
+		return
+	}
+	// Pages default to all trades, which the endpoint returned before it was paginated.
+	page, ok := paginate(c, q, maxPageLimit)
+	if ok {
+		c.JSON(http.StatusOK, page)
 	}
 }
 
@@ -1411,14 +1594,19 @@ func (env *Env) GetNFTCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, q)
 }
 
-// GetNFTClasses returns all NFT classes.
+// GetAllNFTClasses returns the NFT classes on a blockchain, paginated as described in listQuery.
 func (env *Env) GetAllNFTClasses(c *gin.Context) {
 	blockchain := c.Param("blockchain")
 	q, err := env.RelDB.GetAllNFTClasses(blockchain)
 	if len(q) == 0 || err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, nil)
+		return
+	}
+	// Pages default to all classes, which the endpoint returned before it was paginated.
+	page, ok := paginate(c, q, maxPageLimit)
+	if ok {
+		c.JSON(http.StatusOK, page)
 	}
-	c.JSON(http.StatusOK, q)
 }
 
 // GetNFTClasses returns all NFT classes.
@@ -1453,7 +1641,8 @@ func (env *Env) GetNFT(c *gin.Context) {
 	c.JSON(http.StatusOK, q)
 }
 
-// GetNFTTrades returns all trades of the unique NFT with given parameters.
+// GetNFTTrades returns the trades of the unique NFT with given parameters, paginated as described
+// in listQuery.
 func (env *Env) GetNFTTrades(c *gin.Context) {
 	blockchain := c.Param("blockchain")
 	// Sanitize address
@@ -1463,12 +1652,18 @@ func (env *Env) GetNFTTrades(c *gin.Context) {
 	nft, err := env.RelDB.GetNFT(address, blockchain, id)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, nil)
+		return
 	}
 	q, err := env.RelDB.GetNFTTrades(nft)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, nil)
+		return
+	}
+	// Pages default to all trades, which the endpoint returned before it was paginated.
+	page, ok := paginate(c, q, maxPageLimit)
+	if ok {
+		c.JSON(http.StatusOK, page)
 	}
-	c.JSON(http.StatusOK, q)
 }
 
 // GetNFTPrice30Days returns the average price of the whole nft class over the last 30 days.
@@ -1486,3 +1681,121 @@ func (env *Env) GetNFTPrice30Days(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, avgPrice)
 }
+
+// -----------------------------------------------------------------------------
+// FIXING RATES
+// -----------------------------------------------------------------------------
+
+// GetFixingRate returns the daily fixing rate of a symbol. The optional path parameter
+// date (YYYY-MM-DD) selects the fixing of the given day, default is the latest fixing.
+func (env *Env) GetFixingRate(c *gin.Context) {
+	symbol := c.Param("symbol")
+	date := c.Param("date")
+
+	var fixing models.FixingRate
+	var err error
+	if date == "" {
+		fixing, err = env.RelDB.GetLatestFixingRate(symbol)
+	} else {
+		var day time.Time
+		day, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+		fixing, err = env.RelDB.GetFixingRate(symbol, fixingRateService.FixingTime(day))
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			restApi.SendError(c, http.StatusNotFound, err)
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, fixing)
+}
+
+// GetFixingRates returns all fixing rates of a symbol in the range given by the optional
+// query parameters starttime and endtime (unix timestamps). Default is the last 30 days.
+// The rates are paginated as described in listQuery.
+func (env *Env) GetFixingRates(c *gin.Context) {
+	symbol := c.Param("symbol")
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), 30*24*time.Hour)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	q, err := env.RelDB.GetFixingRates(symbol, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	// Pages default to all rates, which the endpoint returned before it was paginated.
+	page, ok := paginate(c, q, maxPageLimit)
+	if ok {
+		c.JSON(http.StatusOK, page)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// ORACLE UPDATES
+// -----------------------------------------------------------------------------
+
+// GetOracleUpdates returns the audit trail of the updates of an oracle feed given by the query
+// parameter key, e.g. BTC/USD. Optional query parameters are chainID, contract, starttime and
+// endtime (unix timestamps). Default is the last 24 hours on all chains and contracts.
+func (env *Env) GetOracleUpdates(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("key is required"))
+		return
+	}
+	var chainID int64
+	if s := c.Query("chainID"); s != "" {
+		var err error
+		chainID, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), 24*time.Hour)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	q, err := env.RelDB.GetOracleUpdates(chainID, c.Query("contract"), key, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, q)
+}
+
+// -----------------------------------------------------------------------------
+// BASIS
+// -----------------------------------------------------------------------------
+
+// GetBasis returns the basis between the DIA spot price of an asset and the mark prices of its
+// perpetual swaps. Optional query parameters are venue, starttime and endtime (unix timestamps).
+// Default is the last 24 hours on all venues.
+func (env *Env) GetBasis(c *gin.Context) {
+	asset := c.Param("asset")
+	venue := c.Query("venue")
+	if !utils.QuerySafe(asset, venue) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid asset %q or venue %q", asset, venue))
+		return
+	}
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), 24*time.Hour)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	q, err := env.DataStore.GetBasisInflux(asset, venue, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, q)
+}
@@ -0,0 +1,112 @@
+package diaApi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// assetEquivalences is a canonical asset along with its bridged and wrapped representations.
+type assetEquivalences struct {
+	Canonical string
+	Assets    []dia.Asset
+}
+
+// GetAssetEquivalences returns the representations of a canonical symbol. Its quotation is
+// computed from the trades of all of them, while each representation keeps its own quotation.
+func (env *Env) GetAssetEquivalences(c *gin.Context) {
+	canonical := strings.ToUpper(c.Param("symbol"))
+	equivalences, err := env.RelDB.GetAssetEquivalences(canonical)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(equivalences) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no representations of "+canonical))
+		return
+	}
+	c.JSON(http.StatusOK, newAssetEquivalences(canonical, equivalences))
+}
+
+// PostAssetEquivalence declares the asset given by Blockchain and Address in the body a
+// representation of a canonical symbol. The filters pick it up within ten minutes.
+func (env *Env) PostAssetEquivalence(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var request struct {
+		Blockchain string
+		Address    string
+	}
+	err = json.Unmarshal(body, &request)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if request.Blockchain == "" || request.Address == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing Blockchain or Address"))
+		return
+	}
+
+	canonical := strings.ToUpper(c.Param("symbol"))
+	err = env.RelDB.SetAssetEquivalence(canonical, dia.Asset{Blockchain: request.Blockchain, Address: request.Address})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			restApi.SendError(c, http.StatusNotFound, errors.New("unknown asset"))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	env.sendAssetEquivalences(c, canonical)
+}
+
+// DeleteAssetEquivalence removes the asset given by the query parameters blockchain and address
+// from the representations of a canonical symbol. Assets representing another symbol are kept.
+func (env *Env) DeleteAssetEquivalence(c *gin.Context) {
+	asset := dia.Asset{Blockchain: c.Query("blockchain"), Address: c.Query("address")}
+	if asset.Blockchain == "" || asset.Address == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing blockchain or address"))
+		return
+	}
+	canonical := strings.ToUpper(c.Param("symbol"))
+	err := env.RelDB.DeleteAssetEquivalence(canonical, asset)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			restApi.SendError(c, http.StatusNotFound, errors.New("asset is no representation of "+canonical))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	env.sendAssetEquivalences(c, canonical)
+}
+
+// sendAssetEquivalences responds with the representations of @canonical.
+func (env *Env) sendAssetEquivalences(c *gin.Context, canonical string) {
+	equivalences, err := env.RelDB.GetAssetEquivalences(canonical)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, newAssetEquivalences(canonical, equivalences))
+}
+
+// newAssetEquivalences collects the assets of the representations @equivalences of @canonical.
+func newAssetEquivalences(canonical string, equivalences []models.AssetEquivalence) assetEquivalences {
+	response := assetEquivalences{Canonical: canonical, Assets: []dia.Asset{}}
+	for _, equivalence := range equivalences {
+		response.Assets = append(response.Assets, equivalence.Asset)
+	}
+	return response
+}
@@ -0,0 +1,121 @@
+package diaApi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// etagWriter buffers the response body so that ConditionalGet can hash it or drop it.
+type etagWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *etagWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ConditionalGet returns a middleware for endpoints polled at high frequency. It sets an ETag,
+// Last-Modified and Cache-Control with @maxAge on successful json responses, and answers requests
+// whose If-None-Match or If-Modified-Since still match with 304 Not Modified and no body.
+// Last-Modified is the latest Time in the response, i.e. the timestamp of the quotation.
+func ConditionalGet(maxAge time.Duration) gin.HandlerFunc {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+	return func(c *gin.Context) {
+		writer := &etagWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if writer.Status() == http.StatusOK && strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+			sum := sha256.Sum256(body)
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+			header := c.Writer.Header()
+			header.Set("ETag", etag)
+			header.Set("Cache-Control", cacheControl)
+			lastModified := latestTime(body)
+			if !lastModified.IsZero() {
+				header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+			}
+			if notModified(c.Request, etag, lastModified) {
+				c.Writer.WriteHeader(http.StatusNotModified)
+				c.Writer.WriteHeaderNow()
+				return
+			}
+		}
+		_, err := c.Writer.Write(body)
+		if err != nil {
+			log.Errorf("ConditionalGet: write response: %v", err)
+		}
+	}
+}
+
+// notModified returns true if the conditional headers of @request match a response with @etag
+// and @lastModified. If-Modified-Since is ignored if If-None-Match is present, as in RFC 7232.
+func notModified(request *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(request.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// latestTime returns the latest value of the Time keys in the json document @data, or the zero
+// time if it has none.
+func latestTime(data []byte) time.Time {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return time.Time{}
+	}
+	return maxTime(document)
+}
+
+func maxTime(value interface{}) (latest time.Time) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			if s, ok := element.(string); ok && key == "Time" {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil && t.After(latest) {
+					latest = t
+				}
+				continue
+			}
+			if t := maxTime(element); t.After(latest) {
+				latest = t
+			}
+		}
+	case []interface{}:
+		for _, element := range v {
+			if t := maxTime(element); t.After(latest) {
+				latest = t
+			}
+		}
+	}
+	return
+}
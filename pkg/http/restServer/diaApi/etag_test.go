@@ -0,0 +1,61 @@
+package diaApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConditionalGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/quotation", ConditionalGet(30*time.Second), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"Symbol": "BTC", "Price": 42.0, "Time": "2021-03-04T05:06:07.5Z"})
+	})
+
+	get := func(header string, value string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/quotation", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("expected 200 with ETag and body, got %d %q %q", w.Code, etag, w.Body.String())
+	}
+	if lastModified := w.Header().Get("Last-Modified"); lastModified != "Thu, 04 Mar 2021 05:06:07 GMT" {
+		t.Errorf("unexpected Last-Modified %q", lastModified)
+	}
+	if cacheControl := w.Header().Get("Cache-Control"); cacheControl != "public, max-age=30" {
+		t.Errorf("unexpected Cache-Control %q", cacheControl)
+	}
+
+	cases := []struct {
+		header   string
+		value    string
+		expected int
+	}{
+		{"If-None-Match", etag, http.StatusNotModified},
+		{"If-None-Match", `"other", W/` + etag, http.StatusNotModified},
+		{"If-None-Match", `"other"`, http.StatusOK},
+		{"If-Modified-Since", "Thu, 04 Mar 2021 05:06:07 GMT", http.StatusNotModified},
+		{"If-Modified-Since", "Thu, 04 Mar 2021 05:06:06 GMT", http.StatusOK},
+	}
+	for _, c := range cases {
+		w := get(c.header, c.value)
+		if w.Code != c.expected {
+			t.Errorf("%s: %s: expected %d, got %d", c.header, c.value, c.expected, w.Code)
+		}
+		if w.Code == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("%s: %s: expected empty body, got %q", c.header, c.value, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetExchangePrice godoc
+// @Summary Get the price of an asset on an exchange
+// @Description GetExchangePrice returns the USD price of the latest trade of an asset on a single
+// @Description exchange, along with the trade, for reconciliation against specific venues. The
+// @Description price across exchanges is returned by /v1/quotation.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   exchange   path    string     true        "Some exchange, see /v1/exchanges"
+// @Param   symbol     path    string     true        "Some symbol"
+// @Success 200 {object} models.ExchangePrice "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No trade of the asset on the exchange"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/exchangePrice/:exchange/:symbol [get]
+func (env *Env) GetExchangePrice(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	exchange := ""
+	for _, e := range env.DataStore.GetExchanges() {
+		if strings.EqualFold(e, c.Param("exchange")) {
+			exchange = e
+			break
+		}
+	}
+	if exchange == "" {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("unknown exchange %q", c.Param("exchange")))
+		return
+	}
+
+	trades, err := env.DataStore.GetLastTrades(symbol, exchange, 1)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(trades) == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no trade of %s on %s", symbol, exchange))
+		return
+	}
+	trade := trades[0]
+	c.JSON(http.StatusOK, models.ExchangePrice{
+		Symbol:   symbol,
+		Exchange: exchange,
+		Price:    trade.EstimatedUSDPrice,
+		Time:     trade.Time,
+		Trade:    trade,
+	})
+}
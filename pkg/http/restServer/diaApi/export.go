@@ -0,0 +1,402 @@
+package diaApi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	exportCSV     = "csv"
+	exportParquet = "parquet"
+	exportNDJSON  = "ndjson"
+	// tradeExportChunk is the time range of the trades queried and flushed at once.
+	tradeExportChunk = time.Hour
+	// maxTradeExportRange bounds the range of a trades export, as it is read from raw trades.
+	maxTradeExportRange = 31 * 24 * time.Hour
+)
+
+// exporter encodes the rows of an export to the response. Rows are sent to the client on each
+// flush, so that a download is made of complete chunks.
+type exporter interface {
+	trade(t *dia.Trade) error
+	price(symbol string, p models.PricePoint) error
+	flush() error
+	close() error
+}
+
+// csvExporter writes one line per row, with the time in RFC 3339 with nanoseconds.
+type csvExporter struct {
+	c *gin.Context
+	w *csv.Writer
+}
+
+func newCSVExporter(c *gin.Context, header []string) (*csvExporter, error) {
+	e := &csvExporter{c: c, w: csv.NewWriter(c.Writer)}
+	return e, e.w.Write(header)
+}
+
+func (e *csvExporter) trade(t *dia.Trade) error {
+	return e.w.Write([]string{
+		t.Time.UTC().Format(time.RFC3339Nano),
+		t.Symbol,
+		t.Pair,
+		t.Source,
+		formatFloat(t.Price),
+		formatFloat(t.Volume),
+		formatFloat(t.EstimatedUSDPrice),
+		t.ForeignTradeID,
+		t.VerificationStatus,
+	})
+}
+
+func (e *csvExporter) price(symbol string, p models.PricePoint) error {
+	return e.w.Write([]string{p.Time.UTC().Format(time.RFC3339Nano), symbol, formatFloat(p.Price)})
+}
+
+func (e *csvExporter) flush() error {
+	e.w.Flush()
+	e.c.Writer.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) close() error {
+	return e.flush()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+var (
+	tradeCSVHeader = []string{"time", "symbol", "pair", "exchange", "price", "volume", "estimatedUSDPrice", "foreignTradeID", "verification"}
+	priceCSVHeader = []string{"time", "symbol", "price"}
+)
+
+type tradeRow struct {
+	Time              int64   `parquet:"name=time, type=TIMESTAMP_MICROS"`
+	Symbol            string  `parquet:"name=symbol, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Pair              string  `parquet:"name=pair, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Exchange          string  `parquet:"name=exchange, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Price             float64 `parquet:"name=price, type=DOUBLE"`
+	Volume            float64 `parquet:"name=volume, type=DOUBLE"`
+	EstimatedUSDPrice float64 `parquet:"name=estimatedUSDPrice, type=DOUBLE"`
+	ForeignTradeID    string  `parquet:"name=foreignTradeID, type=UTF8"`
+	Verification      string  `parquet:"name=verification, type=UTF8, encoding=PLAIN_DICTIONARY"`
+}
+
+type priceRow struct {
+	Time   int64   `parquet:"name=time, type=TIMESTAMP_MICROS"`
+	Symbol string  `parquet:"name=symbol, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Price  float64 `parquet:"name=price, type=DOUBLE"`
+}
+
+// parquetExporter writes a row group per flush. The file is only readable once closed, as
+// parquet keeps its metadata in the footer.
+type parquetExporter struct {
+	c  *gin.Context
+	pw *writer.ParquetWriter
+}
+
+func newParquetExporter(c *gin.Context, schema interface{}) (*parquetExporter, error) {
+	pw, err := writer.NewParquetWriterFromWriter(c.Writer, schema, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetExporter{c: c, pw: pw}, nil
+}
+
+func (e *parquetExporter) trade(t *dia.Trade) error {
+	return e.pw.Write(tradeRow{
+		Time:              t.Time.UnixNano() / 1000,
+		Symbol:            t.Symbol,
+		Pair:              t.Pair,
+		Exchange:          t.Source,
+		Price:             t.Price,
+		Volume:            t.Volume,
+		EstimatedUSDPrice: t.EstimatedUSDPrice,
+		ForeignTradeID:    t.ForeignTradeID,
+		Verification:      t.VerificationStatus,
+	})
+}
+
+func (e *parquetExporter) price(symbol string, p models.PricePoint) error {
+	return e.pw.Write(priceRow{Time: p.Time.UnixNano() / 1000, Symbol: symbol, Price: p.Price})
+}
+
+func (e *parquetExporter) flush() error {
+	err := e.pw.Flush(true)
+	e.c.Writer.Flush()
+	return err
+}
+
+func (e *parquetExporter) close() error {
+	err := e.pw.WriteStop()
+	e.c.Writer.Flush()
+	return err
+}
+
+// ndjsonExporter writes one json object per line, with the keys of the CSV header. Unlike a json
+// array, the download is valid up to its last complete line and can be resumed like a CSV.
+type ndjsonExporter struct {
+	c   *gin.Context
+	enc *json.Encoder
+}
+
+type tradeObject struct {
+	Time              time.Time `json:"time"`
+	Symbol            string    `json:"symbol"`
+	Pair              string    `json:"pair"`
+	Exchange          string    `json:"exchange"`
+	Price             float64   `json:"price"`
+	Volume            float64   `json:"volume"`
+	EstimatedUSDPrice float64   `json:"estimatedUSDPrice"`
+	ForeignTradeID    string    `json:"foreignTradeID"`
+	Verification      string    `json:"verification,omitempty"`
+}
+
+type priceObject struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Price  float64   `json:"price"`
+}
+
+func newNDJSONExporter(c *gin.Context) *ndjsonExporter {
+	return &ndjsonExporter{c: c, enc: json.NewEncoder(c.Writer)}
+}
+
+func (e *ndjsonExporter) trade(t *dia.Trade) error {
+	return e.enc.Encode(tradeObject{
+		Time:              t.Time.UTC(),
+		Symbol:            t.Symbol,
+		Pair:              t.Pair,
+		Exchange:          t.Source,
+		Price:             t.Price,
+		Volume:            t.Volume,
+		EstimatedUSDPrice: t.EstimatedUSDPrice,
+		ForeignTradeID:    t.ForeignTradeID,
+		Verification:      t.VerificationStatus,
+	})
+}
+
+func (e *ndjsonExporter) price(symbol string, p models.PricePoint) error {
+	return e.enc.Encode(priceObject{Time: p.Time.UTC(), Symbol: symbol, Price: p.Price})
+}
+
+func (e *ndjsonExporter) flush() error {
+	e.c.Writer.Flush()
+	return nil
+}
+
+func (e *ndjsonExporter) close() error {
+	return e.flush()
+}
+
+// exportParams are the query parameters shared by the export endpoints.
+type exportParams struct {
+	symbol    string
+	format    string
+	starttime time.Time
+	endtime   time.Time
+}
+
+// parseExportParams returns the parameters of an export request. The range starts after the
+// time passed as after when resuming an interrupted download.
+func parseExportParams(c *gin.Context, defaultRange time.Duration) (exportParams, error) {
+	p := exportParams{symbol: strings.ToUpper(c.Param("symbol")), format: c.DefaultQuery("format", exportCSV)}
+	if !utils.QuerySafe(p.symbol) {
+		return p, fmt.Errorf("invalid symbol %q", p.symbol)
+	}
+	if p.format != exportCSV && p.format != exportParquet && p.format != exportNDJSON {
+		return p, errors.New("format must be csv, ndjson or parquet")
+	}
+	var err error
+	p.endtime, err = unixQuery(c, "endtime", time.Now())
+	if err != nil {
+		return p, err
+	}
+	p.starttime, err = unixQuery(c, "starttime", p.endtime.Add(-defaultRange))
+	if err != nil {
+		return p, err
+	}
+	if after := c.Query("after"); after != "" {
+		t, err := time.Parse(time.RFC3339Nano, after)
+		if err != nil {
+			return p, errors.New("after must be a time of the export, e.g. 2021-03-01T12:00:00.123456789Z")
+		}
+		p.starttime = t.Add(time.Nanosecond)
+	}
+	if !p.starttime.Before(p.endtime) {
+		return p, errors.New("starttime must be before endtime")
+	}
+	return p, nil
+}
+
+// startExport sets the headers of a download of @p and returns the exporter of its rows.
+func startExport(c *gin.Context, p exportParams, name string, header []string, schema interface{}) (exporter, error) {
+	filename := fmt.Sprintf("%s-%s-%d-%d.%s", name, p.symbol, p.starttime.Unix(), p.endtime.Unix(), p.format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	switch p.format {
+	case exportParquet:
+		c.Header("Content-Type", "application/vnd.apache.parquet")
+		c.Status(http.StatusOK)
+		return newParquetExporter(c, schema)
+	case exportNDJSON:
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		return newNDJSONExporter(c), nil
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	return newCSVExporter(c, header)
+}
+
+// abortExport closes the connection of a download that failed after its first rows were sent,
+// so that the client sees an incomplete response instead of a truncated file.
+func abortExport(c *gin.Context, err error) {
+	log.Errorf("export %s: %v", c.Request.URL, err)
+	if hj, ok := c.Writer.(http.Hijacker); ok {
+		conn, _, hjErr := hj.Hijack()
+		if hjErr == nil {
+			conn.Close()
+			return
+		}
+	}
+	c.Abort()
+}
+
+// ExportTrades godoc
+// @Summary Export historical trades
+// @Description ExportTrades streams all trades of an asset between two timestamps, oldest first,
+// @Description as CSV, newline delimited JSON or Parquet. The download is sent in chunks of an
+// @Description hour of trades. An interrupted CSV or JSON download is resumed by passing the time
+// @Description of its last complete line as after. The range is limited to 31 days.
+// @Tags dia
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/vnd.apache.parquet
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   format     query   string     false       "csv, ndjson or parquet, default csv"
+// @Param   starttime  query   int        false       "Unix timestamp, default a day before endtime"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Param   after      query   string     false       "RFC 3339 time of the last received row"
+// @Success 200 {file} file "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Router /v1/export/trades/:symbol [get]
+func (env *Env) ExportTrades(c *gin.Context) {
+	p, err := parseExportParams(c, 24*time.Hour)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if p.endtime.Sub(p.starttime) > maxTradeExportRange {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("range of trades export is limited to 31 days"))
+		return
+	}
+
+	e, err := startExport(c, p, "trades", tradeCSVHeader, new(tradeRow))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for start := p.starttime; start.Before(p.endtime); start = start.Add(tradeExportChunk) {
+		end := start.Add(tradeExportChunk)
+		if end.After(p.endtime) {
+			end = p.endtime
+		}
+		trades, err := env.DataStore.GetTradesByTimerange(p.symbol, start, end)
+		if err != nil {
+			abortExport(c, err)
+			return
+		}
+		for i := range trades {
+			if err = e.trade(&trades[i]); err != nil {
+				abortExport(c, err)
+				return
+			}
+		}
+		if err = e.flush(); err != nil {
+			abortExport(c, err)
+			return
+		}
+	}
+	if err = e.close(); err != nil {
+		abortExport(c, err)
+	}
+}
+
+// ExportQuotations godoc
+// @Summary Export historical quotations
+// @Description ExportQuotations streams the prices of an asset between two timestamps at a
+// @Description fixed resolution, oldest first, as CSV, newline delimited JSON or Parquet. An
+// @Description interrupted CSV or JSON download is resumed by passing the time of its last
+// @Description complete line as after.
+// @Tags dia
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/vnd.apache.parquet
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   format     query   string     false       "csv, ndjson or parquet, default csv"
+// @Param   resolution query   string     false       "5m 30m 1h 4h 1d 1w, default 1h"
+// @Param   starttime  query   int        false       "Unix timestamp, default 30 days before endtime"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Param   after      query   string     false       "RFC 3339 time of the last received row"
+// @Success 200 {file} file "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Router /v1/export/quotations/:symbol [get]
+func (env *Env) ExportQuotations(c *gin.Context) {
+	p, err := parseExportParams(c, 30*24*time.Hour)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	resolution := c.DefaultQuery("resolution", defaultResolution)
+	if !isResolution(resolution) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("resolution must be one of %s", strings.Join(models.QuotationResolutions, ", ")))
+		return
+	}
+
+	e, err := startExport(c, p, "quotations-"+resolution, priceCSVHeader, new(priceRow))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	start := p.starttime
+	for {
+		prices, err := env.DataStore.GetQuotationRange(p.symbol, resolution, start, p.endtime, maxRangeLimit)
+		if err != nil {
+			abortExport(c, err)
+			return
+		}
+		for _, price := range prices {
+			if err = e.price(p.symbol, price); err != nil {
+				abortExport(c, err)
+				return
+			}
+		}
+		if err = e.flush(); err != nil {
+			abortExport(c, err)
+			return
+		}
+		if len(prices) < maxRangeLimit {
+			break
+		}
+		start = prices[len(prices)-1].Time.Add(time.Nanosecond)
+	}
+	if err = e.close(); err != nil {
+		abortExport(c, err)
+	}
+}
@@ -0,0 +1,79 @@
+package diaApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+)
+
+// exportStore serves trades from memory. Other methods of the datastore are not implemented.
+type exportStore struct {
+	models.Datastore
+	trades []dia.Trade
+}
+
+func (s *exportStore) GetTradesByTimerange(symbol string, starttime time.Time, endtime time.Time) ([]dia.Trade, error) {
+	var trades []dia.Trade
+	for _, t := range s.trades {
+		if t.Symbol == symbol && !t.Time.Before(starttime) && t.Time.Before(endtime) {
+			trades = append(trades, t)
+		}
+	}
+	return trades, nil
+}
+
+func TestExportTrades(t *testing.T) {
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := &exportStore{trades: []dia.Trade{
+		{Symbol: "BTC", Pair: "BTCUSDT", Source: "Binance", Price: 50000, Volume: 0.5, Time: start.Add(10 * time.Minute)},
+		{Symbol: "ETH", Pair: "ETHUSDT", Source: "Binance", Price: 1500, Volume: 2, Time: start.Add(20 * time.Minute)},
+		{Symbol: "BTC", Pair: "BTCUSD", Source: "Kraken", Price: 50100, Volume: -0.25, Time: start.Add(90*time.Minute + 5)},
+	}}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/v1/export/trades/:symbol", (&Env{DataStore: store}).ExportTrades)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/export/trades/btc?starttime=1614556800&endtime=1614567600"+query, nil))
+		return w
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body)
+	}
+	expected := "time,symbol,pair,exchange,price,volume,estimatedUSDPrice,foreignTradeID,verification\n" +
+		"2021-03-01T00:10:00Z,BTC,BTCUSDT,Binance,50000,0.5,0,,\n" +
+		"2021-03-01T01:30:00.000000005Z,BTC,BTCUSD,Kraken,50100,-0.25,0,,\n"
+	if w.Body.String() != expected {
+		t.Errorf("unexpected export\n%s", w.Body)
+	}
+
+	w = get("&after=2021-03-01T00:10:00Z")
+	if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], "2021-03-01T01:30:00.000000005Z") {
+		t.Errorf("unexpected resumed export\n%s", w.Body)
+	}
+
+	w = get("&format=parquet")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "PAR1") || !strings.HasSuffix(w.Body.String(), "PAR1") {
+		t.Errorf("expected parquet file, got status %d", w.Code)
+	}
+
+	w = get("&format=ndjson")
+	expected = `{"time":"2021-03-01T00:10:00Z","symbol":"BTC","pair":"BTCUSDT","exchange":"Binance","price":50000,"volume":0.5,"estimatedUSDPrice":0,"foreignTradeID":""}` + "\n" +
+		`{"time":"2021-03-01T01:30:00.000000005Z","symbol":"BTC","pair":"BTCUSD","exchange":"Kraken","price":50100,"volume":-0.25,"estimatedUSDPrice":0,"foreignTradeID":""}` + "\n"
+	if w.Body.String() != expected {
+		t.Errorf("unexpected ndjson export\n%s", w.Body)
+	}
+
+	if w = get("&format=xlsx"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request for unknown format, got %d", w.Code)
+	}
+}
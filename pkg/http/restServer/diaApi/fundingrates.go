@@ -0,0 +1,72 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxFundingRateRange bounds the range of historical funding rates of a request.
+const maxFundingRateRange = 31 * 24 * time.Hour
+
+// GetFundingRates godoc
+// @Summary Get funding rates of perpetual futures
+// @Description GetFundingRates returns the current funding rate of each perpetual futures
+// @Description instrument of an asset on each derivatives exchange. With starttime, it returns
+// @Description the recorded funding rates from starttime until endtime instead, oldest first.
+// @Description The range is limited to 31 days. Each rate comes with its funding interval and,
+// @Description where the exchange predicts it, the predicted rate of the next interval.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   exchanges  query   string     false       "Comma separated exchanges, default all"
+// @Param   starttime  query   int        false       "Unix timestamp"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {array} dia.FundingRate "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No current funding rate"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/fundingrate/:symbol [get]
+func (env *Env) GetFundingRates(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	exchanges, err := exchangesQuery(c, "exchanges")
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if c.Query("starttime") == "" {
+		rates, err := env.DataStore.GetLatestFundingRates(symbol, exchanges)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if len(rates) == 0 {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no current funding rate of %s", symbol))
+			return
+		}
+		c.JSON(http.StatusOK, rates)
+		return
+	}
+
+	starttime, endtime, ok := rangeQuery(c, maxFundingRateRange, maxFundingRateRange)
+	if !ok {
+		return
+	}
+	rates, err := env.DataStore.GetFundingRates(symbol, exchanges, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
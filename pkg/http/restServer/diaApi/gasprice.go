@@ -0,0 +1,62 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultGasPriceWindow = "1h"
+	maxGasPriceWindow     = 24 * time.Hour
+)
+
+// GetGasPrice godoc
+// @Summary Get the gas prices of an EVM blockchain
+// @Description GetGasPrice returns the latest sample of the gas prices of an EVM blockchain in
+// @Description gwei, i.e. the base fee, percentiles of the recent priority fees and the legacy gas
+// @Description price, along with all samples in the window.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   chain   path    string     true        "Blockchain, e.g. Ethereum or Arbitrum"
+// @Param   window  query   string     false       "Window of the history, e.g. 10m or 6h, default 1h, at most 24h"
+// @Success 200 {object} models.GasPrices "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No gas prices in the window"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/gasprice/:chain [get]
+func (env *Env) GetGasPrice(c *gin.Context) {
+	blockchain := c.Param("chain")
+	if !utils.QuerySafe(blockchain) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid chain %q", blockchain))
+		return
+	}
+	window, err := parseWindow(c.DefaultQuery("window", defaultGasPriceWindow))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if window > maxGasPriceWindow {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("window exceeds 24h"))
+		return
+	}
+
+	endtime := time.Now()
+	history, err := env.DataStore.GetGasPrices(blockchain, endtime.Add(-window), endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(history) == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no gas prices of %s in the window", blockchain))
+		return
+	}
+	c.JSON(http.StatusOK, models.GasPrices{Latest: history[len(history)-1], History: history})
+}
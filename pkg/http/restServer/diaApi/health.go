@@ -0,0 +1,123 @@
+package diaApi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+)
+
+// Overall states of the API in Health.
+const (
+	// HealthOK means all sources are fresh.
+	HealthOK = "ok"
+	// HealthDegraded means a source is stale, but all critical sources are fresh.
+	HealthDegraded = "degraded"
+	// HealthUnavailable means a critical source is stale. The response status is 503.
+	HealthUnavailable = "unavailable"
+)
+
+// HealthConfig defines the sources whose freshness the health check gates on.
+type HealthConfig struct {
+	// CriticalExchanges fail the health check if their last trade is older than MaxExchangeLag.
+	CriticalExchanges []string
+	// CriticalFilters fail the health check if their last value is older than MaxFilterLag.
+	CriticalFilters []string
+	MaxExchangeLag  time.Duration
+	MaxFilterLag    time.Duration
+}
+
+// Health is the freshness of the data served by the API.
+type Health struct {
+	Status    string
+	Time      time.Time
+	Exchanges []SourceLag
+	Filters   []SourceLag
+}
+
+// SourceLag is the time since the latest data of an exchange or a filter. LastTime and
+// LagSeconds are missing if a source had no data within the last 7 days.
+type SourceLag struct {
+	Name       string
+	LastTime   *time.Time `json:",omitempty"`
+	LagSeconds *float64   `json:",omitempty"`
+	Critical   bool
+	Stale      bool
+}
+
+// Health returns a handler reporting the ingestion lag of all exchanges, that is the time since
+// their last trade, and the computation lag of all filters. It responds with 503 if a critical
+// source is stale or the lags can't be determined, so that downstream systems can gate on it.
+func (env *Env) Health(config HealthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		exchanges, err := env.DataStore.GetExchangeStatus()
+		if err != nil {
+			restApi.SendError(c, http.StatusServiceUnavailable, err)
+			return
+		}
+		filters, err := env.DataStore.GetFilterStatus()
+		if err != nil {
+			restApi.SendError(c, http.StatusServiceUnavailable, err)
+			return
+		}
+		health := checkHealth(config, exchanges, filters, time.Now())
+		if health.Status == HealthUnavailable {
+			c.JSON(http.StatusServiceUnavailable, health)
+			return
+		}
+		c.JSON(http.StatusOK, health)
+	}
+}
+
+// checkHealth computes the lags of @exchanges and @filters at @now. Exchanges without trades
+// within the last 7 days are only listed if they are critical.
+func checkHealth(config HealthConfig, exchanges []models.ExchangeStatus, filters []models.FilterStatus, now time.Time) Health {
+	health := Health{Status: HealthOK, Time: now, Exchanges: []SourceLag{}, Filters: []SourceLag{}}
+	add := func(lags *[]SourceLag, name string, lastTime *time.Time, critical bool, maxLag time.Duration) {
+		lag := SourceLag{Name: name, LastTime: lastTime, Critical: critical, Stale: true}
+		if lastTime != nil {
+			seconds := now.Sub(*lastTime).Seconds()
+			lag.LagSeconds = &seconds
+			lag.Stale = now.Sub(*lastTime) > maxLag
+		}
+		if lag.Stale {
+			if critical {
+				health.Status = HealthUnavailable
+			} else if health.Status == HealthOK {
+				health.Status = HealthDegraded
+			}
+		}
+		*lags = append(*lags, lag)
+	}
+
+	seen := make(map[string]bool)
+	for _, exchange := range exchanges {
+		critical := containsFold(config.CriticalExchanges, exchange.Name)
+		if exchange.LastTradeTime == nil && !critical {
+			continue
+		}
+		seen[strings.ToLower(exchange.Name)] = true
+		add(&health.Exchanges, exchange.Name, exchange.LastTradeTime, critical, config.MaxExchangeLag)
+	}
+	for _, name := range config.CriticalExchanges {
+		if !seen[strings.ToLower(name)] {
+			add(&health.Exchanges, name, nil, true, config.MaxExchangeLag)
+		}
+	}
+
+	seen = make(map[string]bool)
+	for _, filter := range filters {
+		lastTime := filter.LastTime
+		seen[strings.ToLower(filter.Name)] = true
+		add(&health.Filters, filter.Name, &lastTime, containsFold(config.CriticalFilters, filter.Name), config.MaxFilterLag)
+	}
+	for _, name := range config.CriticalFilters {
+		if !seen[strings.ToLower(name)] {
+			add(&health.Filters, name, nil, true, config.MaxFilterLag)
+		}
+	}
+	return health
+}
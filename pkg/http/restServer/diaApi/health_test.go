@@ -0,0 +1,45 @@
+package diaApi
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func TestCheckHealth(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC)
+	fresh, old := now.Add(-time.Minute), now.Add(-time.Hour)
+	config := HealthConfig{
+		CriticalExchanges: []string{"binance"},
+		CriticalFilters:   []string{"MAIR120"},
+		MaxExchangeLag:    15 * time.Minute,
+		MaxFilterLag:      10 * time.Minute,
+	}
+	exchanges := []models.ExchangeStatus{
+		{Name: "Binance", LastTradeTime: &fresh},
+		{Name: "Kraken", LastTradeTime: &old},
+		{Name: "Unused"},
+	}
+	filters := []models.FilterStatus{{Name: "MAIR120", LastTime: fresh}}
+
+	health := checkHealth(config, exchanges, filters, now)
+	if health.Status != HealthDegraded {
+		t.Errorf("expected degraded with a stale non-critical exchange, got %s", health.Status)
+	}
+	if len(health.Exchanges) != 2 || !health.Exchanges[0].Critical || !health.Exchanges[1].Stale {
+		t.Errorf("unexpected exchanges %+v", health.Exchanges)
+	}
+	if lag := health.Exchanges[1].LagSeconds; lag == nil || *lag != 3600 {
+		t.Errorf("expected lag of an hour, got %v", lag)
+	}
+
+	filters[0].LastTime = old
+	if health := checkHealth(config, exchanges, filters, now); health.Status != HealthUnavailable {
+		t.Errorf("expected unavailable with a stale critical filter, got %s", health.Status)
+	}
+	health = checkHealth(config, exchanges[1:], nil, now)
+	if health.Status != HealthUnavailable || len(health.Filters) != 1 || health.Filters[0].LastTime != nil {
+		t.Errorf("expected missing critical sources to be stale, got %+v", health)
+	}
+}
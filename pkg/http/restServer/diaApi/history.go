@@ -0,0 +1,166 @@
+package diaApi
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultRangeLimit and maxRangeLimit bound the number of prices of a page of
+	// GetQuotationRange.
+	defaultRangeLimit = 500
+	maxRangeLimit     = 2000
+	defaultResolution = "1h"
+)
+
+// GetQuotationRange godoc
+// @Summary Get historical quotations
+// @Description GetQuotationRange returns the prices of an asset between two timestamps at a
+// @Description fixed resolution, oldest first. Long ranges are split into pages, the next of
+// @Description which is requested by passing NextCursor of the response as cursor.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   resolution query   string     false       "5m 30m 1h 4h 1d 1w, default 1h"
+// @Param   starttime  query   int        false       "Unix timestamp, default 7 days ago"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Param   limit      query   int        false       "Prices per page, default 500, at most 2000"
+// @Param   cursor     query   string     false       "NextCursor of the previous page"
+// @Success 200 {object} models.QuotationRange "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/quotationRange/:symbol [get]
+func (env *Env) GetQuotationRange(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	resolution := c.DefaultQuery("resolution", defaultResolution)
+	if !isResolution(resolution) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("resolution must be one of %s", strings.Join(models.QuotationResolutions, ", ")))
+		return
+	}
+
+	now := time.Now()
+	starttime, err := unixQuery(c, "starttime", now.AddDate(0, 0, -7))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	endtime, err := unixQuery(c, "endtime", now)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		starttime, err = decodeCursor(cursor)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if !starttime.Before(endtime) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("starttime must be before endtime"))
+		return
+	}
+
+	limit := defaultRangeLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxRangeLimit {
+			restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxRangeLimit))
+			return
+		}
+	}
+
+	// One price more than requested tells whether there is a next page.
+	prices, err := env.DataStore.GetQuotationRange(symbol, resolution, starttime, endtime, limit+1)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	result := models.QuotationRange{Symbol: symbol, Resolution: resolution, Prices: prices}
+	if len(prices) > limit {
+		result.Prices = prices[:limit]
+		result.NextCursor = encodeCursor(prices[limit].Time)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func isResolution(resolution string) bool {
+	for _, r := range models.QuotationResolutions {
+		if r == resolution {
+			return true
+		}
+	}
+	return false
+}
+
+// unixQuery returns the time of the unix timestamp in the query parameter @key, or @fallback if
+// it isn't set.
+func unixQuery(c *gin.Context, key string, fallback time.Time) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a unix timestamp", key)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// rangeQuery parses the starttime and endtime query parameters of a range of at most @maxRange.
+// The range ends now and spans @defaultRange by default. It sends an error response if the
+// parameters are invalid.
+func rangeQuery(c *gin.Context, defaultRange time.Duration, maxRange time.Duration) (starttime time.Time, endtime time.Time, ok bool) {
+	endtime, err := unixQuery(c, "endtime", time.Now())
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	starttime, err = unixQuery(c, "starttime", endtime.Add(-defaultRange))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !starttime.Before(endtime) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("starttime must be before endtime"))
+		return
+	}
+	if endtime.Sub(starttime) > maxRange {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("range exceeds %v", maxRange))
+		return
+	}
+	return starttime, endtime, true
+}
+
+// encodeCursor returns an opaque cursor of the page starting at @t. Clients shouldn't rely on
+// its format.
+func encodeCursor(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(t.UnixNano(), 10)))
+}
+
+func decodeCursor(cursor string) (time.Time, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, errors.New("invalid cursor")
+	}
+	nanos, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return time.Time{}, errors.New("invalid cursor")
+	}
+	return time.Unix(0, nanos), nil
+}
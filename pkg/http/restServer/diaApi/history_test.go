@@ -0,0 +1,22 @@
+package diaApi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	start := time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC)
+	decoded, err := decodeCursor(encodeCursor(start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(start) {
+		t.Errorf("expected %s, got %s", start, decoded)
+	}
+	for _, cursor := range []string{"not base64!", "bm90IGEgbnVtYmVy"} {
+		if _, err := decodeCursor(cursor); err == nil {
+			t.Errorf("expected error for cursor %s", cursor)
+		}
+	}
+}
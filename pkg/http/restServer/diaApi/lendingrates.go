@@ -0,0 +1,110 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// lendingRateMaxAge is the age after which a lending rate is no longer current, e.g. because
+	// the asset was delisted from the market.
+	lendingRateMaxAge = time.Hour
+	// maxLendingRateRange bounds the range of historical lending rates of a request.
+	maxLendingRateRange = 31 * 24 * time.Hour
+)
+
+// GetLendingRates godoc
+// @Summary Get supply and borrow rates of lending markets
+// @Description GetLendingRates returns the current supply APY and borrow APY in percent and the
+// @Description utilization of each asset in the lending market of a protocol on a blockchain, e.g.
+// @Description AAVEv3, SPARK or COMPOUNDv3 on Arbitrum. With an asset, it returns the recorded
+// @Description rates of the asset from starttime until endtime instead, oldest first. The range is
+// @Description limited to 31 days.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   protocol    path    string     true        "Lending protocol, e.g. AAVEv3"
+// @Param   blockchain  path    string     true        "Blockchain, e.g. Ethereum"
+// @Param   asset       path    string     false       "Symbol of the asset, e.g. USDC"
+// @Param   starttime   query   int        false       "Unix timestamp, default 24 hours before endtime"
+// @Param   endtime     query   int        false       "Unix timestamp, default now"
+// @Success 200 {array} dia.DefiRate "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No rates of the market"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/lendingRates/:protocol/:blockchain/:asset [get]
+func (env *Env) GetLendingRates(c *gin.Context) {
+	protocol := c.Param("protocol")
+	blockchain := c.Param("blockchain")
+	asset := c.Param("asset")
+	for _, param := range []string{protocol, blockchain, asset} {
+		if !utils.QuerySafe(param) {
+			restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid parameter %q", param))
+			return
+		}
+	}
+
+	if asset == "" {
+		rates, err := env.DataStore.GetLatestLendingRates(protocol, blockchain, time.Now().Add(-lendingRateMaxAge))
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if len(rates) == 0 {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no current rates of %s on %s", protocol, blockchain))
+			return
+		}
+		c.JSON(http.StatusOK, rates)
+		return
+	}
+
+	starttime, endtime, ok := rangeQuery(c, 24*time.Hour, maxLendingRateRange)
+	if !ok {
+		return
+	}
+	rates, err := env.DataStore.GetLendingRates(protocol, blockchain, asset, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+// GetLendingCollateral godoc
+// @Summary Get collateral factors of lending markets
+// @Description GetLendingCollateral returns the current collateral factors and supply caps of the
+// @Description collateral assets of the lending markets of a protocol on a blockchain, e.g.
+// @Description COMPOUNDv3 on Base.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   protocol    path    string     true        "Lending protocol, e.g. COMPOUNDv3"
+// @Param   blockchain  path    string     true        "Blockchain, e.g. Ethereum"
+// @Success 200 {array} dia.LendingCollateral "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No collateral of the markets"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/lendingCollateral/:protocol/:blockchain [get]
+func (env *Env) GetLendingCollateral(c *gin.Context) {
+	protocol := c.Param("protocol")
+	blockchain := c.Param("blockchain")
+	if !utils.QuerySafe(protocol, blockchain) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid market %q on %q", protocol, blockchain))
+		return
+	}
+	collaterals, err := env.DataStore.GetLatestLendingCollateral(protocol, blockchain, time.Now().Add(-lendingRateMaxAge))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(collaterals) == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no current collateral of %s on %s", protocol, blockchain))
+		return
+	}
+	c.JSON(http.StatusOK, collaterals)
+}
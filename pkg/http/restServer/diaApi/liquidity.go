@@ -0,0 +1,98 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultPoolLiquidityRange is the range of the history of a pool without starttime.
+	defaultPoolLiquidityRange = 24 * time.Hour
+	// maxPoolLiquidityRange bounds the range of the history of a pool of a request.
+	maxPoolLiquidityRange = 31 * 24 * time.Hour
+)
+
+// GetPoolLiquidity godoc
+// @Summary Get liquidity of DEX pools
+// @Description GetPoolLiquidity returns the latest reserves and TVL in USD of the liquidity pools
+// @Description of DEXes, largest TVL first. The list is paginated.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   exchanges  query   string     false       "Comma separated exchanges, default all"
+// @Param   symbol     query   string     false       "Only pools holding this symbol"
+// @Param   limit      query   int        false       "Pools per page, default 100"
+// @Param   cursor     query   string     false       "Cursor of the page"
+// @Success 200 {array} dia.PoolLiquidity "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No current pool liquidity"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/poolLiquidity [get]
+func (env *Env) GetPoolLiquidity(c *gin.Context) {
+	exchanges, err := exchangesQuery(c, "exchanges")
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	symbol := strings.ToUpper(c.Query("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+
+	liquidities, err := env.DataStore.GetLatestPoolLiquidity(exchanges, symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(liquidities) == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no current pool liquidity"))
+		return
+	}
+	page, ok := paginate(c, liquidities, defaultPageLimit)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// GetPoolLiquidityHistory godoc
+// @Summary Get liquidity history of a DEX pool
+// @Description GetPoolLiquidityHistory returns the snapshots of the reserves and TVL in USD of a
+// @Description liquidity pool from starttime until endtime, oldest first. The range defaults to
+// @Description the last 24 hours and is limited to 31 days.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   exchange   path    string     true        "Exchange"
+// @Param   pool       path    string     true        "Address of the pool"
+// @Param   starttime  query   int        false       "Unix timestamp, default a day before endtime"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {array} dia.PoolLiquidity "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/poolLiquidity/:exchange/:pool [get]
+func (env *Env) GetPoolLiquidityHistory(c *gin.Context) {
+	exchange := c.Param("exchange")
+	pool := c.Param("pool")
+	if !utils.QuerySafe(exchange, pool) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid pool %q of %q", pool, exchange))
+		return
+	}
+	starttime, endtime, ok := rangeQuery(c, defaultPoolLiquidityRange, maxPoolLiquidityRange)
+	if !ok {
+		return
+	}
+	liquidities, err := env.DataStore.GetPoolLiquidity(exchange, pool, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, liquidities)
+}
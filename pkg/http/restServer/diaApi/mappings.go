@@ -0,0 +1,161 @@
+package diaApi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// symbolMapping is the mapping of a symbol along with the assets matching it, from which a
+// curator picks one for ambiguous symbols.
+type symbolMapping struct {
+	models.ExchangeSymbolMapping
+	Candidates []dia.Asset `json:",omitempty"`
+}
+
+// validMappingStatus returns an error if @status isn't empty or one of the mapping statuses.
+func validMappingStatus(status string) error {
+	switch status {
+	case "", models.MappingVerified, models.MappingAmbiguous, models.MappingUnverified:
+		return nil
+	}
+	return fmt.Errorf("status must be %s, %s or %s", models.MappingVerified, models.MappingAmbiguous, models.MappingUnverified)
+}
+
+// GetSymbolMappings returns the mappings of the symbols of an exchange to assets, optionally
+// filtered by the query parameter status. Symbols which aren't verified come with the assets
+// matching them.
+func (env *Env) GetSymbolMappings(c *gin.Context) {
+	status := c.Query("status")
+	if err := validMappingStatus(status); err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	mappings, err := env.RelDB.GetExchangeSymbolMappings(c.Param("exchange"), status)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	response := []symbolMapping{}
+	for _, mapping := range mappings {
+		m := symbolMapping{ExchangeSymbolMapping: mapping}
+		if mapping.Status != models.MappingVerified {
+			m.Candidates, err = env.RelDB.GetAssetsBySymbol(mapping.Symbol)
+			if err != nil {
+				restApi.SendError(c, http.StatusInternalServerError, err)
+				return
+			}
+		}
+		response = append(response, m)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPairMappings returns the mappings of the pairs of an exchange to assets, optionally
+// filtered by the query parameter status.
+func (env *Env) GetPairMappings(c *gin.Context) {
+	status := c.Query("status")
+	if err := validMappingStatus(status); err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	mappings, err := env.RelDB.GetExchangePairMappings(c.Param("exchange"), status)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if mappings == nil {
+		mappings = []models.ExchangePairMapping{}
+	}
+	c.JSON(http.StatusOK, mappings)
+}
+
+// PostSymbolMapping maps a symbol of an exchange to the asset given by Blockchain and Address
+// in the body. The mapping is curated, i.e. kept by the pair discovery, and applies to the
+// pairs of the symbol right away. Scrapers pick it up within DefaultRefresh.
+func (env *Env) PostSymbolMapping(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var request struct {
+		Symbol     string
+		Blockchain string
+		Address    string
+	}
+	err = json.Unmarshal(body, &request)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if request.Symbol == "" || request.Blockchain == "" || request.Address == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing Symbol, Blockchain or Address"))
+		return
+	}
+
+	exchange := c.Param("exchange")
+	mapping := models.ExchangeSymbolMapping{
+		Exchange: exchange,
+		Symbol:   request.Symbol,
+		Status:   models.MappingVerified,
+		Asset:    dia.Asset{Blockchain: request.Blockchain, Address: request.Address},
+		Curated:  true,
+	}
+	err = env.RelDB.SetExchangeSymbolMapping(mapping)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			restApi.SendError(c, http.StatusNotFound, errors.New("unknown asset"))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	env.sendSymbolMapping(c, exchange, request.Symbol)
+}
+
+// DeleteSymbolMapping removes the curated mapping of a symbol of an exchange. The symbol is
+// unverified until the next run of the pair discovery maps it again.
+func (env *Env) DeleteSymbolMapping(c *gin.Context) {
+	exchange := c.Param("exchange")
+	mapping := models.ExchangeSymbolMapping{
+		Exchange: exchange,
+		Symbol:   c.Param("symbol"),
+		Status:   models.MappingUnverified,
+	}
+	err := env.RelDB.SetExchangeSymbolMapping(mapping)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	env.sendSymbolMapping(c, exchange, mapping.Symbol)
+}
+
+// sendSymbolMapping applies the mapping of @symbol to the pairs of @exchange and responds with it.
+func (env *Env) sendSymbolMapping(c *gin.Context, exchange string, symbol string) {
+	err := env.RelDB.UpdateExchangePairMappings(exchange)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	mappings, err := env.RelDB.GetExchangeSymbolMappings(exchange, "")
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for _, mapping := range mappings {
+		if mapping.Symbol == symbol {
+			c.JSON(http.StatusOK, mapping)
+			return
+		}
+	}
+	restApi.SendError(c, http.StatusNotFound, errors.New("unknown symbol"))
+}
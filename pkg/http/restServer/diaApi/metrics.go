@@ -0,0 +1,29 @@
+package diaApi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	apiRequests        = metrics.NewCounter("api", "requests", "Requests to the API by route and status code.", "route", "status")
+	apiRequestDuration = metrics.NewCounter("api", "request_duration_seconds", "Total time spent serving requests by route.", "route")
+)
+
+// Metrics returns a middleware counting requests and their duration per route. Routes are
+// the registered patterns, e.g. /v1/quotation/:symbol, so that the number of series is bounded.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		apiRequests.Inc(route, strconv.Itoa(c.Writer.Status()))
+		apiRequestDuration.Add(time.Since(start).Seconds(), route)
+	}
+}
@@ -0,0 +1,139 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	defaultNFTFloorWindow     = "1d"
+	defaultNFTFloorPercentile = 10
+	maxNFTFloorPercentile     = 50
+	defaultNFTFloorMADays     = 7
+	maxNFTFloorMADays         = 90
+)
+
+// GetNFTFloor godoc
+// @Summary Get the floor price of an NFT collection
+// @Description GetNFTFloor returns the floor price of an NFT collection in USD as a percentile of
+// @Description its sale prices over a window until endtime, so that single outlier sales don't
+// @Description set the floor. It also returns the moving average of the daily floors over madays days.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   blockchain  path    string     true        "Blockchain, e.g. Ethereum"
+// @Param   address     path    string     true        "Address of the collection"
+// @Param   window      query   string     false       "Window, e.g. 12h, 1d or 7d, default 1d, at most 365d"
+// @Param   percentile  query   int        false       "Percentile of the sale prices, 1 to 50, default 10"
+// @Param   madays      query   int        false       "Days of the moving average, 1 to 90, default 7"
+// @Param   endtime     query   int        false       "Unix timestamp, default now"
+// @Success 200 {object} models.NFTFloor "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "Unknown collection or no sales in the window"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/nft/floor/:blockchain/:address [get]
+func (env *Env) GetNFTFloor(c *gin.Context) {
+	blockchain := c.Param("blockchain")
+	address := common.HexToAddress(c.Param("address")).Hex()
+
+	starttime, endtime, ok := nftWindowQuery(c, defaultNFTFloorWindow)
+	if !ok {
+		return
+	}
+	percentile, err := intQuery(c, "percentile", defaultNFTFloorPercentile, 1, maxNFTFloorPercentile)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	maDays, err := intQuery(c, "madays", defaultNFTFloorMADays, 1, maxNFTFloorMADays)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	nftclassID, ok := env.nftClassID(c, blockchain, address)
+	if !ok {
+		return
+	}
+	floor := models.NFTFloor{
+		Blockchain:        blockchain,
+		Address:           address,
+		Percentile:        float64(percentile) / 100,
+		StartTime:         starttime,
+		EndTime:           endtime,
+		MovingAverageDays: maDays,
+	}
+	floor.Floor, floor.Sales, err = env.RelDB.GetNFTFloor(nftclassID, floor.Percentile, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if floor.Sales == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no sales of %s in the window", address))
+		return
+	}
+	floor.MovingAverageFloor, err = env.RelDB.GetNFTMovingAverageFloor(nftclassID, floor.Percentile, maDays, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, floor)
+}
+
+// intQuery returns the integer query parameter @key, which must be in [@min, @max], or
+// @fallback if it is not set.
+func intQuery(c *gin.Context, key string, fallback int, min int, max int) (int, error) {
+	value, ok := c.GetQuery(key)
+	if !ok {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s must be an integer from %d to %d", key, min, max)
+	}
+	return n, nil
+}
+
+// nftWindowQuery parses the window and endtime query parameters of NFT trade statistics, and sends
+// an error response if they are invalid.
+func nftWindowQuery(c *gin.Context, defaultWindow string) (starttime time.Time, endtime time.Time, ok bool) {
+	window, err := parseWindow(c.DefaultQuery("window", defaultWindow))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if window > maxWindow {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("window exceeds 365d"))
+		return
+	}
+	endtime, err = unixQuery(c, "endtime", time.Now())
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	return endtime.Add(-window), endtime, true
+}
+
+// nftClassID returns the ID of the NFT class at @address on @blockchain, and sends an error
+// response if there is none.
+func (env *Env) nftClassID(c *gin.Context, blockchain string, address string) (string, bool) {
+	nftclassID, err := env.RelDB.GetNFTClassID(address, blockchain)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no NFT collection %s on %s", address, blockchain))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return "", false
+	}
+	return nftclassID, true
+}
@@ -0,0 +1,50 @@
+package diaApi
+
+import (
+	"net/http"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultNFTVolumeWindow = "1d"
+
+// GetNFTVolume godoc
+// @Summary Get the trading volume of an NFT collection
+// @Description GetNFTVolume returns the USD volume, the number of sales and the numbers of distinct
+// @Description buyers and sellers of an NFT collection over a window until endtime, in total and
+// @Description broken down by marketplace.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   blockchain  path    string     true        "Blockchain, e.g. Ethereum"
+// @Param   address     path    string     true        "Address of the collection"
+// @Param   window      query   string     false       "Window, e.g. 12h, 1d or 7d, default 1d, at most 365d"
+// @Param   endtime     query   int        false       "Unix timestamp, default now"
+// @Success 200 {object} models.NFTVolume "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "Unknown collection"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/nft/volume/:blockchain/:address [get]
+func (env *Env) GetNFTVolume(c *gin.Context) {
+	blockchain := c.Param("blockchain")
+	address := common.HexToAddress(c.Param("address")).Hex()
+
+	starttime, endtime, ok := nftWindowQuery(c, defaultNFTVolumeWindow)
+	if !ok {
+		return
+	}
+	nftclassID, ok := env.nftClassID(c, blockchain, address)
+	if !ok {
+		return
+	}
+	volume, err := env.RelDB.GetNFTVolume(nftclassID, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	volume.Blockchain = blockchain
+	volume.Address = address
+	c.JSON(http.StatusOK, volume)
+}
@@ -0,0 +1,71 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxOpenInterestRange bounds the range of historical open interest of a request.
+const maxOpenInterestRange = 31 * 24 * time.Hour
+
+// GetOpenInterest godoc
+// @Summary Get open interest of futures
+// @Description GetOpenInterest returns the current open interest of each perpetual and dated
+// @Description futures instrument of an asset on each derivatives exchange, in units of the
+// @Description asset and in USD. With starttime, it returns the recorded open interest from
+// @Description starttime until endtime instead, oldest first. The range is limited to 31 days.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   exchanges  query   string     false       "Comma separated exchanges, default all"
+// @Param   starttime  query   int        false       "Unix timestamp"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {array} dia.OpenInterest "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No current open interest"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/openinterest/:symbol [get]
+func (env *Env) GetOpenInterest(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	exchanges, err := exchangesQuery(c, "exchanges")
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if c.Query("starttime") == "" {
+		openInterests, err := env.DataStore.GetLatestOpenInterest(symbol, exchanges)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if len(openInterests) == 0 {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no current open interest of %s", symbol))
+			return
+		}
+		c.JSON(http.StatusOK, openInterests)
+		return
+	}
+
+	starttime, endtime, ok := rangeQuery(c, maxOpenInterestRange, maxOpenInterestRange)
+	if !ok {
+		return
+	}
+	openInterests, err := env.DataStore.GetOpenInterest(symbol, exchanges, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, openInterests)
+}
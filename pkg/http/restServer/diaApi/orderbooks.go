@@ -0,0 +1,85 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+)
+
+// GetOrderBookDepth godoc
+// @Summary Get the order book depth of an asset
+// @Description GetOrderBookDepth returns the amounts of an asset bid and asked within 2% of the
+// @Description mid price on each exchange pair with a recent order book snapshot, and their
+// @Description total USD value. USD values are zero if the asset has no quotation.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   exchanges  query   string     false       "Comma separated exchanges, default all"
+// @Success 200 {object} models.OrderBookDepths "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No order book of the asset"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/orderbookDepth/:symbol [get]
+func (env *Env) GetOrderBookDepth(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	exchanges, err := exchangesQuery(c, "exchanges")
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	depths, err := env.DataStore.GetOrderBookDepths(symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	var price float64
+	q, err := env.DataStore.GetQuotation(symbol)
+	if err == nil {
+		price = q.Price
+	} else if err != redis.Nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := models.OrderBookDepths{Symbol: symbol, Band: dia.OrderBookDepthBand, Exchanges: []models.ExchangeDepth{}}
+	for _, depth := range depths {
+		if len(exchanges) > 0 && !containsFold(exchanges, depth.Exchange) {
+			continue
+		}
+		exchangeDepth := models.ExchangeDepth{
+			OrderBookDepth: depth,
+			BidDepthUSD:    depth.BidDepth * price,
+			AskDepthUSD:    depth.AskDepth * price,
+		}
+		result.BidDepthUSD += exchangeDepth.BidDepthUSD
+		result.AskDepthUSD += exchangeDepth.AskDepthUSD
+		result.Exchanges = append(result.Exchanges, exchangeDepth)
+	}
+	if len(result.Exchanges) == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no recent order book of %s", symbol))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
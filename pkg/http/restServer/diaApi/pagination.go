@@ -0,0 +1,259 @@
+package diaApi
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultPageLimit is the number of items of a page of list endpoints without limit.
+	defaultPageLimit = 100
+	// maxPageLimit bounds the number of items of a page of all list endpoints.
+	maxPageLimit = 1000
+	// NextCursorHeader holds the cursor of the next page of list endpoints. It is missing on
+	// the last page.
+	NextCursorHeader = "X-Next-Cursor"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// listQuery is the query convention shared by list endpoints:
+//
+//	limit=N              items per page
+//	cursor=C             the cursor of the page, returned in X-Next-Cursor by the previous one
+//	sort=Field           sort by a field of the items, or by -Field for descending order
+//	filter[Field]=value  only items whose field equals value, strings compared case-insensitively
+type listQuery struct {
+	limit      int
+	offset     int
+	sort       string
+	descending bool
+	filters    map[string]string
+}
+
+// parseListQuery parses the list query parameters with a page size of @defaultLimit.
+func parseListQuery(c *gin.Context, defaultLimit int) (q listQuery, err error) {
+	q.limit, err = intQuery(c, "limit", defaultLimit, 1, maxPageLimit)
+	if err != nil {
+		return
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		q.offset, err = decodeOffsetCursor(cursor)
+		if err != nil {
+			return
+		}
+	}
+	q.sort = c.Query("sort")
+	if strings.HasPrefix(q.sort, "-") {
+		q.sort, q.descending = q.sort[1:], true
+	}
+	q.filters = c.QueryMap("filter")
+	return
+}
+
+// paginate filters and sorts the slice @items as requested by the list query parameters and
+// returns the requested page of it. The cursor of the next page is set in X-Next-Cursor along
+// with a Link header. It sends an error response if the parameters are invalid.
+func paginate(c *gin.Context, items interface{}, defaultLimit int) (page interface{}, ok bool) {
+	q, err := parseListQuery(c, defaultLimit)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return nil, false
+	}
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("paginate: items are not a slice"))
+		return nil, false
+	}
+
+	result, err := filterItems(v, q.filters)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if q.sort != "" {
+		err = sortItems(result, q.sort, q.descending)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return nil, false
+		}
+	}
+
+	start, end := q.offset, q.offset+q.limit
+	if start > result.Len() {
+		start = result.Len()
+	}
+	if end < result.Len() {
+		setNextCursor(c, encodeOffsetCursor(end))
+	} else {
+		end = result.Len()
+	}
+	return result.Slice(start, end).Interface(), true
+}
+
+// setNextCursor sets the headers pointing to the next page of a list endpoint at @cursor.
+func setNextCursor(c *gin.Context, cursor string) {
+	c.Header(NextCursorHeader, cursor)
+	query := c.Request.URL.Query()
+	query.Set("cursor", cursor)
+	c.Header("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, c.Request.URL.Path, query.Encode()))
+}
+
+// filterItems returns a copy of the slice @v with the items matching all @filters.
+func filterItems(v reflect.Value, filters map[string]string) (reflect.Value, error) {
+	result := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		match := true
+		for field, value := range filters {
+			f, err := itemField(v.Index(i), field)
+			if err != nil {
+				return result, err
+			}
+			match, err = fieldEquals(f, value)
+			if err != nil {
+				return result, fmt.Errorf("filter %s: %v", field, err)
+			}
+			if !match {
+				break
+			}
+		}
+		if match {
+			result = reflect.Append(result, v.Index(i))
+		}
+	}
+	return result, nil
+}
+
+// sortItems sorts the slice @v by @field. The sort is stable, so items with equal fields keep
+// the order of the endpoint.
+func sortItems(v reflect.Value, field string, descending bool) error {
+	if v.Len() == 0 {
+		return nil
+	}
+	f, err := itemField(v.Index(0), field)
+	if err != nil {
+		return err
+	}
+	if _, err = compareFields(f, f); err != nil {
+		return fmt.Errorf("sort %s: %v", field, err)
+	}
+	swap := reflect.Swapper(v.Interface())
+	less := func(i, j int) bool {
+		a, _ := itemField(v.Index(i), field)
+		b, _ := itemField(v.Index(j), field)
+		cmp, _ := compareFields(a, b)
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+	sort.Stable(reflectSorter{n: v.Len(), less: less, swap: swap})
+	return nil
+}
+
+type reflectSorter struct {
+	n    int
+	less func(i, j int) bool
+	swap func(i, j int)
+}
+
+func (s reflectSorter) Len() int           { return s.n }
+func (s reflectSorter) Less(i, j int) bool { return s.less(i, j) }
+func (s reflectSorter) Swap(i, j int)      { s.swap(i, j) }
+
+// itemField returns the exported top level field @name of the struct @item, or of the struct it
+// points to. Names are matched case-insensitively.
+func itemField(item reflect.Value, name string) (reflect.Value, error) {
+	for item.Kind() == reflect.Ptr || item.Kind() == reflect.Interface {
+		if item.IsNil() {
+			return reflect.Value{}, fmt.Errorf("unknown field %q", name)
+		}
+		item = item.Elem()
+	}
+	if item.Kind() == reflect.Struct {
+		f := item.FieldByNameFunc(func(field string) bool { return strings.EqualFold(field, name) })
+		if f.IsValid() && f.CanInterface() {
+			return f, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown field %q", name)
+}
+
+// fieldEquals returns true if the string, number or boolean field @f equals @value.
+func fieldEquals(f reflect.Value, value string) (bool, error) {
+	switch f.Kind() {
+	case reflect.String:
+		return strings.EqualFold(f.String(), value), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return n == f.Int(), err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		return n == f.Uint(), err
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		return n == f.Float(), err
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		return b == f.Bool(), err
+	}
+	return false, errors.New("field can't be filtered")
+}
+
+// compareFields returns -1, 0 or 1 if @a is less than, equal to or greater than @b, which are
+// fields of the same type.
+func compareFields(a reflect.Value, b reflect.Value) (int, error) {
+	switch {
+	case a.Type() == timeType:
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		return compareOrdered(ta.Before(tb), ta.After(tb)), nil
+	case a.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), nil
+	case a.Kind() >= reflect.Int && a.Kind() <= reflect.Int64:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int()), nil
+	case a.Kind() >= reflect.Uint && a.Kind() <= reflect.Uint64:
+		return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint()), nil
+	case a.Kind() == reflect.Float32 || a.Kind() == reflect.Float64:
+		return compareOrdered(a.Float() < b.Float(), a.Float() > b.Float()), nil
+	case a.Kind() == reflect.Bool:
+		return compareOrdered(!a.Bool() && b.Bool(), a.Bool() && !b.Bool()), nil
+	}
+	return 0, errors.New("field can't be sorted")
+}
+
+func compareOrdered(less bool, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// encodeOffsetCursor returns an opaque cursor of the page starting at @offset.
+func encodeOffsetCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o" + strconv.Itoa(offset)))
+}
+
+func decodeOffsetCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(data) < 2 || data[0] != 'o' {
+		return 0, errors.New("invalid cursor")
+	}
+	offset, err := strconv.Atoi(string(data[1:]))
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return offset, nil
+}
@@ -0,0 +1,62 @@
+package diaApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type paginationItem struct {
+	Exchange string
+	Price    float64
+	Time     time.Time
+}
+
+func TestPaginate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	start := time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC)
+	items := []paginationItem{
+		{"Binance", 3, start},
+		{"Kraken", 1, start.Add(time.Minute)},
+		{"Binance", 2, start.Add(2 * time.Minute)},
+		{"Binance", 4, start.Add(3 * time.Minute)},
+	}
+
+	query := func(rawQuery string) (page []paginationItem, w *httptest.ResponseRecorder) {
+		w = httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/v1/items?"+rawQuery, nil)
+		result, ok := paginate(c, items, 2)
+		if ok {
+			page = result.([]paginationItem)
+		}
+		return
+	}
+
+	page, w := query("filter[exchange]=binance&sort=-price")
+	if !reflect.DeepEqual(page, []paginationItem{items[3], items[0]}) {
+		t.Errorf("unexpected first page %v", page)
+	}
+	cursor := w.Header().Get(NextCursorHeader)
+	if cursor == "" {
+		t.Fatal("expected a next cursor")
+	}
+	page, w = query("filter[exchange]=binance&sort=-price&cursor=" + cursor)
+	if !reflect.DeepEqual(page, []paginationItem{items[2]}) || w.Header().Get(NextCursorHeader) != "" {
+		t.Errorf("unexpected last page %v", page)
+	}
+	page, _ = query("sort=time&limit=10")
+	if !reflect.DeepEqual(page, items) {
+		t.Errorf("unexpected page sorted by time %v", page)
+	}
+
+	for _, rawQuery := range []string{"limit=0", "limit=1001", "cursor=invalid", "sort=unknown", "filter[Time]=1", "filter[Price]=abc"} {
+		if _, w := query(rawQuery); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", rawQuery, w.Code)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package diaApi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// IdentityKey is the jwt claim holding the username of an authenticated admin user.
+	IdentityKey = "id"
+	// adminUserKey is the context key under which the resolved admin user is stored.
+	adminUserKey = "adminUser"
+	// maxAuditBodySize limits the size of request bodies stored in the audit trail.
+	maxAuditBodySize = 1 << 16
+	// redactedValue replaces the values of secret fields in the audit trail.
+	redactedValue = "[redacted]"
+)
+
+// secretAuditFields are the fields of json request bodies which aren't stored in the audit
+// trail, compared case insensitively.
+var secretAuditFields = []string{"password", "secret", "secretkey", "apikey", "token"}
+
+// AdminUserForIdentity resolves the username of an authenticated request into an admin user.
+// The api key from the server's config is the bootstrap admin and doesn't need a postgres entry.
+func (env *Env) AdminUserForIdentity(username string) (models.AdminUser, error) {
+	if env.BootstrapAdmin != "" && username == env.BootstrapAdmin {
+		return models.AdminUser{Username: username, Role: models.RoleAdmin}, nil
+	}
+	return env.RelDB.GetAdminUser(username)
+}
+
+// RequireRole returns a middleware that aborts all requests by users which don't have at least @role.
+// It must be used after the jwt middleware.
+func (env *Env) RequireRole(role models.AdminRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username, _ := jwt.ExtractClaims(c)[IdentityKey].(string)
+		if username == "" {
+			restApi.SendError(c, http.StatusUnauthorized, errors.New("missing identity"))
+			c.Abort()
+			return
+		}
+		user, err := env.AdminUserForIdentity(username)
+		if err != nil {
+			log.Warnf("RequireRole: unknown admin user %s: %v", username, err)
+			restApi.SendError(c, http.StatusForbidden, errors.New("unknown admin user"))
+			c.Abort()
+			return
+		}
+		if !user.Role.Includes(role) {
+			log.Warnf("RequireRole: user %s with role %s rejected for %s %s", user.Username, user.Role, c.Request.Method, c.Request.URL.Path)
+			restApi.SendError(c, http.StatusForbidden, errors.New("insufficient permissions"))
+			c.Abort()
+			return
+		}
+		c.Set(adminUserKey, user)
+		c.Next()
+	}
+}
+
+// AuditMutations returns a middleware that records every mutating request (i.e. every request
+// which isn't GET, HEAD or OPTIONS) in the admin audit trail, including rejected ones.
+func (env *Env) AuditMutations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = ioutil.ReadAll(c.Request.Body)
+			if err != nil {
+				log.Error("AuditMutations: read body: ", err)
+			}
+			c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+		}
+
+		c.Next()
+
+		entry := newAuditEntry(method, c.Request.URL.Path, body, c.Writer.Status())
+		if user, ok := c.Get(adminUserKey); ok {
+			entry.Username = user.(models.AdminUser).Username
+			entry.Role = user.(models.AdminUser).Role
+		} else {
+			entry.Username, _ = jwt.ExtractClaims(c)[IdentityKey].(string)
+		}
+		err := env.RelDB.SetAdminAuditEntry(entry)
+		if err != nil {
+			log.Errorf("AuditMutations: could not store audit entry %v: %v", entry, err)
+		}
+	}
+}
+
+// newAuditEntry returns the audit entry of a request with @body, whose secret fields are
+// redacted before it is truncated to maxAuditBodySize.
+func newAuditEntry(method string, path string, body []byte, statusCode int) models.AdminAuditEntry {
+	body = redactAuditBody(body)
+	if len(body) > maxAuditBodySize {
+		body = body[:maxAuditBodySize]
+	}
+	return models.AdminAuditEntry{
+		Method:     method,
+		Path:       path,
+		Body:       string(body),
+		StatusCode: statusCode,
+		Time:       time.Now(),
+	}
+}
+
+// redactAuditBody replaces the values of secretAuditFields in the json document @body, at any
+// depth. Bodies which aren't json are kept.
+func redactAuditBody(body []byte) []byte {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body
+	}
+	if !redactSecrets(doc) {
+		return body
+	}
+	redacted, err := json.Marshal(doc)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// redactSecrets redacts the secret fields in @doc and returns true if there were any.
+func redactSecrets(doc interface{}) (redacted bool) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecretAuditField(key) {
+				v[key] = redactedValue
+				redacted = true
+			} else if redactSecrets(value) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactSecrets(value) {
+				redacted = true
+			}
+		}
+	}
+	return
+}
+
+func isSecretAuditField(key string) bool {
+	for _, field := range secretAuditFields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentAdminUser returns the user RequireRole resolved for the request, or the zero user if there is none.
+func currentAdminUser(c *gin.Context) models.AdminUser {
+	user, _ := c.Get(adminUserKey)
+	adminUser, _ := user.(models.AdminUser)
+	return adminUser
+}
+
+// -----------------------------------------------------------------------------
+// ADMIN USERS
+// -----------------------------------------------------------------------------
+
+// GetAdminUsers returns all admin users along with their roles.
+func (env *Env) GetAdminUsers(c *gin.Context) {
+	q, err := env.RelDB.GetAdminUsers()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, q)
+}
+
+// PostAdminUser creates an admin user or updates its password and role.
+func (env *Env) PostAdminUser(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var t struct {
+		Username string
+		Password string
+		Role     models.AdminRole
+	}
+	err = json.Unmarshal(body, &t)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if t.Username == "" || !t.Role.Valid() {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing Username or invalid Role"))
+		return
+	}
+	if t.Username == env.BootstrapAdmin {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("cannot modify bootstrap admin"))
+		return
+	}
+
+	if t.Password == "" {
+		err = env.RelDB.SetAdminUserRole(t.Username, t.Role)
+	} else {
+		err = env.RelDB.SetAdminUser(t.Username, t.Password, t.Role)
+	}
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.AdminUser{Username: t.Username, Role: t.Role})
+}
+
+// DeleteAdminUser removes the admin user given by the path parameter.
+func (env *Env) DeleteAdminUser(c *gin.Context) {
+	username := c.Param("username")
+	err := env.RelDB.DeleteAdminUser(username)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, username)
+}
+
+// GetAdminAudit returns the audit trail of mutating admin actions. Optional query parameters
+// are username, starttime and endtime (unix timestamps). Default is the last 7 days.
+func (env *Env) GetAdminAudit(c *gin.Context) {
+	username := c.Query("username")
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), 7*24*time.Hour)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	q, err := env.RelDB.GetAdminAuditEntries(username, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, q)
+}
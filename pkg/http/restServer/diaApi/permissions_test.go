@@ -0,0 +1,27 @@
+package diaApi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuditEntryRedactsPasswords(t *testing.T) {
+	body := `{"Username":"alice","Password":"correct horse battery staple","Role":"curator"}`
+	entry := newAuditEntry("POST", "/v1/admin/users", []byte(body), 200)
+	if strings.Contains(entry.Body, "correct horse") {
+		t.Errorf("got body %s, expected no password", entry.Body)
+	}
+	if !strings.Contains(entry.Body, `"Username":"alice"`) || !strings.Contains(entry.Body, `"Password":"[redacted]"`) {
+		t.Errorf("got body %s, expected the username and a redacted password", entry.Body)
+	}
+
+	// Nested secrets are redacted, bodies without secrets are kept as sent.
+	entry = newAuditEntry("POST", "/v1/webhooks", []byte(`{"config":{"apiKey":"k3y"}}`), 200)
+	if strings.Contains(entry.Body, "k3y") {
+		t.Errorf("got body %s, expected no api key", entry.Body)
+	}
+	body = `{"Symbol": "BTC", "Weight": 0.5}`
+	if entry = newAuditEntry("POST", "/v1/basket", []byte(body), 200); entry.Body != body {
+		t.Errorf("got body %s, expected %s", entry.Body, body)
+	}
+}
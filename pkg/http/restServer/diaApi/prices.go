@@ -0,0 +1,101 @@
+package diaApi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxPricePrecision is the highest number of decimals clients can request for price strings.
+const maxPricePrecision = 18
+
+// priceKeys are the json keys holding prices which PriceStrings adds strings for.
+var priceKeys = map[string]bool{
+	"Price":             true,
+	"PriceYesterday":    true,
+	"EstimatedUSDPrice": true,
+	"Value":             true,
+}
+
+// priceWriter buffers the response body so that PriceStrings can rewrite it.
+type priceWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *priceWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *priceWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// PriceStrings returns a middleware adding a string with @precision decimals next to each price
+// in json responses, e.g. "PriceString":"0.00000123" next to "Price":1.23e-06, for clients
+// whose json decoders lose precision on float64 or don't accept exponents. Requests can set a
+// different number of decimals with ?precision=N.
+func PriceStrings(precision int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prec := precision
+		if p, err := strconv.Atoi(c.Query("precision")); err == nil && p >= 0 && p <= maxPricePrecision {
+			prec = p
+		}
+		writer := &priceWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if writer.Status() == 200 && strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+			formatted, err := AddPriceStrings(body, prec)
+			if err != nil {
+				log.Errorf("PriceStrings: %v", err)
+			} else {
+				body = formatted
+			}
+		}
+		_, err := c.Writer.Write(body)
+		if err != nil {
+			log.Errorf("PriceStrings: write response: %v", err)
+		}
+	}
+}
+
+// AddPriceStrings adds a string with @precision decimals next to every price in the json
+// document @data. The key of the string is the key of the price with the suffix String.
+// Other numbers keep their exact representation.
+func AddPriceStrings(data []byte, precision int) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	err := decoder.Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(addPriceStrings(document, precision))
+}
+
+func addPriceStrings(value interface{}, precision int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			if number, ok := element.(json.Number); ok && priceKeys[key] {
+				if f, err := number.Float64(); err == nil {
+					v[key+"String"] = strconv.FormatFloat(f, 'f', precision, 64)
+				}
+				continue
+			}
+			v[key] = addPriceStrings(element, precision)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = addPriceStrings(element, precision)
+		}
+	}
+	return value
+}
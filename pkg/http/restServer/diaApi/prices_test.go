@@ -0,0 +1,59 @@
+package diaApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAddPriceStrings(t *testing.T) {
+	cases := []struct {
+		data      string
+		precision int
+		expected  string
+	}{
+		{`{"Symbol":"SHIB","Price":1.23e-06,"VolumeYesterdayUSD":123456789012345678}`, 8,
+			`{"Price":1.23e-06,"PriceString":"0.00000123","Symbol":"SHIB","VolumeYesterdayUSD":123456789012345678}`},
+		{`[{"EstimatedUSDPrice":0.1,"Volume":2},{"EstimatedUSDPrice":3}]`, 2,
+			`[{"EstimatedUSDPrice":0.1,"EstimatedUSDPriceString":"0.10","Volume":2},{"EstimatedUSDPrice":3,"EstimatedUSDPriceString":"3.00"}]`},
+		{`{"Quotation":{"PriceYesterday":null,"Value":42}}`, 0,
+			`{"Quotation":{"PriceYesterday":null,"Value":42,"ValueString":"42"}}`},
+	}
+	for i, c := range cases {
+		result, err := AddPriceStrings([]byte(c.data), c.precision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(result) != c.expected {
+			t.Errorf("case %d: expected %s, got %s", i, c.expected, result)
+		}
+	}
+	if _, err := AddPriceStrings([]byte("not json"), 8); err == nil {
+		t.Errorf("expected error for invalid json")
+	}
+}
+
+func TestPriceStringsPrecision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(PriceStrings(2))
+	r.GET("/price", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"Price": 1.5})
+	})
+	// The precision of a request must not change the default of the following ones.
+	for _, c := range []struct {
+		path     string
+		expected string
+	}{
+		{"/price?precision=4", `{"Price":1.5,"PriceString":"1.5000"}`},
+		{"/price", `{"Price":1.5,"PriceString":"1.50"}`},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if w.Body.String() != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.path, c.expected, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package diaApi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/internal/pkg/dexRouting"
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+)
+
+// GetPricingRoute returns the pools, hops and liquidity used for the USD price of an asset,
+// along with the candidate pools of each hop and whether they are pinned or forbidden.
+func (env *Env) GetPricingRoute(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	route, err := dexRouting.BuildRoute(env.DataStore, symbol)
+	if err != nil {
+		if len(route.Hops) == 0 {
+			restApi.SendError(c, http.StatusNotFound, err)
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, route)
+}
+
+// GetRouteRules returns the pin and forbid rules of the asset given by the path parameter.
+func (env *Env) GetRouteRules(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	rules, err := env.DataStore.GetRouteRules(symbol)
+	if err != nil && err != redis.Nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if rules == nil {
+		rules = []dia.RouteRule{}
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// PostRouteRule pins or forbids a pool for the pricing of an asset. A rule for the same
+// exchange and pair replaces the existing one. The rule is applied by the tradesBlockService
+// within a minute.
+func (env *Env) PostRouteRule(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var rule dia.RouteRule
+	err = json.Unmarshal(body, &rule)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if rule.Exchange == "" || (rule.Action != dia.RoutePin && rule.Action != dia.RouteForbid) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing Exchange or invalid Action"))
+		return
+	}
+	rule.Asset = symbol
+	rule.Time = time.Now()
+
+	rules, err := env.DataStore.GetRouteRules(symbol)
+	if err != nil && err != redis.Nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	rules = append(removeRouteRule(rules, rule.Exchange, rule.Pair), rule)
+	err = env.DataStore.SetRouteRules(symbol, rules)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// DeleteRouteRule removes the rule of an asset for the exchange and optional pair given
+// as query parameters. Without exchange, all rules of the asset are removed.
+func (env *Env) DeleteRouteRule(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	exchange := c.Query("exchange")
+
+	var rules []dia.RouteRule
+	if exchange != "" {
+		var err error
+		rules, err = env.DataStore.GetRouteRules(symbol)
+		if err != nil {
+			if err == redis.Nil {
+				restApi.SendError(c, http.StatusNotFound, err)
+			} else {
+				restApi.SendError(c, http.StatusInternalServerError, err)
+			}
+			return
+		}
+		rules = removeRouteRule(rules, exchange, c.Query("pair"))
+	}
+	err := env.DataStore.SetRouteRules(symbol, rules)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if rules == nil {
+		rules = []dia.RouteRule{}
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// removeRouteRule returns @rules without the rule for @pair on @exchange.
+func removeRouteRule(rules []dia.RouteRule, exchange string, pair string) []dia.RouteRule {
+	var r []dia.RouteRule
+	for _, rule := range rules {
+		if rule.Exchange == exchange && strings.EqualFold(rule.Pair, pair) {
+			continue
+		}
+		r = append(r, rule)
+	}
+	return r
+}
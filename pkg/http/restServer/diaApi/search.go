@@ -0,0 +1,92 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	maxSearchQuery     = 64
+	// searchCandidates is the number of best textual matches which are ranked by volume.
+	searchCandidates = 100
+)
+
+// SearchAssets godoc
+// @Summary Search assets
+// @Description SearchAssets returns the assets whose symbol, name or address match query, for
+// @Description type-ahead search. Exact symbols and addresses come first, followed by prefixes,
+// @Description substrings and similar symbols or names. Matches of the same rank are ordered by
+// @Description their trading volume of the last 24 hours.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   query      query   string     true        "Symbol, name or address, e.g. eth"
+// @Param   limit      query   int        false       "Number of assets, default 10, at most 50"
+// @Success 200 {object} []models.AssetMatch "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/search [get]
+func (env *Env) SearchAssets(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("query"))
+	if query == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing query"))
+		return
+	}
+	if len(query) > maxSearchQuery {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("query is longer than %d characters", maxSearchQuery))
+		return
+	}
+	limit, err := intQuery(c, "limit", defaultSearchLimit, 1, maxSearchLimit)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	matches, err := env.RelDB.SearchAssets(query, searchCandidates)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	volumes := make(map[string]float64)
+	for i := range matches {
+		symbol := matches[i].Symbol
+		if _, ok := volumes[symbol]; !ok {
+			// Assets without trades have no volume and rank last among their matches.
+			if volume, err := env.DataStore.GetVolume(symbol); err == nil && volume != nil {
+				volumes[symbol] = *volume
+			} else {
+				volumes[symbol] = 0
+			}
+		}
+		matches[i].VolumeUSD = volumes[symbol]
+	}
+	rankMatches(matches)
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	if matches == nil {
+		matches = []models.AssetMatch{}
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// rankMatches sorts @matches by rank and then by descending volume. Matches of equal rank and
+// volume keep the order of the search.
+func rankMatches(matches []models.AssetMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Rank != matches[j].Rank {
+			return matches[i].Rank < matches[j].Rank
+		}
+		return matches[i].VolumeUSD > matches[j].VolumeUSD
+	})
+}
@@ -0,0 +1,29 @@
+package diaApi
+
+import (
+	"testing"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func TestRankMatches(t *testing.T) {
+	matches := []models.AssetMatch{
+		{Rank: 2, VolumeUSD: 500},
+		{Rank: 0, VolumeUSD: 1},
+		{Rank: 2, VolumeUSD: 1000},
+		{Rank: 1},
+		{Rank: 2, VolumeUSD: 500},
+	}
+	matches[0].Symbol, matches[4].Symbol = "first", "second"
+	rankMatches(matches)
+
+	expected := []float64{1, 0, 1000, 500, 500}
+	for i, match := range matches {
+		if match.VolumeUSD != expected[i] {
+			t.Fatalf("unexpected order %+v", matches)
+		}
+	}
+	if matches[3].Symbol != "first" || matches[4].Symbol != "second" {
+		t.Errorf("expected matches of equal rank and volume to keep their order")
+	}
+}
@@ -0,0 +1,117 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+)
+
+// GetStablecoinPegs godoc
+// @Summary Get the peg deviations of stablecoins
+// @Description GetStablecoinPegs returns the deviation of each monitored stablecoin from its peg,
+// @Description computed from its quotation and the fiat rate of its peg currency, and a composite
+// @Description de-peg index, the mean absolute deviation weighted by market cap. A stablecoin is
+// @Description depegged if it deviates by more than its threshold, which the threshold parameter
+// @Description overrides. Stablecoins without a quotation are left out, and stablecoins with an
+// @Description unknown supply don't count towards the index unless no supply is known.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   threshold  query   number     false       "Depeg threshold in percent for all stablecoins, e.g. 0.5"
+// @Success 200 {object} models.PegIndex "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No quotation of any stablecoin"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/stablecoins/peg [get]
+func (env *Env) GetStablecoinPegs(c *gin.Context) {
+	var threshold float64
+	if value := c.Query("threshold"); value != "" {
+		var err error
+		threshold, err = strconv.ParseFloat(value, 64)
+		if err != nil || threshold <= 0 || threshold >= 100 {
+			restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid threshold %q", value))
+			return
+		}
+		threshold /= 100
+	}
+
+	fxRates := map[string]float64{"USD": 1}
+	index := models.PegIndex{Depegged: []string{}, Stablecoins: []models.PegDeviation{}}
+	for _, stablecoin := range models.Stablecoins {
+		fx, ok := fxRates[stablecoin.Peg]
+		if !ok {
+			q, err := env.DataStore.GetQuotation(stablecoin.Peg)
+			if err != nil && err != redis.Nil {
+				restApi.SendError(c, http.StatusInternalServerError, err)
+				return
+			}
+			if err == nil {
+				fx = q.Price
+			}
+			fxRates[stablecoin.Peg] = fx
+		}
+		if fx == 0 {
+			continue
+		}
+		q, err := env.DataStore.GetQuotation(stablecoin.Symbol)
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		deviation := models.PegDeviation{
+			Stablecoin: stablecoin,
+			Price:      q.Price / fx,
+			PriceUSD:   q.Price,
+			Deviation:  q.Price/fx - 1,
+			Time:       q.Time,
+		}
+		if threshold > 0 {
+			deviation.Threshold = threshold
+		}
+		deviation.Depegged = math.Abs(deviation.Deviation) > deviation.Threshold
+		if deviation.Depegged {
+			index.Depegged = append(index.Depegged, stablecoin.Symbol)
+		}
+		if supply, err := env.DataStore.GetLatestSupply(stablecoin.Symbol); err == nil {
+			deviation.MarketCap = supply.CirculatingSupply * q.Price
+		}
+		if index.Time.Before(q.Time) {
+			index.Time = q.Time
+		}
+		index.Stablecoins = append(index.Stablecoins, deviation)
+	}
+	if len(index.Stablecoins) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no quotation of any stablecoin"))
+		return
+	}
+	index.Index = pegIndex(index.Stablecoins)
+	c.JSON(http.StatusOK, index)
+}
+
+// pegIndex returns the mean absolute deviation of @deviations weighted by market cap, or
+// unweighted if no market cap is known.
+func pegIndex(deviations []models.PegDeviation) float64 {
+	var sum, weights float64
+	for _, d := range deviations {
+		sum += math.Abs(d.Deviation) * d.MarketCap
+		weights += d.MarketCap
+	}
+	if weights > 0 {
+		return sum / weights
+	}
+	for _, d := range deviations {
+		sum += math.Abs(d.Deviation)
+	}
+	return sum / float64(len(deviations))
+}
@@ -0,0 +1,164 @@
+package diaApi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+)
+
+// maxStakingRange bounds the range of historical staking yields and rates of a request.
+const maxStakingRange = 365 * 24 * time.Hour
+
+// GetStakingYield godoc
+// @Summary Get the staking yield of a proof of stake asset
+// @Description GetStakingYield returns the current staking APR in percent of a proof of stake
+// @Description asset, e.g. ETH, SOL, ATOM or DOT, from each source. With starttime, it returns the
+// @Description recorded yields from starttime until endtime instead, oldest first. The range is
+// @Description limited to 365 days.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   asset      path    string     true        "Some symbol"
+// @Param   starttime  query   int        false       "Unix timestamp"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {array} dia.StakingYield "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No current staking yield"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/stakingYield/:asset [get]
+func (env *Env) GetStakingYield(c *gin.Context) {
+	asset := strings.ToUpper(c.Param("asset"))
+	if !utils.QuerySafe(asset) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid asset %q", asset))
+		return
+	}
+
+	if c.Query("starttime") == "" {
+		yields, err := env.DataStore.GetLatestStakingYields(asset)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if len(yields) == 0 {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no current staking yield of %s", asset))
+			return
+		}
+		c.JSON(http.StatusOK, yields)
+		return
+	}
+
+	starttime, endtime, ok := rangeQuery(c, maxStakingRange, maxStakingRange)
+	if !ok {
+		return
+	}
+	yields, err := env.DataStore.GetStakingYields(asset, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, yields)
+}
+
+// GetLiquidStakingRate godoc
+// @Summary Get the exchange rate of a liquid staking token
+// @Description GetLiquidStakingRate returns the amount of the underlying asset a liquid staking
+// @Description token redeems for at its protocol, e.g. ETH per rETH or stETH per wstETH, as read
+// @Description from its contract at Block, along with the market rate from the quotations of both
+// @Description and the fair value of the token in USD at the redemption rate. With starttime, it
+// @Description returns the recorded redemption rates from starttime until endtime instead, oldest
+// @Description first. The range is limited to 365 days.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   token      path    string     true        "Liquid staking token, e.g. wstETH, rETH, cbETH, sfrxETH or swETH"
+// @Param   starttime  query   int        false       "Unix timestamp"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {object} models.LiquidStakingRate "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No current rate"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/liquidStakingRate/:token [get]
+func (env *Env) GetLiquidStakingRate(c *gin.Context) {
+	// Symbols of liquid staking tokens are case sensitive, e.g. wstETH.
+	token := c.Param("token")
+	if !utils.QuerySafe(token) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid token %q", token))
+		return
+	}
+
+	if c.Query("starttime") != "" {
+		starttime, endtime, ok := rangeQuery(c, maxStakingRange, maxStakingRange)
+		if !ok {
+			return
+		}
+		rates, err := env.DataStore.GetLiquidStakingRates(token, starttime, endtime)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, rates)
+		return
+	}
+
+	rate, err := env.DataStore.GetLatestLiquidStakingRate(token)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if rate == nil {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no current rate of %s", token))
+		return
+	}
+	result := models.LiquidStakingRate{LiquidStakingRate: *rate}
+	var prices [2]float64
+	for i, symbol := range []string{rate.Token, rate.Underlying} {
+		prices[i], err = env.quotedPrice(symbol)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if prices[0] > 0 && prices[1] > 0 && rate.Rate > 0 {
+		marketRate := prices[0] / prices[1]
+		premium := marketRate/rate.Rate - 1
+		result.MarketRate = &marketRate
+		result.Premium = &premium
+	}
+	underlyingPrice := prices[1]
+	if peggedUnderlyings[rate.Underlying] {
+		underlyingPrice, err = env.quotedPrice("ETH")
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if underlyingPrice > 0 {
+		fairValue := rate.Rate * underlyingPrice
+		result.FairValue = &fairValue
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// peggedUnderlyings are underlyings of liquid staking tokens which their protocol keeps 1:1 with
+// ETH. The fair value of their tokens is derived from the price of ETH rather than their own thin
+// markets.
+var peggedUnderlyings = map[string]bool{"stETH": true, "frxETH": true}
+
+// quotedPrice returns the USD quotation of @symbol, or zero if there is none.
+func (env *Env) quotedPrice(symbol string) (float64, error) {
+	q, err := env.DataStore.GetQuotation(strings.ToUpper(symbol))
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return q.Price, nil
+}
@@ -0,0 +1,177 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultVolatilityWindow   = "30d"
+	defaultVolatilityInterval = "1d"
+	// maxWindow is the retention of the downsampled prices.
+	maxWindow = 365 * 24 * time.Hour
+	// maxWindowPrices bounds the number of prices a window can span at its interval.
+	maxWindowPrices = 10000
+	// year annualizes volatilities. Crypto assets trade on every day of the year.
+	year = 365 * 24 * time.Hour
+)
+
+// logReturn is the log return of an asset over the interval ending at Time.
+type logReturn struct {
+	Time  time.Time
+	Value float64
+}
+
+// GetVolatility godoc
+// @Summary Get the realized volatility of an asset
+// @Description GetVolatility returns the annualized realized volatility of an asset over a window
+// @Description until endtime, computed from the log returns of its prices sampled at interval.
+// @Description Returns across intervals without a price are left out.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol     path    string     true        "Some symbol"
+// @Param   window     query   string     false       "Window, e.g. 7d, 30d or 12w, default 30d, at most 365d"
+// @Param   interval   query   string     false       "5m 30m 1h 4h 1d 1w, default 1d"
+// @Param   endtime    query   int        false       "Unix timestamp, default now"
+// @Success 200 {object} models.Volatility "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "Not enough prices in the window"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/volatility/:symbol [get]
+func (env *Env) GetVolatility(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	resolution, starttime, endtime, ok := windowQuery(c, defaultVolatilityWindow, defaultVolatilityInterval)
+	if !ok {
+		return
+	}
+
+	returns, err := env.logReturns(symbol, resolution, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(returns) < 2 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("not enough prices of %s in the window", symbol))
+		return
+	}
+	values := make([]float64, len(returns))
+	for i, r := range returns {
+		values[i] = r.Value
+	}
+	c.JSON(http.StatusOK, models.Volatility{
+		Symbol:     symbol,
+		Window:     c.DefaultQuery("window", defaultVolatilityWindow),
+		Interval:   resolution,
+		StartTime:  starttime,
+		EndTime:    endtime,
+		Returns:    len(values),
+		Volatility: stdDev(values) * math.Sqrt(float64(year)/float64(models.QuotationResolutionDurations[resolution])),
+	})
+}
+
+// windowQuery parses the window, interval and endtime query parameters of statistics over a
+// window of prices. It returns the interval as a resolution of the downsampled prices, and sends
+// an error response if the parameters are invalid.
+func windowQuery(c *gin.Context, defaultWindow string, defaultInterval string) (resolution string, starttime time.Time, endtime time.Time, ok bool) {
+	window, err := parseWindow(c.DefaultQuery("window", defaultWindow))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if window > maxWindow {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("window exceeds 365d"))
+		return
+	}
+	resolution = c.DefaultQuery("interval", defaultInterval)
+	interval, known := models.QuotationResolutionDurations[resolution]
+	if !known {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("interval must be one of %s", strings.Join(models.QuotationResolutions, ", ")))
+		return
+	}
+	if window < 2*interval {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("window must span at least two intervals"))
+		return
+	}
+	if window/interval > maxWindowPrices {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("window exceeds %d intervals", maxWindowPrices))
+		return
+	}
+	endtime, err = unixQuery(c, "endtime", time.Now())
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	return resolution, endtime.Add(-window), endtime, true
+}
+
+// parseWindow parses a window in days or weeks, e.g. 30d or 12w, or a duration such as 12h.
+func parseWindow(window string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(window, "d") || strings.HasSuffix(window, "w"):
+		var n int
+		n, err = strconv.Atoi(window[:len(window)-1])
+		d = time.Duration(n) * 24 * time.Hour
+		if strings.HasSuffix(window, "w") {
+			d *= 7
+		}
+	default:
+		d, err = time.ParseDuration(window)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q", window)
+	}
+	return d, nil
+}
+
+// logReturns returns the log returns of @symbol from @starttime to @endtime at @resolution.
+// Returns are only computed between prices one interval apart.
+func (env *Env) logReturns(symbol string, resolution string, starttime time.Time, endtime time.Time) ([]logReturn, error) {
+	interval := models.QuotationResolutionDurations[resolution]
+	prices, err := env.DataStore.GetQuotationRange(symbol, resolution, starttime, endtime, int(endtime.Sub(starttime)/interval)+1)
+	if err != nil {
+		return nil, err
+	}
+	var returns []logReturn
+	for i := 1; i < len(prices); i++ {
+		previous, current := prices[i-1], prices[i]
+		if current.Time.Sub(previous.Time) != interval || previous.Price <= 0 || current.Price <= 0 {
+			continue
+		}
+		returns = append(returns, logReturn{Time: current.Time, Value: math.Log(current.Price / previous.Price)})
+	}
+	return returns, nil
+}
+
+// stdDev returns the sample standard deviation of @values.
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
@@ -0,0 +1,105 @@
+package diaApi
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+)
+
+// priceStore serves daily prices from memory. Other methods of the datastore are not implemented.
+type priceStore struct {
+	models.Datastore
+	prices map[string][]models.PricePoint
+}
+
+func (s *priceStore) GetQuotationRange(symbol string, resolution string, starttime time.Time, endtime time.Time, limit int) ([]models.PricePoint, error) {
+	points := []models.PricePoint{}
+	for _, p := range s.prices[symbol] {
+		if !p.Time.Before(starttime) && p.Time.Before(endtime) && len(points) < limit {
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+func TestParseWindow(t *testing.T) {
+	for window, expected := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "12h": 12 * time.Hour} {
+		if d, err := parseWindow(window); err != nil || d != expected {
+			t.Errorf("parseWindow(%s) = %v, %v", window, d, err)
+		}
+	}
+	for _, window := range []string{"", "d", "-3d", "1.5d", "30"} {
+		if _, err := parseWindow(window); err == nil {
+			t.Errorf("expected error for window %q", window)
+		}
+	}
+}
+
+func TestGetVolatility(t *testing.T) {
+	end := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	// Prices alternate between 100 and 110, so that the log returns alternate in sign. The day
+	// without a price splits the series, leaving out the returns across it.
+	var prices []models.PricePoint
+	for day := 10; day > 0; day-- {
+		if day == 5 {
+			continue
+		}
+		price := 100.0
+		if day%2 == 0 {
+			price = 110
+		}
+		prices = append(prices, models.PricePoint{Time: end.AddDate(0, 0, -day), Price: price})
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/v1/volatility/:symbol", (&Env{DataStore: &priceStore{prices: map[string][]models.PricePoint{"BTC": prices}}}).GetVolatility)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/volatility/btc?window=10d&endtime=1617235200", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body)
+	}
+	var volatility models.Volatility
+	if err := json.Unmarshal(w.Body.Bytes(), &volatility); err != nil {
+		t.Fatal(err)
+	}
+	values := []float64{}
+	for i := 0; i < 7; i++ {
+		values = append(values, math.Log(110.0/100)*math.Pow(-1, float64(i)))
+	}
+	expected := stdDev(values) * math.Sqrt(365)
+	if volatility.Returns != 7 || math.Abs(volatility.Volatility-expected) > 1e-12 {
+		t.Errorf("expected 7 returns with volatility %v, got %+v", expected, volatility)
+	}
+
+	for _, query := range []string{"window=1d", "window=400d", "interval=2d", "window=x"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/volatility/btc?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request for %s, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestPearson(t *testing.T) {
+	x := []float64{0.01, -0.02, 0.03, 0.005}
+	negated := []float64{-0.01, 0.02, -0.03, -0.005}
+	if r, ok := pearson(x, x); !ok || r != 1 {
+		t.Errorf("expected correlation 1, got %v", r)
+	}
+	if r, ok := pearson(x, negated); !ok || r != -1 {
+		t.Errorf("expected correlation -1, got %v", r)
+	}
+	if _, ok := pearson(x, []float64{0.01, 0.01, 0.01, 0.01}); ok {
+		t.Error("expected no correlation with constant returns")
+	}
+	if _, ok := pearson(x[:1], negated[:1]); ok {
+		t.Error("expected no correlation of a single return")
+	}
+}
@@ -0,0 +1,99 @@
+package diaApi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxVWAPRange bounds the range of a VWAP request, as it is aggregated from raw trades.
+const maxVWAPRange = 31 * 24 * time.Hour
+
+// GetVWAP godoc
+// @Summary Get the VWAP of an asset
+// @Description GetVWAP returns the volume weighted average USD price of an asset over a time range,
+// @Description computed from its trades on the requested exchanges only, along with the VWAP and
+// @Description volume of each of them. The range is limited to 31 days.
+// @Tags dia
+// @Accept  json
+// @Produce  json
+// @Param   symbol            path    string     true        "Some symbol"
+// @Param   exchanges         query   string     false       "Comma separated exchanges to include, default all"
+// @Param   excludeExchanges  query   string     false       "Comma separated exchanges to leave out"
+// @Param   starttime         query   int        false       "Unix timestamp, default 24 hours before endtime"
+// @Param   endtime           query   int        false       "Unix timestamp, default now"
+// @Success 200 {object} models.VWAP "success"
+// @Failure 400 {object} restApi.APIError "Invalid parameter"
+// @Failure 404 {object} restApi.APIError "No trades in the range"
+// @Failure 500 {object} restApi.APIError "error"
+// @Router /v1/vwap/:symbol [get]
+func (env *Env) GetVWAP(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if !utils.QuerySafe(symbol) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("invalid symbol %q", symbol))
+		return
+	}
+	exchanges, err := exchangesQuery(c, "exchanges")
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	excluded, err := exchangesQuery(c, "excludeExchanges")
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	endtime, err := unixQuery(c, "endtime", time.Now())
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	starttime, err := unixQuery(c, "starttime", endtime.Add(-24*time.Hour))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if !starttime.Before(endtime) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("starttime must be before endtime"))
+		return
+	}
+	if endtime.Sub(starttime) > maxVWAPRange {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("range exceeds %v", maxVWAPRange))
+		return
+	}
+
+	vwap, err := env.DataStore.GetVWAP(symbol, exchanges, excluded, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if vwap.Volume == 0 {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("no trades of %s in the range", symbol))
+		return
+	}
+	c.JSON(http.StatusOK, vwap)
+}
+
+// exchangesQuery returns the exchanges in the comma separated query parameter @key.
+func exchangesQuery(c *gin.Context, key string) ([]string, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	var exchanges []string
+	for _, exchange := range strings.Split(value, ",") {
+		exchange = strings.TrimSpace(exchange)
+		if exchange == "" || !utils.QuerySafe(exchange) {
+			return nil, fmt.Errorf("invalid exchange %q", exchange)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, nil
+}
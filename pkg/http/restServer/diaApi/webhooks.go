@@ -0,0 +1,190 @@
+package diaApi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// maxWebhooks bounds the number of webhooks of a user.
+	maxWebhooks = 100
+	// maxWebhookWindow is the longest window of move conditions the webhook service keeps
+	// prices for.
+	maxWebhookWindow = 24 * 60
+)
+
+// PostWebhook registers a webhook which the webhook service posts to when the price of Symbol
+// crosses above or below Threshold, or moves by more than Threshold percent within
+// WindowMinutes. The response holds the secret the callbacks are signed with, which isn't
+// returned again.
+func (env *Env) PostWebhook(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var webhook models.Webhook
+	err = json.Unmarshal(body, &webhook)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	err = validateWebhook(&webhook)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	_, err = env.DataStore.GetQuotation(webhook.Symbol)
+	if err != nil {
+		if err == redis.Nil {
+			restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("no quotation of %s", webhook.Symbol))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	webhook.Creator = currentAdminUser(c).Username
+	webhooks, err := env.RelDB.GetWebhooks(webhook.Creator)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(webhooks) >= maxWebhooks {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("a user can have at most %d webhooks", maxWebhooks))
+		return
+	}
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	webhook.Secret = hex.EncodeToString(secret)
+	webhook.CreatedAt = time.Now()
+
+	webhook.ID, err = env.RelDB.SetWebhook(webhook)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// GetWebhooks returns the webhooks of the user, or all webhooks for admins, without their secrets.
+func (env *Env) GetWebhooks(c *gin.Context) {
+	user := currentAdminUser(c)
+	creator := user.Username
+	if user.Role.Includes(models.RoleAdmin) {
+		creator = ""
+	}
+	webhooks, err := env.RelDB.GetWebhooks(creator)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// DeleteWebhook removes a webhook of the user along with its dead letters.
+func (env *Env) DeleteWebhook(c *gin.Context) {
+	webhook, ok := env.ownWebhook(c)
+	if !ok {
+		return
+	}
+	err := env.RelDB.DeleteWebhook(webhook.ID)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook.ID)
+}
+
+// GetWebhookDeadLetters returns the latest events which couldn't be delivered to a webhook of
+// the user after all retries, at most limit, default 100.
+func (env *Env) GetWebhookDeadLetters(c *gin.Context) {
+	limit, err := intQuery(c, "limit", 100, 1, 1000)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	webhook, ok := env.ownWebhook(c)
+	if !ok {
+		return
+	}
+	deadLetters, err := env.RelDB.GetWebhookDeadLetters(webhook.ID, limit)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if deadLetters == nil {
+		deadLetters = []models.WebhookDeadLetter{}
+	}
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+// ownWebhook returns the webhook in the id path parameter if it belongs to the user or the user
+// is an admin, and sends an error response otherwise.
+func (env *Env) ownWebhook(c *gin.Context) (models.Webhook, bool) {
+	webhook, err := env.RelDB.GetWebhook(c.Param("id"))
+	user := currentAdminUser(c)
+	if err == nil && webhook.Creator != user.Username && !user.Role.Includes(models.RoleAdmin) {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			restApi.SendError(c, http.StatusNotFound, fmt.Errorf("unknown webhook %q", c.Param("id")))
+		} else {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+		}
+		return webhook, false
+	}
+	webhook.Secret = ""
+	return webhook, true
+}
+
+// validateWebhook validates the url and condition of a new webhook and upper cases its symbol.
+func validateWebhook(webhook *models.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q", webhook.URL)
+	}
+	webhook.Symbol = strings.ToUpper(webhook.Symbol)
+	if webhook.Symbol == "" {
+		return errors.New("missing Symbol")
+	}
+	if webhook.Threshold <= 0 {
+		return errors.New("Threshold must be positive")
+	}
+	switch webhook.Condition {
+	case models.WebhookAbove, models.WebhookBelow:
+		webhook.WindowMinutes = 0
+	case models.WebhookMove:
+		if webhook.WindowMinutes < 1 || webhook.WindowMinutes > maxWebhookWindow {
+			return fmt.Errorf("WindowMinutes must be from 1 to %d", maxWebhookWindow)
+		}
+	default:
+		return fmt.Errorf("Condition must be one of %s, %s, %s", models.WebhookAbove, models.WebhookBelow, models.WebhookMove)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package graphqlApi
+
+import (
+	"net/http"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+const (
+	// maxDepth bounds the nesting of queries, e.g. exchanges { assets { lastTrades } } has depth 3.
+	maxDepth = 5
+	// maxParallelism bounds the resolvers run concurrently for a single query.
+	maxParallelism = 10
+)
+
+// NewHandler returns the http handler of the schema over @datastore and @relDB. It answers POST
+// requests with a json body holding the query, the operationName and the variables.
+func NewHandler(datastore models.Datastore, relDB models.RelDatastore) (http.Handler, error) {
+	s, err := graphql.ParseSchema(schema, &Resolver{DataStore: datastore, RelDB: relDB}, graphql.MaxDepth(maxDepth), graphql.MaxParallelism(maxParallelism))
+	if err != nil {
+		return nil, err
+	}
+	return &relay.Handler{Schema: s}, nil
+}
@@ -0,0 +1,66 @@
+package graphqlApi
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/go-redis/redis"
+)
+
+// fakeStore serves the quotation and trades of BTC. Other methods panic.
+type fakeStore struct {
+	models.Datastore
+}
+
+func (fakeStore) GetQuotation(symbol string) (*models.Quotation, error) {
+	if symbol != "BTC" {
+		return nil, redis.Nil
+	}
+	return &models.Quotation{Symbol: "BTC", Name: "Bitcoin", Price: 50000, Time: time.Unix(1600000000, 0).UTC()}, nil
+}
+
+func (fakeStore) GetLastTradesAllExchanges(symbol string, maxTrades int) ([]dia.Trade, error) {
+	trades := []dia.Trade{{Symbol: symbol, Pair: symbol + "USDT", Price: 50001, Source: "Binance"}, {Symbol: symbol, Pair: symbol + "USD", Price: 49999, Source: "Kraken"}}
+	if len(trades) > maxTrades {
+		trades = trades[:maxTrades]
+	}
+	return trades, nil
+}
+
+func TestHandler(t *testing.T) {
+	handler, err := NewHandler(fakeStore{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{`{ quotation(symbol: "btc") { symbol price time } }`,
+			`{"data":{"quotation":{"symbol":"BTC","price":50000,"time":"2020-09-13T12:26:40Z"}}}`},
+		{`{ quotation(symbol: "XYZ") { price } }`,
+			`{"data":{"quotation":null}}`},
+		{`{ asset(symbol: "BTC") { name quotation { price } lastTrades(limit: 1) { exchange price } } }`,
+			`{"data":{"asset":{"name":"Bitcoin","quotation":{"price":50000},"lastTrades":[{"exchange":"Binance","price":50001}]}}}`},
+	}
+	for i, c := range cases {
+		recorder := httptest.NewRecorder()
+		body := `{"query":"` + strings.ReplaceAll(c.query, `"`, `\"`) + `"}`
+		handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/v1/graphql", strings.NewReader(body)))
+		if got := strings.TrimSpace(recorder.Body.String()); got != c.expected {
+			t.Errorf("case %d: expected %s, got %s", i, c.expected, got)
+		}
+	}
+
+	// Quotes in a symbol are rejected before it reaches a query.
+	recorder := httptest.NewRecorder()
+	body := `{"query":"{ asset(symbol: \"BTC' or symbol='ETH\") { lastTrades { price } } }"}`
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/v1/graphql", strings.NewReader(body)))
+	if got := recorder.Body.String(); !strings.Contains(got, "invalid symbol") {
+		t.Errorf("expected invalid symbol error, got %s", got)
+	}
+}
@@ -0,0 +1,253 @@
+package graphqlApi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	maxTrades     = 1000
+	maxNFTClasses = 1000
+)
+
+// Resolver resolves the root query of the schema.
+type Resolver struct {
+	DataStore models.Datastore
+	RelDB     models.RelDatastore
+}
+
+// Symbols and exchanges reach InfluxQL queries through the fields of assets and exchanges, so
+// arguments with quotes or backslashes are rejected.
+
+func (r *Resolver) Asset(args struct{ Symbol string }) (*assetResolver, error) {
+	if !utils.QuerySafe(args.Symbol) {
+		return nil, fmt.Errorf("invalid symbol %q", args.Symbol)
+	}
+	return &assetResolver{r: r, symbol: strings.ToUpper(args.Symbol)}, nil
+}
+
+func (r *Resolver) Assets(args struct{ Exchange *string }) ([]*assetResolver, error) {
+	var symbols []string
+	if args.Exchange != nil {
+		if !utils.QuerySafe(*args.Exchange) {
+			return nil, fmt.Errorf("invalid exchange %q", *args.Exchange)
+		}
+		symbols = r.DataStore.GetSymbolsByExchange(*args.Exchange)
+	} else {
+		symbols = r.DataStore.GetAllSymbols()
+	}
+	return r.assets(symbols), nil
+}
+
+func (r *Resolver) assets(symbols []string) []*assetResolver {
+	assets := make([]*assetResolver, len(symbols))
+	for i, symbol := range symbols {
+		assets[i] = &assetResolver{r: r, symbol: symbol}
+	}
+	return assets
+}
+
+func (r *Resolver) Quotation(args struct{ Symbol string }) (*quotationResolver, error) {
+	return r.quotation(strings.ToUpper(args.Symbol))
+}
+
+// quotation returns nil if there is no quotation of @symbol.
+func (r *Resolver) quotation(symbol string) (*quotationResolver, error) {
+	q, err := r.DataStore.GetQuotation(symbol)
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &quotationResolver{q}, nil
+}
+
+func (r *Resolver) Exchanges() []*exchangeResolver {
+	names := r.DataStore.GetExchanges()
+	exchanges := make([]*exchangeResolver, len(names))
+	for i, name := range names {
+		exchanges[i] = &exchangeResolver{r: r, name: name}
+	}
+	return exchanges
+}
+
+func (r *Resolver) Exchange(args struct{ Name string }) (*exchangeResolver, error) {
+	if !utils.QuerySafe(args.Name) {
+		return nil, fmt.Errorf("invalid exchange %q", args.Name)
+	}
+	return &exchangeResolver{r: r, name: args.Name}, nil
+}
+
+func (r *Resolver) NftClasses(args struct {
+	Blockchain *string
+	Limit      int32
+	Offset     int32
+}) ([]*nftClassResolver, error) {
+	var classes []dia.NFTClass
+	var err error
+	if args.Blockchain != nil {
+		classes, err = r.RelDB.GetAllNFTClasses(*args.Blockchain)
+	} else {
+		if args.Limit < 0 || args.Limit > maxNFTClasses || args.Offset < 0 {
+			return nil, fmt.Errorf("limit must be between 0 and %d and offset positive", maxNFTClasses)
+		}
+		classes, err = r.RelDB.GetNFTClasses(uint64(args.Limit), uint64(args.Offset))
+	}
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*nftClassResolver, len(classes))
+	for i := range classes {
+		resolvers[i] = &nftClassResolver{r: r, c: classes[i]}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) NftClass(args struct {
+	Blockchain string
+	Address    string
+}) (*nftClassResolver, error) {
+	class, err := r.RelDB.GetNFTClass(common.HexToAddress(args.Address).Hex(), args.Blockchain)
+	if err != nil {
+		return nil, err
+	}
+	return &nftClassResolver{r: r, c: class}, nil
+}
+
+type assetResolver struct {
+	r      *Resolver
+	symbol string
+}
+
+func (a *assetResolver) Symbol() string {
+	return a.symbol
+}
+
+func (a *assetResolver) Name() string {
+	return helpers.NameForSymbol(a.symbol)
+}
+
+func (a *assetResolver) Quotation() (*quotationResolver, error) {
+	return a.r.quotation(a.symbol)
+}
+
+func (a *assetResolver) Supply() (*supplyResolver, error) {
+	s, err := a.r.DataStore.GetLatestSupply(a.symbol)
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &supplyResolver{s}, nil
+}
+
+func (a *assetResolver) Exchanges() ([]string, error) {
+	return a.r.DataStore.GetExchangesForSymbol(a.symbol)
+}
+
+func (a *assetResolver) LastTrades(args struct {
+	Exchange *string
+	Limit    int32
+}) ([]*tradeResolver, error) {
+	if args.Limit < 0 || args.Limit > maxTrades {
+		return nil, fmt.Errorf("limit must be between 0 and %d", maxTrades)
+	}
+	var trades []dia.Trade
+	var err error
+	if args.Exchange != nil {
+		if !utils.QuerySafe(*args.Exchange) {
+			return nil, fmt.Errorf("invalid exchange %q", *args.Exchange)
+		}
+		trades, err = a.r.DataStore.GetLastTrades(a.symbol, *args.Exchange, int(args.Limit))
+	} else {
+		trades, err = a.r.DataStore.GetLastTradesAllExchanges(a.symbol, int(args.Limit))
+	}
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*tradeResolver, len(trades))
+	for i := range trades {
+		resolvers[i] = &tradeResolver{&trades[i]}
+	}
+	return resolvers, nil
+}
+
+type quotationResolver struct {
+	q *models.Quotation
+}
+
+func (q *quotationResolver) Symbol() string               { return q.q.Symbol }
+func (q *quotationResolver) Name() string                 { return q.q.Name }
+func (q *quotationResolver) Price() float64               { return q.q.Price }
+func (q *quotationResolver) PriceYesterday() *float64     { return q.q.PriceYesterday }
+func (q *quotationResolver) VolumeYesterdayUSD() *float64 { return q.q.VolumeYesterdayUSD }
+func (q *quotationResolver) Source() string               { return q.q.Source }
+func (q *quotationResolver) Time() graphql.Time           { return graphql.Time{Time: q.q.Time} }
+func (q *quotationResolver) Itin() string                 { return q.q.ITIN }
+
+type supplyResolver struct {
+	s *dia.Supply
+}
+
+func (s *supplyResolver) Symbol() string             { return s.s.Symbol }
+func (s *supplyResolver) Name() string               { return s.s.Name }
+func (s *supplyResolver) Supply() float64            { return s.s.Supply }
+func (s *supplyResolver) CirculatingSupply() float64 { return s.s.CirculatingSupply }
+func (s *supplyResolver) Source() string             { return s.s.Source }
+func (s *supplyResolver) Time() graphql.Time         { return graphql.Time{Time: s.s.Time} }
+
+type tradeResolver struct {
+	t *dia.Trade
+}
+
+func (t *tradeResolver) Symbol() string             { return t.t.Symbol }
+func (t *tradeResolver) Pair() string               { return t.t.Pair }
+func (t *tradeResolver) Price() float64             { return t.t.Price }
+func (t *tradeResolver) Volume() float64            { return t.t.Volume }
+func (t *tradeResolver) EstimatedUSDPrice() float64 { return t.t.EstimatedUSDPrice }
+func (t *tradeResolver) Exchange() string           { return t.t.Source }
+func (t *tradeResolver) ForeignTradeID() string     { return t.t.ForeignTradeID }
+func (t *tradeResolver) Time() graphql.Time         { return graphql.Time{Time: t.t.Time} }
+
+type exchangeResolver struct {
+	r    *Resolver
+	name string
+}
+
+func (e *exchangeResolver) Name() string {
+	return e.name
+}
+
+func (e *exchangeResolver) Volume24h() (float64, error) {
+	return e.r.DataStore.Sum24HoursExchange(e.name)
+}
+
+func (e *exchangeResolver) Assets() []*assetResolver {
+	return e.r.assets(e.r.DataStore.GetSymbolsByExchange(e.name))
+}
+
+type nftClassResolver struct {
+	r *Resolver
+	c dia.NFTClass
+}
+
+func (n *nftClassResolver) Address() string      { return n.c.Address }
+func (n *nftClassResolver) Symbol() string       { return n.c.Symbol }
+func (n *nftClassResolver) Name() string         { return n.c.Name }
+func (n *nftClassResolver) Blockchain() string   { return n.c.Blockchain }
+func (n *nftClassResolver) ContractType() string { return n.c.ContractType }
+func (n *nftClassResolver) Category() string     { return n.c.Category }
+
+func (n *nftClassResolver) Price30Days() (float64, error) {
+	return n.r.RelDB.GetNFTPrice30Days(n.c)
+}
@@ -0,0 +1,84 @@
+// Package graphqlApi serves the assets, quotations, trades, supplies, exchanges and NFT classes
+// of the REST API as a GraphQL schema, so that clients can select the fields they need and
+// fetch nested data in a single request.
+package graphqlApi
+
+const schema = `
+schema {
+	query: Query
+}
+
+scalar Time
+
+type Query {
+	# Asset with the symbol, e.g. BTC.
+	asset(symbol: String!): Asset!
+	# Assets traded on the exchange, or all assets.
+	assets(exchange: String): [Asset!]!
+	quotation(symbol: String!): Quotation
+	exchanges: [Exchange!]!
+	exchange(name: String!): Exchange!
+	# NFT classes of the blockchain, or a page of all NFT classes.
+	nftClasses(blockchain: String, limit: Int = 100, offset: Int = 0): [NFTClass!]!
+	nftClass(blockchain: String!, address: String!): NFTClass
+}
+
+type Asset {
+	symbol: String!
+	name: String!
+	quotation: Quotation
+	supply: Supply
+	exchanges: [String!]!
+	# Latest trades, of the exchange if given. At most 1000.
+	lastTrades(exchange: String, limit: Int = 10): [Trade!]!
+}
+
+type Quotation {
+	symbol: String!
+	name: String!
+	price: Float!
+	priceYesterday: Float
+	volumeYesterdayUSD: Float
+	source: String!
+	time: Time!
+	itin: String!
+}
+
+type Supply {
+	symbol: String!
+	name: String!
+	supply: Float!
+	circulatingSupply: Float!
+	source: String!
+	time: Time!
+}
+
+type Trade {
+	symbol: String!
+	pair: String!
+	price: Float!
+	volume: Float!
+	estimatedUSDPrice: Float!
+	exchange: String!
+	foreignTradeID: String!
+	time: Time!
+}
+
+type Exchange {
+	name: String!
+	# Trading volume in USD of the last 24 hours.
+	volume24h: Float!
+	assets: [Asset!]!
+}
+
+type NFTClass {
+	address: String!
+	symbol: String!
+	name: String!
+	blockchain: String!
+	contractType: String!
+	category: String!
+	# Average price of the trades of the class in the last 30 days.
+	price30Days: Float!
+}
+`
@@ -0,0 +1,339 @@
+// Package streamApi streams quotations and trades to websocket and server-sent events clients.
+// Clients subscribe to channels, optionally narrowed down to a symbol, an exchange or a filter,
+// and receive every matching message as it is read from kafka.
+package streamApi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/metrics"
+)
+
+// Channels clients can subscribe to.
+const (
+	// ChannelTrades streams the raw trades of all scrapers.
+	ChannelTrades = "trades"
+	// ChannelQuotations streams the filter values computed for each trades block, e.g. MA120.
+	ChannelQuotations = "quotations"
+)
+
+const (
+	// maxSubscriptions bounds the subscriptions of a single connection.
+	maxSubscriptions = 100
+	// sendBuffer is the number of messages queued per connection. Clients which fall further
+	// behind are disconnected instead of slowing down the stream for everyone.
+	sendBuffer = 256
+	// historySize is the number of quotation messages kept for replay to reconnecting clients.
+	// It covers a few filters blocks.
+	historySize = 20000
+	// maxReplay bounds the messages replayed to a reconnecting client, leaving room in its
+	// queue for new messages.
+	maxReplay = sendBuffer / 2
+)
+
+var (
+	streamConnections = metrics.NewGauge("api", "stream_connections", "Open websocket connections and gRPC streams of the streaming API.")
+	streamMessages    = metrics.NewCounter("api", "stream_messages", "Messages sent by the streaming API by channel.", "channel")
+	streamDropped     = metrics.NewCounter("api", "stream_dropped_connections", "Connections closed because the client didn't keep up.")
+)
+
+// Subscription selects the messages of a channel. Empty fields match all values. Exchange only
+// applies to trades and Filter only to quotations.
+type Subscription struct {
+	Channel  string `json:"channel"`
+	Symbol   string `json:"symbol,omitempty"`
+	Exchange string `json:"exchange,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+}
+
+// validate normalizes the subscription and returns an error if it can never match.
+func (s *Subscription) validate() error {
+	s.Symbol = strings.ToUpper(s.Symbol)
+	switch s.Channel {
+	case ChannelTrades:
+		if s.Filter != "" {
+			return errors.New("trades have no filter")
+		}
+	case ChannelQuotations:
+		if s.Exchange != "" {
+			return errors.New("quotations have no exchange")
+		}
+	default:
+		return fmt.Errorf("unknown channel %q, expected %s or %s", s.Channel, ChannelTrades, ChannelQuotations)
+	}
+	return nil
+}
+
+// matches returns true if a message of @channel about @symbol on @exchange for @filter is
+// selected by the subscription.
+func (s Subscription) matches(channel string, symbol string, exchange string, filter string) bool {
+	return s.Channel == channel &&
+		(s.Symbol == "" || strings.EqualFold(s.Symbol, symbol)) &&
+		(s.Exchange == "" || strings.EqualFold(s.Exchange, exchange)) &&
+		(s.Filter == "" || strings.EqualFold(s.Filter, filter))
+}
+
+// Message is sent to clients. Type is the channel for data messages, or one of subscribed,
+// unsubscribed and error in reply to requests.
+type Message struct {
+	// ID identifies data messages in the order they are published, see Hub.EventID.
+	ID           uint64        `json:"-"`
+	Type         string        `json:"type"`
+	Subscription *Subscription `json:"subscription,omitempty"`
+	Data         interface{}   `json:"data,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// event is a message queued for clients. Its json encoding is computed once and shared by all
+// websocket clients.
+type event struct {
+	Message
+	// symbol, exchange and filter of the message, to match it against subscriptions on replay.
+	symbol   string
+	exchange string
+	filter   string
+	once     sync.Once
+	encoded  []byte
+	err      error
+}
+
+func (e *event) json() ([]byte, error) {
+	e.once.Do(func() {
+		e.encoded, e.err = json.Marshal(e.Message)
+	})
+	return e.encoded, e.err
+}
+
+// client is a connection of the hub. Its subscriptions are guarded by the hub.
+type client struct {
+	send          chan *event
+	subscriptions map[Subscription]bool
+	closed        bool
+}
+
+func newClient() *client {
+	return &client{send: make(chan *event, sendBuffer), subscriptions: make(map[Subscription]bool)}
+}
+
+// Hub dispatches messages to the clients subscribed to them.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[*client]bool
+	// epoch distinguishes the message IDs of this hub from those of a previous process.
+	epoch string
+	seq   uint64
+	// history is a ring of the latest quotation messages, the oldest at historyNext once full.
+	history     []*event
+	historyNext int
+}
+
+// NewHub returns a hub without clients.
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[*client]bool),
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		history: make([]*event, 0, historySize),
+	}
+}
+
+func (h *Hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[c] = true
+	streamConnections.Set(float64(len(h.clients)))
+}
+
+// unregister removes @c and closes its send channel, which ends its write loop.
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(c)
+}
+
+// remove must be called with h.mu held.
+func (h *Hub) remove(c *client) {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	delete(h.clients, c)
+	close(c.send)
+	streamConnections.Set(float64(len(h.clients)))
+}
+
+func (h *Hub) subscribe(c *client, s Subscription) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !c.subscriptions[s] && len(c.subscriptions) >= maxSubscriptions {
+		return fmt.Errorf("at most %d subscriptions per connection", maxSubscriptions)
+	}
+	c.subscriptions[s] = true
+	return nil
+}
+
+func (h *Hub) unsubscribe(c *client, s Subscription) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !c.subscriptions[s] {
+		return errors.New("not subscribed")
+	}
+	delete(c.subscriptions, s)
+	return nil
+}
+
+// reply queues @m for @c alone. Replies are dropped if the client is gone.
+func (h *Hub) reply(c *client, m Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.queue(c, &event{Message: m})
+}
+
+// Publish sends @data on @channel to all clients subscribed to @symbol, @exchange or @filter.
+// It never blocks: clients whose queue is full are disconnected.
+func (h *Hub) Publish(channel string, symbol string, exchange string, filter string, data interface{}) {
+	e := &event{Message: Message{Type: channel, Data: data}, symbol: symbol, exchange: exchange, filter: filter}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seq++
+	e.ID = h.seq
+	if channel == ChannelQuotations {
+		h.record(e)
+	}
+	for c := range h.clients {
+		for s := range c.subscriptions {
+			if s.matches(channel, symbol, exchange, filter) {
+				h.queue(c, e)
+				streamMessages.Inc(channel)
+				break
+			}
+		}
+	}
+}
+
+// record adds @e to the history. It must be called with h.mu held.
+func (h *Hub) record(e *event) {
+	if len(h.history) < historySize {
+		h.history = append(h.history, e)
+		return
+	}
+	h.history[h.historyNext] = e
+	h.historyNext = (h.historyNext + 1) % historySize
+}
+
+// replay queues the recorded messages published after @after which match the subscriptions of
+// @c, at most maxReplay of the latest. It must be called with h.mu held.
+func (h *Hub) replay(c *client, after uint64) {
+	var matching []*event
+	for i := range h.history {
+		e := h.history[(h.historyNext+i)%len(h.history)]
+		if e.ID <= after {
+			continue
+		}
+		for s := range c.subscriptions {
+			if s.matches(e.Type, e.symbol, e.exchange, e.filter) {
+				matching = append(matching, e)
+				break
+			}
+		}
+	}
+	if len(matching) > maxReplay {
+		matching = matching[len(matching)-maxReplay:]
+	}
+	for _, e := range matching {
+		h.queue(c, e)
+	}
+}
+
+// EventID returns the ID of @m for clients resuming the stream, e.g. with the Last-Event-ID of
+// server-sent events. IDs of a previous process are ignored by SubscribeAfter.
+func (h *Hub) EventID(m Message) string {
+	return h.epoch + "-" + strconv.FormatUint(m.ID, 10)
+}
+
+// queue must be called with h.mu held.
+func (h *Hub) queue(c *client, e *event) {
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- e:
+	default:
+		streamDropped.Inc()
+		h.remove(c)
+	}
+}
+
+// ErrDropped is returned by Subscriber.Next once the subscriber has been disconnected because
+// it didn't keep up with the stream.
+var ErrDropped = errors.New("subscriber didn't keep up with the stream")
+
+// Subscriber receives the messages of a fixed set of subscriptions without a websocket
+// connection, e.g. for server streams of the gRPC API.
+type Subscriber struct {
+	hub    *Hub
+	client *client
+}
+
+// Subscribe returns a subscriber to @subscriptions. It must be closed when no longer needed.
+func (h *Hub) Subscribe(subscriptions ...Subscription) (*Subscriber, error) {
+	return h.SubscribeAfter("", subscriptions...)
+}
+
+// SubscribeAfter returns a subscriber to @subscriptions which first receives the recorded
+// quotations published after the message with ID @lastEventID, if it is known.
+func (h *Hub) SubscribeAfter(lastEventID string, subscriptions ...Subscription) (*Subscriber, error) {
+	if len(subscriptions) == 0 || len(subscriptions) > maxSubscriptions {
+		return nil, fmt.Errorf("between 1 and %d subscriptions required", maxSubscriptions)
+	}
+	c := newClient()
+	for i := range subscriptions {
+		err := subscriptions[i].validate()
+		if err != nil {
+			return nil, err
+		}
+		c.subscriptions[subscriptions[i]] = true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if after, ok := h.parseEventID(lastEventID); ok {
+		h.replay(c, after)
+	}
+	h.clients[c] = true
+	streamConnections.Set(float64(len(h.clients)))
+	return &Subscriber{hub: h, client: c}, nil
+}
+
+// parseEventID returns the sequence number of @id if it was issued by this hub.
+func (h *Hub) parseEventID(id string) (uint64, bool) {
+	i := strings.LastIndexByte(id, '-')
+	if i < 0 || id[:i] != h.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(id[i+1:], 10, 64)
+	return seq, err == nil
+}
+
+// Next blocks until the next message or the end of @ctx.
+func (s *Subscriber) Next(ctx context.Context) (Message, error) {
+	select {
+	case e, ok := <-s.client.send:
+		if !ok {
+			return Message{}, ErrDropped
+		}
+		return e.Message, nil
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+// Close unsubscribes the subscriber from the hub.
+func (s *Subscriber) Close() {
+	s.hub.unregister(s.client)
+}
@@ -0,0 +1,189 @@
+package streamApi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionMatches(t *testing.T) {
+	cases := []struct {
+		s        Subscription
+		channel  string
+		symbol   string
+		exchange string
+		filter   string
+		expected bool
+	}{
+		{Subscription{Channel: ChannelTrades}, ChannelTrades, "BTC", "Binance", "", true},
+		{Subscription{Channel: ChannelTrades}, ChannelQuotations, "BTC", "", "MA120", false},
+		{Subscription{Channel: ChannelTrades, Symbol: "BTC"}, ChannelTrades, "btc", "Binance", "", true},
+		{Subscription{Channel: ChannelTrades, Symbol: "BTC"}, ChannelTrades, "ETH", "Binance", "", false},
+		{Subscription{Channel: ChannelTrades, Exchange: "Kraken"}, ChannelTrades, "BTC", "Binance", "", false},
+		{Subscription{Channel: ChannelQuotations, Symbol: "ETH", Filter: "MA120"}, ChannelQuotations, "ETH", "", "MA120", true},
+		{Subscription{Channel: ChannelQuotations, Filter: "MA120"}, ChannelQuotations, "ETH", "", "VOL120", false},
+	}
+	for i, c := range cases {
+		if c.s.matches(c.channel, c.symbol, c.exchange, c.filter) != c.expected {
+			t.Errorf("case %d: expected %v", i, c.expected)
+		}
+	}
+}
+
+func TestSubscriptionValidate(t *testing.T) {
+	valid := []Subscription{
+		{Channel: ChannelTrades, Symbol: "btc", Exchange: "Binance"},
+		{Channel: ChannelQuotations, Filter: "MA120"},
+	}
+	for _, s := range valid {
+		if err := s.validate(); err != nil {
+			t.Errorf("%v: %v", s, err)
+		}
+	}
+	invalid := []Subscription{
+		{Channel: "orders"},
+		{Channel: ChannelTrades, Filter: "MA120"},
+		{Channel: ChannelQuotations, Exchange: "Binance"},
+	}
+	for _, s := range invalid {
+		if err := s.validate(); err == nil {
+			t.Errorf("%v: expected error", s)
+		}
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	h := NewHub()
+	btc, all, slow := newClient(), newClient(), newClient()
+	for _, c := range []*client{btc, all, slow} {
+		h.register(c)
+	}
+	if err := h.subscribe(btc, Subscription{Channel: ChannelTrades, Symbol: "BTC"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.subscribe(all, Subscription{Channel: ChannelTrades}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.subscribe(slow, Subscription{Channel: ChannelTrades}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < sendBuffer; i++ {
+		slow.send <- nil
+	}
+
+	h.Publish(ChannelTrades, "ETH", "Binance", "", "eth trade")
+	h.Publish(ChannelTrades, "BTC", "Binance", "", "btc trade")
+
+	if len(btc.send) != 1 {
+		t.Fatalf("expected 1 message for BTC, got %d", len(btc.send))
+	}
+	data, err := (<-btc.send).json()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"type":"trades","data":"btc trade"}` {
+		t.Errorf("unexpected message %s", data)
+	}
+	if len(all.send) != 2 {
+		t.Errorf("expected 2 messages for all trades, got %d", len(all.send))
+	}
+	if !slow.closed || h.clients[slow] {
+		t.Errorf("expected slow client to be dropped")
+	}
+
+	if err := h.unsubscribe(btc, Subscription{Channel: ChannelTrades, Symbol: "BTC"}); err != nil {
+		t.Fatal(err)
+	}
+	h.Publish(ChannelTrades, "BTC", "Binance", "", "btc trade")
+	if len(btc.send) != 0 {
+		t.Errorf("expected no message after unsubscribe")
+	}
+	h.unregister(btc)
+	h.unregister(btc)
+}
+
+func TestHubMaxSubscriptions(t *testing.T) {
+	h := NewHub()
+	c := newClient()
+	h.register(c)
+	for i := 0; i < maxSubscriptions; i++ {
+		if err := h.subscribe(c, Subscription{Channel: ChannelTrades, Symbol: string(rune('A' + i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.subscribe(c, Subscription{Channel: ChannelQuotations}); err == nil {
+		t.Errorf("expected error beyond %d subscriptions", maxSubscriptions)
+	}
+}
+
+func TestSubscriber(t *testing.T) {
+	h := NewHub()
+	if _, err := h.Subscribe(Subscription{Channel: "orders"}); err == nil {
+		t.Errorf("expected error for unknown channel")
+	}
+	s, err := h.Subscribe(Subscription{Channel: ChannelQuotations, Symbol: "eth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Publish(ChannelQuotations, "BTC", "", "MA120", 1.0)
+	h.Publish(ChannelQuotations, "ETH", "", "MA120", 2.0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m, err := s.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Data != 2.0 {
+		t.Errorf("expected ETH quotation, got %v", m.Data)
+	}
+	s.Close()
+	if _, err := s.Next(ctx); err != ErrDropped {
+		t.Errorf("expected ErrDropped after close, got %v", err)
+	}
+}
+
+func TestSubscribeAfter(t *testing.T) {
+	h := NewHub()
+	h.Publish(ChannelQuotations, "BTC", "", "MA120", 1.0)
+	h.Publish(ChannelQuotations, "ETH", "", "MA120", 2.0)
+	h.Publish(ChannelTrades, "ETH", "Binance", "", "eth trade")
+	h.Publish(ChannelQuotations, "ETH", "", "MA120", 3.0)
+
+	s, err := h.Subscribe(Subscription{Channel: ChannelQuotations, Symbol: "ETH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if len(s.client.send) != 0 {
+		t.Fatalf("expected no replay without last event id")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, lastEventID := range []string{h.EventID(Message{ID: 1}), "-1", "other-1"} {
+		r, err := h.SubscribeAfter(lastEventID, Subscription{Channel: ChannelQuotations, Symbol: "ETH"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastEventID != h.EventID(Message{ID: 1}) {
+			if len(r.client.send) != 0 {
+				t.Errorf("%s: expected no replay of unknown id", lastEventID)
+			}
+			r.Close()
+			continue
+		}
+		for _, expected := range []float64{2.0, 3.0} {
+			m, err := r.Next(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Data != expected {
+				t.Errorf("expected replay of %v, got %v", expected, m.Data)
+			}
+		}
+		if len(r.client.send) != 0 {
+			t.Errorf("expected replay of ETH quotations only")
+		}
+		r.Close()
+	}
+}
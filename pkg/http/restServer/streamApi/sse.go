@@ -0,0 +1,91 @@
+package streamApi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// keepAlivePeriod is the time between two comments sent to idle event streams, which keeps
+	// proxies from closing them.
+	keepAlivePeriod = 15 * time.Second
+	// retryMillis is the reconnection delay suggested to event stream clients.
+	retryMillis = 3000
+)
+
+// ServeSSE streams the quotations of the assets in the symbols query parameter as server-sent
+// events, for clients which can't open a websocket. The filter query parameter restricts the
+// stream to a filter, e.g. MA120. Each event has the id of its message, so that clients
+// reconnecting with Last-Event-ID first receive the quotations they missed.
+func (h *Hub) ServeSSE(c *gin.Context) {
+	var subscriptions []Subscription
+	for _, symbol := range strings.Split(c.Query("symbols"), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			subscriptions = append(subscriptions, Subscription{Channel: ChannelQuotations, Symbol: symbol, Filter: c.Query("filter")})
+		}
+	}
+	if len(subscriptions) == 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("symbols required"))
+		return
+	}
+	// Browsers send Last-Event-ID on reconnect; the query parameter is for clients which can't
+	// set headers.
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	subscriber, err := h.SubscribeAfter(lastEventID, subscriptions...)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	defer subscriber.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Disables response buffering of nginx.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", retryMillis)
+	c.Writer.Flush()
+
+	for {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), keepAlivePeriod)
+		m, err := subscriber.Next(ctx)
+		cancel()
+		switch {
+		case err == context.DeadlineExceeded:
+			_, err = fmt.Fprint(c.Writer, ": keep-alive\n\n")
+		case err != nil:
+			// The client disconnected or didn't keep up. The latter reconnects and resumes.
+			return
+		default:
+			err = writeEvent(c.Writer, h.EventID(m), m)
+		}
+		if err != nil {
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes the data of @m as a server-sent event of type m.Type with @id.
+func writeEvent(w http.ResponseWriter, id string, m Message) error {
+	data, err := json.Marshal(m.Data)
+	if err != nil {
+		log.Errorf("stream %s: %v", m.Type, err)
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, m.Type, data)
+	return err
+}
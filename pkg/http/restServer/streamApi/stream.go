@@ -0,0 +1,182 @@
+package streamApi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/segmentio/kafka-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// writeWait is the time allowed to write a message to a client.
+	writeWait = 10 * time.Second
+	// pongWait is the time allowed to read the next pong or request from a client.
+	pongWait = 60 * time.Second
+	// pingPeriod is the time between two pings. It must be less than pongWait.
+	pingPeriod = pongWait * 9 / 10
+	// maxRequestSize bounds the size of requests of clients.
+	maxRequestSize = 4096
+)
+
+// Actions of client requests.
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// Request is sent by clients to change their subscriptions, e.g.
+// {"action":"subscribe","channel":"trades","symbol":"BTC","exchange":"Binance"}.
+type Request struct {
+	Action string `json:"action"`
+	Subscription
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// The stream is public and read-only, like the REST API.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ServeWS upgrades the request to a websocket connection and streams the messages of its
+// subscriptions until the client disconnects.
+func (h *Hub) ServeWS(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Warnf("stream upgrade: %v", err)
+		return
+	}
+	cl := newClient()
+	h.register(cl)
+	go h.writeLoop(cl, conn)
+	h.readLoop(cl, conn)
+}
+
+// readLoop handles the requests of @cl until the connection fails or the client stops
+// answering pings.
+func (h *Hub) readLoop(cl *client, conn *websocket.Conn) {
+	defer func() {
+		h.unregister(cl)
+		conn.Close()
+	}()
+	conn.SetReadLimit(maxRequestSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		var request Request
+		err := conn.ReadJSON(&request)
+		if err != nil {
+			if _, ok := err.(*websocket.CloseError); !ok {
+				log.Debugf("stream read: %v", err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		h.handle(cl, request)
+	}
+}
+
+// handle applies @request of @cl and replies with the outcome.
+func (h *Hub) handle(cl *client, request Request) {
+	s := request.Subscription
+	err := s.validate()
+	if err == nil {
+		switch request.Action {
+		case ActionSubscribe:
+			err = h.subscribe(cl, s)
+		case ActionUnsubscribe:
+			err = h.unsubscribe(cl, s)
+		default:
+			h.reply(cl, Message{Type: "error", Error: "unknown action " + request.Action})
+			return
+		}
+	}
+	if err != nil {
+		h.reply(cl, Message{Type: "error", Subscription: &s, Error: err.Error()})
+		return
+	}
+	h.reply(cl, Message{Type: request.Action + "d", Subscription: &s})
+}
+
+// writeLoop sends the queued messages of @cl and pings it every pingPeriod. It returns when
+// the hub closes the queue or a write fails.
+func (h *Hub) writeLoop(cl *client, conn *websocket.Conn) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+	for {
+		select {
+		case e, ok := <-cl.send:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// The hub closed the connection, either because it was closed by the client or
+				// because the client didn't keep up with the stream.
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, ""))
+				return
+			}
+			data, err := e.json()
+			if err != nil {
+				log.Errorf("stream %s: %v", e.Type, err)
+				continue
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// StreamTrades publishes the trades read by @reader on ChannelTrades. It blocks forever.
+func (h *Hub) StreamTrades(reader *kafka.Reader) {
+	for {
+		m, err := reader.ReadMessage(context.Background())
+		if err != nil {
+			log.Errorf("stream trades: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		var trade dia.Trade
+		err = trade.UnmarshalBinary(m.Value)
+		if err != nil {
+			log.Errorf("stream trades: %v", err)
+			continue
+		}
+		h.Publish(ChannelTrades, trade.Symbol, trade.Source, "", trade)
+	}
+}
+
+// StreamQuotations publishes the filter points of the filters blocks read by @reader on
+// ChannelQuotations. It blocks forever.
+func (h *Hub) StreamQuotations(reader *kafka.Reader) {
+	for {
+		m, err := reader.ReadMessage(context.Background())
+		if err != nil {
+			log.Errorf("stream quotations: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		var block dia.FiltersBlock
+		err = block.UnmarshalBinary(m.Value)
+		if err != nil {
+			log.Errorf("stream quotations: %v", err)
+			continue
+		}
+		for _, point := range block.FiltersBlockData.FilterPoints {
+			h.Publish(ChannelQuotations, point.Symbol, "", point.Name, point)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package logging configures the logrus logger of a service from the environment, so that
+// operators can switch services to JSON logs for a log aggregator without changing their flags.
+//
+// LOG_FORMAT is either text (default) or json. LOG_LEVEL is one of the logrus levels, e.g.
+// debug, info (default), warn or error. All entries carry the field service, and entries of
+// oracle services carry fields such as chain, symbol, key, txHash, gasPrice and deviation.
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Setup configures the standard logger of @service from LOG_FORMAT and LOG_LEVEL. Invalid
+// values are reported and replaced by the defaults.
+func Setup(service string) {
+	err := configure(log.StandardLogger(), service, os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		log.Warnf("logging: %v", err)
+	}
+}
+
+func configure(logger *log.Logger, service string, format string, level string) error {
+	logger.AddHook(serviceHook{service: service})
+
+	var problems []string
+	switch strings.ToLower(format) {
+	case "", "text":
+		logger.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	case "json":
+		logger.SetFormatter(&log.JSONFormatter{})
+	default:
+		problems = append(problems, fmt.Sprintf("unknown LOG_FORMAT %q, using text", format))
+	}
+
+	logger.SetLevel(log.InfoLevel)
+	if level != "" {
+		l, err := log.ParseLevel(level)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("unknown LOG_LEVEL %q, using info", level))
+		} else {
+			logger.SetLevel(l)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// serviceHook adds the name of the service to all entries.
+type serviceHook struct {
+	service string
+}
+
+func (h serviceHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h serviceHook) Fire(entry *log.Entry) error {
+	if _, ok := entry.Data["service"]; !ok {
+		entry.Data["service"] = h.service
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestConfigure(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&buf)
+	err := configure(logger, "diaMultiOracleService", "json", "debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.WithField("symbol", "ETH").Debug("updating")
+
+	var entry map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &entry)
+	if err != nil {
+		t.Fatalf("entry is no json: %v", err)
+	}
+	if entry["service"] != "diaMultiOracleService" || entry["symbol"] != "ETH" || entry["level"] != "debug" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+
+	if err := configure(log.New(), "s", "xml", ""); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	logger = log.New()
+	if err := configure(logger, "s", "", "verbose"); err == nil || logger.GetLevel() != log.InfoLevel {
+		t.Errorf("expected error and info level for unknown level, got %v and %v", err, logger.GetLevel())
+	}
+	err = configure(log.New(), "s", "xml", "verbose")
+	if err == nil || !strings.Contains(err.Error(), "LOG_FORMAT") || !strings.Contains(err.Error(), "LOG_LEVEL") {
+		t.Errorf("got %v, expected errors for format and level", err)
+	}
+}
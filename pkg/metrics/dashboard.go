@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	panelWidth  = 12
+	panelHeight = 8
+	// rateWindow is the range over which counters are turned into rates.
+	rateWindow = "5m"
+)
+
+// Expr returns the PromQL query shown for the metric: per second rates for counters and the
+// current value for gauges, both split by all labels.
+func (d Desc) Expr() string {
+	if d.Kind == KindCounter {
+		if len(d.Labels) == 0 {
+			return "sum(rate(" + d.Name + "[" + rateWindow + "]))"
+		}
+		return "sum by (" + strings.Join(d.Labels, ", ") + ") (rate(" + d.Name + "[" + rateWindow + "]))"
+	}
+	return d.Name
+}
+
+// legendFormat shows the label values of a series.
+func (d Desc) legendFormat() string {
+	if len(d.Labels) == 0 {
+		return d.Name
+	}
+	parts := make([]string, len(d.Labels))
+	for i, l := range d.Labels {
+		parts[i] = "{{" + l + "}}"
+	}
+	return strings.Join(parts, " ")
+}
+
+func (d Desc) unit() string {
+	switch {
+	case strings.HasSuffix(d.Name, "_timestamp_seconds"):
+		return "dateTimeAsIso"
+	case strings.HasSuffix(strings.TrimSuffix(d.Name, "_total"), "_seconds"):
+		return "s"
+	case strings.HasSuffix(strings.TrimSuffix(d.Name, "_total"), "_bytes"):
+		return "bytes"
+	case strings.HasSuffix(d.Name, "_ratio"):
+		return "percentunit"
+	case d.Kind == KindCounter:
+		return "ops"
+	}
+	return "short"
+}
+
+// Dashboard returns a Grafana dashboard with one row per subsystem and one time series panel
+// per metric. The Prometheus datasource is selected through the DS_PROMETHEUS variable.
+func (r *Registry) Dashboard(service string) map[string]interface{} {
+	var panels []interface{}
+	id := 1
+	y := 0
+	subsystem := ""
+	x := 0
+	for _, d := range r.Descs() {
+		if d.Subsystem != subsystem {
+			if x > 0 {
+				y += panelHeight
+				x = 0
+			}
+			subsystem = d.Subsystem
+			panels = append(panels, map[string]interface{}{
+				"id":        id,
+				"type":      "row",
+				"title":     subsystem,
+				"collapsed": false,
+				"gridPos":   map[string]int{"h": 1, "w": 2 * panelWidth, "x": 0, "y": y},
+				"panels":    []interface{}{},
+			})
+			id++
+			y++
+		}
+		panels = append(panels, map[string]interface{}{
+			"id":          id,
+			"type":        "timeseries",
+			"title":       d.Name,
+			"description": d.Help,
+			"datasource":  "${DS_PROMETHEUS}",
+			"gridPos":     map[string]int{"h": panelHeight, "w": panelWidth, "x": x, "y": y},
+			"fieldConfig": map[string]interface{}{
+				"defaults":  map[string]interface{}{"unit": d.unit()},
+				"overrides": []interface{}{},
+			},
+			"targets": []interface{}{
+				map[string]interface{}{
+					"refId":        "A",
+					"expr":         d.Expr(),
+					"legendFormat": d.legendFormat(),
+				},
+			},
+		})
+		id++
+		x += panelWidth
+		if x >= 2*panelWidth {
+			x = 0
+			y += panelHeight
+		}
+	}
+
+	return map[string]interface{}{
+		"title":         "DIA " + service,
+		"uid":           "dia-" + strings.ToLower(service),
+		"tags":          []string{"dia", service},
+		"schemaVersion": 30,
+		"editable":      true,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":  "DS_PROMETHEUS",
+					"label": "Prometheus",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+			},
+		},
+		"panels": panels,
+	}
+}
+
+// DashboardHandler serves the Grafana dashboard of @r as json, ready for import.
+func (r *Registry) DashboardHandler(service string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(r.Dashboard(service))
+	})
+}
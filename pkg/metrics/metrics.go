@@ -0,0 +1,285 @@
+// Package metrics provides counters and gauges exported in the Prometheus text format,
+// along with a Grafana dashboard generated from the registered metrics.
+//
+// All metric names follow the scheme dia_<subsystem>_<name>[_<unit>], where subsystem is the
+// service or package emitting the metric (e.g. oraclefeeder, tradesblock, api) and name is in
+// snake case. Counters end in _total. Units are spelled out in their base form: _seconds,
+// _bytes, _wei, _ratio. Timestamps are gauges named *_timestamp_seconds.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	KindCounter = "counter"
+	KindGauge   = "gauge"
+)
+
+var (
+	subsystemRegex = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	nameRegex      = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+)
+
+// Desc describes a metric.
+type Desc struct {
+	Name      string
+	Subsystem string
+	Help      string
+	Kind      string
+	Labels    []string
+}
+
+// Validate returns an error if the metric doesn't follow the naming scheme.
+func (d Desc) Validate() error {
+	if !subsystemRegex.MatchString(d.Subsystem) {
+		return fmt.Errorf("invalid subsystem %q", d.Subsystem)
+	}
+	if !strings.HasPrefix(d.Name, "dia_"+d.Subsystem+"_") || !nameRegex.MatchString(d.Name) {
+		return fmt.Errorf("metric %q doesn't match dia_%s_<name>", d.Name, d.Subsystem)
+	}
+	switch d.Kind {
+	case KindCounter:
+		if !strings.HasSuffix(d.Name, "_total") {
+			return fmt.Errorf("counter %q must end in _total", d.Name)
+		}
+	case KindGauge:
+		if strings.HasSuffix(d.Name, "_total") {
+			return fmt.Errorf("gauge %q must not end in _total", d.Name)
+		}
+	default:
+		return fmt.Errorf("unknown kind %q of metric %q", d.Kind, d.Name)
+	}
+	if d.Help == "" {
+		return fmt.Errorf("metric %q has no help", d.Name)
+	}
+	for _, l := range d.Labels {
+		if !nameRegex.MatchString(l) {
+			return fmt.Errorf("invalid label %q of metric %q", l, d.Name)
+		}
+	}
+	return nil
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// metric holds the series of all label combinations of a metric.
+type metric struct {
+	desc   Desc
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func (m *metric) add(v float64, set bool, labelValues []string) {
+	if len(labelValues) != len(m.desc.Labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", m.desc.Name, len(m.desc.Labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string{}, labelValues...)}
+		m.series[key] = s
+	}
+	if set {
+		s.value = v
+	} else {
+		s.value += v
+	}
+}
+
+// Counter is a monotonically increasing metric.
+type Counter struct {
+	m *metric
+}
+
+// Inc increments the series given by @labelValues by one.
+func (c *Counter) Inc(labelValues ...string) {
+	c.m.add(1, false, labelValues)
+}
+
+// Add adds @v, which must not be negative, to the series given by @labelValues.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("counter " + c.m.desc.Name + " can't decrease")
+	}
+	c.m.add(v, false, labelValues)
+}
+
+// Gauge is a metric which can go up and down.
+type Gauge struct {
+	m *metric
+}
+
+// Set sets the series given by @labelValues to @v.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.m.add(v, true, labelValues)
+}
+
+// Add adds @v to the series given by @labelValues.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.m.add(v, false, labelValues)
+}
+
+// Registry holds the metrics of a service.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]*metric
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*metric)}
+}
+
+// DefaultRegistry is used by NewCounter, NewGauge and Serve.
+var DefaultRegistry = NewRegistry()
+
+// register adds a metric described by @desc. Registering the same metric twice returns the
+// existing one, so that packages can be instrumented independent of each other.
+func (r *Registry) register(desc Desc) *metric {
+	if err := desc.Validate(); err != nil {
+		panic(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.metrics[desc.Name]; ok {
+		if m.desc.Kind != desc.Kind || strings.Join(m.desc.Labels, ",") != strings.Join(desc.Labels, ",") {
+			panic("metric " + desc.Name + " registered twice with different kind or labels")
+		}
+		return m
+	}
+	m := &metric{desc: desc, series: make(map[string]*series)}
+	r.metrics[desc.Name] = m
+	return m
+}
+
+// NewCounter registers the counter dia_@subsystem_@name_total.
+func (r *Registry) NewCounter(subsystem string, name string, help string, labels ...string) *Counter {
+	return &Counter{r.register(Desc{
+		Name:      "dia_" + subsystem + "_" + name + "_total",
+		Subsystem: subsystem,
+		Help:      help,
+		Kind:      KindCounter,
+		Labels:    labels,
+	})}
+}
+
+// NewGauge registers the gauge dia_@subsystem_@name.
+func (r *Registry) NewGauge(subsystem string, name string, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(Desc{
+		Name:      "dia_" + subsystem + "_" + name,
+		Subsystem: subsystem,
+		Help:      help,
+		Kind:      KindGauge,
+		Labels:    labels,
+	})}
+}
+
+// NewCounter registers a counter in the DefaultRegistry.
+func NewCounter(subsystem string, name string, help string, labels ...string) *Counter {
+	return DefaultRegistry.NewCounter(subsystem, name, help, labels...)
+}
+
+// NewGauge registers a gauge in the DefaultRegistry.
+func NewGauge(subsystem string, name string, help string, labels ...string) *Gauge {
+	return DefaultRegistry.NewGauge(subsystem, name, help, labels...)
+}
+
+// Descs returns the descriptions of all registered metrics, sorted by name.
+func (r *Registry) Descs() []Desc {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var descs []Desc
+	for _, m := range r.metrics {
+		descs = append(descs, m.desc)
+	}
+	sort.Slice(descs, func(i, j int) bool {
+		return descs[i].Name < descs[j].Name
+	})
+	return descs
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteText writes all metrics in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	for _, desc := range r.Descs() {
+		r.mu.Lock()
+		m := r.metrics[desc.Name]
+		r.mu.Unlock()
+
+		m.mu.Lock()
+		keys := make([]string, 0, len(m.series))
+		for k := range m.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", desc.Name, strings.Replace(desc.Help, "\n", " ", -1), desc.Name, desc.Kind)
+		for _, k := range keys {
+			s := m.series[k]
+			b.WriteString(desc.Name)
+			if len(desc.Labels) > 0 {
+				pairs := make([]string, len(desc.Labels))
+				for i, l := range desc.Labels {
+					pairs[i] = fmt.Sprintf(`%s="%s"`, l, labelEscaper.Replace(s.labelValues[i]))
+				}
+				b.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			fmt.Fprintf(&b, " %g\n", s.value)
+		}
+		m.mu.Unlock()
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the metrics of @r in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		r.WriteText(w)
+	})
+}
+
+// serveMux is served by Serve. Services add their own endpoints to it with Handle.
+var serveMux = http.NewServeMux()
+
+// Handle adds @handler for @pattern to the endpoints served by Serve, e.g. status endpoints of
+// a service next to its metrics.
+func Handle(pattern string, handler http.Handler) {
+	serveMux.Handle(pattern, handler)
+}
+
+// Serve exports the DefaultRegistry on @addr under /metrics and its Grafana dashboard under
+// /metrics/dashboard, along with the endpoints added by Handle. It returns immediately; an
+// empty @addr disables the export.
+func Serve(addr string, service string) {
+	if addr == "" {
+		return
+	}
+	mux := serveMux
+	mux.Handle("/metrics", DefaultRegistry.Handler())
+	mux.Handle("/metrics/dashboard", DefaultRegistry.DashboardHandler(service))
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Fatalf("metrics: %v", err)
+		}
+	}()
+}
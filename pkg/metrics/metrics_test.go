@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []Desc{
+		{Name: "dia_oraclefeeder_updates_total", Subsystem: "oraclefeeder", Help: "h", Kind: KindCounter, Labels: []string{"symbol"}},
+		{Name: "dia_api_last_request_timestamp_seconds", Subsystem: "api", Help: "h", Kind: KindGauge},
+	}
+	for _, d := range valid {
+		if err := d.Validate(); err != nil {
+			t.Errorf("unexpected error for %s: %v", d.Name, err)
+		}
+	}
+	invalid := []Desc{
+		{Name: "dia_oraclefeeder_updates", Subsystem: "oraclefeeder", Help: "h", Kind: KindCounter},
+		{Name: "dia_api_requests_total", Subsystem: "api", Help: "h", Kind: KindGauge},
+		{Name: "oraclefeeder_updates_total", Subsystem: "oraclefeeder", Help: "h", Kind: KindCounter},
+		{Name: "dia_api_Requests_total", Subsystem: "api", Help: "h", Kind: KindCounter},
+		{Name: "dia_api_requests_total", Subsystem: "api", Kind: KindCounter},
+		{Name: "dia_api_requests_total", Subsystem: "api", Help: "h", Kind: KindCounter, Labels: []string{"Route"}},
+	}
+	for _, d := range invalid {
+		if err := d.Validate(); err == nil {
+			t.Errorf("expected error for %+v", d)
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("api", "requests", "Number of requests.", "route", "status")
+	c.Inc("/v1/quotation/:symbol", "200")
+	c.Add(2, "/v1/quotation/:symbol", "200")
+	g := r.NewGauge("api", "up", "Whether the api is up.")
+	g.Set(1)
+	// Registering again returns the same metric.
+	r.NewCounter("api", "requests", "Number of requests.", "route", "status").Inc("/v1/coins", "500")
+
+	var b bytes.Buffer
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP dia_api_requests_total Number of requests.
+# TYPE dia_api_requests_total counter
+dia_api_requests_total{route="/v1/coins",status="500"} 1
+dia_api_requests_total{route="/v1/quotation/:symbol",status="200"} 3
+# HELP dia_api_up Whether the api is up.
+# TYPE dia_api_up gauge
+dia_api_up 1
+`
+	if b.String() != expected {
+		t.Errorf("unexpected output:\n%s", b.String())
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("api", "requests", "Number of requests.", "route")
+	r.NewGauge("oraclefeeder", "last_update_timestamp_seconds", "Time of the last update.", "symbol")
+
+	panels := r.Dashboard("test")["panels"].([]interface{})
+	// One row and one panel per subsystem.
+	if len(panels) != 4 {
+		t.Fatalf("expected 4 panels, got %d", len(panels))
+	}
+	counter := panels[1].(map[string]interface{})
+	expr := counter["targets"].([]interface{})[0].(map[string]interface{})["expr"].(string)
+	if expr != "sum by (route) (rate(dia_api_requests_total[5m]))" {
+		t.Errorf("unexpected expr %s", expr)
+	}
+	gauge := panels[3].(map[string]interface{})
+	unit := gauge["fieldConfig"].(map[string]interface{})["defaults"].(map[string]interface{})["unit"]
+	if unit != "dateTimeAsIso" || !strings.HasPrefix(gauge["title"].(string), "dia_oraclefeeder_") {
+		t.Errorf("unexpected gauge panel %v", gauge)
+	}
+}
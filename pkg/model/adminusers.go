@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// AdminRole is the role of a user of the authenticated admin/curation endpoints.
+// Roles are ordered, i.e. each role includes all permissions of the roles below it.
+type AdminRole string
+
+const (
+	RoleViewer   AdminRole = "viewer"
+	RoleCurator  AdminRole = "curator"
+	RoleOperator AdminRole = "operator"
+	RoleAdmin    AdminRole = "admin"
+)
+
+var adminRoleLevels = map[AdminRole]int{
+	RoleViewer:   1,
+	RoleCurator:  2,
+	RoleOperator: 3,
+	RoleAdmin:    4,
+}
+
+// Valid returns true if @r is one of the known admin roles.
+func (r AdminRole) Valid() bool {
+	_, ok := adminRoleLevels[r]
+	return ok
+}
+
+// Includes returns true if @r carries at least the permissions of @required.
+func (r AdminRole) Includes(required AdminRole) bool {
+	if !r.Valid() || !required.Valid() {
+		return false
+	}
+	return adminRoleLevels[r] >= adminRoleLevels[required]
+}
+
+// AdminUser is a user of the admin API along with its role.
+type AdminUser struct {
+	Username string
+	Role     AdminRole
+}
+
+// AdminAuditEntry records a mutating action performed on the admin API.
+type AdminAuditEntry struct {
+	Username   string
+	Role       AdminRole
+	Method     string
+	Path       string
+	Body       string
+	StatusCode int
+	Time       time.Time
+}
+
+// SetAdminUser inserts or updates the admin user @username. The password is hashed in postgres
+// using pgcrypto, so it is never stored in clear.
+func (rdb *RelDB) SetAdminUser(username string, password string, role AdminRole) error {
+	if !role.Valid() {
+		return fmt.Errorf("unknown admin role %s", role)
+	}
+	query := fmt.Sprintf(`insert into %s (username,password_hash,role) values ($1,crypt($2,gen_salt('bf')),$3)
+	on conflict (username) do update set password_hash=excluded.password_hash, role=excluded.role`, adminuserTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, username, password, string(role))
+	return err
+}
+
+// SetAdminUserRole changes the role of the existing admin user @username.
+func (rdb *RelDB) SetAdminUserRole(username string, role AdminRole) error {
+	if !role.Valid() {
+		return fmt.Errorf("unknown admin role %s", role)
+	}
+	query := fmt.Sprintf("update %s set role=$1 where username=$2", adminuserTable)
+	resp, err := rdb.postgresClient.Exec(context.Background(), query, string(role), username)
+	if err != nil {
+		return err
+	}
+	if resp.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetAdminUser returns the admin user @username.
+func (rdb *RelDB) GetAdminUser(username string) (user AdminUser, err error) {
+	query := fmt.Sprintf("select username,role from %s where username=$1", adminuserTable)
+	var role string
+	err = rdb.postgresClient.QueryRow(context.Background(), query, username).Scan(&user.Username, &role)
+	if err != nil {
+		return
+	}
+	user.Role = AdminRole(role)
+	return
+}
+
+// VerifyAdminUser returns the admin user @username if @password matches the stored hash.
+func (rdb *RelDB) VerifyAdminUser(username string, password string) (user AdminUser, err error) {
+	query := fmt.Sprintf("select username,role from %s where username=$1 and password_hash=crypt($2,password_hash)", adminuserTable)
+	var role string
+	err = rdb.postgresClient.QueryRow(context.Background(), query, username, password).Scan(&user.Username, &role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errors.New("invalid credentials")
+		}
+		return
+	}
+	user.Role = AdminRole(role)
+	return
+}
+
+// GetAdminUsers returns all admin users.
+func (rdb *RelDB) GetAdminUsers() (users []AdminUser, err error) {
+	query := fmt.Sprintf("select username,role from %s order by username", adminuserTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user AdminUser
+		var role string
+		err = rows.Scan(&user.Username, &role)
+		if err != nil {
+			return
+		}
+		user.Role = AdminRole(role)
+		users = append(users, user)
+	}
+	return
+}
+
+// DeleteAdminUser removes the admin user @username.
+func (rdb *RelDB) DeleteAdminUser(username string) error {
+	query := fmt.Sprintf("delete from %s where username=$1", adminuserTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, username)
+	return err
+}
+
+// SetAdminAuditEntry stores @entry in the admin audit trail.
+func (rdb *RelDB) SetAdminAuditEntry(entry AdminAuditEntry) error {
+	query := fmt.Sprintf("insert into %s (username,role,method,path,body,status_code,action_time) values ($1,$2,$3,$4,$5,$6,$7)", adminauditTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, entry.Username, string(entry.Role), entry.Method, entry.Path, entry.Body, entry.StatusCode, entry.Time)
+	return err
+}
+
+// GetAdminAuditEntries returns all audit entries in the given time range, most recent first.
+// If @username is empty, entries of all users are returned.
+func (rdb *RelDB) GetAdminAuditEntries(username string, starttime time.Time, endtime time.Time) (entries []AdminAuditEntry, err error) {
+	var rows pgx.Rows
+	if username == "" {
+		query := fmt.Sprintf("select username,role,method,path,body,status_code,action_time from %s where action_time>=$1 and action_time<=$2 order by action_time desc", adminauditTable)
+		rows, err = rdb.postgresClient.Query(context.Background(), query, starttime, endtime)
+	} else {
+		query := fmt.Sprintf("select username,role,method,path,body,status_code,action_time from %s where username=$1 and action_time>=$2 and action_time<=$3 order by action_time desc", adminauditTable)
+		rows, err = rdb.postgresClient.Query(context.Background(), query, username, starttime, endtime)
+	}
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry AdminAuditEntry
+		var role string
+		err = rows.Scan(&entry.Username, &role, &entry.Method, &entry.Path, &entry.Body, &entry.StatusCode, &entry.Time)
+		if err != nil {
+			return
+		}
+		entry.Role = AdminRole(role)
+		entries = append(entries, entry)
+	}
+	return
+}
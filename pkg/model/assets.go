@@ -0,0 +1,110 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// AssetInfo is an asset of the asset table along with the exchanges it is traded on.
+type AssetInfo struct {
+	dia.Asset
+	Exchanges []string
+}
+
+// AssetsPage is a page of the asset catalogue.
+type AssetsPage struct {
+	Assets []AssetInfo
+	// NextCursor requests the following page. It is empty on the last page.
+	NextCursor string
+}
+
+// AssetKey identifies an asset in the order of GetAssets. The zero key precedes all assets.
+type AssetKey struct {
+	Blockchain string
+	Address    string
+}
+
+// GetAssets returns at most @limit assets following @after, ordered by blockchain and address.
+// The exchanges of an asset are those with a verified symbol or pair mapped to it.
+func (rdb *RelDB) GetAssets(after AssetKey, limit int) (assets []AssetInfo, err error) {
+	query := fmt.Sprintf(`select a.symbol,a.name,a.address,coalesce(a.decimals,''),coalesce(a.blockchain,''),
+	coalesce(array_agg(distinct e.exchange) filter (where e.exchange is not null),'{}')
+	from %s a left join (
+		select asset_id,exchange from %s where verified
+		union select id_basetoken,exchange from %s where verified
+	) e on e.asset_id=a.asset_id
+	where (coalesce(a.blockchain,''),a.address)>($1,$2)
+	group by a.asset_id,a.symbol,a.name,a.address,a.decimals,a.blockchain
+	order by coalesce(a.blockchain,''),a.address
+	limit $3`, assetTable, exchangesymbolTable, exchangepairTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, after.Blockchain, after.Address, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var asset AssetInfo
+		var decimals string
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain, &asset.Exchanges)
+		if err != nil {
+			return
+		}
+		// Decimals are unknown for some assets, in which case they are left zero.
+		if d, err := strconv.ParseUint(decimals, 10, 8); err == nil {
+			asset.Decimals = uint8(d)
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
+
+// AssetMatch is an asset matching a search query. Matches of lower Rank are better: 0 for an
+// exact symbol or address, 1 for a symbol prefix, 2 for a name or address prefix, 3 for a
+// substring and 4 for a similar symbol or name.
+type AssetMatch struct {
+	dia.Asset
+	Rank      int
+	VolumeUSD float64
+}
+
+// SearchAssets returns at most @limit assets whose symbol, name or address match @query, best
+// matches first. Symbols and names also match if they are similar to @query by trigram.
+func (rdb *RelDB) SearchAssets(query string, limit int) (matches []AssetMatch, err error) {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query)
+	sqlQuery := fmt.Sprintf(`select symbol,name,address,coalesce(decimals,''),coalesce(blockchain,''),
+	case
+		when upper(symbol)=upper($1) or lower(address)=lower($1) then 0
+		when symbol ilike $2 then 1
+		when name ilike $2 or address ilike $2 then 2
+		when symbol ilike $3 or name ilike $3 then 3
+		else 4
+	end as rank
+	from %s
+	where symbol ilike $3 or name ilike $3 or address ilike $2 or symbol %% $1 or name %% $1
+	order by rank,greatest(similarity(symbol,$1),similarity(name,$1)) desc,symbol,blockchain
+	limit $4`, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), sqlQuery, query, escaped+"%", "%"+escaped+"%", limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var match AssetMatch
+		var decimals string
+		err = rows.Scan(&match.Symbol, &match.Name, &match.Address, &decimals, &match.Blockchain, &match.Rank)
+		if err != nil {
+			return
+		}
+		if d, err := strconv.ParseUint(decimals, 10, 8); err == nil {
+			match.Decimals = uint8(d)
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// BasisPoint is the basis between the DIA spot price of an asset and the mark price of a
+// perpetual swap on a derivatives venue.
+type BasisPoint struct {
+	Asset      string
+	Venue      string
+	Instrument string
+	SpotPrice  float64
+	MarkPrice  float64
+	// Basis is (MarkPrice-SpotPrice)/SpotPrice.
+	Basis float64
+	// AnnualizedBasis is the basis scaled to one year using the funding interval of the perp.
+	AnnualizedBasis float64
+	FundingRate     float64
+	Time            time.Time
+}
+
+// SaveBasisInflux stores @bp in influx.
+func (db *DB) SaveBasisInflux(bp BasisPoint) error {
+	fields := map[string]interface{}{
+		"spotPrice":       bp.SpotPrice,
+		"markPrice":       bp.MarkPrice,
+		"basis":           bp.Basis,
+		"annualizedBasis": bp.AnnualizedBasis,
+		"fundingRate":     bp.FundingRate,
+	}
+	tags := map[string]string{
+		"asset":      bp.Asset,
+		"venue":      bp.Venue,
+		"instrument": bp.Instrument,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbBasisTable, tags, fields, bp.Time)
+	if err != nil {
+		log.Errorln("SaveBasisInflux:", err)
+	} else {
+		db.addPoint(pt)
+	}
+	err = db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("Write influx batch: ", err)
+	}
+	return err
+}
+
+// GetBasisInflux returns all basis points of @asset in the given time range, oldest first.
+// If @venue is empty, basis points of all venues are returned.
+func (db *DB) GetBasisInflux(asset string, venue string, starttime time.Time, endtime time.Time) ([]BasisPoint, error) {
+	retval := []BasisPoint{}
+	q := fmt.Sprintf("SELECT spotPrice,markPrice,basis,annualizedBasis,fundingRate,\"venue\",\"instrument\" FROM %s WHERE \"asset\"='%s' AND time>=%d AND time<=%d", influxDbBasisTable, asset, starttime.UnixNano(), endtime.UnixNano())
+	if venue != "" {
+		q += fmt.Sprintf(" AND \"venue\"='%s'", venue)
+	}
+	q += " ORDER BY time ASC"
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return retval, err
+	}
+
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, vals := range res[0].Series[0].Values {
+			var bp BasisPoint
+			bp.Asset = asset
+			bp.Time, err = time.Parse(time.RFC3339, vals[0].(string))
+			if err != nil {
+				return retval, err
+			}
+			bp.SpotPrice, _ = vals[1].(json.Number).Float64()
+			bp.MarkPrice, _ = vals[2].(json.Number).Float64()
+			bp.Basis, _ = vals[3].(json.Number).Float64()
+			bp.AnnualizedBasis, _ = vals[4].(json.Number).Float64()
+			bp.FundingRate, _ = vals[5].(json.Number).Float64()
+			bp.Venue, _ = vals[6].(string)
+			bp.Instrument, _ = vals[7].(string)
+			retval = append(retval, bp)
+		}
+	}
+	return retval, nil
+}
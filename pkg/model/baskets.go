@@ -0,0 +1,111 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Basket is a weighted basket of assets. Its index value is BaseValue at BaseTime and moves with
+// the prices of its constituents relative to their prices at BaseTime.
+type Basket struct {
+	ID           string
+	Name         string
+	BaseValue    float64
+	BaseTime     time.Time
+	Creator      string `json:",omitempty"`
+	Constituents []BasketConstituent
+}
+
+// BasketConstituent is an asset in a basket. Weights of a basket sum to one.
+type BasketConstituent struct {
+	Symbol    string
+	Weight    float64
+	BasePrice float64
+}
+
+// BasketValue is the index value of a basket at Time, along with the prices of its constituents.
+type BasketValue struct {
+	ID     string
+	Name   string
+	Value  float64
+	Time   time.Time
+	Prices map[string]float64 `json:",omitempty"`
+}
+
+// BasketHistory is the index value of a basket at each interval of a window.
+type BasketHistory struct {
+	ID        string
+	Name      string
+	Interval  string
+	StartTime time.Time
+	EndTime   time.Time
+	Values    []BasketValue
+}
+
+// Value returns the index value of @basket at @prices, which must hold the price of every
+// constituent.
+func (basket *Basket) Value(prices map[string]float64) float64 {
+	var value float64
+	for _, constituent := range basket.Constituents {
+		value += constituent.Weight * prices[constituent.Symbol] / constituent.BasePrice
+	}
+	return basket.BaseValue * value
+}
+
+// SetBasket stores @basket with its constituents and returns its ID.
+func (rdb *RelDB) SetBasket(basket Basket) (string, error) {
+	tx, err := rdb.postgresClient.Begin(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(context.Background())
+
+	var id string
+	query := fmt.Sprintf("insert into %s (name,base_value,base_time,creator) values ($1,$2,$3,$4) returning basket_id::text", basketTable)
+	err = tx.QueryRow(context.Background(), query, basket.Name, basket.BaseValue, basket.BaseTime, basket.Creator).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	query = fmt.Sprintf("insert into %s (basket_id,symbol,weight,base_price) values ($1,$2,$3,$4)", basketconstTable)
+	for _, constituent := range basket.Constituents {
+		_, err = tx.Exec(context.Background(), query, id, constituent.Symbol, constituent.Weight, constituent.BasePrice)
+		if err != nil {
+			return "", err
+		}
+	}
+	return id, tx.Commit(context.Background())
+}
+
+// GetBasket returns the basket with @id and its constituents. It returns pgx.ErrNoRows if there
+// is no such basket.
+func (rdb *RelDB) GetBasket(id string) (basket Basket, err error) {
+	query := fmt.Sprintf("select basket_id::text,name,base_value,base_time,coalesce(creator,'') from %s where basket_id::text=$1", basketTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, id).Scan(
+		&basket.ID,
+		&basket.Name,
+		&basket.BaseValue,
+		&basket.BaseTime,
+		&basket.Creator,
+	)
+	if err != nil {
+		return
+	}
+
+	query = fmt.Sprintf("select symbol,weight,base_price from %s where basket_id::text=$1 order by weight desc,symbol", basketconstTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var constituent BasketConstituent
+		err = rows.Scan(&constituent.Symbol, &constituent.Weight, &constituent.BasePrice)
+		if err != nil {
+			return
+		}
+		basket.Constituents = append(basket.Constituents, constituent)
+	}
+	return
+}
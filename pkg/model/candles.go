@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CandleIntervals are the supported intervals of candles and their durations.
+var CandleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// Candle holds the open, high, low and close USD price and the traded volume of an asset in
+// the interval starting at Time. Volume is the sum of the absolute amounts of all trades.
+type Candle struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// GetCandles returns the candles of @symbol at @interval from @starttime until before @endtime,
+// oldest first, computed from the estimated USD prices of its trades. Only trades of
+// @exchanges are taken into account, or those of all exchanges if it is empty. Intervals
+// without trades are omitted.
+func (db *DB) GetCandles(symbol string, exchanges []string, interval string, starttime time.Time, endtime time.Time) ([]Candle, error) {
+	if _, ok := CandleIntervals[interval]; !ok {
+		return nil, fmt.Errorf("unsupported interval %s", interval)
+	}
+	where := fmt.Sprintf("symbol='%s' AND estimatedUSDPrice>0 AND time>=%d AND time<%d", symbol, starttime.UnixNano(), endtime.UnixNano())
+	if len(exchanges) > 0 {
+		conditions := make([]string, len(exchanges))
+		for i, exchange := range exchanges {
+			conditions[i] = "exchange='" + exchange + "'"
+		}
+		where += " AND (" + strings.Join(conditions, " OR ") + ")"
+	}
+	groupBy := "GROUP BY time(" + interval + ") fill(none)"
+	// The volume of sells is negative, so buys and sells are summed separately.
+	q := fmt.Sprintf("SELECT FIRST(estimatedUSDPrice),MAX(estimatedUSDPrice),MIN(estimatedUSDPrice),LAST(estimatedUSDPrice) FROM %s WHERE %s %s;", influxDbTradesTable, where, groupBy) +
+		fmt.Sprintf("SELECT SUM(volume) FROM %s WHERE %s AND volume>0 %s;", influxDbTradesTable, where, groupBy) +
+		fmt.Sprintf("SELECT SUM(volume) FROM %s WHERE %s AND volume<0 %s", influxDbTradesTable, where, groupBy)
+
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != 3 {
+		return nil, fmt.Errorf("expected 3 results, got %d", len(res))
+	}
+
+	candles := make(map[int64]*Candle)
+	if len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			t, values, err := parseCandleRow(row)
+			if err != nil {
+				return nil, err
+			}
+			candles[t.Unix()] = &Candle{Time: t, Open: values[0], High: values[1], Low: values[2], Close: values[3]}
+		}
+	}
+	for _, volumes := range res[1:] {
+		if len(volumes.Series) == 0 {
+			continue
+		}
+		for _, row := range volumes.Series[0].Values {
+			t, values, err := parseCandleRow(row)
+			if err != nil {
+				return nil, err
+			}
+			if candle, ok := candles[t.Unix()]; ok {
+				if values[0] < 0 {
+					values[0] = -values[0]
+				}
+				candle.Volume += values[0]
+			}
+		}
+	}
+
+	result := make([]Candle, 0, len(candles))
+	for _, candle := range candles {
+		result = append(result, *candle)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
+
+// parseCandleRow returns the time and the values of a row of an aggregating query.
+func parseCandleRow(row []interface{}) (time.Time, []float64, error) {
+	timeStr, ok := row[0].(string)
+	if !ok {
+		return time.Time{}, nil, fmt.Errorf("invalid time in row %v", row)
+	}
+	t, err := time.Parse(time.RFC3339, timeStr)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	values := make([]float64, len(row)-1)
+	for i, v := range row[1:] {
+		number, ok := v.(json.Number)
+		if !ok {
+			return time.Time{}, nil, fmt.Errorf("invalid value in row %v", row)
+		}
+		values[i], err = number.Float64()
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+	}
+	return t, values, nil
+}
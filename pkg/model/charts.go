@@ -116,3 +116,51 @@ func (db *DB) GetLastPriceBefore(symbol string, filter string, exchange string,
 
 	return price, err
 }
+
+// QuotationResolutions are the resolutions of the downsampled filter tables, see
+// scripts/influxdb.sh.
+var QuotationResolutions = []string{"5m", "30m", "1h", "4h", "1d", "1w"}
+
+// QuotationResolutionDurations are the durations of QuotationResolutions.
+var QuotationResolutionDurations = map[string]time.Duration{
+	"5m":  5 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
+}
+
+// GetQuotationRange returns at most @limit prices of @symbol at @resolution from @starttime until
+// before @endtime, oldest first. The prices are the means of the MA120 filter across all
+// exchanges, which the quotations are taken from.
+func (db *DB) GetQuotationRange(symbol string, resolution string, starttime time.Time, endtime time.Time, limit int) ([]PricePoint, error) {
+	q := fmt.Sprintf("SELECT value FROM a_year.filters_mean_%s WHERE filter='MA120' and exchange='' and symbol='%s' and time>=%d and time<%d ORDER BY ASC LIMIT %d",
+		resolution, symbol, starttime.UnixNano(), endtime.UnixNano(), limit)
+
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []PricePoint{}
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			t, err := time.Parse(time.RFC3339, row[0].(string))
+			if err != nil {
+				return nil, fmt.Errorf("parse time of %v: %v", row, err)
+			}
+			v, ok := row[1].(json.Number)
+			if !ok {
+				// Empty intervals of the downsampled tables have no value.
+				continue
+			}
+			price, err := v.Float64()
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, PricePoint{Time: t, Price: price})
+		}
+	}
+	return points, nil
+}
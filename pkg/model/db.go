@@ -45,12 +45,24 @@ type Datastore interface {
 	GetLastTrades(symbol string, exchange string, maxTrades int) ([]dia.Trade, error)
 	GetLastTradesAllExchanges(string, int) ([]dia.Trade, error)
 	GetAllTrades(t time.Time, maxTrades int) ([]dia.Trade, error)
+	GetTradesByTimerange(symbol string, starttime time.Time, endtime time.Time) ([]dia.Trade, error)
+	GetForeignTradeIDs(exchange string, pair string, starttime time.Time, endtime time.Time) (map[string]struct{}, error)
+	MarkTradeSeen(key string, ttl time.Duration) (bool, error)
+	AcquireLease(name string, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(name string, holder string) error
 	Flush() error
 	GetFilterPoints(filter string, exchange string, symbol string, scale string, starttime time.Time, endtime time.Time) (*Points, error)
+	GetQuotationRange(symbol string, resolution string, starttime time.Time, endtime time.Time, limit int) ([]PricePoint, error)
+	GetCandles(symbol string, exchanges []string, interval string, starttime time.Time, endtime time.Time) ([]Candle, error)
 	SetFilter(filterName string, symbol string, exchange string, value float64, t time.Time) error
 	GetLastPriceBefore(symbol string, filter string, exchange string, timestamp time.Time) (Price, error)
 	SetAvailablePairsForExchange(exchange string, pairs []dia.Pair) error
 	GetAvailablePairsForExchange(exchange string) ([]dia.Pair, error)
+	SetPairMetaForExchange(exchange string, meta []dia.PairMeta) error
+	GetPairMetaForExchange(exchange string) ([]dia.PairMeta, error)
+	SetPoolVolume(volume dia.PoolVolume) error
+	SetRouteRules(asset string, rules []dia.RouteRule) error
+	GetRouteRules(asset string) ([]dia.RouteRule, error)
 	SetCurrencyChange(cc *Change) error
 	GetCurrencyChange() (*Change, error)
 	GetAllSymbols() []string
@@ -60,11 +72,39 @@ type Datastore interface {
 	UpdateSymbolDetails(symbol string, rank int)
 	GetConfigTogglePairDiscovery() (bool, error)
 	GetExchanges() []string
+	GetExchangeStatus() ([]ExchangeStatus, error)
+	GetFilterStatus() ([]FilterStatus, error)
+	SaveOrderBookDepthInflux(depth dia.OrderBookDepth) error
+	GetOrderBookDepths(symbol string) ([]dia.OrderBookDepth, error)
+	SaveFundingRatesInflux(rates []dia.FundingRate) error
+	GetLatestFundingRates(symbol string, exchanges []string) ([]dia.FundingRate, error)
+	GetFundingRates(symbol string, exchanges []string, starttime time.Time, endtime time.Time) ([]dia.FundingRate, error)
+	SaveOpenInterestInflux(openInterests []dia.OpenInterest) error
+	GetLatestOpenInterest(symbol string, exchanges []string) ([]dia.OpenInterest, error)
+	GetOpenInterest(symbol string, exchanges []string, starttime time.Time, endtime time.Time) ([]dia.OpenInterest, error)
+	SavePoolLiquidityInflux(liquidities []dia.PoolLiquidity) error
+	GetLatestPoolLiquidity(exchanges []string, symbol string) ([]dia.PoolLiquidity, error)
+	GetPoolLiquidity(exchange string, pool string, starttime time.Time, endtime time.Time) ([]dia.PoolLiquidity, error)
+	SavePerpetualTradeInflux(trade dia.PerpetualTrade) error
+	SavePerpetualPriceInflux(price dia.PerpetualPrice) error
+	SaveOptionTradeInflux(trade dia.OptionTrade) error
+	SaveOptionMarketDatumInflux(datum dia.OptionMarketDatum) error
+	GetOptionMarketData(baseCurrency string, exchanges []string, timestamp time.Time) ([]dia.OptionMarketDatum, error)
+	SaveStakingYieldInflux(yield dia.StakingYield) error
+	GetLatestStakingYields(asset string) ([]dia.StakingYield, error)
+	GetStakingYields(asset string, starttime time.Time, endtime time.Time) ([]dia.StakingYield, error)
+	SaveLiquidStakingRateInflux(rate dia.LiquidStakingRate) error
+	GetLatestLiquidStakingRate(token string) (*dia.LiquidStakingRate, error)
+	GetLiquidStakingRates(token string, starttime time.Time, endtime time.Time) ([]dia.LiquidStakingRate, error)
+	SaveGasPriceInflux(gasPrice GasPrice) error
+	GetGasPrices(blockchain string, starttime time.Time, endtime time.Time) ([]GasPrice, error)
+	GetVWAP(symbol string, exchanges []string, excluded []string, starttime time.Time, endtime time.Time) (*VWAP, error)
 	SetOptionMeta(optionMeta *dia.OptionMeta) error
 	GetOptionMeta(baseCurrency string) ([]dia.OptionMeta, error)
 	SaveCVIInflux(float64, time.Time) error
 	GetCVIInflux(time.Time, time.Time, string) ([]dia.CviDataPoint, error)
 	GetSupplyInflux(string, time.Time, time.Time) ([]dia.Supply, error)
+	GetSupplyHistoryInflux(string, time.Time, time.Time) ([]dia.Supply, error)
 	GetVolumeInflux(string, time.Time, time.Time) (float64, error)
 	// Get24Volume(symbol string, exchange string) (float64, error)
 	// Get24VolumeExchange(exchange string) (float64, error)
@@ -97,6 +137,10 @@ type Datastore interface {
 
 	GetDefiRateInflux(time.Time, time.Time, string, string) ([]dia.DefiRate, error)
 	SetDefiRateInflux(rate *dia.DefiRate) error
+	GetLendingRates(protocol string, blockchain string, asset string, starttime time.Time, endtime time.Time) ([]dia.DefiRate, error)
+	GetLatestLendingRates(protocol string, blockchain string, starttime time.Time) ([]dia.DefiRate, error)
+	SaveLendingCollateralInflux(collaterals []dia.LendingCollateral) error
+	GetLatestLendingCollateral(protocol string, blockchain string, starttime time.Time) ([]dia.LendingCollateral, error)
 
 	GetDefiStateInflux(time.Time, time.Time, string) ([]dia.DefiProtocolState, error)
 	SetDefiStateInflux(state *dia.DefiProtocolState) error
@@ -132,6 +176,10 @@ type Datastore interface {
 	SetStockQuotation(sq StockQuotation) error
 	GetStockQuotation(source string, symbol string, timeInit time.Time, timeFinal time.Time) ([]StockQuotation, error)
 	GetStockSymbols() (map[Stock]string, error)
+
+	// Basis methods
+	SaveBasisInflux(bp BasisPoint) error
+	GetBasisInflux(asset string, venue string, starttime time.Time, endtime time.Time) ([]BasisPoint, error)
 }
 
 const (
@@ -151,17 +199,30 @@ const (
 	influxDbTradesTable                  = "trades"
 	influxDbFiltersTable                 = "filters"
 	influxDbOptionsTable                 = "options"
+	influxDbOptionTradesTable            = "optionTrades"
+	influxDbOptionMarketTable            = "optionMarketData"
 	influxDbCVITable                     = "cvi"
 	influxDbETHCVITable                  = "cviETH"
 	influxDbSupplyTable                  = "supplies"
 	influxDbSupplyTableOld               = "supply"
 	influxDbDefiRateTable                = "defiRate"
+	influxDbLendingCollateralTable       = "lendingCollateral"
 	influxDbDefiStateTable               = "defiState"
 	influxDbPoolTable                    = "defiPools"
 	influxDbCryptoIndexTable             = "cryptoindex"
 	influxDbCryptoIndexConstituentsTable = "cryptoindexconstituents"
 	influxDbGithubCommitTable            = "githubcommits"
 	influxDbStockQuotationsTable         = "stockquotations"
+	influxDbBasisTable                   = "basis"
+	influxDbOrderBookDepthTable          = "orderbookDepth"
+	influxDbFundingRateTable             = "fundingRates"
+	influxDbOpenInterestTable            = "openInterest"
+	influxDbPoolLiquidityTable           = "poolLiquidity"
+	influxDbPerpetualTradesTable         = "perpetualTrades"
+	influxDbPerpetualPricesTable         = "perpetualPrices"
+	influxDbStakingYieldTable            = "stakingYields"
+	influxDbLiquidStakingRateTable       = "liquidStakingRates"
+	influxDbGasPriceTable                = "gasPrices"
 )
 
 // queryInfluxDB convenience function to query the database
@@ -210,14 +271,8 @@ func NewDataStoreWithOptions(withRedis bool, withInflux bool) (*DB, error) {
 	address := ""
 
 	if withRedis {
-		// Run localhost for testing and server for production
-		if executionMode == "production" {
-			address = "redis:6379"
-		} else {
-			address = "localhost:6379"
-		}
 		r = redis.NewClient(&redis.Options{
-			Addr:     address,
+			Addr:     RedisAddress(),
 			Password: "", // no password set
 			DB:       0,  // use default DB
 		})
@@ -251,6 +306,32 @@ func NewDataStoreWithOptions(withRedis bool, withInflux bool) (*DB, error) {
 	return &DB{r, ci, bp, 0}, nil
 }
 
+// RedisAddress returns the address of the redis server, localhost for testing and the redis
+// service in production.
+func RedisAddress() string {
+	if os.Getenv("EXEC_MODE") == "production" {
+		return "redis:6379"
+	}
+	return "localhost:6379"
+}
+
+// PingRedis returns an error if the redis server of @db isn't reachable.
+func (db *DB) PingRedis() error {
+	if db.redisClient == nil {
+		return errors.New("no redis client")
+	}
+	return db.redisClient.Ping().Err()
+}
+
+// PingInflux returns an error if the influx server of @db isn't reachable.
+func (db *DB) PingInflux() error {
+	if db.influxClient == nil {
+		return errors.New("no influx client")
+	}
+	_, _, err := db.influxClient.Ping(10 * time.Second)
+	return err
+}
+
 func createBatchInflux() clientInfluxdb.BatchPoints {
 	bp, err := clientInfluxdb.NewBatchPoints(clientInfluxdb.BatchPointsConfig{
 		Database:  influxDbName,
@@ -453,6 +534,9 @@ func (db *DB) SaveTradeInflux(t *dia.Trade) error {
 		"estimatedUSDPrice": t.EstimatedUSDPrice,
 		"foreignTradeID":    t.ForeignTradeID,
 	}
+	if t.VerificationStatus != "" {
+		fields["verification"] = t.VerificationStatus
+	}
 
 	pt, err := clientInfluxdb.NewPoint(influxDbTradesTable, tags, fields, t.Time)
 	if err != nil {
@@ -467,9 +551,9 @@ func (db *DB) GetTradeInflux(symbol string, exchange string, timestamp time.Time
 	retval := dia.Trade{}
 	var q string
 	if exchange != "" {
-		q = fmt.Sprintf("SELECT * FROM %s WHERE symbol='%s' and echange='%s' and time < %d order by desc limit 1", influxDbTradesTable, symbol, exchange, timestamp.UnixNano())
+		q = fmt.Sprintf("SELECT %s FROM %s WHERE symbol='%s' and echange='%s' and time < %d order by desc limit 1", tradeColumns, influxDbTradesTable, symbol, exchange, timestamp.UnixNano())
 	} else {
-		q = fmt.Sprintf("SELECT * FROM %s WHERE symbol='%s' and time < %d order by desc limit 1", influxDbTradesTable, symbol, timestamp.UnixNano())
+		q = fmt.Sprintf("SELECT %s FROM %s WHERE symbol='%s' and time < %d order by desc limit 1", tradeColumns, influxDbTradesTable, symbol, timestamp.UnixNano())
 	}
 
 	/// TODO
@@ -782,6 +866,12 @@ func (db *DB) SetDefiRateInflux(rate *dia.DefiRate) error {
 		"asset":    rate.Asset,
 		"protocol": rate.Protocol,
 	}
+	if rate.Blockchain != "" {
+		tags["blockchain"] = rate.Blockchain
+	}
+	if rate.Utilization != 0 {
+		fields["utilization"] = rate.Utilization
+	}
 	pt, err := clientInfluxdb.NewPoint(influxDbDefiRateTable, tags, fields, rate.Timestamp)
 	if err != nil {
 		log.Errorln("SetDefiRateInflux:", err)
@@ -935,34 +1025,59 @@ func (db *DB) GetSupplyInflux(symbol string, starttime time.Time, endtime time.T
 		return retval, err
 	}
 	if len(res) > 0 && len(res[0].Series) > 0 {
-		for i := 0; i < len(res[0].Series[0].Values); i++ {
-			currentSupply := dia.Supply{}
-			currentSupply.Time, err = time.Parse(time.RFC3339, res[0].Series[0].Values[i][0].(string))
-			if err != nil {
-				return retval, err
-			}
-			currentSupply.Supply, err = res[0].Series[0].Values[i][1].(json.Number).Float64()
-			if err != nil {
-				return retval, err
-			}
-			currentSupply.CirculatingSupply, err = res[0].Series[0].Values[i][2].(json.Number).Float64()
-			if err != nil {
-				return retval, err
-			}
-			currentSupply.Source = res[0].Series[0].Values[i][3].(string)
-			if err != nil {
-				return retval, err
-			}
-			currentSupply.Name = res[0].Series[0].Values[i][4].(string)
-			if err != nil {
-				log.Error("error getting symbol name from influx: ", err)
-			}
+		return parseSupplies(symbol, res[0].Series[0].Values)
+	}
+	return retval, errors.New("Error parsing Supply value from Database")
+}
 
-			currentSupply.Symbol = symbol
-			retval = append(retval, currentSupply)
+// GetSupplyHistoryInflux returns the supplies of @symbol recorded from @starttime to @endtime
+// in ascending order. The first supply is the latest one before @starttime, if any, as it was
+// still valid at @starttime.
+func (db *DB) GetSupplyHistoryInflux(symbol string, starttime time.Time, endtime time.Time) ([]dia.Supply, error) {
+	q := fmt.Sprintf("SELECT supply,circulatingsupply,source,\"name\" FROM %s WHERE time <= %d and \"symbol\" = '%s' ORDER BY time DESC LIMIT 1;"+
+		"SELECT supply,circulatingsupply,source,\"name\" FROM %s WHERE time > %d and time <= %d and \"symbol\" = '%s' ORDER BY time ASC",
+		influxDbSupplyTable, starttime.UnixNano(), symbol, influxDbSupplyTable, starttime.UnixNano(), endtime.UnixNano(), symbol)
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	supplies := []dia.Supply{}
+	for _, r := range res {
+		if len(r.Series) == 0 {
+			continue
 		}
-	} else {
-		return retval, errors.New("Error parsing Supply value from Database")
+		s, err := parseSupplies(symbol, r.Series[0].Values)
+		if err != nil {
+			return nil, err
+		}
+		supplies = append(supplies, s...)
+	}
+	return supplies, nil
+}
+
+// parseSupplies parses rows of the supply table with the columns time, supply,
+// circulatingsupply, source and name.
+func parseSupplies(symbol string, rows [][]interface{}) ([]dia.Supply, error) {
+	retval := []dia.Supply{}
+	for _, row := range rows {
+		var err error
+		currentSupply := dia.Supply{Symbol: symbol}
+		currentSupply.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return retval, err
+		}
+		currentSupply.Supply, err = row[1].(json.Number).Float64()
+		if err != nil {
+			return retval, err
+		}
+		currentSupply.CirculatingSupply, err = row[2].(json.Number).Float64()
+		if err != nil {
+			return retval, err
+		}
+		currentSupply.Source, _ = row[3].(string)
+		// The name is missing for supplies recorded before it was added.
+		currentSupply.Name, _ = row[4].(string)
+		retval = append(retval, currentSupply)
 	}
 	return retval, nil
 }
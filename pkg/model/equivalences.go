@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+// AssetEquivalence declares Asset a representation of the economic asset with the symbol
+// Canonical, e.g. WETH on Polygon or WETH.e on Avalanche of ETH.
+type AssetEquivalence struct {
+	Canonical string
+	Asset     dia.Asset
+}
+
+// SetAssetEquivalence declares @asset, identified by its blockchain and address, a
+// representation of @canonical. An asset belongs to one canonical symbol at most, so a previous
+// declaration is replaced. The asset must be in the asset table.
+func (rdb *RelDB) SetAssetEquivalence(canonical string, asset dia.Asset) error {
+	var assetID string
+	query := fmt.Sprintf("select asset_id::text from %s where address=$1 and blockchain=$2", assetTable)
+	err := rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&assetID)
+	if err != nil {
+		return fmt.Errorf("asset %s on %s: %w", asset.Address, asset.Blockchain, err)
+	}
+	query = fmt.Sprintf(`insert into %s (asset_id,canonical) values ($1::uuid,$2)
+	on conflict (asset_id) do update set canonical=excluded.canonical`, equivalenceTable)
+	_, err = rdb.postgresClient.Exec(context.Background(), query, assetID, canonical)
+	return err
+}
+
+// DeleteAssetEquivalence removes @asset from the representations of @canonical. It returns
+// pgx.ErrNoRows if @asset isn't a representation of @canonical.
+func (rdb *RelDB) DeleteAssetEquivalence(canonical string, asset dia.Asset) error {
+	query := fmt.Sprintf(`delete from %s where asset_id in (select asset_id from %s where address=$1 and blockchain=$2) and canonical=$3`,
+		equivalenceTable, assetTable)
+	resp, err := rdb.postgresClient.Exec(context.Background(), query, asset.Address, asset.Blockchain, canonical)
+	if err != nil {
+		return err
+	}
+	if resp.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetAssetEquivalences returns the representations of @canonical, or of all canonical symbols
+// if it is empty, ordered by canonical symbol, blockchain and address.
+func (rdb *RelDB) GetAssetEquivalences(canonical string) (equivalences []AssetEquivalence, err error) {
+	query := fmt.Sprintf(`select e.canonical,a.symbol,a.name,a.address,coalesce(a.decimals,''),coalesce(a.blockchain,'')
+	from %s e join %s a on a.asset_id=e.asset_id
+	where ($1='' or e.canonical=$1)
+	order by e.canonical,coalesce(a.blockchain,''),a.address`, equivalenceTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, canonical)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var equivalence AssetEquivalence
+		var decimals string
+		err = rows.Scan(
+			&equivalence.Canonical,
+			&equivalence.Asset.Symbol,
+			&equivalence.Asset.Name,
+			&equivalence.Asset.Address,
+			&decimals,
+			&equivalence.Asset.Blockchain,
+		)
+		if err != nil {
+			return
+		}
+		equivalence.Asset.Decimals = assetDecimals(decimals)
+		equivalences = append(equivalences, equivalence)
+	}
+	return equivalences, rows.Err()
+}
@@ -0,0 +1,254 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Statuses of the mapping of an exchange's symbols and pairs to assets.
+const (
+	// MappingVerified symbols are mapped to exactly one asset. Pairs are verified if both
+	// their symbols are.
+	MappingVerified = "verified"
+	// MappingAmbiguous symbols match several assets and need a curator to pick one. Pairs are
+	// ambiguous if one of their symbols is.
+	MappingAmbiguous = "ambiguous"
+	// MappingUnverified symbols match no asset.
+	MappingUnverified = "unverified"
+)
+
+// ExchangeSymbolMapping maps the symbol of an exchange to an asset. Asset is empty unless the
+// mapping is verified.
+type ExchangeSymbolMapping struct {
+	Exchange string
+	Symbol   string
+	Status   string
+	Asset    dia.Asset
+	// Curated mappings were set by a curator and are kept by the pair discovery.
+	Curated bool
+}
+
+// ExchangePairMapping maps a pair of an exchange to assets. As in dia.Trade, QuoteAsset is the
+// asset of Symbol and BaseAsset the asset of BaseSymbol, in which prices are given. Both are
+// empty unless the mapping is verified.
+type ExchangePairMapping struct {
+	Exchange    string
+	ForeignName string
+	Symbol      string
+	BaseSymbol  string
+	Status      string
+	QuoteAsset  dia.Asset
+	BaseAsset   dia.Asset
+}
+
+// mappingStatus returns the status of a mapping from its flags in postgres.
+func mappingStatus(verified bool, ambiguous bool) string {
+	switch {
+	case verified:
+		return MappingVerified
+	case ambiguous:
+		return MappingAmbiguous
+	default:
+		return MappingUnverified
+	}
+}
+
+// assetDecimals parses the decimals of an asset, which are unknown and left zero for some assets.
+func assetDecimals(decimals string) uint8 {
+	d, err := strconv.ParseUint(decimals, 10, 8)
+	if err != nil {
+		return 0
+	}
+	return uint8(d)
+}
+
+// SetExchangePairs stores the pairs of @exchange along with their symbols. The mappings of known
+// pairs and symbols are kept.
+func (rdb *RelDB) SetExchangePairs(exchange string, pairs []ExchangePairMapping) error {
+	pairQuery := fmt.Sprintf(`insert into %s (symbol,foreignname,exchange,basesymbol) values ($1,$2,$3,$4)
+	on conflict (foreignname,exchange) do update set symbol=excluded.symbol,basesymbol=excluded.basesymbol`, exchangepairTable)
+	symbolQuery := fmt.Sprintf("insert into %s (symbol,exchange) values ($1,$2) on conflict (symbol,exchange) do nothing", exchangesymbolTable)
+	for _, pair := range pairs {
+		_, err := rdb.postgresClient.Exec(context.Background(), pairQuery, pair.Symbol, pair.ForeignName, exchange, pair.BaseSymbol)
+		if err != nil {
+			return err
+		}
+		for _, symbol := range []string{pair.Symbol, pair.BaseSymbol} {
+			if symbol == "" {
+				continue
+			}
+			_, err = rdb.postgresClient.Exec(context.Background(), symbolQuery, symbol, exchange)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetExchangeSymbolMappings returns the symbol mappings of @exchange with @status, ordered by
+// exchange and symbol. Empty @exchange or @status match all mappings.
+func (rdb *RelDB) GetExchangeSymbolMappings(exchange string, status string) (mappings []ExchangeSymbolMapping, err error) {
+	query := fmt.Sprintf(`select s.exchange,s.symbol,coalesce(s.verified,false),coalesce(s.ambiguous,false),coalesce(s.curated,false),
+	coalesce(a.symbol,''),coalesce(a.name,''),coalesce(a.address,''),coalesce(a.decimals,''),coalesce(a.blockchain,'')
+	from %s s left join %s a on a.asset_id=s.asset_id and s.verified
+	where ($1='' or s.exchange=$1)
+	order by s.exchange,s.symbol`, exchangesymbolTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mapping ExchangeSymbolMapping
+		var verified, ambiguous bool
+		var decimals string
+		err = rows.Scan(
+			&mapping.Exchange,
+			&mapping.Symbol,
+			&verified,
+			&ambiguous,
+			&mapping.Curated,
+			&mapping.Asset.Symbol,
+			&mapping.Asset.Name,
+			&mapping.Asset.Address,
+			&decimals,
+			&mapping.Asset.Blockchain,
+		)
+		if err != nil {
+			return
+		}
+		mapping.Status = mappingStatus(verified, ambiguous)
+		if status != "" && mapping.Status != status {
+			continue
+		}
+		mapping.Asset.Decimals = assetDecimals(decimals)
+		mappings = append(mappings, mapping)
+	}
+	return mappings, rows.Err()
+}
+
+// SetExchangeSymbolMapping stores @mapping. The asset of a verified mapping is identified by
+// its blockchain and address and must be in the asset table.
+func (rdb *RelDB) SetExchangeSymbolMapping(mapping ExchangeSymbolMapping) error {
+	var assetID interface{}
+	if mapping.Status == MappingVerified {
+		var id string
+		query := fmt.Sprintf("select asset_id::text from %s where address=$1 and blockchain=$2", assetTable)
+		err := rdb.postgresClient.QueryRow(context.Background(), query, mapping.Asset.Address, mapping.Asset.Blockchain).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("asset %s on %s: %w", mapping.Asset.Address, mapping.Asset.Blockchain, err)
+		}
+		assetID = id
+	}
+	query := fmt.Sprintf(`insert into %s (symbol,exchange,verified,ambiguous,curated,asset_id) values ($1,$2,$3,$4,$5,$6::uuid)
+	on conflict (symbol,exchange) do update set verified=excluded.verified,ambiguous=excluded.ambiguous,curated=excluded.curated,asset_id=excluded.asset_id`, exchangesymbolTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query,
+		mapping.Symbol,
+		mapping.Exchange,
+		mapping.Status == MappingVerified,
+		mapping.Status == MappingAmbiguous,
+		mapping.Curated,
+		assetID,
+	)
+	return err
+}
+
+// GetAssetsBySymbol returns all assets with @symbol, regardless of case, ordered by blockchain
+// and address.
+func (rdb *RelDB) GetAssetsBySymbol(symbol string) (assets []dia.Asset, err error) {
+	query := fmt.Sprintf(`select symbol,name,address,coalesce(decimals,''),coalesce(blockchain,'')
+	from %s where upper(symbol)=upper($1)
+	order by coalesce(blockchain,''),address`, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, symbol)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var asset dia.Asset
+		var decimals string
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		asset.Decimals = assetDecimals(decimals)
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
+
+// UpdateExchangePairMappings maps the pairs of @exchange to the assets of their symbols. Pairs
+// are verified if both symbols are verified and ambiguous if one of them is ambiguous.
+func (rdb *RelDB) UpdateExchangePairMappings(exchange string) error {
+	query := fmt.Sprintf(`update %[1]s p set
+	id_quotetoken=(select asset_id from %[2]s s where s.exchange=p.exchange and s.symbol=p.symbol and s.verified),
+	id_basetoken=(select asset_id from %[2]s s where s.exchange=p.exchange and s.symbol=p.basesymbol and s.verified),
+	ambiguous=exists(select 1 from %[2]s s where s.exchange=p.exchange and s.symbol in (p.symbol,p.basesymbol) and s.ambiguous)
+	where p.exchange=$1`, exchangepairTable, exchangesymbolTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, exchange)
+	if err != nil {
+		return err
+	}
+	query = fmt.Sprintf("update %s set verified=(id_quotetoken is not null and id_basetoken is not null) where exchange=$1", exchangepairTable)
+	_, err = rdb.postgresClient.Exec(context.Background(), query, exchange)
+	return err
+}
+
+// GetExchangePairMappings returns the pair mappings of @exchange with @status, ordered by
+// exchange and foreign name. Empty @exchange or @status match all mappings.
+func (rdb *RelDB) GetExchangePairMappings(exchange string, status string) (mappings []ExchangePairMapping, err error) {
+	query := fmt.Sprintf(`select p.exchange,p.foreignname,p.symbol,coalesce(p.basesymbol,''),coalesce(p.verified,false),coalesce(p.ambiguous,false),
+	coalesce(q.symbol,''),coalesce(q.name,''),coalesce(q.address,''),coalesce(q.decimals,''),coalesce(q.blockchain,''),
+	coalesce(b.symbol,''),coalesce(b.name,''),coalesce(b.address,''),coalesce(b.decimals,''),coalesce(b.blockchain,'')
+	from %[1]s p
+	left join %[2]s q on q.asset_id=p.id_quotetoken and p.verified
+	left join %[2]s b on b.asset_id=p.id_basetoken and p.verified
+	where ($1='' or p.exchange=$1)
+	order by p.exchange,p.foreignname`, exchangepairTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mapping ExchangePairMapping
+		var verified, ambiguous bool
+		var quoteDecimals, baseDecimals string
+		err = rows.Scan(
+			&mapping.Exchange,
+			&mapping.ForeignName,
+			&mapping.Symbol,
+			&mapping.BaseSymbol,
+			&verified,
+			&ambiguous,
+			&mapping.QuoteAsset.Symbol,
+			&mapping.QuoteAsset.Name,
+			&mapping.QuoteAsset.Address,
+			&quoteDecimals,
+			&mapping.QuoteAsset.Blockchain,
+			&mapping.BaseAsset.Symbol,
+			&mapping.BaseAsset.Name,
+			&mapping.BaseAsset.Address,
+			&baseDecimals,
+			&mapping.BaseAsset.Blockchain,
+		)
+		if err != nil {
+			return
+		}
+		mapping.Status = mappingStatus(verified, ambiguous)
+		if status != "" && mapping.Status != status {
+			continue
+		}
+		mapping.QuoteAsset.Decimals = assetDecimals(quoteDecimals)
+		mapping.BaseAsset.Decimals = assetDecimals(baseDecimals)
+		mappings = append(mappings, mapping)
+	}
+	return mappings, rows.Err()
+}
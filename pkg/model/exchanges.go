@@ -1,7 +1,8 @@
 package models
 
 import (
-	// "encoding/json"
+	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +23,83 @@ func (db *DB) GetExchanges() (allExchanges []string) {
 	return
 }
 
+// States of an exchange in ExchangeStatus.
+const (
+	// ExchangeLive exchanges had a trade within exchangeStaleAfter.
+	ExchangeLive = "live"
+	// ExchangeStale exchanges had trades within the last 7 days, but none recently.
+	ExchangeStale = "stale"
+	// ExchangeInactive exchanges had no trades within the last 7 days.
+	ExchangeInactive = "inactive"
+)
+
+// exchangeStaleAfter is the time after the last trade of an exchange from which its scraper is
+// considered stale.
+const exchangeStaleAfter = 15 * time.Minute
+
+// ExchangeStatus tells how fresh the trades of an exchange are.
+type ExchangeStatus struct {
+	Name string
+	// LastTradeTime is the time of the latest trade within the last 7 days.
+	LastTradeTime *time.Time `json:",omitempty"`
+	Trades24h     int64
+	State         string
+}
+
+// GetExchangeStatus returns the status of all exchanges, computed from their trades of the last
+// 7 days.
+func (db *DB) GetExchangeStatus() ([]ExchangeStatus, error) {
+	q := fmt.Sprintf("SELECT LAST(price) FROM %s WHERE time > now() - 7d GROUP BY exchange;", influxDbTradesTable) +
+		fmt.Sprintf("SELECT COUNT(price) FROM %s WHERE time > now() - 1d GROUP BY exchange", influxDbTradesTable)
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != 2 {
+		return nil, fmt.Errorf("unexpected number of results %d", len(res))
+	}
+	lastTrades := make(map[string]time.Time)
+	for _, series := range res[0].Series {
+		if len(series.Values) == 0 {
+			continue
+		}
+		t, _, err := parseCandleRow(series.Values[0])
+		if err != nil {
+			return nil, err
+		}
+		lastTrades[series.Tags["exchange"]] = t
+	}
+	counts := make(map[string]int64)
+	for _, series := range res[1].Series {
+		if len(series.Values) == 0 || len(series.Values[0]) < 2 {
+			continue
+		}
+		if count, ok := series.Values[0][1].(json.Number); ok {
+			counts[series.Tags["exchange"]], _ = count.Int64()
+		}
+	}
+
+	now := time.Now()
+	var statuses []ExchangeStatus
+	for _, exchange := range db.GetExchanges() {
+		status := ExchangeStatus{Name: exchange, Trades24h: counts[exchange], State: ExchangeInactive}
+		if t, ok := lastTrades[exchange]; ok {
+			status.LastTradeTime = &t
+			status.State = exchangeState(t, now)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// exchangeState returns the state of an exchange with the last trade at @lastTrade.
+func exchangeState(lastTrade time.Time, now time.Time) string {
+	if now.Sub(lastTrade) > exchangeStaleAfter {
+		return ExchangeStale
+	}
+	return ExchangeLive
+}
+
 func getKeyLastTradeTimeForExchange(symbol string, exchange string) string {
 	if exchange == "" {
 		return "dia_TLT_" + symbol
@@ -106,3 +184,51 @@ func (db *DB) GetAvailablePairsForExchange(exchange string) ([]dia.Pair, error)
 	}
 	return p, nil
 }
+
+// SetPairMetaForExchange stores tick sizes, minimal order sizes and fee tiers of all pairs
+// available in the exchange in the internal redis db
+func (db *DB) SetPairMetaForExchange(exchange string, meta []dia.PairMeta) error {
+	key := "dia_pair_meta_" + exchange
+	var p dia.PairMetas = meta
+	return db.redisClient.Set(key, &p, 0).Err()
+}
+
+// GetPairMetaForExchange returns the trading conditions of all pairs available in the exchange
+func (db *DB) GetPairMetaForExchange(exchange string) ([]dia.PairMeta, error) {
+	key := "dia_pair_meta_" + exchange
+	p := dia.PairMetas{}
+	err := db.redisClient.Get(key).Scan(&p)
+	if err != nil {
+		log.Errorf("Error: %v on GetPairMetaForExchange %v\n", err, exchange)
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetPoolVolume stores the latest volume of a pool of a DEX. It expires after a day.
+func (db *DB) SetPoolVolume(volume dia.PoolVolume) error {
+	key := "dia_pool_volume_" + volume.Exchange + "_" + volume.Pool
+	return db.redisClient.Set(key, &volume, 24*time.Hour).Err()
+}
+
+// SetRouteRules stores the pin and forbid rules used for the pricing of @asset.
+func (db *DB) SetRouteRules(asset string, rules []dia.RouteRule) error {
+	key := "dia_route_rules_" + strings.ToUpper(asset)
+	if len(rules) == 0 {
+		return db.redisClient.Del(key).Err()
+	}
+	var r dia.RouteRules = rules
+	return db.redisClient.Set(key, &r, 0).Err()
+}
+
+// GetRouteRules returns the pin and forbid rules used for the pricing of @asset.
+// It returns redis.Nil if no rules are set.
+func (db *DB) GetRouteRules(asset string) ([]dia.RouteRule, error) {
+	key := "dia_route_rules_" + strings.ToUpper(asset)
+	r := dia.RouteRules{}
+	err := db.redisClient.Get(key).Scan(&r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -9,3 +10,34 @@ func (db *DB) SetFilter(filter string, symbol string, exchange string, volume fl
 	err := db.setZSETValue(getKeyFilterZSET(getKey(filter, symbol, exchange)), volume, t.Unix(), BiggestWindow)
 	return err
 }
+
+// FilterStatus tells when a filter was last computed.
+type FilterStatus struct {
+	Name     string
+	LastTime time.Time
+}
+
+// GetFilterStatus returns the time of the latest value of all filters computed within the last
+// 7 days.
+func (db *DB) GetFilterStatus() ([]FilterStatus, error) {
+	q := fmt.Sprintf("SELECT LAST(value) FROM %s WHERE time > now() - 7d GROUP BY filter", influxDbFiltersTable)
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []FilterStatus
+	if len(res) == 0 {
+		return statuses, nil
+	}
+	for _, series := range res[0].Series {
+		if len(series.Values) == 0 {
+			continue
+		}
+		t, _, err := parseCandleRow(series.Values[0])
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, FilterStatus{Name: series.Tags["filter"], LastTime: t})
+	}
+	return statuses, nil
+}
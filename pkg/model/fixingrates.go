@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrFixingRateExists is returned when a fixing rate that was already published is stored again.
+var ErrFixingRateExists = errors.New("fixing rate already exists")
+
+// FixingRate is the once-daily reference price of an asset.
+type FixingRate struct {
+	Symbol      string
+	Price       float64
+	Volume      float64
+	NumTrades   int
+	Methodology string
+	WindowStart time.Time
+	WindowEnd   time.Time
+	FixingTime  time.Time
+	ComputedAt  time.Time
+}
+
+const fixingRateColumns = "symbol,price,volume,num_trades,methodology,window_start,window_end,fixing_time,computed_at"
+
+// SetFixingRate stores @fixing. Published fixings are immutable, so ErrFixingRateExists is
+// returned if a fixing for the same symbol and time is already stored.
+func (rdb *RelDB) SetFixingRate(fixing FixingRate) error {
+	query := fmt.Sprintf("insert into %s (%s) values ($1,$2,$3,$4,$5,$6,$7,$8,$9) on conflict (symbol,fixing_time) do nothing", fixingrateTable, fixingRateColumns)
+	resp, err := rdb.postgresClient.Exec(context.Background(), query,
+		fixing.Symbol,
+		fixing.Price,
+		fixing.Volume,
+		fixing.NumTrades,
+		fixing.Methodology,
+		fixing.WindowStart,
+		fixing.WindowEnd,
+		fixing.FixingTime,
+		fixing.ComputedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if resp.RowsAffected() == 0 {
+		return ErrFixingRateExists
+	}
+	return nil
+}
+
+// GetFixingRate returns the fixing rate of @symbol at @fixingTime.
+func (rdb *RelDB) GetFixingRate(symbol string, fixingTime time.Time) (fixing FixingRate, err error) {
+	query := fmt.Sprintf("select %s from %s where symbol=$1 and fixing_time=$2", fixingRateColumns, fixingrateTable)
+	err = scanFixingRate(rdb.postgresClient.QueryRow(context.Background(), query, symbol, fixingTime), &fixing)
+	return
+}
+
+// GetLatestFixingRate returns the most recent fixing rate of @symbol.
+func (rdb *RelDB) GetLatestFixingRate(symbol string) (fixing FixingRate, err error) {
+	query := fmt.Sprintf("select %s from %s where symbol=$1 order by fixing_time desc limit 1", fixingRateColumns, fixingrateTable)
+	err = scanFixingRate(rdb.postgresClient.QueryRow(context.Background(), query, symbol), &fixing)
+	return
+}
+
+// GetFixingRates returns all fixing rates of @symbol with fixing time in [@starttime, @endtime], oldest first.
+func (rdb *RelDB) GetFixingRates(symbol string, starttime time.Time, endtime time.Time) (fixings []FixingRate, err error) {
+	query := fmt.Sprintf("select %s from %s where symbol=$1 and fixing_time>=$2 and fixing_time<=$3 order by fixing_time asc", fixingRateColumns, fixingrateTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, symbol, starttime, endtime)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fixing FixingRate
+		err = scanFixingRate(rows, &fixing)
+		if err != nil {
+			return
+		}
+		fixings = append(fixings, fixing)
+	}
+	return
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanFixingRate(row rowScanner, fixing *FixingRate) error {
+	return row.Scan(
+		&fixing.Symbol,
+		&fixing.Price,
+		&fixing.Volume,
+		&fixing.NumTrades,
+		&fixing.Methodology,
+		&fixing.WindowStart,
+		&fixing.WindowEnd,
+		&fixing.FixingTime,
+		&fixing.ComputedAt,
+	)
+}
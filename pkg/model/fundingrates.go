@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// fundingRateMaxAge is the age after which a funding rate is no longer current, e.g. because
+// the instrument was delisted.
+const fundingRateMaxAge = "1h"
+
+// SaveFundingRatesInflux stores funding rates.
+func (db *DB) SaveFundingRatesInflux(rates []dia.FundingRate) error {
+	for _, rate := range rates {
+		tags := map[string]string{"symbol": rate.Symbol, "instrument": rate.Instrument, "exchange": rate.Exchange}
+		fields := map[string]interface{}{
+			"rate":            rate.Rate,
+			"nextFundingTime": rate.NextFundingTime.Unix(),
+			"predictedRate":   rate.PredictedRate,
+			"interval":        int64(rate.Interval / time.Second),
+		}
+		pt, err := clientInfluxdb.NewPoint(influxDbFundingRateTable, tags, fields, rate.Time)
+		if err != nil {
+			log.Errorln("SaveFundingRatesInflux:", err)
+			continue
+		}
+		db.addPoint(pt)
+	}
+
+	err := db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SaveFundingRatesInflux", err)
+	}
+	return err
+}
+
+// GetLatestFundingRates returns the latest funding rate of each instrument of @symbol on
+// @exchanges, or on all exchanges if @exchanges is empty.
+func (db *DB) GetLatestFundingRates(symbol string, exchanges []string) ([]dia.FundingRate, error) {
+	q := fmt.Sprintf("SELECT rate,nextFundingTime,predictedRate,interval FROM %s WHERE symbol='%s'%s AND time > now() - %s GROUP BY exchange,instrument ORDER BY DESC LIMIT 1",
+		influxDbFundingRateTable, symbol, exchangeCondition(exchanges), fundingRateMaxAge)
+	return db.queryFundingRates(symbol, q)
+}
+
+// GetFundingRates returns the funding rates of @symbol on @exchanges, or on all exchanges if
+// @exchanges is empty, in the time range [@starttime, @endtime), oldest first.
+func (db *DB) GetFundingRates(symbol string, exchanges []string, starttime time.Time, endtime time.Time) ([]dia.FundingRate, error) {
+	q := fmt.Sprintf("SELECT rate,nextFundingTime,predictedRate,interval FROM %s WHERE symbol='%s'%s AND time>=%d AND time<%d GROUP BY exchange,instrument ORDER BY ASC",
+		influxDbFundingRateTable, symbol, exchangeCondition(exchanges), starttime.UnixNano(), endtime.UnixNano())
+	rates, err := db.queryFundingRates(symbol, q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].Time.Before(rates[j].Time) })
+	return rates, nil
+}
+
+func (db *DB) queryFundingRates(symbol string, q string) ([]dia.FundingRate, error) {
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	rates := []dia.FundingRate{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				// Rates stored before predicted rates and intervals were collected lack them.
+				for i := range row {
+					if row[i] == nil {
+						row[i] = json.Number("0")
+					}
+				}
+				t, values, err := parseCandleRow(row)
+				if err != nil {
+					return nil, err
+				}
+				rates = append(rates, dia.FundingRate{
+					Symbol:          symbol,
+					Instrument:      series.Tags["instrument"],
+					Exchange:        series.Tags["exchange"],
+					Rate:            values[0],
+					PredictedRate:   values[2],
+					Interval:        time.Duration(values[3]) * time.Second,
+					NextFundingTime: time.Unix(int64(values[1]), 0).UTC(),
+					Time:            t,
+				})
+			}
+		}
+	}
+	return rates, nil
+}
+
+// exchangeCondition returns the condition of a query restricting it to @exchanges, preceded
+// by AND, or an empty string if @exchanges is empty.
+func exchangeCondition(exchanges []string) string {
+	if len(exchanges) == 0 {
+		return ""
+	}
+	var conditions []string
+	for _, exchange := range exchanges {
+		conditions = append(conditions, fmt.Sprintf("exchange='%s'", exchange))
+	}
+	return " AND (" + strings.Join(conditions, " OR ") + ")"
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// GasPrice is a sample of the gas prices of an EVM blockchain in gwei.
+type GasPrice struct {
+	Blockchain string
+	// Block is the latest block of the sample.
+	Block uint64
+	// BaseFee is the base fee of Block, NextBaseFee the base fee of the block after it. Both are
+	// zero on blockchains without EIP-1559 fees.
+	BaseFee     float64
+	NextBaseFee float64
+	// PriorityFee10, PriorityFee50 and PriorityFee90 are percentiles of the priority fees paid in
+	// the sampled blocks, weighted by gas used.
+	PriorityFee10 float64
+	PriorityFee50 float64
+	PriorityFee90 float64
+	// GasPrice is the legacy gas price suggested by the node.
+	GasPrice float64
+	Time     time.Time
+}
+
+// SaveGasPriceInflux stores @gasPrice in influx.
+func (db *DB) SaveGasPriceInflux(gasPrice GasPrice) error {
+	fields := map[string]interface{}{
+		"block":         int64(gasPrice.Block),
+		"baseFee":       gasPrice.BaseFee,
+		"nextBaseFee":   gasPrice.NextBaseFee,
+		"priorityFee10": gasPrice.PriorityFee10,
+		"priorityFee50": gasPrice.PriorityFee50,
+		"priorityFee90": gasPrice.PriorityFee90,
+		"gasPrice":      gasPrice.GasPrice,
+	}
+	tags := map[string]string{"blockchain": gasPrice.Blockchain}
+	pt, err := clientInfluxdb.NewPoint(influxDbGasPriceTable, tags, fields, gasPrice.Time)
+	if err != nil {
+		log.Errorln("SaveGasPriceInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+
+	err = db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SaveGasPriceInflux", err)
+	}
+	return err
+}
+
+// GetGasPrices returns the gas price samples of @blockchain in the time range [@starttime, @endtime),
+// oldest first.
+func (db *DB) GetGasPrices(blockchain string, starttime time.Time, endtime time.Time) ([]GasPrice, error) {
+	q := fmt.Sprintf("SELECT block,baseFee,nextBaseFee,priorityFee10,priorityFee50,priorityFee90,gasPrice FROM %s WHERE blockchain='%s' AND time>=%d AND time<%d ORDER BY ASC",
+		influxDbGasPriceTable, blockchain, starttime.UnixNano(), endtime.UnixNano())
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	gasPrices := []GasPrice{}
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			t, values, err := parseCandleRow(row)
+			if err != nil {
+				return nil, err
+			}
+			gasPrices = append(gasPrices, GasPrice{
+				Blockchain:    blockchain,
+				Block:         uint64(values[0]),
+				BaseFee:       values[1],
+				NextBaseFee:   values[2],
+				PriorityFee10: values[3],
+				PriorityFee50: values[4],
+				PriorityFee90: values[5],
+				GasPrice:      values[6],
+				Time:          t,
+			})
+		}
+	}
+	return gasPrices, nil
+}
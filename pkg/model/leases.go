@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const leaseKeyPrefix = "dia_lease_"
+
+// acquireLeaseScript sets the holder ARGV[1] of the lease KEYS[1] for ARGV[2] milliseconds if
+// the lease is free or already held by ARGV[1].
+var acquireLeaseScript = redis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+if holder == false or holder == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0`)
+
+// releaseLeaseScript frees the lease KEYS[1] if it is held by ARGV[1].
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// AcquireLease takes or renews the lease @name for @holder for @ttl. It returns false if the
+// lease is held by another holder.
+func (db *DB) AcquireLease(name string, holder string, ttl time.Duration) (bool, error) {
+	if db.redisClient == nil {
+		return false, errors.New("no redis client")
+	}
+	acquired, err := acquireLeaseScript.Run(db.redisClient, []string{leaseKeyPrefix + name}, holder, ttl.Milliseconds()).Int64()
+	return acquired == 1, err
+}
+
+// ReleaseLease frees the lease @name if it is held by @holder, so that another holder can take
+// it without waiting for it to expire.
+func (db *DB) ReleaseLease(name string, holder string) error {
+	if db.redisClient == nil {
+		return errors.New("no redis client")
+	}
+	return releaseLeaseScript.Run(db.redisClient, []string{leaseKeyPrefix + name}, holder).Err()
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetLendingRates returns the supply and borrow rates and the utilization of @asset in the lending market of @protocol
+// on @blockchain in the time range [@starttime, @endtime), oldest first.
+func (db *DB) GetLendingRates(protocol string, blockchain string, asset string, starttime time.Time, endtime time.Time) ([]dia.DefiRate, error) {
+	q := fmt.Sprintf("SELECT lendingRate,borrowRate,utilization FROM %s WHERE protocol='%s' AND blockchain='%s' AND asset='%s' AND time>=%d AND time<%d GROUP BY asset ORDER BY ASC",
+		influxDbDefiRateTable, protocol, blockchain, asset, starttime.UnixNano(), endtime.UnixNano())
+	return db.queryLendingRates(protocol, blockchain, q)
+}
+
+// GetLatestLendingRates returns the latest supply and borrow rates and utilization of each asset in the lending
+// market of @protocol on @blockchain which has rates since @starttime.
+func (db *DB) GetLatestLendingRates(protocol string, blockchain string, starttime time.Time) ([]dia.DefiRate, error) {
+	q := fmt.Sprintf("SELECT lendingRate,borrowRate,utilization FROM %s WHERE protocol='%s' AND blockchain='%s' AND time>=%d GROUP BY asset ORDER BY DESC LIMIT 1",
+		influxDbDefiRateTable, protocol, blockchain, starttime.UnixNano())
+	return db.queryLendingRates(protocol, blockchain, q)
+}
+
+func (db *DB) queryLendingRates(protocol string, blockchain string, q string) ([]dia.DefiRate, error) {
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	rates := []dia.DefiRate{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				// Rates of markets without utilization have no value in the last column.
+				t, values, err := parseCandleRow(row[:3])
+				if err != nil {
+					return nil, err
+				}
+				rate := dia.DefiRate{
+					Timestamp:     t,
+					LendingRate:   values[0],
+					BorrowingRate: values[1],
+					Asset:         series.Tags["asset"],
+					Protocol:      protocol,
+					Blockchain:    blockchain,
+				}
+				if utilization, ok := row[3].(json.Number); ok {
+					rate.Utilization, _ = utilization.Float64()
+				}
+				rates = append(rates, rate)
+			}
+		}
+	}
+	return rates, nil
+}
+
+// SaveLendingCollateralInflux stores the collateral factors of the collateral assets of lending
+// markets.
+func (db *DB) SaveLendingCollateralInflux(collaterals []dia.LendingCollateral) error {
+	for _, collateral := range collaterals {
+		tags := map[string]string{
+			"protocol":   collateral.Protocol,
+			"blockchain": collateral.Blockchain,
+			"market":     collateral.Market,
+			"asset":      collateral.Asset,
+			"address":    collateral.Address,
+		}
+		fields := map[string]interface{}{
+			"borrowCollateralFactor":    collateral.BorrowCollateralFactor,
+			"liquidateCollateralFactor": collateral.LiquidateCollateralFactor,
+			"supplyCap":                 collateral.SupplyCap,
+		}
+		pt, err := clientInfluxdb.NewPoint(influxDbLendingCollateralTable, tags, fields, collateral.Time)
+		if err != nil {
+			log.Errorln("SaveLendingCollateralInflux:", err)
+			continue
+		}
+		db.addPoint(pt)
+	}
+
+	err := db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SaveLendingCollateralInflux", err)
+	}
+	return err
+}
+
+// GetLatestLendingCollateral returns the latest collateral factors of each collateral asset of the
+// lending markets of @protocol on @blockchain which were recorded since @starttime.
+func (db *DB) GetLatestLendingCollateral(protocol string, blockchain string, starttime time.Time) ([]dia.LendingCollateral, error) {
+	q := fmt.Sprintf("SELECT borrowCollateralFactor,liquidateCollateralFactor,supplyCap FROM %s WHERE protocol='%s' AND blockchain='%s' AND time>=%d GROUP BY market,asset,address ORDER BY DESC LIMIT 1",
+		influxDbLendingCollateralTable, protocol, blockchain, starttime.UnixNano())
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	collaterals := []dia.LendingCollateral{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				t, values, err := parseCandleRow(row)
+				if err != nil {
+					return nil, err
+				}
+				collaterals = append(collaterals, dia.LendingCollateral{
+					Protocol:                  protocol,
+					Blockchain:                blockchain,
+					Market:                    series.Tags["market"],
+					Asset:                     series.Tags["asset"],
+					Address:                   series.Tags["address"],
+					BorrowCollateralFactor:    values[0],
+					LiquidateCollateralFactor: values[1],
+					SupplyCap:                 values[2],
+					Time:                      t,
+				})
+			}
+		}
+	}
+	return collaterals, nil
+}
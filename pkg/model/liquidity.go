@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// poolLiquidityMaxAge is the age after which a snapshot of a pool is no longer current.
+	poolLiquidityMaxAge = "1h"
+	poolLiquidityTags   = "exchange,blockchain,pool,pair,token0,token1,address0,address1"
+)
+
+// SavePoolLiquidityInflux stores snapshots of the reserves of liquidity pools.
+func (db *DB) SavePoolLiquidityInflux(liquidities []dia.PoolLiquidity) error {
+	for _, liquidity := range liquidities {
+		tags := map[string]string{
+			"exchange":   liquidity.Exchange,
+			"blockchain": liquidity.Blockchain,
+			"pool":       liquidity.Pool,
+			"pair":       liquidity.Pair,
+			"token0":     liquidity.Token0.Symbol,
+			"token1":     liquidity.Token1.Symbol,
+			"address0":   liquidity.Token0.Address,
+			"address1":   liquidity.Token1.Address,
+		}
+		fields := map[string]interface{}{
+			"reserve0": liquidity.Reserve0,
+			"reserve1": liquidity.Reserve1,
+			"tvlUSD":   liquidity.TVLUSD,
+			"block":    int64(liquidity.Block),
+		}
+		pt, err := clientInfluxdb.NewPoint(influxDbPoolLiquidityTable, tags, fields, liquidity.Time)
+		if err != nil {
+			log.Errorln("SavePoolLiquidityInflux:", err)
+			continue
+		}
+		db.addPoint(pt)
+	}
+
+	err := db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SavePoolLiquidityInflux", err)
+	}
+	return err
+}
+
+// GetLatestPoolLiquidity returns the latest snapshot of each pool on @exchanges, or on all
+// exchanges if @exchanges is empty, which holds @symbol, or of all pools if @symbol is empty. The
+// pools are sorted by TVL, largest first.
+func (db *DB) GetLatestPoolLiquidity(exchanges []string, symbol string) ([]dia.PoolLiquidity, error) {
+	var symbolCondition string
+	if symbol != "" {
+		symbolCondition = fmt.Sprintf(" AND (token0='%s' OR token1='%s')", symbol, symbol)
+	}
+	q := fmt.Sprintf("SELECT reserve0,reserve1,tvlUSD,block FROM %s WHERE time > now() - %s%s%s GROUP BY %s ORDER BY DESC LIMIT 1",
+		influxDbPoolLiquidityTable, poolLiquidityMaxAge, exchangeCondition(exchanges), symbolCondition, poolLiquidityTags)
+	liquidities, err := db.queryPoolLiquidity(q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(liquidities, func(i, j int) bool { return liquidities[i].TVLUSD > liquidities[j].TVLUSD })
+	return liquidities, nil
+}
+
+// GetPoolLiquidity returns the snapshots of the pool with address @pool on @exchange in the time
+// range [@starttime, @endtime), oldest first.
+func (db *DB) GetPoolLiquidity(exchange string, pool string, starttime time.Time, endtime time.Time) ([]dia.PoolLiquidity, error) {
+	q := fmt.Sprintf("SELECT reserve0,reserve1,tvlUSD,block FROM %s WHERE exchange='%s' AND pool='%s' AND time>=%d AND time<%d GROUP BY %s ORDER BY ASC",
+		influxDbPoolLiquidityTable, exchange, pool, starttime.UnixNano(), endtime.UnixNano(), poolLiquidityTags)
+	liquidities, err := db.queryPoolLiquidity(q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(liquidities, func(i, j int) bool { return liquidities[i].Time.Before(liquidities[j].Time) })
+	return liquidities, nil
+}
+
+func (db *DB) queryPoolLiquidity(q string) ([]dia.PoolLiquidity, error) {
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	liquidities := []dia.PoolLiquidity{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				t, values, err := parseCandleRow(row)
+				if err != nil {
+					return nil, err
+				}
+				blockchain := series.Tags["blockchain"]
+				liquidities = append(liquidities, dia.PoolLiquidity{
+					Exchange:   series.Tags["exchange"],
+					Blockchain: blockchain,
+					Pool:       series.Tags["pool"],
+					Pair:       series.Tags["pair"],
+					Token0:     dia.Asset{Symbol: series.Tags["token0"], Address: series.Tags["address0"], Blockchain: blockchain},
+					Token1:     dia.Asset{Symbol: series.Tags["token1"], Address: series.Tags["address1"], Blockchain: blockchain},
+					Reserve0:   values[0],
+					Reserve1:   values[1],
+					TVLUSD:     values[2],
+					Block:      uint64(values[3]),
+					Time:       t,
+				})
+			}
+		}
+	}
+	return liquidities, nil
+}
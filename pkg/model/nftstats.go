@@ -0,0 +1,98 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NFTFloor is the floor price of an NFT collection, i.e. a low percentile of its sale prices,
+// which unlike the minimum isn't set by single wash or mistaken sales.
+type NFTFloor struct {
+	Blockchain string
+	Address    string
+	// Percentile of the USD sale prices which is taken as the floor, e.g. 0.1.
+	Percentile float64
+	StartTime  time.Time
+	EndTime    time.Time
+	Floor      float64
+	Sales      int64
+	// MovingAverageFloor is the mean of the daily floors of the last MovingAverageDays days
+	// until EndTime, leaving out days without sales.
+	MovingAverageFloor float64
+	MovingAverageDays  int
+}
+
+// GetNFTFloor returns the @percentile of the USD prices of the sales of the NFT class with
+// @nftclassID in the time range [@starttime, @endtime), along with the number of sales.
+func (rdb *RelDB) GetNFTFloor(nftclassID string, percentile float64, starttime time.Time, endtime time.Time) (floor float64, sales int64, err error) {
+	query := fmt.Sprintf(`select coalesce(percentile_cont($1) within group (order by price_usd::double precision),0),count(*)
+	from %s where nftclass_id=$2 and trade_time>=$3 and trade_time<$4 and price_usd>0`, nfttradeTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, percentile, nftclassID, starttime, endtime).Scan(&floor, &sales)
+	return
+}
+
+// GetNFTMovingAverageFloor returns the mean of the daily @percentile floors of the NFT class with
+// @nftclassID over the @days days until @endtime. Days without sales are left out.
+func (rdb *RelDB) GetNFTMovingAverageFloor(nftclassID string, percentile float64, days int, endtime time.Time) (floor float64, err error) {
+	query := fmt.Sprintf(`select coalesce(avg(floor),0) from (
+		select percentile_cont($1) within group (order by price_usd::double precision) as floor
+		from %s where nftclass_id=$2 and trade_time>=$3 and trade_time<$4 and price_usd>0
+		group by date_trunc('day',trade_time)
+	) daily`, nfttradeTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, percentile, nftclassID, endtime.AddDate(0, 0, -days), endtime).Scan(&floor)
+	return
+}
+
+// NFTVolume is the trading activity of an NFT collection in a time range.
+type NFTVolume struct {
+	Blockchain string
+	Address    string
+	StartTime  time.Time
+	EndTime    time.Time
+	NFTMarketplaceVolume
+	Marketplaces []NFTMarketplaceVolume
+}
+
+// NFTMarketplaceVolume is the USD volume, number of sales and number of distinct buyers and
+// sellers of an NFT collection on a marketplace, or on all marketplaces if Marketplace is empty.
+type NFTMarketplaceVolume struct {
+	Marketplace string `json:",omitempty"`
+	Volume      float64
+	Sales       int64
+	Buyers      int64
+	Sellers     int64
+}
+
+// GetNFTVolume returns the trading activity of the NFT class with @nftclassID in the time range
+// [@starttime, @endtime), in total and by marketplace.
+func (rdb *RelDB) GetNFTVolume(nftclassID string, starttime time.Time, endtime time.Time) (volume NFTVolume, err error) {
+	// The total is a grouping set of its own as distinct buyers and sellers don't add up across marketplaces.
+	query := fmt.Sprintf(`select grouping(marketplace),coalesce(marketplace,''),coalesce(sum(price_usd)::double precision,0),count(*),count(distinct transfer_to),count(distinct transfer_from)
+	from %s where nftclass_id=$1 and trade_time>=$2 and trade_time<$3
+	group by grouping sets ((marketplace),()) order by 1 desc,3 desc`, nfttradeTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, nftclassID, starttime, endtime)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	volume.StartTime = starttime
+	volume.EndTime = endtime
+	volume.Marketplaces = []NFTMarketplaceVolume{}
+	for rows.Next() {
+		var total int
+		var marketplaceVolume NFTMarketplaceVolume
+		err = rows.Scan(&total, &marketplaceVolume.Marketplace, &marketplaceVolume.Volume, &marketplaceVolume.Sales, &marketplaceVolume.Buyers, &marketplaceVolume.Sellers)
+		if err != nil {
+			return
+		}
+		if total == 1 {
+			volume.NFTMarketplaceVolume = marketplaceVolume
+		} else {
+			volume.Marketplaces = append(volume.Marketplaces, marketplaceVolume)
+		}
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// openInterestMaxAge is the age after which open interest is no longer current.
+const openInterestMaxAge = "1h"
+
+// SaveOpenInterestInflux stores the open interest of instruments.
+func (db *DB) SaveOpenInterestInflux(openInterests []dia.OpenInterest) error {
+	for _, oi := range openInterests {
+		tags := map[string]string{"symbol": oi.Symbol, "instrument": oi.Instrument, "exchange": oi.Exchange}
+		fields := map[string]interface{}{
+			"openInterest":    oi.OpenInterest,
+			"openInterestUSD": oi.OpenInterestUSD,
+		}
+		pt, err := clientInfluxdb.NewPoint(influxDbOpenInterestTable, tags, fields, oi.Time)
+		if err != nil {
+			log.Errorln("SaveOpenInterestInflux:", err)
+			continue
+		}
+		db.addPoint(pt)
+	}
+
+	err := db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SaveOpenInterestInflux", err)
+	}
+	return err
+}
+
+// GetLatestOpenInterest returns the latest open interest of each instrument of @symbol on
+// @exchanges, or on all exchanges if @exchanges is empty.
+func (db *DB) GetLatestOpenInterest(symbol string, exchanges []string) ([]dia.OpenInterest, error) {
+	q := fmt.Sprintf("SELECT openInterest,openInterestUSD FROM %s WHERE symbol='%s'%s AND time > now() - %s GROUP BY exchange,instrument ORDER BY DESC LIMIT 1",
+		influxDbOpenInterestTable, symbol, exchangeCondition(exchanges), openInterestMaxAge)
+	return db.queryOpenInterest(symbol, q)
+}
+
+// GetOpenInterest returns the open interest of the instruments of @symbol on @exchanges, or on
+// all exchanges if @exchanges is empty, in the time range [@starttime, @endtime), oldest first.
+func (db *DB) GetOpenInterest(symbol string, exchanges []string, starttime time.Time, endtime time.Time) ([]dia.OpenInterest, error) {
+	q := fmt.Sprintf("SELECT openInterest,openInterestUSD FROM %s WHERE symbol='%s'%s AND time>=%d AND time<%d GROUP BY exchange,instrument ORDER BY ASC",
+		influxDbOpenInterestTable, symbol, exchangeCondition(exchanges), starttime.UnixNano(), endtime.UnixNano())
+	openInterests, err := db.queryOpenInterest(symbol, q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(openInterests, func(i, j int) bool { return openInterests[i].Time.Before(openInterests[j].Time) })
+	return openInterests, nil
+}
+
+func (db *DB) queryOpenInterest(symbol string, q string) ([]dia.OpenInterest, error) {
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	openInterests := []dia.OpenInterest{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				t, values, err := parseCandleRow(row)
+				if err != nil {
+					return nil, err
+				}
+				openInterests = append(openInterests, dia.OpenInterest{
+					Symbol:          symbol,
+					Instrument:      series.Tags["instrument"],
+					Exchange:        series.Tags["exchange"],
+					OpenInterest:    values[0],
+					OpenInterestUSD: values[1],
+					Time:            t,
+				})
+			}
+		}
+	}
+	return openInterests, nil
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// optionMarketMaxAge is the age after which a snapshot of the market of an option is no longer
+// part of the volatility surface, e.g. because the option expired.
+const optionMarketMaxAge = time.Hour
+
+const optionMarketColumns = "markPrice,markIV,bidIV,askIV,openInterest,underlyingPrice,indexPrice,strikePrice,expirationTime,delta,gamma,vega,theta"
+
+// SaveOptionTradeInflux adds a trade of an option to the batch, which is written once it is full
+// or on Flush.
+func (db *DB) SaveOptionTradeInflux(trade dia.OptionTrade) error {
+	tags := map[string]string{"instrumentName": trade.InstrumentName, "baseCurrency": trade.BaseCurrency, "exchange": trade.Exchange}
+	fields := map[string]interface{}{
+		"price":             trade.Price,
+		"volume":            trade.Volume,
+		"impliedVolatility": trade.ImpliedVolatility,
+		"indexPrice":        trade.IndexPrice,
+		"foreignTradeID":    trade.ForeignTradeID,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbOptionTradesTable, tags, fields, trade.Time)
+	if err != nil {
+		log.Errorln("SaveOptionTradeInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+	return nil
+}
+
+// SaveOptionMarketDatumInflux adds a snapshot of the market of an option to the batch, which is
+// written once it is full or on Flush.
+func (db *DB) SaveOptionMarketDatumInflux(datum dia.OptionMarketDatum) error {
+	tags := map[string]string{
+		"instrumentName": datum.InstrumentName,
+		"baseCurrency":   datum.BaseCurrency,
+		"exchange":       datum.Exchange,
+		"optionType":     optionTypeTag(datum.OptionType),
+	}
+	fields := map[string]interface{}{
+		"markPrice":       datum.MarkPrice,
+		"markIV":          datum.MarkIV,
+		"bidIV":           datum.BidIV,
+		"askIV":           datum.AskIV,
+		"openInterest":    datum.OpenInterest,
+		"underlyingPrice": datum.UnderlyingPrice,
+		"indexPrice":      datum.IndexPrice,
+		"strikePrice":     datum.StrikePrice,
+		"expirationTime":  datum.ExpirationTime.Unix(),
+		"delta":           datum.Delta,
+		"gamma":           datum.Gamma,
+		"vega":            datum.Vega,
+		"theta":           datum.Theta,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbOptionMarketTable, tags, fields, datum.Time)
+	if err != nil {
+		log.Errorln("SaveOptionMarketDatumInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+	return nil
+}
+
+// GetOptionMarketData returns the latest snapshot of each option on @baseCurrency on @exchanges,
+// or on all exchanges if @exchanges is empty, at @timestamp. These are the implied volatility
+// surface of @baseCurrency at @timestamp.
+func (db *DB) GetOptionMarketData(baseCurrency string, exchanges []string, timestamp time.Time) ([]dia.OptionMarketDatum, error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE baseCurrency='%s'%s AND time>%d AND time<=%d GROUP BY exchange,instrumentName,optionType ORDER BY DESC LIMIT 1",
+		optionMarketColumns, influxDbOptionMarketTable, baseCurrency, exchangeCondition(exchanges), timestamp.Add(-optionMarketMaxAge).UnixNano(), timestamp.UnixNano())
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	data := []dia.OptionMarketDatum{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				t, values, err := parseCandleRow(row)
+				if err != nil {
+					return nil, err
+				}
+				expirationTime := time.Unix(int64(values[8]), 0).UTC()
+				if !expirationTime.After(timestamp) {
+					continue
+				}
+				optionType := dia.CallOption
+				if series.Tags["optionType"] == "put" {
+					optionType = dia.PutOption
+				}
+				data = append(data, dia.OptionMarketDatum{
+					InstrumentName:  series.Tags["instrumentName"],
+					BaseCurrency:    baseCurrency,
+					Exchange:        series.Tags["exchange"],
+					StrikePrice:     values[7],
+					ExpirationTime:  expirationTime,
+					OptionType:      optionType,
+					MarkPrice:       values[0],
+					MarkIV:          values[1],
+					BidIV:           values[2],
+					AskIV:           values[3],
+					OpenInterest:    values[4],
+					UnderlyingPrice: values[5],
+					IndexPrice:      values[6],
+					Delta:           values[9],
+					Gamma:           values[10],
+					Vega:            values[11],
+					Theta:           values[12],
+					Time:            t,
+				})
+			}
+		}
+	}
+	return data, nil
+}
+
+func optionTypeTag(optionType dia.OptionType) string {
+	if optionType == dia.PutOption {
+		return "put"
+	}
+	return "call"
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Statuses of oracle updates.
+const (
+	// OracleUpdateSubmitted is a transaction sent to the chain.
+	OracleUpdateSubmitted = "submitted"
+	// OracleUpdateConfirmed is a transaction mined successfully.
+	OracleUpdateConfirmed = "confirmed"
+	// OracleUpdateReverted is a transaction mined but reverted.
+	OracleUpdateReverted = "reverted"
+	// OracleUpdateFailed is an update which couldn't be sent or whose receipt couldn't be retrieved.
+	OracleUpdateFailed = "failed"
+	// OracleUpdateProposed is an update proposed to the owners of a Safe, which execute it.
+	OracleUpdateProposed = "proposed"
+	// OracleUpdateSkipped is an update the feeder didn't send, e.g. because its gas couldn't be estimated.
+	OracleUpdateSkipped = "skipped"
+)
+
+// OracleUpdate is one event in the life of an update of an oracle contract. The audit trail is
+// append-only, so an update which was sent and mined is recorded as submitted and as confirmed.
+type OracleUpdate struct {
+	ChainID  int64
+	Contract string
+	Key      string
+	Value    float64
+	// Timestamp is the timestamp of the value as written to the contract.
+	Timestamp   time.Time
+	TxHash      string
+	GasUsed     uint64
+	BlockNumber uint64
+	Status      string
+	Error       string
+	// Time is the time the event was recorded.
+	Time time.Time
+}
+
+const oracleUpdateColumns = "chain_id,contract,key,value,value_time,tx_hash,gas_used,block_number,status,error,update_time"
+
+// SetOracleUpdate appends @update to the audit trail of oracle updates.
+func (rdb *RelDB) SetOracleUpdate(update OracleUpdate) error {
+	query := fmt.Sprintf("insert into %s (%s) values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)", oracleupdateTable, oracleUpdateColumns)
+	_, err := rdb.postgresClient.Exec(context.Background(), query,
+		update.ChainID,
+		update.Contract,
+		update.Key,
+		update.Value,
+		update.Timestamp,
+		update.TxHash,
+		int64(update.GasUsed),
+		int64(update.BlockNumber),
+		update.Status,
+		update.Error,
+		update.Time,
+	)
+	return err
+}
+
+// GetOracleUpdates returns the audit trail of the feed @key recorded in [@starttime, @endtime],
+// oldest first. A zero @chainID and an empty @contract match all chains and contracts.
+func (rdb *RelDB) GetOracleUpdates(chainID int64, contract string, key string, starttime time.Time, endtime time.Time) (updates []OracleUpdate, err error) {
+	query := fmt.Sprintf(`select %s from %s where key=$1 and ($2::bigint=0 or chain_id=$2) and ($3='' or lower(contract)=lower($3))
+	and update_time>=$4 and update_time<=$5 order by update_time asc`, oracleUpdateColumns, oracleupdateTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, key, chainID, contract, starttime, endtime)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var update OracleUpdate
+		var gasUsed, blockNumber int64
+		err = rows.Scan(
+			&update.ChainID,
+			&update.Contract,
+			&update.Key,
+			&update.Value,
+			&update.Timestamp,
+			&update.TxHash,
+			&gasUsed,
+			&blockNumber,
+			&update.Status,
+			&update.Error,
+			&update.Time,
+		)
+		if err != nil {
+			return
+		}
+		update.GasUsed = uint64(gasUsed)
+		update.BlockNumber = uint64(blockNumber)
+		updates = append(updates, update)
+	}
+	return
+}
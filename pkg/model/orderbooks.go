@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// orderBookDepthMaxAge is the age after which order book depths are considered outdated, as
+// the book has likely changed since.
+const orderBookDepthMaxAge = "1h"
+
+// SaveOrderBookDepthInflux stores the order book depth of a pair.
+func (db *DB) SaveOrderBookDepthInflux(depth dia.OrderBookDepth) error {
+	tags := map[string]string{"symbol": depth.Symbol, "pair": depth.Pair, "exchange": depth.Exchange}
+	fields := map[string]interface{}{
+		"midPrice": depth.MidPrice,
+		"bidDepth": depth.BidDepth,
+		"askDepth": depth.AskDepth,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbOrderBookDepthTable, tags, fields, depth.Time)
+	if err != nil {
+		log.Errorln("SaveOrderBookDepthInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+
+	err = db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SaveOrderBookDepthInflux", err)
+	}
+	return err
+}
+
+// GetOrderBookDepths returns the latest order book depth of each pair of @symbol on each
+// exchange, leaving out depths older than orderBookDepthMaxAge.
+func (db *DB) GetOrderBookDepths(symbol string) ([]dia.OrderBookDepth, error) {
+	q := fmt.Sprintf("SELECT midPrice,bidDepth,askDepth FROM %s WHERE symbol='%s' AND time > now() - %s GROUP BY exchange,pair ORDER BY DESC LIMIT 1",
+		influxDbOrderBookDepthTable, symbol, orderBookDepthMaxAge)
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	depths := []dia.OrderBookDepth{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			if len(series.Values) == 0 {
+				continue
+			}
+			t, values, err := parseCandleRow(series.Values[0])
+			if err != nil {
+				return nil, err
+			}
+			depths = append(depths, dia.OrderBookDepth{
+				Symbol:   symbol,
+				Pair:     series.Tags["pair"],
+				Exchange: series.Tags["exchange"],
+				MidPrice: values[0],
+				BidDepth: values[1],
+				AskDepth: values[2],
+				Time:     t,
+			})
+		}
+	}
+	return depths, nil
+}
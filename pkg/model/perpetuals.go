@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// SavePerpetualTradeInflux adds a trade of a perpetual future to the batch, which is written
+// once it is full or on Flush. Perpetual trades are kept apart from spot trades.
+func (db *DB) SavePerpetualTradeInflux(trade dia.PerpetualTrade) error {
+	tags := map[string]string{"symbol": trade.Symbol, "instrument": trade.Instrument, "exchange": trade.Exchange}
+	fields := map[string]interface{}{
+		"price":          trade.Price,
+		"volume":         trade.Volume,
+		"foreignTradeID": trade.ForeignTradeID,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbPerpetualTradesTable, tags, fields, trade.Time)
+	if err != nil {
+		log.Errorln("SavePerpetualTradeInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+	return nil
+}
+
+// SavePerpetualPriceInflux adds the mark and index price of a perpetual future to the batch,
+// which is written once it is full or on Flush.
+func (db *DB) SavePerpetualPriceInflux(price dia.PerpetualPrice) error {
+	tags := map[string]string{"symbol": price.Symbol, "instrument": price.Instrument, "exchange": price.Exchange}
+	fields := map[string]interface{}{
+		"markPrice":  price.MarkPrice,
+		"indexPrice": price.IndexPrice,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbPerpetualPricesTable, tags, fields, price.Time)
+	if err != nil {
+		log.Errorln("SavePerpetualPriceInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+	return nil
+}
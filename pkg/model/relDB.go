@@ -3,6 +3,7 @@ package models
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -16,6 +17,10 @@ import (
 // RelDatastore is a (persistent) relational database with an additional redis caching layer
 type RelDatastore interface {
 
+	// Asset methods
+	GetAssets(after AssetKey, limit int) ([]AssetInfo, error)
+	SearchAssets(query string, limit int) ([]AssetMatch, error)
+
 	// NFT class methods
 	SetNFTClass(nftClass dia.NFTClass) error
 	GetAllNFTClasses(blockchain string) (nftClasses []dia.NFTClass, err error)
@@ -35,6 +40,9 @@ type RelDatastore interface {
 	SetNFTTrade(trade dia.NFTTrade) error
 	GetNFTTrades(nft dia.NFT) ([]dia.NFTTrade, error)
 	GetNFTPrice30Days(nftclass dia.NFTClass) (float64, error)
+	GetNFTFloor(nftclassID string, percentile float64, starttime time.Time, endtime time.Time) (float64, int64, error)
+	GetNFTMovingAverageFloor(nftclassID string, percentile float64, days int, endtime time.Time) (float64, error)
+	GetNFTVolume(nftclassID string, starttime time.Time, endtime time.Time) (NFTVolume, error)
 	GetLastBlockheightTopshot(upperBound time.Time) (uint64, error)
 	GetLastBlockNFTTradeScraper(nftclass dia.NFTClass) (uint64, error)
 	SetNFTBid(bid dia.NFTBid) error
@@ -56,20 +64,77 @@ type RelDatastore interface {
 	SetBlockData(dia.BlockData) error
 	GetBlockData(blockchain string, blocknumber int64) (dia.BlockData, error)
 	GetLastBlockBlockscraper(blockchain string) (int64, error)
+
+	// Admin users and audit trail
+	SetAdminUser(username string, password string, role AdminRole) error
+	SetAdminUserRole(username string, role AdminRole) error
+	GetAdminUser(username string) (AdminUser, error)
+	VerifyAdminUser(username string, password string) (AdminUser, error)
+	GetAdminUsers() ([]AdminUser, error)
+	DeleteAdminUser(username string) error
+	SetAdminAuditEntry(entry AdminAuditEntry) error
+	GetAdminAuditEntries(username string, starttime time.Time, endtime time.Time) ([]AdminAuditEntry, error)
+
+	// Fixing rates
+	SetFixingRate(fixing FixingRate) error
+	GetFixingRate(symbol string, fixingTime time.Time) (FixingRate, error)
+	GetLatestFixingRate(symbol string) (FixingRate, error)
+	GetFixingRates(symbol string, starttime time.Time, endtime time.Time) ([]FixingRate, error)
+
+	// Audit trail of oracle updates
+	SetOracleUpdate(update OracleUpdate) error
+	GetOracleUpdates(chainID int64, contract string, key string, starttime time.Time, endtime time.Time) ([]OracleUpdate, error)
+
+	// Custom baskets
+	SetBasket(basket Basket) (string, error)
+	GetBasket(id string) (Basket, error)
+
+	// Webhook subscriptions to price alerts
+	SetWebhook(webhook Webhook) (string, error)
+	GetWebhook(id string) (Webhook, error)
+	GetWebhooks(creator string) ([]Webhook, error)
+	DeleteWebhook(id string) error
+	SetWebhookDeadLetter(deadLetter WebhookDeadLetter) error
+	GetWebhookDeadLetters(webhookID string, limit int) ([]WebhookDeadLetter, error)
+
+	// Mapping of the symbols and pairs of exchanges to assets
+	SetExchangePairs(exchange string, pairs []ExchangePairMapping) error
+	GetExchangeSymbolMappings(exchange string, status string) ([]ExchangeSymbolMapping, error)
+	SetExchangeSymbolMapping(mapping ExchangeSymbolMapping) error
+	GetAssetsBySymbol(symbol string) ([]dia.Asset, error)
+	UpdateExchangePairMappings(exchange string) error
+	GetExchangePairMappings(exchange string, status string) ([]ExchangePairMapping, error)
+
+	// Equivalence of bridged and wrapped assets
+	SetAssetEquivalence(canonical string, asset dia.Asset) error
+	DeleteAssetEquivalence(canonical string, asset dia.Asset) error
+	GetAssetEquivalences(canonical string) ([]AssetEquivalence, error)
 }
 
 const (
 	postgresKey = "postgres_credentials.txt"
 
-	blockchainTable  = "blockchain"
-	blockdataTable   = "blockdata"
-	nftcategoryTable = "nftcategory"
-	nftclassTable    = "nftclass"
-	nftTable         = "nft"
-	nfttradeTable    = "nfttrade"
-	nftbidTable      = "nftbid"
-	nftofferTable    = "nftoffer"
-	scrapersTable    = "scrapers"
+	assetTable          = "asset"
+	exchangepairTable   = "exchangepair"
+	exchangesymbolTable = "exchangesymbol"
+	equivalenceTable    = "assetequivalence"
+	blockchainTable     = "blockchain"
+	blockdataTable      = "blockdata"
+	nftcategoryTable    = "nftcategory"
+	nftclassTable       = "nftclass"
+	nftTable            = "nft"
+	nfttradeTable       = "nfttrade"
+	nftbidTable         = "nftbid"
+	nftofferTable       = "nftoffer"
+	scrapersTable       = "scrapers"
+	adminuserTable      = "adminuser"
+	adminauditTable     = "adminaudit"
+	fixingrateTable     = "fixingrate"
+	oracleupdateTable   = "oracleupdate"
+	basketTable         = "basket"
+	basketconstTable    = "basketconstituent"
+	webhookTable        = "webhook"
+	webhookdeadTable    = "webhookdeadletter"
 
 	// time format for blockchain genesis dates
 	timeFormatBlockchain = "2006-01-02"
@@ -138,6 +203,14 @@ func NewRelDataStoreWithOptions(withPostgres bool, withRedis bool) (*RelDB, erro
 	return &RelDB{url, postgresClient, redisClient, 32}, nil
 }
 
+// PingPostgres returns an error if the postgres server of @rdb isn't reachable.
+func (rdb *RelDB) PingPostgres() error {
+	if rdb.postgresClient == nil {
+		return errors.New("no postgres client")
+	}
+	return rdb.postgresClient.Ping(context.Background())
+}
+
 // GetKeys returns a slice of strings holding the names of the keys of @table in postgres
 func (rdb *RelDB) GetKeys(table string) (keys []string, err error) {
 	query := fmt.Sprintf("select column_name from information_schema.columns where table_name='%s'", table)
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Stablecoin is a token pegged to one unit of a fiat currency.
+type Stablecoin struct {
+	Symbol string
+	// Peg is the fiat currency of the peg, e.g. USD or EUR.
+	Peg string
+	// Threshold is the relative deviation from the peg above which the stablecoin is depegged.
+	Threshold float64
+}
+
+// Stablecoins are the stablecoins whose pegs are monitored.
+var Stablecoins = []Stablecoin{
+	{Symbol: "USDT", Peg: "USD", Threshold: 0.005},
+	{Symbol: "USDC", Peg: "USD", Threshold: 0.005},
+	{Symbol: "DAI", Peg: "USD", Threshold: 0.005},
+	{Symbol: "FDUSD", Peg: "USD", Threshold: 0.005},
+	{Symbol: "PYUSD", Peg: "USD", Threshold: 0.005},
+	{Symbol: "USDP", Peg: "USD", Threshold: 0.005},
+	{Symbol: "TUSD", Peg: "USD", Threshold: 0.01},
+	{Symbol: "FRAX", Peg: "USD", Threshold: 0.01},
+	{Symbol: "LUSD", Peg: "USD", Threshold: 0.01},
+	{Symbol: "USDE", Peg: "USD", Threshold: 0.01},
+	{Symbol: "EURC", Peg: "EUR", Threshold: 0.005},
+	{Symbol: "EURS", Peg: "EUR", Threshold: 0.01},
+}
+
+// PegDeviation is the deviation of a stablecoin from its peg.
+type PegDeviation struct {
+	Stablecoin
+	// Price is the price of the stablecoin in its peg currency.
+	Price    float64
+	PriceUSD float64
+	// Deviation is Price-1, negative if the stablecoin trades below its peg.
+	Deviation float64
+	Depegged  bool
+	// MarketCap is the USD market cap of the stablecoin, zero if its supply is unknown.
+	MarketCap float64
+	Time      time.Time
+}
+
+// PegIndex is the composite deviation of all monitored stablecoins from their pegs.
+type PegIndex struct {
+	// Index is the mean absolute deviation of the stablecoins weighted by market cap.
+	Index float64
+	// Depegged are the symbols of the stablecoins deviating by more than their threshold.
+	Depegged    []string
+	Stablecoins []PegDeviation
+	Time        time.Time
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// stakingMaxAge is the age after which a staking yield or liquid staking rate is no longer
+// current. Both are collected about hourly.
+const stakingMaxAge = "1d"
+
+// SaveStakingYieldInflux stores a staking yield.
+func (db *DB) SaveStakingYieldInflux(yield dia.StakingYield) error {
+	tags := map[string]string{"asset": yield.Asset, "source": yield.Source}
+	fields := map[string]interface{}{"apr": yield.APR}
+	pt, err := clientInfluxdb.NewPoint(influxDbStakingYieldTable, tags, fields, yield.Time)
+	if err != nil {
+		log.Errorln("SaveStakingYieldInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+
+	err = db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SaveStakingYieldInflux", err)
+	}
+	return err
+}
+
+// GetLatestStakingYields returns the latest staking yield of @asset from each source.
+func (db *DB) GetLatestStakingYields(asset string) ([]dia.StakingYield, error) {
+	q := fmt.Sprintf("SELECT apr FROM %s WHERE asset='%s' AND time > now() - %s GROUP BY source ORDER BY DESC LIMIT 1",
+		influxDbStakingYieldTable, asset, stakingMaxAge)
+	return db.queryStakingYields(asset, q)
+}
+
+// GetStakingYields returns the staking yields of @asset in the time range [@starttime, @endtime),
+// oldest first.
+func (db *DB) GetStakingYields(asset string, starttime time.Time, endtime time.Time) ([]dia.StakingYield, error) {
+	q := fmt.Sprintf("SELECT apr FROM %s WHERE asset='%s' AND time>=%d AND time<%d GROUP BY source ORDER BY ASC",
+		influxDbStakingYieldTable, asset, starttime.UnixNano(), endtime.UnixNano())
+	yields, err := db.queryStakingYields(asset, q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(yields, func(i, j int) bool { return yields[i].Time.Before(yields[j].Time) })
+	return yields, nil
+}
+
+func (db *DB) queryStakingYields(asset string, q string) ([]dia.StakingYield, error) {
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	yields := []dia.StakingYield{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				t, values, err := parseCandleRow(row)
+				if err != nil {
+					return nil, err
+				}
+				yields = append(yields, dia.StakingYield{
+					Asset:  asset,
+					Source: series.Tags["source"],
+					APR:    values[0],
+					Time:   t,
+				})
+			}
+		}
+	}
+	return yields, nil
+}
+
+// SaveLiquidStakingRateInflux stores the redemption rate of a liquid staking token.
+func (db *DB) SaveLiquidStakingRateInflux(rate dia.LiquidStakingRate) error {
+	tags := map[string]string{"token": rate.Token, "underlying": rate.Underlying}
+	fields := map[string]interface{}{"rate": rate.Rate, "block": int64(rate.Block)}
+	pt, err := clientInfluxdb.NewPoint(influxDbLiquidStakingRateTable, tags, fields, rate.Time)
+	if err != nil {
+		log.Errorln("SaveLiquidStakingRateInflux:", err)
+		return err
+	}
+	db.addPoint(pt)
+
+	err = db.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("SaveLiquidStakingRateInflux", err)
+	}
+	return err
+}
+
+// GetLatestLiquidStakingRate returns the latest redemption rate of the liquid staking token
+// @token, or nil if there is no current rate.
+func (db *DB) GetLatestLiquidStakingRate(token string) (*dia.LiquidStakingRate, error) {
+	q := fmt.Sprintf("SELECT rate,block FROM %s WHERE token='%s' AND time > now() - %s GROUP BY underlying ORDER BY DESC LIMIT 1",
+		influxDbLiquidStakingRateTable, token, stakingMaxAge)
+	rates, err := db.queryLiquidStakingRates(token, q)
+	if err != nil || len(rates) == 0 {
+		return nil, err
+	}
+	return &rates[0], nil
+}
+
+// GetLiquidStakingRates returns the redemption rates of the liquid staking token @token in the
+// time range [@starttime, @endtime), oldest first.
+func (db *DB) GetLiquidStakingRates(token string, starttime time.Time, endtime time.Time) ([]dia.LiquidStakingRate, error) {
+	q := fmt.Sprintf("SELECT rate,block FROM %s WHERE token='%s' AND time>=%d AND time<%d GROUP BY underlying ORDER BY ASC",
+		influxDbLiquidStakingRateTable, token, starttime.UnixNano(), endtime.UnixNano())
+	return db.queryLiquidStakingRates(token, q)
+}
+
+func (db *DB) queryLiquidStakingRates(token string, q string) ([]dia.LiquidStakingRate, error) {
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+	rates := []dia.LiquidStakingRate{}
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			for _, row := range series.Values {
+				// Rates recorded before the block have no block.
+				t, values, err := parseCandleRow(row[:2])
+				if err != nil {
+					return nil, err
+				}
+				var block uint64
+				if number, ok := row[2].(json.Number); ok {
+					b, _ := number.Int64()
+					block = uint64(b)
+				}
+				rates = append(rates, dia.LiquidStakingRate{
+					Token:      token,
+					Underlying: series.Tags["underlying"],
+					Rate:       values[0],
+					Block:      block,
+					Time:       t,
+				})
+			}
+		}
+	}
+	return rates, nil
+}
@@ -9,6 +9,10 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// tradeColumns are the columns of the trades table in the order expected by parseTrade. They
+// are listed explicitly as SELECT * orders the columns by name.
+const tradeColumns = "time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verification"
+
 func parseTrade(row []interface{}) *dia.Trade {
 	if len(row) > 7 {
 		t, err := time.Parse(time.RFC3339, row[0].(string))
@@ -57,15 +61,22 @@ func parseTrade(row []interface{}) *dia.Trade {
 				log.Errorln("error on parsing row 2", row)
 			}
 
+			// Trades stored before verification was introduced have no status.
+			var verificationStatus string
+			if len(row) > 8 && row[8] != nil {
+				verificationStatus, _ = row[8].(string)
+			}
+
 			trade := dia.Trade{
-				Symbol:            symbol,
-				Pair:              pair,
-				Time:              t,
-				Source:            source,
-				EstimatedUSDPrice: estimatedUSDPrice,
-				Price:             price,
-				Volume:            volume,
-				ForeignTradeID:    foreignTradeID,
+				Symbol:             symbol,
+				Pair:               pair,
+				Time:               t,
+				Source:             source,
+				EstimatedUSDPrice:  estimatedUSDPrice,
+				Price:              price,
+				Volume:             volume,
+				ForeignTradeID:     foreignTradeID,
+				VerificationStatus: verificationStatus,
 			}
 			return &trade
 		}
@@ -78,8 +89,7 @@ func parseTrade(row []interface{}) *dia.Trade {
 // GetAllTrades returns at most @maxTrades trades from influx with timestamp > @t. Only used by replayInflux option.
 func (db *DB) GetAllTrades(t time.Time, maxTrades int) ([]dia.Trade, error) {
 	r := []dia.Trade{}
-	// TO DO: Substitute select * with precise statment select estimatedUSDPrice, source,...
-	q := fmt.Sprintf("SELECT * FROM %s WHERE time > %d LIMIT %d", influxDbTradesTable, t.Unix()*1000000000, maxTrades)
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE time > %d LIMIT %d", tradeColumns, influxDbTradesTable, t.Unix()*1000000000, maxTrades)
 	log.Debug(q)
 	res, err := queryInfluxDB(db.influxClient, q)
 	if err != nil {
@@ -101,7 +111,7 @@ func (db *DB) GetAllTrades(t time.Time, maxTrades int) ([]dia.Trade, error) {
 
 func (db *DB) GetLastTrades(symbol string, exchange string, maxTrades int) ([]dia.Trade, error) {
 	r := []dia.Trade{}
-	q := fmt.Sprintf("SELECT * FROM %s WHERE exchange='%s' and symbol='%s' ORDER BY DESC LIMIT %d", influxDbTradesTable, exchange, symbol, maxTrades)
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE exchange='%s' and symbol='%s' ORDER BY DESC LIMIT %d", tradeColumns, influxDbTradesTable, exchange, symbol, maxTrades)
 	res, err := queryInfluxDB(db.influxClient, q)
 	if err != nil {
 		log.Errorln("GetLastTrades", err)
@@ -123,7 +133,7 @@ func (db *DB) GetLastTrades(symbol string, exchange string, maxTrades int) ([]di
 
 func (db *DB) GetLastTradesAllExchanges(symbol string, maxTrades int) ([]dia.Trade, error) {
 	r := []dia.Trade{}
-	q := fmt.Sprintf("SELECT * FROM %s WHERE symbol='%s' ORDER BY DESC LIMIT %d", influxDbTradesTable, symbol, maxTrades)
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE symbol='%s' ORDER BY DESC LIMIT %d", tradeColumns, influxDbTradesTable, symbol, maxTrades)
 	res, err := queryInfluxDB(db.influxClient, q)
 	if err != nil {
 		log.Errorln("GetLastTrades", err)
@@ -142,3 +152,55 @@ func (db *DB) GetLastTradesAllExchanges(symbol string, maxTrades int) ([]dia.Tra
 	}
 	return r, nil
 }
+
+// GetTradesByTimerange returns all trades of @symbol on all exchanges in the time range
+// [@starttime, @endtime), oldest first.
+func (db *DB) GetTradesByTimerange(symbol string, starttime time.Time, endtime time.Time) ([]dia.Trade, error) {
+	r := []dia.Trade{}
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE symbol='%s' AND time>=%d AND time<%d ORDER BY ASC", tradeColumns, influxDbTradesTable, symbol, starttime.UnixNano(), endtime.UnixNano())
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		log.Errorln("GetTradesByTimerange", err)
+		return r, err
+	}
+
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			t := parseTrade(row)
+			if t != nil {
+				r = append(r, *t)
+			}
+		}
+	}
+	return r, nil
+}
+
+// GetForeignTradeIDs returns the foreign ids of the trades of @pair on @exchange in the time
+// range [@starttime, @endtime), e.g. to skip trades which are already stored when backfilling.
+func (db *DB) GetForeignTradeIDs(exchange string, pair string, starttime time.Time, endtime time.Time) (map[string]struct{}, error) {
+	ids := make(map[string]struct{})
+	q := fmt.Sprintf("SELECT foreignTradeID FROM %s WHERE exchange='%s' AND pair='%s' AND time>=%d AND time<%d", influxDbTradesTable, exchange, pair, starttime.UnixNano(), endtime.UnixNano())
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		log.Errorln("GetForeignTradeIDs", err)
+		return ids, err
+	}
+
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			if id, ok := row[1].(string); ok {
+				ids[id] = struct{}{}
+			}
+		}
+	}
+	return ids, nil
+}
+
+// MarkTradeSeen stores the dedup key @key of a trade in redis for @ttl. It returns true if the
+// key wasn't stored yet, i.e. if the trade is seen for the first time.
+func (db *DB) MarkTradeSeen(key string, ttl time.Duration) (bool, error) {
+	if db.redisClient == nil {
+		return true, nil
+	}
+	return db.redisClient.SetNX("dia_trade_seen_"+key, 1, ttl).Result()
+}
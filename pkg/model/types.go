@@ -38,6 +38,22 @@ type Quotation struct {
 	ITIN               string
 }
 
+// ConvertedQuotation is a quotation along with its price in another currency.
+type ConvertedQuotation struct {
+	*Quotation
+	Converted ConvertedPrice
+}
+
+// ConvertedPrice is a USD price converted to another currency.
+type ConvertedPrice struct {
+	Currency       string
+	Price          float64
+	PriceYesterday *float64
+	// FXRate is the USD price of one unit of Currency at FXTime.
+	FXRate float64
+	FXTime time.Time
+}
+
 type StockQuotation struct {
 	Symbol     string
 	Name       string
@@ -173,6 +189,21 @@ func (e *Coin) MarshalBinary() ([]byte, error) {
 	return json.Marshal(e)
 }
 
+// PricePoint is the price of an asset at a point in time.
+type PricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// QuotationRange is a page of historical prices of an asset at a fixed resolution.
+type QuotationRange struct {
+	Symbol     string
+	Resolution string
+	Prices     []PricePoint
+	// NextCursor requests the following page. It is empty on the last page.
+	NextCursor string
+}
+
 type Points struct {
 	DataPoints []clientInfluxdb.Result
 }
@@ -188,3 +219,96 @@ func (e *Points) UnmarshalBinary(data []byte) error {
 func (e *Points) MarshalBinary() ([]byte, error) {
 	return json.Marshal(e)
 }
+
+// ExchangePrice is the latest price of an asset on a single exchange, along with the trade it
+// is taken from.
+type ExchangePrice struct {
+	Symbol   string
+	Exchange string
+	// Price is the USD price of the trade.
+	Price float64
+	Time  time.Time
+	Trade dia.Trade
+}
+
+// Volatility is the annualized realized volatility of an asset, i.e. the standard deviation of
+// its log returns per Interval scaled to one year.
+type Volatility struct {
+	Symbol     string
+	Window     string
+	Interval   string
+	StartTime  time.Time
+	EndTime    time.Time
+	Returns    int
+	Volatility float64
+}
+
+// CorrelationMatrix holds the pairwise correlations of the log returns of assets. Correlations[i][j]
+// is the correlation of Symbols[i] and Symbols[j], computed from the intervals in which both have
+// a return. It is null if there are fewer than two of them or one asset has constant prices.
+type CorrelationMatrix struct {
+	Symbols      []string
+	Window       string
+	Interval     string
+	StartTime    time.Time
+	EndTime      time.Time
+	Correlations [][]*float64
+}
+
+// OrderBookDepths are the order book depths of an asset on all exchanges, along with their
+// USD value at the quotation of the asset.
+type OrderBookDepths struct {
+	Symbol string
+	// Band is the distance from the mid price within which orders count, e.g. 0.02 for 2%.
+	Band        float64
+	BidDepthUSD float64
+	AskDepthUSD float64
+	Exchanges   []ExchangeDepth
+}
+
+// ExchangeDepth is the order book depth of a pair on an exchange.
+type ExchangeDepth struct {
+	dia.OrderBookDepth
+	BidDepthUSD float64
+	AskDepthUSD float64
+}
+
+// CrossRate is the price of an asset in units of another asset or fiat currency, derived from
+// the USD quotations of both.
+type CrossRate struct {
+	Base  string
+	Quote string
+	// Price is the number of Quote units per Base unit.
+	Price float64
+	// Time is the time of the older constituent quotation.
+	Time time.Time
+	// Rates are the USD quotations of Base and Quote, in this order.
+	Rates []CrossRateConstituent
+}
+
+// CrossRateConstituent is a USD quotation a cross rate is computed from.
+type CrossRateConstituent struct {
+	Symbol string
+	Price  float64
+	Source string
+	Time   time.Time
+}
+
+// LiquidStakingRate is the redemption rate of a liquid staking token along with its market rate.
+type LiquidStakingRate struct {
+	dia.LiquidStakingRate
+	// MarketRate is the price of the token in units of the underlying derived from their USD
+	// quotations. It is nil if either has no quotation.
+	MarketRate *float64
+	// Premium is MarketRate/Rate-1, negative if the token trades at a discount.
+	Premium *float64
+	// FairValue is the price of the token in USD at its redemption rate, i.e. Rate times the
+	// price of the underlying. It is nil if the underlying has no quotation.
+	FairValue *float64
+}
+
+// GasPrices are the latest gas prices of a blockchain along with their recent samples.
+type GasPrices struct {
+	Latest  GasPrice
+	History []GasPrice
+}
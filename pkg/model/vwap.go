@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VWAP is the volume weighted average USD price of an asset over a time range.
+type VWAP struct {
+	Symbol    string
+	Price     float64
+	Volume    float64
+	StartTime time.Time
+	EndTime   time.Time
+	// Exchanges are the exchanges with trades in the range, along with their own VWAP and volume.
+	Exchanges []ExchangeVWAP
+}
+
+// ExchangeVWAP is the VWAP of an asset on a single exchange.
+type ExchangeVWAP struct {
+	Exchange string
+	Price    float64
+	Volume   float64
+}
+
+// GetVWAP returns the VWAP of @symbol in the time range [@starttime, @endtime) from the USD
+// prices of its trades. Only trades on @exchanges are included, or on all exchanges if
+// @exchanges is empty, and trades on @excluded are left out.
+func (db *DB) GetVWAP(symbol string, exchanges []string, excluded []string, starttime time.Time, endtime time.Time) (*VWAP, error) {
+	conditions := []string{
+		fmt.Sprintf("symbol='%s'", symbol),
+		fmt.Sprintf("time>=%d AND time<%d", starttime.UnixNano(), endtime.UnixNano()),
+		"estimatedUSDPrice>0",
+	}
+	for _, exchange := range excluded {
+		conditions = append(conditions, fmt.Sprintf("exchange!='%s'", exchange))
+	}
+	// The turnover of each trade is computed in a subquery as aggregates don't take expressions.
+	q := fmt.Sprintf("SELECT SUM(turnover),SUM(amount) FROM (SELECT estimatedUSDPrice*ABS(volume) AS turnover,ABS(volume) AS amount FROM %s WHERE %s GROUP BY exchange) GROUP BY exchange",
+		influxDbTradesTable, strings.Join(conditions, " AND ")+exchangeCondition(exchanges))
+	res, err := queryInfluxDB(db.influxClient, q)
+	if err != nil {
+		return nil, err
+	}
+
+	vwap := &VWAP{Symbol: symbol, StartTime: starttime, EndTime: endtime, Exchanges: []ExchangeVWAP{}}
+	var turnover float64
+	if len(res) > 0 {
+		for _, series := range res[0].Series {
+			if len(series.Values) == 0 {
+				continue
+			}
+			_, sums, err := parseCandleRow(series.Values[0])
+			if err != nil {
+				return nil, err
+			}
+			if len(sums) != 2 || sums[1] == 0 {
+				continue
+			}
+			turnover += sums[0]
+			vwap.Volume += sums[1]
+			vwap.Exchanges = append(vwap.Exchanges, ExchangeVWAP{
+				Exchange: series.Tags["exchange"],
+				Price:    sums[0] / sums[1],
+				Volume:   sums[1],
+			})
+		}
+	}
+	if vwap.Volume > 0 {
+		vwap.Price = turnover / vwap.Volume
+	}
+	return vwap, nil
+}
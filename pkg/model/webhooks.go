@@ -0,0 +1,152 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Conditions of webhooks.
+const (
+	// WebhookAbove triggers when the price of the asset crosses above the threshold.
+	WebhookAbove = "above"
+	// WebhookBelow triggers when the price of the asset crosses below the threshold.
+	WebhookBelow = "below"
+	// WebhookMove triggers when the price of the asset moves by more than the threshold in
+	// percent within the window.
+	WebhookMove = "move"
+)
+
+// Webhook is a subscription to a price alert. The webhook service posts an event to URL when the
+// condition triggers, signed with Secret.
+type Webhook struct {
+	ID            string
+	URL           string
+	Symbol        string
+	Condition     string
+	Threshold     float64
+	WindowMinutes int    `json:",omitempty"`
+	Secret        string `json:",omitempty"`
+	Creator       string `json:",omitempty"`
+	CreatedAt     time.Time
+}
+
+// WebhookDeadLetter is an event which couldn't be delivered to a webhook after all retries.
+type WebhookDeadLetter struct {
+	WebhookID  string
+	Payload    string
+	Attempts   int
+	StatusCode int    `json:",omitempty"`
+	Error      string `json:",omitempty"`
+	Time       time.Time
+}
+
+const webhookColumns = "webhook_id::text,url,symbol,condition,threshold,coalesce(window_minutes,0),secret,coalesce(creator,''),created_at"
+
+// SetWebhook stores @webhook and returns its ID.
+func (rdb *RelDB) SetWebhook(webhook Webhook) (id string, err error) {
+	query := fmt.Sprintf(`insert into %s (url,symbol,condition,threshold,window_minutes,secret,creator,created_at)
+	values ($1,$2,$3,$4,$5,$6,$7,$8) returning webhook_id::text`, webhookTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query,
+		webhook.URL,
+		webhook.Symbol,
+		webhook.Condition,
+		webhook.Threshold,
+		webhook.WindowMinutes,
+		webhook.Secret,
+		webhook.Creator,
+		webhook.CreatedAt,
+	).Scan(&id)
+	return
+}
+
+// GetWebhook returns the webhook with @id. It returns pgx.ErrNoRows if there is no such webhook.
+func (rdb *RelDB) GetWebhook(id string) (webhook Webhook, err error) {
+	query := fmt.Sprintf("select %s from %s where webhook_id::text=$1", webhookColumns, webhookTable)
+	err = scanWebhook(rdb.postgresClient.QueryRow(context.Background(), query, id), &webhook)
+	return
+}
+
+// GetWebhooks returns the webhooks of @creator, or all webhooks if @creator is empty, oldest first.
+func (rdb *RelDB) GetWebhooks(creator string) (webhooks []Webhook, err error) {
+	query := fmt.Sprintf("select %s from %s where ($1='' or creator=$1) order by created_at asc", webhookColumns, webhookTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, creator)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var webhook Webhook
+		err = scanWebhook(rows, &webhook)
+		if err != nil {
+			return
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return
+}
+
+// DeleteWebhook removes the webhook with @id along with its dead letters.
+func (rdb *RelDB) DeleteWebhook(id string) error {
+	query := fmt.Sprintf("delete from %s where webhook_id::text=$1", webhookTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, id)
+	return err
+}
+
+// SetWebhookDeadLetter stores an event which couldn't be delivered.
+func (rdb *RelDB) SetWebhookDeadLetter(deadLetter WebhookDeadLetter) error {
+	query := fmt.Sprintf("insert into %s (webhook_id,payload,attempts,status_code,error,failed_at) values ($1,$2,$3,$4,$5,$6)", webhookdeadTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query,
+		deadLetter.WebhookID,
+		deadLetter.Payload,
+		deadLetter.Attempts,
+		deadLetter.StatusCode,
+		deadLetter.Error,
+		deadLetter.Time,
+	)
+	return err
+}
+
+// GetWebhookDeadLetters returns the latest @limit dead letters of the webhook with @webhookID,
+// newest first.
+func (rdb *RelDB) GetWebhookDeadLetters(webhookID string, limit int) (deadLetters []WebhookDeadLetter, err error) {
+	query := fmt.Sprintf(`select webhook_id::text,payload,coalesce(attempts,0),coalesce(status_code,0),coalesce(error,''),failed_at
+	from %s where webhook_id::text=$1 order by failed_at desc limit $2`, webhookdeadTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, webhookID, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deadLetter WebhookDeadLetter
+		err = rows.Scan(
+			&deadLetter.WebhookID,
+			&deadLetter.Payload,
+			&deadLetter.Attempts,
+			&deadLetter.StatusCode,
+			&deadLetter.Error,
+			&deadLetter.Time,
+		)
+		if err != nil {
+			return
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return
+}
+
+func scanWebhook(row rowScanner, webhook *Webhook) error {
+	return row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Symbol,
+		&webhook.Condition,
+		&webhook.Threshold,
+		&webhook.WindowMinutes,
+		&webhook.Secret,
+		&webhook.Creator,
+		&webhook.CreatedAt,
+	)
+}
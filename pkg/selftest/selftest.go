@@ -0,0 +1,117 @@
+// Package selftest verifies the dependencies of a service, such as databases, blockchain nodes,
+// contracts and the DIA API, and reports the outcome. Services run it with --selftest so that
+// deployment pipelines can check a release before switching traffic to it.
+package selftest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DefaultTimeout is the time a single check may take before it fails.
+const DefaultTimeout = 30 * time.Second
+
+// Check verifies a single dependency. Run returns nil if the dependency is usable.
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+// Checker is implemented by components which know how to verify their own dependencies.
+type Checker interface {
+	Checks() []Check
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// Collect returns the checks of @v if it implements Checker, with their names prefixed by
+// @prefix.
+func Collect(prefix string, v interface{}) []Check {
+	checker, ok := v.(Checker)
+	if !ok {
+		return nil
+	}
+	var checks []Check
+	for _, c := range checker.Checks() {
+		checks = append(checks, Check{Name: prefix + c.Name, Run: c.Run})
+	}
+	return checks
+}
+
+// Run executes @checks one after another. Checks exceeding @timeout fail, the remaining checks
+// run regardless.
+func Run(checks []Check, timeout time.Duration) []Result {
+	var results []Result
+	for _, c := range checks {
+		start := time.Now()
+		done := make(chan error, 1)
+		go func(c Check) {
+			defer func() {
+				if r := recover(); r != nil {
+					done <- fmt.Errorf("panic: %v", r)
+				}
+			}()
+			done <- c.Run()
+		}(c)
+		var err error
+		select {
+		case err = <-done:
+		case <-time.After(timeout):
+			err = fmt.Errorf("timed out after %v", timeout)
+		}
+		results = append(results, Result{Name: c.Name, Err: err, Duration: time.Since(start)})
+	}
+	return results
+}
+
+// WriteReport writes one line per result to @w and returns true if all checks passed.
+func WriteReport(w io.Writer, service string, results []Result) bool {
+	failed := 0
+	fmt.Fprintf(w, "self-test of %s\n", service)
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(w, "  FAIL  %s (%v): %v\n", r.Name, r.Duration.Round(time.Millisecond), r.Err)
+			continue
+		}
+		fmt.Fprintf(w, "  PASS  %s (%v)\n", r.Name, r.Duration.Round(time.Millisecond))
+	}
+	fmt.Fprintf(w, "%d of %d checks passed\n", len(results)-failed, len(results))
+	return failed == 0
+}
+
+// Exit runs @checks, prints the report to stdout and exits with status 0 if all checks passed
+// and 1 otherwise.
+func Exit(service string, checks []Check) {
+	if !WriteReport(os.Stdout, service, Run(checks, DefaultTimeout)) {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// HTTP returns a check which fails unless a GET request to @url succeeds with a status below 400.
+func HTTP(name string, url string) Check {
+	return Check{
+		Name: name,
+		Run: func() error {
+			client := http.Client{Timeout: DefaultTimeout}
+			response, err := client.Get(url)
+			if err != nil {
+				return err
+			}
+			defer response.Body.Close()
+			if response.StatusCode >= 400 {
+				return fmt.Errorf("GET %s returned %s", url, response.Status)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package selftest
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type checker struct{}
+
+func (checker) Checks() []Check {
+	return []Check{{Name: "ok", Run: func() error { return nil }}}
+}
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "pass", Run: func() error { return nil }},
+		{Name: "fail", Run: func() error { return errors.New("broken") }},
+		{Name: "slow", Run: func() error { time.Sleep(time.Second); return nil }},
+		{Name: "panic", Run: func() error { panic("boom") }},
+	}
+	checks = append(checks, Collect("child: ", checker{})...)
+	checks = append(checks, Collect("none: ", struct{}{})...)
+
+	results := Run(checks, 100*time.Millisecond)
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	for i, failed := range []bool{false, true, true, true, false} {
+		if (results[i].Err != nil) != failed {
+			t.Errorf("check %s: unexpected error %v", results[i].Name, results[i].Err)
+		}
+	}
+	if results[4].Name != "child: ok" {
+		t.Errorf("unexpected name %q of collected check", results[4].Name)
+	}
+
+	var b bytes.Buffer
+	if WriteReport(&b, "test", results) {
+		t.Error("report passed despite failures")
+	}
+	if !strings.Contains(b.String(), "FAIL  fail") || !strings.Contains(b.String(), "2 of 5 checks passed") {
+		t.Errorf("unexpected report:\n%s", b.String())
+	}
+	if !WriteReport(&b, "test", results[:1]) {
+		t.Error("report failed without failures")
+	}
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Backoff computes exponentially growing delays between retries.
+type Backoff struct {
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+	// Max caps the delay.
+	Max time.Duration
+	// Factor is the growth of the delay per attempt.
+	Factor float64
+	// Attempts is the maximal number of attempts, including the first one.
+	Attempts int
+}
+
+// DefaultBackoff retries for about a minute, which covers restarts and short outages of the API.
+var DefaultBackoff = Backoff{
+	Initial:  time.Second,
+	Max:      30 * time.Second,
+	Factor:   2,
+	Attempts: 5,
+}
+
+// Delay returns the delay before retry number @attempt, starting at 0. Half of the delay is
+// random, so that many clients failing at the same time don't retry in lockstep.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := float64(b.Initial) * math.Pow(b.Factor, float64(attempt))
+	if d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	return time.Duration(d/2 + rand.Float64()*d/2)
+}
+
+// Retry calls @f until it succeeds or the attempts of @b are used up, and returns the last error.
+func (b Backoff) Retry(f func() error) error {
+	var err error
+	for attempt := 0; attempt < b.Attempts; attempt++ {
+		if attempt > 0 {
+			delay := b.Delay(attempt - 1)
+			log.Warnf("attempt %d of %d failed: %v. Retrying in %v", attempt, b.Attempts, err, delay)
+			time.Sleep(delay)
+		}
+		err = f()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// GetWithBackoff is http.Get retrying transport errors, rate limits and server errors with
+// DefaultBackoff. Other responses, e.g. 404, are returned as is.
+func GetWithBackoff(url string) (*http.Response, error) {
+	var response *http.Response
+	err := DefaultBackoff.Retry(func() error {
+		var err error
+		response, err = http.Get(url)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500 {
+			response.Body.Close()
+			return fmt.Errorf("%s returned status %d", url, response.StatusCode)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetJSONWithBackoff decodes the json response of a GET request to @url into @v, retrying as
+// GetWithBackoff does. Responses other than 200 are returned as error.
+func GetJSONWithBackoff(url string, v interface{}) error {
+	response, err := GetWithBackoff(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, response.StatusCode)
+	}
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+// CircuitBreaker stops a periodic job after consecutive failures, e.g. to stop sending
+// transactions while the API or the node are down. After a cooldown a single attempt is let
+// through; a success closes the breaker, a failure opens it again.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewCircuitBreaker returns a breaker opening for @cooldown after @threshold consecutive failures.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Allow returns false while the breaker is open.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return !cb.now().Before(cb.openUntil)
+}
+
+// Record registers the outcome of an attempt.
+func (cb *CircuitBreaker) Record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if err == nil {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.openUntil = cb.now().Add(cb.cooldown)
+		log.Errorf("%d consecutive failures, pausing for %v. Last error: %v", cb.failures, cb.cooldown, err)
+	}
+}
+
+// Failures returns the number of consecutive failures.
+func (cb *CircuitBreaker) Failures() int {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.failures
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 10 * time.Second, Factor: 2}
+	tables := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 500 * time.Millisecond, time.Second},
+		{2, 2 * time.Second, 4 * time.Second},
+		{10, 5 * time.Second, 10 * time.Second},
+	}
+	for _, table := range tables {
+		for i := 0; i < 100; i++ {
+			d := b.Delay(table.attempt)
+			if d < table.min || d > table.max {
+				t.Fatalf("Delay(%d) = %v, want between %v and %v", table.attempt, d, table.min, table.max)
+			}
+		}
+	}
+}
+
+func TestBackoffRetry(t *testing.T) {
+	b := Backoff{Initial: time.Millisecond, Max: time.Millisecond, Factor: 2, Attempts: 3}
+	calls := 0
+	err := b.Retry(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Retry returned %v after %d calls, want nil after 3", err, calls)
+	}
+
+	calls = 0
+	err = b.Retry(func() error {
+		calls++
+		return errors.New("permanent")
+	})
+	if err == nil || calls != 3 {
+		t.Errorf("Retry returned %v after %d calls, want error after 3", err, calls)
+	}
+}
+
+func TestGetJSONWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"rate":0.0001}`))
+	}))
+	defer server.Close()
+
+	var v struct{ Rate float64 }
+	if err := GetJSONWithBackoff(server.URL+"/rate", &v); err != nil || v.Rate != 0.0001 {
+		t.Errorf("GetJSONWithBackoff returned %v and rate %v, want nil and 0.0001", err, v.Rate)
+	}
+	if err := GetJSONWithBackoff(server.URL+"/missing", &v); err == nil {
+		t.Error("GetJSONWithBackoff returned nil, want error for status 404")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	cb := NewCircuitBreaker(2, time.Minute)
+	cb.now = func() time.Time { return now }
+	failure := errors.New("failure")
+
+	cb.Record(failure)
+	if !cb.Allow() {
+		t.Fatal("breaker opened before reaching the threshold")
+	}
+	cb.Record(failure)
+	if cb.Allow() {
+		t.Fatal("breaker still closed after reaching the threshold")
+	}
+
+	// Half open after the cooldown, a failure opens it again.
+	now = now.Add(time.Minute)
+	if !cb.Allow() {
+		t.Fatal("breaker still open after the cooldown")
+	}
+	cb.Record(failure)
+	if cb.Allow() {
+		t.Fatal("breaker closed after a failed attempt")
+	}
+
+	now = now.Add(time.Minute)
+	cb.Record(nil)
+	if !cb.Allow() || cb.Failures() != 0 {
+		t.Fatal("breaker not reset by a success")
+	}
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var bigRadix = big.NewInt(58)
+
+// Base58Encode encodes @b using the bitcoin alphabet, as used by NEAR and Tron among others.
+func Base58Encode(b []byte) string {
+	x := new(big.Int).SetBytes(b)
+	mod := new(big.Int)
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, bigRadix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Base58Decode decodes the base58 string @s.
+func Base58Decode(s string) ([]byte, error) {
+	x := new(big.Int)
+	for _, c := range []byte(s) {
+		i := base58Index(c)
+		if i < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		x.Mul(x, bigRadix)
+		x.Add(x, big.NewInt(int64(i)))
+	}
+	var zeros int
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), x.Bytes()...), nil
+}
+
+func base58Index(c byte) int {
+	for i := 0; i < len(base58Alphabet); i++ {
+		if base58Alphabet[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// Base58CheckEncode appends the 4 byte double-sha256 checksum to @b and encodes it in base58.
+func Base58CheckEncode(b []byte) string {
+	h1 := sha256.Sum256(b)
+	h2 := sha256.Sum256(h1[:])
+	return Base58Encode(append(append([]byte{}, b...), h2[:4]...))
+}
+
+// Base58CheckDecode decodes @s and verifies and strips its 4 byte checksum.
+func Base58CheckDecode(s string) ([]byte, error) {
+	b, err := Base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, errors.New("base58check string too short")
+	}
+	payload := b[:len(b)-4]
+	h1 := sha256.Sum256(payload)
+	h2 := sha256.Sum256(h1[:])
+	if !bytes.Equal(h2[:4], b[len(b)-4:]) {
+		return nil, errors.New("invalid base58check checksum")
+	}
+	return payload, nil
+}
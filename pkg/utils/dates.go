@@ -18,6 +18,42 @@ func StrToUnixtime(s string) (t time.Time, err error) {
 	return
 }
 
+// MakeTimerange parses unix timestamps given as strings. In case only one of them is given,
+// the other one is set such that the time range has length @timeRange. If none is given,
+// the time range ends now and starts @timeRange before.
+func MakeTimerange(starttimeString string, endtimeString string, timeRange time.Duration) (starttime time.Time, endtime time.Time, err error) {
+	switch {
+	case starttimeString == "" && endtimeString == "":
+		endtime = time.Now()
+		starttime = endtime.Add(-timeRange)
+	case starttimeString == "":
+		endtime, err = StrToUnixtime(endtimeString)
+		if err != nil {
+			return
+		}
+		starttime = endtime.Add(-timeRange)
+	case endtimeString == "":
+		starttime, err = StrToUnixtime(starttimeString)
+		if err != nil {
+			return
+		}
+		endtime = starttime.Add(timeRange)
+	default:
+		starttime, err = StrToUnixtime(starttimeString)
+		if err != nil {
+			return
+		}
+		endtime, err = StrToUnixtime(endtimeString)
+		if err != nil {
+			return
+		}
+	}
+	if starttime.After(endtime) {
+		err = errors.New("starttime must not be after endtime")
+	}
+	return
+}
+
 // CheckWeekDay returns true if @date is not weekend and false otherwise.
 func CheckWeekDay(date time.Time) bool {
 	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
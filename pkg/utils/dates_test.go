@@ -130,3 +130,30 @@ func TestGetYesterday(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeTimerange(t *testing.T) {
+	tables := []struct {
+		starttime string
+		endtime   string
+		timeRange time.Duration
+		start     time.Time
+		end       time.Time
+		err       bool
+	}{
+		{"1600000000", "1600003600", time.Hour, time.Unix(1600000000, 0), time.Unix(1600003600, 0), false},
+		{"1600000000", "", time.Hour, time.Unix(1600000000, 0), time.Unix(1600003600, 0), false},
+		{"", "1600003600", time.Hour, time.Unix(1600000000, 0), time.Unix(1600003600, 0), false},
+		{"1600003600", "1600000000", time.Hour, time.Unix(1600003600, 0), time.Unix(1600000000, 0), true},
+		{"abc", "", time.Hour, time.Time{}, time.Time{}, true},
+	}
+	for _, table := range tables {
+		start, end, err := MakeTimerange(table.starttime, table.endtime, table.timeRange)
+		if (err != nil) != table.err {
+			t.Errorf("Error for (%s,%s) was incorrect, got: %v, want error: %v.", table.starttime, table.endtime, err, table.err)
+			continue
+		}
+		if !table.err && (!start.Equal(table.start) || !end.Equal(table.end)) {
+			t.Errorf("Time range for (%s,%s) was incorrect, got: (%v,%v), want: (%v,%v).", table.starttime, table.endtime, start, end, table.start, table.end)
+		}
+	}
+}
@@ -1,9 +1,22 @@
 package utils
 
 import (
+	"strings"
+
 	log "github.com/sirupsen/logrus"
 )
 
+// QuerySafe returns true if none of @values contains a quote or backslash, which would let it
+// break out of the string literals of an InfluxQL query.
+func QuerySafe(values ...string) bool {
+	for _, value := range values {
+		if strings.ContainsAny(value, `'"\`) {
+			return false
+		}
+	}
+	return true
+}
+
 // UniqueStrings returns a subslice of @s such that each entry only appears once
 func UniqueStrings(s []string) []string {
 	if len(s) == 0 {
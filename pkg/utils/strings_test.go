@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestQuerySafe(t *testing.T) {
+	tables := []struct {
+		values []string
+		safe   bool
+	}{
+		{[]string{"BTC"}, true},
+		{[]string{"Uniswap", "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc"}, true},
+		{[]string{}, true},
+		{[]string{"BTC' or 1=1 or symbol='"}, false},
+		{[]string{"BTC", `ETH"`}, false},
+		{[]string{`BTC\`}, false},
+	}
+	for _, table := range tables {
+		safe := QuerySafe(table.values...)
+		if safe != table.safe {
+			t.Errorf("QuerySafe(%q) was incorrect, got: %v, want: %v.", table.values, safe, table.safe)
+		}
+	}
+}